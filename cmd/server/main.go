@@ -170,6 +170,9 @@ func main() {
 		adminRoutes.POST("/users/:uid", handlers.UpdateUserHandler)
 		adminRoutes.POST("/users/:uid/toggle-status", handlers.ToggleUserStatusHandler)
 		adminRoutes.POST("/users/:uid/reset-password", handlers.ResetUserPasswordHandler)
+
+		adminRoutes.GET("/audit", handlers.ShowGlobalAuditLog)
+		adminRoutes.GET("/audit/export", handlers.ExportGlobalAuditExcel)
 	}
 
 	// Project-scoped routes (with project context middleware)
@@ -181,7 +184,12 @@ func main() {
 
 		// Project-scoped DC listing and serial search
 		projectRoutes.GET("/dcs-list", handlers.ListAllDeliveryChallans)
+		projectRoutes.GET("/dcs-list/eway-bill", handlers.ExportEwayBillBatchJSON)
+		projectRoutes.GET("/dcs-list/tally", handlers.ExportTallyXML)
 		projectRoutes.GET("/serial-search", handlers.ShowSerialSearch)
+		projectRoutes.GET("/serial-search/timeline", handlers.ShowSerialTimeline)
+		projectRoutes.GET("/serial-search/timeline/json", handlers.SerialTimelineJSON)
+		projectRoutes.GET("/serial-search/timeline/certificate", handlers.PrintSerialCertificate)
 
 		// Project detail/settings
 		projectRoutes.GET("", handlers.ShowProject)
@@ -193,6 +201,8 @@ func main() {
 		projectRoutes.GET("/settings", handlers.ShowProjectSettings)
 		projectRoutes.POST("/settings", handlers.UpdateProjectSettings)
 		projectRoutes.GET("/settings/dc-preview", handlers.PreviewDCNumberAPI)
+		projectRoutes.POST("/settings/financial-years/:fy/close", handlers.CloseFinancialYearHandler, appmiddleware.RequireRole("admin"))
+		projectRoutes.POST("/settings/financial-years/:fy/reopen", handlers.ReopenFinancialYearHandler, appmiddleware.RequireRole("admin"))
 
 		// Product routes
 		projectRoutes.GET("/products", handlers.ListProducts)
@@ -205,6 +215,18 @@ func main() {
 		projectRoutes.POST("/products/import", handlers.ImportProductsHandler)
 		projectRoutes.GET("/products/import-template", handlers.DownloadProductImportTemplate)
 
+		// BOQ plan
+		projectRoutes.GET("/boq-plan", handlers.ShowBOQPlan)
+		projectRoutes.GET("/boq-plan/sheet", handlers.DownloadBOQPlanSheet)
+		projectRoutes.POST("/boq-plan/import", handlers.ImportBOQPlanHandler)
+		projectRoutes.POST("/boq-plan/clear", handlers.ClearBOQPlanHandler)
+
+		// Serial registry
+		projectRoutes.GET("/serials", handlers.ShowSerialRegistry)
+		projectRoutes.POST("/serials/stock-in", handlers.StockInSerialsHandler)
+		projectRoutes.POST("/serials/:sid/condition", handlers.SetRegistrySerialConditionHandler, appmiddleware.RequireRole("admin"))
+		projectRoutes.POST("/serials/:sid/delete", handlers.DeleteRegistrySerialHandler, appmiddleware.RequireRole("admin"))
+
 		// DC Template routes
 		projectRoutes.GET("/templates", handlers.ListTemplates)
 		projectRoutes.GET("/templates/new", handlers.ShowCreateTemplateForm)
@@ -250,10 +272,20 @@ func main() {
 		projectRoutes.POST("/shipments/new/back-to-step3", handlers.BackToStep3)
 		projectRoutes.POST("/shipments/new/back-to-step4", handlers.BackToStep4)
 		projectRoutes.POST("/shipments", handlers.CreateShipment)
+		projectRoutes.GET("/shipments/import", handlers.ShowShipmentImport)
+		projectRoutes.POST("/shipments/import/preview", handlers.PreviewShipmentImport)
+		projectRoutes.GET("/shipments/import/template", handlers.DownloadAllocationTemplate)
+		projectRoutes.POST("/shipments/import", handlers.ImportShipmentsHandler)
 		projectRoutes.GET("/shipments/:gid", handlers.ShowShipmentGroup)
 		projectRoutes.GET("/shipments", handlers.ListShipmentGroups)
 		projectRoutes.POST("/shipments/:gid/issue", handlers.IssueShipmentGroup)
+		projectRoutes.POST("/shipments/:gid/cancel", handlers.CancelShipmentGroupHandler)
+		projectRoutes.POST("/shipments/:gid/submit", handlers.SubmitShipmentGroupHandler)
+		projectRoutes.POST("/shipments/:gid/reject", handlers.RejectShipmentGroupHandler)
+		projectRoutes.POST("/shipments/:gid/reopen", handlers.ReopenShipmentGroupHandler)
 		projectRoutes.GET("/shipments/:gid/export/pdf", handlers.ExportShipmentGroupPDF)
+		projectRoutes.GET("/shipments/:gid/labels", handlers.ShowBoxLabels)
+		projectRoutes.GET("/shipments/:gid/labels/pdf", handlers.DownloadBoxLabels)
 		projectRoutes.DELETE("/shipments/:gid", handlers.DeleteShipmentGroupHandler)
 
 		// Edit Draft Shipment Wizard
@@ -268,6 +300,11 @@ func main() {
 		projectRoutes.POST("/shipments/:gid/edit/back-to-step4", handlers.EditBackToStep4)
 		projectRoutes.POST("/shipments/:gid/edit", handlers.SaveShipmentEdit)
 
+		// Scanner serial capture for draft shipments
+		projectRoutes.GET("/shipments/:gid/scan", handlers.ShowShipmentSerialCapture)
+		projectRoutes.POST("/shipments/:gid/scan", handlers.ScanShipmentSerial)
+		projectRoutes.POST("/shipments/:gid/scan/:sid/delete", handlers.RemoveShipmentScannedSerial)
+
 		// Transfer DC list & detail
 		projectRoutes.GET("/transfer-dcs", handlers.ListTransferDCs)
 		projectRoutes.POST("/transfer-dcs/:dcid/issue", handlers.IssueTransferDC)
@@ -297,6 +334,11 @@ func main() {
 		projectRoutes.POST("/transfer-dcs/:tdcid/edit/back-to-step3", handlers.EditTransferBackToStep3)
 		projectRoutes.POST("/transfer-dcs/:tdcid/edit/back-to-step4", handlers.EditTransferBackToStep4)
 
+		// Scanner serial capture for draft transfer DCs
+		projectRoutes.GET("/transfer-dcs/:tdcid/scan", handlers.ShowTransferSerialCapture)
+		projectRoutes.POST("/transfer-dcs/:tdcid/scan", handlers.ScanTransferSerial)
+		projectRoutes.POST("/transfer-dcs/:tdcid/scan/:sid/delete", handlers.RemoveTransferScannedSerial)
+
 		// Split wizard routes
 		projectRoutes.GET("/transfer-dcs/:tdcid/split", handlers.ShowSplitWizardStep1)
 		projectRoutes.POST("/transfer-dcs/:tdcid/split/step2", handlers.SplitWizardStep2)
@@ -321,6 +363,19 @@ func main() {
 		projectRoutes.GET("/dcs/:dcid/print", handlers.ShowTransitDCPrintView)
 		projectRoutes.GET("/dcs/:dcid/official-print", handlers.ShowOfficialDCPrintView)
 		projectRoutes.POST("/dcs/:dcid/issue", handlers.IssueDCHandler)
+		projectRoutes.POST("/dcs/:dcid/cancel", handlers.CancelDCHandler)
+		projectRoutes.POST("/dcs/:dcid/submit", handlers.SubmitDCHandler)
+		projectRoutes.POST("/dcs/:dcid/reject", handlers.RejectDCHandler)
+		projectRoutes.POST("/dcs/:dcid/reopen", handlers.ReopenDCHandler)
+		projectRoutes.GET("/dcs/:dcid/amend", handlers.ShowAmendDCForm)
+		projectRoutes.POST("/dcs/:dcid/amend", handlers.AmendDCHandler)
+		projectRoutes.POST("/dcs/:dcid/pod", handlers.RecordProofOfDeliveryHandler)
+		projectRoutes.GET("/dcs/:dcid/receipt", handlers.ShowGoodsReceiptForm)
+		projectRoutes.POST("/dcs/:dcid/receipt", handlers.SaveGoodsReceiptHandler)
+		projectRoutes.GET("/dcs/:dcid/return", handlers.ShowCreateReturnDCForm)
+		projectRoutes.POST("/dcs/:dcid/return", handlers.CreateReturnDCHandler)
+		projectRoutes.GET("/dcs/:dcid/eway-bill/json", handlers.ExportDCEwayBillJSON)
+		projectRoutes.POST("/dcs/:dcid/eway-bill", handlers.SaveEwayBillNumberHandler)
 		projectRoutes.DELETE("/dcs/:dcid", handlers.DeleteDCHandler)
 
 		// DC Export routes (PDF & Excel)
@@ -339,6 +394,21 @@ func main() {
 		projectRoutes.GET("/reports/serial/export", handlers.ExportSerialExcel)
 		projectRoutes.GET("/reports/transfer", handlers.ShowTransferDCReport)
 		projectRoutes.GET("/reports/transfer/export", handlers.ExportTransferDCReportExcel)
+		projectRoutes.GET("/reports/discrepancy", handlers.ShowDiscrepancyReport)
+		projectRoutes.GET("/reports/discrepancy/export", handlers.ExportDiscrepancyExcel)
+		projectRoutes.GET("/reports/hsn-summary", handlers.ShowHSNSummaryReport)
+		projectRoutes.GET("/reports/hsn-summary/export", handlers.ExportHSNSummaryExcel)
+		projectRoutes.GET("/reports/documents-issued", handlers.ShowDocumentsIssuedReport)
+		projectRoutes.GET("/reports/documents-issued/export", handlers.ExportDocumentsIssuedExcel)
+		projectRoutes.GET("/reports/warranty", handlers.ShowWarrantyReport)
+		projectRoutes.GET("/reports/warranty/export", handlers.ExportWarrantyExcel)
+		projectRoutes.GET("/reports/boq", handlers.ShowBOQReport)
+		projectRoutes.GET("/reports/boq/export", handlers.ExportBOQExcel)
+
+		// Audit log
+		projectRoutes.GET("/approvals", handlers.ShowApprovalQueue)
+		projectRoutes.GET("/audit", handlers.ShowProjectAuditLog)
+		projectRoutes.GET("/audit/export", handlers.ExportProjectAuditExcel)
 
 		// Legacy address redirects (for backward compatibility)
 		projectRoutes.GET("/bill-to", func(c echov4.Context) error {
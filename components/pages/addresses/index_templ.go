@@ -1211,7 +1211,7 @@ func Index(user *models.User, currentProject *models.Project, allProjects []*mod
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 123, "\"><div class=\"space-y-4\"><div><label class=\"block text-sm font-medium text-gray-700 mb-1\">Address Code</label> <input type=\"text\" name=\"address_code\" id=\"addr-field-address_code\" placeholder=\"e.g. ADDR-001\" class=\"input text-sm w-full\"><p class=\"mt-1 text-xs text-gray-400\">Unique identifier for searching (optional)</p></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 123, "\"> <input type=\"hidden\" name=\"edit_version\" id=\"addr-edit-version\" value=\"\"><div class=\"space-y-4\"><div><label class=\"block text-sm font-medium text-gray-700 mb-1\">Address Code</label> <input type=\"text\" name=\"address_code\" id=\"addr-field-address_code\" placeholder=\"e.g. ADDR-001\" class=\"input text-sm w-full\"><p class=\"mt-1 text-xs text-gray-400\">Unique identifier for searching (optional)</p></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1234,7 +1234,7 @@ func Index(user *models.User, currentProject *models.Project, allProjects []*mod
 				var templ_7745c5c3_Var63 string
 				templ_7745c5c3_Var63, templ_7745c5c3_Err = templ.JoinStringErrs(col.Name)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/addresses/index.templ`, Line: 542, Col: 20}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/addresses/index.templ`, Line: 543, Col: 20}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var63))
 				if templ_7745c5c3_Err != nil {
@@ -1257,7 +1257,7 @@ func Index(user *models.User, currentProject *models.Project, allProjects []*mod
 				var templ_7745c5c3_Var64 string
 				templ_7745c5c3_Var64, templ_7745c5c3_Err = templ.JoinStringErrs("field_" + sanitizeField(col.Name))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/addresses/index.templ`, Line: 547, Col: 69}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/addresses/index.templ`, Line: 548, Col: 69}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var64))
 				if templ_7745c5c3_Err != nil {
@@ -1270,7 +1270,7 @@ func Index(user *models.User, currentProject *models.Project, allProjects []*mod
 				var templ_7745c5c3_Var65 string
 				templ_7745c5c3_Var65, templ_7745c5c3_Err = templ.JoinStringErrs("addr-field-" + sanitizeField(col.Name))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/addresses/index.templ`, Line: 547, Col: 116}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/addresses/index.templ`, Line: 548, Col: 116}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var65))
 				if templ_7745c5c3_Err != nil {
@@ -1282,7 +1282,7 @@ func Index(user *models.User, currentProject *models.Project, allProjects []*mod
 				}
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 132, "</div></div><div class=\"flex justify-end gap-3 mt-6\"><button type=\"button\" onclick=\"closeAddressModal()\" class=\"btn btn-secondary\">Cancel</button> <button type=\"submit\" class=\"btn btn-primary\" id=\"address-submit-btn\">Add Address</button></div></form></div></div><!-- Delete Confirmation Modal --><div id=\"delete-address-modal\" class=\"hidden fixed inset-0 bg-gray-600 bg-opacity-50 z-50 flex items-center justify-center\"><div class=\"bg-white rounded-lg shadow-xl max-w-md w-full mx-4 p-6\"><h3 class=\"text-lg font-semibold text-gray-900 mb-2\">Delete Address</h3><p class=\"text-sm text-gray-600 mb-4\">Are you sure you want to delete this address? This action cannot be undone.</p><div class=\"flex justify-end gap-3\"><button onclick=\"document.getElementById('delete-address-modal').classList.add('hidden')\" class=\"btn btn-secondary\">Cancel</button> <button id=\"confirm-delete-addr-btn\" class=\"btn btn-danger\">Delete</button></div></div></div><!-- Delete All Confirmation Modal --><div id=\"delete-all-modal\" class=\"hidden fixed inset-0 bg-gray-600 bg-opacity-50 z-50 flex items-center justify-center\"><div class=\"bg-white rounded-lg shadow-xl max-w-md w-full mx-4 p-6\"><h3 class=\"text-lg font-semibold text-gray-900 mb-2\">Delete All Addresses</h3><p class=\"text-sm text-gray-600 mb-4\">Are you sure you want to delete all addresses for this type? This action cannot be undone.</p><div class=\"flex justify-end gap-3\"><button onclick=\"document.getElementById('delete-all-modal').classList.add('hidden')\" class=\"btn btn-secondary\">Cancel</button> <button id=\"confirm-delete-all-btn\" class=\"btn btn-danger\" onclick=\"deleteAllAddresses()\">Delete All</button></div></div></div><script>\n\tvar _aip = document.getElementById('addresses-index-page');\n\tvar currentTab = _aip.dataset.tab;\n\tvar projectID = _aip.dataset.projectId;\n\tvar csrfTokenVal = _aip.dataset.csrfToken;\n\tvar originalDynamicFields = document.getElementById('dynamic-addr-fields').innerHTML;\n\n\tfunction openAddressModal() {\n\t\tdocument.getElementById('add-address-modal').classList.remove('hidden');\n\t}\n\tfunction openConfigSlideOver() {\n\t\tdocument.getElementById('config-slideover').classList.remove('hidden');\n\t}\n\tfunction closeConfigSlideOver() {\n\t\tdocument.getElementById('config-slideover').classList.add('hidden');\n\t}\n\tfunction addColumnRow() {\n\t\tvar container = document.getElementById('columns-container');\n\t\tvar row = document.createElement('div');\n\t\trow.className = 'flex items-center gap-2 column-row';\n\t\trow.innerHTML = '<input type=\"text\" name=\"col_name[]\" placeholder=\"Column name\" class=\"input text-sm flex-1 min-w-0\" required>' +\n\t\t\t'<label class=\"flex items-center shrink-0 justify-center\" style=\"width:24px\" title=\"Required\">' +\n\t\t\t'<input type=\"hidden\" name=\"col_required[]\" value=\"false\">' +\n\t\t\t'<input type=\"checkbox\" onchange=\"this.previousElementSibling.value = this.checked ? \\'true\\' : \\'false\\'\" class=\"rounded text-brand-600 focus:ring-brand-500\"></label>' +\n\t\t\t'<label class=\"flex items-center shrink-0 justify-center\" style=\"width:24px\" title=\"Show in Table\">' +\n\t\t\t'<input type=\"hidden\" name=\"col_show_table[]\" value=\"true\">' +\n\t\t\t'<input type=\"checkbox\" checked onchange=\"this.previousElementSibling.value = this.checked ? \\'true\\' : \\'false\\'\" class=\"rounded text-green-600 focus:ring-green-500\"></label>' +\n\t\t\t'<input type=\"number\" name=\"col_table_order[]\" value=\"0\" min=\"0\" max=\"99\" style=\"width:44px\" class=\"shrink-0 text-sm text-center border border-gray-300 rounded-lg py-1 px-1 focus:outline-none focus:border-brand-500\" title=\"Table sort order (0 = default)\">' +\n\t\t\t'<label class=\"flex items-center shrink-0 justify-center\" style=\"width:24px\" title=\"Show in Print/PDF\">' +\n\t\t\t'<input type=\"hidden\" name=\"col_show_print[]\" value=\"true\">' +\n\t\t\t'<input type=\"checkbox\" checked onchange=\"this.previousElementSibling.value = this.checked ? \\'true\\' : \\'false\\'\" class=\"rounded text-purple-600 focus:ring-purple-500\"></label>' +\n\t\t\t'<input type=\"number\" name=\"col_print_order[]\" value=\"0\" min=\"0\" max=\"99\" style=\"width:44px\" class=\"shrink-0 text-sm text-center border border-gray-300 rounded-lg py-1 px-1 focus:outline-none focus:border-brand-500\" title=\"Print sort order (0 = default)\">' +\n\t\t\t'<button type=\"button\" onclick=\"this.closest(\\'.column-row\\').remove()\" class=\"shrink-0 text-red-500 hover:text-red-700\" style=\"width:20px\">' +\n\t\t\t'<svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M6 18L18 6M6 6l12 12\"/></svg></button>';\n\t\tcontainer.appendChild(row);\n\t}\n\tfunction editAddress(id) {\n\t\tfetch('/projects/' + projectID + '/addresses/' + id + '?tab=' + currentTab, {\n\t\t\theaders: { 'Accept': 'application/json' }\n\t\t}).then(function(resp) {\n\t\t\tif (!resp.ok) {\n\t\t\t\treturn resp.text().then(function(t) { throw new Error('Server error ' + resp.status + ': ' + t); });\n\t\t\t}\n\t\t\treturn resp.json();\n\t\t}).then(function(data) {\n\t\t\tdocument.getElementById('address-modal-title').textContent = 'Edit Address';\n\t\t\tdocument.getElementById('address-submit-btn').textContent = 'Save Changes';\n\t\t\tvar form = document.getElementById('address-form');\n\t\t\tform.action = '/projects/' + projectID + '/addresses/' + id + '?tab=' + currentTab;\n\t\t\tvar addrCodeField = document.getElementById('addr-field-address_code');\n\t\t\tif (addrCodeField) addrCodeField.value = data.address_code || '';\n\t\t\tvar districtField = document.getElementById('addr-district-name');\n\t\t\tif (districtField) districtField.value = data.district_name || '';\n\t\t\tvar mandalField = document.getElementById('addr-mandal-name');\n\t\t\tif (mandalField) mandalField.value = data.mandal_name || '';\n\t\t\tvar codeField = document.getElementById('addr-mandal-code');\n\t\t\tif (codeField) codeField.value = data.mandal_code || '';\n\t\t\tif (data.data) {\n\t\t\t\tvar dynFields = document.getElementById('dynamic-addr-fields');\n\t\t\t\tdynFields.innerHTML = '';\n\t\t\t\tfor (var key in data.data) {\n\t\t\t\t\tif (Object.prototype.hasOwnProperty.call(data.data, key)) {\n\t\t\t\t\t\tvar fieldName = key.toLowerCase().replace(/ /g, '_').replace(/\\//g, '_');\n\t\t\t\t\t\tvar div = document.createElement('div');\n\t\t\t\t\t\tdiv.innerHTML = '<label class=\"block text-sm font-medium text-gray-700 mb-1\">' + key + '</label>' +\n\t\t\t\t\t\t\t'<input type=\"text\" name=\"field_' + fieldName + '\" id=\"addr-field-' + fieldName +\n\t\t\t\t\t\t\t'\" class=\"input text-sm w-full\" value=\"' + (data.data[key] || '') + '\">';\n\t\t\t\t\t\tdynFields.appendChild(div);\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t}\n\t\t\tdocument.getElementById('add-address-modal').classList.remove('hidden');\n\t\t}).catch(function(err) {\n\t\t\tconsole.error('Edit address failed:', err);\n\t\t\tshowToast('Failed to load address data: ' + err.message, 'error');\n\t\t});\n\t}\n\tfunction closeAddressModal() {\n\t\tdocument.getElementById('add-address-modal').classList.add('hidden');\n\t\tdocument.getElementById('address-modal-title').textContent = 'Add Address';\n\t\tdocument.getElementById('address-submit-btn').textContent = 'Add Address';\n\t\tvar form = document.getElementById('address-form');\n\t\tform.action = '/projects/' + projectID + '/addresses/create?tab=' + currentTab;\n\t\tdocument.getElementById('dynamic-addr-fields').innerHTML = originalDynamicFields;\n\t\tvar addrCodeField = document.getElementById('addr-field-address_code');\n\t\tif (addrCodeField) addrCodeField.value = '';\n\t\tform.reset();\n\t}\n\tfunction deleteAddress(id) {\n\t\tdocument.getElementById('delete-address-modal').classList.remove('hidden');\n\t\tdocument.getElementById('confirm-delete-addr-btn').onclick = function() {\n\t\t\tfetch('/projects/' + projectID + '/addresses/' + id + '?tab=' + currentTab, {\n\t\t\t\tmethod: 'DELETE',\n\t\t\t\theaders: { 'X-CSRF-Token': csrfTokenVal, 'Content-Type': 'application/json' }\n\t\t\t}).then(function(resp) {\n\t\t\t\treturn resp.json().then(function(data) { return { ok: resp.ok, data: data }; });\n\t\t\t}).then(function(result) {\n\t\t\t\tdocument.getElementById('delete-address-modal').classList.add('hidden');\n\t\t\t\tif (result.ok && result.data.success) {\n\t\t\t\t\tvar row = document.getElementById('address-row-' + id);\n\t\t\t\t\tif (row) row.remove();\n\t\t\t\t\tshowToast('Address deleted successfully', 'success');\n\t\t\t\t} else {\n\t\t\t\t\tshowToast(result.data.error || 'Failed to delete address', 'error');\n\t\t\t\t}\n\t\t\t}).catch(function(err) {\n\t\t\t\tdocument.getElementById('delete-address-modal').classList.add('hidden');\n\t\t\t\tshowToast('Failed to delete address. Please try again.', 'error');\n\t\t\t});\n\t\t};\n\t}\n\tfunction deleteAllAddresses() {\n\t\tfetch('/projects/' + projectID + '/addresses?tab=' + currentTab, {\n\t\t\tmethod: 'DELETE',\n\t\t\theaders: { 'X-CSRF-Token': csrfTokenVal, 'Content-Type': 'application/json' }\n\t\t}).then(function(resp) {\n\t\t\treturn resp.json().then(function(data) { return { ok: resp.ok, data: data }; });\n\t\t}).then(function(result) {\n\t\t\tif (result.ok && result.data.success) {\n\t\t\t\twindow.location.href = result.data.redirect;\n\t\t\t} else {\n\t\t\t\tshowToast(result.data.error || 'Failed to delete addresses', 'error');\n\t\t\t}\n\t\t}).catch(function(err) {\n\t\t\tshowToast('Failed to delete addresses. Please try again.', 'error');\n\t\t});\n\t}\n\tdocument.addEventListener('keydown', function(e) {\n\t\tif (e.key === 'Escape') {\n\t\t\tcloseConfigSlideOver();\n\t\t\tcloseAddressModal();\n\t\t\tdocument.getElementById('delete-address-modal').classList.add('hidden');\n\t\t\tdocument.getElementById('delete-all-modal').classList.add('hidden');\n\t\t}\n\t});\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 132, "</div></div><div class=\"flex justify-end gap-3 mt-6\"><button type=\"button\" onclick=\"closeAddressModal()\" class=\"btn btn-secondary\">Cancel</button> <button type=\"submit\" class=\"btn btn-primary\" id=\"address-submit-btn\">Add Address</button></div></form></div></div><!-- Delete Confirmation Modal --><div id=\"delete-address-modal\" class=\"hidden fixed inset-0 bg-gray-600 bg-opacity-50 z-50 flex items-center justify-center\"><div class=\"bg-white rounded-lg shadow-xl max-w-md w-full mx-4 p-6\"><h3 class=\"text-lg font-semibold text-gray-900 mb-2\">Delete Address</h3><p class=\"text-sm text-gray-600 mb-4\">Are you sure you want to delete this address? This action cannot be undone.</p><div class=\"flex justify-end gap-3\"><button onclick=\"document.getElementById('delete-address-modal').classList.add('hidden')\" class=\"btn btn-secondary\">Cancel</button> <button id=\"confirm-delete-addr-btn\" class=\"btn btn-danger\">Delete</button></div></div></div><!-- Delete All Confirmation Modal --><div id=\"delete-all-modal\" class=\"hidden fixed inset-0 bg-gray-600 bg-opacity-50 z-50 flex items-center justify-center\"><div class=\"bg-white rounded-lg shadow-xl max-w-md w-full mx-4 p-6\"><h3 class=\"text-lg font-semibold text-gray-900 mb-2\">Delete All Addresses</h3><p class=\"text-sm text-gray-600 mb-4\">Are you sure you want to delete all addresses for this type? This action cannot be undone.</p><div class=\"flex justify-end gap-3\"><button onclick=\"document.getElementById('delete-all-modal').classList.add('hidden')\" class=\"btn btn-secondary\">Cancel</button> <button id=\"confirm-delete-all-btn\" class=\"btn btn-danger\" onclick=\"deleteAllAddresses()\">Delete All</button></div></div></div><script>\n\tvar _aip = document.getElementById('addresses-index-page');\n\tvar currentTab = _aip.dataset.tab;\n\tvar projectID = _aip.dataset.projectId;\n\tvar csrfTokenVal = _aip.dataset.csrfToken;\n\tvar originalDynamicFields = document.getElementById('dynamic-addr-fields').innerHTML;\n\n\tfunction openAddressModal() {\n\t\tdocument.getElementById('add-address-modal').classList.remove('hidden');\n\t}\n\tfunction openConfigSlideOver() {\n\t\tdocument.getElementById('config-slideover').classList.remove('hidden');\n\t}\n\tfunction closeConfigSlideOver() {\n\t\tdocument.getElementById('config-slideover').classList.add('hidden');\n\t}\n\tfunction addColumnRow() {\n\t\tvar container = document.getElementById('columns-container');\n\t\tvar row = document.createElement('div');\n\t\trow.className = 'flex items-center gap-2 column-row';\n\t\trow.innerHTML = '<input type=\"text\" name=\"col_name[]\" placeholder=\"Column name\" class=\"input text-sm flex-1 min-w-0\" required>' +\n\t\t\t'<label class=\"flex items-center shrink-0 justify-center\" style=\"width:24px\" title=\"Required\">' +\n\t\t\t'<input type=\"hidden\" name=\"col_required[]\" value=\"false\">' +\n\t\t\t'<input type=\"checkbox\" onchange=\"this.previousElementSibling.value = this.checked ? \\'true\\' : \\'false\\'\" class=\"rounded text-brand-600 focus:ring-brand-500\"></label>' +\n\t\t\t'<label class=\"flex items-center shrink-0 justify-center\" style=\"width:24px\" title=\"Show in Table\">' +\n\t\t\t'<input type=\"hidden\" name=\"col_show_table[]\" value=\"true\">' +\n\t\t\t'<input type=\"checkbox\" checked onchange=\"this.previousElementSibling.value = this.checked ? \\'true\\' : \\'false\\'\" class=\"rounded text-green-600 focus:ring-green-500\"></label>' +\n\t\t\t'<input type=\"number\" name=\"col_table_order[]\" value=\"0\" min=\"0\" max=\"99\" style=\"width:44px\" class=\"shrink-0 text-sm text-center border border-gray-300 rounded-lg py-1 px-1 focus:outline-none focus:border-brand-500\" title=\"Table sort order (0 = default)\">' +\n\t\t\t'<label class=\"flex items-center shrink-0 justify-center\" style=\"width:24px\" title=\"Show in Print/PDF\">' +\n\t\t\t'<input type=\"hidden\" name=\"col_show_print[]\" value=\"true\">' +\n\t\t\t'<input type=\"checkbox\" checked onchange=\"this.previousElementSibling.value = this.checked ? \\'true\\' : \\'false\\'\" class=\"rounded text-purple-600 focus:ring-purple-500\"></label>' +\n\t\t\t'<input type=\"number\" name=\"col_print_order[]\" value=\"0\" min=\"0\" max=\"99\" style=\"width:44px\" class=\"shrink-0 text-sm text-center border border-gray-300 rounded-lg py-1 px-1 focus:outline-none focus:border-brand-500\" title=\"Print sort order (0 = default)\">' +\n\t\t\t'<button type=\"button\" onclick=\"this.closest(\\'.column-row\\').remove()\" class=\"shrink-0 text-red-500 hover:text-red-700\" style=\"width:20px\">' +\n\t\t\t'<svg class=\"w-5 h-5\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M6 18L18 6M6 6l12 12\"/></svg></button>';\n\t\tcontainer.appendChild(row);\n\t}\n\tfunction editAddress(id) {\n\t\tfetch('/projects/' + projectID + '/addresses/' + id + '?tab=' + currentTab, {\n\t\t\theaders: { 'Accept': 'application/json' }\n\t\t}).then(function(resp) {\n\t\t\tif (!resp.ok) {\n\t\t\t\treturn resp.text().then(function(t) { throw new Error('Server error ' + resp.status + ': ' + t); });\n\t\t\t}\n\t\t\treturn resp.json();\n\t\t}).then(function(data) {\n\t\t\tdocument.getElementById('address-modal-title').textContent = 'Edit Address';\n\t\t\tdocument.getElementById('address-submit-btn').textContent = 'Save Changes';\n\t\t\tvar form = document.getElementById('address-form');\n\t\t\tform.action = '/projects/' + projectID + '/addresses/' + id + '?tab=' + currentTab;\n\t\t\tdocument.getElementById('addr-edit-version').value = data.version || '';\n\t\t\tvar addrCodeField = document.getElementById('addr-field-address_code');\n\t\t\tif (addrCodeField) addrCodeField.value = data.address_code || '';\n\t\t\tvar districtField = document.getElementById('addr-district-name');\n\t\t\tif (districtField) districtField.value = data.district_name || '';\n\t\t\tvar mandalField = document.getElementById('addr-mandal-name');\n\t\t\tif (mandalField) mandalField.value = data.mandal_name || '';\n\t\t\tvar codeField = document.getElementById('addr-mandal-code');\n\t\t\tif (codeField) codeField.value = data.mandal_code || '';\n\t\t\tif (data.data) {\n\t\t\t\tvar dynFields = document.getElementById('dynamic-addr-fields');\n\t\t\t\tdynFields.innerHTML = '';\n\t\t\t\tfor (var key in data.data) {\n\t\t\t\t\tif (Object.prototype.hasOwnProperty.call(data.data, key)) {\n\t\t\t\t\t\tvar fieldName = key.toLowerCase().replace(/ /g, '_').replace(/\\//g, '_');\n\t\t\t\t\t\tvar div = document.createElement('div');\n\t\t\t\t\t\tdiv.innerHTML = '<label class=\"block text-sm font-medium text-gray-700 mb-1\">' + key + '</label>' +\n\t\t\t\t\t\t\t'<input type=\"text\" name=\"field_' + fieldName + '\" id=\"addr-field-' + fieldName +\n\t\t\t\t\t\t\t'\" class=\"input text-sm w-full\" value=\"' + (data.data[key] || '') + '\">';\n\t\t\t\t\t\tdynFields.appendChild(div);\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t}\n\t\t\tdocument.getElementById('add-address-modal').classList.remove('hidden');\n\t\t}).catch(function(err) {\n\t\t\tconsole.error('Edit address failed:', err);\n\t\t\tshowToast('Failed to load address data: ' + err.message, 'error');\n\t\t});\n\t}\n\tfunction closeAddressModal() {\n\t\tdocument.getElementById('add-address-modal').classList.add('hidden');\n\t\tdocument.getElementById('address-modal-title').textContent = 'Add Address';\n\t\tdocument.getElementById('address-submit-btn').textContent = 'Add Address';\n\t\tvar form = document.getElementById('address-form');\n\t\tform.action = '/projects/' + projectID + '/addresses/create?tab=' + currentTab;\n\t\tdocument.getElementById('dynamic-addr-fields').innerHTML = originalDynamicFields;\n\t\tvar addrCodeField = document.getElementById('addr-field-address_code');\n\t\tif (addrCodeField) addrCodeField.value = '';\n\t\tform.reset();\n\t\tdocument.getElementById('addr-edit-version').value = '';\n\t}\n\tfunction deleteAddress(id) {\n\t\tdocument.getElementById('delete-address-modal').classList.remove('hidden');\n\t\tdocument.getElementById('confirm-delete-addr-btn').onclick = function() {\n\t\t\tfetch('/projects/' + projectID + '/addresses/' + id + '?tab=' + currentTab, {\n\t\t\t\tmethod: 'DELETE',\n\t\t\t\theaders: { 'X-CSRF-Token': csrfTokenVal, 'Content-Type': 'application/json' }\n\t\t\t}).then(function(resp) {\n\t\t\t\treturn resp.json().then(function(data) { return { ok: resp.ok, data: data }; });\n\t\t\t}).then(function(result) {\n\t\t\t\tdocument.getElementById('delete-address-modal').classList.add('hidden');\n\t\t\t\tif (result.ok && result.data.success) {\n\t\t\t\t\tvar row = document.getElementById('address-row-' + id);\n\t\t\t\t\tif (row) row.remove();\n\t\t\t\t\tshowToast('Address deleted successfully', 'success');\n\t\t\t\t} else {\n\t\t\t\t\tshowToast(result.data.error || 'Failed to delete address', 'error');\n\t\t\t\t}\n\t\t\t}).catch(function(err) {\n\t\t\t\tdocument.getElementById('delete-address-modal').classList.add('hidden');\n\t\t\t\tshowToast('Failed to delete address. Please try again.', 'error');\n\t\t\t});\n\t\t};\n\t}\n\tfunction deleteAllAddresses() {\n\t\tfetch('/projects/' + projectID + '/addresses?tab=' + currentTab, {\n\t\t\tmethod: 'DELETE',\n\t\t\theaders: { 'X-CSRF-Token': csrfTokenVal, 'Content-Type': 'application/json' }\n\t\t}).then(function(resp) {\n\t\t\treturn resp.json().then(function(data) { return { ok: resp.ok, data: data }; });\n\t\t}).then(function(result) {\n\t\t\tif (result.ok && result.data.success) {\n\t\t\t\twindow.location.href = result.data.redirect;\n\t\t\t} else {\n\t\t\t\tshowToast(result.data.error || 'Failed to delete addresses', 'error');\n\t\t\t}\n\t\t}).catch(function(err) {\n\t\t\tshowToast('Failed to delete addresses. Please try again.', 'error');\n\t\t});\n\t}\n\tdocument.addEventListener('keydown', function(e) {\n\t\tif (e.key === 'Escape') {\n\t\t\tcloseConfigSlideOver();\n\t\t\tcloseAddressModal();\n\t\t\tdocument.getElementById('delete-address-modal').classList.add('hidden');\n\t\t\tdocument.getElementById('delete-all-modal').classList.add('hidden');\n\t\t}\n\t});\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
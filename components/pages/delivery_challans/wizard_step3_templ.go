@@ -454,7 +454,7 @@ func WizardStep3(
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, " locations), one per line</p><textarea id=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, " locations), one per line or as ranges like ABC000100-ABC000599</p><textarea id=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -467,7 +467,7 @@ func WizardStep3(
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "\" rows=\"8\" class=\"w-full rounded-lg border-gray-300 shadow-sm focus:border-brand-500 focus:ring-brand-500 text-sm font-mono\" placeholder=\"Enter serial numbers, one per line...\" data-product-id=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "\" rows=\"8\" class=\"w-full rounded-lg border-gray-300 shadow-sm focus:border-brand-500 focus:ring-brand-500 text-sm font-mono\" placeholder=\"Enter serial numbers one per line, or ranges like ABC000100-ABC000599...\" data-product-id=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -530,7 +530,7 @@ func WizardStep3(
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "<div class=\"flex items-center justify-between mt-6\"><a href=\"javascript:history.back()\" class=\"btn btn-secondary\">&#x2190; Back</a> <button type=\"submit\" class=\"btn btn-primary\" id=\"step3-next\">Next: Review &#x2192;</button></div></form></div><script src=\"/static/js/serial-assignment.js\"></script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "<div class=\"flex items-center justify-between mt-6\"><a href=\"javascript:history.back()\" class=\"btn btn-secondary\">&#x2190; Back</a> <button type=\"submit\" class=\"btn btn-primary\" id=\"step3-next\">Next: Review &#x2192;</button></div></form></div><script src=\"/static/js/serial-input.js\"></script><script src=\"/static/js/serial-assignment.js\"></script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -12,7 +12,7 @@ import (
 // ShipStep1Prefill holds pre-populated values for the edit wizard's Step 1 form.
 // All fields map directly to form inputs. Zero values mean "no pre-fill".
 type ShipStep1Prefill struct {
-	TemplateID      *int   // nil = no selection
+	TemplateID      *int // nil = no selection
 	NumLocations    int
 	ChallanDate     string // "YYYY-MM-DD" or ""
 	TransporterID   int    // 0 = no pre-selection
@@ -51,31 +51,91 @@ func preselectedIDsJSON(ids []int) string {
 }
 
 // prefillAssignmentsForProduct returns newline-joined serials assigned to
-// a specific (product, shipTo) pair, or "" if the map is nil/missing.
-func prefillAssignmentsForProduct(m map[string][]string, productID, shipToID int) string {
+// a specific (serial slot, shipTo) pair, or "" if the map is nil/missing.
+func prefillAssignmentsForProduct(m map[string][]string, slotKey string, shipToID int) string {
 	if m == nil {
 		return ""
 	}
-	key := fmt.Sprintf("%d_%d", productID, shipToID)
+	key := fmt.Sprintf("%s_%d", slotKey, shipToID)
 	return strings.Join(m[key], "\n")
 }
 
 // serialTextareaClass returns the appropriate Tailwind border class for a
-// serial textarea depending on whether that product has a server-side error.
-func serialTextareaClass(serialErrors map[int]string, productID int) string {
-	if serialErrors[productID] != "" {
+// serial textarea depending on whether that slot has a server-side error.
+func serialTextareaClass(serialErrors map[string]string, slotKey string) string {
+	if serialErrors[slotKey] != "" {
 		return "mt-1 block w-full rounded-md border-red-500 shadow-sm font-mono text-sm"
 	}
 	return "mt-1 block w-full rounded-md border-gray-300 shadow-sm font-mono text-sm"
 }
 
-// prefillSerialsForProduct returns the newline-joined serial numbers for productID
-// from the prefill map, or "" if the map is nil or has no entry for that product.
-func prefillSerialsForProduct(m map[int][]string, productID int) string {
+// prefillSerialsForProduct returns the newline-joined serial numbers for a
+// serial slot from the prefill map, or "" if the map is nil or has no entry for it.
+func prefillSerialsForProduct(m map[string][]string, slotKey string) string {
 	if m == nil {
 		return ""
 	}
-	return strings.Join(m[productID], "\n")
+	return strings.Join(m[slotKey], "\n")
+}
+
+// serialSlot is one serial textarea of the serial step: a plain product, or
+// one component of a kit.
+type serialSlot struct {
+	Key     string // models.SerialSlotKey of the slot, used in the form field names
+	Name    string
+	PerUnit int // serials per unit of the template product
+}
+
+// serialSlots lists the serial textareas of a template product, one per
+// component when it is a kit.
+func serialSlots(p *models.TemplateProductRow) []serialSlot {
+	if !p.IsKit() {
+		return []serialSlot{{Key: models.SerialSlotKey(p.ID, 0), Name: p.ItemName, PerUnit: 1}}
+	}
+	slots := make([]serialSlot, 0, len(p.Components))
+	for _, c := range p.Components {
+		slots = append(slots, serialSlot{
+			Key:     models.SerialSlotKey(p.ID, c.ProductID),
+			Name:    p.ItemName + " — " + c.ItemName,
+			PerUnit: c.Quantity,
+		})
+	}
+	return slots
+}
+
+// WizardSerialPrefill keys parsed serial data by serial slot for re-rendering
+// the serial step: the serials of each slot, and the serials assigned per
+// "slot_shipToID".
+func WizardSerialPrefill(serialData []WizardSerialData) (serials, assignments map[string][]string) {
+	serials = make(map[string][]string)
+	assignments = make(map[string][]string)
+	add := func(key string, sd WizardSerialData) {
+		serials[key] = sd.AllSerials
+		for shipToID, assigned := range sd.Assignments {
+			assignments[fmt.Sprintf("%s_%d", key, shipToID)] = assigned
+		}
+	}
+	for _, pd := range serialData {
+		add(models.SerialSlotKey(pd.ProductID, 0), pd)
+		for _, cd := range pd.Components {
+			add(models.SerialSlotKey(pd.ProductID, cd.ProductID), cd)
+		}
+	}
+	return serials, assignments
+}
+
+// serialHiddenFields carries serial data through the review page, with the
+// same field names as the serial step.
+func serialHiddenFields(serialData []WizardSerialData) []QuantityHiddenField {
+	serials, assignments := WizardSerialPrefill(serialData)
+	fields := make([]QuantityHiddenField, 0, len(serials)+len(assignments))
+	for key, s := range serials {
+		fields = append(fields, QuantityHiddenField{Name: "serials_" + key, Value: joinStrings(s, "\n")})
+	}
+	for key, s := range assignments {
+		fields = append(fields, QuantityHiddenField{Name: "assign_" + key, Value: joinStrings(s, "\n")})
+	}
+	return fields
 }
 
 // vehiclesJSON serializes a slice of TransporterVehicle pointers to a JSON
@@ -134,9 +194,30 @@ func joinStrings(items []string, sep string) string {
 }
 
 // QuantityAddress pairs an address ID with a display name for the quantity grid columns.
+// PlanRemaining holds, per product ID, the BOQ plan units still to go to the
+// address; products not planned for it are absent.
 type QuantityAddress struct {
-	ID   int
-	Name string
+	ID            int
+	Name          string
+	PlanRemaining map[int]int
+}
+
+// planRemainingText describes what is left of the BOQ plan for a cell.
+func planRemainingText(remaining int) string {
+	if remaining < 0 {
+		return fmt.Sprintf("Plan: %d over", -remaining)
+	}
+	return fmt.Sprintf("Plan: %d left", remaining)
+}
+
+// hasBOQPlan reports whether any of the addresses has a BOQ plan to check against.
+func hasBOQPlan(addrs []QuantityAddress) bool {
+	for _, a := range addrs {
+		if len(a.PlanRemaining) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // QuantityHiddenField holds a name/value pair for carrying quantity data through form steps.
@@ -176,5 +257,7 @@ func productExpectedTotal(quantities map[int]int, productID, defaultQty, numLoca
 type WizardSerialData struct {
 	ProductID   int
 	AllSerials  []string
-	Assignments map[int][]string // shipToAddressID -> serials
+	Assignments map[int][]string   // shipToAddressID -> serials
+	InputError  string             // set when the pasted serials could not be read, e.g. an oversized range
+	Components  []WizardSerialData // serials per component when the product is a kit; the kit itself has none
 }
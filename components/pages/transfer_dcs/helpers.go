@@ -24,9 +24,30 @@ type TransferStep1Prefill struct {
 }
 
 // QuantityAddress pairs an address ID with a display name for the quantity grid columns.
+// PlanRemaining holds, per product ID, the BOQ plan units still to go to the
+// address; products not planned for it are absent.
 type QuantityAddress struct {
-	ID   int
-	Name string
+	ID            int
+	Name          string
+	PlanRemaining map[int]int
+}
+
+// planRemainingText describes what is left of the BOQ plan for a cell.
+func planRemainingText(remaining int) string {
+	if remaining < 0 {
+		return fmt.Sprintf("Plan: %d over", -remaining)
+	}
+	return fmt.Sprintf("Plan: %d left", remaining)
+}
+
+// hasBOQPlan reports whether any of the addresses has a BOQ plan to check against.
+func hasBOQPlan(addrs []QuantityAddress) bool {
+	for _, a := range addrs {
+		if len(a.PlanRemaining) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // QuantityHiddenField holds a name/value pair for carrying quantity data through form steps.
@@ -40,6 +61,7 @@ type QuantityHiddenField struct {
 type TransferSerialData struct {
 	ProductID  int
 	AllSerials []string
+	InputError string // set when the pasted serials could not be read, e.g. an oversized range
 }
 
 // prefillStr returns fn(p) if p is non-nil, or "" otherwise.
@@ -288,7 +288,7 @@ func SplitStep3(
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "<div><label class=\"block text-sm font-medium text-gray-700 mb-1\">Serial Numbers (one per line)</label> ")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "<div><label class=\"block text-sm font-medium text-gray-700 mb-1\">Serial Numbers (one per line, ranges allowed)</label> ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -323,7 +323,7 @@ func SplitStep3(
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "\" placeholder=\"Enter serial numbers, one per line...\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "\" placeholder=\"Enter serial numbers one per line, or ranges like ABC000100-ABC000599...\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
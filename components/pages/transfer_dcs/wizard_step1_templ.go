@@ -27,6 +27,7 @@ func WizardStep1(
 	csrfToken string,
 	prefill *TransferStep1Prefill,
 	tdcID int,
+	editVersion int,
 ) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -80,7 +81,7 @@ func WizardStep1(
 		var templ_7745c5c3_Var2 templ.SafeURL
 		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinURLErrs(templ.SafeURL(transferWizardURL(currentProject.ID, tdcID, "/step2")))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 38, Col: 80}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 39, Col: 80}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 		if templ_7745c5c3_Err != nil {
@@ -93,308 +94,316 @@ func WizardStep1(
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(csrfToken)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 40, Col: 67}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 41, Col: 67}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\"><div class=\"bg-white shadow rounded-lg p-6 space-y-4\"><div><label class=\"block text-sm font-medium text-gray-700\">Template</label> <select name=\"template_id\" required class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">Select a template...</option> ")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = partials.EditVersionField(editVersion).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<div class=\"bg-white shadow rounded-lg p-6 space-y-4\"><div><label class=\"block text-sm font-medium text-gray-700\">Template</label> <select name=\"template_id\" required class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">Select a template...</option> ")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		for _, t := range templates {
 			if prefill != nil && prefill.TemplateID != nil && *prefill.TemplateID == t.ID {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var4 string
 				templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", t.ID))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 48, Col: 47}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 50, Col: 47}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "\" selected>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\" selected>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var5 string
 				templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(t.Name)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 48, Col: 67}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 50, Col: 67}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			} else {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var6 string
 				templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", t.ID))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 50, Col: 47}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 52, Col: 47}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var7 string
 				templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(t.Name)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 50, Col: 58}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 52, Col: 58}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "</select></div><div><label class=\"block text-sm font-medium text-gray-700 mb-1\">Hub / Transit Location</label><div id=\"hub-picker\" class=\"relative\" data-addresses=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "</select></div><div><label class=\"block text-sm font-medium text-gray-700 mb-1\">Hub / Transit Location</label><div id=\"hub-picker\" class=\"relative\" data-addresses=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var8 string
 		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(hubAddressesJSON(hubAddresses))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 57, Col: 90}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 59, Col: 90}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "\" data-preselected-id=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "\" data-preselected-id=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var9 string
 		templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(hubPreselectedJSON(prefill))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 57, Col: 142}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 59, Col: 142}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "\"><div id=\"hub-tags\" class=\"flex flex-wrap gap-1.5 min-h-[42px] p-2 border border-gray-300 rounded-lg bg-white cursor-text focus-within:ring-2 focus-within:ring-blue-500 focus-within:border-blue-500\" onclick=\"document.getElementById('hub-search').focus()\"><input type=\"text\" id=\"hub-search\" placeholder=\"Type to search hub locations...\" autocomplete=\"off\" class=\"flex-1 min-w-[200px] border-0 p-0 text-sm focus:ring-0 focus:outline-none placeholder-gray-400\"></div><div id=\"hub-hidden-inputs\"></div><div id=\"hub-dropdown\" class=\"hidden absolute z-20 mt-1 w-full bg-white border border-gray-200 rounded-lg shadow-lg max-h-60 overflow-y-auto\"></div></div></div><div><label class=\"block text-sm font-medium text-gray-700\">Challan Date</label> <input type=\"date\" name=\"challan_date\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "\"><div id=\"hub-tags\" class=\"flex flex-wrap gap-1.5 min-h-[42px] p-2 border border-gray-300 rounded-lg bg-white cursor-text focus-within:ring-2 focus-within:ring-blue-500 focus-within:border-blue-500\" onclick=\"document.getElementById('hub-search').focus()\"><input type=\"text\" id=\"hub-search\" placeholder=\"Type to search hub locations...\" autocomplete=\"off\" class=\"flex-1 min-w-[200px] border-0 p-0 text-sm focus:ring-0 focus:outline-none placeholder-gray-400\"></div><div id=\"hub-hidden-inputs\"></div><div id=\"hub-dropdown\" class=\"hidden absolute z-20 mt-1 w-full bg-white border border-gray-200 rounded-lg shadow-lg max-h-60 overflow-y-auto\"></div></div></div><div><label class=\"block text-sm font-medium text-gray-700\">Challan Date</label> <input type=\"date\" name=\"challan_date\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var10 string
 		templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(prefillStr(prefill, func(p *TransferStep1Prefill) string { return p.ChallanDate }))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 77, Col: 134}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 79, Col: 134}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "\" required class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"></div><div><label class=\"block text-sm font-medium text-gray-700\">Transporter</label> <select name=\"transporter_id\" id=\"transporter-select\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">-- None --</option> ")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "\" required class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"></div><div><label class=\"block text-sm font-medium text-gray-700\">Transporter</label> <select name=\"transporter_id\" id=\"transporter-select\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">-- None --</option> ")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		for _, t := range transporters {
 			if prefill != nil && prefill.TransporterID == t.ID {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var11 string
 				templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", t.ID))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 85, Col: 47}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 87, Col: 47}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\" data-vehicles=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "\" data-vehicles=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var12 string
 				templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(vehiclesJSON(t.Vehicles))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 85, Col: 90}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 87, Col: 90}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "\" selected>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "\" selected>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var13 string
 				templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(t.CompanyName)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 85, Col: 117}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 87, Col: 117}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			} else {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var14 string
 				templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", t.ID))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 87, Col: 47}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 89, Col: 47}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "\" data-vehicles=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "\" data-vehicles=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var15 string
 				templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(vehiclesJSON(t.Vehicles))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 87, Col: 90}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 89, Col: 90}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var16 string
 				templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(t.CompanyName)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 87, Col: 108}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 89, Col: 108}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "</select> <input type=\"hidden\" name=\"transporter_name\" id=\"transporter-name-hidden\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "</select> <input type=\"hidden\" name=\"transporter_name\" id=\"transporter-name-hidden\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var17 string
 		templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(prefillStr(prefill, func(p *TransferStep1Prefill) string { return p.TransporterName }))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 91, Col: 173}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 93, Col: 173}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "\"> <input type=\"hidden\" id=\"prefill-vehicle-number\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "\"> <input type=\"hidden\" id=\"prefill-vehicle-number\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var18 string
 		templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(prefillStr(prefill, func(p *TransferStep1Prefill) string { return p.VehicleNumber }))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 92, Col: 146}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 94, Col: 146}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "\"></div><div><label class=\"block text-sm font-medium text-gray-700\">Vehicle</label> <select name=\"vehicle_number\" id=\"vehicle-select\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">-- Select transporter first --</option></select> <input type=\"hidden\" name=\"driver_name\" id=\"driver-name-hidden\"> <input type=\"hidden\" name=\"driver_phone\" id=\"driver-phone-hidden\"></div><div><label class=\"block text-sm font-medium text-gray-700\">E-Way Bill Number</label> <input type=\"text\" name=\"eway_bill_number\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "\"></div><div><label class=\"block text-sm font-medium text-gray-700\">Vehicle</label> <select name=\"vehicle_number\" id=\"vehicle-select\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">-- Select transporter first --</option></select> <input type=\"hidden\" name=\"driver_name\" id=\"driver-name-hidden\"> <input type=\"hidden\" name=\"driver_phone\" id=\"driver-phone-hidden\"></div><div><label class=\"block text-sm font-medium text-gray-700\">E-Way Bill Number</label> <input type=\"text\" name=\"eway_bill_number\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var19 string
 		templ_7745c5c3_Var19, templ_7745c5c3_Err = templ.JoinStringErrs(prefillStr(prefill, func(p *TransferStep1Prefill) string { return p.EwayBillNumber }))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 104, Col: 141}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 106, Col: 141}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var19))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"></div><div><label class=\"block text-sm font-medium text-gray-700\">Docket Number</label> <input type=\"text\" name=\"docket_number\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"></div><div><label class=\"block text-sm font-medium text-gray-700\">Docket Number</label> <input type=\"text\" name=\"docket_number\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var20 string
 		templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinStringErrs(prefillStr(prefill, func(p *TransferStep1Prefill) string { return p.DocketNumber }))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 108, Col: 136}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step1.templ`, Line: 110, Col: 136}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"></div><div><label class=\"block text-sm font-medium text-gray-700\">Tax Type</label> <select name=\"tax_type\" required class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"cgst_sgst\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"></div><div><label class=\"block text-sm font-medium text-gray-700\">Tax Type</label> <select name=\"tax_type\" required class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"cgst_sgst\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		if prefill != nil && prefill.TaxType == "cgst_sgst" {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, " selected")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, " selected")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, ">CGST + SGST</option> <option value=\"igst\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, ">CGST + SGST</option> <option value=\"igst\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		if prefill != nil && prefill.TaxType == "igst" {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, " selected")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, " selected")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, ">IGST</option></select></div><div><label class=\"block text-sm font-medium text-gray-700\">Reverse Charge</label> <select name=\"reverse_charge\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"N\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, ">IGST</option></select></div><div><label class=\"block text-sm font-medium text-gray-700\">Reverse Charge</label> <select name=\"reverse_charge\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"N\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		if prefill != nil && prefill.ReverseCharge == "N" {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, " selected")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, " selected")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, ">No</option> <option value=\"Y\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, ">No</option> <option value=\"Y\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		if prefill != nil && prefill.ReverseCharge == "Y" {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, " selected")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, " selected")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, ">Yes</option></select></div></div><div class=\"mt-6 flex justify-end\"><button type=\"submit\" class=\"bg-blue-600 text-white px-6 py-2 rounded-md hover:bg-blue-700\">Next: Select Addresses →</button></div></form></div><script>\n\t\t// Hub location searchable picker (single-select)\n\t\t(function() {\n\t\t\tvar picker = document.getElementById('hub-picker');\n\t\t\tvar allAddresses = JSON.parse(picker.dataset.addresses || '[]');\n\t\t\tvar searchInput = document.getElementById('hub-search');\n\t\t\tvar dropdown = document.getElementById('hub-dropdown');\n\t\t\tvar tagsContainer = document.getElementById('hub-tags');\n\t\t\tvar hiddenContainer = document.getElementById('hub-hidden-inputs');\n\t\t\tvar selectedAddr = null;\n\n\t\t\tfunction escapeHtml(str) {\n\t\t\t\tvar div = document.createElement('div');\n\t\t\t\tdiv.textContent = str;\n\t\t\t\treturn div.innerHTML;\n\t\t\t}\n\n\t\t\tfunction render() {\n\t\t\t\ttagsContainer.querySelectorAll('.hub-tag').forEach(function(el) { el.remove(); });\n\t\t\t\thiddenContainer.innerHTML = '';\n\n\t\t\t\tif (selectedAddr) {\n\t\t\t\t\tvar tag = document.createElement('span');\n\t\t\t\t\ttag.className = 'hub-tag inline-flex items-center gap-1 px-2 py-0.5 rounded-md text-xs font-medium bg-blue-100 text-blue-800';\n\t\t\t\t\ttag.innerHTML = '<span>' + escapeHtml(selectedAddr.name) + '</span>' +\n\t\t\t\t\t\t'<button type=\"button\" class=\"ml-1 hover:text-blue-600 font-bold\" aria-label=\"Remove\">&times;</button>';\n\t\t\t\t\ttag.querySelector('button').addEventListener('click', function(e) {\n\t\t\t\t\t\te.stopPropagation();\n\t\t\t\t\t\tselectedAddr = null;\n\t\t\t\t\t\trender();\n\t\t\t\t\t\tsearchInput.focus();\n\t\t\t\t\t});\n\t\t\t\t\ttagsContainer.insertBefore(tag, searchInput);\n\n\t\t\t\t\tvar input = document.createElement('input');\n\t\t\t\t\tinput.type = 'hidden';\n\t\t\t\t\tinput.name = 'hub_address_id';\n\t\t\t\t\tinput.value = selectedAddr.id;\n\t\t\t\t\thiddenContainer.appendChild(input);\n\n\t\t\t\t\tsearchInput.placeholder = '';\n\t\t\t\t} else {\n\t\t\t\t\tsearchInput.placeholder = 'Type to search hub locations...';\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tfunction selectAddress(addr) {\n\t\t\t\tselectedAddr = addr;\n\t\t\t\tsearchInput.value = '';\n\t\t\t\tdropdown.classList.add('hidden');\n\t\t\t\trender();\n\t\t\t\tsearchInput.focus();\n\t\t\t}\n\n\t\t\tfunction showDropdown(query) {\n\t\t\t\tvar filtered = allAddresses.filter(function(a) {\n\t\t\t\t\tif (selectedAddr && a.id === selectedAddr.id) return false;\n\t\t\t\t\tif (!query) return true;\n\t\t\t\t\treturn a.name.toLowerCase().indexOf(query.toLowerCase()) !== -1;\n\t\t\t\t});\n\t\t\t\tif (filtered.length === 0) {\n\t\t\t\t\tdropdown.innerHTML = '<div class=\"px-3 py-2 text-sm text-gray-500\">No matches found</div>';\n\t\t\t\t} else {\n\t\t\t\t\tdropdown.innerHTML = '';\n\t\t\t\t\tfiltered.slice(0, 20).forEach(function(addr) {\n\t\t\t\t\t\tvar item = document.createElement('div');\n\t\t\t\t\t\titem.className = 'px-3 py-2 text-sm cursor-pointer hover:bg-blue-50 transition-colors';\n\t\t\t\t\t\titem.textContent = addr.name;\n\t\t\t\t\t\titem.addEventListener('mousedown', function(e) {\n\t\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\t\tselectAddress(addr);\n\t\t\t\t\t\t});\n\t\t\t\t\t\tdropdown.appendChild(item);\n\t\t\t\t\t});\n\t\t\t\t\tif (filtered.length > 20) {\n\t\t\t\t\t\tvar more = document.createElement('div');\n\t\t\t\t\t\tmore.className = 'px-3 py-1.5 text-xs text-gray-400 border-t';\n\t\t\t\t\t\tmore.textContent = '+ ' + (filtered.length - 20) + ' more — refine your search';\n\t\t\t\t\t\tdropdown.appendChild(more);\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\tdropdown.classList.remove('hidden');\n\t\t\t}\n\n\t\t\tsearchInput.addEventListener('focus', function() { showDropdown(this.value); });\n\t\t\tsearchInput.addEventListener('input', function() { showDropdown(this.value); });\n\t\t\tsearchInput.addEventListener('blur', function() {\n\t\t\t\tsetTimeout(function() { dropdown.classList.add('hidden'); }, 150);\n\t\t\t});\n\t\t\tsearchInput.addEventListener('keydown', function(e) {\n\t\t\t\tif (e.key === 'Backspace' && this.value === '' && selectedAddr) {\n\t\t\t\t\tselectedAddr = null;\n\t\t\t\t\trender();\n\t\t\t\t}\n\t\t\t});\n\n\t\t\trender();\n\t\t\t// Prefill from data attribute\n\t\t\tvar preselectedId = parseInt(picker.dataset.preselectedId, 10);\n\t\t\tif (preselectedId) {\n\t\t\t\tvar addr = allAddresses.find(function(a) { return a.id === preselectedId; });\n\t\t\t\tif (addr) selectAddress(addr);\n\t\t\t}\n\t\t})();\n\n\t\t// Transporter / vehicle logic\n\t\tdocument.getElementById('transporter-select').addEventListener('change', function() {\n\t\t\tvar opt = this.options[this.selectedIndex];\n\t\t\tdocument.getElementById('transporter-name-hidden').value = opt.text !== '-- None --' ? opt.text : '';\n\t\t\tvar vehicleSelect = document.getElementById('vehicle-select');\n\t\t\tvehicleSelect.innerHTML = '<option value=\"\">-- Select vehicle --</option>';\n\t\t\tdocument.getElementById('driver-name-hidden').value = '';\n\t\t\tdocument.getElementById('driver-phone-hidden').value = '';\n\t\t\tif (!opt.value) return;\n\t\t\ttry {\n\t\t\t\tvar vehicles = JSON.parse(opt.getAttribute('data-vehicles') || '[]');\n\t\t\t\tvehicles.forEach(function(v) {\n\t\t\t\t\tvar o = document.createElement('option');\n\t\t\t\t\to.value = v.vehicle_number;\n\t\t\t\t\to.textContent = v.vehicle_number + (v.vehicle_type ? ' (' + v.vehicle_type + ')' : '');\n\t\t\t\t\to.setAttribute('data-driver', v.driver_name || '');\n\t\t\t\t\to.setAttribute('data-phone', v.driver_phone1 || '');\n\t\t\t\t\tvehicleSelect.appendChild(o);\n\t\t\t\t});\n\t\t\t} catch(e) {}\n\t\t});\n\t\tdocument.getElementById('vehicle-select').addEventListener('change', function() {\n\t\t\tvar opt = this.options[this.selectedIndex];\n\t\t\tdocument.getElementById('driver-name-hidden').value = opt.getAttribute('data-driver') || '';\n\t\t\tdocument.getElementById('driver-phone-hidden').value = opt.getAttribute('data-phone') || '';\n\t\t});\n\t\t// Auto-populate vehicle dropdown and pre-select vehicle when editing\n\t\t(function() {\n\t\t\tvar transporterSelect = document.getElementById('transporter-select');\n\t\t\tvar prefillVehicle = document.getElementById('prefill-vehicle-number');\n\t\t\tif (transporterSelect.value && prefillVehicle && prefillVehicle.value) {\n\t\t\t\t// Trigger change to populate vehicles\n\t\t\t\ttransporterSelect.dispatchEvent(new Event('change'));\n\t\t\t\t// Select the prefilled vehicle\n\t\t\t\tvar vehicleSelect = document.getElementById('vehicle-select');\n\t\t\t\tvehicleSelect.value = prefillVehicle.value;\n\t\t\t\tif (vehicleSelect.value === prefillVehicle.value) {\n\t\t\t\t\tvehicleSelect.dispatchEvent(new Event('change'));\n\t\t\t\t}\n\t\t\t}\n\t\t})();\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, ">Yes</option></select></div></div><div class=\"mt-6 flex justify-end\"><button type=\"submit\" class=\"bg-blue-600 text-white px-6 py-2 rounded-md hover:bg-blue-700\">Next: Select Addresses →</button></div></form></div><script>\n\t\t// Hub location searchable picker (single-select)\n\t\t(function() {\n\t\t\tvar picker = document.getElementById('hub-picker');\n\t\t\tvar allAddresses = JSON.parse(picker.dataset.addresses || '[]');\n\t\t\tvar searchInput = document.getElementById('hub-search');\n\t\t\tvar dropdown = document.getElementById('hub-dropdown');\n\t\t\tvar tagsContainer = document.getElementById('hub-tags');\n\t\t\tvar hiddenContainer = document.getElementById('hub-hidden-inputs');\n\t\t\tvar selectedAddr = null;\n\n\t\t\tfunction escapeHtml(str) {\n\t\t\t\tvar div = document.createElement('div');\n\t\t\t\tdiv.textContent = str;\n\t\t\t\treturn div.innerHTML;\n\t\t\t}\n\n\t\t\tfunction render() {\n\t\t\t\ttagsContainer.querySelectorAll('.hub-tag').forEach(function(el) { el.remove(); });\n\t\t\t\thiddenContainer.innerHTML = '';\n\n\t\t\t\tif (selectedAddr) {\n\t\t\t\t\tvar tag = document.createElement('span');\n\t\t\t\t\ttag.className = 'hub-tag inline-flex items-center gap-1 px-2 py-0.5 rounded-md text-xs font-medium bg-blue-100 text-blue-800';\n\t\t\t\t\ttag.innerHTML = '<span>' + escapeHtml(selectedAddr.name) + '</span>' +\n\t\t\t\t\t\t'<button type=\"button\" class=\"ml-1 hover:text-blue-600 font-bold\" aria-label=\"Remove\">&times;</button>';\n\t\t\t\t\ttag.querySelector('button').addEventListener('click', function(e) {\n\t\t\t\t\t\te.stopPropagation();\n\t\t\t\t\t\tselectedAddr = null;\n\t\t\t\t\t\trender();\n\t\t\t\t\t\tsearchInput.focus();\n\t\t\t\t\t});\n\t\t\t\t\ttagsContainer.insertBefore(tag, searchInput);\n\n\t\t\t\t\tvar input = document.createElement('input');\n\t\t\t\t\tinput.type = 'hidden';\n\t\t\t\t\tinput.name = 'hub_address_id';\n\t\t\t\t\tinput.value = selectedAddr.id;\n\t\t\t\t\thiddenContainer.appendChild(input);\n\n\t\t\t\t\tsearchInput.placeholder = '';\n\t\t\t\t} else {\n\t\t\t\t\tsearchInput.placeholder = 'Type to search hub locations...';\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tfunction selectAddress(addr) {\n\t\t\t\tselectedAddr = addr;\n\t\t\t\tsearchInput.value = '';\n\t\t\t\tdropdown.classList.add('hidden');\n\t\t\t\trender();\n\t\t\t\tsearchInput.focus();\n\t\t\t}\n\n\t\t\tfunction showDropdown(query) {\n\t\t\t\tvar filtered = allAddresses.filter(function(a) {\n\t\t\t\t\tif (selectedAddr && a.id === selectedAddr.id) return false;\n\t\t\t\t\tif (!query) return true;\n\t\t\t\t\treturn a.name.toLowerCase().indexOf(query.toLowerCase()) !== -1;\n\t\t\t\t});\n\t\t\t\tif (filtered.length === 0) {\n\t\t\t\t\tdropdown.innerHTML = '<div class=\"px-3 py-2 text-sm text-gray-500\">No matches found</div>';\n\t\t\t\t} else {\n\t\t\t\t\tdropdown.innerHTML = '';\n\t\t\t\t\tfiltered.slice(0, 20).forEach(function(addr) {\n\t\t\t\t\t\tvar item = document.createElement('div');\n\t\t\t\t\t\titem.className = 'px-3 py-2 text-sm cursor-pointer hover:bg-blue-50 transition-colors';\n\t\t\t\t\t\titem.textContent = addr.name;\n\t\t\t\t\t\titem.addEventListener('mousedown', function(e) {\n\t\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\t\tselectAddress(addr);\n\t\t\t\t\t\t});\n\t\t\t\t\t\tdropdown.appendChild(item);\n\t\t\t\t\t});\n\t\t\t\t\tif (filtered.length > 20) {\n\t\t\t\t\t\tvar more = document.createElement('div');\n\t\t\t\t\t\tmore.className = 'px-3 py-1.5 text-xs text-gray-400 border-t';\n\t\t\t\t\t\tmore.textContent = '+ ' + (filtered.length - 20) + ' more — refine your search';\n\t\t\t\t\t\tdropdown.appendChild(more);\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\tdropdown.classList.remove('hidden');\n\t\t\t}\n\n\t\t\tsearchInput.addEventListener('focus', function() { showDropdown(this.value); });\n\t\t\tsearchInput.addEventListener('input', function() { showDropdown(this.value); });\n\t\t\tsearchInput.addEventListener('blur', function() {\n\t\t\t\tsetTimeout(function() { dropdown.classList.add('hidden'); }, 150);\n\t\t\t});\n\t\t\tsearchInput.addEventListener('keydown', function(e) {\n\t\t\t\tif (e.key === 'Backspace' && this.value === '' && selectedAddr) {\n\t\t\t\t\tselectedAddr = null;\n\t\t\t\t\trender();\n\t\t\t\t}\n\t\t\t});\n\n\t\t\trender();\n\t\t\t// Prefill from data attribute\n\t\t\tvar preselectedId = parseInt(picker.dataset.preselectedId, 10);\n\t\t\tif (preselectedId) {\n\t\t\t\tvar addr = allAddresses.find(function(a) { return a.id === preselectedId; });\n\t\t\t\tif (addr) selectAddress(addr);\n\t\t\t}\n\t\t})();\n\n\t\t// Transporter / vehicle logic\n\t\tdocument.getElementById('transporter-select').addEventListener('change', function() {\n\t\t\tvar opt = this.options[this.selectedIndex];\n\t\t\tdocument.getElementById('transporter-name-hidden').value = opt.text !== '-- None --' ? opt.text : '';\n\t\t\tvar vehicleSelect = document.getElementById('vehicle-select');\n\t\t\tvehicleSelect.innerHTML = '<option value=\"\">-- Select vehicle --</option>';\n\t\t\tdocument.getElementById('driver-name-hidden').value = '';\n\t\t\tdocument.getElementById('driver-phone-hidden').value = '';\n\t\t\tif (!opt.value) return;\n\t\t\ttry {\n\t\t\t\tvar vehicles = JSON.parse(opt.getAttribute('data-vehicles') || '[]');\n\t\t\t\tvehicles.forEach(function(v) {\n\t\t\t\t\tvar o = document.createElement('option');\n\t\t\t\t\to.value = v.vehicle_number;\n\t\t\t\t\to.textContent = v.vehicle_number + (v.vehicle_type ? ' (' + v.vehicle_type + ')' : '');\n\t\t\t\t\to.setAttribute('data-driver', v.driver_name || '');\n\t\t\t\t\to.setAttribute('data-phone', v.driver_phone1 || '');\n\t\t\t\t\tvehicleSelect.appendChild(o);\n\t\t\t\t});\n\t\t\t} catch(e) {}\n\t\t});\n\t\tdocument.getElementById('vehicle-select').addEventListener('change', function() {\n\t\t\tvar opt = this.options[this.selectedIndex];\n\t\t\tdocument.getElementById('driver-name-hidden').value = opt.getAttribute('data-driver') || '';\n\t\t\tdocument.getElementById('driver-phone-hidden').value = opt.getAttribute('data-phone') || '';\n\t\t});\n\t\t// Auto-populate vehicle dropdown and pre-select vehicle when editing\n\t\t(function() {\n\t\t\tvar transporterSelect = document.getElementById('transporter-select');\n\t\t\tvar prefillVehicle = document.getElementById('prefill-vehicle-number');\n\t\t\tif (transporterSelect.value && prefillVehicle && prefillVehicle.value) {\n\t\t\t\t// Trigger change to populate vehicles\n\t\t\t\ttransporterSelect.dispatchEvent(new Event('change'));\n\t\t\t\t// Select the prefilled vehicle\n\t\t\t\tvar vehicleSelect = document.getElementById('vehicle-select');\n\t\t\t\tvehicleSelect.value = prefillVehicle.value;\n\t\t\t\tif (vehicleSelect.value === prefillVehicle.value) {\n\t\t\t\t\tvehicleSelect.dispatchEvent(new Event('change'));\n\t\t\t\t}\n\t\t\t}\n\t\t})();\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -40,6 +40,7 @@ func WizardStep2(
 	preselectedDispatchFromID int,
 	preselectedBillToID int,
 	tdcID int,
+	editVersion int,
 ) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -93,7 +94,7 @@ func WizardStep2(
 		var templ_7745c5c3_Var2 string
 		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(tmpl.Name)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 51, Col: 33}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 52, Col: 33}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 		if templ_7745c5c3_Err != nil {
@@ -106,7 +107,7 @@ func WizardStep2(
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(challanDate)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 51, Col: 74}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 52, Col: 74}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
@@ -119,7 +120,7 @@ func WizardStep2(
 		var templ_7745c5c3_Var4 string
 		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(transferDCNumber)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 54, Col: 48}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 55, Col: 48}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 		if templ_7745c5c3_Err != nil {
@@ -132,7 +133,7 @@ func WizardStep2(
 		var templ_7745c5c3_Var5 templ.SafeURL
 		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinURLErrs(templ.SafeURL(transferWizardURL(currentProject.ID, tdcID, "/step3")))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 59, Col: 80}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 60, Col: 80}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 		if templ_7745c5c3_Err != nil {
@@ -145,376 +146,384 @@ func WizardStep2(
 		var templ_7745c5c3_Var6 string
 		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(csrfToken)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 61, Col: 67}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 62, Col: 67}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\"><!-- Carry forward step 1 data --><input type=\"hidden\" name=\"template_id\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = partials.EditVersionField(editVersion).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<!-- Carry forward step 1 data --><input type=\"hidden\" name=\"template_id\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var7 string
 		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(templateID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 63, Col: 61}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 65, Col: 61}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\"> <input type=\"hidden\" name=\"hub_address_id\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "\"> <input type=\"hidden\" name=\"hub_address_id\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var8 string
 		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(hubAddressID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 64, Col: 66}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 66, Col: 66}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "\"> <input type=\"hidden\" name=\"challan_date\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "\"> <input type=\"hidden\" name=\"challan_date\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var9 string
 		templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(challanDate)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 65, Col: 63}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 67, Col: 63}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "\"> <input type=\"hidden\" name=\"transporter_name\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "\"> <input type=\"hidden\" name=\"transporter_name\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var10 string
 		templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(transporterName)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 66, Col: 71}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 68, Col: 71}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "\"> <input type=\"hidden\" name=\"vehicle_number\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "\"> <input type=\"hidden\" name=\"vehicle_number\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var11 string
 		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(vehicleNumber)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 67, Col: 67}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 69, Col: 67}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "\"> <input type=\"hidden\" name=\"eway_bill_number\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "\"> <input type=\"hidden\" name=\"eway_bill_number\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var12 string
 		templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(ewayBillNumber)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 68, Col: 70}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 70, Col: 70}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "\"> <input type=\"hidden\" name=\"docket_number\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "\"> <input type=\"hidden\" name=\"docket_number\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var13 string
 		templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(docketNumber)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 69, Col: 65}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 71, Col: 65}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "\"> <input type=\"hidden\" name=\"tax_type\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "\"> <input type=\"hidden\" name=\"tax_type\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var14 string
 		templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(taxType)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 70, Col: 55}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 72, Col: 55}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "\"> <input type=\"hidden\" name=\"reverse_charge\" value=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\"> <input type=\"hidden\" name=\"reverse_charge\" value=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var15 string
 		templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(reverseCharge)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 71, Col: 67}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 73, Col: 67}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\"><div class=\"bg-white shadow rounded-lg p-6 space-y-4\"><div><label class=\"block text-sm font-medium text-gray-700\">Bill From Address</label> <select name=\"bill_from_address_id\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">Select...</option> ")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "\"><div class=\"bg-white shadow rounded-lg p-6 space-y-4\"><div><label class=\"block text-sm font-medium text-gray-700\">Bill From Address</label> <select name=\"bill_from_address_id\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">Select...</option> ")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		for _, a := range billFromAddresses {
 			if preselectedBillFromID == a.ID {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var16 string
 				templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(a.ID))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 79, Col: 42}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 81, Col: 42}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "\" selected>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "\" selected>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var17 string
 				templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(a.DisplayName())
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 79, Col: 71}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 81, Col: 71}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			} else {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var18 string
 				templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(a.ID))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 81, Col: 42}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 83, Col: 42}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var19 string
 				templ_7745c5c3_Var19, templ_7745c5c3_Err = templ.JoinStringErrs(a.DisplayName())
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 81, Col: 62}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 83, Col: 62}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var19))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "</select></div><div><label class=\"block text-sm font-medium text-gray-700\">Dispatch From Address</label> <select name=\"dispatch_from_address_id\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">Select...</option> ")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "</select></div><div><label class=\"block text-sm font-medium text-gray-700\">Dispatch From Address</label> <select name=\"dispatch_from_address_id\" class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">Select...</option> ")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		for _, a := range dispatchFromAddresses {
 			if preselectedDispatchFromID == a.ID {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var20 string
 				templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(a.ID))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 92, Col: 42}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 94, Col: 42}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "\" selected>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "\" selected>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var21 string
 				templ_7745c5c3_Var21, templ_7745c5c3_Err = templ.JoinStringErrs(a.DisplayName())
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 92, Col: 71}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 94, Col: 71}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var21))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			} else {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var22 string
 				templ_7745c5c3_Var22, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(a.ID))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 94, Col: 42}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 96, Col: 42}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var22))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var23 string
 				templ_7745c5c3_Var23, templ_7745c5c3_Err = templ.JoinStringErrs(a.DisplayName())
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 94, Col: 62}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 96, Col: 62}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var23))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "</select></div><div><label class=\"block text-sm font-medium text-gray-700\">Bill To Address</label> <select name=\"bill_to_address_id\" required class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">Select...</option> ")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "</select></div><div><label class=\"block text-sm font-medium text-gray-700\">Bill To Address</label> <select name=\"bill_to_address_id\" required class=\"mt-1 block w-full rounded-md border-gray-300 shadow-sm\"><option value=\"\">Select...</option> ")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		for _, a := range billToAddresses {
 			if preselectedBillToID == a.ID {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var24 string
 				templ_7745c5c3_Var24, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(a.ID))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 105, Col: 42}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 107, Col: 42}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var24))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "\" selected>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "\" selected>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var25 string
 				templ_7745c5c3_Var25, templ_7745c5c3_Err = templ.JoinStringErrs(a.DisplayName())
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 105, Col: 71}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 107, Col: 71}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var25))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			} else {
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "<option value=\"")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var26 string
 				templ_7745c5c3_Var26, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(a.ID))
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 107, Col: 42}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 109, Col: 42}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var26))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "\">")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "\">")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				var templ_7745c5c3_Var27 string
 				templ_7745c5c3_Var27, templ_7745c5c3_Err = templ.JoinStringErrs(a.DisplayName())
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 107, Col: 62}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 109, Col: 62}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var27))
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "</option>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "</option>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "</select></div><!-- Ship To Addresses - tag picker (no limit) --><div><label class=\"block text-sm font-medium text-gray-700 mb-1\">Ship To Addresses (select destinations)</label><div id=\"ship-to-picker\" class=\"relative\" data-addresses=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "</select></div><!-- Ship To Addresses - tag picker (no limit) --><div><label class=\"block text-sm font-medium text-gray-700 mb-1\">Ship To Addresses (select destinations)</label><div id=\"ship-to-picker\" class=\"relative\" data-addresses=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var28 string
 		templ_7745c5c3_Var28, templ_7745c5c3_Err = templ.JoinStringErrs(shipToAddressesJSON(shipToAddresses))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 117, Col: 100}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 119, Col: 100}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var28))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "\" data-preselected=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "\" data-preselected=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var29 string
 		templ_7745c5c3_Var29, templ_7745c5c3_Err = templ.JoinStringErrs(preselectedIDsJSON(preselectedIDs))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 117, Col: 156}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 119, Col: 156}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var29))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "\"><!-- Selected tags --><div id=\"ship-to-tags\" class=\"flex flex-wrap gap-1.5 min-h-[42px] p-2 border border-gray-300 rounded-lg bg-white cursor-text focus-within:ring-2 focus-within:ring-blue-500 focus-within:border-blue-500\" onclick=\"document.getElementById('ship-to-search').focus()\"><input type=\"text\" id=\"ship-to-search\" placeholder=\"Type to search addresses...\" autocomplete=\"off\" class=\"flex-1 min-w-[200px] border-0 p-0 text-sm focus:ring-0 focus:outline-none placeholder-gray-400\"></div><!-- Hidden inputs container --><div id=\"ship-to-hidden-inputs\"></div><!-- Dropdown --><div id=\"ship-to-dropdown\" class=\"hidden absolute z-20 mt-1 w-full bg-white border border-gray-200 rounded-lg shadow-lg max-h-60 overflow-y-auto\"></div><p class=\"text-xs text-gray-500 mt-1\"><span id=\"ship-to-count\">0</span> selected</p></div></div></div><div class=\"mt-6 flex justify-between\"><button type=\"submit\" formaction=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "\"><!-- Selected tags --><div id=\"ship-to-tags\" class=\"flex flex-wrap gap-1.5 min-h-[42px] p-2 border border-gray-300 rounded-lg bg-white cursor-text focus-within:ring-2 focus-within:ring-blue-500 focus-within:border-blue-500\" onclick=\"document.getElementById('ship-to-search').focus()\"><input type=\"text\" id=\"ship-to-search\" placeholder=\"Type to search addresses...\" autocomplete=\"off\" class=\"flex-1 min-w-[200px] border-0 p-0 text-sm focus:ring-0 focus:outline-none placeholder-gray-400\"></div><!-- Hidden inputs container --><div id=\"ship-to-hidden-inputs\"></div><!-- Dropdown --><div id=\"ship-to-dropdown\" class=\"hidden absolute z-20 mt-1 w-full bg-white border border-gray-200 rounded-lg shadow-lg max-h-60 overflow-y-auto\"></div><p class=\"text-xs text-gray-500 mt-1\"><span id=\"ship-to-count\">0</span> selected</p></div></div></div><div class=\"mt-6 flex justify-between\"><button type=\"submit\" formaction=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var30 string
 		templ_7745c5c3_Var30, templ_7745c5c3_Err = templ.JoinStringErrs(string(templ.SafeURL(transferWizardURL(currentProject.ID, tdcID, "/back-to-step1"))))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 143, Col: 123}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/pages/transfer_dcs/wizard_step2.templ`, Line: 145, Col: 123}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var30))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "\" class=\"text-gray-600 hover:text-gray-800 px-4 py-2 border border-gray-300 rounded-md\">← Back</button> <button type=\"submit\" class=\"bg-blue-600 text-white px-6 py-2 rounded-md hover:bg-blue-700\">Next: Assign Quantities →</button></div></form></div><script>\n\t\t(function() {\n\t\t\tvar picker = document.getElementById('ship-to-picker');\n\t\t\tvar allAddresses = JSON.parse(picker.dataset.addresses || '[]');\n\t\t\tvar searchInput = document.getElementById('ship-to-search');\n\t\t\tvar dropdown = document.getElementById('ship-to-dropdown');\n\t\t\tvar tagsContainer = document.getElementById('ship-to-tags');\n\t\t\tvar hiddenContainer = document.getElementById('ship-to-hidden-inputs');\n\t\t\tvar countEl = document.getElementById('ship-to-count');\n\t\t\tvar selected = [];\n\n\t\t\tfunction escapeHtml(str) {\n\t\t\t\tvar div = document.createElement('div');\n\t\t\t\tdiv.textContent = str;\n\t\t\t\treturn div.innerHTML;\n\t\t\t}\n\n\t\t\tfunction render() {\n\t\t\t\tcountEl.textContent = selected.length;\n\t\t\t\ttagsContainer.querySelectorAll('.ship-tag').forEach(function(el) { el.remove(); });\n\t\t\t\tselected.forEach(function(addr) {\n\t\t\t\t\tvar tag = document.createElement('span');\n\t\t\t\t\ttag.className = 'ship-tag inline-flex items-center gap-1 px-2 py-0.5 rounded-md text-xs font-medium bg-blue-100 text-blue-800';\n\t\t\t\t\ttag.innerHTML = '<span>' + escapeHtml(addr.name) + '</span>' +\n\t\t\t\t\t\t'<button type=\"button\" class=\"ml-1 hover:text-blue-600 font-bold\" data-id=\"' + addr.id + '\">&times;</button>';\n\t\t\t\t\ttag.querySelector('button').addEventListener('click', function(e) {\n\t\t\t\t\t\te.stopPropagation();\n\t\t\t\t\t\tremoveAddress(addr.id);\n\t\t\t\t\t});\n\t\t\t\t\ttagsContainer.insertBefore(tag, searchInput);\n\t\t\t\t});\n\n\t\t\t\thiddenContainer.innerHTML = '';\n\t\t\t\tselected.forEach(function(addr) {\n\t\t\t\t\tvar input = document.createElement('input');\n\t\t\t\t\tinput.type = 'hidden';\n\t\t\t\t\tinput.name = 'ship_to_address_ids';\n\t\t\t\t\tinput.value = addr.id;\n\t\t\t\t\thiddenContainer.appendChild(input);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tfunction addAddress(addr) {\n\t\t\t\tif (selected.some(function(s) { return s.id === addr.id; })) return;\n\t\t\t\tselected.push(addr);\n\t\t\t\tsearchInput.value = '';\n\t\t\t\tdropdown.classList.add('hidden');\n\t\t\t\trender();\n\t\t\t\tsearchInput.focus();\n\t\t\t}\n\n\t\t\tfunction removeAddress(id) {\n\t\t\t\tselected = selected.filter(function(s) { return s.id !== id; });\n\t\t\t\trender();\n\t\t\t}\n\n\t\t\tfunction showDropdown(query) {\n\t\t\t\tvar selectedIds = selected.map(function(s) { return s.id; });\n\t\t\t\tvar filtered = allAddresses.filter(function(a) {\n\t\t\t\t\tif (selectedIds.indexOf(a.id) !== -1) return false;\n\t\t\t\t\tif (!query) return true;\n\t\t\t\t\treturn a.name.toLowerCase().indexOf(query.toLowerCase()) !== -1;\n\t\t\t\t});\n\t\t\t\tif (filtered.length === 0) {\n\t\t\t\t\tdropdown.innerHTML = '<div class=\"px-3 py-2 text-sm text-gray-500\">No matches found</div>';\n\t\t\t\t} else {\n\t\t\t\t\tdropdown.innerHTML = '';\n\t\t\t\t\tfiltered.slice(0, 20).forEach(function(addr) {\n\t\t\t\t\t\tvar item = document.createElement('div');\n\t\t\t\t\t\titem.className = 'px-3 py-2 text-sm cursor-pointer hover:bg-blue-50 transition-colors';\n\t\t\t\t\t\titem.textContent = addr.name;\n\t\t\t\t\t\titem.addEventListener('mousedown', function(e) {\n\t\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\t\taddAddress(addr);\n\t\t\t\t\t\t});\n\t\t\t\t\t\tdropdown.appendChild(item);\n\t\t\t\t\t});\n\t\t\t\t\tif (filtered.length > 20) {\n\t\t\t\t\t\tvar more = document.createElement('div');\n\t\t\t\t\t\tmore.className = 'px-3 py-1.5 text-xs text-gray-400 border-t';\n\t\t\t\t\t\tmore.textContent = '+ ' + (filtered.length - 20) + ' more \\u2014 refine your search';\n\t\t\t\t\t\tdropdown.appendChild(more);\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\tdropdown.classList.remove('hidden');\n\t\t\t}\n\n\t\t\tsearchInput.addEventListener('focus', function() { showDropdown(this.value); });\n\t\t\tsearchInput.addEventListener('input', function() { showDropdown(this.value); });\n\t\t\tsearchInput.addEventListener('blur', function() {\n\t\t\t\tsetTimeout(function() { dropdown.classList.add('hidden'); }, 150);\n\t\t\t});\n\t\t\tsearchInput.addEventListener('keydown', function(e) {\n\t\t\t\tif (e.key === 'Backspace' && this.value === '' && selected.length > 0) {\n\t\t\t\t\tremoveAddress(selected[selected.length - 1].id);\n\t\t\t\t}\n\t\t\t});\n\n\t\t\trender();\n\t\t\tvar preselected = JSON.parse(picker.dataset.preselected || '[]') || [];\n\t\t\tpreselected.forEach(function(id) {\n\t\t\t\tvar addr = allAddresses.find(function(a) { return a.id === id; });\n\t\t\t\tif (addr) addAddress(addr);\n\t\t\t});\n\t\t})();\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "\" class=\"text-gray-600 hover:text-gray-800 px-4 py-2 border border-gray-300 rounded-md\">← Back</button> <button type=\"submit\" class=\"bg-blue-600 text-white px-6 py-2 rounded-md hover:bg-blue-700\">Next: Assign Quantities →</button></div></form></div><script>\n\t\t(function() {\n\t\t\tvar picker = document.getElementById('ship-to-picker');\n\t\t\tvar allAddresses = JSON.parse(picker.dataset.addresses || '[]');\n\t\t\tvar searchInput = document.getElementById('ship-to-search');\n\t\t\tvar dropdown = document.getElementById('ship-to-dropdown');\n\t\t\tvar tagsContainer = document.getElementById('ship-to-tags');\n\t\t\tvar hiddenContainer = document.getElementById('ship-to-hidden-inputs');\n\t\t\tvar countEl = document.getElementById('ship-to-count');\n\t\t\tvar selected = [];\n\n\t\t\tfunction escapeHtml(str) {\n\t\t\t\tvar div = document.createElement('div');\n\t\t\t\tdiv.textContent = str;\n\t\t\t\treturn div.innerHTML;\n\t\t\t}\n\n\t\t\tfunction render() {\n\t\t\t\tcountEl.textContent = selected.length;\n\t\t\t\ttagsContainer.querySelectorAll('.ship-tag').forEach(function(el) { el.remove(); });\n\t\t\t\tselected.forEach(function(addr) {\n\t\t\t\t\tvar tag = document.createElement('span');\n\t\t\t\t\ttag.className = 'ship-tag inline-flex items-center gap-1 px-2 py-0.5 rounded-md text-xs font-medium bg-blue-100 text-blue-800';\n\t\t\t\t\ttag.innerHTML = '<span>' + escapeHtml(addr.name) + '</span>' +\n\t\t\t\t\t\t'<button type=\"button\" class=\"ml-1 hover:text-blue-600 font-bold\" data-id=\"' + addr.id + '\">&times;</button>';\n\t\t\t\t\ttag.querySelector('button').addEventListener('click', function(e) {\n\t\t\t\t\t\te.stopPropagation();\n\t\t\t\t\t\tremoveAddress(addr.id);\n\t\t\t\t\t});\n\t\t\t\t\ttagsContainer.insertBefore(tag, searchInput);\n\t\t\t\t});\n\n\t\t\t\thiddenContainer.innerHTML = '';\n\t\t\t\tselected.forEach(function(addr) {\n\t\t\t\t\tvar input = document.createElement('input');\n\t\t\t\t\tinput.type = 'hidden';\n\t\t\t\t\tinput.name = 'ship_to_address_ids';\n\t\t\t\t\tinput.value = addr.id;\n\t\t\t\t\thiddenContainer.appendChild(input);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tfunction addAddress(addr) {\n\t\t\t\tif (selected.some(function(s) { return s.id === addr.id; })) return;\n\t\t\t\tselected.push(addr);\n\t\t\t\tsearchInput.value = '';\n\t\t\t\tdropdown.classList.add('hidden');\n\t\t\t\trender();\n\t\t\t\tsearchInput.focus();\n\t\t\t}\n\n\t\t\tfunction removeAddress(id) {\n\t\t\t\tselected = selected.filter(function(s) { return s.id !== id; });\n\t\t\t\trender();\n\t\t\t}\n\n\t\t\tfunction showDropdown(query) {\n\t\t\t\tvar selectedIds = selected.map(function(s) { return s.id; });\n\t\t\t\tvar filtered = allAddresses.filter(function(a) {\n\t\t\t\t\tif (selectedIds.indexOf(a.id) !== -1) return false;\n\t\t\t\t\tif (!query) return true;\n\t\t\t\t\treturn a.name.toLowerCase().indexOf(query.toLowerCase()) !== -1;\n\t\t\t\t});\n\t\t\t\tif (filtered.length === 0) {\n\t\t\t\t\tdropdown.innerHTML = '<div class=\"px-3 py-2 text-sm text-gray-500\">No matches found</div>';\n\t\t\t\t} else {\n\t\t\t\t\tdropdown.innerHTML = '';\n\t\t\t\t\tfiltered.slice(0, 20).forEach(function(addr) {\n\t\t\t\t\t\tvar item = document.createElement('div');\n\t\t\t\t\t\titem.className = 'px-3 py-2 text-sm cursor-pointer hover:bg-blue-50 transition-colors';\n\t\t\t\t\t\titem.textContent = addr.name;\n\t\t\t\t\t\titem.addEventListener('mousedown', function(e) {\n\t\t\t\t\t\t\te.preventDefault();\n\t\t\t\t\t\t\taddAddress(addr);\n\t\t\t\t\t\t});\n\t\t\t\t\t\tdropdown.appendChild(item);\n\t\t\t\t\t});\n\t\t\t\t\tif (filtered.length > 20) {\n\t\t\t\t\t\tvar more = document.createElement('div');\n\t\t\t\t\t\tmore.className = 'px-3 py-1.5 text-xs text-gray-400 border-t';\n\t\t\t\t\t\tmore.textContent = '+ ' + (filtered.length - 20) + ' more \\u2014 refine your search';\n\t\t\t\t\t\tdropdown.appendChild(more);\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\tdropdown.classList.remove('hidden');\n\t\t\t}\n\n\t\t\tsearchInput.addEventListener('focus', function() { showDropdown(this.value); });\n\t\t\tsearchInput.addEventListener('input', function() { showDropdown(this.value); });\n\t\t\tsearchInput.addEventListener('blur', function() {\n\t\t\t\tsetTimeout(function() { dropdown.classList.add('hidden'); }, 150);\n\t\t\t});\n\t\t\tsearchInput.addEventListener('keydown', function(e) {\n\t\t\t\tif (e.key === 'Backspace' && this.value === '' && selected.length > 0) {\n\t\t\t\t\tremoveAddress(selected[selected.length - 1].id);\n\t\t\t\t}\n\t\t\t});\n\n\t\t\trender();\n\t\t\tvar preselected = JSON.parse(picker.dataset.preselected || '[]') || [];\n\t\t\tpreselected.forEach(function(id) {\n\t\t\t\tvar addr = allAddresses.find(function(a) { return a.id === id; });\n\t\t\t\tif (addr) addAddress(addr);\n\t\t\t});\n\t\t})();\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -73,7 +73,7 @@ SET status     = 'issued',
     issued_by  = ?,
     updated_at = ?
 WHERE id     = ?
-  AND status = 'draft';
+  AND status IN ('draft', 'pending_approval');
 
 -- name: DeleteSerialNumbersByDCID :exec
 -- Step 1 of DeleteDC transaction: remove serial numbers for all line items of a DC.
@@ -110,7 +110,7 @@ WHERE id = ?;
 -- name: CountIssuedDCsForProject :one
 SELECT COUNT(*)
 FROM delivery_challans
-WHERE project_id = ? AND status = 'issued';
+WHERE project_id = ? AND status IN ('issued', 'delivered', 'partially_delivered');
 
 -- name: DeleteProject :exec
 DELETE FROM projects
@@ -123,11 +123,11 @@ SET status     = ?,
 WHERE id = ?;
 
 -- name: IssueAllDCsInGroup :execresult
--- Issues every draft DC in a shipment group atomically.
+-- Issues every draft or approval-pending DC in a shipment group atomically.
 UPDATE delivery_challans
 SET status     = 'issued',
     issued_at  = ?,
     issued_by  = ?,
     updated_at = ?
 WHERE shipment_group_id = ?
-  AND status            = 'draft';
+  AND status            IN ('draft', 'pending_approval');
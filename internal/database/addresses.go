@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	db "github.com/narendhupati/dc-management-tool/internal/database/sqlc"
+	"github.com/narendhupati/dc-management-tool/internal/helpers"
 	"github.com/narendhupati/dc-management-tool/internal/models"
 )
 
@@ -380,30 +381,50 @@ func GetAddress(addressID int) (*models.Address, error) {
 	return a, nil
 }
 
-// UpdateAddress updates a single address's data.
-func UpdateAddress(addressID int, data map[string]string, districtName, mandalName, mandalCode, addressCode string) error {
+// UpdateAddress updates a single address's data, provided it is still at the
+// edit version the form was opened with (ErrStaleVersion otherwise).
+func UpdateAddress(addressID, version int, data map[string]string, districtName, mandalName, mandalCode, addressCode string) error {
 	dataJSON, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := claimEditVersion(tx, models.AuditEntityAddress, addressID, version); err != nil {
+		return err
+	}
 	ctx := context.Background()
-	return addressQueries().UpdateAddress(ctx, db.UpdateAddressParams{
+	if err := db.New(tx).UpdateAddress(ctx, db.UpdateAddressParams{
 		AddressData:  string(dataJSON),
 		DistrictName: districtName,
 		MandalName:   mandalName,
 		MandalCode:   mandalCode,
 		AddressCode:  sql.NullString{String: addressCode, Valid: addressCode != ""},
 		ID:           int64(addressID),
-	})
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // ValidateAddressData validates address data against column definitions.
+// Columns holding a GSTIN must carry a valid one when filled in.
 func ValidateAddressData(data map[string]string, columns []models.ColumnDefinition) []string {
 	var errs []string
 	for _, col := range columns {
 		val := strings.TrimSpace(data[col.Name])
 		if col.Required && val == "" {
 			errs = append(errs, fmt.Sprintf("%s is required", col.Name))
+			continue
+		}
+		if val != "" && models.IsGSTINColumn(col.Name) {
+			if err := helpers.ValidateGSTIN(strings.ToUpper(val)); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %s", col.Name, err.Error()))
+			}
 		}
 	}
 	return errs
@@ -504,7 +525,7 @@ func IsAddressUsedInIssuedDC(addressID int) (bool, error) {
 	var count int
 	err := DB.QueryRow(
 		`SELECT COUNT(*) FROM delivery_challans
-		 WHERE status = 'issued'
+		 WHERE status IN `+issuedStatusesSQL+`
 		   AND (bill_to_address_id = ? OR ship_to_address_id = ?)`,
 		addressID, addressID,
 	).Scan(&count)
@@ -0,0 +1,169 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// AuditFilters holds the filter and pagination parameters for the audit log.
+type AuditFilters struct {
+	ProjectID  int // 0 = all projects (global audit page)
+	UserID     int // 0 = all users
+	EntityType string
+	EntityID   int
+	Action     string
+	DateFrom   string // YYYY-MM-DD, inclusive
+	DateTo     string // YYYY-MM-DD, inclusive
+	Page       int
+	PageSize   int // 0 = no limit (exports)
+}
+
+// AuditListResult holds one page of audit events.
+type AuditListResult struct {
+	Events     []*models.AuditEvent
+	TotalCount int
+	Page       int
+	PageSize   int
+	TotalPages int
+}
+
+// InsertAuditEvent stores an audit event.
+func InsertAuditEvent(e *models.AuditEvent) error {
+	changes := e.Changes
+	if changes == nil {
+		changes = []models.AuditChange{}
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("InsertAuditEvent marshal changes: %w", err)
+	}
+	res, err := DB.ExecContext(ctx(),
+		`INSERT INTO audit_events (project_id, user_id, entity_type, entity_id, action, summary, changes_json)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		nullInt64FromPtr(e.ProjectID), nullInt64FromPtr(e.UserID),
+		e.EntityType, e.EntityID, e.Action, e.Summary, string(changesJSON),
+	)
+	if err != nil {
+		return fmt.Errorf("InsertAuditEvent: %w", err)
+	}
+	id, _ := res.LastInsertId()
+	e.ID = int(id)
+	return nil
+}
+
+// ListAuditEvents returns audit events matching the filters, newest first.
+// Hand-written SQL: the WHERE clause is built dynamically from the filters.
+func ListAuditEvents(f AuditFilters) (*AuditListResult, error) {
+	var where []string
+	var args []interface{}
+
+	if f.ProjectID > 0 {
+		where = append(where, "ae.project_id = ?")
+		args = append(args, f.ProjectID)
+	}
+	if f.UserID > 0 {
+		where = append(where, "ae.user_id = ?")
+		args = append(args, f.UserID)
+	}
+	if f.EntityType != "" && f.EntityType != "all" {
+		where = append(where, "ae.entity_type = ?")
+		args = append(args, f.EntityType)
+	}
+	if f.EntityID > 0 {
+		where = append(where, "ae.entity_id = ?")
+		args = append(args, f.EntityID)
+	}
+	if f.Action != "" && f.Action != "all" {
+		where = append(where, "ae.action = ?")
+		args = append(args, f.Action)
+	}
+	if f.DateFrom != "" {
+		where = append(where, "DATE(ae.created_at) >= ?")
+		args = append(args, f.DateFrom)
+	}
+	if f.DateTo != "" {
+		where = append(where, "DATE(ae.created_at) <= ?")
+		args = append(args, f.DateTo)
+	}
+
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	var totalCount int
+	if err := DB.QueryRowContext(ctx(),
+		fmt.Sprintf(`SELECT COUNT(*) FROM audit_events ae %s`, whereClause), args...,
+	).Scan(&totalCount); err != nil {
+		return nil, fmt.Errorf("ListAuditEvents count: %w", err)
+	}
+
+	if f.Page <= 0 {
+		f.Page = 1
+	}
+	limitClause := ""
+	queryArgs := append([]interface{}{}, args...)
+	if f.PageSize > 0 {
+		limitClause = "LIMIT ? OFFSET ?"
+		queryArgs = append(queryArgs, f.PageSize, (f.Page-1)*f.PageSize)
+	}
+
+	rows, err := DB.QueryContext(ctx(), fmt.Sprintf(`
+		SELECT ae.id, ae.project_id, ae.user_id, ae.entity_type, ae.entity_id, ae.action,
+		       ae.summary, ae.changes_json, ae.created_at,
+		       COALESCE(NULLIF(u.full_name, ''), u.username, ''), COALESCE(p.name, '')
+		  FROM audit_events ae
+		  LEFT JOIN users u ON u.id = ae.user_id
+		  LEFT JOIN projects p ON p.id = ae.project_id
+		  %s
+		 ORDER BY ae.created_at DESC, ae.id DESC
+		 %s`, whereClause, limitClause), queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("ListAuditEvents: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.AuditEvent
+	for rows.Next() {
+		e := &models.AuditEvent{}
+		var projectID, userID sql.NullInt64
+		var changesJSON string
+		var createdAt sql.NullTime
+		if err := rows.Scan(&e.ID, &projectID, &userID, &e.EntityType, &e.EntityID, &e.Action,
+			&e.Summary, &changesJSON, &createdAt, &e.UserName, &e.ProjectName); err != nil {
+			return nil, fmt.Errorf("ListAuditEvents scan: %w", err)
+		}
+		if projectID.Valid {
+			v := int(projectID.Int64)
+			e.ProjectID = &v
+		}
+		if userID.Valid {
+			v := int(userID.Int64)
+			e.UserID = &v
+		}
+		if createdAt.Valid {
+			e.CreatedAt = createdAt.Time
+		}
+		_ = json.Unmarshal([]byte(changesJSON), &e.Changes)
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	totalPages := 1
+	if f.PageSize > 0 && totalCount > 0 {
+		totalPages = (totalCount + f.PageSize - 1) / f.PageSize
+	}
+	return &AuditListResult{
+		Events:     events,
+		TotalCount: totalCount,
+		Page:       f.Page,
+		PageSize:   f.PageSize,
+		TotalPages: totalPages,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+func setupAuditTestDB(t *testing.T) func() {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:audit_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	stmts := []string{
+		`CREATE TABLE projects (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL)`,
+		`CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, username TEXT NOT NULL, full_name TEXT DEFAULT '')`,
+		`CREATE TABLE audit_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project_id INTEGER REFERENCES projects(id) ON DELETE SET NULL,
+			user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+			entity_type TEXT NOT NULL,
+			entity_id INTEGER NOT NULL DEFAULT 0,
+			action TEXT NOT NULL,
+			summary TEXT NOT NULL DEFAULT '',
+			changes_json TEXT NOT NULL DEFAULT '[]',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`INSERT INTO projects (id, name) VALUES (1, 'Alpha'), (2, 'Beta')`,
+		`INSERT INTO users (id, username, full_name) VALUES (1, 'admin', 'Asha Rao'), (2, 'ops', '')`,
+	}
+	for _, s := range stmts {
+		if _, err := db.Exec(s); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+
+	DB = db
+	return func() { db.Close() }
+}
+
+func TestInsertAndListAuditEvents(t *testing.T) {
+	cleanup := setupAuditTestDB(t)
+	defer cleanup()
+
+	p1, p2, u1, u2 := 1, 2, 1, 2
+	events := []*models.AuditEvent{
+		{ProjectID: &p1, UserID: &u1, EntityType: models.AuditEntityProduct, EntityID: 10, Action: models.AuditActionCreate, Summary: "Created product Router",
+			Changes: models.DiffAudit(nil, map[string]interface{}{"item_name": "Router"})},
+		{ProjectID: &p1, UserID: &u2, EntityType: models.AuditEntityProduct, EntityID: 10, Action: models.AuditActionUpdate,
+			Changes: models.DiffAudit(map[string]interface{}{"uom": "Nos"}, map[string]interface{}{"uom": "Set"})},
+		{ProjectID: &p2, UserID: &u1, EntityType: models.AuditEntityDC, EntityID: 5, Action: models.AuditActionCancel},
+		{UserID: &u1, EntityType: models.AuditEntityUser, EntityID: 2, Action: models.AuditActionUpdate},
+	}
+	for _, e := range events {
+		if err := InsertAuditEvent(e); err != nil {
+			t.Fatalf("InsertAuditEvent: %v", err)
+		}
+		if e.ID == 0 {
+			t.Fatal("expected ID to be set")
+		}
+	}
+
+	// Project filter, newest first, joined names.
+	res, err := ListAuditEvents(AuditFilters{ProjectID: 1})
+	if err != nil {
+		t.Fatalf("ListAuditEvents: %v", err)
+	}
+	if res.TotalCount != 2 || len(res.Events) != 2 {
+		t.Fatalf("project 1: want 2 events, got %d", res.TotalCount)
+	}
+	if res.Events[0].Action != models.AuditActionUpdate || res.Events[0].UserName != "ops" {
+		t.Errorf("unexpected newest event: %+v", res.Events[0])
+	}
+	if res.Events[1].UserName != "Asha Rao" || res.Events[1].ProjectName != "Alpha" {
+		t.Errorf("unexpected joined names: %+v", res.Events[1])
+	}
+	if len(res.Events[0].Changes) != 1 || res.Events[0].Changes[0].Before != "Nos" || res.Events[0].Changes[0].After != "Set" {
+		t.Errorf("unexpected changes: %+v", res.Events[0].Changes)
+	}
+
+	// Global: all events, paginated.
+	res, err = ListAuditEvents(AuditFilters{Page: 2, PageSize: 3})
+	if err != nil {
+		t.Fatalf("ListAuditEvents: %v", err)
+	}
+	if res.TotalCount != 4 || res.TotalPages != 2 || len(res.Events) != 1 {
+		t.Errorf("pagination: total=%d pages=%d events=%d", res.TotalCount, res.TotalPages, len(res.Events))
+	}
+	if res.Events[0].ID != events[0].ID {
+		t.Errorf("last page should hold the oldest event, got %+v", res.Events[0])
+	}
+
+	// Entity, action and user filters.
+	res, _ = ListAuditEvents(AuditFilters{EntityType: models.AuditEntityProduct, EntityID: 10, Action: models.AuditActionCreate})
+	if res.TotalCount != 1 || res.Events[0].Summary != "Created product Router" {
+		t.Errorf("entity filter: got %d events", res.TotalCount)
+	}
+	res, _ = ListAuditEvents(AuditFilters{UserID: 1})
+	if res.TotalCount != 3 {
+		t.Errorf("user filter: want 3, got %d", res.TotalCount)
+	}
+}
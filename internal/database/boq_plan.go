@@ -0,0 +1,230 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// boqKey identifies a cell of the BOQ plan: a ship-to address and a product.
+type boqKey struct {
+	addressID int
+	productID int
+}
+
+// GetBOQPlan returns a project's planned quantities per ship-to address and product.
+// Hand-written SQL: boq_plan_items is not part of the sqlc queries.
+func GetBOQPlan(projectID int) ([]models.BOQPlanItem, error) {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT ship_to_address_id, product_id, planned_quantity
+		 FROM boq_plan_items WHERE project_id = ?
+		 ORDER BY ship_to_address_id, product_id`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("GetBOQPlan: %w", err)
+	}
+	defer rows.Close()
+	var items []models.BOQPlanItem
+	for rows.Next() {
+		var it models.BOQPlanItem
+		if err := rows.Scan(&it.ShipToAddressID, &it.ProductID, &it.PlannedQuantity); err != nil {
+			return nil, fmt.Errorf("GetBOQPlan: %w", err)
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// ReplaceBOQPlan replaces a project's whole BOQ plan; no items clears it.
+func ReplaceBOQPlan(projectID int, items []models.BOQPlanItem) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ReplaceBOQPlan: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx(), `DELETE FROM boq_plan_items WHERE project_id = ?`, projectID); err != nil {
+		return fmt.Errorf("ReplaceBOQPlan: %w", err)
+	}
+	for _, it := range items {
+		if _, err := tx.ExecContext(ctx(),
+			`INSERT INTO boq_plan_items (project_id, ship_to_address_id, product_id, planned_quantity)
+			 VALUES (?, ?, ?, ?)`,
+			projectID, it.ShipToAddressID, it.ProductID, it.PlannedQuantity); err != nil {
+			return fmt.Errorf("ReplaceBOQPlan: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// boqQuantities returns the units per ship-to address and product that went
+// out on issued official DCs, and those pending on draft official DCs and on
+// Transfer DC destinations not yet split into a shipment. Cancelled DCs do not
+// count. The official DCs of excludeGroupID and the destinations of
+// excludeTransferDCID are left out, so that a DC being edited is not counted
+// against itself.
+func boqQuantities(projectID, excludeGroupID, excludeTransferDCID int) (dispatched, pending map[boqKey]int, err error) {
+	dispatched = make(map[boqKey]int)
+	pending = make(map[boqKey]int)
+
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT dc.ship_to_address_id, li.product_id,
+		        COALESCE(SUM(CASE WHEN dc.status IN `+issuedStatusesSQL+` THEN li.quantity END), 0),
+		        COALESCE(SUM(CASE WHEN dc.status NOT IN `+issuedStatusesSQL+` THEN li.quantity END), 0)
+		 FROM dc_line_items li
+		 INNER JOIN delivery_challans dc ON dc.id = li.dc_id
+		 WHERE dc.project_id = ? AND dc.dc_type = 'official' AND dc.status != 'cancelled'
+		   AND dc.ship_to_address_id IS NOT NULL AND COALESCE(dc.shipment_group_id, 0) != ?
+		 GROUP BY dc.ship_to_address_id, li.product_id`,
+		projectID, excludeGroupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for rows.Next() {
+		var k boqKey
+		var issued, draft int
+		if err := rows.Scan(&k.addressID, &k.productID, &issued, &draft); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		dispatched[k] += issued
+		pending[k] += draft
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	rows, err = DB.QueryContext(ctx(),
+		`SELECT d.ship_to_address_id, q.product_id, COALESCE(SUM(q.quantity), 0)
+		 FROM transfer_dc_destination_quantities q
+		 INNER JOIN transfer_dc_destinations d ON d.id = q.destination_id
+		 INNER JOIN transfer_dcs t ON t.id = d.transfer_dc_id
+		 INNER JOIN delivery_challans dc ON dc.id = t.dc_id
+		 WHERE dc.project_id = ? AND dc.status != 'cancelled' AND d.is_split = 0 AND t.id != ?
+		 GROUP BY d.ship_to_address_id, q.product_id`,
+		projectID, excludeTransferDCID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var k boqKey
+		var qty int
+		if err := rows.Scan(&k.addressID, &k.productID, &qty); err != nil {
+			return nil, nil, err
+		}
+		pending[k] += qty
+	}
+	return dispatched, pending, rows.Err()
+}
+
+// GetBOQRemaining returns, per ship-to address and product, the planned units
+// not yet dispatched nor pending on another DC. Only cells with a planned
+// quantity are included; the map is empty when the project has no plan.
+func GetBOQRemaining(projectID, excludeGroupID, excludeTransferDCID int) (map[int]map[int]int, error) {
+	plan, err := GetBOQPlan(projectID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := make(map[int]map[int]int)
+	if len(plan) == 0 {
+		return remaining, nil
+	}
+	dispatched, pending, err := boqQuantities(projectID, excludeGroupID, excludeTransferDCID)
+	if err != nil {
+		return nil, fmt.Errorf("GetBOQRemaining: %w", err)
+	}
+	for _, it := range plan {
+		k := boqKey{it.ShipToAddressID, it.ProductID}
+		if remaining[it.ShipToAddressID] == nil {
+			remaining[it.ShipToAddressID] = make(map[int]int)
+		}
+		remaining[it.ShipToAddressID][it.ProductID] = it.PlannedQuantity - dispatched[k] - pending[k]
+	}
+	return remaining, nil
+}
+
+// GetBOQProgress returns the plan of each ship-to address and product against
+// the units dispatched and pending to it. Addresses that received units of a
+// product without planning any are included, so that over-delivery shows up;
+// nothing is returned when the project has no plan.
+// Hand-written SQL: boq_plan_items is not part of the sqlc queries.
+func GetBOQProgress(projectID int) ([]models.BOQProgress, error) {
+	plan, err := GetBOQPlan(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan) == 0 {
+		return nil, nil
+	}
+	dispatched, pending, err := boqQuantities(projectID, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("GetBOQProgress: %w", err)
+	}
+
+	planned := make(map[boqKey]int, len(plan))
+	var keys []boqKey
+	for _, it := range plan {
+		k := boqKey{it.ShipToAddressID, it.ProductID}
+		planned[k] = it.PlannedQuantity
+		keys = append(keys, k)
+	}
+	inPlan := len(keys)
+	for _, m := range []map[boqKey]int{dispatched, pending} {
+		for k, qty := range m {
+			if _, ok := planned[k]; !ok && qty > 0 {
+				planned[k] = 0
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	names, err := boqProductNames(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("GetBOQProgress: %w", err)
+	}
+	addresses := make(map[int]*models.Address)
+	out := make([]models.BOQProgress, 0, len(keys))
+	for i, k := range keys {
+		a, ok := addresses[k.addressID]
+		if !ok {
+			if a, err = GetAddress(k.addressID); err != nil {
+				a = &models.Address{ID: k.addressID}
+			}
+			addresses[k.addressID] = a
+		}
+		p := models.BOQProgress{
+			AddressID:  k.addressID,
+			District:   a.DistrictName,
+			Mandal:     a.MandalName,
+			ShipTo:     a.DisplayName(),
+			ProductID:  k.productID,
+			ItemName:   names[k.productID],
+			Planned:    planned[k],
+			Dispatched: dispatched[k],
+			Pending:    pending[k],
+			HasPlan:    i < inPlan,
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// boqProductNames returns the item names of a project's products by ID.
+func boqProductNames(projectID int) (map[int]string, error) {
+	rows, err := DB.QueryContext(ctx(), `SELECT id, item_name FROM products WHERE project_id = ?`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	names := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		names[id] = name
+	}
+	return names, rows.Err()
+}
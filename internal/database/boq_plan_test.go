@@ -0,0 +1,177 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+func setupBOQTestDB(t *testing.T) {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:boq_plan_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		`CREATE TABLE products (id INTEGER PRIMARY KEY, project_id INTEGER NOT NULL, item_name TEXT NOT NULL)`,
+		`CREATE TABLE addresses (
+			id INTEGER PRIMARY KEY, config_id INTEGER NOT NULL, address_data TEXT NOT NULL DEFAULT '{}',
+			district_name TEXT NOT NULL DEFAULT '', mandal_name TEXT NOT NULL DEFAULT '', mandal_code TEXT NOT NULL DEFAULT '',
+			address_code TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE boq_plan_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, project_id INTEGER NOT NULL, ship_to_address_id INTEGER NOT NULL,
+			product_id INTEGER NOT NULL, planned_quantity INTEGER NOT NULL, created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(project_id, ship_to_address_id, product_id))`,
+		`CREATE TABLE delivery_challans (
+			id INTEGER PRIMARY KEY, project_id INTEGER NOT NULL, dc_type TEXT NOT NULL, status TEXT NOT NULL,
+			shipment_group_id INTEGER, ship_to_address_id INTEGER)`,
+		`CREATE TABLE dc_line_items (id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL, product_id INTEGER NOT NULL, quantity INTEGER NOT NULL)`,
+		`CREATE TABLE transfer_dcs (id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL)`,
+		`CREATE TABLE transfer_dc_destinations (id INTEGER PRIMARY KEY, transfer_dc_id INTEGER NOT NULL, ship_to_address_id INTEGER NOT NULL, is_split INTEGER NOT NULL DEFAULT 0)`,
+		`CREATE TABLE transfer_dc_destination_quantities (id INTEGER PRIMARY KEY, destination_id INTEGER NOT NULL, product_id INTEGER NOT NULL, quantity INTEGER NOT NULL)`,
+		`INSERT INTO products (id, project_id, item_name) VALUES (10, 1, 'Router'), (11, 1, 'Switch')`,
+		`INSERT INTO addresses (id, config_id, district_name, mandal_name) VALUES (7, 1, 'Guntur', 'Tenali'), (8, 1, 'Guntur', 'Bapatla')`,
+		// Shipment 5 is issued, shipment 6 is a draft and shipment 9 was cancelled.
+		`INSERT INTO delivery_challans (id, project_id, dc_type, status, shipment_group_id, ship_to_address_id) VALUES
+			(100, 1, 'transit', 'issued', 5, NULL),
+			(101, 1, 'official', 'issued', 5, 7),
+			(102, 1, 'official', 'delivered', 5, 8),
+			(201, 1, 'official', 'draft', 6, 7),
+			(301, 1, 'official', 'cancelled', 9, 7),
+			(400, 1, 'transfer', 'issued', NULL, NULL)`,
+		`INSERT INTO dc_line_items (id, dc_id, product_id, quantity) VALUES
+			(1, 100, 10, 30), (2, 101, 10, 10), (3, 102, 10, 20), (4, 102, 11, 2), (5, 201, 10, 5), (6, 301, 10, 50)`,
+		// Transfer DC 40 has an unsplit destination 7 and a split destination 8.
+		`INSERT INTO transfer_dcs (id, dc_id) VALUES (40, 400)`,
+		`INSERT INTO transfer_dc_destinations (id, transfer_dc_id, ship_to_address_id, is_split) VALUES (1, 40, 7, 0), (2, 40, 8, 1)`,
+		`INSERT INTO transfer_dc_destination_quantities (destination_id, product_id, quantity) VALUES (1, 10, 3), (2, 10, 20)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	DB = db
+}
+
+func TestReplaceBOQPlan(t *testing.T) {
+	setupBOQTestDB(t)
+
+	if err := ReplaceBOQPlan(1, []models.BOQPlanItem{{ShipToAddressID: 7, ProductID: 10, PlannedQuantity: 25}}); err != nil {
+		t.Fatalf("ReplaceBOQPlan: %v", err)
+	}
+	if err := ReplaceBOQPlan(1, []models.BOQPlanItem{{ShipToAddressID: 8, ProductID: 10, PlannedQuantity: 20}}); err != nil {
+		t.Fatalf("ReplaceBOQPlan: %v", err)
+	}
+	plan, err := GetBOQPlan(1)
+	if err != nil {
+		t.Fatalf("GetBOQPlan: %v", err)
+	}
+	if len(plan) != 1 || plan[0] != (models.BOQPlanItem{ShipToAddressID: 8, ProductID: 10, PlannedQuantity: 20}) {
+		t.Errorf("plan = %+v, want only the second import", plan)
+	}
+
+	if err := ReplaceBOQPlan(1, nil); err != nil {
+		t.Fatalf("ReplaceBOQPlan(nil): %v", err)
+	}
+	if plan, _ := GetBOQPlan(1); len(plan) != 0 {
+		t.Errorf("plan = %+v after clearing, want none", plan)
+	}
+}
+
+func TestGetBOQRemaining(t *testing.T) {
+	setupBOQTestDB(t)
+
+	remaining, err := GetBOQRemaining(1, 0, 0)
+	if err != nil {
+		t.Fatalf("GetBOQRemaining: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("remaining = %v without a plan, want empty", remaining)
+	}
+
+	if err := ReplaceBOQPlan(1, []models.BOQPlanItem{
+		{ShipToAddressID: 7, ProductID: 10, PlannedQuantity: 25},
+		{ShipToAddressID: 8, ProductID: 10, PlannedQuantity: 15},
+	}); err != nil {
+		t.Fatalf("ReplaceBOQPlan: %v", err)
+	}
+	tests := []struct {
+		name                  string
+		excludeGroup, exclude int
+		addr7, addr8          int
+	}{
+		// 25 - 10 issued - 5 draft - 3 on the unsplit transfer destination.
+		{"all DCs", 0, 0, 7, -5},
+		{"editing the draft shipment", 6, 0, 12, -5},
+		{"editing the transfer DC", 0, 40, 10, -5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining, err := GetBOQRemaining(1, tt.excludeGroup, tt.exclude)
+			if err != nil {
+				t.Fatalf("GetBOQRemaining: %v", err)
+			}
+			if got := remaining[7][10]; got != tt.addr7 {
+				t.Errorf("address 7 = %d, want %d", got, tt.addr7)
+			}
+			if got := remaining[8][10]; got != tt.addr8 {
+				t.Errorf("address 8 = %d, want %d", got, tt.addr8)
+			}
+			if _, ok := remaining[8][11]; ok {
+				t.Error("unplanned product 11 at address 8 should be absent")
+			}
+		})
+	}
+}
+
+func TestGetBOQProgress(t *testing.T) {
+	setupBOQTestDB(t)
+
+	rows, err := GetBOQProgress(1)
+	if err != nil {
+		t.Fatalf("GetBOQProgress: %v", err)
+	}
+	if rows != nil {
+		t.Errorf("rows = %+v without a plan, want nil", rows)
+	}
+
+	if err := ReplaceBOQPlan(1, []models.BOQPlanItem{
+		{ShipToAddressID: 7, ProductID: 10, PlannedQuantity: 25},
+		{ShipToAddressID: 8, ProductID: 10, PlannedQuantity: 15},
+	}); err != nil {
+		t.Fatalf("ReplaceBOQPlan: %v", err)
+	}
+	rows, err = GetBOQProgress(1)
+	if err != nil {
+		t.Fatalf("GetBOQProgress: %v", err)
+	}
+	type cell struct {
+		addr, product                int
+		planned, dispatched, pending int
+		hasPlan                      bool
+	}
+	got := make(map[[2]int]cell)
+	for _, r := range rows {
+		got[[2]int{r.AddressID, r.ProductID}] = cell{r.AddressID, r.ProductID, r.Planned, r.Dispatched, r.Pending, r.HasPlan}
+		if r.District != "Guntur" {
+			t.Errorf("address %d district = %q, want Guntur", r.AddressID, r.District)
+		}
+	}
+	want := []cell{
+		{7, 10, 25, 10, 8, true},
+		{8, 10, 15, 20, 0, true},
+		{8, 11, 0, 2, 0, false}, // dispatched without a plan
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(got), len(want), rows)
+	}
+	for _, w := range want {
+		if g := got[[2]int{w.addr, w.product}]; g != w {
+			t.Errorf("address %d product %d = %+v, want %+v", w.addr, w.product, g, w)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// GetBoxLabelDestinations returns the official DCs of a shipment group with
+// their products and the serials of the group's transit DC that were assigned
+// or scanned for each destination. Product names and ship-to addresses come
+// from each DC's issue-time snapshot, like the DC print, when it has one.
+// Hand-written SQL: joins official DC line items with transit DC serials by destination.
+func GetBoxLabelDestinations(groupID int) ([]*models.BoxLabelDestination, error) {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT dc.id, dc.dc_number, dc.ship_to_address_id, li.product_id, p.item_name, li.quantity
+		 FROM delivery_challans dc
+		 INNER JOIN dc_line_items li ON li.dc_id = dc.id
+		 INNER JOIN products p ON p.id = li.product_id
+		 WHERE dc.shipment_group_id = ? AND dc.dc_type = 'official' AND li.quantity > 0
+		 ORDER BY dc.id, li.line_order, li.id`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("GetBoxLabelDestinations: %w", err)
+	}
+	var dests []*models.BoxLabelDestination
+	var dcIDs []int
+	byDC := make(map[int]*models.BoxLabelDestination)
+	for rows.Next() {
+		var dcID int
+		var dcNumber string
+		line := &models.BoxLabelLine{}
+		var addressID int
+		if err := rows.Scan(&dcID, &dcNumber, &addressID, &line.ProductID, &line.ItemName, &line.Quantity); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("GetBoxLabelDestinations: %w", err)
+		}
+		d := byDC[dcID]
+		if d == nil {
+			d = &models.BoxLabelDestination{DCNumber: dcNumber, AddressID: addressID}
+			byDC[dcID] = d
+			dests = append(dests, d)
+			dcIDs = append(dcIDs, dcID)
+		}
+		d.Lines = append(d.Lines, line)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetBoxLabelDestinations: %w", err)
+	}
+
+	rows, err = DB.QueryContext(ctx(),
+		`SELECT sn.ship_to_address_id, sn.product_id, sn.serial_number
+		 FROM serial_numbers sn
+		 INNER JOIN dc_line_items li ON li.id = sn.line_item_id
+		 INNER JOIN delivery_challans dc ON dc.id = li.dc_id
+		 WHERE dc.shipment_group_id = ? AND dc.dc_type = 'transit'
+		   AND sn.ship_to_address_id IS NOT NULL AND sn.released_at IS NULL
+		 ORDER BY sn.id`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("GetBoxLabelDestinations: %w", err)
+	}
+	serials := make(map[[2]int][]string) // {address, product} -> serials
+	for rows.Next() {
+		var addressID, productID int
+		var sn string
+		if err := rows.Scan(&addressID, &productID, &sn); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("GetBoxLabelDestinations: %w", err)
+		}
+		key := [2]int{addressID, productID}
+		serials[key] = append(serials[key], sn)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetBoxLabelDestinations: %w", err)
+	}
+
+	for i, d := range dests {
+		snap, err := GetDCSnapshot(dcIDs[i])
+		if err != nil {
+			return nil, fmt.Errorf("GetBoxLabelDestinations: %w", err)
+		}
+		for _, line := range d.Lines {
+			line.Serials = serials[[2]int{d.AddressID, line.ProductID}]
+			if snap != nil {
+				if p, ok := snap.Products[line.ProductID]; ok {
+					line.ItemName = p.ItemName
+				}
+			}
+		}
+		var shipTo *models.Address
+		if snap != nil {
+			shipTo = snap.Address(models.SnapshotRoleShipTo)
+		}
+		if shipTo == nil {
+			// DCs issued before snapshots were recorded use the live address.
+			shipTo, _ = GetAddress(d.AddressID)
+		}
+		if shipTo != nil {
+			d.ShipTo = shipTo.DisplayName()
+		} else {
+			d.ShipTo = fmt.Sprintf("Address #%d", d.AddressID)
+		}
+	}
+	return dests, nil
+}
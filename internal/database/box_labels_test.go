@@ -0,0 +1,48 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetBoxLabelDestinations(t *testing.T) {
+	setupSerialCaptureTestDB(t)
+
+	for _, stmt := range []string{
+		`CREATE TABLE dc_snapshots (dc_id INTEGER PRIMARY KEY, snapshot_json TEXT NOT NULL, captured_at DATETIME)`,
+		// TST-ODC-001 was issued before the router was renamed and its school moved.
+		`INSERT INTO dc_snapshots (dc_id, snapshot_json) VALUES (101,
+			'{"addresses":{"ship_to":{"id":7,"data":{"School Name":"ZPHS Tenali"}}},"products":{"10":{"item_name":"Router v1"}}}')`,
+		`INSERT INTO serial_numbers (project_id, line_item_id, product_id, serial_number, ship_to_address_id) VALUES
+			(1, 1000, 10, 'RT1', 8), (1, 1000, 10, 'RT2', 7), (1, 1000, 10, 'RT3', 7)`,
+		`INSERT INTO serial_numbers (project_id, line_item_id, product_id, serial_number) VALUES (1, 1001, 11, 'SW1')`,
+	} {
+		if _, err := DB.Exec(stmt); err != nil {
+			t.Fatalf("fixture: %v", err)
+		}
+	}
+
+	dests, err := GetBoxLabelDestinations(5)
+	if err != nil {
+		t.Fatalf("GetBoxLabelDestinations: %v", err)
+	}
+	if len(dests) != 2 || dests[0].DCNumber != "TST-ODC-001" || dests[0].AddressID != 7 {
+		t.Fatalf("destinations = %+v, want TST-ODC-001 to address 7 then TST-ODC-002", dests)
+	}
+	if len(dests[0].Lines) != 2 || dests[0].Lines[0].ItemName != "Router v1" || dests[0].Lines[0].Quantity != 2 {
+		t.Errorf("address 7 lines = %+v, want 2 routers (as issued) and 2 switches", dests[0].Lines)
+	}
+	if dests[0].ShipTo != "ZPHS Tenali" || dests[1].Lines[0].ItemName != "Router" {
+		t.Errorf("ship-to = %q, address 8 product = %q; want the snapshot for the issued DC only",
+			dests[0].ShipTo, dests[1].Lines[0].ItemName)
+	}
+	if got := dests[0].Lines[0].Serials; !reflect.DeepEqual(got, []string{"RT2", "RT3"}) {
+		t.Errorf("address 7 router serials = %v, want [RT2 RT3]", got)
+	}
+	if got := dests[0].Lines[1].Serials; got != nil {
+		t.Errorf("address 7 switch serials = %v, want none: SW1 has no destination", got)
+	}
+	if got := dests[1].Lines[0].Serials; !reflect.DeepEqual(got, []string{"RT1"}) {
+		t.Errorf("address 8 router serials = %v, want [RT1]", got)
+	}
+}
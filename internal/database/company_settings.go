@@ -3,8 +3,11 @@ package database
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 
 	db "github.com/narendhupati/dc-management-tool/internal/database/sqlc"
+	"github.com/narendhupati/dc-management-tool/internal/helpers"
 	"github.com/narendhupati/dc-management-tool/internal/models"
 )
 
@@ -20,6 +23,9 @@ func GetCompanySettings() (*models.CompanySettings, error) {
 
 // UpdateCompanySettings persists all editable fields for the single company settings row.
 func UpdateCompanySettings(cs *models.CompanySettings) error {
+	if err := ValidateCompanySettings(cs); err != nil {
+		return err
+	}
 	q := db.New(DB)
 	return q.UpdateCompanySettings(context.Background(), db.UpdateCompanySettingsParams{
 		Name:           cs.Name,
@@ -35,6 +41,29 @@ func UpdateCompanySettings(cs *models.CompanySettings) error {
 	})
 }
 
+// ValidateCompanySettings checks the company GSTIN and that the state code, which
+// decides intra- versus inter-state tax, agrees with the GSTIN's state prefix.
+// The GSTIN is upper-cased in place.
+func ValidateCompanySettings(cs *models.CompanySettings) error {
+	cs.GSTIN = strings.ToUpper(strings.TrimSpace(cs.GSTIN))
+	if cs.GSTIN != "" {
+		if err := helpers.ValidateGSTIN(cs.GSTIN); err != nil {
+			return err
+		}
+	}
+	if cs.StateCode == "" {
+		return nil
+	}
+	code := models.GSTStateCode(cs.StateCode)
+	if code == 0 {
+		return fmt.Errorf("state code %q is not a GST state code", cs.StateCode)
+	}
+	if cs.GSTIN != "" && models.GSTINStateCode(cs.GSTIN) != code {
+		return fmt.Errorf("state code %02d does not match the GSTIN, which is registered in state %s", code, cs.GSTIN[:2])
+	}
+	return nil
+}
+
 // UpdateCompanySignature updates only the signature image path.
 func UpdateCompanySignature(signatureImage string) error {
 	q := db.New(DB)
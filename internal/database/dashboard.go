@@ -24,7 +24,12 @@ type DashboardStats struct {
 	TransitDCsDraft   int
 	TransitDCsIssued  int
 	OfficialDCsDraft  int
-	OfficialDCsIssued int
+	OfficialDCsIssued int // includes delivered
+
+	// Official DC delivery (proof of delivery) breakdown
+	OfficialDCsDelivered          int
+	OfficialDCsPartiallyDelivered int
+	OfficialDCsPendingDelivery    int // issued, no POD yet
 
 	// Transfer DC breakdown
 	TransferDCs         int
@@ -102,7 +107,7 @@ func GetDashboardStats(projectID int, startDate, endDate *time.Time) (*Dashboard
 	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ?"+dateFilter, buildArgs()...).Scan(&stats.TotalDCs)
 	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='transit'"+dateFilter, buildArgs()...).Scan(&stats.TransitDCs)
 	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='official'"+dateFilter, buildArgs()...).Scan(&stats.OfficialDCs)
-	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND status='issued'"+dateFilter, buildArgs()...).Scan(&stats.IssuedDCs)
+	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND status IN "+issuedStatusesSQL+dateFilter, buildArgs()...).Scan(&stats.IssuedDCs)
 	// DraftDCs is intentionally not date-filtered (matches original behavior).
 	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND status='draft'", projectID).Scan(&stats.DraftDCs)
 
@@ -110,7 +115,12 @@ func GetDashboardStats(projectID int, startDate, endDate *time.Time) (*Dashboard
 	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='transit' AND status='draft'"+dateFilter, buildArgs()...).Scan(&stats.TransitDCsDraft)
 	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='transit' AND status='issued'"+dateFilter, buildArgs()...).Scan(&stats.TransitDCsIssued)
 	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='official' AND status='draft'"+dateFilter, buildArgs()...).Scan(&stats.OfficialDCsDraft)
-	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='official' AND status='issued'"+dateFilter, buildArgs()...).Scan(&stats.OfficialDCsIssued)
+	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='official' AND status IN "+issuedStatusesSQL+dateFilter, buildArgs()...).Scan(&stats.OfficialDCsIssued)
+
+	// Official delivery breakdown
+	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='official' AND status='delivered'"+dateFilter, buildArgs()...).Scan(&stats.OfficialDCsDelivered)
+	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='official' AND status='partially_delivered'"+dateFilter, buildArgs()...).Scan(&stats.OfficialDCsPartiallyDelivered)
+	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='official' AND status='issued'"+dateFilter, buildArgs()...).Scan(&stats.OfficialDCsPendingDelivery)
 
 	// Transfer DC breakdown
 	_ = DB.QueryRow("SELECT COUNT(*) FROM delivery_challans WHERE project_id = ? AND dc_type='transfer'"+dateFilter, buildArgs()...).Scan(&stats.TransferDCs)
@@ -0,0 +1,288 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// GetApprovalSettings returns whether a project requires approval before issue
+// and which users hold the approver right on it.
+// Hand-written SQL: the approval queries are not part of the sqlc queries.
+func GetApprovalSettings(projectID int) (*models.ApprovalSettings, error) {
+	s := &models.ApprovalSettings{ProjectID: projectID}
+	if err := DB.QueryRowContext(ctx(),
+		`SELECT require_approval FROM projects WHERE id = ?`, projectID,
+	).Scan(&s.RequireApproval); err != nil {
+		return nil, fmt.Errorf("GetApprovalSettings: %w", err)
+	}
+
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT user_id FROM project_approvers WHERE project_id = ? ORDER BY user_id`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("GetApprovalSettings approvers: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("GetApprovalSettings scan: %w", err)
+		}
+		s.ApproverIDs = append(s.ApproverIDs, id)
+	}
+	return s, rows.Err()
+}
+
+// SaveApprovalSettings replaces a project's approval flag and approver list.
+func SaveApprovalSettings(s *models.ApprovalSettings) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("SaveApprovalSettings: begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx(),
+		`UPDATE projects SET require_approval = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		s.RequireApproval, s.ProjectID,
+	); err != nil {
+		return fmt.Errorf("SaveApprovalSettings: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx(), `DELETE FROM project_approvers WHERE project_id = ?`, s.ProjectID); err != nil {
+		return fmt.Errorf("SaveApprovalSettings: clear approvers: %w", err)
+	}
+	for _, userID := range s.ApproverIDs {
+		if _, err := tx.ExecContext(ctx(),
+			`INSERT OR IGNORE INTO project_approvers (project_id, user_id) VALUES (?, ?)`,
+			s.ProjectID, userID,
+		); err != nil {
+			return fmt.Errorf("SaveApprovalSettings: add approver %d: %w", userID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// approvalTargetColumns returns the dc_approvals values identifying the target.
+func approvalTargetColumns(t models.ApprovalTarget) (groupID, dcID interface{}) {
+	if t.ShipmentGroupID > 0 {
+		return t.ShipmentGroupID, nil
+	}
+	return nil, t.DCID
+}
+
+// insertApprovalStep appends a step to the target's approval history.
+func insertApprovalStep(exec execer, t models.ApprovalTarget, action string, userID int, comments string) error {
+	groupID, dcID := approvalTargetColumns(t)
+	_, err := exec.ExecContext(ctx(),
+		`INSERT INTO dc_approvals (project_id, shipment_group_id, dc_id, action, comments, user_id)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		t.ProjectID, groupID, dcID, action, comments, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("record %s step: %w", action, err)
+	}
+	return nil
+}
+
+// transitionApproval moves the target's DCs (and the shipment group, if any) from
+// one of the from statuses to the to status and records the step, atomically.
+func transitionApproval(t models.ApprovalTarget, from []string, to, action string, userID int, comments string) error {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(from)), ", ")
+	args := make([]interface{}, 0, len(from)+3)
+	args = append(args, to)
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	where := "id = ?"
+	if t.ShipmentGroupID > 0 {
+		where = "shipment_group_id = ?"
+		args = append(args, t.ShipmentGroupID)
+	} else {
+		args = append(args, t.DCID)
+	}
+	args = append(args, t.ProjectID)
+	for _, s := range from {
+		args = append(args, s)
+	}
+	res, err := tx.ExecContext(ctx(),
+		`UPDATE delivery_challans SET status = ?, updated_at = CURRENT_TIMESTAMP
+		  WHERE `+where+` AND project_id = ? AND status IN (`+placeholders+`)`,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("update DC status: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("nothing to %s: the DCs are not in a state that allows it", action)
+	}
+
+	if t.ShipmentGroupID > 0 {
+		groupArgs := []interface{}{to, t.ShipmentGroupID}
+		for _, s := range from {
+			groupArgs = append(groupArgs, s)
+		}
+		if _, err := tx.ExecContext(ctx(),
+			`UPDATE shipment_groups SET status = ?, updated_at = CURRENT_TIMESTAMP
+			  WHERE id = ? AND status IN (`+placeholders+`)`,
+			groupArgs...,
+		); err != nil {
+			return fmt.Errorf("update shipment group status: %w", err)
+		}
+	}
+
+	if err := insertApprovalStep(tx, t, action, userID, comments); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SubmitForApproval moves a draft or rejected shipment group or DC to pending approval.
+func SubmitForApproval(t models.ApprovalTarget, userID int, comments string) error {
+	if err := transitionApproval(t, []string{models.DCStatusDraft, models.DCStatusRejected},
+		models.DCStatusPendingApproval, models.ApprovalActionSubmit, userID, comments); err != nil {
+		return fmt.Errorf("SubmitForApproval: %w", err)
+	}
+	return nil
+}
+
+// RejectApproval sends a pending shipment group or DC back as rejected with the
+// approver's comments.
+func RejectApproval(t models.ApprovalTarget, userID int, comments string) error {
+	if strings.TrimSpace(comments) == "" {
+		return fmt.Errorf("rejection comments are required")
+	}
+	if err := transitionApproval(t, []string{models.DCStatusPendingApproval},
+		models.DCStatusRejected, models.ApprovalActionReject, userID, comments); err != nil {
+		return fmt.Errorf("RejectApproval: %w", err)
+	}
+	return nil
+}
+
+// ReopenRejected moves a rejected shipment group or DC back to draft for editing.
+func ReopenRejected(t models.ApprovalTarget, userID int) error {
+	if err := transitionApproval(t, []string{models.DCStatusRejected},
+		models.DCStatusDraft, models.ApprovalActionReopen, userID, ""); err != nil {
+		return fmt.Errorf("ReopenRejected: %w", err)
+	}
+	return nil
+}
+
+// RecordApprovalStep appends a step to the approval history without changing any
+// status; issuing an approved request records its "approve" step this way.
+func RecordApprovalStep(t models.ApprovalTarget, action string, userID int, comments string) error {
+	if err := insertApprovalStep(DB, t, action, userID, comments); err != nil {
+		return fmt.Errorf("RecordApprovalStep: %w", err)
+	}
+	return nil
+}
+
+// GetApprovalHistory lists the approval steps of a shipment group or DC, oldest first.
+func GetApprovalHistory(t models.ApprovalTarget) ([]models.DCApproval, error) {
+	column, id := "dc_id", t.DCID
+	if t.ShipmentGroupID > 0 {
+		column, id = "shipment_group_id", t.ShipmentGroupID
+	}
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT a.id, a.project_id, a.shipment_group_id, a.dc_id, a.action, a.comments, a.user_id, a.created_at,
+		        COALESCE(NULLIF(u.full_name, ''), u.username, '')
+		   FROM dc_approvals a
+		   LEFT JOIN users u ON u.id = a.user_id
+		  WHERE a.`+column+` = ?
+		  ORDER BY a.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("GetApprovalHistory: %w", err)
+	}
+	defer rows.Close()
+
+	var history []models.DCApproval
+	for rows.Next() {
+		var a models.DCApproval
+		var groupID, dcID, userID sql.NullInt64
+		var createdAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.ProjectID, &groupID, &dcID, &a.Action, &a.Comments, &userID, &createdAt, &a.UserName); err != nil {
+			return nil, fmt.Errorf("GetApprovalHistory scan: %w", err)
+		}
+		a.ShipmentGroupID = intPtrFromNullInt64(groupID)
+		a.DCID = intPtrFromNullInt64(dcID)
+		a.UserID = intPtrFromNullInt64(userID)
+		if createdAt.Valid {
+			a.CreatedAt = createdAt.Time
+		}
+		history = append(history, a)
+	}
+	return history, rows.Err()
+}
+
+// GetPendingSubmitter returns the user who submitted the target's pending request,
+// or 0 when no submission is recorded.
+func GetPendingSubmitter(t models.ApprovalTarget) (int, error) {
+	history, err := GetApprovalHistory(t)
+	if err != nil {
+		return 0, err
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Action == models.ApprovalActionSubmit {
+			if history[i].UserID != nil {
+				return *history[i].UserID, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, nil
+}
+
+// GetApprovalQueue lists the shipment groups and single DCs awaiting approval in
+// every project where the user holds the approver right, oldest submission first.
+func GetApprovalQueue(userID int) ([]*models.ApprovalQueueItem, error) {
+	rows, err := DB.QueryContext(ctx(), `
+		SELECT sg.project_id, p.name, sg.id, 0, '',
+		       COUNT(dc.id), COALESCE(GROUP_CONCAT(dc.dc_number, ', '), ''),
+		       COALESCE(a.user_id, 0), COALESCE(NULLIF(u.full_name, ''), u.username, ''),
+		       a.created_at, COALESCE(a.comments, '')
+		  FROM shipment_groups sg
+		 INNER JOIN project_approvers pa ON pa.project_id = sg.project_id AND pa.user_id = ?
+		 INNER JOIN projects p ON p.id = sg.project_id
+		 INNER JOIN delivery_challans dc ON dc.shipment_group_id = sg.id AND dc.status = 'pending_approval'
+		  LEFT JOIN dc_approvals a ON a.id = (
+		        SELECT MAX(id) FROM dc_approvals WHERE shipment_group_id = sg.id AND action = 'submit')
+		  LEFT JOIN users u ON u.id = a.user_id
+		 WHERE sg.status = 'pending_approval'
+		 GROUP BY sg.id
+		UNION ALL
+		SELECT dc.project_id, p.name, 0, dc.id, dc.dc_type,
+		       1, dc.dc_number,
+		       COALESCE(a.user_id, 0), COALESCE(NULLIF(u.full_name, ''), u.username, ''),
+		       a.created_at, COALESCE(a.comments, '')
+		  FROM delivery_challans dc
+		 INNER JOIN project_approvers pa ON pa.project_id = dc.project_id AND pa.user_id = ?
+		 INNER JOIN projects p ON p.id = dc.project_id
+		  LEFT JOIN dc_approvals a ON a.id = (
+		        SELECT MAX(id) FROM dc_approvals WHERE dc_id = dc.id AND action = 'submit')
+		  LEFT JOIN users u ON u.id = a.user_id
+		 WHERE dc.status = 'pending_approval' AND dc.shipment_group_id IS NULL
+		 ORDER BY 10`, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("GetApprovalQueue: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*models.ApprovalQueueItem
+	for rows.Next() {
+		q := &models.ApprovalQueueItem{}
+		var submittedAt sql.NullTime
+		if err := rows.Scan(&q.ProjectID, &q.ProjectName, &q.ShipmentGroupID, &q.DCID, &q.DCType,
+			&q.DCCount, &q.DCNumbers, &q.SubmittedByID, &q.SubmittedBy, &submittedAt, &q.Comments); err != nil {
+			return nil, fmt.Errorf("GetApprovalQueue scan: %w", err)
+		}
+		if submittedAt.Valid {
+			q.SubmittedAt = submittedAt.Time
+		}
+		items = append(items, q)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,241 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+func setupApprovalTestDB(t *testing.T) func() {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:approval_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	stmts := []string{
+		`CREATE TABLE projects (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			require_approval INTEGER NOT NULL DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, username TEXT NOT NULL, full_name TEXT DEFAULT '')`,
+		`CREATE TABLE project_approvers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(project_id, user_id)
+		)`,
+		`CREATE TABLE shipment_groups (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project_id INTEGER NOT NULL,
+			status TEXT NOT NULL DEFAULT 'draft',
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE delivery_challans (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project_id INTEGER NOT NULL,
+			dc_number TEXT NOT NULL,
+			dc_type TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'draft',
+			shipment_group_id INTEGER,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE dc_approvals (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project_id INTEGER NOT NULL,
+			shipment_group_id INTEGER,
+			dc_id INTEGER,
+			action TEXT NOT NULL CHECK(action IN ('submit', 'approve', 'reject', 'reopen')),
+			comments TEXT NOT NULL DEFAULT '',
+			user_id INTEGER,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			CHECK ((shipment_group_id IS NULL) != (dc_id IS NULL))
+		)`,
+		`INSERT INTO projects (id, name) VALUES (1, 'Alpha'), (2, 'Beta')`,
+		`INSERT INTO users (id, username, full_name) VALUES (1, 'maker', 'Asha Rao'), (2, 'checker', 'Vikram Iyer'), (3, 'other', '')`,
+		`INSERT INTO shipment_groups (id, project_id) VALUES (10, 1)`,
+		`INSERT INTO delivery_challans (id, project_id, dc_number, dc_type, shipment_group_id) VALUES
+			(100, 1, 'TDC-001', 'transit', 10),
+			(101, 1, 'ODC-001', 'official', 10),
+			(102, 1, 'ODC-002', 'official', 10),
+			(200, 2, 'TRF-001', 'transfer', NULL)`,
+	}
+	for _, s := range stmts {
+		if _, err := db.Exec(s); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+
+	DB = db
+	return func() { db.Close() }
+}
+
+func dcStatus(t *testing.T, id int) string {
+	t.Helper()
+	var status string
+	if err := DB.QueryRow(`SELECT status FROM delivery_challans WHERE id = ?`, id).Scan(&status); err != nil {
+		t.Fatalf("read DC %d status: %v", id, err)
+	}
+	return status
+}
+
+func TestApprovalSettingsRoundTrip(t *testing.T) {
+	cleanup := setupApprovalTestDB(t)
+	defer cleanup()
+
+	if err := SaveApprovalSettings(&models.ApprovalSettings{ProjectID: 1, RequireApproval: true, ApproverIDs: []int{2, 3}}); err != nil {
+		t.Fatalf("SaveApprovalSettings: %v", err)
+	}
+	// Saving again replaces the approver list.
+	if err := SaveApprovalSettings(&models.ApprovalSettings{ProjectID: 1, RequireApproval: true, ApproverIDs: []int{2}}); err != nil {
+		t.Fatalf("SaveApprovalSettings: %v", err)
+	}
+
+	s, err := GetApprovalSettings(1)
+	if err != nil {
+		t.Fatalf("GetApprovalSettings: %v", err)
+	}
+	if !s.RequireApproval || len(s.ApproverIDs) != 1 || !s.IsApprover(2) || s.IsApprover(3) {
+		t.Errorf("settings = %+v, want require_approval with approver 2 only", s)
+	}
+
+	other, err := GetApprovalSettings(2)
+	if err != nil {
+		t.Fatalf("GetApprovalSettings: %v", err)
+	}
+	if other.RequireApproval || len(other.ApproverIDs) != 0 {
+		t.Errorf("project 2 settings = %+v, want defaults", other)
+	}
+}
+
+func TestShipmentGroupApprovalLifecycle(t *testing.T) {
+	cleanup := setupApprovalTestDB(t)
+	defer cleanup()
+
+	if err := SaveApprovalSettings(&models.ApprovalSettings{ProjectID: 1, RequireApproval: true, ApproverIDs: []int{2}}); err != nil {
+		t.Fatalf("SaveApprovalSettings: %v", err)
+	}
+	target := models.ApprovalTarget{ProjectID: 1, ShipmentGroupID: 10}
+
+	if err := SubmitForApproval(target, 1, "Ready for dispatch"); err != nil {
+		t.Fatalf("SubmitForApproval: %v", err)
+	}
+	for _, id := range []int{100, 101, 102} {
+		if got := dcStatus(t, id); got != models.DCStatusPendingApproval {
+			t.Errorf("DC %d status = %q, want pending_approval", id, got)
+		}
+	}
+	if submitter, _ := GetPendingSubmitter(target); submitter != 1 {
+		t.Errorf("GetPendingSubmitter = %d, want 1", submitter)
+	}
+
+	queue, err := GetApprovalQueue(2)
+	if err != nil {
+		t.Fatalf("GetApprovalQueue: %v", err)
+	}
+	if len(queue) != 1 {
+		t.Fatalf("queue has %d items, want 1", len(queue))
+	}
+	q := queue[0]
+	if q.ShipmentGroupID != 10 || q.DCCount != 3 || q.SubmittedByID != 1 || q.SubmittedBy != "Asha Rao" || q.Comments != "Ready for dispatch" {
+		t.Errorf("queue item = %+v", q)
+	}
+	if q.URL() != "/projects/1/shipments/10" {
+		t.Errorf("URL = %q", q.URL())
+	}
+	if queue, _ := GetApprovalQueue(3); len(queue) != 0 {
+		t.Errorf("non-approver queue has %d items, want 0", len(queue))
+	}
+
+	// Submitting twice is refused: the DCs are no longer draft.
+	if err := SubmitForApproval(target, 1, ""); err == nil {
+		t.Error("expected resubmitting a pending group to fail")
+	}
+
+	if err := RejectApproval(target, 2, "  "); err == nil {
+		t.Error("expected rejection without comments to fail")
+	}
+	if err := RejectApproval(target, 2, "Wrong ship-to for location 2"); err != nil {
+		t.Fatalf("RejectApproval: %v", err)
+	}
+	if got := dcStatus(t, 101); got != models.DCStatusRejected {
+		t.Errorf("DC status after reject = %q, want rejected", got)
+	}
+	if queue, _ := GetApprovalQueue(2); len(queue) != 0 {
+		t.Errorf("queue after reject has %d items, want 0", len(queue))
+	}
+
+	if err := ReopenRejected(target, 1); err != nil {
+		t.Fatalf("ReopenRejected: %v", err)
+	}
+	if got := dcStatus(t, 100); got != models.DCStatusDraft {
+		t.Errorf("DC status after reopen = %q, want draft", got)
+	}
+	var groupStatus string
+	if err := DB.QueryRow(`SELECT status FROM shipment_groups WHERE id = 10`).Scan(&groupStatus); err != nil {
+		t.Fatalf("read group status: %v", err)
+	}
+	if groupStatus != models.DCStatusDraft {
+		t.Errorf("group status = %q, want draft", groupStatus)
+	}
+
+	history, err := GetApprovalHistory(target)
+	if err != nil {
+		t.Fatalf("GetApprovalHistory: %v", err)
+	}
+	wantActions := []string{models.ApprovalActionSubmit, models.ApprovalActionReject, models.ApprovalActionReopen}
+	if len(history) != len(wantActions) {
+		t.Fatalf("history has %d steps, want %d", len(history), len(wantActions))
+	}
+	for i, want := range wantActions {
+		if history[i].Action != want {
+			t.Errorf("step %d action = %q, want %q", i, history[i].Action, want)
+		}
+	}
+	if history[1].UserName != "Vikram Iyer" || history[1].Comments != "Wrong ship-to for location 2" {
+		t.Errorf("reject step = %+v", history[1])
+	}
+}
+
+func TestSingleDCApprovalQueue(t *testing.T) {
+	cleanup := setupApprovalTestDB(t)
+	defer cleanup()
+
+	if err := SaveApprovalSettings(&models.ApprovalSettings{ProjectID: 2, RequireApproval: true, ApproverIDs: []int{2}}); err != nil {
+		t.Fatalf("SaveApprovalSettings: %v", err)
+	}
+	target := models.ApprovalTarget{ProjectID: 2, DCID: 200}
+
+	// A DC is only moved within its own project.
+	if err := SubmitForApproval(models.ApprovalTarget{ProjectID: 1, DCID: 200}, 3, ""); err == nil {
+		t.Error("expected submitting a DC under the wrong project to fail")
+	}
+	if err := SubmitForApproval(target, 3, ""); err != nil {
+		t.Fatalf("SubmitForApproval: %v", err)
+	}
+
+	queue, err := GetApprovalQueue(2)
+	if err != nil {
+		t.Fatalf("GetApprovalQueue: %v", err)
+	}
+	if len(queue) != 1 || queue[0].DCID != 200 || queue[0].DCNumbers != "TRF-001" || queue[0].ProjectName != "Beta" {
+		t.Fatalf("queue = %+v", queue)
+	}
+	if queue[0].SubmittedBy != "other" {
+		t.Errorf("SubmittedBy = %q, want username fallback", queue[0].SubmittedBy)
+	}
+
+	if err := RecordApprovalStep(target, models.ApprovalActionApprove, 2, "OK"); err != nil {
+		t.Fatalf("RecordApprovalStep: %v", err)
+	}
+	history, _ := GetApprovalHistory(target)
+	if len(history) != 2 || history[1].Action != models.ApprovalActionApprove {
+		t.Errorf("history = %+v", history)
+	}
+}
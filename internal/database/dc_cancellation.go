@@ -0,0 +1,163 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// cancelDCsInTx marks the given issued DCs as cancelled and releases their serial
+// numbers so they can be dispatched again. The DC rows (and numbers) are kept.
+// Returns the number of DCs actually cancelled.
+func cancelDCsInTx(tx *sql.Tx, dcIDs []int, userID int, reason string) (int, error) {
+	cancelled := 0
+	for _, dcID := range dcIDs {
+		res, err := tx.ExecContext(ctx(),
+			`UPDATE delivery_challans
+			    SET status = 'cancelled', cancelled_at = CURRENT_TIMESTAMP, cancelled_by = ?,
+			        cancellation_reason = ?, updated_at = CURRENT_TIMESTAMP
+			  WHERE id = ? AND status = 'issued'`,
+			userID, reason, dcID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("cancel DC %d: %w", dcID, err)
+		}
+		n, _ := res.RowsAffected()
+		if n == 0 {
+			continue
+		}
+		cancelled++
+
+		if _, err := tx.ExecContext(ctx(),
+			`UPDATE serial_numbers SET released_at = CURRENT_TIMESTAMP
+			  WHERE released_at IS NULL
+			    AND line_item_id IN (SELECT id FROM dc_line_items WHERE dc_id = ?)`,
+			dcID,
+		); err != nil {
+			return 0, fmt.Errorf("release serials for DC %d: %w", dcID, err)
+		}
+	}
+	return cancelled, nil
+}
+
+// CancelDC cancels a single issued DC, recording who cancelled it and why.
+// Its serial numbers are released back to the project; the DC number stays consumed.
+func CancelDC(dcID, userID int, reason string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	n, err := cancelDCsInTx(tx, []int{dcID}, userID, reason)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("DC not found or not issued")
+	}
+	return tx.Commit()
+}
+
+// ErrShipmentGroupDelivered is returned when a shipment group to cancel has a
+// DC with a proof of delivery.
+var ErrShipmentGroupDelivered = errors.New("shipment group has a delivered DC")
+
+// ErrShipmentGroupNotIssued is returned when a shipment group to cancel is no
+// longer issued.
+var ErrShipmentGroupNotIssued = errors.New("shipment group is not issued")
+
+// CancelShipmentGroup cancels every issued DC in a shipment group and marks the
+// group itself as cancelled. Returns the number of DCs cancelled. The checks run
+// in the transaction, so a proof of delivery recorded after the caller looked
+// refuses the cancellation (ErrShipmentGroupDelivered), as does a group that is
+// no longer issued (ErrShipmentGroupNotIssued).
+func CancelShipmentGroup(groupID, userID int, reason string) (int, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var delivered int
+	if err := tx.QueryRowContext(ctx(),
+		`SELECT COUNT(*) FROM delivery_challans
+		  WHERE shipment_group_id = ? AND status IN ('delivered', 'partially_delivered')`, groupID,
+	).Scan(&delivered); err != nil {
+		return 0, fmt.Errorf("CancelShipmentGroup check deliveries: %w", err)
+	}
+	if delivered > 0 {
+		return 0, ErrShipmentGroupDelivered
+	}
+
+	rows, err := tx.QueryContext(ctx(),
+		`SELECT id FROM delivery_challans WHERE shipment_group_id = ? AND status = 'issued'`, groupID)
+	if err != nil {
+		return 0, fmt.Errorf("CancelShipmentGroup list DCs: %w", err)
+	}
+	var dcIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		dcIDs = append(dcIDs, id)
+	}
+	rows.Close()
+	if len(dcIDs) == 0 {
+		return 0, fmt.Errorf("shipment group has no issued DCs to cancel")
+	}
+
+	n, err := cancelDCsInTx(tx, dcIDs, userID, reason)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.ExecContext(ctx(),
+		`UPDATE shipment_groups SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		  WHERE id = ? AND status = 'issued'`, groupID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("CancelShipmentGroup update group: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return 0, fmt.Errorf("CancelShipmentGroup update group: %w", err)
+	} else if n == 0 {
+		return 0, ErrShipmentGroupNotIssued
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// loadDCCancellation fills the cancellation fields of a cancelled DC.
+// Hand-written SQL: the cancellation columns are not part of the sqlc DC queries.
+func loadDCCancellation(dc *models.DeliveryChallan) error {
+	var cancelledAt sql.NullTime
+	var cancelledBy sql.NullInt64
+	var reason, byName sql.NullString
+	err := DB.QueryRowContext(ctx(),
+		`SELECT dc.cancelled_at, dc.cancelled_by, dc.cancellation_reason, COALESCE(NULLIF(u.full_name, ''), u.username)
+		   FROM delivery_challans dc
+		   LEFT JOIN users u ON u.id = dc.cancelled_by
+		  WHERE dc.id = ?`, dc.ID,
+	).Scan(&cancelledAt, &cancelledBy, &reason, &byName)
+	if err != nil {
+		return fmt.Errorf("loadDCCancellation: %w", err)
+	}
+	if cancelledAt.Valid {
+		dc.CancelledAt = &cancelledAt.Time
+	}
+	if cancelledBy.Valid {
+		v := int(cancelledBy.Int64)
+		dc.CancelledBy = &v
+	}
+	dc.CancellationReason = reason.String
+	dc.CancelledByName = byName.String
+	return nil
+}
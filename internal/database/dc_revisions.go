@@ -0,0 +1,214 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// loadDCRevision fills the current revision number of a DC.
+// Hand-written SQL: the revision column is not part of the sqlc DC queries.
+func loadDCRevision(dc *models.DeliveryChallan) error {
+	if err := DB.QueryRowContext(ctx(),
+		`SELECT revision FROM delivery_challans WHERE id = ?`, dc.ID,
+	).Scan(&dc.Revision); err != nil {
+		return fmt.Errorf("loadDCRevision: %w", err)
+	}
+	return nil
+}
+
+// addressDisplayName returns the display name of an address, or "" when unset or missing.
+func addressDisplayName(id *int) string {
+	if id == nil || *id == 0 {
+		return ""
+	}
+	addr, err := GetAddress(*id)
+	if err != nil || addr == nil {
+		return ""
+	}
+	return addr.DisplayName()
+}
+
+// GetDCRevisionSnapshot captures the current amendable state of a DC:
+// challan date, addresses, transit details and line items with serials.
+func GetDCRevisionSnapshot(dcID int) (*models.DCRevisionSnapshot, error) {
+	dc, err := GetDeliveryChallanByID(dcID)
+	if err != nil {
+		return nil, fmt.Errorf("GetDCRevisionSnapshot: %w", err)
+	}
+
+	shipTo := dc.ShipToAddressID
+	snap := &models.DCRevisionSnapshot{
+		BillFromAddressID:     derefIntPtr(dc.BillFromAddressID),
+		BillFromAddress:       addressDisplayName(dc.BillFromAddressID),
+		DispatchFromAddressID: derefIntPtr(dc.DispatchFromAddressID),
+		DispatchFromAddress:   addressDisplayName(dc.DispatchFromAddressID),
+		BillToAddressID:       derefIntPtr(dc.BillToAddressID),
+		BillToAddress:         addressDisplayName(dc.BillToAddressID),
+		ShipToAddressID:       shipTo,
+		ShipToAddress:         addressDisplayName(&shipTo),
+	}
+	if dc.ChallanDate != nil {
+		snap.ChallanDate = *dc.ChallanDate
+	}
+
+	td, err := GetTransitDetailsByDCID(dcID)
+	if err != nil {
+		return nil, fmt.Errorf("GetDCRevisionSnapshot transit details: %w", err)
+	}
+	if td != nil {
+		snap.TransporterName = td.TransporterName
+		snap.VehicleNumber = td.VehicleNumber
+		snap.EwayBillNumber = td.EwayBillNumber
+		snap.Notes = td.Notes
+	}
+
+	lineItems, err := GetLineItemsByDCID(dcID)
+	if err != nil {
+		return nil, fmt.Errorf("GetDCRevisionSnapshot line items: %w", err)
+	}
+	snap.LineItems = make([]models.DCRevisionLineItem, 0, len(lineItems))
+	for _, li := range lineItems {
+		serials, _ := GetSerialNumbersByLineItemID(li.ID)
+		snap.LineItems = append(snap.LineItems, models.DCRevisionLineItem{
+			ProductID:     li.ProductID,
+			ItemName:      li.ItemName,
+			Quantity:      li.Quantity,
+			SerialNumbers: serials,
+		})
+	}
+	return snap, nil
+}
+
+// derefIntPtr returns *p, or 0 for nil.
+func derefIntPtr(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+// AmendDC amends an issued DC: the current state is stored read-only in dc_revisions,
+// the amendment is applied and the DC's revision number is bumped. The DC number itself
+// is unchanged; it is printed with a "/R<n>" suffix. Returns the new revision number.
+//
+// dc must be freshly loaded; if another amendment landed since, nothing is written.
+func AmendDC(dc *models.DeliveryChallan, a *models.DCAmendment, userID int) (int, error) {
+	// Snapshot before opening the transaction: the pool has a single connection.
+	snap, err := GetDCRevisionSnapshot(dc.ID)
+	if err != nil {
+		return 0, err
+	}
+	snapJSON, err := json.Marshal(snap)
+	if err != nil {
+		return 0, fmt.Errorf("AmendDC marshal snapshot: %w", err)
+	}
+	frozen, err := amendedDCSnapshot(dc, a)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx(),
+		`UPDATE delivery_challans SET revision = revision + 1, updated_at = CURRENT_TIMESTAMP
+		  WHERE id = ? AND revision = ? AND status = 'issued'`,
+		dc.ID, dc.Revision,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("AmendDC bump revision: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return 0, fmt.Errorf("DC is no longer issued or was amended by someone else; reload and try again")
+	}
+
+	if _, err := tx.ExecContext(ctx(),
+		`INSERT INTO dc_revisions (dc_id, revision, dc_number, snapshot_json, amendment_reason, amended_by)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		dc.ID, dc.Revision, dc.DisplayNumber(), string(snapJSON), a.Reason, userID,
+	); err != nil {
+		return 0, fmt.Errorf("AmendDC insert revision: %w", err)
+	}
+
+	if dc.DCType == "transit" {
+		err = updateTransitDC(tx, dc.ID, a.ChallanDate, a.TransporterName, a.VehicleNumber, a.EwayBillNumber, a.Notes)
+	} else {
+		err = updateDeliveryChallanAddressesAndDate(tx, dc.ID, a.ChallanDate,
+			a.BillFromAddressID, a.DispatchFromAddressID, a.BillToAddressID, dc.ShipToAddressID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if frozen != nil {
+		if err := saveDCSnapshot(tx, dc.ID, frozen); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return dc.Revision + 1, nil
+}
+
+// GetDCRevisions returns the superseded revisions of a DC, oldest first.
+func GetDCRevisions(dcID int) ([]*models.DCRevision, error) {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT r.id, r.dc_id, r.revision, r.dc_number, r.snapshot_json, r.amendment_reason,
+		        r.amended_by, COALESCE(NULLIF(u.full_name, ''), u.username, ''), r.amended_at
+		   FROM dc_revisions r
+		   LEFT JOIN users u ON u.id = r.amended_by
+		  WHERE r.dc_id = ?
+		  ORDER BY r.revision`, dcID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetDCRevisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []*models.DCRevision
+	for rows.Next() {
+		r := &models.DCRevision{}
+		var snapJSON string
+		var amendedBy sql.NullInt64
+		var amendedAt sql.NullTime
+		if err := rows.Scan(&r.ID, &r.DCID, &r.Revision, &r.DCNumber, &snapJSON, &r.AmendmentReason,
+			&amendedBy, &r.AmendedByName, &amendedAt); err != nil {
+			return nil, fmt.Errorf("GetDCRevisions scan: %w", err)
+		}
+		if err := json.Unmarshal([]byte(snapJSON), &r.Snapshot); err != nil {
+			return nil, fmt.Errorf("GetDCRevisions decode revision %d: %w", r.Revision, err)
+		}
+		if amendedBy.Valid {
+			v := int(amendedBy.Int64)
+			r.AmendedBy = &v
+		}
+		if amendedAt.Valid {
+			r.AmendedAt = amendedAt.Time
+		}
+		revisions = append(revisions, r)
+	}
+	return revisions, rows.Err()
+}
+
+// GetDCRevisionHistory returns a DC's amendments, newest first, each with its field-level diff.
+func GetDCRevisionHistory(dc *models.DeliveryChallan) ([]models.DCRevisionHistoryEntry, error) {
+	if dc.Revision == 0 {
+		return nil, nil
+	}
+	revisions, err := GetDCRevisions(dc.ID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := GetDCRevisionSnapshot(dc.ID)
+	if err != nil {
+		return nil, err
+	}
+	return models.BuildDCRevisionHistory(dc.DCNumber, revisions, current), nil
+}
@@ -0,0 +1,227 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	db "github.com/narendhupati/dc-management-tool/internal/database/sqlc"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// snapshotAddress loads an address for a snapshot, or nil when unset or missing.
+func snapshotAddress(id int) *models.DCSnapshotAddress {
+	if id <= 0 {
+		return nil
+	}
+	addr, err := GetAddress(id)
+	if err != nil {
+		return nil
+	}
+	return models.NewDCSnapshotAddress(addr)
+}
+
+// resolveDCAddresses loads the addresses printed on a DC, keyed by snapshot role.
+// Official DCs without their own bill-from/dispatch-from fall back to the transit DC
+// of their shipment group; transfer DCs also carry their hub address.
+func resolveDCAddresses(dc *models.DeliveryChallan) map[string]*models.DCSnapshotAddress {
+	addrs := make(map[string]*models.DCSnapshotAddress)
+	set := func(role string, id int) {
+		if a := snapshotAddress(id); a != nil {
+			addrs[role] = a
+		}
+	}
+
+	set(models.SnapshotRoleShipTo, dc.ShipToAddressID)
+	set(models.SnapshotRoleBillTo, derefIntPtr(dc.BillToAddressID))
+	set(models.SnapshotRoleBillFrom, derefIntPtr(dc.BillFromAddressID))
+	set(models.SnapshotRoleDispatchFrom, derefIntPtr(dc.DispatchFromAddressID))
+
+	if dc.DCType == "official" && dc.ShipmentGroupID != nil && *dc.ShipmentGroupID > 0 {
+		groupDCs, _ := GetDCsByShipmentGroup(*dc.ShipmentGroupID)
+		for _, groupDC := range groupDCs {
+			if groupDC.DCType != "transit" {
+				continue
+			}
+			if addrs[models.SnapshotRoleBillFrom] == nil {
+				set(models.SnapshotRoleBillFrom, derefIntPtr(groupDC.BillFromAddressID))
+			}
+			if addrs[models.SnapshotRoleDispatchFrom] == nil {
+				set(models.SnapshotRoleDispatchFrom, derefIntPtr(groupDC.DispatchFromAddressID))
+			}
+			break
+		}
+	}
+
+	if dc.DCType == "transfer" {
+		if tdc, err := GetTransferDCByDCID(dc.ID); err == nil && tdc != nil {
+			set(models.SnapshotRoleHub, tdc.HubAddressID)
+		}
+	}
+	return addrs
+}
+
+// BuildDCSnapshot captures the live master data a DC is printed with.
+func BuildDCSnapshot(dc *models.DeliveryChallan) (*models.DCSnapshot, error) {
+	project, err := GetProjectByID(dc.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("BuildDCSnapshot project: %w", err)
+	}
+
+	snap := &models.DCSnapshot{
+		CapturedAt:    time.Now(),
+		Project:       project,
+		Addresses:     resolveDCAddresses(dc),
+		PrintColumns:  make(map[string][]models.ColumnDefinition),
+		Products:      make(map[int]models.DCSnapshotProduct),
+		KitComponents: make(map[int][]models.KitComponent),
+	}
+	if company, err := GetCompanySettings(); err == nil {
+		snap.Company = company
+	}
+
+	for _, addressType := range models.SnapshotAddressTypes {
+		cfg, err := GetOrCreateAddressConfig(dc.ProjectID, addressType)
+		if err != nil {
+			return nil, fmt.Errorf("BuildDCSnapshot %s config: %w", addressType, err)
+		}
+		snap.PrintColumns[addressType] = cfg.ColumnDefinitions
+	}
+
+	lineItems, err := GetLineItemsByDCID(dc.ID)
+	if err != nil {
+		return nil, fmt.Errorf("BuildDCSnapshot line items: %w", err)
+	}
+	for _, li := range lineItems { //nolint:gocritic
+		snap.Products[li.ProductID] = models.DCSnapshotProduct{
+			ItemName:        li.ItemName,
+			ItemDescription: li.ItemDescription,
+			HSNCode:         li.HSNCode,
+			UoM:             li.UoM,
+			BrandModel:      li.BrandModel,
+			GSTPercentage:   li.GSTPercentage,
+		}
+		if len(li.KitComponents) > 0 {
+			comps := make([]models.KitComponent, len(li.KitComponents))
+			copy(comps, li.KitComponents)
+			for i := range comps {
+				comps[i].SerialNumbers = nil
+			}
+			snap.KitComponents[li.ProductID] = comps
+		}
+	}
+
+	if dc.DCType == "transfer" {
+		if tdc, err := GetTransferDCByDCID(dc.ID); err == nil && tdc != nil {
+			dests, err := GetTransferDCDestinations(tdc.ID)
+			if err != nil {
+				return nil, fmt.Errorf("BuildDCSnapshot destinations: %w", err)
+			}
+			snap.Destinations = make(map[int]*models.DCSnapshotAddress, len(dests))
+			for _, d := range dests {
+				if a := snapshotAddress(d.ShipToAddressID); a != nil {
+					snap.Destinations[d.ShipToAddressID] = a
+				}
+			}
+		}
+	}
+	return snap, nil
+}
+
+// saveDCSnapshot stores (or replaces) the snapshot of a DC.
+// Hand-written SQL: dc_snapshots is not part of the sqlc queries.
+func saveDCSnapshot(q db.DBTX, dcID int, snap *models.DCSnapshot) error {
+	snapJSON, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("saveDCSnapshot marshal: %w", err)
+	}
+	if _, err := q.ExecContext(ctx(),
+		`INSERT INTO dc_snapshots (dc_id, snapshot_json, captured_at) VALUES (?, ?, ?)
+		 ON CONFLICT(dc_id) DO UPDATE SET snapshot_json = excluded.snapshot_json, captured_at = excluded.captured_at`,
+		dcID, string(snapJSON), snap.CapturedAt,
+	); err != nil {
+		return fmt.Errorf("saveDCSnapshot: %w", err)
+	}
+	return nil
+}
+
+// GetDCSnapshot returns the snapshot frozen when a DC was issued, or nil if it has none.
+func GetDCSnapshot(dcID int) (*models.DCSnapshot, error) {
+	var snapJSON string
+	err := DB.QueryRowContext(ctx(),
+		`SELECT snapshot_json FROM dc_snapshots WHERE dc_id = ?`, dcID,
+	).Scan(&snapJSON)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetDCSnapshot: %w", err)
+	}
+	snap := &models.DCSnapshot{}
+	if err := json.Unmarshal([]byte(snapJSON), snap); err != nil {
+		return nil, fmt.Errorf("GetDCSnapshot decode: %w", err)
+	}
+	return snap, nil
+}
+
+// GetDCPrintSnapshot returns the master data a DC is printed and exported with:
+// the snapshot frozen at issue, or live data for drafts and for DCs issued
+// before snapshots were recorded.
+func GetDCPrintSnapshot(dc *models.DeliveryChallan) (*models.DCSnapshot, error) {
+	if dc.Status != "draft" {
+		snap, err := GetDCSnapshot(dc.ID)
+		if err != nil {
+			return nil, err
+		}
+		if snap != nil {
+			return snap, nil
+		}
+	}
+	return BuildDCSnapshot(dc)
+}
+
+// amendedDCSnapshot returns the snapshot of a DC with the addresses changed by an
+// amendment re-captured; addresses the amendment kept stay frozen. DCs issued before
+// snapshots were recorded get a full snapshot. Returns nil when nothing changes.
+func amendedDCSnapshot(dc *models.DeliveryChallan, a *models.DCAmendment) (*models.DCSnapshot, error) {
+	if dc.DCType == "transit" {
+		return nil, nil
+	}
+	amended := *dc
+	amended.BillFromAddressID = intPtrOrNil(a.BillFromAddressID)
+	amended.DispatchFromAddressID = intPtrOrNil(a.DispatchFromAddressID)
+	amended.BillToAddressID = intPtrOrNil(a.BillToAddressID)
+
+	snap, err := GetDCSnapshot(dc.ID)
+	if err != nil {
+		return nil, err
+	}
+	if snap == nil {
+		return BuildDCSnapshot(&amended)
+	}
+
+	resolved := resolveDCAddresses(&amended)
+	for _, role := range []string{models.SnapshotRoleBillTo, models.SnapshotRoleBillFrom, models.SnapshotRoleDispatchFrom} {
+		current, next := snap.Addresses[role], resolved[role]
+		switch {
+		case next == nil:
+			delete(snap.Addresses, role)
+		case current == nil || current.ID != next.ID:
+			if snap.Addresses == nil {
+				snap.Addresses = make(map[string]*models.DCSnapshotAddress)
+			}
+			snap.Addresses[role] = next
+		}
+	}
+	snap.CapturedAt = time.Now()
+	return snap, nil
+}
+
+// intPtrOrNil returns &v, or nil for 0.
+func intPtrOrNil(v int) *int {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+func TestGetDCPrintSnapshot_FrozenAfterIssue(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	dcID := insertTestDC(t, 1, "ODC-SNAP-001", "official", 1)
+	DB.Exec(`UPDATE delivery_challans SET status = 'issued' WHERE id = ?`, dcID)
+
+	snap := &models.DCSnapshot{
+		Project: &models.Project{ID: 1, Name: "Test Project"},
+		Addresses: map[string]*models.DCSnapshotAddress{
+			models.SnapshotRoleShipTo: {ID: 1, Data: map[string]string{"name": "Old Site"}},
+		},
+		Products: map[int]models.DCSnapshotProduct{1: {ItemName: "Router v1", HSNCode: "8517"}},
+	}
+	if err := saveDCSnapshot(DB, dcID, snap); err != nil {
+		t.Fatalf("saveDCSnapshot: %v", err)
+	}
+
+	// Later edits to the masters must not reach the issued DC.
+	DB.Exec(`UPDATE addresses SET address_data = '{"name":"New Site"}' WHERE id = 1`)
+	DB.Exec(`UPDATE products SET item_name = 'Router v2' WHERE id = 1`)
+
+	dc := &models.DeliveryChallan{ID: dcID, ProjectID: 1, DCType: "official", Status: "issued", ShipToAddressID: 1}
+	got, err := GetDCPrintSnapshot(dc)
+	if err != nil {
+		t.Fatalf("GetDCPrintSnapshot: %v", err)
+	}
+	if addr := got.Address(models.SnapshotRoleShipTo); addr == nil || addr.Data["name"] != "Old Site" {
+		t.Errorf("ship-to should be frozen, got %+v", addr)
+	}
+
+	items := []models.DCLineItem{{ProductID: 1, ItemName: "Router v2"}}
+	got.ApplyProducts(items)
+	if items[0].ItemName != "Router v1" || items[0].HSNCode != "8517" {
+		t.Errorf("product fields should be frozen, got %+v", items[0])
+	}
+}
+
+func TestAmendedDCSnapshot_RecapturesChangedAddresses(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	DB.Exec(`INSERT INTO addresses (id, config_id, address_data) VALUES (10, 1, '{"name":"Billing HQ"}')`)
+	dcID := insertTestDC(t, 1, "ODC-SNAP-002", "official", 1)
+
+	billTo := 2
+	snap := &models.DCSnapshot{
+		Addresses: map[string]*models.DCSnapshotAddress{
+			models.SnapshotRoleShipTo: {ID: 1, Data: map[string]string{"name": "Old Site"}},
+			models.SnapshotRoleBillTo: {ID: 2, Data: map[string]string{"name": "Old Billing"}},
+		},
+	}
+	if err := saveDCSnapshot(DB, dcID, snap); err != nil {
+		t.Fatalf("saveDCSnapshot: %v", err)
+	}
+
+	dc := &models.DeliveryChallan{ID: dcID, ProjectID: 1, DCType: "official", Status: "issued", ShipToAddressID: 1, BillToAddressID: &billTo}
+	got, err := amendedDCSnapshot(dc, &models.DCAmendment{BillToAddressID: 10})
+	if err != nil {
+		t.Fatalf("amendedDCSnapshot: %v", err)
+	}
+	if addr := got.Address(models.SnapshotRoleBillTo); addr == nil || addr.Data["name"] != "Billing HQ" {
+		t.Errorf("bill-to should be re-captured, got %+v", addr)
+	}
+	if addr := got.Address(models.SnapshotRoleShipTo); addr == nil || addr.Data["name"] != "Old Site" {
+		t.Errorf("ship-to should stay frozen, got %+v", addr)
+	}
+}
@@ -103,6 +103,13 @@ func GetTemplateProducts(templateID int) ([]*models.TemplateProductRow, error) {
 		}
 		products = append(products, p)
 	}
+	rules := make([]*models.Product, len(products))
+	for i, p := range products {
+		rules[i] = &p.Product
+	}
+	if err := loadProductRules(rules...); err != nil {
+		return nil, err
+	}
 	return products, nil
 }
 
@@ -156,13 +163,19 @@ func CreateTemplate(t *models.DCTemplate, products []TemplateProductInput) error
 
 // UpdateTemplate updates template fields and replaces all product associations in a transaction.
 // Transaction uses DB.Begin(); sqlc queries run within that transaction via db.New(tx).
-func UpdateTemplate(t *models.DCTemplate, products []TemplateProductInput) error {
+// The template must still be at the edit version the form was opened with
+// (ErrStaleVersion otherwise).
+func UpdateTemplate(t *models.DCTemplate, products []TemplateProductInput, version int) error {
 	tx, err := DB.Begin()
 	if err != nil {
 		return err
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	if err := claimEditVersion(tx, models.AuditEntityTemplate, t.ID, version); err != nil {
+		return err
+	}
+
 	qtx := db.New(tx)
 
 	err = qtx.UpdateTemplate(context.Background(), db.UpdateTemplateParams{
@@ -2,6 +2,7 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/narendhupati/dc-management-tool/internal/models"
@@ -37,10 +38,23 @@ func setupTemplateTestDB(t *testing.T) func() {
 		brand_model TEXT DEFAULT '',
 		per_unit_price DECIMAL(10,2) DEFAULT 0,
 		gst_percentage DECIMAL(5,2) DEFAULT 0,
+		serial_pattern TEXT NOT NULL DEFAULT '',
+		serial_min_length INTEGER NOT NULL DEFAULT 0,
+		serial_max_length INTEGER NOT NULL DEFAULT 0,
+		serial_prefix TEXT NOT NULL DEFAULT '',
+		serial_luhn INTEGER NOT NULL DEFAULT 0,
+		warranty_months INTEGER NOT NULL DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
 	)`)
+	db.Exec(`CREATE TABLE product_kit_components (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kit_product_id INTEGER NOT NULL,
+		component_product_id INTEGER NOT NULL,
+		quantity INTEGER NOT NULL DEFAULT 1,
+		line_order INTEGER NOT NULL DEFAULT 0
+	)`)
 	db.Exec(`INSERT INTO products (id, project_id, item_name, uom, per_unit_price) VALUES (1, 1, 'Product A', 'Nos', 100.00)`)
 	db.Exec(`INSERT INTO products (id, project_id, item_name, uom, per_unit_price) VALUES (2, 1, 'Product B', 'Kg', 200.00)`)
 	db.Exec(`INSERT INTO products (id, project_id, item_name, uom, per_unit_price) VALUES (3, 1, 'Product C', 'Nos', 300.00)`)
@@ -50,6 +64,7 @@ func setupTemplateTestDB(t *testing.T) func() {
 		project_id INTEGER NOT NULL,
 		name TEXT NOT NULL,
 		purpose TEXT NOT NULL,
+		version INTEGER NOT NULL DEFAULT 1,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
@@ -173,11 +188,17 @@ func TestUpdateTemplate(t *testing.T) {
 	err := UpdateTemplate(tmpl, []TemplateProductInput{
 		{ProductID: 2, DefaultQuantity: 20},
 		{ProductID: 3, DefaultQuantity: 30},
-	})
+	}, 1)
 	if err != nil {
 		t.Fatalf("UpdateTemplate failed: %v", err)
 	}
 
+	// A second save from the same form version is stale and changes nothing.
+	tmpl.Name = "Kit A Again"
+	if err := UpdateTemplate(tmpl, nil, 1); !errors.Is(err, ErrStaleVersion) {
+		t.Errorf("stale UpdateTemplate err = %v, want ErrStaleVersion", err)
+	}
+
 	got, _ := GetTemplateByID(tmpl.ID)
 	if got.Name != "Kit A Updated" {
 		t.Errorf("Expected updated name, got '%s'", got.Name)
@@ -225,7 +225,16 @@ func GetDeliveryChallanByID(id int) (*models.DeliveryChallan, error) {
 	if err != nil {
 		return nil, err
 	}
-	return mapGetDCRow(row), nil
+	dc := mapGetDCRow(row)
+	if err := loadDCRevision(dc); err != nil {
+		return nil, err
+	}
+	if dc.Status == models.DCStatusCancelled {
+		if err := loadDCCancellation(dc); err != nil {
+			return nil, err
+		}
+	}
+	return dc, nil
 }
 
 // GetTransitDetailsByDCID fetches transit details for a DC.
@@ -257,7 +266,8 @@ func GetTransitDetailsByDCID(dcID int) (*models.DCTransitDetails, error) {
 	return td, nil
 }
 
-// GetLineItemsByDCID fetches all line items for a DC with product details joined.
+// GetLineItemsByDCID fetches all line items for a DC with product details joined,
+// and the components of kit lines.
 // sqlc-backed: GetLineItemsByDCID.
 func GetLineItemsByDCID(dcID int) ([]models.DCLineItem, error) {
 	rows, err := queries().GetLineItemsByDCID(ctx(), int64(dcID))
@@ -309,6 +319,9 @@ func GetLineItemsByDCID(dcID int) ([]models.DCLineItem, error) {
 		}
 		items = append(items, li)
 	}
+	if err := loadLineItemKits(items); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
 
@@ -328,7 +341,7 @@ type SerialConflict struct {
 }
 
 // CheckSerialsInProject checks which serial numbers already exist in a project.
-// Returns conflicts with DC info. Optionally excludes a specific DC (for edit mode).
+// Serials released by a cancelled DC are not reported. Returns conflicts with DC info. Optionally excludes a specific DC (for edit mode).
 // Hand-written SQL: dynamic IN clause with variable-length serial list cannot be handled by sqlc.
 func CheckSerialsInProject(projectID int, serials []string, excludeDCID *int) ([]SerialConflict, error) {
 	if len(serials) == 0 {
@@ -350,6 +363,7 @@ func CheckSerialsInProject(projectID int, serials []string, excludeDCID *int) ([
 		INNER JOIN delivery_challans dc ON li.dc_id = dc.id
 		INNER JOIN products p ON li.product_id = p.id
 		WHERE sn.project_id = ?
+		  AND sn.released_at IS NULL
 		  AND sn.serial_number IN (` + strings.Join(placeholders, ",") + `)`
 
 	if excludeDCID != nil {
@@ -376,6 +390,7 @@ func CheckSerialsInProject(projectID int, serials []string, excludeDCID *int) ([
 }
 
 // CheckSerialsInProjectByProduct checks serial uniqueness scoped to (project_id, product_id).
+// Serials released by a cancelled DC are not reported.
 // Hand-written SQL: dynamic IN clause with variable-length serial list cannot be handled by sqlc.
 func CheckSerialsInProjectByProduct(projectID, productID int, serials []string, excludeDCID *int) ([]SerialConflict, error) {
 	if len(serials) == 0 {
@@ -398,6 +413,7 @@ func CheckSerialsInProjectByProduct(projectID, productID int, serials []string,
 		INNER JOIN products p ON li.product_id = p.id
 		WHERE sn.project_id = ?
 		  AND sn.product_id = ?
+		  AND sn.released_at IS NULL
 		  AND sn.serial_number IN (` + strings.Join(placeholders, ",") + `)`
 
 	if excludeDCID != nil {
@@ -432,7 +448,15 @@ func DeleteDC(dcID int) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	q := db.New(tx)
+	if err := deleteDC(tx, dcID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteDC is DeleteDC against q.
+func deleteDC(dbtx db.DBTX, dcID int) error {
+	q := db.New(dbtx)
 
 	if err := q.DeleteSerialNumbersByDCID(ctx(), int64(dcID)); err != nil {
 		return fmt.Errorf("failed to delete serial numbers: %w", err)
@@ -445,8 +469,7 @@ func DeleteDC(dcID int) error {
 	if err := q.DeleteDeliveryChallan(ctx(), int64(dcID)); err != nil {
 		return fmt.Errorf("failed to delete DC: %w", err)
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 // GetDCsByProjectID fetches all DCs for a project.
@@ -478,11 +501,27 @@ func GetDCsByProjectID(projectID int, dcType string) ([]*models.DeliveryChallan,
 	return dcs, nil
 }
 
-// IssueDC transitions a DC from draft to issued status.
+// IssueDC transitions a DC from draft to issued status and freezes its print snapshot.
 // sqlc-backed: IssueDC.
 func IssueDC(dcID int, userID int) error {
+	dc, err := GetDeliveryChallanByID(dcID)
+	if err != nil {
+		return fmt.Errorf("failed to issue DC: %w", err)
+	}
+	// Snapshot before opening the transaction: the pool has a single connection.
+	snap, err := BuildDCSnapshot(dc)
+	if err != nil {
+		return fmt.Errorf("failed to issue DC: %w", err)
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
 	now := time.Now()
-	result, err := queries().IssueDC(ctx(), db.IssueDCParams{
+	result, err := db.New(tx).IssueDC(ctx(), db.IssueDCParams{
 		IssuedAt:  sql.NullTime{Time: now, Valid: true},
 		IssuedBy:  sql.NullInt64{Int64: int64(userID), Valid: true},
 		UpdatedAt: sql.NullTime{Time: now, Valid: true},
@@ -498,7 +537,10 @@ func IssueDC(dcID int, userID int) error {
 	if rowsAffected == 0 {
 		return fmt.Errorf("DC not found or already issued")
 	}
-	return nil
+	if err := saveDCSnapshot(tx, dcID, snap); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // GetDCsByShipmentGroup fetches all DCs belonging to a shipment group.
@@ -543,7 +585,7 @@ func GetAllAddressesByConfigID(configID int) ([]*models.Address, error) {
 }
 
 // UpdateTransitDC updates header and transit details of a transit DC in one transaction.
-// Only call this while the DC is still in "draft" status.
+// Only call this while the DC is still in "draft" status; issued DCs are amended via AmendDC.
 func UpdateTransitDC(dcID int, challanDate *string, transporterName, vehicleNumber, ewayBillNumber, notes string) error {
 	tx, err := DB.Begin()
 	if err != nil {
@@ -551,14 +593,22 @@ func UpdateTransitDC(dcID int, challanDate *string, transporterName, vehicleNumb
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	if _, err = tx.ExecContext(ctx(),
+	if err := updateTransitDC(tx, dcID, challanDate, transporterName, vehicleNumber, ewayBillNumber, notes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateTransitDC writes the challan date and transit details of a transit DC using q.
+func updateTransitDC(q db.DBTX, dcID int, challanDate *string, transporterName, vehicleNumber, ewayBillNumber, notes string) error {
+	if _, err := q.ExecContext(ctx(),
 		`UPDATE delivery_challans SET challan_date = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
 		nullTimeFromDateStr(challanDate), dcID,
 	); err != nil {
 		return fmt.Errorf("UpdateTransitDC challan: %w", err)
 	}
 
-	if _, err = tx.ExecContext(ctx(),
+	if _, err := q.ExecContext(ctx(),
 		`UPDATE dc_transit_details
 		    SET transporter_name = ?, vehicle_number = ?, eway_bill_number = ?, notes = ?
 		  WHERE dc_id = ?`,
@@ -567,13 +617,17 @@ func UpdateTransitDC(dcID int, challanDate *string, transporterName, vehicleNumb
 	); err != nil {
 		return fmt.Errorf("UpdateTransitDC transit details: %w", err)
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 // UpdateOfficialDC updates header fields on an existing official DC.
 func UpdateOfficialDC(dcID int, shipToAddressID int, challanDate *string) error {
-	_, err := DB.ExecContext(ctx(),
+	return updateOfficialDC(DB, dcID, shipToAddressID, challanDate)
+}
+
+// updateOfficialDC is UpdateOfficialDC against q.
+func updateOfficialDC(q db.DBTX, dcID int, shipToAddressID int, challanDate *string) error {
+	_, err := q.ExecContext(ctx(),
 		`UPDATE delivery_challans
 		    SET ship_to_address_id = ?, challan_date = ?, updated_at = CURRENT_TIMESTAMP
 		  WHERE id = ?`,
@@ -587,7 +641,12 @@ func UpdateOfficialDC(dcID int, shipToAddressID int, challanDate *string) error
 
 // UpdateDeliveryChallanAddressesAndDate updates address IDs and challan date on a delivery_challans record.
 func UpdateDeliveryChallanAddressesAndDate(dcID int, challanDate *string, billFromID, dispatchFromID, billToID, shipToID int) error {
-	_, err := DB.ExecContext(ctx(),
+	return updateDeliveryChallanAddressesAndDate(DB, dcID, challanDate, billFromID, dispatchFromID, billToID, shipToID)
+}
+
+// updateDeliveryChallanAddressesAndDate is UpdateDeliveryChallanAddressesAndDate against q.
+func updateDeliveryChallanAddressesAndDate(q db.DBTX, dcID int, challanDate *string, billFromID, dispatchFromID, billToID, shipToID int) error {
+	_, err := q.ExecContext(ctx(),
 		`UPDATE delivery_challans SET
             bill_from_address_id = ?, dispatch_from_address_id = ?,
             bill_to_address_id = ?, ship_to_address_id = ?,
@@ -613,12 +672,20 @@ func ReplaceLineItemsAndSerials(dcID int, projectID int, lineItems []models.DCLi
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	q := db.New(tx)
+	if err := replaceLineItemsAndSerials(tx, dcID, projectID, lineItems, serialsByLine); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// replaceLineItemsAndSerials is ReplaceLineItemsAndSerials against dbtx.
+func replaceLineItemsAndSerials(dbtx db.DBTX, dcID int, projectID int, lineItems []models.DCLineItem, serialsByLine [][]string) error {
+	q := db.New(dbtx)
 
-	if err = q.DeleteSerialNumbersByDCID(ctx(), int64(dcID)); err != nil {
+	if err := q.DeleteSerialNumbersByDCID(ctx(), int64(dcID)); err != nil {
 		return fmt.Errorf("ReplaceLineItemsAndSerials delete serials: %w", err)
 	}
-	if err = q.DeleteLineItemsByDCID(ctx(), int64(dcID)); err != nil {
+	if err := q.DeleteLineItemsByDCID(ctx(), int64(dcID)); err != nil {
 		return fmt.Errorf("ReplaceLineItemsAndSerials delete line items: %w", err)
 	}
 
@@ -659,8 +726,7 @@ func ReplaceLineItemsAndSerials(dcID int, projectID int, lineItems []models.DCLi
 			}
 		}
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 // DeleteOfficialDC is a thin wrapper around DeleteDC for semantic clarity in the edit flow.
@@ -679,6 +745,30 @@ func CreateOfficialDCInGroup(
 	lineItems []models.DCLineItem,
 	serialsByLine [][]string,
 	createdBy int,
+) (int, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("CreateOfficialDCInGroup: begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	dcID, err := createOfficialDCInGroup(tx, projectID, groupID, shipToAddressID, challanDate, lineItems, serialsByLine, createdBy)
+	if err != nil {
+		return 0, err
+	}
+	return dcID, tx.Commit()
+}
+
+// createOfficialDCInGroup is CreateOfficialDCInGroup within tx, so the DC number
+// is only consumed if the whole save commits. taxType and reverseCharge are on
+// the shipment_group, not the DC row.
+func createOfficialDCInGroup(
+	tx *sql.Tx,
+	projectID, groupID, shipToAddressID int,
+	challanDate *string,
+	lineItems []models.DCLineItem,
+	serialsByLine [][]string,
+	createdBy int,
 ) (int, error) {
 	// Parse challan date for DC number generation (financial year depends on date).
 	var dcDate time.Time
@@ -694,12 +784,11 @@ func CreateOfficialDCInGroup(
 	}
 
 	// Generate the next official DC number (consumes the sequence).
-	dcNumber, err := services.GenerateDCNumberForDate(DB, projectID, services.DCTypeOfficial, dcDate)
+	dcNumber, err := services.GenerateDCNumberTx(tx, projectID, services.DCTypeOfficial, dcDate)
 	if err != nil {
 		return 0, fmt.Errorf("CreateOfficialDCInGroup: generate DC number: %w", err)
 	}
 
-	// Build DC model. taxType and reverseCharge are on the shipment_group, not the DC row.
 	groupIDCopy := groupID
 	dc := &models.DeliveryChallan{
 		ProjectID:       projectID,
@@ -712,16 +801,9 @@ func CreateOfficialDCInGroup(
 		CreatedBy:       createdBy,
 	}
 
-	// Insert DC with line items and serials in a transaction.
-	tx, err := DB.Begin()
-	if err != nil {
-		return 0, fmt.Errorf("CreateOfficialDCInGroup: begin tx: %w", err)
-	}
-	defer func() { _ = tx.Rollback() }()
-
 	dcID, err := insertDCWithLineItemsAndSerials(tx, dc, nil, lineItems, serialsByLine)
 	if err != nil {
 		return 0, fmt.Errorf("CreateOfficialDCInGroup: %w", err)
 	}
-	return dcID, tx.Commit()
+	return dcID, nil
 }
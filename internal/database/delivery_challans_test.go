@@ -2,6 +2,8 @@ package database
 
 import (
 	"database/sql"
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -31,6 +33,7 @@ func setupDCTestDB(t *testing.T) func() {
             id INTEGER PRIMARY KEY AUTOINCREMENT,
             username TEXT NOT NULL,
             password_hash TEXT NOT NULL,
+            full_name TEXT DEFAULT '',
             role TEXT DEFAULT 'user'
         )`,
 		`INSERT OR IGNORE INTO users (id, username, password_hash) VALUES (1, 'testuser', 'hash')`,
@@ -46,6 +49,7 @@ func setupDCTestDB(t *testing.T) func() {
             district_name TEXT DEFAULT '',
             mandal_name TEXT DEFAULT '',
             mandal_code TEXT DEFAULT '',
+            address_code TEXT,
             created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
             updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
         )`,
@@ -81,7 +85,64 @@ func setupDCTestDB(t *testing.T) func() {
             shipment_group_id INTEGER,
             bill_from_address_id INTEGER,
             dispatch_from_address_id INTEGER,
+            cancelled_at DATETIME,
+            cancelled_by INTEGER,
+            cancellation_reason TEXT,
+            revision INTEGER NOT NULL DEFAULT 0,
             UNIQUE(project_id, dc_number)
+        )`,
+		`CREATE TABLE IF NOT EXISTS dc_revisions (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            dc_id INTEGER NOT NULL REFERENCES delivery_challans(id) ON DELETE CASCADE,
+            revision INTEGER NOT NULL,
+            dc_number TEXT NOT NULL,
+            snapshot_json TEXT NOT NULL,
+            amendment_reason TEXT NOT NULL,
+            amended_by INTEGER,
+            amended_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            UNIQUE(dc_id, revision)
+        )`,
+		`CREATE TABLE IF NOT EXISTS dc_snapshots (
+            dc_id INTEGER PRIMARY KEY REFERENCES delivery_challans(id) ON DELETE CASCADE,
+            snapshot_json TEXT NOT NULL,
+            captured_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+		`CREATE TABLE IF NOT EXISTS dc_proofs_of_delivery (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            dc_id INTEGER NOT NULL REFERENCES delivery_challans(id) ON DELETE CASCADE,
+            received_date DATE NOT NULL,
+            receiver_name TEXT NOT NULL,
+            receiver_designation TEXT NOT NULL DEFAULT '',
+            is_partial INTEGER NOT NULL DEFAULT 0,
+            remarks TEXT NOT NULL DEFAULT '',
+            file_path TEXT NOT NULL DEFAULT '',
+            recorded_by INTEGER,
+            recorded_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+		`CREATE TABLE IF NOT EXISTS dc_receipts (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            dc_id INTEGER NOT NULL UNIQUE REFERENCES delivery_challans(id) ON DELETE CASCADE,
+            received_date DATE NOT NULL,
+            remarks TEXT NOT NULL DEFAULT '',
+            recorded_by INTEGER,
+            recorded_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+            updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+        )`,
+		`CREATE TABLE IF NOT EXISTS dc_receipt_lines (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            receipt_id INTEGER NOT NULL REFERENCES dc_receipts(id) ON DELETE CASCADE,
+            line_item_id INTEGER NOT NULL,
+            received_qty INTEGER NOT NULL DEFAULT 0,
+            short_qty INTEGER NOT NULL DEFAULT 0,
+            damaged_qty INTEGER NOT NULL DEFAULT 0
+        )`,
+		`CREATE TABLE IF NOT EXISTS dc_receipt_serials (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            receipt_id INTEGER NOT NULL REFERENCES dc_receipts(id) ON DELETE CASCADE,
+            line_item_id INTEGER NOT NULL,
+            serial_number TEXT NOT NULL,
+            condition TEXT NOT NULL CHECK(condition IN ('damaged', 'missing')),
+            UNIQUE(line_item_id, serial_number)
         )`,
 		`CREATE TABLE IF NOT EXISTS dc_transit_details (
             id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -99,9 +160,21 @@ func setupDCTestDB(t *testing.T) func() {
             hsn_code TEXT DEFAULT '',
             uom TEXT DEFAULT 'nos',
             brand_model TEXT DEFAULT '',
-            gst_percentage REAL DEFAULT 0
+            gst_percentage REAL DEFAULT 0,
+            serial_pattern TEXT NOT NULL DEFAULT '',
+            serial_min_length INTEGER NOT NULL DEFAULT 0,
+            serial_max_length INTEGER NOT NULL DEFAULT 0,
+            serial_prefix TEXT NOT NULL DEFAULT '',
+            serial_luhn INTEGER NOT NULL DEFAULT 0
         )`,
 		`INSERT OR IGNORE INTO products (id, item_name) VALUES (1, 'Test Product')`,
+		`CREATE TABLE IF NOT EXISTS product_kit_components (
+            id INTEGER PRIMARY KEY AUTOINCREMENT,
+            kit_product_id INTEGER NOT NULL,
+            component_product_id INTEGER NOT NULL,
+            quantity INTEGER NOT NULL DEFAULT 1,
+            line_order INTEGER NOT NULL DEFAULT 0
+        )`,
 		`CREATE TABLE IF NOT EXISTS dc_line_items (
             id INTEGER PRIMARY KEY AUTOINCREMENT,
             dc_id INTEGER NOT NULL,
@@ -124,9 +197,11 @@ func setupDCTestDB(t *testing.T) func() {
             serial_number TEXT NOT NULL,
             product_id INTEGER,
             created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-            FOREIGN KEY (line_item_id) REFERENCES dc_line_items(id) ON DELETE CASCADE,
-            UNIQUE(project_id, serial_number)
+            released_at DATETIME,
+            FOREIGN KEY (line_item_id) REFERENCES dc_line_items(id) ON DELETE CASCADE
         )`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_serial_numbers_project_product_serial
+            ON serial_numbers(project_id, product_id, serial_number) WHERE released_at IS NULL`,
 	}
 	for _, s := range stmts {
 		if _, err := db.Exec(s); err != nil {
@@ -255,6 +330,27 @@ func TestUpdateTransitDC(t *testing.T) {
 	}
 }
 
+func TestSetEwayBillNumberRecordsOnce(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	dcID := insertTestDC(t, 1, "TDC-001", "transit", 1)
+	dc := &models.DeliveryChallan{ID: dcID, DCType: "transit"}
+
+	if err := SetEwayBillNumber(dc, "123456789012"); err != nil {
+		t.Fatalf("SetEwayBillNumber: %v", err)
+	}
+	if err := SetEwayBillNumber(dc, "999999999999"); !errors.Is(err, ErrEwayBillRecorded) {
+		t.Errorf("second SetEwayBillNumber err = %v, want ErrEwayBillRecorded", err)
+	}
+
+	var eway string
+	DB.QueryRow(`SELECT eway_bill_number FROM dc_transit_details WHERE dc_id = ?`, dcID).Scan(&eway)
+	if eway != "123456789012" {
+		t.Errorf("eway_bill_number = %q, want the first number kept", eway)
+	}
+}
+
 func TestReplaceLineItemsAndSerials(t *testing.T) {
 	cleanup := setupDCTestDB(t)
 	defer cleanup()
@@ -304,6 +400,59 @@ func TestReplaceLineItemsAndSerials(t *testing.T) {
 	}
 }
 
+func TestKitComponentsKeptOnDCs(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	for _, stmt := range []string{
+		`INSERT INTO products (id, item_name) VALUES (2, 'Tablet Kit'), (3, 'Tablet'), (4, 'Charger'), (5, 'Old Charger')`,
+		`INSERT INTO product_kit_components (kit_product_id, component_product_id, quantity, line_order) VALUES (2, 3, 1, 1), (2, 4, 1, 2)`,
+	} {
+		if _, err := DB.Exec(stmt); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	kit := []models.KitComponent{{ProductID: 3, Quantity: 1}, {ProductID: 4, Quantity: 1}}
+
+	// An unused kit may change; saving the same list is always allowed.
+	if err := setKitComponents(DB, 2, []models.KitComponent{{ProductID: 3, Quantity: 1}}); err != nil {
+		t.Fatalf("change unused kit: %v", err)
+	}
+	if err := setKitComponents(DB, 2, kit); err != nil {
+		t.Fatalf("restore kit: %v", err)
+	}
+
+	draftID := insertTestDC(t, 1, "ODC-001", "official", 1)
+	issuedID := insertTestDC(t, 1, "ODC-002", "official", 1)
+	for _, id := range []int{draftID, issuedID} {
+		DB.Exec(`INSERT INTO dc_line_items (dc_id, product_id, quantity, line_order) VALUES (?, 2, 1, 1)`, id)
+	}
+	if err := setKitComponents(DB, 2, kit); err != nil {
+		t.Errorf("unchanged kit on a DC: %v", err)
+	}
+	if err := setKitComponents(DB, 2, kit[:1]); !errors.Is(err, ErrKitComponentsLocked) {
+		t.Errorf("changed kit on a DC: err = %v, want ErrKitComponentsLocked", err)
+	}
+
+	// ODC-002 was issued with the kit's earlier charger.
+	DB.Exec(`INSERT INTO dc_snapshots (dc_id, snapshot_json) VALUES (?,
+		'{"products":{"2":{"item_name":"Tablet Kit"}},"kit_components":{"2":[{"product_id":3,"item_name":"Tablet","quantity":1},{"product_id":5,"item_name":"Old Charger","quantity":1}]}}')`,
+		issuedID)
+	for id, want := range map[int][]int{draftID: {3, 4}, issuedID: {3, 5}} {
+		items, err := GetLineItemsByDCID(id)
+		if err != nil || len(items) != 1 {
+			t.Fatalf("GetLineItemsByDCID(%d) = %v, %v", id, items, err)
+		}
+		var got []int
+		for _, c := range items[0].KitComponents {
+			got = append(got, c.ProductID)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("DC %d components = %v, want %v", id, got, want)
+		}
+	}
+}
+
 func TestDeleteOfficialDC(t *testing.T) {
 	cleanup := setupDCTestDB(t)
 	defer cleanup()
@@ -333,3 +482,352 @@ func TestDeleteOfficialDC(t *testing.T) {
 		t.Error("line item should have been deleted")
 	}
 }
+
+func TestCancelDC_ReleasesSerials(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	dcID := insertTestDC(t, 1, "TDC-CAN-001", "transit", 1)
+	DB.Exec(`UPDATE delivery_challans SET status = 'issued' WHERE id = ?`, dcID)
+	liRes, _ := DB.Exec(`INSERT INTO dc_line_items (dc_id, product_id, quantity, line_order) VALUES (?, 1, 1, 1)`, dcID)
+	liID, _ := liRes.LastInsertId()
+	DB.Exec(`INSERT INTO serial_numbers (project_id, line_item_id, serial_number, product_id) VALUES (1, ?, 'CAN-SN-001', 1)`, liID)
+
+	conflicts, err := CheckSerialsInProjectByProduct(1, 1, []string{"CAN-SN-001"}, nil)
+	if err != nil {
+		t.Fatalf("CheckSerialsInProjectByProduct failed: %v", err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("want 1 conflict before cancel, got %d", len(conflicts))
+	}
+
+	if err := CancelDC(dcID, 1, "Vehicle breakdown"); err != nil {
+		t.Fatalf("CancelDC failed: %v", err)
+	}
+
+	var status, reason string
+	var cancelledBy int
+	DB.QueryRow(`SELECT status, cancellation_reason, cancelled_by FROM delivery_challans WHERE id = ?`, dcID).Scan(&status, &reason, &cancelledBy)
+	if status != models.DCStatusCancelled {
+		t.Errorf("status: want cancelled, got %q", status)
+	}
+	if reason != "Vehicle breakdown" || cancelledBy != 1 {
+		t.Errorf("cancellation not recorded: reason=%q by=%d", reason, cancelledBy)
+	}
+
+	conflicts, err = CheckSerialsInProjectByProduct(1, 1, []string{"CAN-SN-001"}, nil)
+	if err != nil {
+		t.Fatalf("CheckSerialsInProjectByProduct failed: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("want no conflicts after cancel, got %d", len(conflicts))
+	}
+
+	// The released serial can be dispatched again on a new DC.
+	dc2 := insertTestDC(t, 1, "TDC-CAN-002", "transit", 1)
+	li2Res, _ := DB.Exec(`INSERT INTO dc_line_items (dc_id, product_id, quantity, line_order) VALUES (?, 1, 1, 1)`, dc2)
+	li2ID, _ := li2Res.LastInsertId()
+	if _, err := DB.Exec(`INSERT INTO serial_numbers (project_id, line_item_id, serial_number, product_id) VALUES (1, ?, 'CAN-SN-001', 1)`, li2ID); err != nil {
+		t.Errorf("re-using released serial failed: %v", err)
+	}
+
+	if err := CancelDC(dcID, 1, "again"); err == nil {
+		t.Error("cancelling an already cancelled DC should fail")
+	}
+}
+
+func TestCancelShipmentGroup_UnlocksShipTo(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	res, _ := DB.Exec(`INSERT INTO shipment_groups (project_id, status) VALUES (1, 'issued')`)
+	gid64, _ := res.LastInsertId()
+	gid := int(gid64)
+
+	transitID := insertTestDC(t, 1, "TDC-GRP-001", "transit", 1)
+	officialID := insertTestDC(t, 1, "ODC-GRP-001", "official", 2)
+	DB.Exec(`UPDATE delivery_challans SET status = 'issued', shipment_group_id = ? WHERE id IN (?, ?)`, gid, transitID, officialID)
+
+	locked, err := GetIssuedShipToAddressIDs(1)
+	if err != nil {
+		t.Fatalf("GetIssuedShipToAddressIDs failed: %v", err)
+	}
+	if len(locked) != 1 || locked[0] != 2 {
+		t.Fatalf("want ship-to 2 locked before cancel, got %v", locked)
+	}
+
+	n, err := CancelShipmentGroup(gid, 1, "Order withdrawn")
+	if err != nil {
+		t.Fatalf("CancelShipmentGroup failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("want 2 DCs cancelled, got %d", n)
+	}
+
+	locked, _ = GetIssuedShipToAddressIDs(1)
+	if len(locked) != 0 {
+		t.Errorf("want no locked ship-to after cancel, got %v", locked)
+	}
+
+	var groupStatus string
+	DB.QueryRow(`SELECT status FROM shipment_groups WHERE id = ?`, gid).Scan(&groupStatus)
+	if groupStatus != "cancelled" {
+		t.Errorf("group status: want cancelled, got %q", groupStatus)
+	}
+}
+
+func TestCancelShipmentGroup_RefusesInTransaction(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	res, _ := DB.Exec(`INSERT INTO shipment_groups (project_id, status) VALUES (1, 'issued')`)
+	gid64, _ := res.LastInsertId()
+	gid := int(gid64)
+	transitID := insertTestDC(t, 1, "TDC-GRP-001", "transit", 1)
+	officialID := insertTestDC(t, 1, "ODC-GRP-001", "official", 2)
+	DB.Exec(`UPDATE delivery_challans SET status = 'issued', shipment_group_id = ? WHERE id = ?`, gid, transitID)
+	// Proof of delivery recorded after the handler's checks.
+	DB.Exec(`UPDATE delivery_challans SET status = 'delivered', shipment_group_id = ? WHERE id = ?`, gid, officialID)
+
+	if _, err := CancelShipmentGroup(gid, 1, "Order withdrawn"); !errors.Is(err, ErrShipmentGroupDelivered) {
+		t.Fatalf("delivered group: err = %v, want ErrShipmentGroupDelivered", err)
+	}
+	var groupStatus, transitStatus string
+	DB.QueryRow(`SELECT status FROM shipment_groups WHERE id = ?`, gid).Scan(&groupStatus)
+	DB.QueryRow(`SELECT status FROM delivery_challans WHERE id = ?`, transitID).Scan(&transitStatus)
+	if groupStatus != "issued" || transitStatus != "issued" {
+		t.Errorf("after refusal: group %q, transit %q; want both still issued", groupStatus, transitStatus)
+	}
+
+	// Group no longer issued, though a DC still is.
+	DB.Exec(`UPDATE delivery_challans SET status = 'issued' WHERE id = ?`, officialID)
+	DB.Exec(`UPDATE shipment_groups SET status = 'cancelled' WHERE id = ?`, gid)
+	if _, err := CancelShipmentGroup(gid, 1, "Order withdrawn"); !errors.Is(err, ErrShipmentGroupNotIssued) {
+		t.Errorf("cancelled group: err = %v, want ErrShipmentGroupNotIssued", err)
+	}
+	DB.QueryRow(`SELECT status FROM delivery_challans WHERE id = ?`, transitID).Scan(&transitStatus)
+	if transitStatus != "issued" {
+		t.Errorf("transit status = %q, want issued: the refused cancel rolls back", transitStatus)
+	}
+}
+
+func TestAmendDC_CreatesRevision(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	dcID := insertTestDC(t, 1, "TDC-AMD-001", "transit", 1)
+	DB.Exec(`UPDATE delivery_challans SET status = 'issued', challan_date = '2026-04-01' WHERE id = ?`, dcID)
+	DB.Exec(`INSERT INTO dc_transit_details (dc_id, transporter_name, vehicle_number) VALUES (?, 'ABC Logistics', 'AP01AB1234')`, dcID)
+
+	dc, err := GetDeliveryChallanByID(dcID)
+	if err != nil {
+		t.Fatalf("GetDeliveryChallanByID failed: %v", err)
+	}
+	if dc.Revision != 0 {
+		t.Fatalf("new DC should be revision 0, got %d", dc.Revision)
+	}
+
+	date := "2026-04-01"
+	rev, err := AmendDC(dc, &models.DCAmendment{
+		Reason:          "Vehicle changed",
+		ChallanDate:     &date,
+		TransporterName: "ABC Logistics",
+		VehicleNumber:   "AP01CD5678",
+		EwayBillNumber:  "331000123456",
+	}, 1)
+	if err != nil {
+		t.Fatalf("AmendDC failed: %v", err)
+	}
+	if rev != 1 {
+		t.Errorf("want revision 1, got %d", rev)
+	}
+
+	// A stale copy of the DC must not be amended again.
+	if _, err := AmendDC(dc, &models.DCAmendment{Reason: "stale", ChallanDate: &date}, 1); err == nil {
+		t.Error("amending a stale DC should fail")
+	}
+
+	amended, err := GetDeliveryChallanByID(dcID)
+	if err != nil {
+		t.Fatalf("GetDeliveryChallanByID failed: %v", err)
+	}
+	if amended.DCNumber != "TDC-AMD-001" || amended.DisplayNumber() != "TDC-AMD-001/R1" {
+		t.Errorf("unexpected numbers: %q / %q", amended.DCNumber, amended.DisplayNumber())
+	}
+
+	td, _ := GetTransitDetailsByDCID(dcID)
+	if td == nil || td.VehicleNumber != "AP01CD5678" || td.EwayBillNumber != "331000123456" {
+		t.Errorf("transit details not amended: %+v", td)
+	}
+
+	history, err := GetDCRevisionHistory(amended)
+	if err != nil {
+		t.Fatalf("GetDCRevisionHistory failed: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("want 1 history entry, got %d", len(history))
+	}
+	if history[0].Reason != "Vehicle changed" || history[0].AmendedByName != "testuser" {
+		t.Errorf("unexpected history entry: %+v", history[0])
+	}
+	if len(history[0].Changes) != 2 {
+		t.Errorf("want vehicle and e-way bill changes, got %+v", history[0].Changes)
+	}
+}
+
+func TestRecordProofOfDelivery(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	dcID := insertTestDC(t, 1, "ODC-POD-1", "official", 1)
+	draftID := insertTestDC(t, 1, "ODC-POD-2", "official", 2)
+	if _, err := DB.Exec(`UPDATE delivery_challans SET status = 'issued' WHERE id = ?`, dcID); err != nil {
+		t.Fatalf("issue DC: %v", err)
+	}
+
+	user := 1
+	record := func(id int, partial bool) error {
+		return RecordProofOfDelivery(&models.ProofOfDelivery{
+			DCID: id, ReceivedDate: "2026-04-10", ReceiverName: "K. Ramesh", ReceiverDesignation: "Headmaster",
+			IsPartial: partial, FilePath: "/static/uploads/pods/pod.pdf", RecordedBy: &user,
+		})
+	}
+
+	if err := record(draftID, false); err == nil {
+		t.Error("expected a draft DC to reject a POD")
+	}
+
+	if err := record(dcID, true); err != nil {
+		t.Fatalf("partial POD: %v", err)
+	}
+	dc, _ := GetDeliveryChallanByID(dcID)
+	if dc.Status != models.DCStatusPartiallyDelivered {
+		t.Errorf("after partial POD: status = %q", dc.Status)
+	}
+
+	if err := record(dcID, false); err != nil {
+		t.Fatalf("full POD: %v", err)
+	}
+	dc, _ = GetDeliveryChallanByID(dcID)
+	if dc.Status != models.DCStatusDelivered {
+		t.Errorf("after full POD: status = %q", dc.Status)
+	}
+
+	if err := record(dcID, false); err == nil {
+		t.Error("expected a delivered DC to reject another POD")
+	}
+
+	pods, err := GetProofsOfDelivery(dcID)
+	if err != nil {
+		t.Fatalf("GetProofsOfDelivery: %v", err)
+	}
+	if len(pods) != 2 || !pods[0].IsPartial || pods[1].IsPartial {
+		t.Fatalf("want partial then full POD, got %+v", pods)
+	}
+	if pods[0].ReceiverName != "K. Ramesh" || pods[0].RecordedByName != "testuser" || pods[0].ReceivedDate != "2026-04-10" {
+		t.Errorf("unexpected POD: %+v", pods[0])
+	}
+}
+
+func TestSaveGoodsReceipt_Discrepancy(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	dcID := insertTestDC(t, 1, "ODC-GR-1", "official", 1)
+	okID := insertTestDC(t, 1, "ODC-GR-2", "official", 2)
+	DB.Exec(`UPDATE delivery_challans SET status = 'issued', challan_date = '2026-04-01' WHERE id IN (?, ?)`, dcID, okID)
+
+	var liIDs []int
+	for _, id := range []int{dcID, okID} {
+		res, err := DB.Exec(`INSERT INTO dc_line_items (dc_id, product_id, quantity, line_order) VALUES (?, 1, 3, 1)`, id)
+		if err != nil {
+			t.Fatalf("insert line item: %v", err)
+		}
+		liID, _ := res.LastInsertId()
+		liIDs = append(liIDs, int(liID))
+	}
+	for _, sn := range []string{"GR-SN-1", "GR-SN-2", "GR-SN-3"} {
+		DB.Exec(`INSERT INTO serial_numbers (project_id, line_item_id, serial_number, product_id) VALUES (1, ?, ?, 1)`, liIDs[0], sn)
+	}
+
+	user := 1
+	save := func(id int, mutate func(l *models.GoodsReceiptLine)) {
+		t.Helper()
+		lines, err := NewGoodsReceiptLines(id)
+		if err != nil || len(lines) != 1 {
+			t.Fatalf("NewGoodsReceiptLines: %v (%d lines)", err, len(lines))
+		}
+		mutate(&lines[0])
+		r := &models.GoodsReceipt{DCID: id, ReceivedDate: "2026-04-05", RecordedBy: &user, Lines: lines}
+		if errs := r.Validate(); len(errs) > 0 {
+			t.Fatalf("Validate: %v", errs)
+		}
+		if err := SaveGoodsReceipt(r); err != nil {
+			t.Fatalf("SaveGoodsReceipt: %v", err)
+		}
+	}
+
+	save(dcID, func(l *models.GoodsReceiptLine) {
+		l.Received, l.Short = 2, 1
+		l.MissingSerials = []string{"GR-SN-3"}
+	})
+	// Recording again replaces the earlier figures.
+	save(dcID, func(l *models.GoodsReceiptLine) {
+		l.Received, l.Damaged = 2, 1
+		l.DamagedSerials = []string{"GR-SN-2"}
+	})
+	save(okID, func(l *models.GoodsReceiptLine) {})
+
+	r, err := GetGoodsReceipt(dcID)
+	if err != nil || r == nil {
+		t.Fatalf("GetGoodsReceipt: %v", err)
+	}
+	l := r.Lines[0]
+	if l.Received != 2 || l.Short != 0 || l.Damaged != 1 || len(l.MissingSerials) != 0 ||
+		len(l.DamagedSerials) != 1 || l.DamagedSerials[0] != "GR-SN-2" {
+		t.Errorf("unexpected receipt line: %+v", l)
+	}
+	if r.RecordedByName != "testuser" {
+		t.Errorf("RecordedByName = %q", r.RecordedByName)
+	}
+
+	rows, err := GetDiscrepancyReport(1, nil, nil)
+	if err != nil {
+		t.Fatalf("GetDiscrepancyReport: %v", err)
+	}
+	if len(rows) != 1 || rows[0].DCID != dcID {
+		t.Fatalf("want only DC %d in the report, got %+v", dcID, rows)
+	}
+	if rows[0].Dispatched != 3 || rows[0].Received != 2 || rows[0].Damaged != 1 || rows[0].DamagedSerials != 1 {
+		t.Errorf("unexpected report row: %+v", rows[0])
+	}
+}
+
+func TestGetReturnableSerials_SkipsReleased(t *testing.T) {
+	cleanup := setupDCTestDB(t)
+	defer cleanup()
+
+	dcID := insertTestDC(t, 1, "TDC-RET-001", "transit", 1)
+	DB.Exec(`UPDATE delivery_challans SET status = 'issued' WHERE id = ?`, dcID)
+	liRes, _ := DB.Exec(`INSERT INTO dc_line_items (dc_id, product_id, quantity, line_order) VALUES (?, 1, 3, 1)`, dcID)
+	liID, _ := liRes.LastInsertId()
+	for _, sn := range []string{"RET-SN-001", "RET-SN-002", "RET-SN-003"} {
+		DB.Exec(`INSERT INTO serial_numbers (project_id, line_item_id, serial_number, product_id) VALUES (1, ?, ?, 1)`, liID, sn)
+	}
+	// RET-SN-002 has already come back on an earlier return.
+	DB.Exec(`UPDATE serial_numbers SET released_at = CURRENT_TIMESTAMP WHERE serial_number = 'RET-SN-002'`)
+
+	serials, err := GetReturnableSerials(dcID)
+	if err != nil {
+		t.Fatalf("GetReturnableSerials failed: %v", err)
+	}
+	if len(serials) != 2 {
+		t.Fatalf("want 2 returnable serials, got %d", len(serials))
+	}
+	if serials[0].SerialNumber != "RET-SN-001" || serials[1].SerialNumber != "RET-SN-003" {
+		t.Errorf("returnable serials: got %q, %q", serials[0].SerialNumber, serials[1].SerialNumber)
+	}
+	if serials[0].ItemName != "Test Product" || serials[0].LineItemID != int(liID) {
+		t.Errorf("serial details not loaded: %+v", serials[0])
+	}
+}
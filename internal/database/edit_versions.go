@@ -0,0 +1,92 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	db "github.com/narendhupati/dc-management-tool/internal/database/sqlc"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// ErrStaleVersion is returned when a save carries an older edit version than the
+// stored record, i.e. someone else saved it after the form was opened.
+var ErrStaleVersion = errors.New("record was changed by someone else")
+
+// versionedTables maps the audit entity types that carry an edit version to
+// their table. Only these names are ever interpolated into SQL.
+var versionedTables = map[string]string{
+	models.AuditEntityShipmentGroup: "shipment_groups",
+	models.AuditEntityTransferDC:    "transfer_dcs",
+	models.AuditEntityProduct:       "products",
+	models.AuditEntityAddress:       "addresses",
+	models.AuditEntityTemplate:      "dc_templates",
+}
+
+func versionedTable(entityType string) (string, error) {
+	table, ok := versionedTables[entityType]
+	if !ok {
+		return "", fmt.Errorf("entity type %q has no edit version", entityType)
+	}
+	return table, nil
+}
+
+// GetEditVersion returns the current edit version of a record. Edit forms carry
+// it so the save can detect that the record changed in the meantime.
+// Hand-written SQL: the version column is not part of the sqlc queries.
+func GetEditVersion(entityType string, id int) (int, error) {
+	table, err := versionedTable(entityType)
+	if err != nil {
+		return 0, err
+	}
+	var version int
+	if err := DB.QueryRowContext(ctx(),
+		fmt.Sprintf(`SELECT version FROM %s WHERE id = ?`, table), id,
+	).Scan(&version); err != nil {
+		return 0, fmt.Errorf("GetEditVersion: %w", err)
+	}
+	return version, nil
+}
+
+// claimEditVersion bumps a record's edit version using q, provided it still
+// equals the version the form was opened with. Saves call it inside their own
+// transaction, so of two concurrent saves from the same version exactly one
+// proceeds and a failed save leaves the version as it was. A form without a
+// version (expected of 0) is treated as stale rather than saved unchecked.
+func claimEditVersion(q db.DBTX, entityType string, id, expected int) error {
+	table, err := versionedTable(entityType)
+	if err != nil {
+		return err
+	}
+	if expected <= 0 {
+		return ErrStaleVersion
+	}
+	res, err := q.ExecContext(ctx(),
+		fmt.Sprintf(`UPDATE %s SET version = version + 1 WHERE id = ? AND version = ?`, table),
+		id, expected,
+	)
+	if err != nil {
+		return fmt.Errorf("claimEditVersion: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("claimEditVersion: %w", err)
+	} else if n == 0 {
+		return ErrStaleVersion
+	}
+	return nil
+}
+
+// bumpEditVersion moves a record's edit version on using q, without checking
+// it, for saves that do not come from an edit form (the serial capture
+// screen), so edit forms opened before them detect the change.
+func bumpEditVersion(q db.DBTX, entityType string, id int) error {
+	table, err := versionedTable(entityType)
+	if err != nil {
+		return err
+	}
+	if _, err := q.ExecContext(ctx(),
+		fmt.Sprintf(`UPDATE %s SET version = version + 1 WHERE id = ?`, table), id,
+	); err != nil {
+		return fmt.Errorf("bumpEditVersion: %w", err)
+	}
+	return nil
+}
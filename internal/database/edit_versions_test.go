@@ -0,0 +1,117 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+func setupEditVersionTestDB(t *testing.T) func() {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:edit_version_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	stmts := []string{
+		`CREATE TABLE products (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			item_name TEXT NOT NULL,
+			version INTEGER NOT NULL DEFAULT 1
+		)`,
+		`INSERT INTO products (id, item_name) VALUES (1, 'Router')`,
+	}
+	for _, s := range stmts {
+		if _, err := db.Exec(s); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+
+	DB = db
+	return func() { db.Close() }
+}
+
+func TestClaimEditVersion(t *testing.T) {
+	cleanup := setupEditVersionTestDB(t)
+	defer cleanup()
+
+	v, err := GetEditVersion(models.AuditEntityProduct, 1)
+	if err != nil {
+		t.Fatalf("GetEditVersion: %v", err)
+	}
+	if v != 1 {
+		t.Fatalf("initial version = %d, want 1", v)
+	}
+
+	// Two forms opened at version 1: the first save wins, the second is stale.
+	if err := claimEditVersion(DB, models.AuditEntityProduct, 1, 1); err != nil {
+		t.Fatalf("first claim: %v", err)
+	}
+	if err := claimEditVersion(DB, models.AuditEntityProduct, 1, 1); !errors.Is(err, ErrStaleVersion) {
+		t.Errorf("second claim err = %v, want ErrStaleVersion", err)
+	}
+	if v, _ := GetEditVersion(models.AuditEntityProduct, 1); v != 2 {
+		t.Errorf("version after one save = %d, want 2", v)
+	}
+
+	// Reopening the form picks up the new version.
+	if err := claimEditVersion(DB, models.AuditEntityProduct, 1, 2); err != nil {
+		t.Errorf("claim from current version: %v", err)
+	}
+
+	// A form without a version is stale, not saved unchecked.
+	if err := claimEditVersion(DB, models.AuditEntityProduct, 1, 0); !errors.Is(err, ErrStaleVersion) {
+		t.Errorf("claim without a version err = %v, want ErrStaleVersion", err)
+	}
+	if v, _ := GetEditVersion(models.AuditEntityProduct, 1); v != 3 {
+		t.Errorf("version = %d, want 3", v)
+	}
+}
+
+func TestClaimEditVersionRolledBack(t *testing.T) {
+	cleanup := setupEditVersionTestDB(t)
+	defer cleanup()
+
+	// A save that fails after claiming leaves the version as it was, so the
+	// user's next submit from the same form still goes through.
+	tx, err := DB.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if err := claimEditVersion(tx, models.AuditEntityProduct, 1, 1); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	_ = tx.Rollback()
+	if v, _ := GetEditVersion(models.AuditEntityProduct, 1); v != 1 {
+		t.Errorf("version after a failed save = %d, want 1", v)
+	}
+}
+
+func TestBumpEditVersion(t *testing.T) {
+	cleanup := setupEditVersionTestDB(t)
+	defer cleanup()
+
+	if err := bumpEditVersion(DB, models.AuditEntityProduct, 1); err != nil {
+		t.Fatalf("bumpEditVersion: %v", err)
+	}
+	if err := claimEditVersion(DB, models.AuditEntityProduct, 1, 1); !errors.Is(err, ErrStaleVersion) {
+		t.Errorf("claim from before the bump err = %v, want ErrStaleVersion", err)
+	}
+}
+
+func TestClaimEditVersionUnknownEntity(t *testing.T) {
+	cleanup := setupEditVersionTestDB(t)
+	defer cleanup()
+
+	err := claimEditVersion(DB, models.AuditEntityUser, 1, 1)
+	if err == nil || errors.Is(err, ErrStaleVersion) {
+		t.Errorf("err = %v, want an unversioned-entity error", err)
+	}
+	if _, err := GetEditVersion("products; DROP TABLE products", 1); err == nil {
+		t.Error("expected GetEditVersion to reject an unknown entity type")
+	}
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// GetTransporterGSTIN returns the GSTIN recorded for the project's transporter of
+// this name, or "" when the transporter is not on file. DCs store the transporter
+// by name, so the lookup ignores case and surrounding spaces.
+// Hand-written SQL: no sqlc query matches transporters by name.
+func GetTransporterGSTIN(projectID int, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil
+	}
+	var gstin sql.NullString
+	err := DB.QueryRowContext(ctx(),
+		`SELECT gst_number FROM transporters
+		  WHERE project_id = ? AND TRIM(company_name) = ? COLLATE NOCASE
+		  ORDER BY is_active DESC, id DESC
+		  LIMIT 1`,
+		projectID, name,
+	).Scan(&gstin)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("GetTransporterGSTIN: %w", err)
+	}
+	return strings.TrimSpace(gstin.String), nil
+}
+
+// ErrEwayBillRecorded is returned when a DC already carries an e-way bill
+// number; a recorded number is changed by amending the DC.
+var ErrEwayBillRecorded = errors.New("DC already has an e-way bill number")
+
+// SetEwayBillNumber records the e-way bill number generated on the portal for a
+// transit or transfer DC that has none yet (ErrEwayBillRecorded otherwise).
+// Transit DCs keep it in dc_transit_details, transfer DCs in transfer_dcs.
+// Hand-written SQL: the sqlc updates rewrite every transit field.
+func SetEwayBillNumber(dc *models.DeliveryChallan, number string) error {
+	var res sql.Result
+	var err error
+	switch dc.DCType {
+	case "transit":
+		res, err = DB.ExecContext(ctx(),
+			`INSERT INTO dc_transit_details (dc_id, eway_bill_number) VALUES (?, ?)
+			 ON CONFLICT(dc_id) DO UPDATE SET eway_bill_number = excluded.eway_bill_number
+			 WHERE COALESCE(dc_transit_details.eway_bill_number, '') = ''`,
+			dc.ID, nullStringFromStr(number),
+		)
+	case "transfer":
+		res, err = DB.ExecContext(ctx(),
+			`UPDATE transfer_dcs SET eway_bill_number = ?, updated_at = CURRENT_TIMESTAMP
+			  WHERE dc_id = ? AND COALESCE(eway_bill_number, '') = ''`,
+			nullStringFromStr(number), dc.ID,
+		)
+	default:
+		return fmt.Errorf("SetEwayBillNumber: %s DCs do not carry an e-way bill", dc.DCType)
+	}
+	if err != nil {
+		return fmt.Errorf("SetEwayBillNumber: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("SetEwayBillNumber: %w", err)
+	} else if n == 0 {
+		return ErrEwayBillRecorded
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// GetDCDateSettings returns a project's closed financial years and whether it
+// enforces challan date order.
+// Hand-written SQL: the financial year queries are not part of the sqlc queries.
+func GetDCDateSettings(projectID int) (*models.DCDateSettings, error) {
+	s := &models.DCDateSettings{ProjectID: projectID}
+	if err := DB.QueryRowContext(ctx(),
+		`SELECT enforce_date_order FROM projects WHERE id = ?`, projectID,
+	).Scan(&s.EnforceDateOrder); err != nil {
+		return nil, fmt.Errorf("GetDCDateSettings: %w", err)
+	}
+
+	rows, err := DB.QueryContext(ctx(), `
+		SELECT c.id, c.project_id, c.financial_year, c.closed_by, c.closed_at, COALESCE(u.full_name, '')
+		FROM financial_year_closures c
+		LEFT JOIN users u ON u.id = c.closed_by
+		WHERE c.project_id = ?
+		ORDER BY c.financial_year`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("GetDCDateSettings closures: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c models.FinancialYearClosure
+		if err := rows.Scan(&c.ID, &c.ProjectID, &c.FinancialYear, &c.ClosedBy, &c.ClosedAt, &c.ClosedByName); err != nil {
+			return nil, fmt.Errorf("GetDCDateSettings scan: %w", err)
+		}
+		s.ClosedYears = append(s.ClosedYears, c)
+	}
+	return s, rows.Err()
+}
+
+// SetEnforceDateOrder turns the challan date order rule on or off for a project.
+func SetEnforceDateOrder(projectID int, enforce bool) error {
+	if _, err := DB.ExecContext(ctx(),
+		`UPDATE projects SET enforce_date_order = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		enforce, projectID,
+	); err != nil {
+		return fmt.Errorf("SetEnforceDateOrder: %w", err)
+	}
+	return nil
+}
+
+// CloseFinancialYear closes a financial year of a project against new DCs and
+// edits. Closing a year that is already closed is a no-op.
+func CloseFinancialYear(projectID int, fy string, userID int) error {
+	if _, err := DB.ExecContext(ctx(),
+		`INSERT OR IGNORE INTO financial_year_closures (project_id, financial_year, closed_by) VALUES (?, ?, ?)`,
+		projectID, fy, userID,
+	); err != nil {
+		return fmt.Errorf("CloseFinancialYear: %w", err)
+	}
+	return nil
+}
+
+// ReopenFinancialYear reopens a closed financial year of a project.
+func ReopenFinancialYear(projectID int, fy string) error {
+	if _, err := DB.ExecContext(ctx(),
+		`DELETE FROM financial_year_closures WHERE project_id = ? AND financial_year = ?`,
+		projectID, fy,
+	); err != nil {
+		return fmt.Errorf("ReopenFinancialYear: %w", err)
+	}
+	return nil
+}
+
+// ListDCFinancialYears returns the financial years in which a project has
+// numbered DCs, plus any closed years and the extra years given, newest first.
+func ListDCFinancialYears(projectID int, extra ...string) ([]string, error) {
+	rows, err := DB.QueryContext(ctx(), `
+		SELECT financial_year FROM dc_number_sequences WHERE project_id = ?
+		UNION
+		SELECT financial_year FROM financial_year_closures WHERE project_id = ?`,
+		projectID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("ListDCFinancialYears: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	var years []string
+	for rows.Next() {
+		var fy string
+		if err := rows.Scan(&fy); err != nil {
+			return nil, fmt.Errorf("ListDCFinancialYears scan: %w", err)
+		}
+		seen[fy] = true
+		years = append(years, fy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListDCFinancialYears: %w", err)
+	}
+	for _, fy := range extra {
+		if !seen[fy] {
+			seen[fy] = true
+			years = append(years, fy)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(years)))
+	return years, nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupFinancialYearTestDB(t *testing.T) func() {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:financial_year_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	stmts := []string{
+		`CREATE TABLE projects (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			enforce_date_order INTEGER NOT NULL DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, username TEXT NOT NULL, full_name TEXT DEFAULT '')`,
+		`CREATE TABLE dc_number_sequences (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project_id INTEGER NOT NULL,
+			dc_type TEXT NOT NULL,
+			financial_year TEXT NOT NULL,
+			next_sequence INTEGER NOT NULL DEFAULT 1,
+			UNIQUE (project_id, dc_type, financial_year)
+		)`,
+		`CREATE TABLE financial_year_closures (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project_id INTEGER NOT NULL,
+			financial_year TEXT NOT NULL,
+			closed_by INTEGER,
+			closed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(project_id, financial_year)
+		)`,
+		`INSERT INTO projects (id, name) VALUES (1, 'Alpha'), (2, 'Beta')`,
+		`INSERT INTO users (id, username, full_name) VALUES (1, 'admin', 'Asha Rao')`,
+		`INSERT INTO dc_number_sequences (project_id, dc_type, financial_year, next_sequence) VALUES
+			(1, 'transit', '2425', 40), (1, 'official', '2425', 90), (1, 'transit', '2526', 3), (2, 'transit', '2324', 5)`,
+	}
+	for _, s := range stmts {
+		if _, err := db.Exec(s); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+
+	DB = db
+	return func() { db.Close() }
+}
+
+func TestFinancialYearClosures(t *testing.T) {
+	defer setupFinancialYearTestDB(t)()
+
+	if err := CloseFinancialYear(1, "2425", 1); err != nil {
+		t.Fatalf("CloseFinancialYear: %v", err)
+	}
+	// Closing twice keeps the first closure.
+	if err := CloseFinancialYear(1, "2425", 1); err != nil {
+		t.Fatalf("CloseFinancialYear again: %v", err)
+	}
+	if err := SetEnforceDateOrder(1, true); err != nil {
+		t.Fatalf("SetEnforceDateOrder: %v", err)
+	}
+
+	s, err := GetDCDateSettings(1)
+	if err != nil {
+		t.Fatalf("GetDCDateSettings: %v", err)
+	}
+	if !s.EnforceDateOrder {
+		t.Error("EnforceDateOrder = false; want true")
+	}
+	if len(s.ClosedYears) != 1 {
+		t.Fatalf("ClosedYears = %+v; want one", s.ClosedYears)
+	}
+	if c := s.Closure("2425"); c == nil || c.ClosedByName != "Asha Rao" {
+		t.Errorf("Closure(2425) = %+v; want closed by Asha Rao", c)
+	}
+	if s.Closure("2526") != nil {
+		t.Error("2526 reported closed")
+	}
+
+	years, err := ListDCFinancialYears(1, "2627", "2526")
+	if err != nil {
+		t.Fatalf("ListDCFinancialYears: %v", err)
+	}
+	if want := []string{"2627", "2526", "2425"}; !reflect.DeepEqual(years, want) {
+		t.Errorf("ListDCFinancialYears = %v; want %v", years, want)
+	}
+
+	if err := ReopenFinancialYear(1, "2425"); err != nil {
+		t.Fatalf("ReopenFinancialYear: %v", err)
+	}
+	s, _ = GetDCDateSettings(1)
+	if len(s.ClosedYears) != 0 {
+		t.Errorf("ClosedYears after reopen = %+v; want none", s.ClosedYears)
+	}
+}
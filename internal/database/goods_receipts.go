@@ -0,0 +1,240 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	db "github.com/narendhupati/dc-management-tool/internal/database/sqlc"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// DiscrepancyReportRow is one DC whose received quantity differs from what was dispatched.
+type DiscrepancyReportRow struct {
+	DCID           int
+	DCNumber       string
+	ChallanDate    string
+	District       string
+	Mandal         string
+	ReceivedDate   string
+	Dispatched     int
+	Received       int
+	Short          int
+	Damaged        int
+	DamagedSerials int
+	MissingSerials int
+}
+
+// NewGoodsReceiptLines returns one receipt line per DC line item, with every
+// dispatched unit counted as received.
+func NewGoodsReceiptLines(dcID int) ([]models.GoodsReceiptLine, error) {
+	items, err := GetLineItemsByDCID(dcID)
+	if err != nil {
+		return nil, fmt.Errorf("NewGoodsReceiptLines: %w", err)
+	}
+	lines := make([]models.GoodsReceiptLine, 0, len(items))
+	for _, li := range items { //nolint:gocritic
+		serials, err := GetSerialNumbersByLineItemID(li.ID)
+		if err != nil {
+			return nil, fmt.Errorf("NewGoodsReceiptLines serials: %w", err)
+		}
+		lines = append(lines, models.GoodsReceiptLine{
+			LineItemID:        li.ID,
+			Received:          li.Quantity,
+			ItemName:          li.ItemName,
+			Dispatched:        li.Quantity,
+			DispatchedSerials: serials,
+		})
+	}
+	return lines, nil
+}
+
+// GetGoodsReceipt returns the goods receipt recorded against a DC, or nil if none.
+// Hand-written SQL: the receipt tables are not part of the sqlc queries.
+func GetGoodsReceipt(dcID int) (*models.GoodsReceipt, error) {
+	r := &models.GoodsReceipt{}
+	var recordedBy sql.NullInt64
+	var recordedAt, updatedAt sql.NullTime
+	err := DB.QueryRowContext(ctx(),
+		`SELECT r.id, r.dc_id, COALESCE(r.received_date, ''), r.remarks, r.recorded_by,
+		        COALESCE(NULLIF(u.full_name, ''), u.username, ''), r.recorded_at, r.updated_at
+		   FROM dc_receipts r
+		   LEFT JOIN users u ON u.id = r.recorded_by
+		  WHERE r.dc_id = ?`, dcID,
+	).Scan(&r.ID, &r.DCID, &r.ReceivedDate, &r.Remarks, &recordedBy, &r.RecordedByName, &recordedAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetGoodsReceipt: %w", err)
+	}
+	if recordedBy.Valid {
+		v := int(recordedBy.Int64)
+		r.RecordedBy = &v
+	}
+	if recordedAt.Valid {
+		r.RecordedAt = recordedAt.Time
+	}
+	if updatedAt.Valid {
+		r.UpdatedAt = updatedAt.Time
+	}
+
+	lines, err := NewGoodsReceiptLines(dcID)
+	if err != nil {
+		return nil, err
+	}
+	byLine := make(map[int]*models.GoodsReceiptLine, len(lines))
+	for i := range lines {
+		byLine[lines[i].LineItemID] = &lines[i]
+	}
+
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT line_item_id, received_qty, short_qty, damaged_qty FROM dc_receipt_lines WHERE receipt_id = ?`, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("GetGoodsReceipt lines: %w", err)
+	}
+	for rows.Next() {
+		var lineItemID, received, short, damaged int
+		if err := rows.Scan(&lineItemID, &received, &short, &damaged); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("GetGoodsReceipt lines scan: %w", err)
+		}
+		if l := byLine[lineItemID]; l != nil {
+			l.Received, l.Short, l.Damaged = received, short, damaged
+		}
+	}
+	rows.Close()
+
+	rows, err = DB.QueryContext(ctx(),
+		`SELECT line_item_id, serial_number, condition FROM dc_receipt_serials WHERE receipt_id = ? ORDER BY id`, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("GetGoodsReceipt serials: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var lineItemID int
+		var serial, condition string
+		if err := rows.Scan(&lineItemID, &serial, &condition); err != nil {
+			return nil, fmt.Errorf("GetGoodsReceipt serials scan: %w", err)
+		}
+		l := byLine[lineItemID]
+		if l == nil {
+			continue
+		}
+		if condition == models.SerialConditionDamaged {
+			l.DamagedSerials = append(l.DamagedSerials, serial)
+		} else {
+			l.MissingSerials = append(l.MissingSerials, serial)
+		}
+	}
+	r.Lines = lines
+	return r, rows.Err()
+}
+
+// SaveGoodsReceipt records the goods receipt of a DC, replacing any earlier one.
+func SaveGoodsReceipt(r *models.GoodsReceipt) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx(),
+		`INSERT INTO dc_receipts (dc_id, received_date, remarks, recorded_by) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(dc_id) DO UPDATE SET received_date = excluded.received_date, remarks = excluded.remarks,
+		     recorded_by = excluded.recorded_by, updated_at = CURRENT_TIMESTAMP`,
+		r.DCID, r.ReceivedDate, r.Remarks, nullInt64FromPtr(r.RecordedBy),
+	); err != nil {
+		return fmt.Errorf("SaveGoodsReceipt: %w", err)
+	}
+	if err := tx.QueryRowContext(ctx(), `SELECT id FROM dc_receipts WHERE dc_id = ?`, r.DCID).Scan(&r.ID); err != nil {
+		return fmt.Errorf("SaveGoodsReceipt id: %w", err)
+	}
+
+	for _, stmt := range []string{
+		`DELETE FROM dc_receipt_lines WHERE receipt_id = ?`,
+		`DELETE FROM dc_receipt_serials WHERE receipt_id = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx(), stmt, r.ID); err != nil {
+			return fmt.Errorf("SaveGoodsReceipt clear: %w", err)
+		}
+	}
+
+	for i := range r.Lines {
+		l := &r.Lines[i]
+		if _, err := tx.ExecContext(ctx(),
+			`INSERT INTO dc_receipt_lines (receipt_id, line_item_id, received_qty, short_qty, damaged_qty) VALUES (?, ?, ?, ?, ?)`,
+			r.ID, l.LineItemID, l.Received, l.Short, l.Damaged,
+		); err != nil {
+			return fmt.Errorf("SaveGoodsReceipt line %d: %w", l.LineItemID, err)
+		}
+		if err := insertReceiptSerials(tx, r.ID, l.LineItemID, models.SerialConditionDamaged, l.DamagedSerials); err != nil {
+			return err
+		}
+		if err := insertReceiptSerials(tx, r.ID, l.LineItemID, models.SerialConditionMissing, l.MissingSerials); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertReceiptSerials records the serials of a receipt line reported in the given condition.
+func insertReceiptSerials(q db.DBTX, receiptID, lineItemID int, condition string, serials []string) error {
+	for _, sn := range serials {
+		if _, err := q.ExecContext(ctx(),
+			`INSERT INTO dc_receipt_serials (receipt_id, line_item_id, serial_number, condition) VALUES (?, ?, ?, ?)`,
+			receiptID, lineItemID, sn, condition,
+		); err != nil {
+			return fmt.Errorf("SaveGoodsReceipt serial %s: %w", sn, err)
+		}
+	}
+	return nil
+}
+
+// GetDiscrepancyReport lists the DCs of a project whose goods receipt shows fewer
+// units received than dispatched, newest challan first.
+func GetDiscrepancyReport(projectID int, startDate, endDate *time.Time) ([]DiscrepancyReportRow, error) {
+	args := []interface{}{projectID}
+	dateClause, args := dateFilterSQL(startDate, endDate, args)
+
+	rows, err := DB.QueryContext(ctx(), `
+		SELECT
+			dc.id,
+			dc.dc_number,
+			COALESCE(dc.challan_date, ''),
+			COALESCE(a.district_name, ''),
+			COALESCE(a.mandal_name, ''),
+			COALESCE(r.received_date, ''),
+			SUM(li.quantity),
+			SUM(rl.received_qty),
+			SUM(rl.short_qty),
+			SUM(rl.damaged_qty),
+			(SELECT COUNT(*) FROM dc_receipt_serials rs WHERE rs.receipt_id = r.id AND rs.condition = 'damaged'),
+			(SELECT COUNT(*) FROM dc_receipt_serials rs WHERE rs.receipt_id = r.id AND rs.condition = 'missing')
+		FROM dc_receipts r
+		INNER JOIN delivery_challans dc ON dc.id = r.dc_id
+		INNER JOIN dc_receipt_lines rl ON rl.receipt_id = r.id
+		INNER JOIN dc_line_items li ON li.id = rl.line_item_id
+		LEFT JOIN addresses a ON a.id = dc.ship_to_address_id
+		WHERE dc.project_id = ? AND dc.status != 'cancelled'`+dateClause+`
+		GROUP BY r.id
+		HAVING SUM(rl.received_qty) <> SUM(li.quantity)
+		ORDER BY dc.challan_date DESC, dc.dc_number
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("GetDiscrepancyReport: %w", err)
+	}
+	defer rows.Close()
+
+	var results []DiscrepancyReportRow
+	for rows.Next() {
+		var r DiscrepancyReportRow
+		if err := rows.Scan(&r.DCID, &r.DCNumber, &r.ChallanDate, &r.District, &r.Mandal, &r.ReceivedDate,
+			&r.Dispatched, &r.Received, &r.Short, &r.Damaged, &r.DamagedSerials, &r.MissingSerials); err != nil {
+			return nil, fmt.Errorf("GetDiscrepancyReport scan: %w", err)
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
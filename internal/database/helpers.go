@@ -10,6 +10,11 @@ func ctx() context.Context {
 	return context.Background()
 }
 
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
 // nullStringFromStr converts a string to sql.NullString (valid when non-empty).
 func nullStringFromStr(s string) sql.NullString {
 	if s == "" {
@@ -38,3 +43,12 @@ func nullInt64FromPtr(p *int) sql.NullInt64 {
 	}
 	return sql.NullInt64{Int64: int64(*p), Valid: true}
 }
+
+// intPtrFromNullInt64 converts a sql.NullInt64 to *int (nil when NULL).
+func intPtrFromNullInt64(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
@@ -0,0 +1,288 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	db "github.com/narendhupati/dc-management-tool/internal/database/sqlc"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// loadKitComponents fills in the bills of materials of products that are kits.
+// Hand-written SQL: product_kit_components is not part of the sqlc queries.
+func loadKitComponents(products ...*models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	byID := make(map[int]*models.Product, len(products))
+	placeholders := make([]string, 0, len(products))
+	args := make([]interface{}, 0, len(products))
+	for _, p := range products {
+		p.Components = nil
+		byID[p.ID] = p
+		placeholders = append(placeholders, "?")
+		args = append(args, p.ID)
+	}
+	components, err := queryKitComponents(
+		`k.kit_product_id IN (`+strings.Join(placeholders, ", ")+`)`, args...)
+	if err != nil {
+		return fmt.Errorf("loadKitComponents: %w", err)
+	}
+	for kitID, comps := range components {
+		if p, ok := byID[kitID]; ok {
+			p.Components = comps
+		}
+	}
+	return nil
+}
+
+// queryKitComponents reads the kit components matching where, keyed by kit
+// product ID, in each kit's line order.
+func queryKitComponents(where string, args ...interface{}) (map[int][]models.KitComponent, error) {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT k.kit_product_id, k.component_product_id, p.item_name, p.brand_model, k.quantity,
+		        p.serial_pattern, p.serial_min_length, p.serial_max_length, p.serial_prefix, p.serial_luhn
+		 FROM product_kit_components k
+		 INNER JOIN products p ON p.id = k.component_product_id
+		 WHERE `+where+`
+		 ORDER BY k.kit_product_id, k.line_order, k.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[int][]models.KitComponent)
+	for rows.Next() {
+		var kitID int
+		var c models.KitComponent
+		r := &c.SerialRule
+		if err := rows.Scan(&kitID, &c.ProductID, &c.ItemName, &c.BrandModel, &c.Quantity,
+			&r.Pattern, &r.MinLength, &r.MaxLength, &r.Prefix, &r.Luhn); err != nil {
+			return nil, err
+		}
+		out[kitID] = append(out[kitID], c)
+	}
+	return out, rows.Err()
+}
+
+// ErrKitComponentsLocked is returned when the components of a kit that is
+// already on a DC are changed: the DC was made out for the kit as it was.
+var ErrKitComponentsLocked = errors.New("kit components cannot change once the product is on a DC")
+
+// setKitComponents replaces a product's bill of materials; no components
+// makes it a plain product again. A product already on a DC keeps its
+// components (ErrKitComponentsLocked).
+// Hand-written SQL: product_kit_components is not part of the sqlc queries.
+func setKitComponents(q db.DBTX, kitID int, components []models.KitComponent) error {
+	current, err := kitComponentList(q, kitID)
+	if err != nil {
+		return fmt.Errorf("setKitComponents: %w", err)
+	}
+	if sameKitComponents(current, components) {
+		return nil
+	}
+	var onDCs int
+	if err := q.QueryRowContext(ctx(),
+		`SELECT COUNT(*) FROM dc_line_items WHERE product_id = ?`, kitID,
+	).Scan(&onDCs); err != nil {
+		return fmt.Errorf("setKitComponents: %w", err)
+	}
+	if onDCs > 0 {
+		return ErrKitComponentsLocked
+	}
+
+	if _, err := q.ExecContext(ctx(), `DELETE FROM product_kit_components WHERE kit_product_id = ?`, kitID); err != nil {
+		return fmt.Errorf("setKitComponents: %w", err)
+	}
+	for i, c := range components {
+		if _, err := q.ExecContext(ctx(),
+			`INSERT INTO product_kit_components (kit_product_id, component_product_id, quantity, line_order)
+			 VALUES (?, ?, ?, ?)`,
+			kitID, c.ProductID, c.Quantity, i+1); err != nil {
+			return fmt.Errorf("setKitComponents: %w", err)
+		}
+	}
+	return nil
+}
+
+// kitComponentList reads a kit's component products and quantities, in line order.
+func kitComponentList(q db.DBTX, kitID int) ([]models.KitComponent, error) {
+	rows, err := q.QueryContext(ctx(),
+		`SELECT component_product_id, quantity FROM product_kit_components
+		 WHERE kit_product_id = ? ORDER BY line_order, id`, kitID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.KitComponent
+	for rows.Next() {
+		var c models.KitComponent
+		if err := rows.Scan(&c.ProductID, &c.Quantity); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// sameKitComponents reports whether two bills of materials list the same
+// products in the same quantities and order.
+func sameKitComponents(a, b []models.KitComponent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ProductID != b[i].ProductID || a[i].Quantity != b[i].Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// IsKitComponent reports whether a product is a component of any kit.
+func IsKitComponent(productID int) (bool, error) {
+	var n int
+	if err := DB.QueryRowContext(ctx(),
+		`SELECT COUNT(*) FROM product_kit_components WHERE component_product_id = ?`, productID,
+	).Scan(&n); err != nil {
+		return false, fmt.Errorf("IsKitComponent: %w", err)
+	}
+	return n > 0, nil
+}
+
+// loadLineItemKits fills in the components of kit line items, each with the
+// serials captured for it on the line. A DC with a snapshot keeps the
+// components it was issued with; drafts, lines added since and DCs issued
+// before components were recorded use the products' current kits.
+// Hand-written SQL: serial_numbers.product_id tells a kit line's components apart.
+func loadLineItemKits(items []models.DCLineItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	issued := make(map[int]*models.DCSnapshot)
+	for _, li := range items {
+		if _, ok := issued[li.DCID]; ok {
+			continue
+		}
+		snap, err := GetDCSnapshot(li.DCID)
+		if err != nil {
+			return fmt.Errorf("loadLineItemKits: %w", err)
+		}
+		if snap != nil && snap.KitComponents == nil {
+			snap = nil
+		}
+		issued[li.DCID] = snap
+	}
+	frozen := func(li models.DCLineItem) ([]models.KitComponent, bool) {
+		snap := issued[li.DCID]
+		if snap == nil {
+			return nil, false
+		}
+		if _, ok := snap.Products[li.ProductID]; !ok {
+			return nil, false
+		}
+		return snap.KitComponents[li.ProductID], true
+	}
+
+	placeholders := make([]string, 0, len(items))
+	args := make([]interface{}, 0, len(items))
+	for _, li := range items {
+		if _, ok := frozen(li); !ok {
+			placeholders = append(placeholders, "?")
+			args = append(args, li.ProductID)
+		}
+	}
+	components := map[int][]models.KitComponent{}
+	if len(args) > 0 {
+		var err error
+		components, err = queryKitComponents(
+			`k.kit_product_id IN (`+strings.Join(placeholders, ", ")+`)`, args...)
+		if err != nil {
+			return fmt.Errorf("loadLineItemKits: %w", err)
+		}
+	}
+	for i := range items {
+		comps, ok := frozen(items[i])
+		if !ok {
+			comps = components[items[i].ProductID]
+		}
+		if len(comps) == 0 {
+			continue
+		}
+		items[i].KitComponents = make([]models.KitComponent, len(comps))
+		copy(items[i].KitComponents, comps)
+		serials, err := componentSerialsByLineItem(items[i].ID)
+		if err != nil {
+			return fmt.Errorf("loadLineItemKits: %w", err)
+		}
+		for j := range items[i].KitComponents {
+			items[i].KitComponents[j].SerialNumbers = serials[items[i].KitComponents[j].ProductID]
+		}
+	}
+	return nil
+}
+
+// componentSerialsByLineItem returns a line item's unreleased serials keyed by
+// the product they were captured for.
+func componentSerialsByLineItem(lineItemID int) (map[int][]string, error) {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT COALESCE(product_id, 0), serial_number FROM serial_numbers
+		 WHERE line_item_id = ? AND released_at IS NULL
+		 ORDER BY id`, lineItemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[int][]string)
+	for rows.Next() {
+		var productID int
+		var sn string
+		if err := rows.Scan(&productID, &sn); err != nil {
+			return nil, err
+		}
+		out[productID] = append(out[productID], sn)
+	}
+	return out, rows.Err()
+}
+
+// InsertKitComponentSerials adds the serials of one component to the line item
+// of a kit on a DC.
+func InsertKitComponentSerials(dcID, projectID, kitProductID, componentProductID int, serials []string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("InsertKitComponentSerials: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := insertKitComponentSerials(tx, dcID, projectID, kitProductID, componentProductID, serials); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertKitComponentSerials is InsertKitComponentSerials against q.
+func insertKitComponentSerials(q db.DBTX, dcID, projectID, kitProductID, componentProductID int, serials []string) error {
+	if len(serials) == 0 {
+		return nil
+	}
+	var lineItemID int
+	if err := q.QueryRowContext(ctx(),
+		`SELECT id FROM dc_line_items WHERE dc_id = ? AND product_id = ? ORDER BY line_order, id LIMIT 1`,
+		dcID, kitProductID,
+	).Scan(&lineItemID); err != nil {
+		return fmt.Errorf("InsertKitComponentSerials: %w", err)
+	}
+
+	for _, sn := range serials {
+		sn = strings.TrimSpace(sn)
+		if sn == "" {
+			continue
+		}
+		if _, err := q.ExecContext(ctx(),
+			`INSERT INTO serial_numbers (project_id, line_item_id, serial_number, product_id) VALUES (?, ?, ?, ?)`,
+			projectID, lineItemID, sn, componentProductID); err != nil {
+			return fmt.Errorf("InsertKitComponentSerials %q: %w", sn, err)
+		}
+	}
+	return nil
+}
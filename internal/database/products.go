@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	db "github.com/narendhupati/dc-management-tool/internal/database/sqlc"
 	"github.com/narendhupati/dc-management-tool/internal/models"
@@ -44,6 +45,9 @@ func GetProductsByProjectID(projectID int) ([]*models.Product, error) {
 			UpdatedAt: row.UpdatedAt, ProductCode: row.ProductCode,
 		}))
 	}
+	if err := loadProductRules(products...); err != nil {
+		return nil, err
+	}
 	return products, nil
 }
 
@@ -56,18 +60,28 @@ func GetProductByID(id int) (*models.Product, error) {
 	if err != nil {
 		return nil, err
 	}
-	return productFromRow(db.Product{
+	p := productFromRow(db.Product{
 		ID: row.ID, ProjectID: row.ProjectID, ItemName: row.ItemName,
 		ItemDescription: row.ItemDescription, HsnCode: row.HsnCode, Uom: row.Uom,
 		BrandModel: row.BrandModel, PerUnitPrice: row.PerUnitPrice,
 		GstPercentage: row.GstPercentage, CreatedAt: row.CreatedAt,
 		UpdatedAt: row.UpdatedAt, ProductCode: row.ProductCode,
-	}), nil
+	})
+	if err := loadProductRules(p); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
+// CreateProductRecord saves a new product with its rules and kit components.
 func CreateProductRecord(p *models.Product) error {
-	q := db.New(DB)
-	result, err := q.CreateProduct(context.Background(), db.CreateProductParams{
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := db.New(tx).CreateProduct(context.Background(), db.CreateProductParams{
 		ProjectID:       int64(p.ProjectID),
 		ItemName:        p.ItemName,
 		ItemDescription: p.ItemDescription,
@@ -86,12 +100,40 @@ func CreateProductRecord(p *models.Product) error {
 		return err
 	}
 	p.ID = int(id)
-	return nil
+	if err := saveProductRules(tx, p); err != nil {
+		return err
+	}
+	if err := setKitComponents(tx, p.ID, p.Components); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func UpdateProductRecord(p *models.Product) error {
-	q := db.New(DB)
-	return q.UpdateProduct(context.Background(), db.UpdateProductParams{
+// UpdateProductRecord saves a product's fields, rules and kit components,
+// provided it is still at the edit version the form was opened with
+// (ErrStaleVersion otherwise). The components of a product already on a DC
+// cannot change (ErrKitComponentsLocked).
+func UpdateProductRecord(p *models.Product, version int) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := updateProductRecord(tx, p, version); err != nil {
+		return err
+	}
+	if err := setKitComponents(tx, p.ID, p.Components); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func updateProductRecord(q db.DBTX, p *models.Product, version int) error {
+	if err := claimEditVersion(q, models.AuditEntityProduct, p.ID, version); err != nil {
+		return err
+	}
+	err := db.New(q).UpdateProduct(context.Background(), db.UpdateProductParams{
 		ItemName:        p.ItemName,
 		ItemDescription: p.ItemDescription,
 		HsnCode:         sql.NullString{String: p.HSNCode, Valid: p.HSNCode != ""},
@@ -102,6 +144,10 @@ func UpdateProductRecord(p *models.Product) error {
 		ID:              int64(p.ID),
 		ProjectID:       int64(p.ProjectID),
 	})
+	if err != nil {
+		return err
+	}
+	return saveProductRules(q, p)
 }
 
 func DeleteProductRecord(id, projectID int) error {
@@ -112,6 +158,13 @@ func DeleteProductRecord(id, projectID int) error {
 	if used {
 		return fmt.Errorf("cannot delete product: it is used in DC templates")
 	}
+	component, err := IsKitComponent(id)
+	if err != nil {
+		return err
+	}
+	if component {
+		return fmt.Errorf("cannot delete product: it is a component of a kit")
+	}
 
 	q := db.New(DB)
 	return q.DeleteProduct(context.Background(), db.DeleteProductParams{
@@ -401,3 +454,58 @@ func CheckProductCodeUnique(productCode string, excludeID int) (bool, error) {
 	}
 	return count == 0, nil
 }
+
+// loadProductRules fills in the serial rules, warranty periods and kit
+// components of products.
+// Hand-written SQL: the serial rule and warranty columns are not part of the sqlc queries.
+func loadProductRules(products ...*models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	byID := make(map[int]*models.Product, len(products))
+	placeholders := make([]string, 0, len(products))
+	args := make([]interface{}, 0, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+		placeholders = append(placeholders, "?")
+		args = append(args, p.ID)
+	}
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT id, serial_pattern, serial_min_length, serial_max_length, serial_prefix, serial_luhn, warranty_months
+		 FROM products WHERE id IN (`+strings.Join(placeholders, ", ")+`)`, args...)
+	if err != nil {
+		return fmt.Errorf("loadProductRules: %w", err)
+	}
+	for rows.Next() {
+		var id int
+		var r models.SerialRule
+		var warranty int
+		if err := rows.Scan(&id, &r.Pattern, &r.MinLength, &r.MaxLength, &r.Prefix, &r.Luhn, &warranty); err != nil {
+			rows.Close()
+			return fmt.Errorf("loadProductRules: %w", err)
+		}
+		if p, ok := byID[id]; ok {
+			p.SerialRule = r
+			p.WarrantyMonths = warranty
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loadProductRules: %w", err)
+	}
+	return loadKitComponents(products...)
+}
+
+// saveProductRules stores a product's serial rule and warranty period.
+func saveProductRules(q db.DBTX, p *models.Product) error {
+	r := p.SerialRule
+	_, err := q.ExecContext(ctx(),
+		`UPDATE products SET serial_pattern = ?, serial_min_length = ?, serial_max_length = ?,
+		     serial_prefix = ?, serial_luhn = ?, warranty_months = ?
+		 WHERE id = ?`,
+		r.Pattern, r.MinLength, r.MaxLength, r.Prefix, r.Luhn, p.WarrantyMonths, p.ID)
+	if err != nil {
+		return fmt.Errorf("saveProductRules: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// issuedStatusesSQL matches DCs that have been issued, including those since delivered.
+const issuedStatusesSQL = "('issued', 'delivered', 'partially_delivered')"
+
+// RecordProofOfDelivery stores a POD and moves the DC to delivered, or to
+// partially_delivered for a partial POD. Only issued or partially delivered
+// official DCs accept a POD.
+// Hand-written SQL: dc_proofs_of_delivery is not part of the sqlc queries.
+func RecordProofOfDelivery(pod *models.ProofOfDelivery) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx(),
+		`UPDATE delivery_challans SET status = ?, updated_at = CURRENT_TIMESTAMP
+		  WHERE id = ? AND dc_type = 'official' AND status IN ('issued', 'partially_delivered')`,
+		pod.DeliveryStatus(), pod.DCID,
+	)
+	if err != nil {
+		return fmt.Errorf("RecordProofOfDelivery update status: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("DC is not awaiting delivery")
+	}
+
+	res, err = tx.ExecContext(ctx(),
+		`INSERT INTO dc_proofs_of_delivery
+		    (dc_id, received_date, receiver_name, receiver_designation, is_partial, remarks, file_path, recorded_by)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		pod.DCID, pod.ReceivedDate, pod.ReceiverName, pod.ReceiverDesignation,
+		pod.IsPartial, pod.Remarks, pod.FilePath, nullInt64FromPtr(pod.RecordedBy),
+	)
+	if err != nil {
+		return fmt.Errorf("RecordProofOfDelivery insert: %w", err)
+	}
+	id, _ := res.LastInsertId()
+	pod.ID = int(id)
+
+	return tx.Commit()
+}
+
+// GetProofsOfDelivery returns the PODs recorded against a DC, oldest first.
+func GetProofsOfDelivery(dcID int) ([]*models.ProofOfDelivery, error) {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT p.id, p.dc_id, COALESCE(p.received_date, ''), p.receiver_name, p.receiver_designation, p.is_partial,
+		        p.remarks, p.file_path, p.recorded_by, COALESCE(NULLIF(u.full_name, ''), u.username, ''), p.recorded_at
+		   FROM dc_proofs_of_delivery p
+		   LEFT JOIN users u ON u.id = p.recorded_by
+		  WHERE p.dc_id = ?
+		  ORDER BY p.id`, dcID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetProofsOfDelivery: %w", err)
+	}
+	defer rows.Close()
+
+	var pods []*models.ProofOfDelivery
+	for rows.Next() {
+		p := &models.ProofOfDelivery{}
+		var recordedBy sql.NullInt64
+		var recordedAt sql.NullTime
+		if err := rows.Scan(&p.ID, &p.DCID, &p.ReceivedDate, &p.ReceiverName, &p.ReceiverDesignation, &p.IsPartial,
+			&p.Remarks, &p.FilePath, &recordedBy, &p.RecordedByName, &recordedAt); err != nil {
+			return nil, fmt.Errorf("GetProofsOfDelivery scan: %w", err)
+		}
+		if recordedBy.Valid {
+			v := int(recordedBy.Int64)
+			p.RecordedBy = &v
+		}
+		if recordedAt.Valid {
+			p.RecordedAt = recordedAt.Time
+		}
+		pods = append(pods, p)
+	}
+	return pods, rows.Err()
+}
@@ -3,11 +3,14 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	db "github.com/narendhupati/dc-management-tool/internal/database/sqlc"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
 )
 
 // DCSummaryReport holds aggregate stats for the DC summary report.
@@ -20,6 +23,7 @@ type DCSummaryReport struct {
 	TransferIssuedDCs    int
 	TransferSplittingDCs int
 	TransferSplitDCs     int
+	CancelledDCs         int
 	TotalItemsDispatched int
 	TotalSerialsUsed     int
 }
@@ -31,7 +35,12 @@ type DestinationRow struct {
 	OfficialDCs int
 	TotalItems  int
 	DraftCount  int
-	IssuedCount int
+	IssuedCount int // includes delivered
+
+	// Proof-of-delivery breakdown of the issued official DCs
+	DeliveredCount          int
+	PartiallyDeliveredCount int
+	PendingCount            int // issued, no POD yet
 }
 
 // ProductReportRow holds one row of the product-wise report.
@@ -42,7 +51,14 @@ type ProductReportRow struct {
 	DestinationCount int
 }
 
-// SerialReportRow holds one row of the serial number report.
+// Serial movements shown in the serial number report.
+const (
+	SerialMovementOutbound = "outbound"
+	SerialMovementReturn   = "return"
+)
+
+// SerialReportRow holds one movement of a serial number: dispatched on a DC, or
+// taken back on a return DC.
 type SerialReportRow struct {
 	SerialNumber    string
 	ProductName     string
@@ -51,6 +67,7 @@ type SerialReportRow struct {
 	ChallanDate     string
 	VehicleNumber   string
 	ProjectID       int
+	Movement        string
 }
 
 // DestinationDCRow holds one DC for a destination drill-down.
@@ -132,7 +149,7 @@ func GetDCSummaryReport(projectID int, startDate, endDate *time.Time) (*DCSummar
 	args4 := []interface{}{projectID}
 	dateClause4, args4 := dateFilterSQL(startDate, endDate, args4)
 	err = DB.QueryRow(
-		"SELECT COUNT(*) FROM delivery_challans dc WHERE dc.project_id = ? AND dc.dc_type='official' AND dc.status='issued'"+dateClause4, args4...,
+		"SELECT COUNT(*) FROM delivery_challans dc WHERE dc.project_id = ? AND dc.dc_type='official' AND dc.status IN "+issuedStatusesSQL+dateClause4, args4...,
 	).Scan(&report.OfficialIssuedDCs)
 	if err != nil {
 		return nil, err
@@ -178,26 +195,36 @@ func GetDCSummaryReport(projectID int, startDate, endDate *time.Time) (*DCSummar
 		return nil, err
 	}
 
+	// Cancelled (all types)
+	args9 := []interface{}{projectID}
+	dateClause9, args9 := dateFilterSQL(startDate, endDate, args9)
+	err = DB.QueryRow(
+		"SELECT COUNT(*) FROM delivery_challans dc WHERE dc.project_id = ? AND dc.status='cancelled'"+dateClause9, args9...,
+	).Scan(&report.CancelledDCs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Total items dispatched (sum of quantities from issued DCs)
 	args5 := []interface{}{projectID}
 	dateClause5, args5 := dateFilterSQL(startDate, endDate, args5)
 	err = DB.QueryRow(
 		`SELECT COALESCE(SUM(li.quantity), 0) FROM dc_line_items li
 		 INNER JOIN delivery_challans dc ON li.dc_id = dc.id
-		 WHERE dc.project_id = ? AND dc.status='issued'`+dateClause5, args5...,
+		 WHERE dc.project_id = ? AND dc.status IN `+issuedStatusesSQL+dateClause5, args5...,
 	).Scan(&report.TotalItemsDispatched)
 	if err != nil {
 		return nil, err
 	}
 
-	// Total serial numbers used
+	// Total serial numbers used (serials on cancelled DCs have been released)
 	args6 := []interface{}{projectID}
 	dateClause6, args6 := dateFilterSQL(startDate, endDate, args6)
 	err = DB.QueryRow(
 		`SELECT COUNT(*) FROM serial_numbers sn
 		 INNER JOIN dc_line_items li ON sn.line_item_id = li.id
 		 INNER JOIN delivery_challans dc ON li.dc_id = dc.id
-		 WHERE dc.project_id = ?`+dateClause6, args6...,
+		 WHERE dc.project_id = ? AND dc.status != 'cancelled'`+dateClause6, args6...,
 	).Scan(&report.TotalSerialsUsed)
 	if err != nil {
 		return nil, err
@@ -218,7 +245,10 @@ func GetDestinationReport(projectID int, startDate, endDate *time.Time) ([]Desti
 			COUNT(CASE WHEN dc.dc_type='official' THEN 1 END) AS official_dcs,
 			COALESCE(SUM(li_counts.total_qty), 0) AS total_items,
 			COUNT(CASE WHEN dc.dc_type='official' AND dc.status='draft' THEN 1 END) AS draft_count,
-			COUNT(CASE WHEN dc.dc_type='official' AND dc.status='issued' THEN 1 END) AS issued_count
+			COUNT(CASE WHEN dc.dc_type='official' AND dc.status IN `+issuedStatusesSQL+` THEN 1 END) AS issued_count,
+			COUNT(CASE WHEN dc.dc_type='official' AND dc.status='delivered' THEN 1 END) AS delivered_count,
+			COUNT(CASE WHEN dc.dc_type='official' AND dc.status='partially_delivered' THEN 1 END) AS partially_delivered_count,
+			COUNT(CASE WHEN dc.dc_type='official' AND dc.status='issued' THEN 1 END) AS pending_count
 		FROM delivery_challans dc
 		LEFT JOIN addresses a ON dc.ship_to_address_id = a.id
 		LEFT JOIN (
@@ -236,7 +266,8 @@ func GetDestinationReport(projectID int, startDate, endDate *time.Time) ([]Desti
 	var results []DestinationRow
 	for rows.Next() {
 		var r DestinationRow
-		if err := rows.Scan(&r.District, &r.Mandal, &r.OfficialDCs, &r.TotalItems, &r.DraftCount, &r.IssuedCount); err != nil {
+		if err := rows.Scan(&r.District, &r.Mandal, &r.OfficialDCs, &r.TotalItems, &r.DraftCount, &r.IssuedCount,
+			&r.DeliveredCount, &r.PartiallyDeliveredCount, &r.PendingCount); err != nil {
 			return nil, err
 		}
 		results = append(results, r)
@@ -314,7 +345,9 @@ func GetProductReport(projectID int, startDate, endDate *time.Time) ([]ProductRe
 	return results, nil
 }
 
-// GetSerialReport returns serial number report rows with optional search.
+// GetSerialReport returns serial number report rows with optional search: one row
+// per DC that dispatched a serial and one per return DC that took it back, ordered
+// by serial and date.
 func GetSerialReport(projectID int, search string, startDate, endDate *time.Time) ([]SerialReportRow, error) {
 	args := []interface{}{projectID}
 	dateClause, args := dateFilterSQL(startDate, endDate, args)
@@ -337,6 +370,8 @@ func GetSerialReport(projectID int, search string, startDate, endDate *time.Time
 			searchClause = " AND (" + strings.Join(clauses, " OR ") + ")"
 		}
 	}
+	// The return movements take the same filters.
+	args = append(args, args...)
 
 	rows, err := DB.Query(`
 		SELECT
@@ -346,14 +381,30 @@ func GetSerialReport(projectID int, search string, startDate, endDate *time.Time
 			dc.id AS dc_id,
 			COALESCE(dc.challan_date, '') AS challan_date,
 			COALESCE(td.vehicle_number, '') AS vehicle_number,
-			dc.project_id
+			dc.project_id,
+			'`+SerialMovementOutbound+`' AS movement
 		FROM serial_numbers sn
 		INNER JOIN dc_line_items li ON sn.line_item_id = li.id
 		INNER JOIN delivery_challans dc ON li.dc_id = dc.id
 		LEFT JOIN products p ON li.product_id = p.id
 		LEFT JOIN dc_transit_details td ON td.dc_id = dc.id
 		WHERE dc.project_id = ?`+dateClause+searchClause+`
-		ORDER BY sn.serial_number
+		UNION ALL
+		SELECT
+			sn.serial_number,
+			COALESCE(p.item_name, 'Unknown'),
+			dc.dc_number,
+			dc.id,
+			COALESCE(dc.challan_date, ''),
+			'',
+			dc.project_id,
+			'`+SerialMovementReturn+`'
+		FROM return_dc_serials sn
+		INNER JOIN return_dcs r ON sn.return_dc_id = r.id
+		INNER JOIN delivery_challans dc ON r.dc_id = dc.id
+		LEFT JOIN products p ON sn.product_id = p.id
+		WHERE dc.project_id = ?`+dateClause+searchClause+`
+		ORDER BY serial_number, challan_date, movement
 		LIMIT 500
 	`, args...)
 	if err != nil {
@@ -364,7 +415,7 @@ func GetSerialReport(projectID int, search string, startDate, endDate *time.Time
 	var results []SerialReportRow
 	for rows.Next() {
 		var r SerialReportRow
-		if err := rows.Scan(&r.SerialNumber, &r.ProductName, &r.TransitDCNumber, &r.TransitDCID, &r.ChallanDate, &r.VehicleNumber, &r.ProjectID); err != nil {
+		if err := rows.Scan(&r.SerialNumber, &r.ProductName, &r.TransitDCNumber, &r.TransitDCID, &r.ChallanDate, &r.VehicleNumber, &r.ProjectID, &r.Movement); err != nil {
 			return nil, err
 		}
 		results = append(results, r)
@@ -420,6 +471,141 @@ func GetTransferDCReport(projectID int, startDate, endDate *time.Time) ([]Transf
 	return results, nil
 }
 
+// GetDocumentsIssuedReport returns the DC number series of a project for
+// GSTR-1 Table 13, with the first and last number of each series and counts of
+// issued, cancelled, unissued and deleted numbers, plus any DC numbers that
+// carry no sequence. All DC types are included, drafts too, so that numbers
+// taken by drafts are not mistaken for deleted ones.
+// Hand-written SQL: sqlc has no query across all DC types and statuses.
+func GetDocumentsIssuedReport(projectID int, startDate, endDate *time.Time) ([]services.DocumentSeries, []string, error) {
+	var format string
+	if err := DB.QueryRowContext(ctx(),
+		`SELECT COALESCE(dc_number_format, '') FROM projects WHERE id = ?`, projectID,
+	).Scan(&format); err != nil {
+		return nil, nil, fmt.Errorf("GetDocumentsIssuedReport project: %w", err)
+	}
+
+	args := []interface{}{projectID}
+	dateClause, args := dateFilterSQL(startDate, endDate, args)
+
+	rows, err := DB.QueryContext(ctx(), `
+		SELECT dc.dc_number, dc.dc_type, dc.status,
+			COALESCE(dc.challan_date, ''), COALESCE(dc.created_at, '')
+		FROM delivery_challans dc
+		WHERE dc.project_id = ?`+dateClause+`
+		ORDER BY dc.id
+	`, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetDocumentsIssuedReport: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []services.IssuedDocument
+	for rows.Next() {
+		var d services.IssuedDocument
+		var challanDate, createdAt string
+		if err := rows.Scan(&d.DCNumber, &d.DCType, &d.Status, &challanDate, &createdAt); err != nil {
+			return nil, nil, fmt.Errorf("GetDocumentsIssuedReport scan: %w", err)
+		}
+		d.ChallanDate = reportDate(challanDate)
+		if d.ChallanDate.IsZero() {
+			d.ChallanDate = reportDate(createdAt)
+		}
+		docs = append(docs, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("GetDocumentsIssuedReport: %w", err)
+	}
+
+	series, unparsed := services.SummariseDocumentSeries(docs, format)
+	return series, unparsed, nil
+}
+
+// reportDate parses the date part of a DATE or DATETIME column read as text.
+// Returns the zero time when the value is empty or unparseable.
+func reportDate(s string) time.Time {
+	if len(s) < 10 {
+		return time.Time{}
+	}
+	t, err := time.Parse("2006-01-02", s[:10])
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 // Ensure the sqlc package import is used — reference the context and db packages.
 var _ = context.Background
 var _ = toNullTime
+
+// GetHSNSummaryReport returns the taxable value and tax of issued transit and
+// transfer DCs grouped by HSN code, unit and GST rate. Transit DCs split from a
+// Transfer DC are left out, since their goods are already on the Transfer DC.
+// HSN codes and units come from each DC's issue-time snapshot when it has one.
+// Hand-written SQL: the report joins dc_snapshots, which sqlc does not cover.
+func GetHSNSummaryReport(projectID int, startDate, endDate *time.Time) ([]models.HSNSummaryRow, error) {
+	args := []interface{}{projectID}
+	dateClause, args := dateFilterSQL(startDate, endDate, args)
+
+	rows, err := DB.QueryContext(ctx(), `
+		SELECT
+			dc.id,
+			li.product_id,
+			COALESCE(p.item_name, ''),
+			COALESCE(p.hsn_code, ''),
+			COALESCE(p.uom, ''),
+			li.tax_percentage,
+			li.quantity,
+			li.taxable_amount,
+			li.tax_amount,
+			COALESCE(CASE WHEN dc.dc_type = 'transfer' THEN t.tax_type ELSE sg.tax_type END, ''),
+			COALESCE(s.snapshot_json, '')
+		FROM dc_line_items li
+		INNER JOIN delivery_challans dc ON li.dc_id = dc.id
+		LEFT JOIN products p ON li.product_id = p.id
+		LEFT JOIN shipment_groups sg ON dc.shipment_group_id = sg.id
+		LEFT JOIN transfer_dcs t ON t.dc_id = dc.id
+		LEFT JOIN dc_snapshots s ON s.dc_id = dc.id
+		WHERE dc.project_id = ?
+		  AND dc.dc_type IN ('transit', 'transfer')
+		  AND dc.status NOT IN ('draft', 'pending_approval', 'rejected', 'cancelled')
+		  AND NOT (dc.dc_type = 'transit' AND sg.transfer_dc_id IS NOT NULL)`+dateClause+`
+		ORDER BY dc.id, li.line_order
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("GetHSNSummaryReport: %w", err)
+	}
+	defer rows.Close()
+
+	var summary models.HSNSummary
+	snapshots := make(map[int]*models.DCSnapshot)
+	for rows.Next() {
+		var (
+			dcID, productID, qty int
+			name, hsn, uom       string
+			rate, taxable, tax   float64
+			taxType, snapJSON    string
+		)
+		if err := rows.Scan(&dcID, &productID, &name, &hsn, &uom, &rate, &qty, &taxable, &tax, &taxType, &snapJSON); err != nil {
+			return nil, fmt.Errorf("GetHSNSummaryReport scan: %w", err)
+		}
+		snap, seen := snapshots[dcID]
+		if !seen && snapJSON != "" {
+			snap = &models.DCSnapshot{}
+			if err := json.Unmarshal([]byte(snapJSON), snap); err != nil {
+				return nil, fmt.Errorf("GetHSNSummaryReport decode snapshot of DC %d: %w", dcID, err)
+			}
+			snapshots[dcID] = snap
+		}
+		if snap != nil {
+			if p, ok := snap.Products[productID]; ok {
+				name, hsn, uom = p.ItemName, p.HSNCode, p.UoM
+			}
+		}
+		summary.Add(hsn, name, uom, rate, qty, taxable, tax, taxType)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetHSNSummaryReport: %w", err)
+	}
+	return summary.Rows(), nil
+}
@@ -42,6 +42,8 @@ func setupReportsTestDB(t *testing.T) *sql.DB {
 		);
 		CREATE TABLE serial_numbers (id INTEGER PRIMARY KEY, project_id INTEGER, line_item_id INTEGER, serial_number TEXT, created_at DATETIME DEFAULT CURRENT_TIMESTAMP);
 		CREATE TABLE dc_transit_details (id INTEGER PRIMARY KEY, dc_id INTEGER, transporter_name TEXT, vehicle_number TEXT, eway_bill_number TEXT, notes TEXT);
+		CREATE TABLE return_dcs (id INTEGER PRIMARY KEY, dc_id INTEGER, original_dc_id INTEGER, reason TEXT, remarks TEXT, created_at DATETIME DEFAULT CURRENT_TIMESTAMP);
+		CREATE TABLE return_dc_serials (id INTEGER PRIMARY KEY, return_dc_id INTEGER, serial_number_id INTEGER, line_item_id INTEGER, product_id INTEGER, serial_number TEXT);
 	`
 	if _, err := db.Exec(schema); err != nil {
 		t.Fatalf("Failed to create schema: %v", err)
@@ -189,11 +191,31 @@ func TestDestinationReport(t *testing.T) {
 			if r.OfficialDCs != 1 {
 				t.Errorf("Secunderabad OfficialDCs = %d; want 1", r.OfficialDCs)
 			}
+			if r.PendingCount != 1 || r.DeliveredCount != 0 {
+				t.Errorf("Secunderabad pending/delivered = %d/%d; want 1/0", r.PendingCount, r.DeliveredCount)
+			}
 		}
 	}
 	if !found {
 		t.Error("Expected Hyderabad/Secunderabad row not found")
 	}
+
+	// A delivered DC still counts as issued, and moves from pending to delivered.
+	if _, err := db.Exec(`UPDATE delivery_challans SET status = 'delivered' WHERE id = 3`); err != nil {
+		t.Fatalf("mark delivered: %v", err)
+	}
+	rows, err = GetDestinationReport(1, nil, nil)
+	if err != nil {
+		t.Fatalf("GetDestinationReport: %v", err)
+	}
+	for _, r := range rows {
+		if r.District == "Hyderabad" && r.Mandal == "Secunderabad" {
+			if r.IssuedCount != 1 || r.DeliveredCount != 1 || r.PendingCount != 0 {
+				t.Errorf("after delivery: issued/delivered/pending = %d/%d/%d; want 1/1/0",
+					r.IssuedCount, r.DeliveredCount, r.PendingCount)
+			}
+		}
+	}
 }
 
 func TestDestinationDrillDown(t *testing.T) {
@@ -239,6 +261,119 @@ func TestProductReport(t *testing.T) {
 	}
 }
 
+func TestHSNSummaryReport(t *testing.T) {
+	db := setupReportsTestDB(t)
+	seedReportsData(t, db)
+	mustExec := func(q string) {
+		t.Helper()
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	mustExec(`ALTER TABLE delivery_challans ADD COLUMN shipment_group_id INTEGER`)
+	mustExec(`CREATE TABLE shipment_groups (id INTEGER PRIMARY KEY, project_id INTEGER, tax_type TEXT, transfer_dc_id INTEGER)`)
+	mustExec(`CREATE TABLE transfer_dcs (id INTEGER PRIMARY KEY, dc_id INTEGER, tax_type TEXT)`)
+	mustExec(`CREATE TABLE dc_snapshots (dc_id INTEGER PRIMARY KEY, snapshot_json TEXT, captured_at DATETIME)`)
+
+	mustExec(`UPDATE products SET hsn_code = '8541', uom = 'Nos' WHERE id = 1`)
+	mustExec(`UPDATE products SET hsn_code = '8504', uom = 'Nos' WHERE id = 2`)
+	mustExec(`INSERT INTO shipment_groups (id, project_id, tax_type) VALUES (1, 1, 'cgst_sgst')`)
+	mustExec(`UPDATE delivery_challans SET shipment_group_id = 1 WHERE id = 1`)
+
+	// An inter-state Transfer DC whose snapshot froze the inverter under an older HSN code.
+	mustExec(`INSERT INTO delivery_challans (id, project_id, dc_number, dc_type, status, challan_date, created_by) VALUES (6, 1, 'FSS-STDC-2526-001', 'transfer', 'split', '2026-01-20', 1)`)
+	mustExec(`INSERT INTO transfer_dcs (id, dc_id, tax_type) VALUES (1, 6, 'igst')`)
+	mustExec(`INSERT INTO dc_line_items (id, dc_id, product_id, quantity, rate, tax_percentage, taxable_amount, tax_amount, total_amount, line_order) VALUES (6, 6, 2, 4, 200, 18, 800, 144, 944, 1)`)
+	mustExec(`INSERT INTO dc_snapshots (dc_id, snapshot_json) VALUES (6, '{"products":{"2":{"item_name":"Inverter 5kW","hsn_code":"8502","uom":"Nos"}}}')`)
+
+	// A transit DC split from that Transfer DC carries the same goods and is skipped.
+	mustExec(`INSERT INTO shipment_groups (id, project_id, tax_type, transfer_dc_id) VALUES (2, 1, 'igst', 1)`)
+	mustExec(`INSERT INTO delivery_challans (id, project_id, dc_number, dc_type, status, challan_date, created_by, shipment_group_id) VALUES (7, 1, 'FSS-TDC-2526-003', 'transit', 'issued', '2026-01-21', 1, 2)`)
+	mustExec(`INSERT INTO dc_line_items (id, dc_id, product_id, quantity, rate, tax_percentage, taxable_amount, tax_amount, total_amount, line_order) VALUES (7, 7, 2, 4, 200, 18, 800, 144, 944, 1)`)
+
+	rows, err := GetHSNSummaryReport(1, nil, nil)
+	if err != nil {
+		t.Fatalf("GetHSNSummaryReport: %v", err)
+	}
+	// Draft DC 2, official DCs 3/4, the split transit DC 7 and project 2 are excluded.
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 HSN rows, got %d: %+v", len(rows), rows)
+	}
+	want := []struct {
+		hsn        string
+		qty        int
+		taxable    float64
+		cgst, igst float64
+	}{
+		{"8502", 4, 800, 0, 144},
+		{"8504", 5, 1000, 90, 0},
+		{"8541", 10, 1000, 90, 0},
+	}
+	for i, w := range want {
+		r := rows[i]
+		if r.HSNCode != w.hsn || r.Quantity != w.qty || r.TaxableValue != w.taxable || r.CGST != w.cgst || r.SGST != w.cgst || r.IGST != w.igst {
+			t.Errorf("row %d = %+v; want %+v", i, r, w)
+		}
+	}
+
+	// Date range excludes the Transfer DC.
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	rows, err = GetHSNSummaryReport(1, &start, &end)
+	if err != nil {
+		t.Fatalf("GetHSNSummaryReport with range: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Errorf("Expected 2 HSN rows in range, got %d", len(rows))
+	}
+}
+
+func TestDocumentsIssuedReport(t *testing.T) {
+	db := setupReportsTestDB(t)
+	seedReportsData(t, db)
+	mustExec := func(q string) {
+		t.Helper()
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	mustExec(`ALTER TABLE projects ADD COLUMN dc_number_format TEXT`)
+	// Transit DC 003 was a draft that got deleted; 004 was cancelled.
+	mustExec(`INSERT INTO delivery_challans (id, project_id, dc_number, dc_type, status, challan_date, created_by) VALUES (6, 1, 'FSS-TDC-2526-004', 'transit', 'cancelled', '2026-01-20', 1)`)
+
+	series, unparsed, err := GetDocumentsIssuedReport(1, nil, nil)
+	if err != nil {
+		t.Fatalf("GetDocumentsIssuedReport: %v", err)
+	}
+	if len(unparsed) != 0 {
+		t.Errorf("unparsed = %v; want none", unparsed)
+	}
+	if len(series) != 2 {
+		t.Fatalf("Expected 2 series, got %d: %+v", len(series), series)
+	}
+	tdc := series[0]
+	if tdc.Series != "FSS-TDC-2526-#" || tdc.FromNumber != "FSS-TDC-2526-001" || tdc.ToNumber != "FSS-TDC-2526-004" {
+		t.Errorf("transit series = %+v", tdc)
+	}
+	if tdc.TotalNumber != 4 || tdc.Issued != 1 || tdc.NotIssued != 1 || tdc.Cancelled != 1 || tdc.Deleted != 1 {
+		t.Errorf("transit counts = %+v; want total 4, 1 issued, 1 draft, 1 cancelled, 1 deleted", tdc)
+	}
+	if series[1].DCType != "official" || series[1].TotalNumber != 2 || series[1].Issued != 1 {
+		t.Errorf("official series = %+v", series[1])
+	}
+
+	// Date range keeps only DCs dated in it.
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)
+	series, _, err = GetDocumentsIssuedReport(1, &start, &end)
+	if err != nil {
+		t.Fatalf("GetDocumentsIssuedReport with range: %v", err)
+	}
+	if len(series) != 2 || series[0].TotalNumber != 1 || series[1].TotalNumber != 1 {
+		t.Errorf("series in range = %+v; want one number each", series)
+	}
+}
+
 func TestSerialReport(t *testing.T) {
 	db := setupReportsTestDB(t)
 	seedReportsData(t, db)
@@ -267,6 +402,41 @@ func TestSerialReport(t *testing.T) {
 	}
 }
 
+func TestSerialReportReturnMovement(t *testing.T) {
+	db := setupReportsTestDB(t)
+	seedReportsData(t, db)
+
+	// SN-001 comes back on a return DC against DC 1
+	db.Exec(`INSERT INTO delivery_challans (id, project_id, dc_number, dc_type, status, ship_to_address_id, challan_date, created_by) VALUES (6, 1, 'FSS-RDC-2526-001', 'return', 'issued', 1, '2026-01-20', 1)`)
+	db.Exec(`INSERT INTO return_dcs (id, dc_id, original_dc_id, reason) VALUES (1, 6, 1, 'faulty')`)
+	db.Exec(`INSERT INTO return_dc_serials (return_dc_id, serial_number_id, line_item_id, product_id, serial_number) VALUES (1, 1, 1, 1, 'SN-001')`)
+
+	rows, err := GetSerialReport(1, "SN-001", nil, nil)
+	if err != nil {
+		t.Fatalf("GetSerialReport: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected outbound and return rows for SN-001, got %d", len(rows))
+	}
+	if rows[0].Movement != SerialMovementOutbound || rows[0].TransitDCNumber != "FSS-TDC-2526-001" {
+		t.Errorf("rows[0] = %s on %s; want outbound on FSS-TDC-2526-001", rows[0].Movement, rows[0].TransitDCNumber)
+	}
+	if rows[1].Movement != SerialMovementReturn || rows[1].TransitDCNumber != "FSS-RDC-2526-001" {
+		t.Errorf("rows[1] = %s on %s; want return on FSS-RDC-2526-001", rows[1].Movement, rows[1].TransitDCNumber)
+	}
+
+	// Date filter applies to the return DC's challan date
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	rows, err = GetSerialReport(1, "SN-001", &start, &end)
+	if err != nil {
+		t.Fatalf("GetSerialReport with dates: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Movement != SerialMovementOutbound {
+		t.Errorf("Expected only the outbound row before the return date, got %d rows", len(rows))
+	}
+}
+
 func TestSerialReportProjectScoped(t *testing.T) {
 	db := setupReportsTestDB(t)
 	seedReportsData(t, db)
@@ -0,0 +1,252 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+// GetReturnableSerials lists the serials a DC dispatched that have not been
+// released by a cancellation or an earlier return, in line order.
+// Hand-written SQL: the return queries are not part of the sqlc queries.
+func GetReturnableSerials(dcID int) ([]models.ReturnDCSerial, error) {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT sn.id, sn.line_item_id, li.product_id, sn.serial_number, COALESCE(p.item_name, '')
+		   FROM serial_numbers sn
+		  INNER JOIN dc_line_items li ON li.id = sn.line_item_id
+		   LEFT JOIN products p ON p.id = li.product_id
+		  WHERE li.dc_id = ? AND sn.released_at IS NULL
+		  ORDER BY li.line_order, sn.id`, dcID)
+	if err != nil {
+		return nil, fmt.Errorf("GetReturnableSerials: %w", err)
+	}
+	defer rows.Close()
+
+	var serials []models.ReturnDCSerial
+	for rows.Next() {
+		var s models.ReturnDCSerial
+		if err := rows.Scan(&s.SerialNumberID, &s.LineItemID, &s.ProductID, &s.SerialNumber, &s.ItemName); err != nil {
+			return nil, fmt.Errorf("GetReturnableSerials scan: %w", err)
+		}
+		serials = append(serials, s)
+	}
+	return serials, rows.Err()
+}
+
+// CreateReturnDC raises an issued return DC against an earlier DC for the given
+// serials. The return DC takes its number from the project's return series, ships
+// from the original ship-to location, and releases the serials for re-dispatch.
+// Returns the ID of the new return DC.
+func CreateReturnDC(original *models.DeliveryChallan, challanDate, reason, remarks string, serialNumberIDs []int, userID int) (int, error) {
+	if len(serialNumberIDs) == 0 {
+		return 0, fmt.Errorf("select at least one serial number to return")
+	}
+	if !models.IsValidReturnReason(reason) {
+		return 0, fmt.Errorf("invalid return reason: %s", reason)
+	}
+	dcDate, err := time.Parse("2006-01-02", challanDate)
+	if err != nil {
+		return 0, fmt.Errorf("invalid return date: %s", challanDate)
+	}
+
+	available, err := GetReturnableSerials(original.ID)
+	if err != nil {
+		return 0, fmt.Errorf("CreateReturnDC: %w", err)
+	}
+	byID := make(map[int]models.ReturnDCSerial, len(available))
+	for _, s := range available {
+		byID[s.SerialNumberID] = s
+	}
+	returned := make([]models.ReturnDCSerial, 0, len(serialNumberIDs))
+	for _, id := range serialNumberIDs {
+		s, ok := byID[id]
+		if !ok {
+			return 0, fmt.Errorf("serial %d is not available for return on DC %s", id, original.DCNumber)
+		}
+		returned = append(returned, s)
+	}
+
+	dc := &models.DeliveryChallan{
+		ProjectID:             original.ProjectID,
+		DCType:                services.DCTypeReturn,
+		Status:                models.DCStatusIssued,
+		BillToAddressID:       original.BillToAddressID,
+		ShipToAddressID:       original.ShipToAddressID,
+		ChallanDate:           &challanDate,
+		CreatedBy:             userID,
+		BillFromAddressID:     original.BillFromAddressID,
+		DispatchFromAddressID: original.DispatchFromAddressID,
+	}
+
+	// Snapshot before opening the transaction: the pool has a single connection.
+	snap, err := BuildDCSnapshot(dc)
+	if err != nil {
+		return 0, fmt.Errorf("CreateReturnDC: %w", err)
+	}
+	for _, s := range returned {
+		if _, ok := snap.Products[s.ProductID]; ok {
+			continue
+		}
+		if p, err := GetProductByID(s.ProductID); err == nil {
+			snap.Products[s.ProductID] = models.DCSnapshotProduct{
+				ItemName:        p.ItemName,
+				ItemDescription: p.ItemDescription,
+				HSNCode:         p.HSNCode,
+				UoM:             p.UoM,
+				BrandModel:      p.BrandModel,
+				GSTPercentage:   p.GSTPercentage,
+			}
+		}
+	}
+
+	dc.DCNumber, err = services.GenerateDCNumberForDate(DB, original.ProjectID, services.DCTypeReturn, dcDate)
+	if err != nil {
+		return 0, fmt.Errorf("CreateReturnDC: generate DC number: %w", err)
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("CreateReturnDC: begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx(),
+		`INSERT INTO delivery_challans (project_id, dc_number, dc_type, status, bill_to_address_id, ship_to_address_id,
+		     challan_date, issued_at, issued_by, created_by, bill_from_address_id, dispatch_from_address_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, ?, ?, ?, ?)`,
+		dc.ProjectID, dc.DCNumber, dc.DCType, dc.Status, dc.BillToAddressID, dc.ShipToAddressID,
+		challanDate, userID, userID, dc.BillFromAddressID, dc.DispatchFromAddressID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("CreateReturnDC: insert DC: %w", err)
+	}
+	dcID, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("CreateReturnDC: %w", err)
+	}
+
+	res, err = tx.ExecContext(ctx(),
+		`INSERT INTO return_dcs (dc_id, original_dc_id, reason, remarks) VALUES (?, ?, ?, ?)`,
+		dcID, original.ID, reason, remarks,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("CreateReturnDC: insert return: %w", err)
+	}
+	returnID, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("CreateReturnDC: %w", err)
+	}
+
+	for _, s := range returned {
+		res, err := tx.ExecContext(ctx(),
+			`UPDATE serial_numbers SET released_at = CURRENT_TIMESTAMP WHERE id = ? AND released_at IS NULL`,
+			s.SerialNumberID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("CreateReturnDC: release serial %s: %w", s.SerialNumber, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return 0, fmt.Errorf("serial %s has already been released", s.SerialNumber)
+		}
+		if _, err := tx.ExecContext(ctx(),
+			`INSERT INTO return_dc_serials (return_dc_id, serial_number_id, line_item_id, product_id, serial_number)
+			 VALUES (?, ?, ?, ?, ?)`,
+			returnID, s.SerialNumberID, s.LineItemID, s.ProductID, s.SerialNumber,
+		); err != nil {
+			return 0, fmt.Errorf("CreateReturnDC: insert serial %s: %w", s.SerialNumber, err)
+		}
+	}
+
+	if err := saveDCSnapshot(tx, int(dcID), snap); err != nil {
+		return 0, err
+	}
+	return int(dcID), tx.Commit()
+}
+
+const returnDCSelect = `
+	SELECT r.id, r.dc_id, r.original_dc_id, r.reason, r.remarks, r.created_at,
+	       dc.dc_number, COALESCE(dc.challan_date, ''), COALESCE(o.dc_number, '')
+	  FROM return_dcs r
+	 INNER JOIN delivery_challans dc ON dc.id = r.dc_id
+	  LEFT JOIN delivery_challans o ON o.id = r.original_dc_id`
+
+func scanReturnDC(scanner interface{ Scan(...interface{}) error }) (*models.ReturnDC, error) {
+	r := &models.ReturnDC{}
+	var createdAt sql.NullTime
+	if err := scanner.Scan(&r.ID, &r.DCID, &r.OriginalDCID, &r.Reason, &r.Remarks, &createdAt,
+		&r.DCNumber, &r.ChallanDate, &r.OriginalDCNumber); err != nil {
+		return nil, err
+	}
+	if createdAt.Valid {
+		r.CreatedAt = createdAt.Time
+	}
+	return r, nil
+}
+
+// loadReturnDCSerials fills in the serials taken back on a return DC.
+func loadReturnDCSerials(r *models.ReturnDC) error {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT rs.serial_number_id, rs.line_item_id, COALESCE(rs.product_id, 0), rs.serial_number, COALESCE(p.item_name, '')
+		   FROM return_dc_serials rs
+		   LEFT JOIN products p ON p.id = rs.product_id
+		  WHERE rs.return_dc_id = ?
+		  ORDER BY rs.id`, r.ID)
+	if err != nil {
+		return fmt.Errorf("loadReturnDCSerials: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s models.ReturnDCSerial
+		if err := rows.Scan(&s.SerialNumberID, &s.LineItemID, &s.ProductID, &s.SerialNumber, &s.ItemName); err != nil {
+			return fmt.Errorf("loadReturnDCSerials scan: %w", err)
+		}
+		r.Serials = append(r.Serials, s)
+	}
+	return rows.Err()
+}
+
+// GetReturnDCByDCID returns the return details of a return DC with its serials,
+// or nil if the DC is not a return DC.
+func GetReturnDCByDCID(dcID int) (*models.ReturnDC, error) {
+	r, err := scanReturnDC(DB.QueryRowContext(ctx(), returnDCSelect+` WHERE r.dc_id = ?`, dcID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetReturnDCByDCID: %w", err)
+	}
+	if err := loadReturnDCSerials(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetReturnDCsByOriginalDC lists the return DCs raised against a DC, oldest first.
+func GetReturnDCsByOriginalDC(originalDCID int) ([]*models.ReturnDC, error) {
+	rows, err := DB.QueryContext(ctx(), returnDCSelect+` WHERE r.original_dc_id = ? ORDER BY r.id`, originalDCID)
+	if err != nil {
+		return nil, fmt.Errorf("GetReturnDCsByOriginalDC: %w", err)
+	}
+	var returns []*models.ReturnDC
+	for rows.Next() {
+		r, err := scanReturnDC(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("GetReturnDCsByOriginalDC scan: %w", err)
+		}
+		returns = append(returns, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, r := range returns {
+		if err := loadReturnDCSerials(r); err != nil {
+			return nil, err
+		}
+	}
+	return returns, nil
+}
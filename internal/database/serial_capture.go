@@ -0,0 +1,344 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// ErrSerialAlreadyCaptured is returned when a scanned serial is already on a
+// DC of the project for the same product.
+var ErrSerialAlreadyCaptured = errors.New("serial already captured")
+
+// GetSerialCaptureSheet loads the scan screen's view of a shipment group or
+// transfer DC: its serial-carrying DC, product lines and destinations with
+// the serials captured so far. It returns sql.ErrNoRows when there is no such draft.
+// Hand-written SQL: joins across the group's DCs and the destination quantity grid.
+func GetSerialCaptureSheet(kind string, entityID int) (*models.SerialCaptureSheet, error) {
+	s := &models.SerialCaptureSheet{Kind: kind, EntityID: entityID}
+	var headerQuery, destQuery string
+	switch kind {
+	case models.SerialCaptureShipment:
+		headerQuery = `SELECT sg.project_id, sg.status, dc.id, dc.dc_number
+			FROM shipment_groups sg
+			INNER JOIN delivery_challans dc ON dc.shipment_group_id = sg.id AND dc.dc_type = 'transit'
+			WHERE sg.id = ?`
+		destQuery = `SELECT dc.ship_to_address_id, li.product_id, li.quantity
+			FROM delivery_challans dc
+			INNER JOIN dc_line_items li ON li.dc_id = dc.id
+			WHERE dc.shipment_group_id = ? AND dc.dc_type = 'official'
+			ORDER BY dc.id, li.line_order`
+	case models.SerialCaptureTransfer:
+		headerQuery = `SELECT dc.project_id, dc.status, dc.id, dc.dc_number
+			FROM transfer_dcs t
+			INNER JOIN delivery_challans dc ON dc.id = t.dc_id
+			WHERE t.id = ?`
+		destQuery = `SELECT d.ship_to_address_id, q.product_id, q.quantity
+			FROM transfer_dc_destinations d
+			INNER JOIN transfer_dc_destination_quantities q ON q.destination_id = d.id
+			WHERE d.transfer_dc_id = ?
+			ORDER BY d.id`
+	default:
+		return nil, fmt.Errorf("GetSerialCaptureSheet: unknown kind %q", kind)
+	}
+
+	if err := DB.QueryRowContext(ctx(), headerQuery, entityID).Scan(&s.ProjectID, &s.Status, &s.DCID, &s.DCNumber); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("GetSerialCaptureSheet: %w", err)
+	}
+	if err := loadCaptureProducts(s); err != nil {
+		return nil, err
+	}
+	if err := expandCaptureKits(s); err != nil {
+		return nil, err
+	}
+	if err := loadCaptureDestinations(s, destQuery); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// loadCaptureProducts reads the line items of the sheet's DC with their
+// products' serial rules and serial counts.
+func loadCaptureProducts(s *models.SerialCaptureSheet) error {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT li.id, li.product_id, p.item_name, li.quantity,
+		        p.serial_pattern, p.serial_min_length, p.serial_max_length, p.serial_prefix, p.serial_luhn,
+		        (SELECT COUNT(*) FROM serial_numbers sn WHERE sn.line_item_id = li.id AND sn.released_at IS NULL)
+		 FROM dc_line_items li
+		 INNER JOIN products p ON p.id = li.product_id
+		 WHERE li.dc_id = ?
+		 ORDER BY li.line_order, li.id`, s.DCID)
+	if err != nil {
+		return fmt.Errorf("loadCaptureProducts: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		p := &models.SerialCaptureProduct{}
+		r := &p.Rule
+		if err := rows.Scan(&p.LineItemID, &p.ProductID, &p.ItemName, &p.Required,
+			&r.Pattern, &r.MinLength, &r.MaxLength, &r.Prefix, &r.Luhn, &p.Captured); err != nil {
+			return fmt.Errorf("loadCaptureProducts: %w", err)
+		}
+		s.Products = append(s.Products, p)
+	}
+	return rows.Err()
+}
+
+// expandCaptureKits replaces the sheet's kit lines with one line per
+// component, so each component's serials are scanned on their own.
+func expandCaptureKits(s *models.SerialCaptureSheet) error {
+	if len(s.Products) == 0 {
+		return nil
+	}
+	placeholders := make([]string, 0, len(s.Products))
+	args := make([]interface{}, 0, len(s.Products))
+	for _, p := range s.Products {
+		placeholders = append(placeholders, "?")
+		args = append(args, p.ProductID)
+	}
+	kits, err := queryKitComponents(`k.kit_product_id IN (`+strings.Join(placeholders, ", ")+`)`, args...)
+	if err != nil {
+		return fmt.Errorf("expandCaptureKits: %w", err)
+	}
+	if len(kits) == 0 {
+		return nil
+	}
+	var expanded []*models.SerialCaptureProduct
+	for _, line := range s.Products {
+		components := kits[line.ProductID]
+		if len(components) == 0 {
+			expanded = append(expanded, line)
+			continue
+		}
+		captured, err := componentSerialsByLineItem(line.LineItemID)
+		if err != nil {
+			return fmt.Errorf("expandCaptureKits: %w", err)
+		}
+		for _, c := range components {
+			expanded = append(expanded, &models.SerialCaptureProduct{
+				ProductID:    c.ProductID,
+				LineItemID:   line.LineItemID,
+				ItemName:     line.ItemName + " — " + c.ItemName,
+				Rule:         c.SerialRule,
+				Required:     line.Required * c.Quantity,
+				Captured:     len(captured[c.ProductID]),
+				KitProductID: line.ProductID,
+				PerKit:       c.Quantity,
+			})
+		}
+	}
+	s.Products = expanded
+	return nil
+}
+
+// loadCaptureDestinations reads the quantity grid with destQuery, the serials
+// scanned per destination and the destinations' names.
+func loadCaptureDestinations(s *models.SerialCaptureSheet, destQuery string) error {
+	rows, err := DB.QueryContext(ctx(), destQuery, s.EntityID)
+	if err != nil {
+		return fmt.Errorf("loadCaptureDestinations: %w", err)
+	}
+	for rows.Next() {
+		var addressID, productID, qty int
+		if err := rows.Scan(&addressID, &productID, &qty); err != nil {
+			rows.Close()
+			return fmt.Errorf("loadCaptureDestinations: %w", err)
+		}
+		d := s.Destination(addressID)
+		if d == nil {
+			d = &models.SerialCaptureDestination{AddressID: addressID, Required: map[int]int{}, Captured: map[int]int{}}
+			s.Destinations = append(s.Destinations, d)
+		}
+		if kit := s.KitComponents(productID); len(kit) > 0 {
+			for _, p := range kit {
+				d.Required[p.ProductID] += qty * p.PerKit
+			}
+			continue
+		}
+		d.Required[productID] += qty
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loadCaptureDestinations: %w", err)
+	}
+
+	rows, err = DB.QueryContext(ctx(),
+		`SELECT sn.ship_to_address_id, sn.product_id, COUNT(*)
+		 FROM serial_numbers sn
+		 INNER JOIN dc_line_items li ON li.id = sn.line_item_id
+		 WHERE li.dc_id = ? AND sn.ship_to_address_id IS NOT NULL AND sn.released_at IS NULL
+		 GROUP BY sn.ship_to_address_id, sn.product_id`, s.DCID)
+	if err != nil {
+		return fmt.Errorf("loadCaptureDestinations: %w", err)
+	}
+	for rows.Next() {
+		var addressID, productID, n int
+		if err := rows.Scan(&addressID, &productID, &n); err != nil {
+			rows.Close()
+			return fmt.Errorf("loadCaptureDestinations: %w", err)
+		}
+		if d := s.Destination(addressID); d != nil {
+			d.Captured[productID] = n
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loadCaptureDestinations: %w", err)
+	}
+
+	for _, d := range s.Destinations {
+		a, err := GetAddress(d.AddressID)
+		if err != nil {
+			d.Name = fmt.Sprintf("Address #%d", d.AddressID)
+			continue
+		}
+		d.Name = a.DisplayName()
+	}
+	return nil
+}
+
+// ListCapturedSerials returns the serials of a product scanned onto a line
+// item for a destination, newest first.
+func ListCapturedSerials(lineItemID, productID, addressID int) ([]models.CapturedSerial, error) {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT id, serial_number, COALESCE(created_at, '')
+		 FROM serial_numbers
+		 WHERE line_item_id = ? AND product_id = ? AND ship_to_address_id = ? AND released_at IS NULL
+		 ORDER BY id DESC`, lineItemID, productID, addressID)
+	if err != nil {
+		return nil, fmt.Errorf("ListCapturedSerials: %w", err)
+	}
+	defer rows.Close()
+	var out []models.CapturedSerial
+	for rows.Next() {
+		var cs models.CapturedSerial
+		if err := rows.Scan(&cs.ID, &cs.SerialNumber, &cs.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ListCapturedSerials: %w", err)
+		}
+		out = append(out, cs)
+	}
+	return out, rows.Err()
+}
+
+// ErrCaptureClosed is returned when a scan reaches a DC that is no longer a
+// draft: its serials were frozen when it was issued or sent for approval.
+var ErrCaptureClosed = errors.New("DC is no longer a draft")
+
+// ErrCaptureQuantityFull is returned when a destination or line already has
+// every serial its quantity needs.
+var ErrCaptureQuantityFull = errors.New("all serials already captured")
+
+// captureEditEntity maps a capture kind to the entity whose edit version a
+// scan bumps, so an edit wizard opened before the scans cannot overwrite them.
+func captureEditEntity(kind string) string {
+	if kind == models.SerialCaptureTransfer {
+		return models.AuditEntityTransferDC
+	}
+	return models.AuditEntityShipmentGroup
+}
+
+// AddCapturedSerial saves a scanned serial of a product on the sheet's DC for
+// a destination. In one transaction it re-checks that the DC is still a draft
+// (ErrCaptureClosed) and the quantities still need the serial
+// (ErrCaptureQuantityFull), inserts it and bumps the draft's edit version. It
+// returns ErrSerialAlreadyCaptured when the project already has the serial
+// for the product.
+func AddCapturedSerial(s *models.SerialCaptureSheet, productID, addressID int, serial string) error {
+	line, dest := s.Product(productID), s.Destination(addressID)
+	if line == nil || dest == nil {
+		return ErrCaptureQuantityFull
+	}
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("AddCapturedSerial: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := checkCaptureDraft(tx, s.DCID); err != nil {
+		return err
+	}
+	// A plain line counts every serial on it, as loadCaptureProducts does; a
+	// kit component only its own.
+	var onLine, forDest int
+	if err := tx.QueryRowContext(ctx(),
+		`SELECT COUNT(*) FROM serial_numbers
+		 WHERE line_item_id = ? AND released_at IS NULL AND (? = 0 OR product_id = ?)`,
+		line.LineItemID, line.KitProductID, productID,
+	).Scan(&onLine); err != nil {
+		return fmt.Errorf("AddCapturedSerial: %w", err)
+	}
+	if err := tx.QueryRowContext(ctx(),
+		`SELECT COUNT(*) FROM serial_numbers sn
+		 INNER JOIN dc_line_items li ON li.id = sn.line_item_id
+		 WHERE li.dc_id = ? AND sn.ship_to_address_id = ? AND sn.product_id = ? AND sn.released_at IS NULL`,
+		s.DCID, addressID, productID,
+	).Scan(&forDest); err != nil {
+		return fmt.Errorf("AddCapturedSerial: %w", err)
+	}
+	if onLine >= line.Required || forDest >= dest.Required[productID] {
+		return ErrCaptureQuantityFull
+	}
+
+	if _, err := tx.ExecContext(ctx(),
+		`INSERT INTO serial_numbers (project_id, line_item_id, serial_number, product_id, ship_to_address_id)
+		 VALUES (?, ?, ?, ?, ?)`,
+		s.ProjectID, line.LineItemID, serial, productID, addressID); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return ErrSerialAlreadyCaptured
+		}
+		return fmt.Errorf("AddCapturedSerial: %w", err)
+	}
+	if err := bumpEditVersion(tx, captureEditEntity(s.Kind), s.EntityID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveCapturedSerial deletes a scanned serial from a line item of the
+// sheet's DC and bumps the draft's edit version, provided the DC is still a
+// draft (ErrCaptureClosed). It returns sql.ErrNoRows when there is no such serial.
+func RemoveCapturedSerial(s *models.SerialCaptureSheet, lineItemID, serialID int) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("RemoveCapturedSerial: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := checkCaptureDraft(tx, s.DCID); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx(),
+		`DELETE FROM serial_numbers
+		 WHERE id = ? AND line_item_id = ? AND ship_to_address_id IS NOT NULL
+		   AND line_item_id IN (SELECT id FROM dc_line_items WHERE dc_id = ?)`,
+		serialID, lineItemID, s.DCID)
+	if err != nil {
+		return fmt.Errorf("RemoveCapturedSerial: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if err := bumpEditVersion(tx, captureEditEntity(s.Kind), s.EntityID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkCaptureDraft returns ErrCaptureClosed unless the DC is a draft.
+func checkCaptureDraft(tx *sql.Tx, dcID int) error {
+	var status string
+	err := tx.QueryRowContext(ctx(), `SELECT status FROM delivery_challans WHERE id = ?`, dcID).Scan(&status)
+	if err == sql.ErrNoRows || (err == nil && status != models.DCStatusDraft) {
+		return ErrCaptureClosed
+	}
+	if err != nil {
+		return fmt.Errorf("checkCaptureDraft: %w", err)
+	}
+	return nil
+}
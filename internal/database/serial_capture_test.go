@@ -0,0 +1,212 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupSerialCaptureTestDB(t *testing.T) {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:serial_capture_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		`CREATE TABLE products (
+			id INTEGER PRIMARY KEY, item_name TEXT NOT NULL, brand_model TEXT NOT NULL DEFAULT '',
+			serial_pattern TEXT NOT NULL DEFAULT '', serial_min_length INTEGER NOT NULL DEFAULT 0,
+			serial_max_length INTEGER NOT NULL DEFAULT 0, serial_prefix TEXT NOT NULL DEFAULT '',
+			serial_luhn INTEGER NOT NULL DEFAULT 0)`,
+		`CREATE TABLE product_kit_components (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, kit_product_id INTEGER NOT NULL, component_product_id INTEGER NOT NULL,
+			quantity INTEGER NOT NULL DEFAULT 1, line_order INTEGER NOT NULL DEFAULT 0)`,
+		`CREATE TABLE shipment_groups (id INTEGER PRIMARY KEY, project_id INTEGER NOT NULL, status TEXT NOT NULL,
+			version INTEGER NOT NULL DEFAULT 1)`,
+		`CREATE TABLE delivery_challans (
+			id INTEGER PRIMARY KEY, project_id INTEGER NOT NULL, dc_number TEXT NOT NULL, dc_type TEXT NOT NULL,
+			status TEXT NOT NULL, shipment_group_id INTEGER, ship_to_address_id INTEGER)`,
+		`CREATE TABLE dc_line_items (
+			id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL, product_id INTEGER NOT NULL,
+			quantity INTEGER NOT NULL, line_order INTEGER NOT NULL DEFAULT 0)`,
+		`CREATE TABLE serial_numbers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, project_id INTEGER NOT NULL, line_item_id INTEGER NOT NULL,
+			product_id INTEGER, serial_number TEXT NOT NULL, created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			released_at DATETIME, ship_to_address_id INTEGER)`,
+		`CREATE UNIQUE INDEX idx_serial_numbers_unique ON serial_numbers(project_id, product_id, serial_number)`,
+		`INSERT INTO products (id, item_name, serial_prefix) VALUES (10, 'Router', 'RT'), (11, 'Switch', ''),
+			(12, 'Smart Class Set', ''), (13, 'Projector', 'PJ'), (14, 'Speaker', '')`,
+		`INSERT INTO product_kit_components (kit_product_id, component_product_id, quantity, line_order) VALUES
+			(12, 13, 1, 1), (12, 14, 2, 2)`,
+		`INSERT INTO shipment_groups (id, project_id, status) VALUES (5, 1, 'draft'), (6, 1, 'draft')`,
+		// Transit DC 100 carries the serials; official DCs go to addresses 7 and 8.
+		`INSERT INTO delivery_challans (id, project_id, dc_number, dc_type, status, shipment_group_id, ship_to_address_id) VALUES
+			(100, 1, 'TST-TDC-001', 'transit', 'draft', 5, NULL),
+			(101, 1, 'TST-ODC-001', 'official', 'draft', 5, 7),
+			(102, 1, 'TST-ODC-002', 'official', 'draft', 5, 8),
+			(200, 1, 'TST-TDC-002', 'transit', 'draft', 6, NULL),
+			(201, 1, 'TST-ODC-003', 'official', 'draft', 6, 7)`,
+		`INSERT INTO dc_line_items (id, dc_id, product_id, quantity, line_order) VALUES
+			(1000, 100, 10, 3, 1), (1001, 100, 11, 2, 2),
+			(1010, 101, 10, 2, 1), (1011, 101, 11, 2, 2),
+			(1020, 102, 10, 1, 1),
+			(2000, 200, 12, 2, 1), (2010, 201, 12, 2, 1)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	DB = db
+}
+
+// captureSheet loads a capture sheet, failing the test when it cannot.
+func captureSheet(t *testing.T, kind string, id int) *models.SerialCaptureSheet {
+	t.Helper()
+	s, err := GetSerialCaptureSheet(kind, id)
+	if err != nil {
+		t.Fatalf("GetSerialCaptureSheet: %v", err)
+	}
+	return s
+}
+
+func TestSerialCaptureSheet_Shipment(t *testing.T) {
+	setupSerialCaptureTestDB(t)
+
+	opened := captureSheet(t, models.SerialCaptureShipment, 5)
+	if err := AddCapturedSerial(opened, 10, 7, "RT001"); err != nil {
+		t.Fatalf("AddCapturedSerial: %v", err)
+	}
+	if err := AddCapturedSerial(opened, 10, 7, "RT002"); err != nil {
+		t.Fatalf("AddCapturedSerial: %v", err)
+	}
+	if err := AddCapturedSerial(opened, 10, 8, "RT001"); !errors.Is(err, ErrSerialAlreadyCaptured) {
+		t.Fatalf("duplicate scan: got %v, want ErrSerialAlreadyCaptured", err)
+	}
+	// The sheet opened before the scans still thinks address 7 needs routers.
+	if err := AddCapturedSerial(opened, 10, 7, "RT003"); !errors.Is(err, ErrCaptureQuantityFull) {
+		t.Errorf("scan past the quantity: got %v, want ErrCaptureQuantityFull", err)
+	}
+	if v, _ := GetEditVersion(models.AuditEntityShipmentGroup, 5); v != 3 {
+		t.Errorf("edit version = %d, want 3: one bump per saved scan only", v)
+	}
+
+	s, err := GetSerialCaptureSheet(models.SerialCaptureShipment, 5)
+	if err != nil {
+		t.Fatalf("GetSerialCaptureSheet: %v", err)
+	}
+	if s.DCID != 100 || s.ProjectID != 1 || s.Status != "draft" {
+		t.Errorf("header = DC %d project %d %s, want DC 100 project 1 draft", s.DCID, s.ProjectID, s.Status)
+	}
+	if len(s.Products) != 2 || s.Products[0].ProductID != 10 || s.Products[0].Rule.Prefix != "RT" {
+		t.Fatalf("products = %+v, want Router with its rule then Switch", s.Products)
+	}
+	if p := s.Product(10); p.Required != 3 || p.Captured != 2 {
+		t.Errorf("Router line = %d/%d, want 2/3", p.Captured, p.Required)
+	}
+	if len(s.Destinations) != 2 {
+		t.Fatalf("destinations = %d, want 2", len(s.Destinations))
+	}
+	if d := s.Destination(7); d.Required[10] != 2 || d.Captured[10] != 2 || d.Required[11] != 2 {
+		t.Errorf("address 7 = required %v captured %v", d.Required, d.Captured)
+	}
+
+	if got := s.Remaining(10, 7); got != 0 {
+		t.Errorf("Remaining(Router, 7) = %d, want 0", got)
+	}
+	if got := s.Remaining(10, 8); got != 1 {
+		t.Errorf("Remaining(Router, 8) = %d, want 1", got)
+	}
+	if got := s.Remaining(11, 8); got != 0 {
+		t.Errorf("Remaining(Switch, 8) = %d, want 0: address 8 takes no switches", got)
+	}
+
+	recent, err := ListCapturedSerials(1000, 10, 7)
+	if err != nil {
+		t.Fatalf("ListCapturedSerials: %v", err)
+	}
+	if len(recent) != 2 || recent[0].SerialNumber != "RT002" {
+		t.Errorf("recent = %+v, want RT002 then RT001", recent)
+	}
+	if err := RemoveCapturedSerial(s, 1000, recent[0].ID); err != nil {
+		t.Fatalf("RemoveCapturedSerial: %v", err)
+	}
+	if err := RemoveCapturedSerial(s, 1000, recent[0].ID); err != sql.ErrNoRows {
+		t.Errorf("second remove: got %v, want sql.ErrNoRows", err)
+	}
+	if s, _ := GetSerialCaptureSheet(models.SerialCaptureShipment, 5); s.Remaining(10, 7) != 1 {
+		t.Errorf("Remaining(Router, 7) after removal = %d, want 1", s.Remaining(10, 7))
+	}
+}
+
+func TestSerialCaptureSheet_Kit(t *testing.T) {
+	setupSerialCaptureTestDB(t)
+
+	if err := AddCapturedSerial(captureSheet(t, models.SerialCaptureShipment, 6), 14, 7, "SP001"); err != nil {
+		t.Fatalf("AddCapturedSerial: %v", err)
+	}
+
+	s, err := GetSerialCaptureSheet(models.SerialCaptureShipment, 6)
+	if err != nil {
+		t.Fatalf("GetSerialCaptureSheet: %v", err)
+	}
+	if len(s.Products) != 2 {
+		t.Fatalf("products = %+v, want the kit's two components", s.Products)
+	}
+	projector, speaker := s.Product(13), s.Product(14)
+	if projector == nil || projector.LineItemID != 2000 || projector.Required != 2 || projector.Rule.Prefix != "PJ" {
+		t.Errorf("Projector line = %+v, want 2 on line 2000 with its rule", projector)
+	}
+	if speaker == nil || speaker.Required != 4 || speaker.Captured != 1 || speaker.ItemName != "Smart Class Set — Speaker" {
+		t.Errorf("Speaker line = %+v, want 1/4", speaker)
+	}
+	if d := s.Destination(7); d.Required[13] != 2 || d.Required[14] != 4 || d.Captured[14] != 1 {
+		t.Errorf("address 7 = required %v captured %v", d.Required, d.Captured)
+	}
+	if got := s.Remaining(14, 7); got != 3 {
+		t.Errorf("Remaining(Speaker, 7) = %d, want 3", got)
+	}
+	if recent, _ := ListCapturedSerials(2000, 13, 7); len(recent) != 0 {
+		t.Errorf("projector serials = %+v, want none", recent)
+	}
+}
+
+func TestCapturedSerialOnIssuedDC(t *testing.T) {
+	setupSerialCaptureTestDB(t)
+
+	s := captureSheet(t, models.SerialCaptureShipment, 5)
+	if err := AddCapturedSerial(s, 10, 7, "RT001"); err != nil {
+		t.Fatalf("AddCapturedSerial: %v", err)
+	}
+	recent, _ := ListCapturedSerials(1000, 10, 7)
+
+	// Issued while the scan screen was open.
+	if _, err := DB.Exec(`UPDATE delivery_challans SET status = 'issued' WHERE shipment_group_id = 5`); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if err := AddCapturedSerial(s, 10, 7, "RT002"); !errors.Is(err, ErrCaptureClosed) {
+		t.Errorf("scan on an issued DC: got %v, want ErrCaptureClosed", err)
+	}
+	if err := RemoveCapturedSerial(s, 1000, recent[0].ID); !errors.Is(err, ErrCaptureClosed) {
+		t.Errorf("remove from an issued DC: got %v, want ErrCaptureClosed", err)
+	}
+	if got, _ := ListCapturedSerials(1000, 10, 7); len(got) != 1 {
+		t.Errorf("serials on the issued DC = %+v, want RT001 only", got)
+	}
+	if v, _ := GetEditVersion(models.AuditEntityShipmentGroup, 5); v != 2 {
+		t.Errorf("edit version = %d, want 2: refused scans leave it alone", v)
+	}
+}
+
+func TestSerialCaptureSheet_NotFound(t *testing.T) {
+	setupSerialCaptureTestDB(t)
+
+	if _, err := GetSerialCaptureSheet(models.SerialCaptureShipment, 99); err != sql.ErrNoRows {
+		t.Errorf("missing group: got %v, want sql.ErrNoRows", err)
+	}
+}
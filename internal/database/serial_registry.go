@@ -0,0 +1,280 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// registryStateQuery selects a project's registered serials with their derived
+// state. A damaged serial stays damaged; otherwise a serial on a live DC is
+// allocated until the DC is issued and dispatched after, and a serial taken
+// back on a return DC is returned. The single placeholder is the project ID.
+const registryStateQuery = `
+	SELECT r.id, r.project_id, r.product_id, r.serial_number, r.condition, r.source,
+	       r.stocked_in_by, r.created_at, p.item_name, live.dc_id, COALESCE(live.dc_number, ''),
+	       CASE
+	           WHEN r.condition = 'damaged' THEN 'damaged'
+	           WHEN live.dc_id IS NOT NULL AND live.status IN ('draft', 'pending_approval', 'rejected') THEN 'allocated'
+	           WHEN live.dc_id IS NOT NULL THEN 'dispatched'
+	           WHEN EXISTS (
+	               SELECT 1 FROM return_dc_serials rs
+	               INNER JOIN serial_numbers rsn ON rsn.id = rs.serial_number_id
+	               WHERE rsn.project_id = r.project_id AND rsn.product_id = r.product_id
+	                 AND rs.serial_number = r.serial_number
+	           ) THEN 'returned'
+	           ELSE 'in_stock'
+	       END AS state
+	FROM serial_registry r
+	INNER JOIN products p ON p.id = r.product_id
+	LEFT JOIN (
+	    SELECT sn.project_id, sn.product_id, sn.serial_number, dc.id AS dc_id, dc.dc_number, dc.status
+	    FROM serial_numbers sn
+	    INNER JOIN dc_line_items li ON li.id = sn.line_item_id
+	    INNER JOIN delivery_challans dc ON dc.id = li.dc_id
+	    WHERE sn.released_at IS NULL
+	) live ON live.project_id = r.project_id AND live.product_id = r.product_id
+	      AND live.serial_number = r.serial_number
+	WHERE r.project_id = ?`
+
+// RegistryFilters narrows the serial registry listing.
+type RegistryFilters struct {
+	ProductID int
+	State     string
+	Search    string
+	Page      int
+	PageSize  int
+}
+
+// ListRegistrySerials returns one page of a project's registered serials and
+// the number of serials matching the filters.
+// Hand-written SQL: serial_registry is not part of the sqlc queries.
+func ListRegistrySerials(projectID int, f RegistryFilters) ([]*models.RegistrySerial, int, error) {
+	where := []string{"1 = 1"}
+	args := []interface{}{projectID}
+	if f.ProductID > 0 {
+		where = append(where, "product_id = ?")
+		args = append(args, f.ProductID)
+	}
+	if f.State != "" {
+		where = append(where, "state = ?")
+		args = append(args, f.State)
+	}
+	if s := strings.TrimSpace(f.Search); s != "" {
+		where = append(where, "serial_number LIKE ?")
+		args = append(args, "%"+s+"%")
+	}
+	filtered := `SELECT * FROM (` + registryStateQuery + `) WHERE ` + strings.Join(where, " AND ")
+
+	var total int
+	if err := DB.QueryRowContext(ctx(), `SELECT COUNT(*) FROM (`+filtered+`)`, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("ListRegistrySerials count: %w", err)
+	}
+
+	if f.PageSize <= 0 {
+		f.PageSize = 50
+	}
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	rows, err := DB.QueryContext(ctx(), filtered+` ORDER BY item_name, serial_number LIMIT ? OFFSET ?`,
+		append(args, f.PageSize, (f.Page-1)*f.PageSize)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ListRegistrySerials: %w", err)
+	}
+	defer rows.Close()
+
+	var serials []*models.RegistrySerial
+	for rows.Next() {
+		s, err := scanRegistrySerial(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("ListRegistrySerials scan: %w", err)
+		}
+		serials = append(serials, s)
+	}
+	return serials, total, rows.Err()
+}
+
+// GetRegistrySerial returns a registered serial of a project with its state.
+func GetRegistrySerial(projectID, id int) (*models.RegistrySerial, error) {
+	row := DB.QueryRowContext(ctx(), registryStateQuery+` AND r.id = ?`, projectID, id)
+	s, err := scanRegistrySerial(row)
+	if err != nil {
+		return nil, fmt.Errorf("GetRegistrySerial: %w", err)
+	}
+	return s, nil
+}
+
+func scanRegistrySerial(row interface{ Scan(...interface{}) error }) (*models.RegistrySerial, error) {
+	var s models.RegistrySerial
+	var dcID sql.NullInt64
+	if err := row.Scan(&s.ID, &s.ProjectID, &s.ProductID, &s.SerialNumber, &s.Condition, &s.Source,
+		&s.StockedInBy, &s.CreatedAt, &s.ItemName, &dcID, &s.DCNumber, &s.State); err != nil {
+		return nil, err
+	}
+	if dcID.Valid {
+		id := int(dcID.Int64)
+		s.DCID = &id
+	}
+	return &s, nil
+}
+
+// RegistryStateCounts returns how many of a project's registered serials are in
+// each state, optionally for one product.
+func RegistryStateCounts(projectID, productID int) (map[string]int, error) {
+	query := `SELECT state, COUNT(*) FROM (` + registryStateQuery + `)`
+	args := []interface{}{projectID}
+	if productID > 0 {
+		query += ` WHERE product_id = ?`
+		args = append(args, productID)
+	}
+	rows, err := DB.QueryContext(ctx(), query+` GROUP BY state`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("RegistryStateCounts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(models.SerialStates))
+	for rows.Next() {
+		var state string
+		var n int
+		if err := rows.Scan(&state, &n); err != nil {
+			return nil, fmt.Errorf("RegistryStateCounts scan: %w", err)
+		}
+		counts[state] = n
+	}
+	return counts, rows.Err()
+}
+
+// StockInSerials adds serials of a product to a project's registry in good
+// condition. Serials already registered for the product are left as they are
+// and counted as existing.
+func StockInSerials(projectID, productID int, serials []string, source string, userID int) (added, existing int, err error) {
+	tx, err := DB.BeginTx(ctx(), nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("StockInSerials: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	stmt, err := tx.PrepareContext(ctx(), `
+		INSERT OR IGNORE INTO serial_registry (project_id, product_id, serial_number, source, stocked_in_by)
+		VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("StockInSerials prepare: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, sn := range serials {
+		res, err := stmt.ExecContext(ctx(), projectID, productID, sn, source, userID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("StockInSerials insert %s: %w", sn, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			added++
+		} else {
+			existing++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("StockInSerials commit: %w", err)
+	}
+	return added, existing, nil
+}
+
+// SetRegistrySerialCondition marks a registered serial good or damaged.
+func SetRegistrySerialCondition(projectID, id int, condition string) error {
+	if _, err := DB.ExecContext(ctx(),
+		`UPDATE serial_registry SET condition = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND project_id = ?`,
+		condition, id, projectID,
+	); err != nil {
+		return fmt.Errorf("SetRegistrySerialCondition: %w", err)
+	}
+	return nil
+}
+
+// DeleteRegistrySerial removes a serial from a project's registry.
+func DeleteRegistrySerial(projectID, id int) error {
+	if _, err := DB.ExecContext(ctx(),
+		`DELETE FROM serial_registry WHERE id = ? AND project_id = ?`, id, projectID,
+	); err != nil {
+		return fmt.Errorf("DeleteRegistrySerial: %w", err)
+	}
+	return nil
+}
+
+// CheckSerialsInRegistry returns the serials of a product that are not in the
+// project's registry, and those that are registered but marked damaged.
+func CheckSerialsInRegistry(projectID, productID int, serials []string) (missing, damaged []string, err error) {
+	if len(serials) == 0 {
+		return nil, nil, nil
+	}
+
+	placeholders := make([]string, len(serials))
+	args := make([]interface{}, 0, len(serials)+2)
+	args = append(args, projectID, productID)
+	for i, s := range serials {
+		placeholders[i] = "?"
+		args = append(args, s)
+	}
+	rows, err := DB.QueryContext(ctx(), `
+		SELECT serial_number, condition FROM serial_registry
+		WHERE project_id = ? AND product_id = ?
+		  AND serial_number IN (`+strings.Join(placeholders, ",")+`)`, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CheckSerialsInRegistry: %w", err)
+	}
+	defer rows.Close()
+
+	conditions := make(map[string]string, len(serials))
+	for rows.Next() {
+		var sn, condition string
+		if err := rows.Scan(&sn, &condition); err != nil {
+			return nil, nil, fmt.Errorf("CheckSerialsInRegistry scan: %w", err)
+		}
+		conditions[sn] = condition
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("CheckSerialsInRegistry: %w", err)
+	}
+
+	for _, sn := range serials {
+		switch condition, ok := conditions[sn]; {
+		case !ok:
+			missing = append(missing, sn)
+		case condition == models.SerialConditionDamaged:
+			damaged = append(damaged, sn)
+		}
+	}
+	return missing, damaged, nil
+}
+
+// GetSerialRegistryMode returns how a project's DC wizards treat serials
+// missing from its registry.
+func GetSerialRegistryMode(projectID int) (string, error) {
+	var mode string
+	err := DB.QueryRowContext(ctx(),
+		`SELECT serial_registry_mode FROM projects WHERE id = ?`, projectID,
+	).Scan(&mode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SerialRegistryOff, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("GetSerialRegistryMode: %w", err)
+	}
+	return mode, nil
+}
+
+// SetSerialRegistryMode sets how a project's DC wizards treat serials missing
+// from its registry.
+func SetSerialRegistryMode(projectID int, mode string) error {
+	if _, err := DB.ExecContext(ctx(),
+		`UPDATE projects SET serial_registry_mode = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		mode, projectID,
+	); err != nil {
+		return fmt.Errorf("SetSerialRegistryMode: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupSerialRegistryTestDB(t *testing.T) {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:serial_registry_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		`CREATE TABLE projects (id INTEGER PRIMARY KEY, serial_registry_mode TEXT NOT NULL DEFAULT 'off', updated_at DATETIME)`,
+		`CREATE TABLE products (id INTEGER PRIMARY KEY, item_name TEXT NOT NULL)`,
+		`CREATE TABLE delivery_challans (id INTEGER PRIMARY KEY, dc_number TEXT NOT NULL, status TEXT NOT NULL)`,
+		`CREATE TABLE dc_line_items (id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL)`,
+		`CREATE TABLE serial_numbers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, project_id INTEGER NOT NULL, line_item_id INTEGER NOT NULL,
+			product_id INTEGER, serial_number TEXT NOT NULL, released_at DATETIME)`,
+		`CREATE TABLE return_dc_serials (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, return_dc_id INTEGER NOT NULL, serial_number_id INTEGER NOT NULL,
+			line_item_id INTEGER NOT NULL, product_id INTEGER, serial_number TEXT NOT NULL)`,
+		`CREATE TABLE serial_registry (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, project_id INTEGER NOT NULL, product_id INTEGER NOT NULL,
+			serial_number TEXT NOT NULL, condition TEXT NOT NULL DEFAULT 'good', source TEXT NOT NULL DEFAULT '',
+			stocked_in_by INTEGER, created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(project_id, product_id, serial_number))`,
+		`INSERT INTO projects (id) VALUES (1)`,
+		`INSERT INTO products (id, item_name) VALUES (10, 'Router'), (11, 'Switch')`,
+		`INSERT INTO delivery_challans (id, dc_number, status) VALUES (100, 'TST-TDC-001', 'draft'), (101, 'TST-TDC-002', 'issued'), (102, 'TST-TDC-003', 'cancelled')`,
+		`INSERT INTO dc_line_items (id, dc_id) VALUES (1000, 100), (1001, 101), (1002, 102)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	DB = db
+}
+
+func TestSerialRegistryStates(t *testing.T) {
+	setupSerialRegistryTestDB(t)
+
+	added, existing, err := StockInSerials(1, 10, []string{"R1", "R2", "R3", "R4", "R5", "R6"}, "PL-001", 1)
+	if err != nil {
+		t.Fatalf("StockInSerials: %v", err)
+	}
+	if added != 6 || existing != 0 {
+		t.Errorf("added/existing = %d/%d, want 6/0", added, existing)
+	}
+	added, existing, _ = StockInSerials(1, 10, []string{"R6", "R7"}, "PL-002", 1)
+	if added != 1 || existing != 1 {
+		t.Errorf("second stock-in added/existing = %d/%d, want 1/1", added, existing)
+	}
+
+	for _, stmt := range []string{
+		// R1 on a draft, R2 on an issued DC, R3 released by a cancelled DC.
+		`INSERT INTO serial_numbers (project_id, line_item_id, product_id, serial_number) VALUES (1, 1000, 10, 'R1'), (1, 1001, 10, 'R2')`,
+		`INSERT INTO serial_numbers (project_id, line_item_id, product_id, serial_number, released_at) VALUES (1, 1002, 10, 'R3', CURRENT_TIMESTAMP)`,
+		// R4 dispatched and taken back on a return DC.
+		`INSERT INTO serial_numbers (id, project_id, line_item_id, product_id, serial_number, released_at) VALUES (50, 1, 1001, 10, 'R4', CURRENT_TIMESTAMP)`,
+		`INSERT INTO return_dc_serials (return_dc_id, serial_number_id, line_item_id, product_id, serial_number) VALUES (1, 50, 1001, 10, 'R4')`,
+	} {
+		if _, err := DB.Exec(stmt); err != nil {
+			t.Fatalf("fixture: %v", err)
+		}
+	}
+
+	serials, total, err := ListRegistrySerials(1, RegistryFilters{})
+	if err != nil {
+		t.Fatalf("ListRegistrySerials: %v", err)
+	}
+	if total != 7 || len(serials) != 7 {
+		t.Fatalf("total = %d, len = %d, want 7", total, len(serials))
+	}
+	if err := SetRegistrySerialCondition(1, serials[4].ID, models.SerialConditionDamaged); err != nil {
+		t.Fatalf("SetRegistrySerialCondition: %v", err)
+	}
+
+	serials, _, _ = ListRegistrySerials(1, RegistryFilters{})
+	got := make(map[string]string)
+	for _, s := range serials {
+		got[s.SerialNumber] = s.State
+	}
+	want := map[string]string{
+		"R1": models.SerialStateAllocated,
+		"R2": models.SerialStateDispatched,
+		"R3": models.SerialStateInStock,
+		"R4": models.SerialStateReturned,
+		"R5": models.SerialStateDamaged,
+		"R6": models.SerialStateInStock,
+		"R7": models.SerialStateInStock,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("states = %v, want %v", got, want)
+	}
+	if serials[1].DCNumber != "TST-TDC-002" || serials[1].DCID == nil || *serials[1].DCID != 101 {
+		t.Errorf("R2 DC = %v %q, want 101 TST-TDC-002", serials[1].DCID, serials[1].DCNumber)
+	}
+
+	counts, err := RegistryStateCounts(1, 0)
+	if err != nil {
+		t.Fatalf("RegistryStateCounts: %v", err)
+	}
+	if counts[models.SerialStateInStock] != 3 || counts[models.SerialStateDamaged] != 1 {
+		t.Errorf("counts = %v", counts)
+	}
+
+	page, total, _ := ListRegistrySerials(1, RegistryFilters{State: models.SerialStateInStock, PageSize: 2, Page: 2})
+	if total != 3 || len(page) != 1 || page[0].SerialNumber != "R7" {
+		t.Errorf("in-stock page 2 = %d of %d", len(page), total)
+	}
+}
+
+func TestCheckSerialsInRegistry(t *testing.T) {
+	setupSerialRegistryTestDB(t)
+
+	if _, _, err := StockInSerials(1, 10, []string{"R1", "R2"}, "", 1); err != nil {
+		t.Fatalf("StockInSerials: %v", err)
+	}
+	if _, err := DB.Exec(`UPDATE serial_registry SET condition = 'damaged' WHERE serial_number = 'R2'`); err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+
+	missing, damaged, err := CheckSerialsInRegistry(1, 10, []string{"R1", "R2", "R9"})
+	if err != nil {
+		t.Fatalf("CheckSerialsInRegistry: %v", err)
+	}
+	if !reflect.DeepEqual(missing, []string{"R9"}) || !reflect.DeepEqual(damaged, []string{"R2"}) {
+		t.Errorf("missing/damaged = %v/%v, want [R9]/[R2]", missing, damaged)
+	}
+	// Registered under another product only.
+	missing, _, _ = CheckSerialsInRegistry(1, 11, []string{"R1"})
+	if !reflect.DeepEqual(missing, []string{"R1"}) {
+		t.Errorf("other product missing = %v, want [R1]", missing)
+	}
+
+	if mode, _ := GetSerialRegistryMode(1); mode != models.SerialRegistryOff {
+		t.Errorf("default mode = %q, want off", mode)
+	}
+	if err := SetSerialRegistryMode(1, models.SerialRegistryEnforce); err != nil {
+		t.Fatalf("SetSerialRegistryMode: %v", err)
+	}
+	if mode, _ := GetSerialRegistryMode(1); mode != models.SerialRegistryEnforce {
+		t.Errorf("mode = %q, want enforce", mode)
+	}
+}
@@ -19,6 +19,7 @@ type SerialSearchResult struct {
 	ChallanDate   string
 	ShipToSummary string
 	Status        string
+	Condition     string // "damaged" or "missing" when reported in a goods receipt
 }
 
 // SearchSerialNumbers searches for serial numbers across all or a specific project.
@@ -89,9 +90,11 @@ func SearchSerialNumbers(query string, projectID string) ([]SerialSearchResult,
 				 )
 				), 'N/A'
 			),
-			dc.status
+			dc.status,
+			COALESCE(rs.condition, '')
 		FROM serial_numbers sn
 		INNER JOIN dc_line_items li ON sn.line_item_id = li.id
+		LEFT JOIN dc_receipt_serials rs ON rs.line_item_id = li.id AND rs.serial_number = sn.serial_number
 		INNER JOIN delivery_challans dc ON li.dc_id = dc.id
 		LEFT JOIN products pr ON li.product_id = pr.id
 		LEFT JOIN projects p ON dc.project_id = p.id
@@ -113,7 +116,7 @@ func SearchSerialNumbers(query string, projectID string) ([]SerialSearchResult,
 		if err := rows.Scan(
 			&r.SerialNumber, &r.DCNumber, &r.DCID, &r.DCType,
 			&r.ProjectID, &r.ProjectName, &r.ProductName,
-			&r.ChallanDate, &r.ShipToSummary, &r.Status,
+			&r.ChallanDate, &r.ShipToSummary, &r.Status, &r.Condition,
 		); err != nil {
 			return nil, nil, fmt.Errorf("serial search scan failed: %w", err)
 		}
@@ -0,0 +1,560 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// serialDCRow is a serial_numbers row of a serial with the DC that holds it.
+type serialDCRow struct {
+	serialID        int
+	released        bool
+	dcID            int
+	dcNumber        string
+	dcType          string
+	status          string
+	date            string
+	cancelledAt     string
+	cancelReason    string
+	shipToAddressID int
+	groupID         *int
+}
+
+// timelineBuilder collects the events of one serial. The pool has a single
+// connection, so every query is read to the end before the next is issued.
+type timelineBuilder struct {
+	t         *models.SerialTimeline
+	seen      map[string]bool
+	addresses map[int]string
+	snapshots map[int]*models.DCSnapshot
+}
+
+// GetSerialTimelines returns the history of a serial number in a project, one
+// timeline per product the serial is recorded against: its stock-in, the
+// transfer DC that took it to a hub, the split and shipment that sent it on,
+// the transit and official DCs with their ship-to addresses, deliveries,
+// goods receipts, cancellations and returns, in date order. The serial must
+// match exactly; nil is returned when it is unknown.
+// Hand-written SQL: the timeline spans tables outside the sqlc queries.
+func GetSerialTimelines(projectID int, serial string) ([]*models.SerialTimeline, error) {
+	serial = strings.TrimSpace(serial)
+	if serial == "" {
+		return nil, nil
+	}
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT p.id, p.item_name FROM products p
+		 WHERE p.id IN (
+		     SELECT COALESCE(sn.product_id, li.product_id) FROM serial_numbers sn
+		     INNER JOIN dc_line_items li ON li.id = sn.line_item_id
+		     WHERE sn.project_id = ? AND sn.serial_number = ?
+		     UNION
+		     SELECT product_id FROM serial_registry WHERE project_id = ? AND serial_number = ?
+		 )
+		 ORDER BY p.item_name, p.id`,
+		projectID, serial, projectID, serial,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetSerialTimelines: %w", err)
+	}
+	var timelines []*models.SerialTimeline
+	for rows.Next() {
+		t := &models.SerialTimeline{ProjectID: projectID, SerialNumber: serial}
+		if err := rows.Scan(&t.ProductID, &t.ItemName); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("GetSerialTimelines: %w", err)
+		}
+		timelines = append(timelines, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetSerialTimelines: %w", err)
+	}
+
+	for _, t := range timelines {
+		b := &timelineBuilder{t: t, seen: map[string]bool{}, addresses: map[int]string{}, snapshots: map[int]*models.DCSnapshot{}}
+		if err := b.build(); err != nil {
+			return nil, fmt.Errorf("GetSerialTimelines: %w", err)
+		}
+	}
+	return timelines, nil
+}
+
+// GetSerialTimeline returns the history of a serial number of one product, or
+// nil when the serial is not recorded against it.
+func GetSerialTimeline(projectID, productID int, serial string) (*models.SerialTimeline, error) {
+	timelines, err := GetSerialTimelines(projectID, serial)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range timelines {
+		if t.ProductID == productID {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *timelineBuilder) build() error {
+	t := b.t
+
+	var condition, source, createdAt string
+	err := DB.QueryRowContext(ctx(),
+		`SELECT condition, source, COALESCE(created_at, '') FROM serial_registry
+		 WHERE project_id = ? AND product_id = ? AND serial_number = ?`,
+		t.ProjectID, t.ProductID, t.SerialNumber,
+	).Scan(&condition, &source, &createdAt)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return fmt.Errorf("registry: %w", err)
+	default:
+		t.Condition = condition
+		detail := ""
+		if source != "" {
+			detail = "Source: " + source
+		}
+		b.add("registry", models.SerialTimelineEvent{
+			Kind:   models.SerialEventStockedIn,
+			Date:   timelineDate(createdAt),
+			Title:  "Stocked into the serial registry",
+			Detail: detail,
+		})
+	}
+
+	dcRows, err := b.serialDCRows()
+	if err != nil {
+		return err
+	}
+	returned := false
+	for _, r := range dcRows {
+		redispatch := returned
+		switch {
+		case r.dcType == "transfer":
+			if err := b.addTransfer(r, redispatch); err != nil {
+				return err
+			}
+		case r.dcType == "transit" && r.groupID != nil:
+			if err := b.addShipment(r, redispatch); err != nil {
+				return err
+			}
+		default:
+			address, err := b.dcAddress(r.dcID, models.SnapshotRoleShipTo, r.shipToAddressID)
+			if err != nil {
+				return err
+			}
+			b.add(fmt.Sprintf("dc:%d", r.dcID), b.dcEvent(models.SerialEventDispatched, r,
+				fmt.Sprintf("Dispatched on DC %s", r.dcNumber), address, redispatch))
+			if !r.released {
+				t.ShipToAddress = address
+			}
+			if err := b.addDeliveries(r, address); err != nil {
+				return err
+			}
+		}
+		if !r.released {
+			id := r.dcID
+			t.CurrentDCID = &id
+			t.CurrentDC = r.dcNumber
+		}
+		if r.status == models.DCStatusCancelled {
+			detail := ""
+			if r.cancelReason != "" {
+				detail = "Reason: " + r.cancelReason
+			}
+			b.add(fmt.Sprintf("cancel:%d", r.dcID), models.SerialTimelineEvent{
+				Kind:     models.SerialEventCancelled,
+				Date:     firstDate(r.cancelledAt, r.date),
+				Title:    fmt.Sprintf("DC %s cancelled; serial released", r.dcNumber),
+				Detail:   detail,
+				DCID:     intPtrOrNil(r.dcID),
+				DCNumber: r.dcNumber,
+				DCType:   r.dcType,
+				DCStatus: r.status,
+			})
+		}
+		ok, err := b.addReturns(r.serialID)
+		if err != nil {
+			return err
+		}
+		returned = returned || ok
+	}
+
+	sort.SliceStable(t.Events, func(i, j int) bool { return t.Events[i].Date < t.Events[j].Date })
+	if t.Events == nil {
+		t.Events = []models.SerialTimelineEvent{}
+	}
+	return nil
+}
+
+// serialDCRows lists every serial_numbers row of the serial, live or released,
+// oldest first, with the DC it is on.
+func (b *timelineBuilder) serialDCRows() ([]serialDCRow, error) {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT sn.id, sn.released_at IS NOT NULL, dc.id, dc.dc_number, dc.dc_type, dc.status,
+		        COALESCE(dc.challan_date, dc.created_at, ''), COALESCE(dc.cancelled_at, ''),
+		        COALESCE(dc.cancellation_reason, ''), dc.ship_to_address_id, dc.shipment_group_id
+		 FROM serial_numbers sn
+		 INNER JOIN dc_line_items li ON li.id = sn.line_item_id
+		 INNER JOIN delivery_challans dc ON dc.id = li.dc_id
+		 WHERE sn.project_id = ? AND sn.serial_number = ? AND COALESCE(sn.product_id, li.product_id) = ?
+		 ORDER BY sn.id`,
+		b.t.ProjectID, b.t.SerialNumber, b.t.ProductID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("serial rows: %w", err)
+	}
+	defer rows.Close()
+	var out []serialDCRow
+	for rows.Next() {
+		var r serialDCRow
+		var groupID sql.NullInt64
+		if err := rows.Scan(&r.serialID, &r.released, &r.dcID, &r.dcNumber, &r.dcType, &r.status,
+			&r.date, &r.cancelledAt, &r.cancelReason, &r.shipToAddressID, &groupID); err != nil {
+			return nil, fmt.Errorf("serial rows: %w", err)
+		}
+		r.date = timelineDate(r.date)
+		r.cancelledAt = timelineDate(r.cancelledAt)
+		if groupID.Valid {
+			id := int(groupID.Int64)
+			r.groupID = &id
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// addTransfer adds a serial sent to a hub on a transfer DC.
+func (b *timelineBuilder) addTransfer(r serialDCRow, redispatch bool) error {
+	var hubID int
+	err := DB.QueryRowContext(ctx(), `SELECT hub_address_id FROM transfer_dcs WHERE dc_id = ?`, r.dcID).Scan(&hubID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("transfer DC %d: %w", r.dcID, err)
+	}
+	hub, err := b.dcAddress(r.dcID, models.SnapshotRoleHub, hubID)
+	if err != nil {
+		return err
+	}
+	b.add(fmt.Sprintf("dc:%d", r.dcID), b.dcEvent(models.SerialEventTransfer, r,
+		fmt.Sprintf("Sent to hub on Transfer DC %s", r.dcNumber), hub, redispatch))
+	b.t.HubAddress = hub
+	return nil
+}
+
+// addShipment adds a serial on the transit DC of a shipment group: the
+// transfer DC and split it came through, if any, the transit DC, and the
+// official DCs of the shipment that carry its product.
+func (b *timelineBuilder) addShipment(r serialDCRow, redispatch bool) error {
+	t := b.t
+
+	var splitID, splitNumber, parentDCID, hubID sql.NullInt64
+	var splitCreated, parentNumber, parentStatus, parentDate sql.NullString
+	err := DB.QueryRowContext(ctx(),
+		`SELECT s.id, s.split_number, COALESCE(s.created_at, ''), pdc.id, pdc.dc_number, pdc.status,
+		        COALESCE(pdc.challan_date, pdc.created_at, ''), td.hub_address_id
+		 FROM shipment_groups sg
+		 LEFT JOIN transfer_dc_splits s ON s.id = sg.split_id
+		 LEFT JOIN transfer_dcs td ON td.id = s.transfer_dc_id
+		 LEFT JOIN delivery_challans pdc ON pdc.id = td.dc_id
+		 WHERE sg.id = ?`, *r.groupID,
+	).Scan(&splitID, &splitNumber, &splitCreated, &parentDCID, &parentNumber, &parentStatus, &parentDate, &hubID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("shipment group %d: %w", *r.groupID, err)
+	}
+	if parentDCID.Valid {
+		hub, err := b.dcAddress(int(parentDCID.Int64), models.SnapshotRoleHub, int(hubID.Int64))
+		if err != nil {
+			return err
+		}
+		parent := serialDCRow{
+			dcID:     int(parentDCID.Int64),
+			dcNumber: parentNumber.String,
+			dcType:   "transfer",
+			status:   parentStatus.String,
+			date:     timelineDate(parentDate.String),
+		}
+		b.add(fmt.Sprintf("dc:%d", parent.dcID), b.dcEvent(models.SerialEventTransfer, parent,
+			fmt.Sprintf("Sent to hub on Transfer DC %s", parent.dcNumber), hub, redispatch))
+		redispatch = false
+		b.add(fmt.Sprintf("split:%d", splitID.Int64), models.SerialTimelineEvent{
+			Kind:     models.SerialEventSplit,
+			Date:     firstDate(timelineDate(splitCreated.String), r.date),
+			Title:    fmt.Sprintf("Allotted to split #%d of Transfer DC %s", splitNumber.Int64, parent.dcNumber),
+			DCID:     intPtrOrNil(parent.dcID),
+			DCNumber: parent.dcNumber,
+			DCType:   parent.dcType,
+			DCStatus: parent.status,
+			Address:  hub,
+		})
+		t.HubAddress = hub
+	}
+
+	shipTo, err := b.dcAddress(r.dcID, models.SnapshotRoleShipTo, r.shipToAddressID)
+	if err != nil {
+		return err
+	}
+	b.add(fmt.Sprintf("dc:%d", r.dcID), b.dcEvent(models.SerialEventTransit, r,
+		fmt.Sprintf("Shipped on Transit DC %s", r.dcNumber), shipTo, redispatch))
+	if err := b.addDeliveries(r, shipTo); err != nil {
+		return err
+	}
+
+	officials, err := b.officialDCs(*r.groupID)
+	if err != nil {
+		return err
+	}
+	for _, o := range officials {
+		address, err := b.dcAddress(o.dcID, models.SnapshotRoleShipTo, o.shipToAddressID)
+		if err != nil {
+			return err
+		}
+		e := b.dcEvent(models.SerialEventOfficial, o, fmt.Sprintf("Covered by Official DC %s", o.dcNumber), address, false)
+		if len(officials) > 1 {
+			e.Detail = fmt.Sprintf("One of %d official DCs in this shipment carrying %s", len(officials), t.ItemName)
+		}
+		b.add(fmt.Sprintf("dc:%d", o.dcID), e)
+		if err := b.addDeliveries(o, address); err != nil {
+			return err
+		}
+	}
+	if !r.released {
+		t.ShipToAddress = shipTo
+		if len(officials) == 1 {
+			t.ShipToAddress, _ = b.dcAddress(officials[0].dcID, models.SnapshotRoleShipTo, officials[0].shipToAddressID)
+		}
+	}
+	return nil
+}
+
+// officialDCs lists the official DCs of a shipment group with a quantity of
+// the timeline's product.
+func (b *timelineBuilder) officialDCs(groupID int) ([]serialDCRow, error) {
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT DISTINCT dc.id, dc.dc_number, dc.status, COALESCE(dc.challan_date, dc.created_at, ''), dc.ship_to_address_id
+		 FROM delivery_challans dc
+		 INNER JOIN dc_line_items li ON li.dc_id = dc.id
+		 WHERE dc.shipment_group_id = ? AND dc.dc_type = 'official' AND li.product_id = ? AND li.quantity > 0
+		 ORDER BY dc.id`,
+		groupID, b.t.ProductID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("official DCs: %w", err)
+	}
+	defer rows.Close()
+	var out []serialDCRow
+	for rows.Next() {
+		r := serialDCRow{dcType: "official"}
+		if err := rows.Scan(&r.dcID, &r.dcNumber, &r.status, &r.date, &r.shipToAddressID); err != nil {
+			return nil, fmt.Errorf("official DCs: %w", err)
+		}
+		r.date = timelineDate(r.date)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// addDeliveries adds the proofs of delivery of a DC and any goods receipt on
+// it that reported the serial damaged or missing.
+func (b *timelineBuilder) addDeliveries(dc serialDCRow, address string) error {
+	dcID := dc.dcID
+	type pod struct {
+		id                   int
+		date, receiver, role string
+		partial              bool
+	}
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT id, COALESCE(received_date, ''), receiver_name, receiver_designation, is_partial
+		 FROM dc_proofs_of_delivery WHERE dc_id = ? ORDER BY id`, dcID)
+	if err != nil {
+		return fmt.Errorf("deliveries of DC %d: %w", dcID, err)
+	}
+	var pods []pod
+	for rows.Next() {
+		var p pod
+		if err := rows.Scan(&p.id, &p.date, &p.receiver, &p.role, &p.partial); err != nil {
+			rows.Close()
+			return fmt.Errorf("deliveries of DC %d: %w", dcID, err)
+		}
+		pods = append(pods, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("deliveries of DC %d: %w", dcID, err)
+	}
+	for _, p := range pods {
+		detail := "Received by " + p.receiver
+		if p.role != "" {
+			detail += ", " + p.role
+		}
+		title := "Delivered"
+		if p.partial {
+			title = "Partially delivered"
+		}
+		b.add(fmt.Sprintf("pod:%d", p.id), models.SerialTimelineEvent{
+			Kind:     models.SerialEventDelivered,
+			Date:     timelineDate(p.date),
+			Title:    title,
+			Detail:   detail,
+			DCID:     intPtrOrNil(dcID),
+			DCNumber: dc.dcNumber,
+			DCType:   dc.dcType,
+			DCStatus: dc.status,
+			Address:  address,
+		})
+	}
+
+	var receivedDate, condition string
+	err = DB.QueryRowContext(ctx(),
+		`SELECT COALESCE(r.received_date, ''), rs.condition
+		 FROM dc_receipt_serials rs
+		 INNER JOIN dc_receipts r ON r.id = rs.receipt_id
+		 INNER JOIN dc_line_items li ON li.id = rs.line_item_id
+		 WHERE r.dc_id = ? AND rs.serial_number = ? AND li.product_id = ?`,
+		dcID, b.t.SerialNumber, b.t.ProductID,
+	).Scan(&receivedDate, &condition)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return fmt.Errorf("goods receipt of DC %d: %w", dcID, err)
+	default:
+		b.add(fmt.Sprintf("receipt:%d", dcID), models.SerialTimelineEvent{
+			Kind:     models.SerialEventReceipt,
+			Date:     timelineDate(receivedDate),
+			Title:    "Reported " + condition + " on goods receipt",
+			DCID:     intPtrOrNil(dcID),
+			DCNumber: dc.dcNumber,
+			DCType:   dc.dcType,
+			DCStatus: dc.status,
+			Address:  address,
+		})
+	}
+	return nil
+}
+
+// addReturns adds the return DC that took back a serial_numbers row, and
+// reports whether there was one.
+func (b *timelineBuilder) addReturns(serialID int) (bool, error) {
+	var r serialDCRow
+	var reason, remarks string
+	err := DB.QueryRowContext(ctx(),
+		`SELECT dc.id, dc.dc_number, dc.status, COALESCE(dc.challan_date, dc.created_at, ''),
+		        dc.ship_to_address_id, rd.reason, rd.remarks
+		 FROM return_dc_serials rs
+		 INNER JOIN return_dcs rd ON rd.id = rs.return_dc_id
+		 INNER JOIN delivery_challans dc ON dc.id = rd.dc_id
+		 WHERE rs.serial_number_id = ?`, serialID,
+	).Scan(&r.dcID, &r.dcNumber, &r.status, &r.date, &r.shipToAddressID, &reason, &remarks)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("return of serial %d: %w", serialID, err)
+	}
+	r.dcType = "return"
+	r.date = timelineDate(r.date)
+	address, err := b.dcAddress(r.dcID, models.SnapshotRoleShipTo, r.shipToAddressID)
+	if err != nil {
+		return false, err
+	}
+	e := b.dcEvent(models.SerialEventReturned, r, fmt.Sprintf("Returned on Return DC %s", r.dcNumber), address, false)
+	e.Detail = "Reason: " + models.ReturnReasonLabel(reason)
+	if remarks != "" {
+		e.Detail += ". " + remarks
+	}
+	b.add(fmt.Sprintf("dc:%d", r.dcID), e)
+	return true, nil
+}
+
+// dcEvent builds the event of a DC carrying the serial.
+func (b *timelineBuilder) dcEvent(kind string, r serialDCRow, title, address string, redispatch bool) models.SerialTimelineEvent {
+	e := models.SerialTimelineEvent{
+		Kind:     kind,
+		Date:     r.date,
+		Title:    title,
+		DCID:     intPtrOrNil(r.dcID),
+		DCNumber: r.dcNumber,
+		DCType:   r.dcType,
+		DCStatus: r.status,
+		Address:  address,
+	}
+	if redispatch {
+		e.Detail = "Dispatched again after a return"
+	}
+	return e
+}
+
+// add appends an event unless one with the same key was already added.
+func (b *timelineBuilder) add(key string, e models.SerialTimelineEvent) {
+	if b.seen[key] {
+		return
+	}
+	b.seen[key] = true
+	b.t.Events = append(b.t.Events, e)
+}
+
+// dcAddress returns the display name of a DC's address in a snapshot role:
+// the address as it was when the DC was issued, or the live address for
+// drafts and for DCs issued before snapshots were recorded.
+func (b *timelineBuilder) dcAddress(dcID int, role string, id int) (string, error) {
+	snap, ok := b.snapshots[dcID]
+	if !ok {
+		var err error
+		if snap, err = GetDCSnapshot(dcID); err != nil {
+			return "", err
+		}
+		b.snapshots[dcID] = snap
+	}
+	if snap != nil {
+		if a := snap.Address(role); a != nil {
+			return a.DisplayName(), nil
+		}
+	}
+	return b.address(id)
+}
+
+// address returns the live display name of an address, "" when it does not exist.
+func (b *timelineBuilder) address(id int) (string, error) {
+	if id == 0 {
+		return "", nil
+	}
+	if s, ok := b.addresses[id]; ok {
+		return s, nil
+	}
+	a := &models.Address{ID: id}
+	err := DB.QueryRowContext(ctx(),
+		`SELECT address_data, district_name, mandal_name FROM addresses WHERE id = ?`, id,
+	).Scan(&a.DataJSON, &a.DistrictName, &a.MandalName)
+	if err == sql.ErrNoRows {
+		b.addresses[id] = ""
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("address %d: %w", id, err)
+	}
+	if err := a.ParseData(); err != nil {
+		return "", fmt.Errorf("address %d: %w", id, err)
+	}
+	b.addresses[id] = a.DisplayName()
+	return b.addresses[id], nil
+}
+
+// timelineDate trims a stored date or timestamp to YYYY-MM-DD.
+func timelineDate(s string) string {
+	if len(s) >= 10 {
+		return s[:10]
+	}
+	return s
+}
+
+// firstDate returns the first non-empty date.
+func firstDate(dates ...string) string {
+	for _, d := range dates {
+		if d != "" {
+			return d
+		}
+	}
+	return ""
+}
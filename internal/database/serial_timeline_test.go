@@ -0,0 +1,130 @@
+package database
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupSerialTimelineTestDB(t *testing.T) {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:serial_timeline_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		`CREATE TABLE products (id INTEGER PRIMARY KEY, item_name TEXT NOT NULL)`,
+		`CREATE TABLE addresses (id INTEGER PRIMARY KEY, address_data TEXT NOT NULL,
+			district_name TEXT NOT NULL DEFAULT '', mandal_name TEXT NOT NULL DEFAULT '')`,
+		`CREATE TABLE delivery_challans (
+			id INTEGER PRIMARY KEY, dc_number TEXT NOT NULL, dc_type TEXT NOT NULL, status TEXT NOT NULL,
+			challan_date DATE, ship_to_address_id INTEGER NOT NULL, shipment_group_id INTEGER,
+			cancelled_at DATETIME, cancellation_reason TEXT, created_at DATETIME DEFAULT CURRENT_TIMESTAMP)`,
+		`CREATE TABLE dc_line_items (id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL, product_id INTEGER NOT NULL, quantity INTEGER NOT NULL)`,
+		`CREATE TABLE serial_numbers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, project_id INTEGER NOT NULL, line_item_id INTEGER NOT NULL,
+			product_id INTEGER, serial_number TEXT NOT NULL, released_at DATETIME)`,
+		`CREATE TABLE transfer_dcs (id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL, hub_address_id INTEGER NOT NULL)`,
+		`CREATE TABLE transfer_dc_splits (id INTEGER PRIMARY KEY, transfer_dc_id INTEGER NOT NULL,
+			shipment_group_id INTEGER NOT NULL, split_number INTEGER NOT NULL, created_at DATETIME)`,
+		`CREATE TABLE shipment_groups (id INTEGER PRIMARY KEY, split_id INTEGER)`,
+		`CREATE TABLE dc_proofs_of_delivery (id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL, received_date DATE NOT NULL,
+			receiver_name TEXT NOT NULL, receiver_designation TEXT NOT NULL DEFAULT '', is_partial INTEGER NOT NULL DEFAULT 0)`,
+		`CREATE TABLE dc_snapshots (dc_id INTEGER PRIMARY KEY, snapshot_json TEXT NOT NULL, captured_at DATETIME)`,
+		`CREATE TABLE dc_receipts (id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL, received_date DATE NOT NULL)`,
+		`CREATE TABLE dc_receipt_serials (id INTEGER PRIMARY KEY, receipt_id INTEGER NOT NULL,
+			line_item_id INTEGER NOT NULL, serial_number TEXT NOT NULL, condition TEXT NOT NULL)`,
+		`CREATE TABLE return_dcs (id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL, original_dc_id INTEGER NOT NULL,
+			reason TEXT NOT NULL, remarks TEXT NOT NULL DEFAULT '')`,
+		`CREATE TABLE return_dc_serials (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, return_dc_id INTEGER NOT NULL, serial_number_id INTEGER NOT NULL,
+			line_item_id INTEGER NOT NULL, product_id INTEGER, serial_number TEXT NOT NULL)`,
+		`CREATE TABLE serial_registry (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, project_id INTEGER NOT NULL, product_id INTEGER NOT NULL,
+			serial_number TEXT NOT NULL, condition TEXT NOT NULL DEFAULT 'good', source TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP)`,
+
+		`INSERT INTO products (id, item_name) VALUES (10, 'Router')`,
+		`INSERT INTO addresses (id, address_data, district_name) VALUES
+			(1, '{"Name":"Vijayawada Hub"}', ''), (2, '{"Name":"Guntur Depot"}', ''),
+			(3, '{"Name":"Tenali School"}', 'Guntur'), (4, '{"Name":"Ponnur School"}', 'Guntur')`,
+		// Transfer DC to the hub, split into a shipment with one transit and one official DC.
+		`INSERT INTO delivery_challans (id, dc_number, dc_type, status, challan_date, ship_to_address_id, shipment_group_id) VALUES
+			(100, 'TRF-001', 'transfer', 'split', '2025-04-01', 1, NULL),
+			(101, 'TDC-001', 'transit', 'issued', '2025-04-05', 2, 5),
+			(102, 'ODC-001', 'official', 'delivered', '2025-04-05', 3, 5),
+			(103, 'RET-001', 'return', 'issued', '2025-05-10', 3, NULL),
+			(104, 'ODC-002', 'official', 'issued', '2025-05-20', 4, NULL)`,
+		`INSERT INTO transfer_dcs (id, dc_id, hub_address_id) VALUES (7, 100, 1)`,
+		`INSERT INTO transfer_dc_splits (id, transfer_dc_id, shipment_group_id, split_number, created_at) VALUES (8, 7, 5, 1, '2025-04-04 10:00:00')`,
+		`INSERT INTO shipment_groups (id, split_id) VALUES (5, 8)`,
+		`INSERT INTO dc_line_items (id, dc_id, product_id, quantity) VALUES (1001, 101, 10, 1), (1002, 102, 10, 1), (1004, 104, 10, 1)`,
+		`INSERT INTO dc_proofs_of_delivery (id, dc_id, received_date, receiver_name) VALUES (1, 102, '2025-04-08', 'Headmaster')`,
+		// Returned faulty, then dispatched again on another official DC.
+		`INSERT INTO serial_numbers (id, project_id, line_item_id, product_id, serial_number, released_at) VALUES (50, 1, 1001, 10, 'SN1', '2025-05-10')`,
+		`INSERT INTO return_dcs (id, dc_id, original_dc_id, reason) VALUES (3, 103, 101, 'faulty')`,
+		`INSERT INTO return_dc_serials (return_dc_id, serial_number_id, line_item_id, product_id, serial_number) VALUES (3, 50, 1001, 10, 'SN1')`,
+		`INSERT INTO serial_numbers (id, project_id, line_item_id, product_id, serial_number) VALUES (51, 1, 1004, 10, 'SN1')`,
+		// ODC-002 was issued before its school was renamed; the timeline keeps the issued address.
+		`INSERT INTO dc_snapshots (dc_id, snapshot_json) VALUES (104,
+			'{"addresses":{"ship_to":{"id":4,"district_name":"Guntur","data":{"Name":"ZPHS Ponnur"}}}}')`,
+		`UPDATE addresses SET address_data = '{"Name":"Ponnur High School"}' WHERE id = 4`,
+		`INSERT INTO serial_registry (project_id, product_id, serial_number, source, created_at) VALUES (1, 10, 'SN1', 'PL-001', '2025-03-28 09:00:00')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	DB = db
+}
+
+func TestGetSerialTimelines(t *testing.T) {
+	setupSerialTimelineTestDB(t)
+
+	timelines, err := GetSerialTimelines(1, " SN1 ")
+	if err != nil {
+		t.Fatalf("GetSerialTimelines: %v", err)
+	}
+	if len(timelines) != 1 {
+		t.Fatalf("got %d timelines, want 1", len(timelines))
+	}
+	tl := timelines[0]
+
+	type step struct{ kind, date, dc, address string }
+	var got []step
+	for _, e := range tl.Events {
+		got = append(got, step{e.Kind, e.Date, e.DCNumber, e.Address})
+	}
+	want := []step{
+		{models.SerialEventStockedIn, "2025-03-28", "", ""},
+		{models.SerialEventTransfer, "2025-04-01", "TRF-001", "Vijayawada Hub"},
+		{models.SerialEventSplit, "2025-04-04", "TRF-001", "Vijayawada Hub"},
+		{models.SerialEventTransit, "2025-04-05", "TDC-001", "Guntur Depot"},
+		{models.SerialEventOfficial, "2025-04-05", "ODC-001", "Guntur | Tenali School"},
+		{models.SerialEventDelivered, "2025-04-08", "ODC-001", "Guntur | Tenali School"},
+		{models.SerialEventReturned, "2025-05-10", "RET-001", "Guntur | Tenali School"},
+		{models.SerialEventDispatched, "2025-05-20", "ODC-002", "Guntur | ZPHS Ponnur"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events =\n%v\nwant\n%v", got, want)
+	}
+	if last := tl.Events[len(tl.Events)-1]; last.Detail != "Dispatched again after a return" {
+		t.Errorf("re-dispatch detail = %q", last.Detail)
+	}
+	if tl.CurrentDC != "ODC-002" || tl.HubAddress != "Vijayawada Hub" || tl.ShipToAddress != "Guntur | ZPHS Ponnur" {
+		t.Errorf("summary = %q / %q / %q", tl.CurrentDC, tl.HubAddress, tl.ShipToAddress)
+	}
+
+	if timelines, _ := GetSerialTimelines(1, "SN9"); timelines != nil {
+		t.Errorf("unknown serial: got %d timelines, want none", len(timelines))
+	}
+	if tl, _ := GetSerialTimeline(1, 11, "SN1"); tl != nil {
+		t.Error("timeline of a product the serial is not recorded against should be nil")
+	}
+}
@@ -162,10 +162,33 @@ func GetShipmentGroupsByProjectID(projectID int) ([]*models.ShipmentGroup, error
 	return groups, nil
 }
 
-// IssueAllDCsInGroup issues all draft DCs in a shipment group.
+// IssueAllDCsInGroup issues all draft DCs in a shipment group and freezes their print snapshots.
 func IssueAllDCsInGroup(groupID int, issuedBy int) (int, error) {
+	dcs, err := GetDCsByShipmentGroup(groupID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to issue DCs in group: %w", err)
+	}
+	// Snapshot before opening the transaction: the pool has a single connection.
+	snapshots := make(map[int]*models.DCSnapshot)
+	for _, dc := range dcs {
+		if !models.IsIssuableStatus(dc.Status) {
+			continue
+		}
+		snap, err := BuildDCSnapshot(dc)
+		if err != nil {
+			return 0, fmt.Errorf("failed to issue DCs in group: %w", err)
+		}
+		snapshots[dc.ID] = snap
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
 	now := time.Now()
-	result, err := queries().IssueAllDCsInGroup(ctx(), db.IssueAllDCsInGroupParams{
+	result, err := db.New(tx).IssueAllDCsInGroup(ctx(), db.IssueAllDCsInGroupParams{
 		IssuedAt:        sql.NullTime{Time: now, Valid: true},
 		IssuedBy:        sql.NullInt64{Int64: int64(issuedBy), Valid: true},
 		UpdatedAt:       sql.NullTime{Time: now, Valid: true},
@@ -174,6 +197,14 @@ func IssueAllDCsInGroup(groupID int, issuedBy int) (int, error) {
 	if err != nil {
 		return 0, fmt.Errorf("failed to issue DCs in group: %w", err)
 	}
+	for dcID, snap := range snapshots {
+		if err := saveDCSnapshot(tx, dcID, snap); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
 	count, _ := result.RowsAffected()
 	return int(count), nil
 }
@@ -181,7 +212,12 @@ func IssueAllDCsInGroup(groupID int, issuedBy int) (int, error) {
 // UpdateShipmentGroup updates mutable fields on an existing draft shipment group.
 // Only call this while the group is still in "draft" status.
 func UpdateShipmentGroup(groupID int, templateID *int, numLocations int, taxType, reverseCharge string) error {
-	_, err := DB.ExecContext(ctx(),
+	return updateShipmentGroup(DB, groupID, templateID, numLocations, taxType, reverseCharge)
+}
+
+// updateShipmentGroup is UpdateShipmentGroup against q.
+func updateShipmentGroup(q db.DBTX, groupID int, templateID *int, numLocations int, taxType, reverseCharge string) error {
+	_, err := q.ExecContext(ctx(),
 		`UPDATE shipment_groups
 		    SET template_id = ?, num_sets = ?, tax_type = ?, reverse_charge = ?, updated_at = CURRENT_TIMESTAMP
 		  WHERE id = ? AND status = 'draft'`,
@@ -193,6 +229,105 @@ func UpdateShipmentGroup(groupID int, templateID *int, numLocations int, taxType
 	return nil
 }
 
+// KitComponentSerials are the serials of one component of a kit line.
+type KitComponentSerials struct {
+	KitProductID       int
+	ComponentProductID int
+	Serials            []string
+}
+
+// OfficialDCEdit is one official DC of an edited shipment group. DCID is 0 for
+// a newly added ship-to address.
+type OfficialDCEdit struct {
+	DCID            int
+	ShipToAddressID int
+	LineItems       []models.DCLineItem
+}
+
+// ShipmentGroupEdit is the full edit of a draft shipment group as saved by
+// SaveShipmentGroupEdit.
+type ShipmentGroupEdit struct {
+	GroupID       int
+	ProjectID     int
+	Version       int // edit version the wizard was opened with
+	TemplateID    *int
+	NumLocations  int
+	TaxType       string
+	ReverseCharge string
+	ChallanDate   *string
+	CreatedBy     int
+
+	TransitDCID      int
+	TransporterName  string
+	VehicleNumber    string
+	EwayBillNumber   string
+	DocketNumber     string
+	TransitLineItems []models.DCLineItem
+	TransitSerials   [][]string
+	KitSerials       []KitComponentSerials
+
+	Officials   []OfficialDCEdit
+	DeleteDCIDs []int
+}
+
+// SaveShipmentGroupEdit writes an edited draft shipment group in one
+// transaction: the group, its transit DC with serials, and its official DCs
+// (updated, removed or added). It returns ErrStaleVersion, writing nothing, if
+// the group is no longer at e.Version.
+func SaveShipmentGroupEdit(e ShipmentGroupEdit) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("SaveShipmentGroupEdit: begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := claimEditVersion(tx, models.AuditEntityShipmentGroup, e.GroupID, e.Version); err != nil {
+		return err
+	}
+	if err := updateShipmentGroup(tx, e.GroupID, e.TemplateID, e.NumLocations, e.TaxType, e.ReverseCharge); err != nil {
+		return err
+	}
+	if err := updateTransitDC(tx, e.TransitDCID, e.ChallanDate, e.TransporterName, e.VehicleNumber, e.EwayBillNumber, e.DocketNumber); err != nil {
+		return err
+	}
+	if err := replaceLineItemsAndSerials(tx, e.TransitDCID, e.ProjectID, e.TransitLineItems, e.TransitSerials); err != nil {
+		return err
+	}
+	// Kit lines were written without serials; add each component's serials to them.
+	for _, k := range e.KitSerials {
+		if err := insertKitComponentSerials(tx, e.TransitDCID, e.ProjectID, k.KitProductID, k.ComponentProductID, k.Serials); err != nil {
+			return err
+		}
+	}
+
+	for _, o := range e.Officials {
+		if o.DCID == 0 {
+			continue
+		}
+		if err := updateOfficialDC(tx, o.DCID, o.ShipToAddressID, e.ChallanDate); err != nil {
+			return err
+		}
+		if err := replaceLineItemsAndSerials(tx, o.DCID, e.ProjectID, o.LineItems, nil); err != nil {
+			return err
+		}
+	}
+	for _, dcID := range e.DeleteDCIDs {
+		if err := deleteDC(tx, dcID); err != nil {
+			return err
+		}
+	}
+	for _, o := range e.Officials {
+		if o.DCID != 0 {
+			continue
+		}
+		if _, err := createOfficialDCInGroup(tx, e.ProjectID, e.GroupID, o.ShipToAddressID, e.ChallanDate, o.LineItems, nil, e.CreatedBy); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // DeleteShipmentGroup deletes a shipment group and all its DCs, line items, and serial numbers.
 func DeleteShipmentGroup(groupID int) error {
 	tx, err := DB.Begin()
@@ -243,13 +378,15 @@ func DeleteShipmentGroup(groupID int) error {
 }
 
 // GetIssuedShipToAddressIDs returns ship-to address IDs used in issued shipment groups for a project.
+// Addresses whose official DC has been cancelled are no longer locked.
 func GetIssuedShipToAddressIDs(projectID int) ([]int, error) {
 	rows, err := DB.QueryContext(ctx(),
 		`SELECT DISTINCT dc.ship_to_address_id
 		 FROM delivery_challans dc
 		 INNER JOIN shipment_groups sg ON dc.shipment_group_id = sg.id
 		 WHERE sg.project_id = ? AND sg.status = 'issued'
-		   AND dc.dc_type = 'official' AND dc.ship_to_address_id IS NOT NULL`,
+		   AND dc.dc_type = 'official' AND dc.status != 'cancelled'
+		   AND dc.ship_to_address_id IS NOT NULL`,
 		projectID)
 	if err != nil {
 		return nil, fmt.Errorf("GetIssuedShipToAddressIDs: %w", err)
@@ -748,7 +748,7 @@ SET status     = 'issued',
     issued_by  = ?,
     updated_at = ?
 WHERE id     = ?
-  AND status = 'draft'
+  AND status IN ('draft', 'pending_approval')
 `
 
 type IssueDCParams struct {
@@ -13,7 +13,7 @@ import (
 const CountIssuedDCsForProject = `-- name: CountIssuedDCsForProject :one
 SELECT COUNT(*)
 FROM delivery_challans
-WHERE project_id = ? AND status = 'issued'
+WHERE project_id = ? AND status IN ('issued', 'delivered', 'partially_delivered')
 `
 
 func (q *Queries) CountIssuedDCsForProject(ctx context.Context, projectID int64) (int64, error) {
@@ -307,7 +307,7 @@ SET status     = 'issued',
     issued_by  = ?,
     updated_at = ?
 WHERE shipment_group_id = ?
-  AND status            = 'draft'
+  AND status            IN ('draft', 'pending_approval')
 `
 
 type IssueAllDCsInGroupParams struct {
@@ -317,7 +317,7 @@ type IssueAllDCsInGroupParams struct {
 	ShipmentGroupID sql.NullInt64
 }
 
-// Issues every draft DC in a shipment group atomically.
+// Issues every draft or approval-pending DC in a shipment group atomically.
 func (q *Queries) IssueAllDCsInGroup(ctx context.Context, arg IssueAllDCsInGroupParams) (sql.Result, error) {
 	return q.db.ExecContext(ctx, IssueAllDCsInGroup,
 		arg.IssuedAt,
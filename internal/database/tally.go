@@ -0,0 +1,69 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// GetTallySettings returns a project's Tally master names, or Tally's defaults
+// when the project has not mapped them.
+// Hand-written SQL: tally_settings is not part of the sqlc queries.
+func GetTallySettings(projectID int) (*models.TallySettings, error) {
+	s := &models.TallySettings{ProjectID: projectID}
+	err := DB.QueryRowContext(ctx(), `
+		SELECT company_name, voucher_type, sales_ledger, cgst_ledger, sgst_ledger,
+		       igst_ledger, party_group, godown, include_masters
+		FROM tally_settings WHERE project_id = ?`, projectID,
+	).Scan(&s.CompanyName, &s.VoucherType, &s.SalesLedger, &s.CGSTLedger, &s.SGSTLedger,
+		&s.IGSTLedger, &s.PartyGroup, &s.Godown, &s.IncludeMasters)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DefaultTallySettings(projectID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetTallySettings: %w", err)
+	}
+	return s, nil
+}
+
+// SaveTallySettings stores a project's Tally master names.
+func SaveTallySettings(s *models.TallySettings) error {
+	if _, err := DB.ExecContext(ctx(), `
+		INSERT INTO tally_settings (project_id, company_name, voucher_type, sales_ledger, cgst_ledger,
+			sgst_ledger, igst_ledger, party_group, godown, include_masters, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(project_id) DO UPDATE SET
+			company_name = excluded.company_name,
+			voucher_type = excluded.voucher_type,
+			sales_ledger = excluded.sales_ledger,
+			cgst_ledger = excluded.cgst_ledger,
+			sgst_ledger = excluded.sgst_ledger,
+			igst_ledger = excluded.igst_ledger,
+			party_group = excluded.party_group,
+			godown = excluded.godown,
+			include_masters = excluded.include_masters,
+			updated_at = CURRENT_TIMESTAMP`,
+		s.ProjectID, s.CompanyName, s.VoucherType, s.SalesLedger, s.CGSTLedger,
+		s.SGSTLedger, s.IGSTLedger, s.PartyGroup, s.Godown, s.IncludeMasters,
+	); err != nil {
+		return fmt.Errorf("SaveTallySettings: %w", err)
+	}
+	return nil
+}
+
+// IsTransferSplitDC reports whether a DC belongs to a shipment group split from
+// a Transfer DC.
+// Hand-written SQL: no sqlc query joins a DC to its group's Transfer DC.
+func IsTransferSplitDC(dcID int) (bool, error) {
+	var n int
+	if err := DB.QueryRowContext(ctx(), `
+		SELECT COUNT(*) FROM delivery_challans dc
+		INNER JOIN shipment_groups sg ON sg.id = dc.shipment_group_id
+		WHERE dc.id = ? AND sg.transfer_dc_id IS NOT NULL`, dcID,
+	).Scan(&n); err != nil {
+		return false, fmt.Errorf("IsTransferSplitDC: %w", err)
+	}
+	return n > 0, nil
+}
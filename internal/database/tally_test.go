@@ -0,0 +1,95 @@
+package database
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestTallySettings(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:tally_settings_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`CREATE TABLE tally_settings (
+		project_id      INTEGER PRIMARY KEY,
+		company_name    TEXT NOT NULL DEFAULT '',
+		voucher_type    TEXT NOT NULL DEFAULT 'Delivery Note',
+		sales_ledger    TEXT NOT NULL DEFAULT 'Sales',
+		cgst_ledger     TEXT NOT NULL DEFAULT 'Output CGST',
+		sgst_ledger     TEXT NOT NULL DEFAULT 'Output SGST',
+		igst_ledger     TEXT NOT NULL DEFAULT 'Output IGST',
+		party_group     TEXT NOT NULL DEFAULT 'Sundry Debtors',
+		godown          TEXT NOT NULL DEFAULT 'Main Location',
+		include_masters INTEGER NOT NULL DEFAULT 1,
+		updated_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	DB = db
+
+	got, err := GetTallySettings(1)
+	if err != nil {
+		t.Fatalf("GetTallySettings: %v", err)
+	}
+	if want := models.DefaultTallySettings(1); !reflect.DeepEqual(got, want) {
+		t.Errorf("unmapped project = %+v; want defaults %+v", got, want)
+	}
+
+	s := models.DefaultTallySettings(1)
+	s.CompanyName = "Acme Infra Pvt Ltd"
+	s.SalesLedger = "Project Sales"
+	s.Godown = "Vijayawada Stores"
+	s.IncludeMasters = false
+	if err := SaveTallySettings(s); err != nil {
+		t.Fatalf("SaveTallySettings: %v", err)
+	}
+	s.IGSTLedger = "IGST Payable"
+	if err := SaveTallySettings(s); err != nil {
+		t.Fatalf("SaveTallySettings again: %v", err)
+	}
+
+	got, err = GetTallySettings(1)
+	if err != nil {
+		t.Fatalf("GetTallySettings after save: %v", err)
+	}
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("saved settings = %+v; want %+v", got, s)
+	}
+}
+
+func TestIsTransferSplitDC(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:tally_split_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		`CREATE TABLE shipment_groups (id INTEGER PRIMARY KEY, transfer_dc_id INTEGER)`,
+		`CREATE TABLE delivery_challans (id INTEGER PRIMARY KEY, shipment_group_id INTEGER)`,
+		`INSERT INTO shipment_groups (id, transfer_dc_id) VALUES (1, NULL), (2, 5)`,
+		`INSERT INTO delivery_challans (id, shipment_group_id) VALUES (10, 1), (11, 2), (12, NULL)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	DB = db
+
+	for dcID, want := range map[int]bool{10: false, 11: true, 12: false} {
+		got, err := IsTransferSplitDC(dcID)
+		if err != nil {
+			t.Fatalf("IsTransferSplitDC(%d): %v", dcID, err)
+		}
+		if got != want {
+			t.Errorf("IsTransferSplitDC(%d) = %v, want %v", dcID, got, want)
+		}
+	}
+}
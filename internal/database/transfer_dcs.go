@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	db "github.com/narendhupati/dc-management-tool/internal/database/sqlc"
 	"github.com/narendhupati/dc-management-tool/internal/models"
 )
 
@@ -121,7 +122,12 @@ func scanTransferDC(row *sql.Row) (*models.TransferDC, error) {
 
 // UpdateTransferDC updates mutable fields on a transfer DC.
 func UpdateTransferDC(tdc *models.TransferDC) error {
-	_, err := DB.ExecContext(ctx(),
+	return updateTransferDC(DB, tdc)
+}
+
+// updateTransferDC is UpdateTransferDC against q.
+func updateTransferDC(q db.DBTX, tdc *models.TransferDC) error {
+	_, err := q.ExecContext(ctx(),
 		`UPDATE transfer_dcs SET
             hub_address_id = ?, template_id = ?, tax_type = ?, reverse_charge = ?,
             transporter_name = ?, vehicle_number = ?, eway_bill_number = ?,
@@ -149,6 +155,113 @@ func DeleteTransferDC(id int) error {
 	return nil
 }
 
+// TransferDCEdit is the full edit of a draft transfer DC as saved by
+// SaveTransferDCEdit.
+type TransferDCEdit struct {
+	TransferDC            *models.TransferDC // updated fields; ID and DCID identify it
+	ProjectID             int
+	Version               int // edit version the wizard was opened with
+	ChallanDate           *string
+	BillFromAddressID     int
+	DispatchFromAddressID int
+	BillToAddressID       int
+	LineItems             []models.DCLineItem
+	SerialsByLine         [][]string
+	// Destinations are the ship-to addresses in order with their quantities.
+	// Existing destinations not listed are removed; new ones are added.
+	Destinations []TransferDestinationEdit
+}
+
+// TransferDestinationEdit is one destination of an edited transfer DC.
+type TransferDestinationEdit struct {
+	ShipToAddressID int
+	Quantities      []models.TransferDCDestinationQty
+}
+
+// SaveTransferDCEdit writes an edited draft transfer DC in one transaction: the
+// transfer DC, its parent DC's addresses, line items and serials, and its
+// destinations with their quantities. It returns ErrStaleVersion, writing
+// nothing, if the transfer DC is no longer at e.Version.
+func SaveTransferDCEdit(e TransferDCEdit) error {
+	tdc := e.TransferDC
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("SaveTransferDCEdit: begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := claimEditVersion(tx, models.AuditEntityTransferDC, tdc.ID, e.Version); err != nil {
+		return err
+	}
+	if err := updateTransferDC(tx, tdc); err != nil {
+		return err
+	}
+	if err := updateDeliveryChallanAddressesAndDate(tx, tdc.DCID, e.ChallanDate,
+		e.BillFromAddressID, e.DispatchFromAddressID, e.BillToAddressID, tdc.HubAddressID); err != nil {
+		return err
+	}
+	if err := replaceLineItemsAndSerials(tx, tdc.DCID, e.ProjectID, e.LineItems, e.SerialsByLine); err != nil {
+		return err
+	}
+
+	// Reconcile destinations: drop removed ones, add new ones, then set every
+	// listed destination's quantities.
+	existing, err := destinationIDsByAddress(tx, tdc.ID)
+	if err != nil {
+		return err
+	}
+	listed := make(map[int]bool, len(e.Destinations))
+	var added []int
+	for _, d := range e.Destinations {
+		listed[d.ShipToAddressID] = true
+		if _, ok := existing[d.ShipToAddressID]; !ok {
+			added = append(added, d.ShipToAddressID)
+		}
+	}
+	for addrID, destID := range existing {
+		if !listed[addrID] {
+			if err := deleteTransferDCDestination(tx, destID); err != nil {
+				return err
+			}
+		}
+	}
+	if len(added) > 0 {
+		if err := addTransferDCDestinations(tx, tdc.ID, added); err != nil {
+			return err
+		}
+		if existing, err = destinationIDsByAddress(tx, tdc.ID); err != nil {
+			return err
+		}
+	}
+	for _, d := range e.Destinations {
+		if err := setDestinationQuantities(tx, existing[d.ShipToAddressID], d.Quantities); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// destinationIDsByAddress maps each ship-to address of a transfer DC to its
+// destination ID, using q.
+func destinationIDsByAddress(q db.DBTX, transferDCID int) (map[int]int, error) {
+	rows, err := q.QueryContext(ctx(),
+		`SELECT id, ship_to_address_id FROM transfer_dc_destinations WHERE transfer_dc_id = ?`, transferDCID)
+	if err != nil {
+		return nil, fmt.Errorf("destinationIDsByAddress: %w", err)
+	}
+	defer rows.Close()
+	out := map[int]int{}
+	for rows.Next() {
+		var id, addrID int
+		if err := rows.Scan(&id, &addrID); err != nil {
+			return nil, fmt.Errorf("destinationIDsByAddress: %w", err)
+		}
+		out[addrID] = id
+	}
+	return out, rows.Err()
+}
+
 // ============================================================
 // Destination Management
 // ============================================================
@@ -161,20 +274,27 @@ func DeleteTransferDCDestination(destID int) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	if err := deleteTransferDCDestination(tx, destID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteTransferDCDestination is DeleteTransferDCDestination against q.
+func deleteTransferDCDestination(q db.DBTX, destID int) error {
 	// Delete quantities first (may not cascade automatically depending on schema)
-	if _, err := tx.ExecContext(ctx(),
+	if _, err := q.ExecContext(ctx(),
 		`DELETE FROM transfer_dc_destination_quantities WHERE destination_id = ?`, destID,
 	); err != nil {
 		return fmt.Errorf("DeleteTransferDCDestination delete quantities: %w", err)
 	}
 
-	if _, err := tx.ExecContext(ctx(),
+	if _, err := q.ExecContext(ctx(),
 		`DELETE FROM transfer_dc_destinations WHERE id = ?`, destID,
 	); err != nil {
 		return fmt.Errorf("DeleteTransferDCDestination delete destination: %w", err)
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 // AddTransferDCDestinations inserts multiple destinations in a batch.
@@ -188,7 +308,15 @@ func AddTransferDCDestinations(transferDCID int, shipToAddressIDs []int) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	stmt, err := tx.Prepare(
+	if err := addTransferDCDestinations(tx, transferDCID, shipToAddressIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// addTransferDCDestinations is AddTransferDCDestinations against q.
+func addTransferDCDestinations(q db.DBTX, transferDCID int, shipToAddressIDs []int) error {
+	stmt, err := q.PrepareContext(ctx(),
 		`INSERT INTO transfer_dc_destinations (transfer_dc_id, ship_to_address_id) VALUES (?, ?)`)
 	if err != nil {
 		return fmt.Errorf("AddTransferDCDestinations prepare: %w", err)
@@ -202,7 +330,7 @@ func AddTransferDCDestinations(transferDCID int, shipToAddressIDs []int) error {
 	}
 
 	// Update num_destinations counter
-	if _, err := tx.Exec(
+	if _, err := q.ExecContext(ctx(),
 		`UPDATE transfer_dcs SET num_destinations = (
             SELECT COUNT(*) FROM transfer_dc_destinations WHERE transfer_dc_id = ?
         ), updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
@@ -210,8 +338,7 @@ func AddTransferDCDestinations(transferDCID int, shipToAddressIDs []int) error {
 	); err != nil {
 		return fmt.Errorf("AddTransferDCDestinations update count: %w", err)
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 // GetTransferDCDestinations retrieves all destinations for a transfer DC.
@@ -326,7 +453,15 @@ func SetDestinationQuantities(destinationID int, quantities []models.TransferDCD
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	stmt, err := tx.Prepare(
+	if err := setDestinationQuantities(tx, destinationID, quantities); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setDestinationQuantities is SetDestinationQuantities against dbtx.
+func setDestinationQuantities(dbtx db.DBTX, destinationID int, quantities []models.TransferDCDestinationQty) error {
+	stmt, err := dbtx.PrepareContext(ctx(),
 		`INSERT INTO transfer_dc_destination_quantities (destination_id, product_id, quantity)
          VALUES (?, ?, ?)
          ON CONFLICT (destination_id, product_id) DO UPDATE SET quantity = excluded.quantity`)
@@ -340,7 +475,7 @@ func SetDestinationQuantities(destinationID int, quantities []models.TransferDCD
 			return fmt.Errorf("SetDestinationQuantities upsert product %d: %w", q.ProductID, err)
 		}
 	}
-	return tx.Commit()
+	return nil
 }
 
 // GetDestinationQuantities retrieves quantities for a single destination with product info.
@@ -614,7 +749,7 @@ func CanDeleteSplit(splitID int) (bool, error) {
 
 	var issuedCount int
 	err = DB.QueryRowContext(ctx(),
-		`SELECT COUNT(*) FROM delivery_challans WHERE shipment_group_id = ? AND status = 'issued'`,
+		`SELECT COUNT(*) FROM delivery_challans WHERE shipment_group_id = ? AND status IN `+issuedStatusesSQL,
 		shipmentGroupID,
 	).Scan(&issuedCount)
 	if err != nil {
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// GetProjectWarrantyMonths returns the warranty period a project applies to
+// all its products; 0 means each product's own period applies.
+func GetProjectWarrantyMonths(projectID int) (int, error) {
+	var months int
+	err := DB.QueryRowContext(ctx(),
+		`SELECT warranty_months FROM projects WHERE id = ?`, projectID,
+	).Scan(&months)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("GetProjectWarrantyMonths: %w", err)
+	}
+	return months, nil
+}
+
+// SetProjectWarrantyMonths sets the warranty period a project applies to all
+// its products; 0 clears the override.
+func SetProjectWarrantyMonths(projectID, months int) error {
+	if _, err := DB.ExecContext(ctx(),
+		`UPDATE projects SET warranty_months = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		months, projectID,
+	); err != nil {
+		return fmt.Errorf("SetProjectWarrantyMonths: %w", err)
+	}
+	return nil
+}
+
+// warrantyDC is an issued DC a serial may have been delivered on.
+type warrantyDC struct {
+	number           string
+	groupID          int
+	addressID        int
+	dcType           string
+	challanDate      string
+	podDate          string // latest POD received date, if any
+	district, mandal string
+}
+
+// GetWarrantySerials returns every serial of a project that went out on an
+// issued DC, with its warranty. A serial on a shipment's transit DC is
+// delivered on the official DC to the destination it was assigned or scanned
+// for; when it has no destination and the shipment has a single official DC,
+// that DC is used, otherwise the transit DC itself. The warranty starts on the
+// delivering DC's latest POD date, or its challan date when no POD is recorded.
+// The serials of a kit carry the warranty of the component they were captured for.
+// Hand-written SQL: reads warranty_months and ship_to_address_id, which sqlc
+// does not cover, and joins the PODs of each DC.
+func GetWarrantySerials(projectID int) ([]models.WarrantySerial, error) {
+	projectMonths, err := GetProjectWarrantyMonths(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := DB.QueryContext(ctx(),
+		`SELECT dc.id, dc.dc_number, dc.dc_type, COALESCE(dc.shipment_group_id, 0), COALESCE(dc.ship_to_address_id, 0),
+		        COALESCE(dc.challan_date, ''),
+		        COALESCE((SELECT MAX(pod.received_date) FROM dc_proofs_of_delivery pod WHERE pod.dc_id = dc.id), ''),
+		        COALESCE(a.district_name, ''), COALESCE(a.mandal_name, '')
+		 FROM delivery_challans dc
+		 LEFT JOIN addresses a ON a.id = dc.ship_to_address_id
+		 WHERE dc.project_id = ? AND dc.status IN `+issuedStatusesSQL,
+		projectID)
+	if err != nil {
+		return nil, fmt.Errorf("GetWarrantySerials: %w", err)
+	}
+	dcs := make(map[int]*warrantyDC)
+	officials := make(map[int][]*warrantyDC) // shipment group -> official DCs
+	for rows.Next() {
+		var id int
+		d := &warrantyDC{}
+		if err := rows.Scan(&id, &d.number, &d.dcType, &d.groupID, &d.addressID, &d.challanDate, &d.podDate, &d.district, &d.mandal); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("GetWarrantySerials: %w", err)
+		}
+		dcs[id] = d
+		if d.dcType == "official" && d.groupID != 0 {
+			officials[d.groupID] = append(officials[d.groupID], d)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetWarrantySerials: %w", err)
+	}
+
+	rows, err = DB.QueryContext(ctx(),
+		`SELECT sn.serial_number, COALESCE(sn.ship_to_address_id, 0), li.dc_id,
+		        COALESCE(p.item_name, ''), COALESCE(p.warranty_months, 0)
+		 FROM serial_numbers sn
+		 INNER JOIN dc_line_items li ON li.id = sn.line_item_id
+		 LEFT JOIN products p ON p.id = COALESCE(sn.product_id, li.product_id)
+		 WHERE sn.project_id = ? AND sn.released_at IS NULL
+		 ORDER BY sn.id`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("GetWarrantySerials: %w", err)
+	}
+	var out []models.WarrantySerial
+	var addressIDs []int // ship-to of each serial, looked up once the rows are closed
+	for rows.Next() {
+		var s models.WarrantySerial
+		var addressID, dcID, months int
+		if err := rows.Scan(&s.SerialNumber, &addressID, &dcID, &s.ItemName, &months); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("GetWarrantySerials: %w", err)
+		}
+		dc := dcs[dcID]
+		if dc == nil || dc.dcType == "official" || dc.dcType == "return" {
+			continue
+		}
+		if group := officials[dc.groupID]; len(group) > 0 {
+			for _, o := range group {
+				if o.addressID == addressID {
+					dc = o
+					break
+				}
+			}
+			if addressID == 0 && len(group) == 1 {
+				dc = group[0]
+			}
+		}
+		s.DCNumber, s.District, s.Mandal = dc.number, dc.district, dc.mandal
+		s.StartDate = reportDate(dc.challanDate)
+		if pod := reportDate(dc.podDate); !pod.IsZero() {
+			s.StartDate, s.FromPOD = pod, true
+		}
+		s.Months = models.EffectiveWarrantyMonths(months, projectMonths)
+		out = append(out, s)
+		addressIDs = append(addressIDs, dc.addressID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetWarrantySerials: %w", err)
+	}
+
+	names := make(map[int]string)
+	for i, id := range addressIDs {
+		if id == 0 {
+			continue
+		}
+		name, ok := names[id]
+		if !ok {
+			if a, err := GetAddress(id); err == nil {
+				name = a.DisplayName()
+			} else {
+				name = fmt.Sprintf("Address #%d", id)
+			}
+			names[id] = name
+		}
+		out[i].ShipTo = name
+	}
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestGetWarrantySerials(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:warranty_test?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatalf("Failed to open test DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		`CREATE TABLE projects (id INTEGER PRIMARY KEY, warranty_months INTEGER NOT NULL DEFAULT 0, updated_at DATETIME)`,
+		`CREATE TABLE products (id INTEGER PRIMARY KEY, item_name TEXT NOT NULL, warranty_months INTEGER NOT NULL DEFAULT 0)`,
+		`CREATE TABLE addresses (id INTEGER PRIMARY KEY, district_name TEXT, mandal_name TEXT)`,
+		`CREATE TABLE delivery_challans (
+			id INTEGER PRIMARY KEY, project_id INTEGER NOT NULL, dc_number TEXT NOT NULL, dc_type TEXT NOT NULL,
+			status TEXT NOT NULL, shipment_group_id INTEGER, ship_to_address_id INTEGER, challan_date TEXT)`,
+		`CREATE TABLE dc_line_items (id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL, product_id INTEGER NOT NULL)`,
+		`CREATE TABLE serial_numbers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, project_id INTEGER NOT NULL, line_item_id INTEGER NOT NULL,
+			serial_number TEXT NOT NULL, product_id INTEGER, released_at DATETIME, ship_to_address_id INTEGER)`,
+		`CREATE TABLE dc_proofs_of_delivery (id INTEGER PRIMARY KEY, dc_id INTEGER NOT NULL, received_date DATE NOT NULL)`,
+		`INSERT INTO projects (id) VALUES (1)`,
+		`INSERT INTO products (id, item_name, warranty_months) VALUES (10, 'Router', 36), (11, 'Switch', 0)`,
+		`INSERT INTO addresses (id, district_name, mandal_name) VALUES (7, 'Guntur', 'Tenali'), (8, 'Guntur', 'Bapatla')`,
+		// Shipment 5: transit DC 100 with official DCs to addresses 7 and 8.
+		// Shipment 6: transit DC 200 with a single official DC; shipment 9 is still a draft.
+		`INSERT INTO delivery_challans (id, project_id, dc_number, dc_type, status, shipment_group_id, ship_to_address_id, challan_date) VALUES
+			(100, 1, 'TST-TDC-001', 'transit', 'issued', 5, NULL, '2025-01-05'),
+			(101, 1, 'TST-ODC-001', 'official', 'delivered', 5, 7, '2025-01-06'),
+			(102, 1, 'TST-ODC-002', 'official', 'issued', 5, 8, '2025-01-07'),
+			(200, 1, 'TST-TDC-002', 'transit', 'issued', 6, NULL, '2025-02-01'),
+			(201, 1, 'TST-ODC-003', 'official', 'issued', 6, 8, '2025-02-02'),
+			(300, 1, 'TST-TDC-003', 'transit', 'draft', 9, NULL, '2025-03-01')`,
+		`INSERT INTO dc_proofs_of_delivery (dc_id, received_date) VALUES (101, '2025-01-15'), (101, '2025-01-20')`,
+		`INSERT INTO dc_line_items (id, dc_id, product_id) VALUES (1000, 100, 10), (1001, 100, 11), (2000, 200, 10), (3000, 300, 10)`,
+		`INSERT INTO serial_numbers (project_id, line_item_id, serial_number, ship_to_address_id, released_at) VALUES
+			(1, 1000, 'RT1', 7, NULL),
+			(1, 1000, 'RT2', 8, NULL),
+			(1, 1000, 'RT3', NULL, NULL),
+			(1, 1001, 'SW1', 7, NULL),
+			(1, 2000, 'RT4', NULL, NULL),
+			(1, 3000, 'RT5', NULL, NULL),
+			(1, 1000, 'RT6', 7, '2025-03-01')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	DB = db
+
+	got, err := GetWarrantySerials(1)
+	if err != nil {
+		t.Fatalf("GetWarrantySerials: %v", err)
+	}
+	type row struct {
+		serial, dc, mandal, start string
+		pod                       bool
+		months                    int
+	}
+	want := []row{
+		{"RT1", "TST-ODC-001", "Tenali", "2025-01-20", true, 36},   // latest POD of the delivered DC
+		{"RT2", "TST-ODC-002", "Bapatla", "2025-01-07", false, 36}, // challan date, no POD
+		{"RT3", "TST-TDC-001", "", "2025-01-05", false, 36},        // no destination, two official DCs
+		{"SW1", "TST-ODC-001", "Tenali", "2025-01-20", true, 0},
+		{"RT4", "TST-ODC-003", "Bapatla", "2025-02-02", false, 36}, // the only official DC
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d serials, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		r := row{g.SerialNumber, g.DCNumber, g.Mandal, g.StartDate.Format("2006-01-02"), g.FromPOD, g.Months}
+		if r != w {
+			t.Errorf("serial %d = %+v, want %+v", i, r, w)
+		}
+	}
+
+	// A project override replaces every product's period.
+	if err := SetProjectWarrantyMonths(1, 60); err != nil {
+		t.Fatalf("SetProjectWarrantyMonths: %v", err)
+	}
+	got, err = GetWarrantySerials(1)
+	if err != nil {
+		t.Fatalf("GetWarrantySerials: %v", err)
+	}
+	for _, g := range got {
+		if g.Months != 60 {
+			t.Errorf("%s months = %d, want the project's 60", g.SerialNumber, g.Months)
+		}
+	}
+}
@@ -121,6 +121,7 @@ func UpdateAddressColumnConfig(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to load config"})
 	}
+	beforeColumns := config.ColumnDefinitions
 
 	// Parse column definitions from form
 	columnsJSON := c.FormValue("columns_json")
@@ -254,6 +255,10 @@ func UpdateAddressColumnConfig(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/addresses?tab=%s", projectID, tab))
 	}
 
+	recordAudit(c, projectID, models.AuditEntityAddressConfig, config.ID, models.AuditActionUpdate,
+		"Updated "+models.AuditLabel(tab)+" column configuration",
+		map[string]interface{}{"columns": beforeColumns}, map[string]interface{}{"columns": config.ColumnDefinitions})
+
 	auth.SetFlash(c.Request(), "success", "Column configuration updated successfully")
 	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/addresses?tab=%s", projectID, tab))
 }
@@ -426,6 +431,11 @@ func UploadAddressesHandler(c echo.Context) error {
 		}
 	}
 
+	if len(validAddresses) > 0 {
+		recordAudit(c, projectID, models.AuditEntityAddress, 0, models.AuditActionImport,
+			fmt.Sprintf("Imported %d %s address(es) from %s (%s mode)", len(validAddresses), models.AuditLabel(tab), header.Filename, mode), nil, nil)
+	}
+
 	msg := fmt.Sprintf("Upload complete: %d of %d addresses imported (%s mode)", result.Successful, result.TotalRows, mode)
 	if result.Failed > 0 {
 		msg += fmt.Sprintf(". %d rows failed validation.", result.Failed)
@@ -495,12 +505,17 @@ func CreateAddressUnified(c echo.Context) error {
 		}
 	}
 
-	if _, err := database.CreateAddress(config.ID, data, districtName, mandalName, mandalCode, addressCode); err != nil {
+	addressID, err := database.CreateAddress(config.ID, data, districtName, mandalName, mandalCode, addressCode)
+	if err != nil {
 		slog.Error("error creating address", slog.String("error", err.Error()), slog.Int("projectID", projectID), slog.String("tab", tab))
 		auth.SetFlash(c.Request(), "error", "Failed to create address")
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/addresses?tab=%s", projectID, tab))
 	}
 
+	recordAudit(c, projectID, models.AuditEntityAddress, addressID, models.AuditActionCreate,
+		"Created "+models.AuditLabel(tab)+" address", nil,
+		addressAuditState(&models.Address{AddressCode: addressCode, Data: data, DistrictName: districtName, MandalName: mandalName, MandalCode: mandalCode}))
+
 	auth.SetFlash(c.Request(), "success", "Address added successfully")
 	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/addresses?tab=%s", projectID, tab))
 }
@@ -578,10 +593,33 @@ func UpdateAddressUnified(c echo.Context) error {
 		}
 	}
 
-	if err := database.UpdateAddress(addressID, data, districtName, mandalName, mandalCode, addressCode); err != nil {
-		slog.Error("error updating address", slog.String("error", err.Error()), slog.Int("addressID", addressID), slog.Int("projectID", projectID))
-		auth.SetFlash(c.Request(), "error", "Failed to update address")
-		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/addresses?tab=%s", projectID, tab))
+	existing, _ := database.GetAddress(addressID)
+
+	version := formEditVersion(c)
+	if err := database.UpdateAddress(addressID, version, data, districtName, mandalName, mandalCode, addressCode); err != nil {
+		if !isStaleVersion(err) || existing == nil {
+			slog.Error("error updating address", slog.String("error", err.Error()), slog.Int("addressID", addressID), slog.Int("projectID", projectID))
+			auth.SetFlash(c.Request(), "error", "Failed to update address")
+			return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/addresses?tab=%s", projectID, tab))
+		}
+		project, err := database.GetProjectByID(projectID)
+		if err != nil {
+			return c.Redirect(http.StatusFound, "/projects")
+		}
+		conflict := newEditConflict(models.AuditEntityAddress, addressID, addressID, version, addressAuditState(existing),
+			addressAuditState(&models.Address{AddressCode: addressCode, Data: data, DistrictName: districtName, MandalName: mandalName, MandalCode: mandalCode}))
+		conflict.Subject = models.AuditLabel(tab) + " address"
+		if existing.AddressCode != "" {
+			conflict.Subject += " " + existing.AddressCode
+		}
+		conflict.ReloadURL = fmt.Sprintf("/projects/%d/addresses?tab=%s", projectID, tab)
+		return renderEditConflict(c, project, conflict)
+	}
+
+	if existing != nil {
+		recordAudit(c, projectID, models.AuditEntityAddress, addressID, models.AuditActionUpdate,
+			"Updated "+models.AuditLabel(tab)+" address", addressAuditState(existing),
+			addressAuditState(&models.Address{AddressCode: addressCode, Data: data, DistrictName: districtName, MandalName: mandalName, MandalCode: mandalCode}))
 	}
 
 	auth.SetFlash(c.Request(), "success", "Address updated successfully")
@@ -622,6 +660,8 @@ func DeleteAddressUnified(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": fmt.Sprintf("Failed to load config: %v", err)})
 	}
 
+	existing, _ := database.GetAddress(addressID)
+
 	if err := database.DeleteAddress(addressID, config.ID); err != nil {
 		slog.Error("delete address: failed to delete", slog.String("error", err.Error()), slog.Int("addressID", addressID), slog.Int("configID", config.ID), slog.Int("projectID", projectID), slog.String("tab", tab))
 		if strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
@@ -630,6 +670,11 @@ func DeleteAddressUnified(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": fmt.Sprintf("Failed to delete address: %v", err)})
 	}
 
+	if existing != nil {
+		recordAudit(c, projectID, models.AuditEntityAddress, addressID, models.AuditActionDelete,
+			"Deleted "+models.AuditLabel(tab)+" address", addressAuditState(existing), nil)
+	}
+
 	return c.JSON(http.StatusOK, map[string]interface{}{"success": true})
 }
 
@@ -675,6 +720,9 @@ func DeleteAllAddressesUnified(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": fmt.Sprintf("Failed to delete addresses: %v", err)})
 	}
 
+	recordAudit(c, projectID, models.AuditEntityAddress, 0, models.AuditActionDelete,
+		fmt.Sprintf("Deleted all %d %s address(es)", len(allAddresses.Addresses), models.AuditLabel(tab)), nil, nil)
+
 	auth.SetFlash(c.Request(), "success", "All addresses deleted")
 	return c.JSON(http.StatusOK, map[string]interface{}{"success": true, "redirect": fmt.Sprintf("/projects/%d/addresses?tab=%s", projectID, tab)})
 }
@@ -698,6 +746,7 @@ func GetAddressJSONUnified(c echo.Context) error {
 		"district_name": addr.DistrictName,
 		"mandal_name":   addr.MandalName,
 		"mandal_code":   addr.MandalCode,
+		"version":       currentEditVersion(models.AuditEntityAddress, addressID),
 	})
 }
 
@@ -871,3 +920,18 @@ func sanitizeFieldName(name string) string {
 	name = strings.ToLower(name)
 	return name
 }
+
+// addressAuditState flattens an address into audited fields: the fixed fields
+// plus one entry per dynamic column (Address.Data is not JSON-serialised).
+func addressAuditState(addr *models.Address) map[string]interface{} {
+	state := map[string]interface{}{
+		"address_code":  addr.AddressCode,
+		"district_name": addr.DistrictName,
+		"mandal_name":   addr.MandalName,
+		"mandal_code":   addr.MandalCode,
+	}
+	for k, v := range addr.Data {
+		state[k] = v
+	}
+	return state
+}
@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	pageapprovals "github.com/narendhupati/dc-management-tool/components/pages/approvals"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// maxApprovalCommentsLen caps the approver and submitter comments.
+const maxApprovalCommentsLen = 1000
+
+// ApprovalRequest is the body accepted by the approval endpoints.
+type ApprovalRequest struct {
+	Comments string `json:"comments" form:"comments"`
+}
+
+// parseApprovalComments binds and validates the optional approval comments.
+func parseApprovalComments(c echo.Context) (string, string) {
+	var req ApprovalRequest
+	if err := c.Bind(&req); err != nil {
+		return "", "Invalid request"
+	}
+	comments := strings.TrimSpace(req.Comments)
+	if len(comments) > maxApprovalCommentsLen {
+		return "", "Comments must be 1000 characters or fewer"
+	}
+	return comments, ""
+}
+
+// approvalSubject is a shipment group or single DC being moved through approval.
+type approvalSubject struct {
+	Target     models.ApprovalTarget
+	EntityType string
+	EntityID   int
+	Label      string
+	Status     string
+}
+
+// approvalGate decides whether userID may issue (approve) a subject in the
+// given status. It returns a user-facing message when issuing is not allowed.
+// A pending request always needs an approver other than its submitter, even if
+// the project has since turned approval off.
+func approvalGate(target models.ApprovalTarget, status string, userID int) (string, error) {
+	settings, err := database.GetApprovalSettings(target.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	switch status {
+	case models.DCStatusDraft:
+		if settings.RequireApproval {
+			return "This project requires approval; submit for approval before issuing", nil
+		}
+		return "", nil
+	case models.DCStatusRejected:
+		return "This request was rejected; reopen or resubmit it first", nil
+	case models.DCStatusPendingApproval:
+		return approverError(settings, target, userID)
+	default:
+		return "", nil
+	}
+}
+
+// approverError returns why userID may not approve or reject the target's
+// pending request, or "" when they may.
+func approverError(settings *models.ApprovalSettings, target models.ApprovalTarget, userID int) (string, error) {
+	if !settings.IsApprover(userID) {
+		return "Only a project approver can approve or reject this request", nil
+	}
+	submitter, err := database.GetPendingSubmitter(target)
+	if err != nil {
+		return "", err
+	}
+	if submitter == userID {
+		return "You submitted this request; another approver must review it", nil
+	}
+	return "", nil
+}
+
+// loadApprovalState builds what a detail page needs to render the approval panel.
+func loadApprovalState(target models.ApprovalTarget, status string, userID int) *models.ApprovalState {
+	state := &models.ApprovalState{}
+	settings, err := database.GetApprovalSettings(target.ProjectID)
+	if err != nil {
+		slog.Error("Error loading approval settings", slog.Int("project_id", target.ProjectID), slog.String("error", err.Error()))
+		return state
+	}
+	state.Required = settings.RequireApproval
+	state.History, err = database.GetApprovalHistory(target)
+	if err != nil {
+		slog.Error("Error loading approval history", slog.Int("project_id", target.ProjectID), slog.String("error", err.Error()))
+	}
+	if status == models.DCStatusPendingApproval {
+		msg, err := approverError(settings, target, userID)
+		state.CanApprove = err == nil && msg == ""
+	}
+	return state
+}
+
+// recordApproval records the approve step after an approved subject was issued.
+// Issuing has already succeeded, so failures are only logged.
+func recordApproval(c echo.Context, s *approvalSubject, userID int, comments string) {
+	if s.Status != models.DCStatusPendingApproval {
+		return
+	}
+	if err := database.RecordApprovalStep(s.Target, models.ApprovalActionApprove, userID, comments); err != nil {
+		slog.Error("Error recording approval step", slog.Int("entity_id", s.EntityID), slog.String("error", err.Error()))
+	}
+	recordAudit(c, s.Target.ProjectID, s.EntityType, s.EntityID, models.AuditActionApprove, "Approved "+s.Label,
+		map[string]interface{}{"status": s.Status},
+		map[string]interface{}{"status": models.DCStatusIssued, "comments": comments})
+}
+
+// groupApprovalSubject loads the shipment group named in the route. On failure
+// it returns a nil subject with the HTTP status and error message to send.
+func groupApprovalSubject(c echo.Context) (*approvalSubject, int, string) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, http.StatusBadRequest, "Invalid project ID"
+	}
+	groupID, err := strconv.Atoi(c.Param("gid"))
+	if err != nil {
+		return nil, http.StatusBadRequest, "Invalid group ID"
+	}
+	group, err := database.GetShipmentGroup(groupID)
+	if err != nil || group.ProjectID != projectID {
+		return nil, http.StatusNotFound, "Shipment group not found"
+	}
+	return &approvalSubject{
+		Target:     models.ApprovalTarget{ProjectID: projectID, ShipmentGroupID: groupID},
+		EntityType: models.AuditEntityShipmentGroup,
+		EntityID:   groupID,
+		Label:      fmt.Sprintf("shipment group #%d", groupID),
+		Status:     group.Status,
+	}, http.StatusOK, ""
+}
+
+// dcApprovalSubject loads the single DC named in the route, failing like
+// groupApprovalSubject. Transit and Official DCs go through approval with their
+// shipment group.
+func dcApprovalSubject(c echo.Context) (*approvalSubject, *models.DeliveryChallan, int, string) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, "Invalid project ID"
+	}
+	dcID, err := strconv.Atoi(c.Param("dcid"))
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, "Invalid DC ID"
+	}
+	dc, err := database.GetDeliveryChallanByID(dcID)
+	if err != nil || dc.ProjectID != projectID {
+		return nil, nil, http.StatusNotFound, "DC not found"
+	}
+	if dc.DCType != "transfer" {
+		return nil, nil, http.StatusBadRequest, "Transit and Official DCs go through approval with their shipment group"
+	}
+	return &approvalSubject{
+		Target:     models.ApprovalTarget{ProjectID: projectID, DCID: dcID},
+		EntityType: models.AuditEntityDC,
+		EntityID:   dcID,
+		Label:      "Transfer DC " + dc.DCNumber,
+		Status:     dc.Status,
+	}, dc, http.StatusOK, ""
+}
+
+// submitForApproval moves a validated subject to pending approval.
+func submitForApproval(c echo.Context, s *approvalSubject) error {
+	user := auth.GetCurrentUser(c)
+
+	settings, err := database.GetApprovalSettings(s.Target.ProjectID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to load approval settings"})
+	}
+	if !settings.RequireApproval {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "This project does not require approval; issue the DCs directly"})
+	}
+	if s.Status != models.DCStatusDraft && s.Status != models.DCStatusRejected {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Only draft or rejected requests can be submitted for approval"})
+	}
+
+	comments, msg := parseApprovalComments(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
+
+	if err := database.SubmitForApproval(s.Target, user.ID, comments); err != nil {
+		slog.Error("Error submitting for approval", slog.Int("entity_id", s.EntityID), slog.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to submit for approval"})
+	}
+
+	recordAudit(c, s.Target.ProjectID, s.EntityType, s.EntityID, models.AuditActionSubmit, "Submitted "+s.Label+" for approval",
+		map[string]interface{}{"status": s.Status},
+		map[string]interface{}{"status": models.DCStatusPendingApproval, "comments": comments})
+
+	auth.SetFlash(c.Request(), "success", "Submitted for approval")
+	return c.JSON(http.StatusOK, map[string]interface{}{"success": true, "message": "Submitted for approval"})
+}
+
+// rejectApproval sends a pending subject back with the approver's comments.
+func rejectApproval(c echo.Context, s *approvalSubject) error {
+	user := auth.GetCurrentUser(c)
+
+	if s.Status != models.DCStatusPendingApproval {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Only requests pending approval can be rejected"})
+	}
+	settings, err := database.GetApprovalSettings(s.Target.ProjectID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to load approval settings"})
+	}
+	msg, err := approverError(settings, s.Target, user.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to check approver rights"})
+	}
+	if msg != "" {
+		return c.JSON(http.StatusForbidden, map[string]interface{}{"error": msg})
+	}
+
+	comments, msg := parseApprovalComments(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
+	if comments == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Comments are required to reject a request"})
+	}
+
+	if err := database.RejectApproval(s.Target, user.ID, comments); err != nil {
+		slog.Error("Error rejecting approval", slog.Int("entity_id", s.EntityID), slog.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to reject request"})
+	}
+
+	recordAudit(c, s.Target.ProjectID, s.EntityType, s.EntityID, models.AuditActionReject, "Rejected "+s.Label,
+		map[string]interface{}{"status": s.Status},
+		map[string]interface{}{"status": models.DCStatusRejected, "comments": comments})
+
+	auth.SetFlash(c.Request(), "success", "Request rejected")
+	return c.JSON(http.StatusOK, map[string]interface{}{"success": true, "message": "Request rejected"})
+}
+
+// reopenRejected moves a rejected subject back to draft so it can be edited.
+func reopenRejected(c echo.Context, s *approvalSubject) error {
+	user := auth.GetCurrentUser(c)
+
+	if s.Status != models.DCStatusRejected {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Only rejected requests can be reopened"})
+	}
+
+	if err := database.ReopenRejected(s.Target, user.ID); err != nil {
+		slog.Error("Error reopening rejected request", slog.Int("entity_id", s.EntityID), slog.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to reopen request"})
+	}
+
+	recordAudit(c, s.Target.ProjectID, s.EntityType, s.EntityID, models.AuditActionUpdate, "Reopened "+s.Label+" for editing",
+		map[string]interface{}{"status": s.Status},
+		map[string]interface{}{"status": models.DCStatusDraft})
+
+	auth.SetFlash(c.Request(), "success", "Reopened as draft")
+	return c.JSON(http.StatusOK, map[string]interface{}{"success": true, "message": "Reopened as draft"})
+}
+
+// SubmitShipmentGroupHandler handles POST /projects/:id/shipments/:gid/submit.
+// The group must pass the same checks as issuing.
+func SubmitShipmentGroupHandler(c echo.Context) error {
+	s, code, msg := groupApprovalSubject(c)
+	if s == nil {
+		return c.JSON(code, map[string]interface{}{"error": msg})
+	}
+	if msg, err := validateGroupIssue(s.Target.ProjectID, s.EntityID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to fetch DCs in group"})
+	} else if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
+	return submitForApproval(c, s)
+}
+
+// RejectShipmentGroupHandler handles POST /projects/:id/shipments/:gid/reject.
+func RejectShipmentGroupHandler(c echo.Context) error {
+	s, code, msg := groupApprovalSubject(c)
+	if s == nil {
+		return c.JSON(code, map[string]interface{}{"error": msg})
+	}
+	return rejectApproval(c, s)
+}
+
+// ReopenShipmentGroupHandler handles POST /projects/:id/shipments/:gid/reopen.
+func ReopenShipmentGroupHandler(c echo.Context) error {
+	s, code, msg := groupApprovalSubject(c)
+	if s == nil {
+		return c.JSON(code, map[string]interface{}{"error": msg})
+	}
+	return reopenRejected(c, s)
+}
+
+// SubmitDCHandler handles POST /projects/:id/dcs/:dcid/submit for Transfer DCs.
+func SubmitDCHandler(c echo.Context) error {
+	s, dc, code, msg := dcApprovalSubject(c)
+	if s == nil {
+		return c.JSON(code, map[string]interface{}{"error": msg})
+	}
+	if msg := validateDCIssue(dc); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
+	return submitForApproval(c, s)
+}
+
+// RejectDCHandler handles POST /projects/:id/dcs/:dcid/reject for Transfer DCs.
+func RejectDCHandler(c echo.Context) error {
+	s, _, code, msg := dcApprovalSubject(c)
+	if s == nil {
+		return c.JSON(code, map[string]interface{}{"error": msg})
+	}
+	return rejectApproval(c, s)
+}
+
+// ReopenDCHandler handles POST /projects/:id/dcs/:dcid/reopen for Transfer DCs.
+func ReopenDCHandler(c echo.Context) error {
+	s, _, code, msg := dcApprovalSubject(c)
+	if s == nil {
+		return c.JSON(code, map[string]interface{}{"error": msg})
+	}
+	return reopenRejected(c, s)
+}
+
+// ShowApprovalQueue handles GET /projects/:id/approvals. It lists the requests
+// awaiting the current user in every project where they are an approver.
+func ShowApprovalQueue(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.Redirect(http.StatusFound, "/projects")
+	}
+	project, err := database.GetProjectByID(projectID)
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Project not found")
+		return c.Redirect(http.StatusFound, "/projects")
+	}
+
+	items, err := database.GetApprovalQueue(user.ID)
+	if err != nil {
+		slog.Error("Error loading approval queue", slog.Int("user_id", user.ID), slog.String("error", err.Error()))
+		items = nil
+	}
+
+	flashType, flashMessage := auth.PopFlash(c.Request())
+	allProjects, _ := database.GetAccessibleProjects(user)
+
+	pageContent := pageapprovals.Queue(user, project, allProjects, items)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
+	return components.RenderOK(c, layouts.MainWithContent("Approvals", sidebar, topbar, flashMessage, flashType, pageContent))
+}
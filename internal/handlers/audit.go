@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	auditpage "github.com/narendhupati/dc-management-tool/components/pages/audit"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// auditPageSize is the number of audit events shown per page.
+const auditPageSize = 50
+
+// recordAudit stores an audit event for a change made by the current user.
+// before/after are the entity state around the change (nil for create/delete);
+// only the fields that differ are stored. projectID 0 records a global event.
+// Failures are logged and never fail the request.
+func recordAudit(c echo.Context, projectID int, entityType string, entityID int, action, summary string, before, after interface{}) {
+	e := &models.AuditEvent{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Summary:    summary,
+		Changes:    models.DiffAudit(before, after),
+	}
+	if projectID > 0 {
+		e.ProjectID = &projectID
+	}
+	if user := auth.GetCurrentUser(c); user != nil {
+		userID := user.ID
+		e.UserID = &userID
+	}
+	if err := database.InsertAuditEvent(e); err != nil {
+		slog.Error("Failed to record audit event",
+			slog.String("entity_type", entityType),
+			slog.Int("entity_id", entityID),
+			slog.String("action", action),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// parseAuditFilters reads the audit filters from the query string.
+func parseAuditFilters(c echo.Context) database.AuditFilters {
+	f := database.AuditFilters{
+		EntityType: c.QueryParam("entity_type"),
+		Action:     c.QueryParam("action"),
+		DateFrom:   c.QueryParam("date_from"),
+		DateTo:     c.QueryParam("date_to"),
+		PageSize:   auditPageSize,
+	}
+	f.UserID, _ = strconv.Atoi(c.QueryParam("user_id"))
+	f.EntityID, _ = strconv.Atoi(c.QueryParam("entity_id"))
+	f.ProjectID, _ = strconv.Atoi(c.QueryParam("project_id"))
+	f.Page, _ = strconv.Atoi(c.QueryParam("page"))
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	return f
+}
+
+// auditFiltersToMap converts AuditFilters to the map used by the audit templ component.
+func auditFiltersToMap(f database.AuditFilters) map[string]string {
+	m := map[string]string{
+		"entity_type": f.EntityType,
+		"action":      f.Action,
+		"date_from":   f.DateFrom,
+		"date_to":     f.DateTo,
+		"page":        strconv.Itoa(f.Page),
+	}
+	if f.UserID > 0 {
+		m["user_id"] = strconv.Itoa(f.UserID)
+	}
+	if f.EntityID > 0 {
+		m["entity_id"] = strconv.Itoa(f.EntityID)
+	}
+	if f.ProjectID > 0 {
+		m["project_id"] = strconv.Itoa(f.ProjectID)
+	}
+	return m
+}
+
+// ShowProjectAuditLog handles GET /projects/:id/audit.
+func ShowProjectAuditLog(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+	project := c.Get("currentProject").(*models.Project)
+
+	filters := parseAuditFilters(c)
+	filters.ProjectID = project.ID
+	return renderAuditLog(c, user, project, filters, fmt.Sprintf("/projects/%d/audit", project.ID))
+}
+
+// ShowGlobalAuditLog handles GET /admin/audit (admins only).
+func ShowGlobalAuditLog(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+	return renderAuditLog(c, user, nil, parseAuditFilters(c), "/admin/audit")
+}
+
+// renderAuditLog renders the audit page for a project, or globally when project is nil.
+func renderAuditLog(c echo.Context, user *models.User, project *models.Project, filters database.AuditFilters, basePath string) error {
+	result, err := database.ListAuditEvents(filters)
+	if err != nil {
+		slog.Error("Failed to load audit events", slog.Int("project_id", filters.ProjectID), slog.String("error", err.Error()))
+		result = &database.AuditListResult{Page: 1, TotalPages: 1}
+	}
+
+	users, _ := database.GetAllUsers()
+	allProjects, _ := database.GetAccessibleProjects(user)
+
+	var projectOptions []*models.Project
+	if project == nil {
+		projectOptions, _ = database.GetAllProjects()
+	}
+
+	pageContent := auditpage.AuditLog(auditpage.AuditLogProps{
+		Project:    project,
+		Events:     result.Events,
+		TotalCount: result.TotalCount,
+		Page:       result.Page,
+		TotalPages: result.TotalPages,
+		Filters:    auditFiltersToMap(filters),
+		Users:      users,
+		Projects:   projectOptions,
+		BasePath:   basePath,
+	})
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, "", "")
+	return components.RenderOK(c, layouts.MainWithContent("Audit Log", sidebar, topbar, "", "", pageContent))
+}
+
+// ExportProjectAuditExcel handles GET /projects/:id/audit/export.
+func ExportProjectAuditExcel(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+	filters := parseAuditFilters(c)
+	filters.ProjectID = project.ID
+	return exportAuditExcel(c, filters, fmt.Sprintf("audit-log-project-%d", project.ID))
+}
+
+// ExportGlobalAuditExcel handles GET /admin/audit/export (admins only).
+func ExportGlobalAuditExcel(c echo.Context) error {
+	return exportAuditExcel(c, parseAuditFilters(c), "audit-log")
+}
+
+// exportAuditExcel writes every event matching filters (all pages) as an Excel sheet,
+// one row per changed field so before/after values can be filtered in Excel.
+func exportAuditExcel(c echo.Context, filters database.AuditFilters, filenamePrefix string) error {
+	filters.Page = 1
+	filters.PageSize = 0
+	result, err := database.ListAuditEvents(filters)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to load audit events"})
+	}
+
+	f := excelize.NewFile()
+	sheet := "Audit Log"
+	_ = f.SetSheetName("Sheet1", sheet)
+
+	headers := []string{"Time", "User", "Project", "Entity", "Entity ID", "Action", "Summary", "Field", "Before", "After"}
+	for i, h := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		_ = f.SetCellValue(sheet, cell, h)
+	}
+
+	row := 2
+	writeRow := func(e *models.AuditEvent, ch *models.AuditChange) {
+		vals := []interface{}{
+			e.CreatedAt.Format("2006-01-02 15:04:05"),
+			e.UserName,
+			e.ProjectName,
+			models.AuditLabel(e.EntityType),
+			e.EntityID,
+			models.AuditLabel(e.Action),
+			e.Summary,
+		}
+		if ch != nil {
+			vals = append(vals, ch.Field, models.FormatAuditValue(ch.Before), models.FormatAuditValue(ch.After))
+		}
+		for j, v := range vals {
+			cell, _ := excelize.CoordinatesToCellName(j+1, row)
+			_ = f.SetCellValue(sheet, cell, v)
+		}
+		row++
+	}
+	for _, e := range result.Events {
+		if len(e.Changes) == 0 {
+			writeRow(e, nil)
+			continue
+		}
+		for i := range e.Changes {
+			writeRow(e, &e.Changes[i])
+		}
+	}
+
+	filename := fmt.Sprintf("%s-%s.xlsx", filenamePrefix, time.Now().Format("2006-01-02"))
+	c.Response().Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	if err := f.Write(c.Response().Writer); err != nil {
+		slog.Error("error writing Excel response", slog.String("error", err.Error()), slog.Int("projectID", filters.ProjectID))
+	}
+	return nil
+}
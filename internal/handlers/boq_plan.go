@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	boqpage "github.com/narendhupati/dc-management-tool/components/pages/boq"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+// boqSheetMaxSize is the largest BOQ sheet accepted for import.
+const boqSheetMaxSize = 10 * 1024 * 1024
+
+// ShowBOQPlan handles GET /projects/:id/boq-plan.
+// It shows the project's BOQ plan as a matrix of ship-to addresses and
+// products, and offers the sheet download and import.
+func ShowBOQPlan(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+	project := c.Get("currentProject").(*models.Project)
+
+	plan, err := database.GetBOQPlan(project.ID)
+	if err != nil {
+		slog.Error("Error fetching BOQ plan", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+	}
+	products, err := database.GetProductsByProjectID(project.ID)
+	if err != nil {
+		slog.Error("Error fetching products", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+	}
+	_, _, _, shipTo := loadAllAddresses(project.ID)
+
+	props := buildBOQPlanProps(plan, products, shipTo)
+	props.CSRFToken = csrf.Token(c.Request())
+
+	flashType, flashMessage := auth.PopFlash(c.Request())
+	allProjects, _ := database.GetAccessibleProjects(user)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
+	pageContent := boqpage.Plan(project, props)
+	return components.RenderOK(c, layouts.MainWithContent("BOQ Plan", sidebar, topbar, flashMessage, flashType, pageContent))
+}
+
+// buildBOQPlanProps lays a BOQ plan out as the plan page's matrix: one row per
+// planned ship-to address in district and mandal order, and one column per
+// planned product in name order.
+func buildBOQPlanProps(plan []models.BOQPlanItem, products []*models.Product, shipTo []*models.Address) boqpage.PlanProps {
+	productByID := make(map[int]*models.Product, len(products))
+	for _, p := range products {
+		productByID[p.ID] = p
+	}
+	addressByID := make(map[int]*models.Address, len(shipTo))
+	for _, a := range shipTo {
+		addressByID[a.ID] = a
+	}
+
+	props := boqpage.PlanProps{Totals: make(map[int]int)}
+	rows := make(map[int]*boqpage.PlanRow)
+	seenProduct := make(map[int]bool)
+	for _, it := range plan {
+		p := productByID[it.ProductID]
+		if p == nil {
+			continue
+		}
+		if !seenProduct[p.ID] {
+			seenProduct[p.ID] = true
+			props.Products = append(props.Products, p)
+		}
+		r, ok := rows[it.ShipToAddressID]
+		if !ok {
+			r = &boqpage.PlanRow{Quantities: make(map[int]int)}
+			if a := addressByID[it.ShipToAddressID]; a != nil {
+				r.District, r.Mandal, r.ShipTo = a.DistrictName, a.MandalName, a.DisplayName()
+			} else {
+				r.ShipTo = fmt.Sprintf("Address #%d", it.ShipToAddressID)
+			}
+			rows[it.ShipToAddressID] = r
+		}
+		r.Quantities[p.ID] = it.PlannedQuantity
+		props.Totals[p.ID] += it.PlannedQuantity
+	}
+
+	sort.SliceStable(props.Products, func(a, b int) bool {
+		return props.Products[a].ItemName < props.Products[b].ItemName
+	})
+	for _, r := range rows {
+		props.Rows = append(props.Rows, *r)
+	}
+	sort.SliceStable(props.Rows, func(a, b int) bool {
+		x, y := props.Rows[a], props.Rows[b]
+		if x.District != y.District {
+			return x.District < y.District
+		}
+		if x.Mandal != y.Mandal {
+			return x.Mandal < y.Mandal
+		}
+		return x.ShipTo < y.ShipTo
+	})
+	return props
+}
+
+// DownloadBOQPlanSheet handles GET /projects/:id/boq-plan/sheet.
+// It exports a BOQ sheet with every ship-to address and product of the
+// project, filled in with the current plan, ready to edit and import.
+func DownloadBOQPlanSheet(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+
+	plan, err := database.GetBOQPlan(project.ID)
+	if err != nil {
+		slog.Error("Error fetching BOQ plan", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to load the BOQ plan"})
+	}
+	products, err := database.GetProductsByProjectID(project.ID)
+	if err != nil {
+		slog.Error("Error fetching products", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to load products"})
+	}
+	_, _, _, shipTo := loadAllAddresses(project.ID)
+
+	planned := make(map[int]map[int]int)
+	for _, it := range plan {
+		if planned[it.ShipToAddressID] == nil {
+			planned[it.ShipToAddressID] = make(map[int]int)
+		}
+		planned[it.ShipToAddressID][it.ProductID] = it.PlannedQuantity
+	}
+	sheet := &services.BOQSheet{}
+	for _, p := range products {
+		sheet.Products = append(sheet.Products, p.ItemName)
+	}
+	for _, a := range shipTo {
+		row := services.BOQSheetRow{
+			District:    a.DistrictName,
+			Mandal:      a.MandalName,
+			MandalCode:  a.MandalCode,
+			AddressCode: a.AddressCode,
+			ShipTo:      a.DisplayName(),
+			Quantities:  make(map[int]int),
+		}
+		for j, p := range products {
+			if qty, ok := planned[a.ID][p.ID]; ok {
+				row.Quantities[j] = qty
+			}
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+
+	filename := fmt.Sprintf("boq-plan-%s.xlsx", time.Now().Format("2006-01-02"))
+	c.Response().Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	if err := services.WriteBOQSheet(c.Response().Writer, sheet); err != nil {
+		slog.Error("Error writing BOQ sheet", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+// ImportBOQPlanHandler handles POST /projects/:id/boq-plan/import.
+// It replaces the project's BOQ plan with an uploaded sheet. The sheet is
+// imported only when every row and column matches an address and product of
+// the project, so that a partly read plan never replaces a complete one.
+func ImportBOQPlanHandler(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+	redirect := fmt.Sprintf("/projects/%d/boq-plan", project.ID)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Please select a BOQ sheet to upload")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	if fh.Size > boqSheetMaxSize {
+		auth.SetFlash(c.Request(), "error", "File size must be less than 10MB")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	file, err := fh.Open()
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Failed to read the BOQ sheet")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	sheet, err := services.ParseBOQSheet(file, fh.Filename)
+	file.Close()
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", err.Error())
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	products, err := database.GetProductsByProjectID(project.ID)
+	if err != nil {
+		slog.Error("Error fetching products", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to load products")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	_, _, _, shipTo := loadAllAddresses(project.ID)
+
+	items, errs := matchBOQSheet(sheet, shipTo, products)
+	if len(errs) > 0 {
+		auth.SetFlash(c.Request(), "error", "BOQ plan not imported. "+summariseList(errs))
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	if len(items) == 0 {
+		auth.SetFlash(c.Request(), "error", "The BOQ sheet has no planned quantities")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	before, _ := database.GetBOQPlan(project.ID)
+	if err := database.ReplaceBOQPlan(project.ID, items); err != nil {
+		slog.Error("Error saving BOQ plan", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to save the BOQ plan")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	destinations := make(map[int]bool)
+	for _, it := range items {
+		destinations[it.ShipToAddressID] = true
+	}
+	summary := fmt.Sprintf("Imported BOQ plan from %s: %d destination(s), %d planned unit(s)",
+		fh.Filename, len(destinations), boqPlannedUnits(items))
+	recordAudit(c, project.ID, models.AuditEntityBOQPlan, 0, models.AuditActionImport, summary,
+		map[string]interface{}{"cells": len(before), "units": boqPlannedUnits(before)},
+		map[string]interface{}{"cells": len(items), "units": boqPlannedUnits(items)})
+
+	auth.SetFlash(c.Request(), "success", summary)
+	return c.Redirect(http.StatusFound, redirect)
+}
+
+// ClearBOQPlanHandler handles POST /projects/:id/boq-plan/clear.
+// It removes the project's BOQ plan; DCs are no longer checked against it.
+func ClearBOQPlanHandler(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+	redirect := fmt.Sprintf("/projects/%d/boq-plan", project.ID)
+
+	before, err := database.GetBOQPlan(project.ID)
+	if err != nil {
+		slog.Error("Error fetching BOQ plan", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to load the BOQ plan")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	if len(before) == 0 {
+		auth.SetFlash(c.Request(), "warning", "There is no BOQ plan to clear")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	if err := database.ReplaceBOQPlan(project.ID, nil); err != nil {
+		slog.Error("Error clearing BOQ plan", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to clear the BOQ plan")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	recordAudit(c, project.ID, models.AuditEntityBOQPlan, 0, models.AuditActionDelete, "Cleared the BOQ plan",
+		map[string]interface{}{"cells": len(before), "units": boqPlannedUnits(before)}, nil)
+	auth.SetFlash(c.Request(), "success", "BOQ plan cleared")
+	return c.Redirect(http.StatusFound, redirect)
+}
+
+// matchBOQSheet resolves a BOQ sheet against the project's ship-to addresses
+// and products. A row is matched on its address code, else its mandal code,
+// else its district and mandal, else its ship-to name; a product column on
+// the product's item name, code or brand/model. It returns the plan items and
+// every row or column that could not be matched.
+func matchBOQSheet(sheet *services.BOQSheet, shipTo []*models.Address, products []*models.Product) ([]models.BOQPlanItem, []string) {
+	var errs []string
+
+	productLookup := make(map[string]*models.Product, len(products)*3)
+	for _, p := range products {
+		productLookup[strings.ToLower(strings.TrimSpace(p.BrandModel))] = p
+		productLookup[strings.ToLower(strings.TrimSpace(p.ProductCode))] = p
+	}
+	// Item names win over codes and brand/models when they collide.
+	for _, p := range products {
+		productLookup[strings.ToLower(strings.TrimSpace(p.ItemName))] = p
+	}
+	delete(productLookup, "")
+	columns := make([]*models.Product, len(sheet.Products))
+	seenColumn := make(map[int]string)
+	for j, name := range sheet.Products {
+		p := productLookup[strings.ToLower(name)]
+		switch {
+		case p == nil:
+			errs = append(errs, fmt.Sprintf("no product named %q", name))
+		case seenColumn[p.ID] != "":
+			errs = append(errs, fmt.Sprintf("columns %q and %q are both %s", seenColumn[p.ID], name, p.ItemName))
+		default:
+			seenColumn[p.ID] = name
+			columns[j] = p
+		}
+	}
+
+	byAddressCode := make(map[string][]*models.Address)
+	byMandalCode := make(map[string][]*models.Address)
+	byPlace := make(map[string][]*models.Address)
+	byMandal := make(map[string][]*models.Address)
+	byName := make(map[string][]*models.Address)
+	for _, a := range shipTo {
+		if k := strings.ToLower(strings.TrimSpace(a.AddressCode)); k != "" {
+			byAddressCode[k] = append(byAddressCode[k], a)
+		}
+		if k := strings.ToLower(strings.TrimSpace(a.MandalCode)); k != "" {
+			byMandalCode[k] = append(byMandalCode[k], a)
+		}
+		if k := strings.ToLower(strings.TrimSpace(a.MandalName)); k != "" {
+			byMandal[k] = append(byMandal[k], a)
+			place := strings.ToLower(strings.TrimSpace(a.DistrictName)) + "|" + k
+			byPlace[place] = append(byPlace[place], a)
+		}
+		name := strings.ToLower(a.DisplayName())
+		byName[name] = append(byName[name], a)
+	}
+
+	var items []models.BOQPlanItem
+	rowOf := make(map[int]int) // address ID -> sheet row it was planned on
+	for _, r := range sheet.Rows {
+		if len(r.Quantities) == 0 {
+			continue
+		}
+		var matches []*models.Address
+		var label string
+		switch {
+		case r.AddressCode != "":
+			matches, label = byAddressCode[strings.ToLower(r.AddressCode)], "address code "+r.AddressCode
+		case r.MandalCode != "":
+			matches, label = byMandalCode[strings.ToLower(r.MandalCode)], "mandal code "+r.MandalCode
+		case r.Mandal != "" && r.District != "":
+			matches, label = byPlace[strings.ToLower(r.District)+"|"+strings.ToLower(r.Mandal)], r.District+" / "+r.Mandal
+		case r.Mandal != "":
+			matches, label = byMandal[strings.ToLower(r.Mandal)], "mandal "+r.Mandal
+		default:
+			matches, label = byName[strings.ToLower(r.ShipTo)], r.ShipTo
+		}
+		switch {
+		case len(matches) == 0:
+			errs = append(errs, fmt.Sprintf("row %d: no ship-to address with %s", r.Row, label))
+			continue
+		case len(matches) > 1:
+			errs = append(errs, fmt.Sprintf("row %d: %s matches %d ship-to addresses; add the address code", r.Row, label, len(matches)))
+			continue
+		}
+		a := matches[0]
+		if prev, ok := rowOf[a.ID]; ok {
+			errs = append(errs, fmt.Sprintf("row %d: %s is already planned on row %d", r.Row, label, prev))
+			continue
+		}
+		rowOf[a.ID] = r.Row
+
+		for j := range sheet.Products {
+			qty, ok := r.Quantities[j]
+			if !ok || columns[j] == nil {
+				continue
+			}
+			items = append(items, models.BOQPlanItem{ShipToAddressID: a.ID, ProductID: columns[j].ID, PlannedQuantity: qty})
+		}
+	}
+	return items, errs
+}
+
+// boqPlannedUnits sums the planned quantities of a plan.
+func boqPlannedUnits(items []models.BOQPlanItem) int {
+	n := 0
+	for _, it := range items {
+		n += it.PlannedQuantity
+	}
+	return n
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+func TestMatchBOQSheet(t *testing.T) {
+	shipTo := []*models.Address{
+		{ID: 1, DistrictName: "Guntur", MandalName: "Tenali", MandalCode: "GNT-01"},
+		{ID: 2, DistrictName: "Guntur", MandalName: "Bapatla", MandalCode: "GNT-02", AddressCode: "ZP-2"},
+		{ID: 3, DistrictName: "Guntur", MandalName: "Bapatla", MandalCode: "GNT-02", AddressCode: "ZP-3"},
+		{ID: 4, DistrictName: "Krishna", MandalName: "Tenali"},
+	}
+	products := []*models.Product{
+		{ID: 10, ItemName: "Router", BrandModel: "AX3000"},
+		{ID: 11, ItemName: "Switch", ProductCode: "SW24"},
+	}
+
+	t.Run("matches rows and columns", func(t *testing.T) {
+		sheet := &services.BOQSheet{
+			Products: []string{"ax3000", "SW24"},
+			Rows: []services.BOQSheetRow{
+				{Row: 2, MandalCode: "gnt-01", Quantities: map[int]int{0: 10, 1: 2}},
+				{Row: 3, AddressCode: "ZP-3", Quantities: map[int]int{1: 0}},
+				{Row: 4, District: "Krishna", Mandal: "Tenali", Quantities: map[int]int{0: 5}},
+				{Row: 5, Mandal: "Bapatla"}, // no quantities: a blank template row
+			},
+		}
+		items, errs := matchBOQSheet(sheet, shipTo, products)
+		if len(errs) != 0 {
+			t.Fatalf("errs = %v", errs)
+		}
+		want := []models.BOQPlanItem{
+			{ShipToAddressID: 1, ProductID: 10, PlannedQuantity: 10},
+			{ShipToAddressID: 1, ProductID: 11, PlannedQuantity: 2},
+			{ShipToAddressID: 3, ProductID: 11, PlannedQuantity: 0},
+			{ShipToAddressID: 4, ProductID: 10, PlannedQuantity: 5},
+		}
+		if !reflect.DeepEqual(items, want) {
+			t.Errorf("items = %+v, want %+v", items, want)
+		}
+		if got := boqPlannedUnits(items); got != 17 {
+			t.Errorf("boqPlannedUnits = %d, want 17", got)
+		}
+	})
+
+	t.Run("reports every mismatch", func(t *testing.T) {
+		sheet := &services.BOQSheet{
+			Products: []string{"Router", "AX3000", "Modem"},
+			Rows: []services.BOQSheetRow{
+				{Row: 2, MandalCode: "GNT-02", Quantities: map[int]int{0: 1}},
+				{Row: 3, Mandal: "Tenali", Quantities: map[int]int{0: 1}},
+				{Row: 4, MandalCode: "GNT-99", Quantities: map[int]int{0: 1}},
+				{Row: 5, MandalCode: "GNT-01", Quantities: map[int]int{0: 1}},
+				{Row: 6, District: "Guntur", Mandal: "Tenali", Quantities: map[int]int{0: 1}},
+			},
+		}
+		_, errs := matchBOQSheet(sheet, shipTo, products)
+		want := []string{
+			`columns "Router" and "AX3000" are both Router`,
+			`no product named "Modem"`,
+			"row 2: mandal code GNT-02 matches 2 ship-to addresses; add the address code",
+			"row 3: mandal Tenali matches 2 ship-to addresses",
+			"row 4: no ship-to address with mandal code GNT-99",
+			"row 6: Guntur / Tenali is already planned on row 5",
+		}
+		if len(errs) != len(want) {
+			t.Fatalf("errs = %q, want %d errors", errs, len(want))
+		}
+		for i, w := range want {
+			if !strings.Contains(errs[i], w) {
+				t.Errorf("errs[%d] = %q, want it to contain %q", i, errs[i], w)
+			}
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	pageshipments "github.com/narendhupati/dc-management-tool/components/pages/shipments"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+// loadLabelGroup loads the issued shipment group in the URL with its
+// destinations. Splits of a transfer DC are shipment groups too. A nil group
+// means the request is answered: err is the HTTP error, or nil after redirecting.
+func loadLabelGroup(c echo.Context) (*models.ShipmentGroup, []*models.BoxLabelDestination, error) {
+	project := c.Get("currentProject").(*models.Project)
+	gid, err := strconv.Atoi(c.Param("gid"))
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "invalid group id")
+	}
+	group, err := database.GetShipmentGroup(gid)
+	if err != nil || group.ProjectID != project.ID {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "shipment group not found")
+	}
+	if group.Status != "issued" {
+		auth.SetFlash(c.Request(), "error", "Box labels can be printed once the shipment is issued")
+		return nil, nil, c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/%d", project.ID, gid))
+	}
+	dests, err := database.GetBoxLabelDestinations(gid)
+	if err != nil {
+		slog.Error("Error loading box label destinations", slog.Int("group_id", gid), slog.String("error", err.Error()))
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to load the shipment's DCs")
+	}
+	return group, dests, nil
+}
+
+// ShowBoxLabels handles GET /projects/:id/shipments/:gid/labels.
+// It offers the label size and how many units of each product go in a box.
+func ShowBoxLabels(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+	project := c.Get("currentProject").(*models.Project)
+
+	group, dests, err := loadLabelGroup(c)
+	if group == nil {
+		return err
+	}
+
+	// Products in the order they first appear across destinations.
+	var products []*models.BoxLabelLine
+	seen := make(map[int]bool)
+	for _, d := range dests {
+		for _, line := range d.Lines {
+			if !seen[line.ProductID] {
+				seen[line.ProductID] = true
+				products = append(products, line)
+			}
+		}
+	}
+
+	flashType, flashMessage := auth.PopFlash(c.Request())
+	allProjects, _ := database.GetAccessibleProjects(user)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
+	pageContent := pageshipments.BoxLabels(project, group, dests, products)
+	return components.RenderOK(c, layouts.MainWithContent("Box Labels", sidebar, topbar, flashMessage, flashType, pageContent))
+}
+
+// DownloadBoxLabels handles GET /projects/:id/shipments/:gid/labels/pdf.
+// It returns a PDF of one label per box, in the size chosen.
+func DownloadBoxLabels(c echo.Context) error {
+	group, dests, err := loadLabelGroup(c)
+	if group == nil {
+		return err
+	}
+
+	unitsPerBox := make(map[int]int)
+	for _, d := range dests {
+		for _, line := range d.Lines {
+			if n, err := strconv.Atoi(c.QueryParam(fmt.Sprintf("per_box_%d", line.ProductID))); err == nil && n > 0 {
+				unitsPerBox[line.ProductID] = n
+			}
+		}
+	}
+	size := c.QueryParam("size")
+	if size != services.LabelSizeThermal {
+		size = services.LabelSizeA4
+	}
+
+	pdf, err := services.GenerateBoxLabelsPDF(services.BuildBoxLabels(dests, unitsPerBox), size)
+	if err != nil {
+		slog.Error("Error generating box labels", slog.Int("group_id", group.ID), slog.String("error", err.Error()))
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to generate the labels")
+	}
+	filename := services.SanitizeDCFilename(group.TransitDCNumber) + "-labels.pdf"
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	return c.Blob(http.StatusOK, "application/pdf", pdf)
+}
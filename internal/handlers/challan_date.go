@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+// overrideDateRulesField is the form field an admin ticks to save a DC despite
+// a closed financial year or the challan date order rule.
+const overrideDateRulesField = "override_date_rules"
+
+// challanDateGuard is the outcome of checking a challan date. Refused is the
+// message to show when the date may not be used; Overridden is the rule an
+// admin overrode, to be audited once the save succeeds.
+type challanDateGuard struct {
+	Refused    string
+	Overridden *models.ChallanDateError
+}
+
+// checkChallanDate checks the challan date (YYYY-MM-DD) of a new or edited DC.
+// previousDate is the date the DC carries now ("" for a new DC); its financial
+// year must be open too, so DCs in a closed year cannot be moved out of it.
+// For each of dcTypes the date order rule is checked, leaving excludeDCID out;
+// with no dcTypes only the financial years are checked. Admins who ticked the
+// override field get the refusal recorded instead of enforced.
+func checkChallanDate(c echo.Context, projectID int, challanDate, previousDate string, excludeDCID int, dcTypes ...string) challanDateGuard {
+	refusal := challanDateRefusal(projectID, challanDate, previousDate, excludeDCID, dcTypes)
+	if refusal == nil {
+		return challanDateGuard{}
+	}
+
+	var dateErr *models.ChallanDateError
+	if !errors.As(refusal, &dateErr) {
+		slog.Error("Error checking challan date", slog.Int("projectID", projectID), slog.String("error", refusal.Error()))
+		return challanDateGuard{Refused: "Could not check the challan date, please try again"}
+	}
+	if user := auth.GetCurrentUser(c); user != nil && user.IsAdmin() && c.FormValue(overrideDateRulesField) == "1" {
+		return challanDateGuard{Overridden: dateErr}
+	}
+	return challanDateGuard{Refused: challanDateMessage(dateErr)}
+}
+
+// challanDay returns the YYYY-MM-DD part of a stored challan date, or "" when unset.
+func challanDay(d *string) string {
+	if d == nil || len(*d) < 10 {
+		return ""
+	}
+	return (*d)[:10]
+}
+
+// challanDateRefusal runs the checks of checkChallanDate. Unparseable dates are
+// skipped; the wizards validate them separately.
+func challanDateRefusal(projectID int, challanDate, previousDate string, excludeDCID int, dcTypes []string) error {
+	if previous, err := time.Parse("2006-01-02", previousDate); err == nil {
+		if err := services.CheckFinancialYearOpen(database.DB, projectID, previous); err != nil {
+			return err
+		}
+	}
+	date, err := time.Parse("2006-01-02", challanDate)
+	if err != nil {
+		return nil
+	}
+	if err := services.CheckFinancialYearOpen(database.DB, projectID, date); err != nil {
+		return err
+	}
+	for _, dcType := range dcTypes {
+		if err := services.CheckChallanDate(database.DB, projectID, dcType, date, excludeDCID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closedYearMessage returns a message when the DC's challan date falls in a
+// closed financial year, for status changes such as issue and cancel. These
+// have no override; an admin reopens the year instead.
+func closedYearMessage(dc *models.DeliveryChallan) string {
+	date, err := time.Parse("2006-01-02", dcChallanDay(dc))
+	if err != nil {
+		return ""
+	}
+	err = services.CheckFinancialYearOpen(database.DB, dc.ProjectID, date)
+	var dateErr *models.ChallanDateError
+	if errors.As(err, &dateErr) {
+		return challanDateMessage(dateErr)
+	}
+	if err != nil {
+		slog.Error("Error checking financial year", slog.Int("dc_id", dc.ID), slog.String("error", err.Error()))
+	}
+	return ""
+}
+
+// challanDateMessage words a refused challan date for a flash message.
+func challanDateMessage(e *models.ChallanDateError) string {
+	if e.Closed {
+		return fmt.Sprintf("Financial year %s is closed for new DCs and edits. An admin can reopen it in project settings.",
+			models.FinancialYearLabel(e.FinancialYear))
+	}
+	return fmt.Sprintf("Challan date %s is before %s, the date of the latest issued DC %s. Use that date or later.",
+		e.Date, e.LatestDate, e.LatestNumber)
+}
+
+// audit records an admin override once the DC it allowed has been saved.
+func (g challanDateGuard) audit(c echo.Context, projectID int, entityType string, entityID int) {
+	if g.Overridden == nil {
+		return
+	}
+	recordAudit(c, projectID, entityType, entityID, models.AuditActionOverride,
+		"Overrode challan date rule: "+g.Overridden.Error(), nil, g.Overridden)
+}
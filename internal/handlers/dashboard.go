@@ -42,6 +42,9 @@ func dashboardStatsToMap(s *database.DashboardStats) map[string]int64 {
 		"transit_dcs_issued":      int64(s.TransitDCsIssued),
 		"official_dcs_draft":      int64(s.OfficialDCsDraft),
 		"official_dcs_issued":     int64(s.OfficialDCsIssued),
+		"official_dcs_delivered":  int64(s.OfficialDCsDelivered),
+		"official_dcs_partial":    int64(s.OfficialDCsPartiallyDelivered),
+		"official_dcs_pending":    int64(s.OfficialDCsPendingDelivery),
 		"transfer_dcs":            int64(s.TransferDCs),
 		"transfer_dcs_draft":      int64(s.TransferDCsDraft),
 		"transfer_dcs_issued":     int64(s.TransferDCsIssued),
@@ -171,6 +174,12 @@ func ShowDashboard(c echo.Context) error {
 		dcFormat = models.DefaultDCNumberFormat
 	}
 
+	// Tender BOQ progress per district; empty when the project has no plan.
+	boqRows, err := database.GetBOQProgress(project.ID)
+	if err != nil {
+		slog.Error("error fetching BOQ progress", slog.String("error", err.Error()), slog.Int("projectID", project.ID))
+	}
+
 	// HTMX partial refresh — return only the stats + activity fragment.
 	if c.Request().Header.Get("HX-Request") == "true" {
 		props := htmx.DashboardStatsPartialProps{
@@ -221,6 +230,8 @@ func ShowDashboard(c echo.Context) error {
 		nextTransitDC,
 		nextOfficialDC,
 		activityItems,
+		models.BOQByDistrict(boqRows),
+		models.BOQGrandTotal(boqRows),
 	)
 
 	// Wrap page content in the main layout.
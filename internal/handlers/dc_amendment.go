@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	deliverychallan "github.com/narendhupati/dc-management-tool/components/pages/delivery_challans"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// maxAmendmentReasonLen caps the free-text reason stored with a DC revision.
+const maxAmendmentReasonLen = 500
+
+// amendableDC loads the project and DC for the amend routes and checks that the DC
+// can be amended. On failure it sets a flash message and returns a redirect URL.
+func amendableDC(c echo.Context) (*models.Project, *models.DeliveryChallan, string) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, nil, "/projects"
+	}
+
+	dcID, err := strconv.Atoi(c.Param("dcid"))
+	if err != nil {
+		return nil, nil, fmt.Sprintf("/projects/%d", projectID)
+	}
+
+	project, err := database.GetProjectByID(projectID)
+	if err != nil {
+		return nil, nil, "/projects"
+	}
+
+	dc, err := database.GetDeliveryChallanByID(dcID)
+	if err != nil || dc.ProjectID != projectID {
+		auth.SetFlash(c.Request(), "error", "DC not found")
+		return nil, nil, fmt.Sprintf("/projects/%d", projectID)
+	}
+
+	detailURL := fmt.Sprintf("/projects/%d/dcs/%d", projectID, dcID)
+	if dc.Status != models.DCStatusIssued {
+		auth.SetFlash(c.Request(), "error", "Only issued DCs can be amended")
+		return nil, nil, detailURL
+	}
+	if dc.DCType != "transit" && dc.DCType != "official" {
+		auth.SetFlash(c.Request(), "error", "Only Transit and Official DCs can be amended")
+		return nil, nil, detailURL
+	}
+	return project, dc, ""
+}
+
+// amendmentFromDC pre-fills the amendment form with the DC's current values.
+func amendmentFromDC(dc *models.DeliveryChallan) *models.DCAmendment {
+	a := &models.DCAmendment{
+		ChallanDate:           dc.ChallanDate,
+		BillFromAddressID:     derefInt(dc.BillFromAddressID),
+		DispatchFromAddressID: derefInt(dc.DispatchFromAddressID),
+		BillToAddressID:       derefInt(dc.BillToAddressID),
+	}
+	if dc.DCType == "transit" {
+		if td, _ := database.GetTransitDetailsByDCID(dc.ID); td != nil {
+			a.TransporterName = td.TransporterName
+			a.VehicleNumber = td.VehicleNumber
+			a.EwayBillNumber = td.EwayBillNumber
+			a.Notes = td.Notes
+		}
+	}
+	return a
+}
+
+// derefInt safely dereferences an *int, returning 0 for nil.
+func derefInt(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+// amendmentFromForm reads the submitted amendment form.
+func amendmentFromForm(c echo.Context) *models.DCAmendment {
+	a := &models.DCAmendment{
+		Reason:          strings.TrimSpace(c.FormValue("reason")),
+		TransporterName: strings.TrimSpace(c.FormValue("transporter_name")),
+		VehicleNumber:   strings.ToUpper(strings.TrimSpace(c.FormValue("vehicle_number"))),
+		EwayBillNumber:  strings.TrimSpace(c.FormValue("eway_bill_number")),
+		Notes:           strings.TrimSpace(c.FormValue("notes")),
+	}
+	if d := strings.TrimSpace(c.FormValue("challan_date")); d != "" {
+		a.ChallanDate = &d
+	}
+	a.BillFromAddressID, _ = strconv.Atoi(c.FormValue("bill_from_address_id"))
+	a.DispatchFromAddressID, _ = strconv.Atoi(c.FormValue("dispatch_from_address_id"))
+	a.BillToAddressID, _ = strconv.Atoi(c.FormValue("bill_to_address_id"))
+	return a
+}
+
+// validateAmendment checks the amendment against the DC's current values.
+func validateAmendment(dc *models.DeliveryChallan, current, a *models.DCAmendment) map[string]string {
+	errors := map[string]string{}
+
+	if a.Reason == "" {
+		errors["reason"] = "A reason is required to amend a DC"
+	} else if len(a.Reason) > maxAmendmentReasonLen {
+		errors["reason"] = "Reason must be 500 characters or fewer"
+	}
+
+	if a.ChallanDate == nil {
+		errors["challan_date"] = "Challan date is required"
+	} else if _, err := time.Parse("2006-01-02", *a.ChallanDate); err != nil {
+		errors["challan_date"] = "Invalid challan date"
+	}
+
+	if dc.DCType == "official" {
+		if a.BillToAddressID > 0 {
+			if addr, err := database.GetAddress(a.BillToAddressID); err != nil || !addressInProjectConfig(addr, dc.ProjectID, "bill_to") {
+				errors["bill_to_address_id"] = "Invalid Bill To address"
+			}
+		}
+		if a.BillFromAddressID > 0 {
+			if addr, err := database.GetAddress(a.BillFromAddressID); err != nil || !addressInProjectConfig(addr, dc.ProjectID, "bill_from") {
+				errors["bill_from_address_id"] = "Invalid Bill From address"
+			}
+		}
+		if a.DispatchFromAddressID > 0 {
+			if addr, err := database.GetAddress(a.DispatchFromAddressID); err != nil || !addressInProjectConfig(addr, dc.ProjectID, "dispatch_from") {
+				errors["dispatch_from_address_id"] = "Invalid Dispatch From address"
+			}
+		}
+	}
+
+	if len(errors) == 0 && !amendmentChanges(dc, current, a) {
+		errors["general"] = "Nothing was changed; edit at least one field to create a new revision"
+	}
+	return errors
+}
+
+// addressInProjectConfig reports whether addr belongs to the project's address list of the given type.
+func addressInProjectConfig(addr *models.Address, projectID int, addressType string) bool {
+	if addr == nil {
+		return false
+	}
+	cfg, err := database.GetOrCreateAddressConfig(projectID, addressType)
+	if err != nil || cfg == nil {
+		return false
+	}
+	return addr.ConfigID == cfg.ID
+}
+
+// amendmentChanges reports whether a differs from the DC's current values.
+func amendmentChanges(dc *models.DeliveryChallan, current, a *models.DCAmendment) bool {
+	if dcChallanDateValue(current.ChallanDate) != dcChallanDateValue(a.ChallanDate) {
+		return true
+	}
+	if dc.DCType == "transit" {
+		return current.TransporterName != a.TransporterName ||
+			current.VehicleNumber != a.VehicleNumber ||
+			current.EwayBillNumber != a.EwayBillNumber ||
+			current.Notes != a.Notes
+	}
+	return current.BillFromAddressID != a.BillFromAddressID ||
+		current.DispatchFromAddressID != a.DispatchFromAddressID ||
+		current.BillToAddressID != a.BillToAddressID
+}
+
+// dcChallanDateValue dereferences an optional challan date.
+func dcChallanDateValue(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
+// renderAmendForm renders the amendment form, optionally with validation errors.
+func renderAmendForm(c echo.Context, project *models.Project, dc *models.DeliveryChallan, form *models.DCAmendment, errors map[string]string) error {
+	user := auth.GetCurrentUser(c)
+	allProjects, _ := database.GetAccessibleProjects(user)
+
+	var billFromAddresses, dispatchFromAddresses, billToAddresses []*models.Address
+	if dc.DCType == "official" {
+		if cfg, _ := database.GetOrCreateAddressConfig(project.ID, "bill_from"); cfg != nil {
+			billFromAddresses, _ = database.GetAllAddressesByConfigID(cfg.ID)
+		}
+		if cfg, _ := database.GetOrCreateAddressConfig(project.ID, "dispatch_from"); cfg != nil {
+			dispatchFromAddresses, _ = database.GetAllAddressesByConfigID(cfg.ID)
+		}
+		if cfg, _ := database.GetOrCreateAddressConfig(project.ID, "bill_to"); cfg != nil {
+			billToAddresses, _ = database.GetAllAddressesByConfigID(cfg.ID)
+		}
+	}
+
+	pageContent := deliverychallan.AmendForm(
+		user,
+		project,
+		allProjects,
+		dc,
+		form,
+		billFromAddresses,
+		dispatchFromAddresses,
+		billToAddresses,
+		errors,
+		csrf.Token(c.Request()),
+	)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, "", "")
+	return components.RenderOK(c, layouts.MainWithContent("Amend DC", sidebar, topbar, "", "", pageContent))
+}
+
+// ShowAmendDCForm handles GET /projects/:id/dcs/:dcid/amend.
+func ShowAmendDCForm(c echo.Context) error {
+	project, dc, redirect := amendableDC(c)
+	if redirect != "" {
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	return renderAmendForm(c, project, dc, amendmentFromDC(dc), map[string]string{})
+}
+
+// AmendDCHandler handles POST /projects/:id/dcs/:dcid/amend.
+// The DC's current state is kept as a read-only revision and the amendment
+// becomes revision N+1 under the same DC number.
+func AmendDCHandler(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+
+	project, dc, redirect := amendableDC(c)
+	if redirect != "" {
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	form := amendmentFromForm(c)
+	errors := validateAmendment(dc, amendmentFromDC(dc), form)
+	var dateGuard challanDateGuard
+	if len(errors) == 0 {
+		// The date order rule only applies when the amendment moves the date.
+		newDate := dcChallanDateValue(form.ChallanDate)
+		var dcTypes []string
+		if newDate != dcChallanDay(dc) {
+			dcTypes = []string{dc.DCType}
+		}
+		dateGuard = checkChallanDate(c, project.ID, newDate, dcChallanDay(dc), dc.ID, dcTypes...)
+		if dateGuard.Refused != "" {
+			errors["challan_date"] = dateGuard.Refused
+		}
+	}
+	if len(errors) > 0 {
+		return renderAmendForm(c, project, dc, form, errors)
+	}
+
+	before, _ := database.GetDCRevisionSnapshot(dc.ID)
+
+	revision, err := database.AmendDC(dc, form, user.ID)
+	if err != nil {
+		slog.Error("Failed to amend DC",
+			slog.Int("dc_id", dc.ID),
+			slog.Int("project_id", project.ID),
+			slog.Int("user_id", user.ID),
+			slog.String("error", err.Error()),
+		)
+		errors["general"] = "Failed to amend DC: " + err.Error()
+		return renderAmendForm(c, project, dc, form, errors)
+	}
+
+	if after, err := database.GetDCRevisionSnapshot(dc.ID); err == nil && before != nil {
+		recordAudit(c, project.ID, models.AuditEntityDC, dc.ID, models.AuditActionAmend,
+			fmt.Sprintf("Amended DC %s%s: %s", dc.DCNumber, models.RevisionSuffix(revision), form.Reason), before, after)
+	}
+	dateGuard.audit(c, project.ID, models.AuditEntityDC, dc.ID)
+
+	auth.SetFlash(c.Request(), "success", fmt.Sprintf("DC amended as %s%s", dc.DCNumber, models.RevisionSuffix(revision)))
+	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/dcs/%d", project.ID, dc.ID))
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// maxCancellationReasonLen caps the free-text reason stored on a cancelled DC.
+const maxCancellationReasonLen = 500
+
+// CancelRequest is the body accepted by the cancel endpoints.
+type CancelRequest struct {
+	Reason string `json:"reason" form:"reason"`
+}
+
+// parseCancellationReason binds and validates the cancellation reason.
+func parseCancellationReason(c echo.Context) (string, string) {
+	var req CancelRequest
+	if err := c.Bind(&req); err != nil {
+		return "", "Invalid request"
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return "", "A reason is required to cancel a DC"
+	}
+	if len(reason) > maxCancellationReasonLen {
+		return "", "Reason must be 500 characters or fewer"
+	}
+	return reason, ""
+}
+
+// CancelDCHandler handles POST /projects/:id/dcs/:dcid/cancel.
+// Official and Transfer DCs are cancelled on their own. A Transit DC carries the
+// goods for every Official DC in its shipment group, so cancelling it cancels the
+// whole group.
+func CancelDCHandler(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Invalid project ID"})
+	}
+
+	dcID, err := strconv.Atoi(c.Param("dcid"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Invalid DC ID"})
+	}
+
+	dc, err := database.GetDeliveryChallanByID(dcID)
+	if err != nil || dc.ProjectID != projectID {
+		return c.JSON(http.StatusNotFound, map[string]interface{}{"error": "DC not found"})
+	}
+	if dc.DCType == "return" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Return DCs cannot be cancelled; the returned serials have already been released"})
+	}
+
+	switch dc.Status {
+	case models.DCStatusIssued:
+	case models.DCStatusDraft:
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Draft DCs cannot be cancelled; delete the draft instead"})
+	case models.DCStatusCancelled:
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "DC is already cancelled"})
+	case models.DCStatusDelivered, models.DCStatusPartiallyDelivered:
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Delivered DCs cannot be cancelled"})
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "DC has splits; undo its splits before cancelling"})
+	}
+
+	if msg := closedYearMessage(dc); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
+
+	reason, msg := parseCancellationReason(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
+
+	if dc.DCType == "transit" && dc.ShipmentGroupID != nil {
+		return cancelGroup(c, projectID, *dc.ShipmentGroupID, user.ID, reason)
+	}
+
+	if dc.ShipmentGroupID != nil {
+		if _, err := database.GetSplitByShipmentGroupID(*dc.ShipmentGroupID); err == nil {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": "This DC was created by a Transfer DC split; undo the split instead",
+			})
+		}
+	}
+
+	if err := database.CancelDC(dcID, user.ID, reason); err != nil {
+		slog.Error("Failed to cancel DC",
+			slog.Int("dc_id", dcID),
+			slog.Int("project_id", projectID),
+			slog.Int("user_id", user.ID),
+			slog.String("error", err.Error()),
+		)
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to cancel DC: " + err.Error()})
+	}
+
+	recordAudit(c, projectID, models.AuditEntityDC, dcID, models.AuditActionCancel, "Cancelled DC "+dc.DCNumber,
+		map[string]interface{}{"status": dc.Status},
+		map[string]interface{}{"status": models.DCStatusCancelled, "cancellation_reason": reason})
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "DC " + dc.DCNumber + " cancelled",
+	})
+}
+
+// CancelShipmentGroupHandler handles POST /projects/:id/shipments/:gid/cancel.
+func CancelShipmentGroupHandler(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Invalid project ID"})
+	}
+
+	groupID, err := strconv.Atoi(c.Param("gid"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Invalid group ID"})
+	}
+
+	reason, msg := parseCancellationReason(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
+
+	return cancelGroup(c, projectID, groupID, user.ID, reason)
+}
+
+// cancelGroup validates and cancels every issued DC in a shipment group.
+func cancelGroup(c echo.Context, projectID, groupID, userID int, reason string) error {
+	group, err := database.GetShipmentGroup(groupID)
+	if err != nil || group.ProjectID != projectID {
+		return c.JSON(http.StatusNotFound, map[string]interface{}{"error": "Shipment group not found"})
+	}
+
+	if group.Status != "issued" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Only issued shipment groups can be cancelled"})
+	}
+
+	if _, err := database.GetSplitByShipmentGroupID(groupID); err == nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": "This shipment group was created by a Transfer DC split; undo the split instead",
+		})
+	}
+
+	groupDCs, err := database.GetDCsByShipmentGroup(groupID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to load shipment group DCs"})
+	}
+	for _, gdc := range groupDCs {
+		if msg := closedYearMessage(gdc); msg != "" {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "DC " + gdc.DCNumber + ": " + msg})
+		}
+		if models.IsDeliveryStatus(gdc.Status) {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": "DC " + gdc.DCNumber + " has a proof of delivery; a delivered shipment group cannot be cancelled",
+			})
+		}
+	}
+
+	count, err := database.CancelShipmentGroup(groupID, userID, reason)
+	switch {
+	case errors.Is(err, database.ErrShipmentGroupDelivered):
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": "A DC of this shipment group now has a proof of delivery; a delivered shipment group cannot be cancelled",
+		})
+	case errors.Is(err, database.ErrShipmentGroupNotIssued):
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Only issued shipment groups can be cancelled"})
+	}
+	if err != nil {
+		slog.Error("Failed to cancel shipment group",
+			slog.Int("group_id", groupID),
+			slog.Int("project_id", projectID),
+			slog.Int("user_id", userID),
+			slog.String("error", err.Error()),
+		)
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to cancel shipment group: " + err.Error()})
+	}
+
+	recordAudit(c, projectID, models.AuditEntityShipmentGroup, groupID, models.AuditActionCancel,
+		fmt.Sprintf("Cancelled %d DC(s) in shipment group #%d", count, groupID),
+		map[string]interface{}{"status": group.Status},
+		map[string]interface{}{"status": models.DCStatusCancelled, "cancellation_reason": reason})
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": strconv.Itoa(count) + " DC(s) cancelled",
+	})
+}
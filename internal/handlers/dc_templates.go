@@ -158,6 +158,9 @@ func CreateTemplateHandler(c echo.Context) error {
 		}))
 	}
 
+	recordAudit(c, projectID, models.AuditEntityTemplate, tmpl.ID, models.AuditActionCreate,
+		"Created template "+tmpl.Name, nil, templateAuditState(tmpl, selectedProducts))
+
 	c.Response().Header().Set("HX-Trigger", "templateChanged")
 	return components.RenderOK(c, htmxdctemplates.DCTemplateFormSuccess(htmxdctemplates.DCTemplateFormSuccessProps{
 		Message: "Template created successfully",
@@ -252,6 +255,7 @@ func ShowEditTemplateForm(c echo.Context) error {
 		Products:         products,
 		SelectedProducts: selectedProducts,
 		CsrfToken:        csrf.Token(c.Request()),
+		Version:          currentEditVersion(models.AuditEntityTemplate, templateID),
 	}))
 }
 
@@ -320,6 +324,7 @@ func UpdateTemplateHandler(c echo.Context) error {
 	}
 
 	allProducts, _ := database.GetProductsByProjectID(projectID)
+	version := formEditVersion(c)
 
 	if len(errors) > 0 {
 		return components.RenderOK(c, htmxdctemplates.DCTemplateForm(htmxdctemplates.DCTemplateFormProps{
@@ -330,13 +335,14 @@ func UpdateTemplateHandler(c echo.Context) error {
 			Products:         allProducts,
 			SelectedProducts: selectedProducts,
 			CsrfToken:        csrf.Token(c.Request()),
+			Version:          version,
 		}))
 	}
 
-	if err := database.UpdateTemplate(tmpl, products); err != nil {
-		slog.Error("Error updating template", slog.String("error", err.Error()), slog.Int("templateID", templateID), slog.Int("projectID", projectID))
-		errors["general"] = "Failed to update template"
-		return components.RenderOK(c, htmxdctemplates.DCTemplateForm(htmxdctemplates.DCTemplateFormProps{
+	existingProducts, _ := database.GetTemplateProductIDs(templateID)
+
+	if err := database.UpdateTemplate(tmpl, products, version); err != nil {
+		props := htmxdctemplates.DCTemplateFormProps{
 			ProjectID:        projectID,
 			Template:         *tmpl,
 			IsEdit:           true,
@@ -344,9 +350,24 @@ func UpdateTemplateHandler(c echo.Context) error {
 			Products:         allProducts,
 			SelectedProducts: selectedProducts,
 			CsrfToken:        csrf.Token(c.Request()),
-		}))
+			Version:          version,
+		}
+		if isStaleVersion(err) {
+			// Keep the user's values; saving again from the current version overwrites.
+			props.Conflict = newEditConflict(models.AuditEntityTemplate, templateID, templateID, version,
+				templateAuditState(existing, existingProducts), templateAuditState(tmpl, selectedProducts))
+			props.Version = props.Conflict.Version
+			errors["general"] = "This template was changed by someone else while you were editing. Review their changes below, then save again to overwrite them or cancel to keep them."
+		} else {
+			slog.Error("Error updating template", slog.String("error", err.Error()), slog.Int("templateID", templateID), slog.Int("projectID", projectID))
+			errors["general"] = "Failed to update template"
+		}
+		return components.RenderOK(c, htmxdctemplates.DCTemplateForm(props))
 	}
 
+	recordAudit(c, projectID, models.AuditEntityTemplate, templateID, models.AuditActionUpdate,
+		"Updated template "+tmpl.Name, templateAuditState(existing, existingProducts), templateAuditState(tmpl, selectedProducts))
+
 	c.Response().Header().Set("HX-Trigger", "templateChanged")
 	return components.RenderOK(c, htmxdctemplates.DCTemplateFormSuccess(htmxdctemplates.DCTemplateFormSuccessProps{
 		Message: "Template updated successfully",
@@ -370,6 +391,10 @@ func DuplicateTemplateHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
 	}
 
+	newProducts, _ := database.GetTemplateProductIDs(newTemplate.ID)
+	recordAudit(c, projectID, models.AuditEntityTemplate, newTemplate.ID, models.AuditActionCreate,
+		fmt.Sprintf("Duplicated template #%d as %s", templateID, newTemplate.Name), nil, templateAuditState(newTemplate, newProducts))
+
 	auth.SetFlash(c.Request(), "success", fmt.Sprintf("Template duplicated as '%s'", newTemplate.Name))
 	c.Response().Header().Set("HX-Redirect", fmt.Sprintf("/projects/%d/templates/%d", projectID, newTemplate.ID))
 	return c.JSON(http.StatusOK, map[string]interface{}{"success": true, "id": newTemplate.ID})
@@ -391,11 +416,26 @@ func DeleteTemplateHandler(c echo.Context) error {
 		return c.JSON(http.StatusNotFound, map[string]interface{}{"error": "Template not found"})
 	}
 
+	existingProducts, _ := database.GetTemplateProductIDs(templateID)
+
 	if err := database.DeleteTemplate(templateID, projectID); err != nil {
 		slog.Error("Error deleting template", slog.String("error", err.Error()), slog.Int("templateID", templateID), slog.Int("projectID", projectID))
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
 	}
 
+	recordAudit(c, projectID, models.AuditEntityTemplate, templateID, models.AuditActionDelete,
+		"Deleted template "+tmpl.Name, templateAuditState(tmpl, existingProducts), nil)
+
 	c.Response().Header().Set("HX-Trigger", "templateChanged")
 	return c.JSON(http.StatusOK, map[string]interface{}{"success": true, "message": "Template deleted successfully"})
 }
+
+// templateAuditState is the audited view of a template: its name, purpose and
+// product ID → default quantity map.
+func templateAuditState(tmpl *models.DCTemplate, productQty map[int]int) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     tmpl.Name,
+		"purpose":  tmpl.Purpose,
+		"products": productQty,
+	}
+}
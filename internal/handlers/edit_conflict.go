@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	errorpage "github.com/narendhupati/dc-management-tool/components/pages/error"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// maxConflictSaves caps how many intervening saves a conflict lists.
+const maxConflictSaves = 10
+
+// formEditVersion reads the edit version carried through an edit form.
+func formEditVersion(c echo.Context) int {
+	v, _ := strconv.Atoi(c.FormValue("edit_version"))
+	return v
+}
+
+// isStaleVersion reports whether a save was rejected because the record changed
+// after the form was opened.
+func isStaleVersion(err error) bool {
+	return errors.Is(err, database.ErrStaleVersion)
+}
+
+// currentEditVersion returns a record's edit version for a form being opened.
+// Failures are logged and yield 0, which every save refuses as stale.
+func currentEditVersion(entityType string, id int) int {
+	v, err := database.GetEditVersion(entityType, id)
+	if err != nil {
+		slog.Error("Failed to load edit version",
+			slog.String("entity_type", entityType),
+			slog.Int("entity_id", id),
+			slog.String("error", err.Error()),
+		)
+		return 0
+	}
+	return v
+}
+
+// newEditConflict describes a save of entityType/id rejected as stale. version is
+// the version the form was opened with, and auditID the entity ID the record's
+// audit events are stored under. current and submitted are the audited states of
+// the saved record and of the rejected values.
+func newEditConflict(entityType string, id, auditID, version int, current, submitted interface{}) *models.EditConflict {
+	conflict := &models.EditConflict{
+		Version: currentEditVersion(entityType, id),
+		Changes: models.DiffAudit(current, submitted),
+	}
+
+	// Every save bumps the version once, so the gap is the number of saves made
+	// since the form was opened.
+	n := conflict.Version - version
+	if n < 1 {
+		n = 1
+	}
+	if n > maxConflictSaves {
+		n = maxConflictSaves
+	}
+	res, err := database.ListAuditEvents(database.AuditFilters{
+		EntityType: entityType,
+		EntityID:   auditID,
+		Action:     models.AuditActionUpdate,
+		PageSize:   n,
+	})
+	if err != nil {
+		slog.Error("Failed to load saves for edit conflict",
+			slog.String("entity_type", entityType),
+			slog.Int("entity_id", auditID),
+			slog.String("error", err.Error()),
+		)
+	} else {
+		conflict.Saves = res.Events
+	}
+	return conflict
+}
+
+// renderEditConflict renders the conflict page for a rejected full-page save.
+func renderEditConflict(c echo.Context, project *models.Project, conflict *models.EditConflict) error {
+	user := auth.GetCurrentUser(c)
+	allProjects, _ := database.GetAccessibleProjects(user)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, "", "")
+	return components.Render(c, http.StatusConflict,
+		layouts.MainWithContent("Changes Not Saved", sidebar, topbar, "", "", errorpage.EditConflictPage(conflict)),
+	)
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	deliverychallan "github.com/narendhupati/dc-management-tool/components/pages/delivery_challans"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+// ewayBillNumberRe matches the 12-digit number the portal assigns to an e-way bill.
+var ewayBillNumberRe = regexp.MustCompile(`^[0-9]{12}$`)
+
+// buildDCEwayBill maps a transit or transfer DC onto an NIC e-way bill from the
+// master data it was issued with.
+func buildDCEwayBill(dc *models.DeliveryChallan) (*services.EwayBill, []string, error) {
+	snap, err := database.GetDCPrintSnapshot(dc)
+	if err != nil {
+		return nil, nil, err
+	}
+	lineItems, err := database.GetLineItemsByDCID(dc.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	snap.ApplyProducts(lineItems)
+
+	in := &services.EwayBillInput{
+		DC:           dc,
+		Project:      snap.Project,
+		Company:      snap.Company,
+		BillFrom:     snap.Address(models.SnapshotRoleBillFrom),
+		DispatchFrom: snap.Address(models.SnapshotRoleDispatchFrom),
+		BillTo:       snap.Address(models.SnapshotRoleBillTo),
+		ShipTo:       snap.Address(models.SnapshotRoleShipTo),
+		LineItems:    lineItems,
+	}
+
+	switch dc.DCType {
+	case "transfer":
+		tdc, err := database.GetTransferDCByDCID(dc.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if hub := snap.Address(models.SnapshotRoleHub); hub != nil {
+			in.ShipTo = hub
+		}
+		in.TaxType = tdc.TaxType
+		in.TransporterName = tdc.TransporterName
+		in.VehicleNumber = tdc.VehicleNumber
+	default:
+		td, err := database.GetTransitDetailsByDCID(dc.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if td != nil {
+			in.TransporterName = td.TransporterName
+			in.VehicleNumber = td.VehicleNumber
+		}
+		in.TaxType = transitTaxType(dc)
+	}
+
+	if in.TransporterGSTIN, err = database.GetTransporterGSTIN(dc.ProjectID, in.TransporterName); err != nil {
+		return nil, nil, err
+	}
+
+	bill, problems := services.BuildEwayBill(in)
+	return bill, problems, nil
+}
+
+// ewayBillCheck returns what stops a DC's e-way bill from being generated, or nil
+// when the DC does not move under an e-way bill in its current status.
+func ewayBillCheck(dc *models.DeliveryChallan) *models.EwayBillCheck {
+	if !models.EwayBillReady(dc) {
+		return nil
+	}
+	check := &models.EwayBillCheck{DCID: dc.ID, DCNumber: dc.DCNumber}
+	_, problems, err := buildDCEwayBill(dc)
+	if err != nil {
+		slog.Error("Failed to build e-way bill", slog.Int("dc_id", dc.ID), slog.String("error", err.Error()))
+		problems = []string{"The DC's data could not be loaded"}
+	}
+	check.Problems = problems
+	return check
+}
+
+// ExportDCEwayBillJSON handles GET /projects/:id/dcs/:dcid/eway-bill/json.
+// It downloads the NIC bulk-upload JSON for one transit or transfer DC, or lists
+// the missing fields when the portal would reject it.
+func ExportDCEwayBillJSON(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+
+	dcID, err := strconv.Atoi(c.Param("dcid"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Invalid DC ID"})
+	}
+	dc, err := database.GetDeliveryChallanByID(dcID)
+	if err != nil || dc.ProjectID != project.ID {
+		return c.JSON(http.StatusNotFound, map[string]interface{}{"error": "DC not found"})
+	}
+
+	detailURL := fmt.Sprintf("/projects/%d/dcs/%d", project.ID, dc.ID)
+	if !models.EwayBillReady(dc) {
+		auth.SetFlash(c.Request(), "error", "E-way bills are generated for issued Transit and Transfer DCs only")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+
+	bill, problems, err := buildDCEwayBill(dc)
+	if err != nil {
+		slog.Error("Failed to build e-way bill", slog.Int("dc_id", dcID), slog.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to generate e-way bill JSON"})
+	}
+	if len(problems) > 0 {
+		return renderEwayBillProblems(c, project, []*models.EwayBillCheck{
+			{DCID: dc.ID, DCNumber: dc.DCNumber, Problems: problems},
+		}, detailURL)
+	}
+
+	return sendEwayBillJSON(c, []services.EwayBill{*bill}, services.SanitizeDCFilename(dc.DCNumber)+"_ewb.json")
+}
+
+// ExportEwayBillBatchJSON handles GET /projects/:id/dcs-list/eway-bill.
+// It downloads one NIC bulk-upload file for the DCs ticked on the DC listing.
+// Nothing is downloaded unless every selected DC passes validation.
+func ExportEwayBillBatchJSON(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+	listURL := fmt.Sprintf("/projects/%d/dcs-list", project.ID)
+
+	params, _ := c.FormParams()
+	var ids []int
+	seen := make(map[int]bool)
+	for _, v := range params["dc_id"] {
+		id, err := strconv.Atoi(v)
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		auth.SetFlash(c.Request(), "error", "Select the Transit or Transfer DCs to generate e-way bills for")
+		return c.Redirect(http.StatusFound, listURL)
+	}
+
+	var bills []services.EwayBill
+	var failed []*models.EwayBillCheck
+	for _, id := range ids {
+		dc, err := database.GetDeliveryChallanByID(id)
+		if err != nil || dc.ProjectID != project.ID {
+			failed = append(failed, &models.EwayBillCheck{DCID: id, DCNumber: fmt.Sprintf("DC #%d", id), Problems: []string{"DC not found"}})
+			continue
+		}
+		if !models.EwayBillReady(dc) {
+			failed = append(failed, &models.EwayBillCheck{DCID: id, DCNumber: dc.DCNumber,
+				Problems: []string{"E-way bills are generated for issued Transit and Transfer DCs only"}})
+			continue
+		}
+		bill, problems, err := buildDCEwayBill(dc)
+		if err != nil {
+			slog.Error("Failed to build e-way bill", slog.Int("dc_id", id), slog.String("error", err.Error()))
+			problems = []string{"The DC's data could not be loaded"}
+		}
+		if len(problems) > 0 {
+			failed = append(failed, &models.EwayBillCheck{DCID: id, DCNumber: dc.DCNumber, Problems: problems})
+			continue
+		}
+		bills = append(bills, *bill)
+	}
+	if len(failed) > 0 {
+		return renderEwayBillProblems(c, project, failed, listURL)
+	}
+
+	filename := fmt.Sprintf("EWB_%s_%d_DCs.json", time.Now().Format("20060102"), len(bills))
+	return sendEwayBillJSON(c, bills, filename)
+}
+
+// sendEwayBillJSON writes bills as a downloadable NIC bulk-upload file.
+func sendEwayBillJSON(c echo.Context, bills []services.EwayBill, filename string) error {
+	data, err := services.MarshalEwayBills(bills)
+	if err != nil {
+		slog.Error("Failed to encode e-way bill JSON", slog.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to generate e-way bill JSON"})
+	}
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	return c.Blob(http.StatusOK, "application/json", data)
+}
+
+// renderEwayBillProblems lists the fields to fix before the e-way bill JSON can be downloaded.
+func renderEwayBillProblems(c echo.Context, project *models.Project, checks []*models.EwayBillCheck, backURL string) error {
+	user := auth.GetCurrentUser(c)
+	allProjects, _ := database.GetAccessibleProjects(user)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, "", "")
+	pageContent := deliverychallan.EwayBillProblems(project, checks, backURL)
+	return components.Render(c, http.StatusUnprocessableEntity,
+		layouts.MainWithContent("E-Way Bill", sidebar, topbar, "", "", pageContent),
+	)
+}
+
+// SaveEwayBillNumberHandler handles POST /projects/:id/dcs/:dcid/eway-bill.
+// It stores the number the portal generated for the DC's e-way bill. A number
+// is recorded once, while the DC's financial year is open; changing it later is
+// an amendment of the DC.
+func SaveEwayBillNumberHandler(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+
+	dcID, err := strconv.Atoi(c.Param("dcid"))
+	if err != nil {
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", project.ID))
+	}
+	dc, err := database.GetDeliveryChallanByID(dcID)
+	if err != nil || dc.ProjectID != project.ID {
+		auth.SetFlash(c.Request(), "error", "DC not found")
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", project.ID))
+	}
+
+	detailURL := fmt.Sprintf("/projects/%d/dcs/%d", project.ID, dcID)
+	if !models.EwayBillReady(dc) {
+		auth.SetFlash(c.Request(), "error", "E-way bill numbers are recorded against issued Transit and Transfer DCs only")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+	if msg := closedYearMessage(dc); msg != "" {
+		auth.SetFlash(c.Request(), "error", msg)
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+	if currentEwayBillNumber(dc) != "" {
+		auth.SetFlash(c.Request(), "error", ewayBillRecordedMessage(dc))
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+
+	number := strings.ReplaceAll(strings.TrimSpace(c.FormValue("eway_bill_number")), " ", "")
+	if !ewayBillNumberRe.MatchString(number) {
+		auth.SetFlash(c.Request(), "error", "An e-way bill number is 12 digits")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+
+	if err := database.SetEwayBillNumber(dc, number); err != nil {
+		if errors.Is(err, database.ErrEwayBillRecorded) {
+			auth.SetFlash(c.Request(), "error", ewayBillRecordedMessage(dc))
+			return c.Redirect(http.StatusFound, detailURL)
+		}
+		slog.Error("Failed to save e-way bill number",
+			slog.Int("dc_id", dcID),
+			slog.Int("project_id", project.ID),
+			slog.String("error", err.Error()),
+		)
+		auth.SetFlash(c.Request(), "error", "Failed to save the e-way bill number")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+
+	recordAudit(c, project.ID, models.AuditEntityDC, dcID, models.AuditActionUpdate,
+		fmt.Sprintf("Recorded e-way bill %s for DC %s", number, dc.DCNumber),
+		map[string]interface{}{"eway_bill_number": ""},
+		map[string]interface{}{"eway_bill_number": number})
+
+	auth.SetFlash(c.Request(), "success", "E-way bill "+number+" saved")
+	return c.Redirect(http.StatusFound, detailURL)
+}
+
+// ewayBillRecordedMessage explains how to change an e-way bill number already
+// recorded for a DC.
+func ewayBillRecordedMessage(dc *models.DeliveryChallan) string {
+	if dc.DCType == "transit" {
+		return "This DC already has an e-way bill number; amend the DC to change it"
+	}
+	return "This DC already has an e-way bill number"
+}
+
+// currentEwayBillNumber returns the e-way bill number stored for a transit or transfer DC.
+func currentEwayBillNumber(dc *models.DeliveryChallan) string {
+	if dc.DCType == "transfer" {
+		if tdc, err := database.GetTransferDCByDCID(dc.ID); err == nil && tdc != nil {
+			return tdc.EwayBillNumber
+		}
+		return ""
+	}
+	if td, err := database.GetTransitDetailsByDCID(dc.ID); err == nil && td != nil {
+		return td.EwayBillNumber
+	}
+	return ""
+}
@@ -53,19 +53,23 @@ func generatePDFForDC(projectID, dcID int, dc *models.DeliveryChallan) ([]byte,
 		return buildOfficialPDF(projectID, dcID, dc)
 	case "transfer":
 		return buildTransferPDF(projectID, dcID, dc)
+	case "return":
+		return buildReturnPDF(dc)
 	default:
 		return buildTransitPDF(projectID, dcID, dc)
 	}
 }
 
 func buildTransitPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte, error) {
-	project, err := database.GetProjectByID(projectID)
+	snap, err := database.GetDCPrintSnapshot(dc)
 	if err != nil {
 		return nil, err
 	}
+	project := snap.Project
 
 	transitDetails, _ := database.GetTransitDetailsByDCID(dcID)
 	lineItems, _ := database.GetLineItemsByDCID(dcID)
+	snap.ApplyProducts(lineItems)
 	for i := range lineItems {
 		serials, _ := database.GetSerialNumbersByLineItemID(lineItems[i].ID)
 		lineItems[i].SerialNumbers = serials
@@ -83,24 +87,12 @@ func buildTransitPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte, e
 	roundOff := roundedTotal - grandTotal
 	halfTax := totalTax / 2.0
 
-	var shipToAddress, billToAddress *models.Address
-	if dc.ShipToAddressID > 0 {
-		shipToAddress, _ = database.GetAddress(dc.ShipToAddressID)
-	}
-	if dc.BillToAddressID != nil && *dc.BillToAddressID > 0 {
-		billToAddress, _ = database.GetAddress(*dc.BillToAddressID)
-	}
-
-	// Fetch Bill From and Dispatch From addresses selected during DC creation
-	var billFromAddress, dispatchFromAddress *models.Address
-	if dc.BillFromAddressID != nil && *dc.BillFromAddressID > 0 {
-		billFromAddress, _ = database.GetAddress(*dc.BillFromAddressID)
-	}
-	if dc.DispatchFromAddressID != nil && *dc.DispatchFromAddressID > 0 {
-		dispatchFromAddress, _ = database.GetAddress(*dc.DispatchFromAddressID)
-	}
+	shipToAddress := snap.Address(models.SnapshotRoleShipTo)
+	billToAddress := snap.Address(models.SnapshotRoleBillTo)
+	billFromAddress := snap.Address(models.SnapshotRoleBillFrom)
+	dispatchFromAddress := snap.Address(models.SnapshotRoleDispatchFrom)
 
-	company, _ := database.GetCompanySettings()
+	company := snap.Company
 	amountInWords := helpers.NumberToIndianWords(roundedTotal)
 
 	// Look up parent Transfer DC number if this transit DC came from a split
@@ -119,10 +111,10 @@ func buildTransitPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte, e
 	}
 
 	// Fetch address configs for print column filtering
-	shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
-	billToConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_to")
-	billFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_from")
-	dispatchFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "dispatch_from")
+	shipToConfig := snap.AddressConfig("ship_to")
+	billToConfig := snap.AddressConfig("bill_to")
+	billFromConfig := snap.AddressConfig("bill_from")
+	dispatchFromConfig := snap.AddressConfig("dispatch_from")
 
 	return services.GenerateTransitDCPDF(&services.TransitDCPDFData{
 		Project:            project,
@@ -147,16 +139,32 @@ func buildTransitPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte, e
 		TotalQty:           totalQty,
 		AmountInWords:      amountInWords,
 		TransferDCNumber:   transferDCNumber,
+		TaxType:            transitTaxType(dc),
 	})
 }
 
+// transitTaxType returns the tax type of a transit DC's shipment group, or ""
+// when the DC has no group.
+func transitTaxType(dc *models.DeliveryChallan) string {
+	if dc.ShipmentGroupID == nil {
+		return ""
+	}
+	group, err := database.GetShipmentGroup(*dc.ShipmentGroupID)
+	if err != nil {
+		return ""
+	}
+	return group.TaxType
+}
+
 func buildOfficialPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte, error) {
-	project, err := database.GetProjectByID(projectID)
+	snap, err := database.GetDCPrintSnapshot(dc)
 	if err != nil {
 		return nil, err
 	}
+	project := snap.Project
 
 	lineItems, _ := database.GetLineItemsByDCID(dcID)
+	snap.ApplyProducts(lineItems)
 	for i := range lineItems {
 		serials, _ := database.GetSerialNumbersByLineItemID(lineItems[i].ID)
 		lineItems[i].SerialNumbers = serials
@@ -167,45 +175,21 @@ func buildOfficialPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte,
 		totalQty += li.Quantity
 	}
 
-	var shipToAddress, billToAddress, billFromAddress, dispatchFromAddress *models.Address
-	if dc.ShipToAddressID > 0 {
-		shipToAddress, _ = database.GetAddress(dc.ShipToAddressID)
-	}
-	if dc.BillToAddressID != nil && *dc.BillToAddressID > 0 {
-		billToAddress, _ = database.GetAddress(*dc.BillToAddressID)
-	}
-	if dc.BillFromAddressID != nil && *dc.BillFromAddressID > 0 {
-		billFromAddress, _ = database.GetAddress(*dc.BillFromAddressID)
-	}
-	if dc.DispatchFromAddressID != nil && *dc.DispatchFromAddressID > 0 {
-		dispatchFromAddress, _ = database.GetAddress(*dc.DispatchFromAddressID)
-	}
-	company, _ := database.GetCompanySettings()
-
-	// Fetch transit details and inherit addresses from parent TDC in the same shipment group
-	var transitDetails *models.DCTransitDetails
-	if dc.ShipmentGroupID != nil && *dc.ShipmentGroupID > 0 {
-		groupDCs, _ := database.GetDCsByShipmentGroup(*dc.ShipmentGroupID)
-		for _, groupDC := range groupDCs {
-			if groupDC.DCType == "transit" {
-				transitDetails, _ = database.GetTransitDetailsByDCID(groupDC.ID)
-				// Fall back to TDC's addresses for ODCs created before address inheritance was added
-				if billFromAddress == nil && groupDC.BillFromAddressID != nil && *groupDC.BillFromAddressID > 0 {
-					billFromAddress, _ = database.GetAddress(*groupDC.BillFromAddressID)
-				}
-				if dispatchFromAddress == nil && groupDC.DispatchFromAddressID != nil && *groupDC.DispatchFromAddressID > 0 {
-					dispatchFromAddress, _ = database.GetAddress(*groupDC.DispatchFromAddressID)
-				}
-				break
-			}
-		}
-	}
+	// Addresses come from the snapshot, which already falls back to the parent TDC's
+	// bill-from/dispatch-from for ODCs created before address inheritance was added
+	shipToAddress := snap.Address(models.SnapshotRoleShipTo)
+	billToAddress := snap.Address(models.SnapshotRoleBillTo)
+	billFromAddress := snap.Address(models.SnapshotRoleBillFrom)
+	dispatchFromAddress := snap.Address(models.SnapshotRoleDispatchFrom)
+	company := snap.Company
+
+	transitDetails := groupTransitDetails(dc)
 
 	// Fetch address configs for print column filtering
-	shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
-	billToConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_to")
-	billFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_from")
-	dispatchFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "dispatch_from")
+	shipToConfig := snap.AddressConfig("ship_to")
+	billToConfig := snap.AddressConfig("bill_to")
+	billFromConfig := snap.AddressConfig("bill_from")
+	dispatchFromConfig := snap.AddressConfig("dispatch_from")
 
 	return services.GenerateOfficialDCPDF(&services.OfficialDCPDFData{
 		Project:             project,
@@ -225,6 +209,60 @@ func buildOfficialPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte,
 	})
 }
 
+// buildReturnPDF prints a return DC with one line per returned product. The goods
+// come back from the snapshotted ship-to address to the dispatch-from address.
+func buildReturnPDF(dc *models.DeliveryChallan) ([]byte, error) {
+	snap, err := database.GetDCPrintSnapshot(dc)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := database.GetReturnDCByDCID(dc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lineItems []models.DCLineItem
+	if ret != nil {
+		for _, p := range ret.Products() {
+			lineItems = append(lineItems, models.DCLineItem{
+				ProductID:     p.ProductID,
+				ItemName:      p.ItemName,
+				Quantity:      len(p.Serials),
+				SerialNumbers: p.Serials,
+			})
+		}
+	}
+	snap.ApplyProducts(lineItems)
+
+	return services.GenerateReturnDCPDF(&services.ReturnDCPDFData{
+		Project:             snap.Project,
+		DC:                  dc,
+		Return:              ret,
+		Company:             snap.Company,
+		LineItems:           lineItems,
+		ReturnedFromAddress: snap.Address(models.SnapshotRoleShipTo),
+		ReturnedToAddress:   snap.Address(models.SnapshotRoleDispatchFrom),
+		ReturnedFromConfig:  snap.AddressConfig("ship_to"),
+		ReturnedToConfig:    snap.AddressConfig("dispatch_from"),
+	})
+}
+
+// groupTransitDetails returns the transit details of the transit DC in an official DC's
+// shipment group, or nil when the DC is not part of a group.
+func groupTransitDetails(dc *models.DeliveryChallan) *models.DCTransitDetails {
+	if dc.ShipmentGroupID == nil || *dc.ShipmentGroupID <= 0 {
+		return nil
+	}
+	groupDCs, _ := database.GetDCsByShipmentGroup(*dc.ShipmentGroupID)
+	for _, groupDC := range groupDCs {
+		if groupDC.DCType == "transit" {
+			transitDetails, _ := database.GetTransitDetailsByDCID(groupDC.ID)
+			return transitDetails
+		}
+	}
+	return nil
+}
+
 // ExportDCExcel generates and serves an Excel file for a DC.
 func ExportDCExcel(c echo.Context) error {
 	projectID, err := strconv.Atoi(c.Param("id"))
@@ -241,28 +279,31 @@ func ExportDCExcel(c echo.Context) error {
 	if err != nil || dc.ProjectID != projectID {
 		return c.JSON(http.StatusNotFound, map[string]interface{}{"error": "DC not found"})
 	}
+	if dc.DCType == "return" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Return DCs are exported as PDF only"})
+	}
 
-	project, _ := database.GetProjectByID(projectID)
+	snap, err := database.GetDCPrintSnapshot(dc)
+	if err != nil {
+		slog.Error("error loading DC snapshot for Excel", slog.String("error", err.Error()), slog.Int("dcID", dcID), slog.Int("projectID", projectID))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to generate Excel"})
+	}
+	project := snap.Project
 	lineItems, _ := database.GetLineItemsByDCID(dcID)
+	snap.ApplyProducts(lineItems)
 	for i := range lineItems {
 		serials, _ := database.GetSerialNumbersByLineItemID(lineItems[i].ID)
 		lineItems[i].SerialNumbers = serials
 	}
 
-	var shipToAddress, billToAddress *models.Address
-	if dc.ShipToAddressID > 0 {
-		shipToAddress, _ = database.GetAddress(dc.ShipToAddressID)
-	}
-	if dc.BillToAddressID != nil && *dc.BillToAddressID > 0 {
-		billToAddress, _ = database.GetAddress(*dc.BillToAddressID)
-	}
-
-	company, _ := database.GetCompanySettings()
+	shipToAddress := snap.Address(models.SnapshotRoleShipTo)
+	billToAddress := snap.Address(models.SnapshotRoleBillTo)
+	company := snap.Company
 
 	filename := services.SanitizeDCFilename(dc.DCNumber) + ".xlsx"
 
 	if dc.DCType == "transfer" {
-		return buildTransferExcel(c, projectID, dc, lineItems, project, company, shipToAddress, billToAddress, filename)
+		return buildTransferExcel(c, snap, dc, lineItems, filename)
 	}
 
 	if dc.DCType == "official" {
@@ -271,38 +312,17 @@ func ExportDCExcel(c echo.Context) error {
 			totalQty += li.Quantity
 		}
 
-		// Fetch Bill From and Dispatch From addresses
-		var billFromAddress, dispatchFromAddress *models.Address
-		if dc.BillFromAddressID != nil && *dc.BillFromAddressID > 0 {
-			billFromAddress, _ = database.GetAddress(*dc.BillFromAddressID)
-		}
-		if dc.DispatchFromAddressID != nil && *dc.DispatchFromAddressID > 0 {
-			dispatchFromAddress, _ = database.GetAddress(*dc.DispatchFromAddressID)
-		}
+		billFromAddress := snap.Address(models.SnapshotRoleBillFrom)
+		dispatchFromAddress := snap.Address(models.SnapshotRoleDispatchFrom)
 
 		// Fetch transit details from parent TDC in shipment group
-		var transitDetails *models.DCTransitDetails
-		if dc.ShipmentGroupID != nil && *dc.ShipmentGroupID > 0 {
-			groupDCs, _ := database.GetDCsByShipmentGroup(*dc.ShipmentGroupID)
-			for _, groupDC := range groupDCs {
-				if groupDC.DCType == "transit" {
-					transitDetails, _ = database.GetTransitDetailsByDCID(groupDC.ID)
-					if billFromAddress == nil && groupDC.BillFromAddressID != nil && *groupDC.BillFromAddressID > 0 {
-						billFromAddress, _ = database.GetAddress(*groupDC.BillFromAddressID)
-					}
-					if dispatchFromAddress == nil && groupDC.DispatchFromAddressID != nil && *groupDC.DispatchFromAddressID > 0 {
-						dispatchFromAddress, _ = database.GetAddress(*groupDC.DispatchFromAddressID)
-					}
-					break
-				}
-			}
-		}
+		transitDetails := groupTransitDetails(dc)
 
 		// Fetch address configs for print column filtering
-		shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
-		billToConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_to")
-		billFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_from")
-		dispatchFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "dispatch_from")
+		shipToConfig := snap.AddressConfig("ship_to")
+		billToConfig := snap.AddressConfig("bill_to")
+		billFromConfig := snap.AddressConfig("bill_from")
+		dispatchFromConfig := snap.AddressConfig("dispatch_from")
 
 		excelFile, err := services.GenerateOfficialDCExcel(&services.OfficialDCExcelData{
 			DC:                  dc,
@@ -341,20 +361,14 @@ func ExportDCExcel(c echo.Context) error {
 			totalQty += li.Quantity
 		}
 
-		// Fetch Bill From and Dispatch From addresses
-		var billFromAddress, dispatchFromAddress *models.Address
-		if dc.BillFromAddressID != nil && *dc.BillFromAddressID > 0 {
-			billFromAddress, _ = database.GetAddress(*dc.BillFromAddressID)
-		}
-		if dc.DispatchFromAddressID != nil && *dc.DispatchFromAddressID > 0 {
-			dispatchFromAddress, _ = database.GetAddress(*dc.DispatchFromAddressID)
-		}
+		billFromAddress := snap.Address(models.SnapshotRoleBillFrom)
+		dispatchFromAddress := snap.Address(models.SnapshotRoleDispatchFrom)
 
 		// Fetch address configs for print column filtering
-		shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
-		billToConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_to")
-		billFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_from")
-		dispatchFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "dispatch_from")
+		shipToConfig := snap.AddressConfig("ship_to")
+		billToConfig := snap.AddressConfig("bill_to")
+		billFromConfig := snap.AddressConfig("bill_from")
+		dispatchFromConfig := snap.AddressConfig("dispatch_from")
 
 		excelFile, err := services.GenerateTransitDCExcel(&services.TransitDCExcelData{
 			DC:                  dc,
@@ -378,6 +392,7 @@ func ExportDCExcel(c echo.Context) error {
 			HalfTax:             halfTax,
 			TotalQty:            totalQty,
 			AmountInWords:       amountInWords,
+			TaxType:             transitTaxType(dc),
 		})
 		if err != nil {
 			slog.Error("error generating transit DC Excel", slog.String("error", err.Error()), slog.Int("dcID", dcID), slog.Int("projectID", projectID))
@@ -395,14 +410,16 @@ func ExportDCExcel(c echo.Context) error {
 }
 
 func buildTransferPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte, error) {
-	project, err := database.GetProjectByID(projectID)
+	snap, err := database.GetDCPrintSnapshot(dc)
 	if err != nil {
 		return nil, err
 	}
+	project := snap.Project
 
 	tdc, _ := database.GetTransferDCByDCID(dcID)
 
 	lineItems, _ := database.GetLineItemsByDCID(dcID)
+	snap.ApplyProducts(lineItems)
 	for i := range lineItems {
 		serials, _ := database.GetSerialNumbersByLineItemID(lineItems[i].ID)
 		lineItems[i].SerialNumbers = serials
@@ -420,22 +437,12 @@ func buildTransferPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte,
 	roundOff := roundedTotal - grandTotal
 	halfTax := totalTax / 2.0
 
-	// Fetch addresses
-	var billToAddress, billFromAddress, dispatchFromAddress, hubAddress *models.Address
-	if dc.BillToAddressID != nil && *dc.BillToAddressID > 0 {
-		billToAddress, _ = database.GetAddress(*dc.BillToAddressID)
-	}
-	if dc.BillFromAddressID != nil && *dc.BillFromAddressID > 0 {
-		billFromAddress, _ = database.GetAddress(*dc.BillFromAddressID)
-	}
-	if dc.DispatchFromAddressID != nil && *dc.DispatchFromAddressID > 0 {
-		dispatchFromAddress, _ = database.GetAddress(*dc.DispatchFromAddressID)
-	}
-	if tdc != nil && tdc.HubAddressID > 0 {
-		hubAddress, _ = database.GetAddress(tdc.HubAddressID)
-	}
+	billToAddress := snap.Address(models.SnapshotRoleBillTo)
+	billFromAddress := snap.Address(models.SnapshotRoleBillFrom)
+	dispatchFromAddress := snap.Address(models.SnapshotRoleDispatchFrom)
+	hubAddress := snap.Address(models.SnapshotRoleHub)
 
-	company, _ := database.GetCompanySettings()
+	company := snap.Company
 	amountInWords := helpers.NumberToIndianWords(roundedTotal)
 
 	// Build destinations and products for the breakdown table
@@ -448,12 +455,11 @@ func buildTransferPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte,
 			for _, q := range d.Quantities {
 				qtyMap[q.ProductID] = q.Quantity
 			}
-			// Fetch full address for proper display name and PDF filtering
+			// Use the frozen address for proper display name and PDF filtering
 			destName := d.AddressName
-			var fullAddr *models.Address
-			if addr, err := database.GetAddress(d.ShipToAddressID); err == nil && addr != nil {
-				destName = addr.DisplayName()
-				fullAddr = addr
+			fullAddr := snap.DestinationAddress(d.ShipToAddressID)
+			if fullAddr != nil {
+				destName = fullAddr.DisplayName()
 			}
 			destinations = append(destinations, services.TransferDCPDFDestination{
 				Name:       destName,
@@ -471,10 +477,10 @@ func buildTransferPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte,
 	}
 
 	// Fetch address configs for print column filtering
-	billFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_from")
-	dispatchFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "dispatch_from")
-	billToConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_to")
-	shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
+	billFromConfig := snap.AddressConfig("bill_from")
+	dispatchFromConfig := snap.AddressConfig("dispatch_from")
+	billToConfig := snap.AddressConfig("bill_to")
+	shipToConfig := snap.AddressConfig("ship_to")
 
 	return services.GenerateTransferDCPDF(&services.TransferDCPDFData{
 		Project:             project,
@@ -503,8 +509,9 @@ func buildTransferPDF(projectID, dcID int, dc *models.DeliveryChallan) ([]byte,
 	})
 }
 
-func buildTransferExcel(c echo.Context, projectID int, dc *models.DeliveryChallan, lineItems []models.DCLineItem, project *models.Project, company *models.CompanySettings, shipToAddress, billToAddress *models.Address, filename string) error {
+func buildTransferExcel(c echo.Context, snap *models.DCSnapshot, dc *models.DeliveryChallan, lineItems []models.DCLineItem, filename string) error {
 	tdc, _ := database.GetTransferDCByDCID(dc.ID)
+	project, company := snap.Project, snap.Company
 
 	totalTaxable, totalTax, grandTotal, roundedTotal, roundOff, _, _ := services.CalcTransitTotals(lineItems)
 	halfTax := totalTax / 2.0
@@ -515,17 +522,10 @@ func buildTransferExcel(c echo.Context, projectID int, dc *models.DeliveryChalla
 		totalQty += li.Quantity
 	}
 
-	// Fetch addresses
-	var billFromAddress, dispatchFromAddress, hubAddress *models.Address
-	if dc.BillFromAddressID != nil && *dc.BillFromAddressID > 0 {
-		billFromAddress, _ = database.GetAddress(*dc.BillFromAddressID)
-	}
-	if dc.DispatchFromAddressID != nil && *dc.DispatchFromAddressID > 0 {
-		dispatchFromAddress, _ = database.GetAddress(*dc.DispatchFromAddressID)
-	}
-	if tdc != nil && tdc.HubAddressID > 0 {
-		hubAddress, _ = database.GetAddress(tdc.HubAddressID)
-	}
+	billToAddress := snap.Address(models.SnapshotRoleBillTo)
+	billFromAddress := snap.Address(models.SnapshotRoleBillFrom)
+	dispatchFromAddress := snap.Address(models.SnapshotRoleDispatchFrom)
+	hubAddress := snap.Address(models.SnapshotRoleHub)
 
 	// Build destinations and products
 	var destinations []services.TransferDCExcelDestination
@@ -537,8 +537,12 @@ func buildTransferExcel(c echo.Context, projectID int, dc *models.DeliveryChalla
 			for _, q := range d.Quantities {
 				qtyMap[q.ProductID] = q.Quantity
 			}
+			destName := d.AddressName
+			if addr := snap.DestinationAddress(d.ShipToAddressID); addr != nil {
+				destName = addr.DisplayName()
+			}
 			destinations = append(destinations, services.TransferDCExcelDestination{
-				Name:       d.AddressName,
+				Name:       destName,
 				Quantities: qtyMap,
 			})
 		}
@@ -551,9 +555,9 @@ func buildTransferExcel(c echo.Context, projectID int, dc *models.DeliveryChalla
 	}
 
 	// Fetch address configs
-	billFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_from")
-	dispatchFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "dispatch_from")
-	billToConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_to")
+	billFromConfig := snap.AddressConfig("bill_from")
+	dispatchFromConfig := snap.AddressConfig("dispatch_from")
+	billToConfig := snap.AddressConfig("bill_to")
 
 	excelFile, err := services.GenerateTransferDCExcel(&services.TransferDCExcelData{
 		DC:                  dc,
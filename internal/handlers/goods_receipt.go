@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	deliverychallan "github.com/narendhupati/dc-management-tool/components/pages/delivery_challans"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// receivableDC loads the project and DC for the goods receipt routes and checks that
+// the DC has been issued. On failure it sets a flash message and returns a redirect URL.
+func receivableDC(c echo.Context) (*models.Project, *models.DeliveryChallan, string) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, nil, "/projects"
+	}
+
+	dcID, err := strconv.Atoi(c.Param("dcid"))
+	if err != nil {
+		return nil, nil, fmt.Sprintf("/projects/%d", projectID)
+	}
+
+	project, err := database.GetProjectByID(projectID)
+	if err != nil {
+		return nil, nil, "/projects"
+	}
+
+	dc, err := database.GetDeliveryChallanByID(dcID)
+	if err != nil || dc.ProjectID != projectID {
+		auth.SetFlash(c.Request(), "error", "DC not found")
+		return nil, nil, fmt.Sprintf("/projects/%d", projectID)
+	}
+
+	detailURL := fmt.Sprintf("/projects/%d/dcs/%d", projectID, dcID)
+	if dc.DCType != "official" {
+		auth.SetFlash(c.Request(), "error", "Goods receipts are recorded against Official DCs only")
+		return nil, nil, detailURL
+	}
+	if dc.Status != models.DCStatusIssued && !models.IsDeliveryStatus(dc.Status) {
+		auth.SetFlash(c.Request(), "error", "Only issued DCs can have a goods receipt")
+		return nil, nil, detailURL
+	}
+	return project, dc, ""
+}
+
+// goodsReceiptFromForm reads the submitted receipt onto lines holding the DC's
+// dispatched quantities and serials.
+func goodsReceiptFromForm(c echo.Context, dc *models.DeliveryChallan, lines []models.GoodsReceiptLine) *models.GoodsReceipt {
+	r := &models.GoodsReceipt{
+		DCID:         dc.ID,
+		ReceivedDate: strings.TrimSpace(c.FormValue("received_date")),
+		Remarks:      strings.TrimSpace(c.FormValue("remarks")),
+		Lines:        lines,
+	}
+	for i := range r.Lines {
+		l := &r.Lines[i]
+		field := func(name string) string {
+			return c.FormValue(fmt.Sprintf("%s_%d", name, l.LineItemID))
+		}
+		l.Received, _ = strconv.Atoi(strings.TrimSpace(field("received")))
+		l.Short, _ = strconv.Atoi(strings.TrimSpace(field("short")))
+		l.Damaged, _ = strconv.Atoi(strings.TrimSpace(field("damaged")))
+		l.DamagedSerials = parseSerialNumbers(field("damaged_serials"))
+		l.MissingSerials = parseSerialNumbers(field("missing_serials"))
+	}
+	return r
+}
+
+// renderGoodsReceiptForm renders the goods receipt form with any validation errors.
+func renderGoodsReceiptForm(c echo.Context, project *models.Project, dc *models.DeliveryChallan, receipt *models.GoodsReceipt, errors map[string]string) error {
+	user := auth.GetCurrentUser(c)
+	allProjects, _ := database.GetAccessibleProjects(user)
+
+	pageContent := deliverychallan.GoodsReceiptForm(
+		user,
+		project,
+		allProjects,
+		dc,
+		receipt,
+		errors,
+		csrf.Token(c.Request()),
+	)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, "", "")
+	return components.RenderOK(c, layouts.MainWithContent("Goods Receipt", sidebar, topbar, "", "", pageContent))
+}
+
+// ShowGoodsReceiptForm handles GET /projects/:id/dcs/:dcid/receipt.
+// It pre-fills the recorded receipt, or every dispatched unit as received.
+func ShowGoodsReceiptForm(c echo.Context) error {
+	project, dc, redirect := receivableDC(c)
+	if redirect != "" {
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	receipt, err := database.GetGoodsReceipt(dc.ID)
+	if err != nil {
+		slog.Error("Error fetching goods receipt", slog.Int("dc_id", dc.ID), slog.String("error", err.Error()))
+	}
+	if receipt == nil {
+		lines, err := database.NewGoodsReceiptLines(dc.ID)
+		if err != nil {
+			auth.SetFlash(c.Request(), "error", "Failed to load DC line items")
+			return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/dcs/%d", project.ID, dc.ID))
+		}
+		receipt = &models.GoodsReceipt{DCID: dc.ID, Lines: lines}
+	}
+	return renderGoodsReceiptForm(c, project, dc, receipt, map[string]string{})
+}
+
+// SaveGoodsReceiptHandler handles POST /projects/:id/dcs/:dcid/receipt.
+func SaveGoodsReceiptHandler(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+
+	project, dc, redirect := receivableDC(c)
+	if redirect != "" {
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	before, _ := database.GetGoodsReceipt(dc.ID)
+
+	lines, err := database.NewGoodsReceiptLines(dc.ID)
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Failed to load DC line items")
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/dcs/%d", project.ID, dc.ID))
+	}
+
+	receipt := goodsReceiptFromForm(c, dc, lines)
+	receipt.RecordedBy = &user.ID
+	errors := receipt.Validate()
+	if len(errors) > 0 {
+		return renderGoodsReceiptForm(c, project, dc, receipt, errors)
+	}
+
+	if err := database.SaveGoodsReceipt(receipt); err != nil {
+		slog.Error("Failed to save goods receipt",
+			slog.Int("dc_id", dc.ID),
+			slog.Int("project_id", project.ID),
+			slog.Int("user_id", user.ID),
+			slog.String("error", err.Error()),
+		)
+		errors["general"] = "Failed to save goods receipt: " + err.Error()
+		return renderGoodsReceiptForm(c, project, dc, receipt, errors)
+	}
+
+	if after, err := database.GetGoodsReceipt(dc.ID); err == nil && after != nil {
+		action := models.AuditActionCreate
+		var beforeState interface{}
+		if before != nil {
+			action = models.AuditActionUpdate
+			beforeState = before
+		}
+		recordAudit(c, project.ID, models.AuditEntityDC, dc.ID, action,
+			"Recorded goods receipt for DC "+dc.DCNumber, beforeState, after)
+	}
+
+	if receipt.HasDiscrepancy() {
+		auth.SetFlash(c.Request(), "warning", "Goods receipt saved with shortage or damage for DC "+dc.DCNumber)
+	} else {
+		auth.SetFlash(c.Request(), "success", "Goods receipt saved for DC "+dc.DCNumber)
+	}
+	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/dcs/%d", project.ID, dc.ID))
+}
@@ -133,6 +133,21 @@ func ShowOfficialDCDetail(c echo.Context) error {
 	breadcrumbItems = append(breadcrumbItems, helpers.Breadcrumb{Title: dc.DCNumber, URL: ""})
 	_ = helpers.BuildBreadcrumbs(breadcrumbItems...)
 
+	revisions, err := database.GetDCRevisionHistory(dc)
+	if err != nil {
+		slog.Error("Error fetching DC revisions", slog.Int("dc_id", dcID), slog.String("error", err.Error()))
+	}
+
+	pods, err := database.GetProofsOfDelivery(dcID)
+	if err != nil {
+		slog.Error("Error fetching proofs of delivery", slog.Int("dc_id", dcID), slog.String("error", err.Error()))
+	}
+
+	receipt, err := database.GetGoodsReceipt(dcID)
+	if err != nil {
+		slog.Error("Error fetching goods receipt", slog.Int("dc_id", dcID), slog.String("error", err.Error()))
+	}
+
 	allProjects, _ := database.GetAccessibleProjects(user)
 
 	pageContent := deliverychallan.OfficialDetail(
@@ -153,6 +168,9 @@ func ShowOfficialDCDetail(c echo.Context) error {
 		siblingDCs,
 		dcPosition,
 		officialCount,
+		revisions,
+		pods,
+		receipt,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
@@ -171,12 +189,6 @@ func ShowOfficialDCPrintView(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", projectID))
 	}
 
-	project, err := database.GetProjectByID(projectID)
-	if err != nil {
-		slog.Error("Error fetching project for print view", slog.Int("project_id", projectID), slog.String("error", err.Error()))
-		return c.Redirect(http.StatusFound, "/projects")
-	}
-
 	dc, err := database.GetDeliveryChallanByID(dcID)
 	if err != nil || dc.ProjectID != projectID {
 		if err != nil {
@@ -186,7 +198,15 @@ func ShowOfficialDCPrintView(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", projectID))
 	}
 
+	// Issued DCs print from the data frozen at issue
+	snap, err := database.GetDCPrintSnapshot(dc)
+	if err != nil {
+		slog.Error("Error loading DC snapshot for print view", slog.Int("dc_id", dcID), slog.String("error", err.Error()))
+		return c.Redirect(http.StatusFound, "/projects")
+	}
+
 	lineItemsVal, _ := database.GetLineItemsByDCID(dcID)
+	snap.ApplyProducts(lineItemsVal)
 
 	// Load serial numbers for each line item
 	for i := range lineItemsVal {
@@ -197,26 +217,19 @@ func ShowOfficialDCPrintView(c echo.Context) error {
 	lineItems := lineItemsToPointers(lineItemsVal)
 
 	// Get addresses
-	var shipToAddress, billToAddress, billFromAddress *models.Address
-	if dc.ShipToAddressID > 0 {
-		shipToAddress, _ = database.GetAddress(dc.ShipToAddressID)
-	}
-	if dc.BillToAddressID != nil && *dc.BillToAddressID > 0 {
-		billToAddress, _ = database.GetAddress(*dc.BillToAddressID)
-	}
-	if dc.BillFromAddressID != nil && *dc.BillFromAddressID > 0 {
-		billFromAddress, _ = database.GetAddress(*dc.BillFromAddressID)
-	}
+	shipToAddress := snap.Address(models.SnapshotRoleShipTo)
+	billToAddress := snap.Address(models.SnapshotRoleBillTo)
+	billFromAddress := snap.Address(models.SnapshotRoleBillFrom)
 
 	// Get company settings
-	company, _ := database.GetCompanySettings()
+	company := snap.Company
 
 	// Get address configs for print filtering
-	shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
-	billToConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_to")
+	shipToConfig := snap.AddressConfig("ship_to")
+	billToConfig := snap.AddressConfig("bill_to")
 
 	return components.RenderOK(c, deliverychallan.OfficialPrint(
-		project,
+		snap.Project,
 		dc,
 		lineItems,
 		shipToAddress,
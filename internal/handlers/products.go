@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -105,11 +106,12 @@ func ShowAddProductForm(c echo.Context) error {
 	}
 
 	return components.RenderOK(c, htmxproducts.ProductForm(htmxproducts.ProductFormProps{
-		ProjectID: projectID,
-		Product:   models.Product{UoM: "Nos"},
-		IsEdit:    false,
-		Errors:    map[string]string{},
-		CsrfToken: csrf.Token(c.Request()),
+		ProjectID:        projectID,
+		Product:          models.Product{UoM: "Nos"},
+		ComponentOptions: kitComponentOptions(projectID, 0),
+		IsEdit:           false,
+		Errors:           map[string]string{},
+		CsrfToken:        csrf.Token(c.Request()),
 	}))
 }
 
@@ -121,6 +123,7 @@ func CreateProductHandler(c echo.Context) error {
 
 	price, _ := strconv.ParseFloat(c.FormValue("per_unit_price"), 64)
 	gst, _ := strconv.ParseFloat(c.FormValue("gst_percentage"), 64)
+	warranty, _ := strconv.Atoi(strings.TrimSpace(c.FormValue("warranty_months")))
 
 	product := &models.Product{
 		ProjectID:       projectID,
@@ -132,6 +135,10 @@ func CreateProductHandler(c echo.Context) error {
 		BrandModel:      strings.TrimSpace(c.FormValue("brand_model")),
 		PerUnitPrice:    price,
 		GSTPercentage:   gst,
+		SerialRule: parseSerialRule(c.FormValue("serial_pattern"), c.FormValue("serial_min_length"),
+			c.FormValue("serial_max_length"), c.FormValue("serial_prefix"), c.FormValue("serial_luhn")),
+		WarrantyMonths: warranty,
+		Components:     parseKitComponents(c),
 	}
 
 	errors := helpers.ValidateStruct(product)
@@ -141,6 +148,12 @@ func CreateProductHandler(c echo.Context) error {
 			errors["hsn_code"] = "HSN code must be 6-8 digits"
 		}
 	}
+	for field, msg := range product.SerialRule.Validate() {
+		errors[field] = msg
+	}
+	if msg := validateKitComponents(product); msg != "" {
+		errors["components"] = msg
+	}
 
 	// Check name uniqueness
 	if _, ok := errors["item_name"]; !ok && product.ItemName != "" {
@@ -166,11 +179,12 @@ func CreateProductHandler(c echo.Context) error {
 
 	if len(errors) > 0 {
 		return components.RenderOK(c, htmxproducts.ProductForm(htmxproducts.ProductFormProps{
-			ProjectID: projectID,
-			Product:   *product,
-			IsEdit:    false,
-			Errors:    errors,
-			CsrfToken: csrfToken,
+			ProjectID:        projectID,
+			Product:          *product,
+			ComponentOptions: kitComponentOptions(projectID, product.ID),
+			IsEdit:           false,
+			Errors:           errors,
+			CsrfToken:        csrfToken,
 		}))
 	}
 
@@ -178,26 +192,31 @@ func CreateProductHandler(c echo.Context) error {
 		slog.Error("error creating product", slog.String("error", err.Error()), slog.Int("projectID", projectID))
 		errors["general"] = "Failed to create product"
 		return components.RenderOK(c, htmxproducts.ProductForm(htmxproducts.ProductFormProps{
-			ProjectID: projectID,
-			Product:   *product,
-			IsEdit:    false,
-			Errors:    errors,
-			CsrfToken: csrfToken,
+			ProjectID:        projectID,
+			Product:          *product,
+			ComponentOptions: kitComponentOptions(projectID, product.ID),
+			IsEdit:           false,
+			Errors:           errors,
+			CsrfToken:        csrfToken,
 		}))
 	}
 
+	recordAudit(c, projectID, models.AuditEntityProduct, product.ID, models.AuditActionCreate,
+		"Created product "+product.ItemName, nil, product)
+
 	saveAndAdd := c.FormValue("save_and_add") == "true"
 
 	if saveAndAdd {
 		// Return a fresh form for adding another product
 		c.Response().Header().Set("HX-Trigger", "productChanged")
 		return components.RenderOK(c, htmxproducts.ProductForm(htmxproducts.ProductFormProps{
-			ProjectID:      projectID,
-			Product:        models.Product{UoM: "Nos"},
-			IsEdit:         false,
-			Errors:         map[string]string{},
-			CsrfToken:      csrfToken,
-			SuccessMessage: "Product added! Add another below.",
+			ProjectID:        projectID,
+			Product:          models.Product{UoM: "Nos"},
+			ComponentOptions: kitComponentOptions(projectID, 0),
+			IsEdit:           false,
+			Errors:           map[string]string{},
+			CsrfToken:        csrfToken,
+			SuccessMessage:   "Product added! Add another below.",
 		}))
 	}
 
@@ -225,11 +244,13 @@ func ShowEditProductForm(c echo.Context) error {
 	}
 
 	return components.RenderOK(c, htmxproducts.ProductForm(htmxproducts.ProductFormProps{
-		ProjectID: projectID,
-		Product:   *product,
-		IsEdit:    true,
-		Errors:    map[string]string{},
-		CsrfToken: csrf.Token(c.Request()),
+		ProjectID:        projectID,
+		Product:          *product,
+		ComponentOptions: kitComponentOptions(projectID, product.ID),
+		IsEdit:           true,
+		Errors:           map[string]string{},
+		CsrfToken:        csrf.Token(c.Request()),
+		Version:          currentEditVersion(models.AuditEntityProduct, productID),
 	}))
 }
 
@@ -251,6 +272,7 @@ func UpdateProductHandler(c echo.Context) error {
 
 	price, _ := strconv.ParseFloat(c.FormValue("per_unit_price"), 64)
 	gst, _ := strconv.ParseFloat(c.FormValue("gst_percentage"), 64)
+	warranty, _ := strconv.Atoi(strings.TrimSpace(c.FormValue("warranty_months")))
 
 	product := &models.Product{
 		ID:              productID,
@@ -263,6 +285,10 @@ func UpdateProductHandler(c echo.Context) error {
 		BrandModel:      strings.TrimSpace(c.FormValue("brand_model")),
 		PerUnitPrice:    price,
 		GSTPercentage:   gst,
+		SerialRule: parseSerialRule(c.FormValue("serial_pattern"), c.FormValue("serial_min_length"),
+			c.FormValue("serial_max_length"), c.FormValue("serial_prefix"), c.FormValue("serial_luhn")),
+		WarrantyMonths: warranty,
+		Components:     parseKitComponents(c),
 	}
 
 	errors := helpers.ValidateStruct(product)
@@ -272,6 +298,12 @@ func UpdateProductHandler(c echo.Context) error {
 			errors["hsn_code"] = "HSN code must be 6-8 digits"
 		}
 	}
+	for field, msg := range product.SerialRule.Validate() {
+		errors[field] = msg
+	}
+	if msg := validateKitComponents(product); msg != "" {
+		errors["components"] = msg
+	}
 
 	// Check name uniqueness excluding current product
 	if _, ok := errors["item_name"]; !ok && product.ItemName != "" {
@@ -294,27 +326,47 @@ func UpdateProductHandler(c echo.Context) error {
 	}
 
 	csrfToken := csrf.Token(c.Request())
+	version := formEditVersion(c)
 
 	if len(errors) > 0 {
 		return components.RenderOK(c, htmxproducts.ProductForm(htmxproducts.ProductFormProps{
-			ProjectID: projectID,
-			Product:   *product,
-			IsEdit:    true,
-			Errors:    errors,
-			CsrfToken: csrfToken,
+			ProjectID:        projectID,
+			Product:          *product,
+			ComponentOptions: kitComponentOptions(projectID, product.ID),
+			IsEdit:           true,
+			Errors:           errors,
+			CsrfToken:        csrfToken,
+			Version:          version,
 		}))
 	}
 
-	if err := database.UpdateProductRecord(product); err != nil {
-		slog.Error("error updating product", slog.String("error", err.Error()), slog.Int("productID", productID), slog.Int("projectID", projectID))
-		errors["general"] = "Failed to update product"
-		return components.RenderOK(c, htmxproducts.ProductForm(htmxproducts.ProductFormProps{
-			ProjectID: projectID,
-			Product:   *product,
-			IsEdit:    true,
-			Errors:    errors,
-			CsrfToken: csrfToken,
-		}))
+	if err := database.UpdateProductRecord(product, version); err != nil {
+		props := htmxproducts.ProductFormProps{
+			ProjectID:        projectID,
+			Product:          *product,
+			ComponentOptions: kitComponentOptions(projectID, product.ID),
+			IsEdit:           true,
+			Errors:           errors,
+			CsrfToken:        csrfToken,
+			Version:          version,
+		}
+		if isStaleVersion(err) {
+			// Keep the user's values; saving again from the current version overwrites.
+			props.Conflict = newEditConflict(models.AuditEntityProduct, productID, productID, version, existing, product)
+			props.Version = props.Conflict.Version
+			errors["general"] = "This product was changed by someone else while you were editing. Review their changes below, then save again to overwrite them or cancel to keep them."
+		} else if kitComponentsLocked(err) {
+			errors["components"] = "This product is already on delivery challans, so its kit components cannot change. Create a new product for the new kit."
+		} else {
+			slog.Error("error updating product", slog.String("error", err.Error()), slog.Int("productID", productID), slog.Int("projectID", projectID))
+			errors["general"] = "Failed to update product"
+		}
+		return components.RenderOK(c, htmxproducts.ProductForm(props))
+	}
+
+	if updated, err := database.GetProductByID(productID); err == nil {
+		recordAudit(c, projectID, models.AuditEntityProduct, productID, models.AuditActionUpdate,
+			"Updated product "+updated.ItemName, existing, updated)
 	}
 
 	c.Response().Header().Set("HX-Trigger", "productChanged")
@@ -344,6 +396,9 @@ func DeleteProductHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
 	}
 
+	recordAudit(c, projectID, models.AuditEntityProduct, productID, models.AuditActionDelete,
+		"Deleted product "+product.ItemName, product, nil)
+
 	c.Response().Header().Set("HX-Trigger", "productChanged")
 	return c.String(http.StatusOK, "")
 }
@@ -372,6 +427,10 @@ func BulkDeleteProductsHandler(c echo.Context) error {
 	}
 
 	deleted, errs := database.BulkDeleteProducts(ids, projectID)
+	if deleted > 0 {
+		recordAudit(c, projectID, models.AuditEntityProduct, 0, models.AuditActionDelete,
+			fmt.Sprintf("Bulk deleted %d product(s)", deleted), nil, nil)
+	}
 
 	c.Response().Header().Set("HX-Trigger", "productChanged")
 	if len(errs) > 0 {
@@ -447,6 +506,9 @@ func ImportProductsHandler(c echo.Context) error {
 				errs["hsn_code"] = "HSN code must be 6-8 digits"
 			}
 		}
+		for field, msg := range product.SerialRule.Validate() {
+			errs[field] = msg
+		}
 
 		if _, ok := errs["item_name"]; !ok && product.ItemName != "" {
 			unique, _ := database.CheckProductNameUnique(projectID, product.ItemName, 0)
@@ -478,6 +540,11 @@ func ImportProductsHandler(c echo.Context) error {
 		result.Successful++
 	}
 
+	if result.Successful > 0 {
+		recordAudit(c, projectID, models.AuditEntityProduct, 0, models.AuditActionImport,
+			fmt.Sprintf("Imported %d product(s) from %s (%d failed)", result.Successful, header.Filename, result.Failed), nil, nil)
+	}
+
 	c.Response().Header().Set("HX-Trigger", "productChanged")
 	return components.RenderOK(c, htmxproducts.ProductImportResult(htmxproducts.ProductImportResultProps{
 		Result: result,
@@ -485,8 +552,10 @@ func ImportProductsHandler(c echo.Context) error {
 }
 
 func DownloadProductImportTemplate(c echo.Context) error {
-	header := []string{"Item Name", "Description", "HSN Code", "UoM", "Brand/Model", "Per Unit Price", "GST %"}
-	example := []string{"Solar Panel 400W", "Monocrystalline 400W solar panel", "85414011", "Nos", "Tata Power Solar", "10000.00", "18"}
+	header := []string{"Item Name", "Description", "HSN Code", "UoM", "Brand/Model", "Per Unit Price", "GST %",
+		"Serial Pattern", "Serial Min Length", "Serial Max Length", "Serial Prefix", "Serial Luhn Check", "Warranty Months"}
+	example := []string{"Solar Panel 400W", "Monocrystalline 400W solar panel", "85414011", "Nos", "Tata Power Solar", "10000.00", "18",
+		"[A-Z0-9]+", "12", "14", "TPS", "N", "60"}
 
 	c.Response().Header().Set("Content-Type", "text/csv")
 	c.Response().Header().Set("Content-Disposition", "attachment; filename=product_import_template.csv")
@@ -537,6 +606,19 @@ func autoMapProductColumns(headers []string) map[string]int {
 	for i, h := range headers {
 		h = strings.ToLower(strings.TrimSpace(h))
 		switch {
+		// Serial rule columns first: "Serial Min Length" must not map to UoM etc.
+		case strings.Contains(h, "serial") && (strings.Contains(h, "pattern") || strings.Contains(h, "regex")):
+			colMap["serial_pattern"] = i
+		case strings.Contains(h, "serial") && strings.Contains(h, "min"):
+			colMap["serial_min_length"] = i
+		case strings.Contains(h, "serial") && strings.Contains(h, "max"):
+			colMap["serial_max_length"] = i
+		case strings.Contains(h, "serial") && strings.Contains(h, "prefix"):
+			colMap["serial_prefix"] = i
+		case strings.Contains(h, "serial") && strings.Contains(h, "luhn"):
+			colMap["serial_luhn"] = i
+		case strings.Contains(h, "warranty"):
+			colMap["warranty_months"] = i
 		case strings.Contains(h, "item") && strings.Contains(h, "name"):
 			colMap["item_name"] = i
 		case strings.Contains(h, "description") || strings.Contains(h, "desc"):
@@ -566,6 +648,7 @@ func mapRowToProduct(row []string, colMap map[string]int, projectID int) *models
 
 	price, _ := strconv.ParseFloat(getVal("per_unit_price"), 64)
 	gst, _ := strconv.ParseFloat(getVal("gst_percentage"), 64)
+	warranty, _ := strconv.Atoi(getVal("warranty_months"))
 
 	uom := getVal("uom")
 	if uom == "" {
@@ -581,5 +664,103 @@ func mapRowToProduct(row []string, colMap map[string]int, projectID int) *models
 		BrandModel:      getVal("brand_model"),
 		PerUnitPrice:    price,
 		GSTPercentage:   gst,
+		SerialRule: parseSerialRule(getVal("serial_pattern"), getVal("serial_min_length"),
+			getVal("serial_max_length"), getVal("serial_prefix"), getVal("serial_luhn")),
+		WarrantyMonths: warranty,
+	}
+}
+
+// parseSerialRule builds a product's serial rule from form or import values.
+// Unparseable lengths count as unset; the Luhn flag accepts a checkbox or Y/Yes/1/True.
+func parseSerialRule(pattern, minLength, maxLength, prefix, luhn string) models.SerialRule {
+	minLen, _ := strconv.Atoi(strings.TrimSpace(minLength))
+	maxLen, _ := strconv.Atoi(strings.TrimSpace(maxLength))
+	switch strings.ToLower(strings.TrimSpace(luhn)) {
+	case "on", "y", "yes", "1", "true":
+		luhn = "true"
+	}
+	return models.SerialRule{
+		Pattern:   strings.TrimSpace(pattern),
+		MinLength: minLen,
+		MaxLength: maxLen,
+		Prefix:    strings.TrimSpace(prefix),
+		Luhn:      luhn == "true",
+	}
+}
+
+// parseKitComponents reads the kit component rows of the product form,
+// skipping rows without a product. A quantity that does not parse is left at
+// 0 so validation reports it.
+func parseKitComponents(c echo.Context) []models.KitComponent {
+	form, _ := c.FormParams()
+	quantities := form["component_quantity"]
+	var components []models.KitComponent
+	for i, raw := range form["component_product_id"] {
+		id, _ := strconv.Atoi(strings.TrimSpace(raw))
+		if id <= 0 {
+			continue
+		}
+		qty := 0
+		if i < len(quantities) {
+			qty, _ = strconv.Atoi(strings.TrimSpace(quantities[i]))
+		}
+		components = append(components, models.KitComponent{ProductID: id, Quantity: qty})
+	}
+	return components
+}
+
+// kitComponentOptions lists the products of a project that can be components
+// of productID (0 for a new product): every other product that is not a kit.
+func kitComponentOptions(projectID, productID int) []*models.Product {
+	products, err := database.GetProductsByProjectID(projectID)
+	if err != nil {
+		slog.Warn("error loading kit component options", slog.String("error", err.Error()), slog.Int("projectID", projectID))
+		return nil
+	}
+	var options []*models.Product
+	for _, p := range products {
+		if p.ID != productID && !p.IsKit() {
+			options = append(options, p)
+		}
+	}
+	return options
+}
+
+// validateKitComponents checks a product's bill of materials against its
+// project and fills in the component names, returning "" when it is usable.
+func validateKitComponents(product *models.Product) string {
+	if len(product.Components) == 0 {
+		return ""
+	}
+	projectProducts, err := database.GetProductsByProjectID(product.ProjectID)
+	if err != nil {
+		slog.Error("error loading products for kit validation", slog.String("error", err.Error()), slog.Int("projectID", product.ProjectID))
+		return "Could not check the kit components"
+	}
+	usedAsComponent := false
+	if product.ID != 0 {
+		if usedAsComponent, err = database.IsKitComponent(product.ID); err != nil {
+			slog.Error("error checking kit component usage", slog.String("error", err.Error()), slog.Int("productID", product.ID))
+			return "Could not check the kit components"
+		}
+	}
+	if msg := models.ValidateKitComponents(product.ID, product.Components, projectProducts, usedAsComponent); msg != "" {
+		return msg
 	}
+	for i := range product.Components {
+		for _, p := range projectProducts {
+			if p.ID == product.Components[i].ProductID {
+				product.Components[i].ItemName = p.ItemName
+				product.Components[i].BrandModel = p.BrandModel
+				product.Components[i].SerialRule = p.SerialRule
+			}
+		}
+	}
+	return ""
+}
+
+// kitComponentsLocked reports whether a save was refused because it changed
+// the components of a kit that is already on a DC.
+func kitComponentsLocked(err error) bool {
+	return errors.Is(err, database.ErrKitComponentsLocked)
 }
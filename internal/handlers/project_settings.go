@@ -15,6 +15,8 @@ import (
 	"github.com/narendhupati/dc-management-tool/internal/auth"
 	"github.com/narendhupati/dc-management-tool/internal/components"
 	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/helpers"
+	"github.com/narendhupati/dc-management-tool/internal/models"
 	"github.com/narendhupati/dc-management-tool/internal/services"
 )
 
@@ -42,6 +44,10 @@ func ShowProjectSettings(c echo.Context) error {
 		activeTab = "general"
 	}
 
+	approval, candidates := loadApprovalSettingsTab(id, activeTab)
+	dates, years := loadFinancialYearsTab(id, activeTab)
+	tally := loadTallyTab(id, activeTab)
+	serialRegistryMode, warrantyMonths := loadSerialsTab(id, activeTab)
 	pageContent := pageprojects.Settings(
 		user,
 		project,
@@ -51,6 +57,13 @@ func ShowProjectSettings(c echo.Context) error {
 		flashType,
 		flashMessage,
 		activeTab,
+		approval,
+		candidates,
+		dates,
+		years,
+		tally,
+		serialRegistryMode,
+		warrantyMonths,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
@@ -75,8 +88,21 @@ func UpdateProjectSettings(c echo.Context) error {
 	if tab == "" {
 		tab = "general"
 	}
+	if tab == "approvals" {
+		return updateApprovalSettings(c, id)
+	}
+	if tab == "financial_years" {
+		return updateDateOrderSetting(c, id)
+	}
+	if tab == "tally" {
+		return updateTallySettings(c, id)
+	}
+	if tab == "serials" {
+		return updateSerialRegistryMode(c, id)
+	}
 
 	// Start with existing project and update only the relevant tab fields
+	before := *existing
 	project := existing
 
 	errors := make(map[string]string)
@@ -105,8 +131,10 @@ func UpdateProjectSettings(c echo.Context) error {
 		project.SignatoryDesignation = strings.TrimSpace(c.FormValue("signatory_designation"))
 		project.SignatoryMobile = strings.TrimSpace(c.FormValue("signatory_mobile"))
 
-		if project.CompanyGSTIN != "" && len(project.CompanyGSTIN) != 15 {
-			errors["company_gstin"] = "GSTIN must be exactly 15 characters"
+		if project.CompanyGSTIN != "" {
+			if err := helpers.ValidateGSTIN(project.CompanyGSTIN); err != nil {
+				errors["company_gstin"] = err.Error()
+			}
 		}
 		if project.CompanyEmail != "" && !strings.Contains(project.CompanyEmail, "@") {
 			errors["company_email"] = "Invalid email address"
@@ -167,6 +195,13 @@ func UpdateProjectSettings(c echo.Context) error {
 			"",
 			"",
 			tab,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			"",
+			0,
 		)
 		sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 		topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -179,10 +214,318 @@ func UpdateProjectSettings(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/settings?tab=%s", id, tab))
 	}
 
+	if updated, err := database.GetProjectByID(id); err == nil {
+		recordAudit(c, id, models.AuditEntityProjectSettings, id, models.AuditActionUpdate,
+			"Updated "+models.AuditLabel(tab)+" settings", &before, updated)
+	}
+
 	auth.SetFlash(c.Request(), "success", "Settings saved successfully")
 	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/settings?tab=%s", id, tab))
 }
 
+// loadApprovalSettingsTab loads the approval settings and approver candidates
+// shown on the approvals tab; other tabs get nil.
+func loadApprovalSettingsTab(projectID int, tab string) (*models.ApprovalSettings, []*models.User) {
+	if tab != "approvals" {
+		return nil, nil
+	}
+	approval, err := database.GetApprovalSettings(projectID)
+	if err != nil {
+		slog.Error("Error fetching approval settings", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+		return nil, nil
+	}
+	return approval, approverCandidates(projectID)
+}
+
+// approverCandidates returns the active users who may be made approvers on a
+// project: those assigned to it, plus admins.
+func approverCandidates(projectID int) []*models.User {
+	assigned, _ := database.GetProjectAssignedUsers(projectID)
+	all, _ := database.GetAllUsers()
+
+	seen := make(map[int]bool)
+	var candidates []*models.User
+	for _, u := range assigned {
+		if u.IsActive && !seen[u.ID] {
+			seen[u.ID] = true
+			candidates = append(candidates, u)
+		}
+	}
+	for _, u := range all {
+		if u.IsActive && u.IsAdmin() && !seen[u.ID] {
+			seen[u.ID] = true
+			candidates = append(candidates, u)
+		}
+	}
+	return candidates
+}
+
+// updateApprovalSettings saves the approvals tab. Approvers are limited to the
+// project's approver candidates.
+func updateApprovalSettings(c echo.Context, projectID int) error {
+	redirect := fmt.Sprintf("/projects/%d/settings?tab=approvals", projectID)
+
+	before, err := database.GetApprovalSettings(projectID)
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Failed to load approval settings")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	allowed := make(map[int]bool)
+	for _, u := range approverCandidates(projectID) {
+		allowed[u.ID] = true
+	}
+	form, _ := c.FormParams()
+	settings := &models.ApprovalSettings{
+		ProjectID:       projectID,
+		RequireApproval: c.FormValue("require_approval") == "1",
+	}
+	for _, v := range form["approver_ids"] {
+		if uid, convErr := strconv.Atoi(v); convErr == nil && allowed[uid] && !settings.IsApprover(uid) {
+			settings.ApproverIDs = append(settings.ApproverIDs, uid)
+		}
+	}
+	if settings.RequireApproval && len(settings.ApproverIDs) == 0 {
+		auth.SetFlash(c.Request(), "error", "Select at least one approver to require approval")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	if err := database.SaveApprovalSettings(settings); err != nil {
+		slog.Error("Error updating approval settings", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to save settings")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	recordAudit(c, projectID, models.AuditEntityProjectSettings, projectID, models.AuditActionUpdate,
+		"Updated Approvals settings", before, settings)
+
+	auth.SetFlash(c.Request(), "success", "Settings saved successfully")
+	return c.Redirect(http.StatusFound, redirect)
+}
+
+// loadFinancialYearsTab loads the challan date settings and the financial years
+// listed on the financial years tab: those with DC numbers or a closure, plus
+// the current year. Other tabs get nil.
+func loadFinancialYearsTab(projectID int, tab string) (*models.DCDateSettings, []string) {
+	if tab != "financial_years" {
+		return nil, nil
+	}
+	dates, err := database.GetDCDateSettings(projectID)
+	if err != nil {
+		slog.Error("Error fetching financial year settings", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+		return nil, nil
+	}
+	years, err := database.ListDCFinancialYears(projectID, services.GetCurrentFinancialYear())
+	if err != nil {
+		slog.Error("Error listing financial years", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+	}
+	return dates, years
+}
+
+// updateDateOrderSetting saves the challan date order rule from the financial
+// years tab.
+func updateDateOrderSetting(c echo.Context, projectID int) error {
+	redirect := fmt.Sprintf("/projects/%d/settings?tab=financial_years", projectID)
+
+	before, err := database.GetDCDateSettings(projectID)
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Failed to load financial year settings")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	enforce := c.FormValue("enforce_date_order") == "1"
+	if err := database.SetEnforceDateOrder(projectID, enforce); err != nil {
+		slog.Error("Error updating date order rule", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to save settings")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	recordAudit(c, projectID, models.AuditEntityProjectSettings, projectID, models.AuditActionUpdate,
+		"Updated Financial Years settings",
+		map[string]interface{}{"enforce_date_order": before.EnforceDateOrder},
+		map[string]interface{}{"enforce_date_order": enforce})
+
+	auth.SetFlash(c.Request(), "success", "Settings saved successfully")
+	return c.Redirect(http.StatusFound, redirect)
+}
+
+// loadTallyTab loads the Tally master names shown on the Tally tab; other tabs get nil.
+func loadTallyTab(projectID int, tab string) *models.TallySettings {
+	if tab != "tally" {
+		return nil
+	}
+	tally, err := database.GetTallySettings(projectID)
+	if err != nil {
+		slog.Error("Error fetching Tally settings", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+		return nil
+	}
+	return tally
+}
+
+// updateTallySettings saves the Tally tab. Every master name except the Tally
+// company is required: Tally refuses vouchers that post to a blank name.
+func updateTallySettings(c echo.Context, projectID int) error {
+	redirect := fmt.Sprintf("/projects/%d/settings?tab=tally", projectID)
+
+	before, err := database.GetTallySettings(projectID)
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Failed to load Tally settings")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	settings := &models.TallySettings{
+		ProjectID:      projectID,
+		CompanyName:    strings.TrimSpace(c.FormValue("tally_company_name")),
+		VoucherType:    strings.TrimSpace(c.FormValue("tally_voucher_type")),
+		SalesLedger:    strings.TrimSpace(c.FormValue("tally_sales_ledger")),
+		CGSTLedger:     strings.TrimSpace(c.FormValue("tally_cgst_ledger")),
+		SGSTLedger:     strings.TrimSpace(c.FormValue("tally_sgst_ledger")),
+		IGSTLedger:     strings.TrimSpace(c.FormValue("tally_igst_ledger")),
+		PartyGroup:     strings.TrimSpace(c.FormValue("tally_party_group")),
+		Godown:         strings.TrimSpace(c.FormValue("tally_godown")),
+		IncludeMasters: c.FormValue("tally_include_masters") == "1",
+	}
+	for _, f := range []struct{ label, value string }{
+		{"Voucher type", settings.VoucherType},
+		{"Sales ledger", settings.SalesLedger},
+		{"Party ledger group", settings.PartyGroup},
+		{"CGST ledger", settings.CGSTLedger},
+		{"SGST ledger", settings.SGSTLedger},
+		{"IGST ledger", settings.IGSTLedger},
+		{"Godown", settings.Godown},
+	} {
+		if f.value == "" {
+			auth.SetFlash(c.Request(), "error", f.label+" is required")
+			return c.Redirect(http.StatusFound, redirect)
+		}
+	}
+
+	if err := database.SaveTallySettings(settings); err != nil {
+		slog.Error("Error updating Tally settings", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to save settings")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	recordAudit(c, projectID, models.AuditEntityProjectSettings, projectID, models.AuditActionUpdate,
+		"Updated Tally settings", before, settings)
+
+	auth.SetFlash(c.Request(), "success", "Settings saved successfully")
+	return c.Redirect(http.StatusFound, redirect)
+}
+
+// loadSerialsTab loads the serial registry mode and warranty override shown
+// on the Serials tab; other tabs get "" and 0.
+func loadSerialsTab(projectID int, tab string) (string, int) {
+	if tab != "serials" {
+		return "", 0
+	}
+	mode, err := database.GetSerialRegistryMode(projectID)
+	if err != nil {
+		slog.Error("Error fetching serial registry mode", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+		return "", 0
+	}
+	months, err := database.GetProjectWarrantyMonths(projectID)
+	if err != nil {
+		slog.Error("Error fetching warranty override", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+	}
+	return mode, months
+}
+
+// updateSerialRegistryMode saves the serial registry mode and warranty
+// override from the Serials tab.
+func updateSerialRegistryMode(c echo.Context, projectID int) error {
+	redirect := fmt.Sprintf("/projects/%d/settings?tab=serials", projectID)
+
+	before, err := database.GetSerialRegistryMode(projectID)
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Failed to load serial settings")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	beforeMonths, err := database.GetProjectWarrantyMonths(projectID)
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Failed to load serial settings")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	mode := c.FormValue("serial_registry_mode")
+	if !models.IsValidSerialRegistryMode(mode) {
+		auth.SetFlash(c.Request(), "error", "Choose how the serial registry is checked")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	months := 0
+	if v := strings.TrimSpace(c.FormValue("warranty_months")); v != "" {
+		months, err = strconv.Atoi(v)
+		if err != nil || months < 0 || months > 600 {
+			auth.SetFlash(c.Request(), "error", "Warranty override must be a number of months between 0 and 600")
+			return c.Redirect(http.StatusFound, redirect)
+		}
+	}
+	if err := database.SetSerialRegistryMode(projectID, mode); err != nil {
+		slog.Error("Error updating serial registry mode", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to save settings")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	if err := database.SetProjectWarrantyMonths(projectID, months); err != nil {
+		slog.Error("Error updating warranty override", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to save settings")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	recordAudit(c, projectID, models.AuditEntityProjectSettings, projectID, models.AuditActionUpdate,
+		"Updated Serials settings",
+		map[string]interface{}{"serial_registry_mode": before, "warranty_months": beforeMonths},
+		map[string]interface{}{"serial_registry_mode": mode, "warranty_months": months})
+
+	auth.SetFlash(c.Request(), "success", "Settings saved successfully")
+	return c.Redirect(http.StatusFound, redirect)
+}
+
+// CloseFinancialYearHandler handles POST /projects/:id/settings/financial-years/:fy/close.
+// Admins only: once closed, no DC in the year can be created, edited, issued or cancelled.
+func CloseFinancialYearHandler(c echo.Context) error {
+	return setFinancialYearClosed(c, true)
+}
+
+// ReopenFinancialYearHandler handles POST /projects/:id/settings/financial-years/:fy/reopen.
+func ReopenFinancialYearHandler(c echo.Context) error {
+	return setFinancialYearClosed(c, false)
+}
+
+// setFinancialYearClosed closes or reopens the financial year in the URL and
+// records it in the audit log.
+func setFinancialYearClosed(c echo.Context, closed bool) error {
+	user := auth.GetCurrentUser(c)
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.Redirect(http.StatusFound, "/projects")
+	}
+	redirect := fmt.Sprintf("/projects/%d/settings?tab=financial_years", projectID)
+
+	fy := c.Param("fy")
+	if _, _, err := services.ParseFinancialYear(fy); err != nil {
+		auth.SetFlash(c.Request(), "error", "Invalid financial year")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	label := models.FinancialYearLabel(fy)
+
+	action, summary := models.AuditActionReopenFY, "Reopened financial year "+label
+	if closed {
+		action, summary = models.AuditActionCloseFY, "Closed financial year "+label
+		err = database.CloseFinancialYear(projectID, fy, user.ID)
+	} else {
+		err = database.ReopenFinancialYear(projectID, fy)
+	}
+	if err != nil {
+		slog.Error("Error updating financial year closure", slog.Int("project_id", projectID), slog.String("fy", fy), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to update financial year "+label)
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	recordAudit(c, projectID, models.AuditEntityProjectSettings, projectID, action, summary,
+		map[string]interface{}{"financial_year": fy, "closed": !closed},
+		map[string]interface{}{"financial_year": fy, "closed": closed})
+
+	auth.SetFlash(c.Request(), "success", summary)
+	return c.Redirect(http.StatusFound, redirect)
+}
+
 // PreviewDCNumberAPI returns a JSON preview of the DC number format.
 func PreviewDCNumberAPI(c echo.Context) error {
 	format := c.QueryParam("format")
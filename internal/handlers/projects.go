@@ -20,6 +20,7 @@ import (
 	"github.com/narendhupati/dc-management-tool/internal/auth"
 	"github.com/narendhupati/dc-management-tool/internal/components"
 	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/helpers"
 	"github.com/narendhupati/dc-management-tool/internal/models"
 )
 
@@ -156,6 +157,9 @@ func CreateProject(c echo.Context) error {
 		return components.RenderOK(c, layouts.MainWithContent("Create Project", sidebar, topbar, "", "", pageContent))
 	}
 
+	recordAudit(c, project.ID, models.AuditEntityProject, project.ID, models.AuditActionCreate,
+		"Created project "+project.Name, nil, project)
+
 	auth.SetFlash(c.Request(), "success", "Project created successfully")
 	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", project.ID))
 }
@@ -253,6 +257,11 @@ func UpdateProject(c echo.Context) error {
 		return components.RenderOK(c, layouts.MainWithContent("Edit Project", sidebar, topbar, "", "", pageContent))
 	}
 
+	if updated, err := database.GetProjectByID(id); err == nil {
+		recordAudit(c, id, models.AuditEntityProject, id, models.AuditActionUpdate,
+			"Updated project "+updated.Name, existing, updated)
+	}
+
 	auth.SetFlash(c.Request(), "success", "Project updated successfully")
 	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", project.ID))
 }
@@ -305,11 +314,19 @@ func DeleteProject(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Cannot delete project with issued delivery challans"})
 	}
 
+	existing, _ := database.GetProjectByID(id)
+
 	if err := database.DeleteProject(id); err != nil {
 		slog.Error("Error deleting project", slog.String("error", err.Error()), slog.Int("projectID", id))
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to delete project"})
 	}
 
+	// The project row is gone, so the event is recorded globally.
+	if existing != nil {
+		recordAudit(c, 0, models.AuditEntityProject, id, models.AuditActionDelete,
+			"Deleted project "+existing.Name, existing, nil)
+	}
+
 	auth.SetFlash(c.Request(), "success", "Project deleted successfully")
 	return c.JSON(http.StatusOK, map[string]interface{}{"success": true, "redirect": "/projects"})
 }
@@ -326,6 +343,11 @@ func validateProject(project *models.Project) map[string]string {
 	if project.SeqPadding != 0 && (project.SeqPadding < 2 || project.SeqPadding > 6) {
 		errors["seq_padding"] = "Sequence padding must be between 2 and 6"
 	}
+	if project.CompanyGSTIN != "" {
+		if err := helpers.ValidateGSTIN(project.CompanyGSTIN); err != nil {
+			errors["company_gstin"] = err.Error()
+		}
+	}
 	return errors
 }
 
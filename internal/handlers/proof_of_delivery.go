@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// RecordProofOfDeliveryHandler handles POST /projects/:id/dcs/:dcid/pod.
+// It stores the scanned signed POD and moves the official DC to delivered,
+// or to partially delivered when the POD covers only part of the goods.
+func RecordProofOfDeliveryHandler(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.Redirect(http.StatusFound, "/projects")
+	}
+
+	dcID, err := strconv.Atoi(c.Param("dcid"))
+	if err != nil {
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", projectID))
+	}
+
+	dc, err := database.GetDeliveryChallanByID(dcID)
+	if err != nil || dc.ProjectID != projectID {
+		auth.SetFlash(c.Request(), "error", "DC not found")
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", projectID))
+	}
+
+	detailURL := fmt.Sprintf("/projects/%d/dcs/%d", projectID, dcID)
+	if dc.DCType != "official" {
+		auth.SetFlash(c.Request(), "error", "Proof of delivery is recorded against Official DCs only")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+	if dc.Status != models.DCStatusIssued && dc.Status != models.DCStatusPartiallyDelivered {
+		auth.SetFlash(c.Request(), "error", "Only issued DCs awaiting delivery accept a proof of delivery")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+
+	pod := &models.ProofOfDelivery{
+		DCID:                dcID,
+		ReceivedDate:        strings.TrimSpace(c.FormValue("received_date")),
+		ReceiverName:        strings.TrimSpace(c.FormValue("receiver_name")),
+		ReceiverDesignation: strings.TrimSpace(c.FormValue("receiver_designation")),
+		IsPartial:           c.FormValue("is_partial") != "",
+		Remarks:             strings.TrimSpace(c.FormValue("remarks")),
+		RecordedBy:          &user.ID,
+	}
+
+	if pod.ReceiverName == "" {
+		auth.SetFlash(c.Request(), "error", "Receiver name is required")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+	received, err := time.Parse("2006-01-02", pod.ReceivedDate)
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "A valid received date is required")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+	if challan, err := time.Parse("2006-01-02", dcChallanDay(dc)); err == nil && received.Before(challan) {
+		auth.SetFlash(c.Request(), "error", "Received date cannot be before the challan date")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+	if received.After(time.Now()) {
+		auth.SetFlash(c.Request(), "error", "Received date cannot be in the future")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+
+	filePath, err := saveUploadedDocument(c, "pod_file", "pods", fmt.Sprintf("pod_%d", dcID))
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "POD upload failed: "+err.Error())
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+	if filePath == "" {
+		auth.SetFlash(c.Request(), "error", "Upload the scanned signed POD")
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+	pod.FilePath = filePath
+
+	if err := database.RecordProofOfDelivery(pod); err != nil {
+		slog.Error("Failed to record proof of delivery",
+			slog.Int("dc_id", dcID),
+			slog.Int("project_id", projectID),
+			slog.Int("user_id", user.ID),
+			slog.String("error", err.Error()),
+		)
+		auth.SetFlash(c.Request(), "error", "Failed to record proof of delivery: "+err.Error())
+		return c.Redirect(http.StatusFound, detailURL)
+	}
+
+	status := pod.DeliveryStatus()
+	recordAudit(c, projectID, models.AuditEntityDC, dcID, models.AuditActionDeliver,
+		fmt.Sprintf("Recorded proof of delivery for DC %s (received by %s)", dc.DCNumber, pod.ReceiverName),
+		map[string]interface{}{"status": dc.Status},
+		map[string]interface{}{
+			"status":               status,
+			"received_date":        pod.ReceivedDate,
+			"receiver_name":        pod.ReceiverName,
+			"receiver_designation": pod.ReceiverDesignation,
+		})
+
+	if status == models.DCStatusPartiallyDelivered {
+		auth.SetFlash(c.Request(), "success", "Partial delivery recorded for DC "+dc.DCNumber)
+	} else {
+		auth.SetFlash(c.Request(), "success", "DC "+dc.DCNumber+" marked as delivered")
+	}
+	return c.Redirect(http.StatusFound, detailURL)
+}
+
+// dcChallanDay returns the YYYY-MM-DD part of a DC's challan date, or "" when unset.
+func dcChallanDay(dc *models.DeliveryChallan) string {
+	return challanDay(dc.ChallanDate)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/csrf"
@@ -379,6 +380,174 @@ func ShowTransferDCReport(c echo.Context) error {
 	return components.RenderOK(c, layouts.MainWithContent("Reports", sidebar, topbar, f.flashMessage, f.flashType, pageContent))
 }
 
+// ShowDiscrepancyReport shows the DCs whose goods receipt differs from what was dispatched.
+func ShowDiscrepancyReport(c echo.Context) error {
+	f := getReportFields(c, "Receipt Discrepancy Report")
+
+	rows, err := database.GetDiscrepancyReport(f.currentProject.ID, f.startDate, f.endDate)
+	if err != nil {
+		slog.Error("error fetching discrepancy report", slog.String("error", err.Error()), slog.Int("projectID", f.currentProject.ID))
+		rows = nil
+	}
+
+	rowPtrs := make([]*database.DiscrepancyReportRow, len(rows))
+	for i := range rows {
+		rowPtrs[i] = &rows[i]
+	}
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return components.RenderOK(c, pagesreports.DiscrepancyContent(rowPtrs, f.currentProject.ID))
+	}
+
+	pageContent := pagesreports.Discrepancy(
+		f.user,
+		f.currentProject,
+		f.allProjects,
+		rowPtrs,
+		f.dateRange,
+		f.fromDate,
+		f.toDate,
+		f.flashType,
+		f.flashMessage,
+	)
+	sidebar := partials.Sidebar(f.user, f.currentProject, f.allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(f.user, f.currentProject, f.allProjects, f.flashType, f.flashMessage)
+	return components.RenderOK(c, layouts.MainWithContent("Reports", sidebar, topbar, f.flashMessage, f.flashType, pageContent))
+}
+
+// ShowHSNSummaryReport shows the taxable value and tax of issued DCs grouped by HSN code.
+func ShowHSNSummaryReport(c echo.Context) error {
+	f := getReportFields(c, "HSN Summary")
+
+	rows, err := database.GetHSNSummaryReport(f.currentProject.ID, f.startDate, f.endDate)
+	if err != nil {
+		slog.Error("error fetching HSN summary report", slog.String("error", err.Error()), slog.Int("projectID", f.currentProject.ID))
+		rows = nil
+	}
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return components.RenderOK(c, pagesreports.HSNSummaryContent(rows))
+	}
+
+	pageContent := pagesreports.HSNSummary(
+		f.user,
+		f.currentProject,
+		f.allProjects,
+		rows,
+		f.dateRange,
+		f.fromDate,
+		f.toDate,
+		f.flashType,
+		f.flashMessage,
+	)
+	sidebar := partials.Sidebar(f.user, f.currentProject, f.allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(f.user, f.currentProject, f.allProjects, f.flashType, f.flashMessage)
+	return components.RenderOK(c, layouts.MainWithContent("Reports", sidebar, topbar, f.flashMessage, f.flashType, pageContent))
+}
+
+// warrantyReportParams reads the warranty report's look-ahead window, 90 days
+// by default, and whether already expired serials are listed.
+func warrantyReportParams(c echo.Context) (days int, includeExpired bool) {
+	days, err := strconv.Atoi(c.QueryParam("days"))
+	if err != nil || days <= 0 {
+		days = 90
+	}
+	return days, c.QueryParam("expired") == "true"
+}
+
+// ShowWarrantyReport shows delivered serials whose warranty expires within
+// the chosen number of days, grouped by district and mandal.
+func ShowWarrantyReport(c echo.Context) error {
+	f := getReportFields(c, "Warranty Expiry")
+	days, includeExpired := warrantyReportParams(c)
+	today := time.Now()
+
+	serials, err := database.GetWarrantySerials(f.currentProject.ID)
+	if err != nil {
+		slog.Error("error fetching warranty report", slog.String("error", err.Error()), slog.Int("projectID", f.currentProject.ID))
+		serials = nil
+	}
+	groups := models.ExpiringWarranties(serials, today, days, includeExpired)
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return components.RenderOK(c, pagesreports.WarrantyContent(groups, today))
+	}
+
+	pageContent := pagesreports.Warranty(
+		f.user,
+		f.currentProject,
+		f.allProjects,
+		groups,
+		today,
+		days,
+		includeExpired,
+		f.flashType,
+		f.flashMessage,
+	)
+	sidebar := partials.Sidebar(f.user, f.currentProject, f.allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(f.user, f.currentProject, f.allProjects, f.flashType, f.flashMessage)
+	return components.RenderOK(c, layouts.MainWithContent("Reports", sidebar, topbar, f.flashMessage, f.flashType, pageContent))
+}
+
+// ShowBOQReport shows the BOQ plan's planned, dispatched and balance
+// quantities per district, mandal and product.
+func ShowBOQReport(c echo.Context) error {
+	f := getReportFields(c, "BOQ Progress")
+
+	rows, err := database.GetBOQProgress(f.currentProject.ID)
+	if err != nil {
+		slog.Error("error fetching BOQ progress", slog.String("error", err.Error()), slog.Int("projectID", f.currentProject.ID))
+		rows = nil
+	}
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return components.RenderOK(c, pagesreports.BOQContent(rows))
+	}
+
+	pageContent := pagesreports.BOQ(
+		f.user,
+		f.currentProject,
+		f.allProjects,
+		rows,
+		f.flashType,
+		f.flashMessage,
+	)
+	sidebar := partials.Sidebar(f.user, f.currentProject, f.allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(f.user, f.currentProject, f.allProjects, f.flashType, f.flashMessage)
+	return components.RenderOK(c, layouts.MainWithContent("Reports", sidebar, topbar, f.flashMessage, f.flashType, pageContent))
+}
+
+// ShowDocumentsIssuedReport renders the GSTR-1 documents-issued report.
+func ShowDocumentsIssuedReport(c echo.Context) error {
+	f := getReportFields(c, "Documents Issued")
+
+	series, unparsed, err := database.GetDocumentsIssuedReport(f.currentProject.ID, f.startDate, f.endDate)
+	if err != nil {
+		slog.Error("error fetching documents issued report", slog.String("error", err.Error()), slog.Int("projectID", f.currentProject.ID))
+		series, unparsed = nil, nil
+	}
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return components.RenderOK(c, pagesreports.DocumentsIssuedContent(series, unparsed))
+	}
+
+	pageContent := pagesreports.DocumentsIssued(
+		f.user,
+		f.currentProject,
+		f.allProjects,
+		series,
+		unparsed,
+		f.dateRange,
+		f.fromDate,
+		f.toDate,
+		f.flashType,
+		f.flashMessage,
+	)
+	sidebar := partials.Sidebar(f.user, f.currentProject, f.allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(f.user, f.currentProject, f.allProjects, f.flashType, f.flashMessage)
+	return components.RenderOK(c, layouts.MainWithContent("Reports", sidebar, topbar, f.flashMessage, f.flashType, pageContent))
+}
+
 // ExportDCSummaryExcel exports the DC summary report as Excel.
 func ExportDCSummaryExcel(c echo.Context) error {
 	project, _ := c.Get("currentProject").(*models.Project)
@@ -408,6 +577,7 @@ func ExportDCSummaryExcel(c echo.Context) error {
 		{"Transfer DCs (Issued)", report.TransferIssuedDCs},
 		{"Transfer DCs (Splitting)", report.TransferSplittingDCs},
 		{"Transfer DCs (Split)", report.TransferSplitDCs},
+		{"Cancelled DCs", report.CancelledDCs},
 		{"Total Items Dispatched", report.TotalItemsDispatched},
 		{"Total Serial Numbers Used", report.TotalSerialsUsed},
 	}
@@ -441,7 +611,7 @@ func ExportDestinationExcel(c echo.Context) error {
 	sheet := "Destination Report"
 	_ = f.SetSheetName("Sheet1", sheet)
 
-	headers := []string{"District", "Mandal", "Official DCs", "Total Items", "Draft", "Issued"}
+	headers := []string{"District", "Mandal", "Official DCs", "Total Items", "Draft", "Issued", "Delivered", "Partially Delivered", "Pending Delivery"}
 	for i, h := range headers {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
 		_ = f.SetCellValue(sheet, cell, h)
@@ -454,6 +624,9 @@ func ExportDestinationExcel(c echo.Context) error {
 		_ = f.SetCellValue(sheet, cellName(4, row), r.TotalItems)
 		_ = f.SetCellValue(sheet, cellName(5, row), r.DraftCount)
 		_ = f.SetCellValue(sheet, cellName(6, row), r.IssuedCount)
+		_ = f.SetCellValue(sheet, cellName(7, row), r.DeliveredCount)
+		_ = f.SetCellValue(sheet, cellName(8, row), r.PartiallyDeliveredCount)
+		_ = f.SetCellValue(sheet, cellName(9, row), r.PendingCount)
 	}
 
 	filename := fmt.Sprintf("destination-report-%s.xlsx", time.Now().Format("2006-01-02"))
@@ -512,7 +685,7 @@ func ExportSerialExcel(c echo.Context) error {
 	sheet := "Serial Numbers"
 	_ = f.SetSheetName("Sheet1", sheet)
 
-	headers := []string{"Serial Number", "Product", "DC Number", "Date", "Vehicle"}
+	headers := []string{"Serial Number", "Product", "Movement", "DC Number", "Date", "Vehicle"}
 	for i, h := range headers {
 		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
 		_ = f.SetCellValue(sheet, cell, h)
@@ -521,9 +694,14 @@ func ExportSerialExcel(c echo.Context) error {
 		row := i + 2
 		_ = f.SetCellValue(sheet, cellName(1, row), r.SerialNumber)
 		_ = f.SetCellValue(sheet, cellName(2, row), r.ProductName)
-		_ = f.SetCellValue(sheet, cellName(3, row), r.TransitDCNumber)
-		_ = f.SetCellValue(sheet, cellName(4, row), r.ChallanDate)
-		_ = f.SetCellValue(sheet, cellName(5, row), r.VehicleNumber)
+		movement := "Dispatched"
+		if r.Movement == database.SerialMovementReturn {
+			movement = "Returned"
+		}
+		_ = f.SetCellValue(sheet, cellName(3, row), movement)
+		_ = f.SetCellValue(sheet, cellName(4, row), r.TransitDCNumber)
+		_ = f.SetCellValue(sheet, cellName(5, row), r.ChallanDate)
+		_ = f.SetCellValue(sheet, cellName(6, row), r.VehicleNumber)
 	}
 
 	filename := fmt.Sprintf("serial-report-%s.xlsx", time.Now().Format("2006-01-02"))
@@ -533,6 +711,277 @@ func ExportSerialExcel(c echo.Context) error {
 	return nil
 }
 
+// ExportDiscrepancyExcel exports the receipt discrepancy report as Excel.
+func ExportDiscrepancyExcel(c echo.Context) error {
+	project, _ := c.Get("currentProject").(*models.Project)
+	_, startDate, endDate := parseDateRange(c)
+
+	rows, err := database.GetDiscrepancyReport(project.ID, startDate, endDate)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to generate report"})
+	}
+
+	f := excelize.NewFile()
+	sheet := "Receipt Discrepancies"
+	_ = f.SetSheetName("Sheet1", sheet)
+
+	headers := []string{"DC Number", "Challan Date", "District", "Mandal", "Received On", "Dispatched", "Received", "Short", "Damaged", "Missing Serials", "Damaged Serials"}
+	for i, h := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		_ = f.SetCellValue(sheet, cell, h)
+	}
+	for i, r := range rows {
+		row := i + 2
+		_ = f.SetCellValue(sheet, cellName(1, row), r.DCNumber)
+		_ = f.SetCellValue(sheet, cellName(2, row), r.ChallanDate)
+		_ = f.SetCellValue(sheet, cellName(3, row), r.District)
+		_ = f.SetCellValue(sheet, cellName(4, row), r.Mandal)
+		_ = f.SetCellValue(sheet, cellName(5, row), r.ReceivedDate)
+		_ = f.SetCellValue(sheet, cellName(6, row), r.Dispatched)
+		_ = f.SetCellValue(sheet, cellName(7, row), r.Received)
+		_ = f.SetCellValue(sheet, cellName(8, row), r.Short)
+		_ = f.SetCellValue(sheet, cellName(9, row), r.Damaged)
+		_ = f.SetCellValue(sheet, cellName(10, row), r.MissingSerials)
+		_ = f.SetCellValue(sheet, cellName(11, row), r.DamagedSerials)
+	}
+
+	filename := fmt.Sprintf("discrepancy-report-%s.xlsx", time.Now().Format("2006-01-02"))
+	c.Response().Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	_ = f.Write(c.Response().Writer)
+	return nil
+}
+
+// ExportHSNSummaryExcel exports the HSN summary report as Excel.
+func ExportHSNSummaryExcel(c echo.Context) error {
+	project, _ := c.Get("currentProject").(*models.Project)
+	_, startDate, endDate := parseDateRange(c)
+
+	rows, err := database.GetHSNSummaryReport(project.ID, startDate, endDate)
+	if err != nil {
+		slog.Error("error exporting HSN summary report", slog.String("error", err.Error()), slog.Int("projectID", project.ID))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to generate report"})
+	}
+
+	f := excelize.NewFile()
+	sheet := "HSN Summary"
+	_ = f.SetSheetName("Sheet1", sheet)
+
+	headers := []string{"HSN", "Description", "UoM", "Total Quantity", "GST Rate %", "Taxable Value", "CGST", "SGST", "IGST", "Total Tax"}
+	for i, h := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		_ = f.SetCellValue(sheet, cell, h)
+	}
+	writeRow := func(row int, r models.HSNSummaryRow) {
+		_ = f.SetCellValue(sheet, cellName(1, row), r.HSNCode)
+		_ = f.SetCellValue(sheet, cellName(2, row), r.Description)
+		_ = f.SetCellValue(sheet, cellName(3, row), r.UoM)
+		_ = f.SetCellValue(sheet, cellName(4, row), r.Quantity)
+		_ = f.SetCellValue(sheet, cellName(6, row), r.TaxableValue)
+		_ = f.SetCellValue(sheet, cellName(7, row), r.CGST)
+		_ = f.SetCellValue(sheet, cellName(8, row), r.SGST)
+		_ = f.SetCellValue(sheet, cellName(9, row), r.IGST)
+		_ = f.SetCellValue(sheet, cellName(10, row), r.TotalTax())
+	}
+	for i, r := range rows {
+		writeRow(i+2, r)
+		_ = f.SetCellValue(sheet, cellName(5, i+2), r.TaxRate)
+	}
+	if len(rows) > 0 {
+		totalRow := len(rows) + 2
+		writeRow(totalRow, models.HSNSummaryTotals(rows))
+		_ = f.SetCellValue(sheet, cellName(2, totalRow), "Total")
+	}
+
+	filename := fmt.Sprintf("hsn-summary-%s.xlsx", time.Now().Format("2006-01-02"))
+	c.Response().Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	_ = f.Write(c.Response().Writer)
+	return nil
+}
+
+// ExportWarrantyExcel exports the warranty expiry report as Excel, one row per
+// serial ordered by district, mandal and expiry.
+func ExportWarrantyExcel(c echo.Context) error {
+	project, _ := c.Get("currentProject").(*models.Project)
+	days, includeExpired := warrantyReportParams(c)
+	today := time.Now()
+
+	serials, err := database.GetWarrantySerials(project.ID)
+	if err != nil {
+		slog.Error("error exporting warranty report", slog.String("error", err.Error()), slog.Int("projectID", project.ID))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to generate report"})
+	}
+
+	f := excelize.NewFile()
+	sheet := "Warranty Expiry"
+	_ = f.SetSheetName("Sheet1", sheet)
+
+	headers := []string{"District", "Mandal", "Serial Number", "Product", "DC Number", "Ship To",
+		"Warranty Start", "Start From", "Warranty Months", "Expiry Date", "Days Left"}
+	for i, h := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		_ = f.SetCellValue(sheet, cell, h)
+	}
+	row := 2
+	for _, g := range models.ExpiringWarranties(serials, today, days, includeExpired) {
+		for _, s := range g.Serials {
+			from := "DC date"
+			if s.FromPOD {
+				from = "POD"
+			}
+			_ = f.SetCellValue(sheet, cellName(1, row), s.District)
+			_ = f.SetCellValue(sheet, cellName(2, row), s.Mandal)
+			_ = f.SetCellValue(sheet, cellName(3, row), s.SerialNumber)
+			_ = f.SetCellValue(sheet, cellName(4, row), s.ItemName)
+			_ = f.SetCellValue(sheet, cellName(5, row), s.DCNumber)
+			_ = f.SetCellValue(sheet, cellName(6, row), s.ShipTo)
+			_ = f.SetCellValue(sheet, cellName(7, row), s.StartDate.Format("02-01-2006"))
+			_ = f.SetCellValue(sheet, cellName(8, row), from)
+			_ = f.SetCellValue(sheet, cellName(9, row), s.Months)
+			_ = f.SetCellValue(sheet, cellName(10, row), s.Expiry().Format("02-01-2006"))
+			_ = f.SetCellValue(sheet, cellName(11, row), s.DaysLeft(today))
+			row++
+		}
+	}
+
+	filename := fmt.Sprintf("warranty-expiry-%s.xlsx", today.Format("2006-01-02"))
+	c.Response().Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	_ = f.Write(c.Response().Writer)
+	return nil
+}
+
+// ExportBOQExcel exports the BOQ progress as Excel: one row per ship-to
+// address and product on the first sheet, and the district and product
+// totals on the next two.
+func ExportBOQExcel(c echo.Context) error {
+	project, _ := c.Get("currentProject").(*models.Project)
+
+	rows, err := database.GetBOQProgress(project.ID)
+	if err != nil {
+		slog.Error("error exporting BOQ progress", slog.String("error", err.Error()), slog.Int("projectID", project.ID))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to generate report"})
+	}
+
+	f := excelize.NewFile()
+	sheet := "BOQ Progress"
+	_ = f.SetSheetName("Sheet1", sheet)
+
+	headers := []string{"District", "Mandal", "Ship To", "Product", "Planned", "Dispatched", "On Draft DCs", "Balance", "Planned In BOQ"}
+	for i, h := range headers {
+		_ = f.SetCellValue(sheet, cellName(i+1, 1), h)
+	}
+	row := 2
+	for _, g := range models.GroupBOQByMandal(rows) {
+		for _, r := range g.Rows {
+			planned := "Yes"
+			if !r.HasPlan {
+				planned = "No"
+			}
+			_ = f.SetCellValue(sheet, cellName(1, row), r.District)
+			_ = f.SetCellValue(sheet, cellName(2, row), r.Mandal)
+			_ = f.SetCellValue(sheet, cellName(3, row), r.ShipTo)
+			_ = f.SetCellValue(sheet, cellName(4, row), r.ItemName)
+			_ = f.SetCellValue(sheet, cellName(5, row), r.Planned)
+			_ = f.SetCellValue(sheet, cellName(6, row), r.Dispatched)
+			_ = f.SetCellValue(sheet, cellName(7, row), r.Pending)
+			_ = f.SetCellValue(sheet, cellName(8, row), r.Balance())
+			_ = f.SetCellValue(sheet, cellName(9, row), planned)
+			row++
+		}
+	}
+
+	writeTotals := func(name, label string, totals []models.BOQTotal) {
+		_, _ = f.NewSheet(name)
+		for i, h := range []string{label, "Planned", "Dispatched", "Balance", "Progress %"} {
+			_ = f.SetCellValue(name, cellName(i+1, 1), h)
+		}
+		for i, t := range append(totals, models.BOQGrandTotal(rows)) {
+			_ = f.SetCellValue(name, cellName(1, i+2), t.Label)
+			_ = f.SetCellValue(name, cellName(2, i+2), t.Planned)
+			_ = f.SetCellValue(name, cellName(3, i+2), t.Dispatched)
+			_ = f.SetCellValue(name, cellName(4, i+2), t.Balance())
+			_ = f.SetCellValue(name, cellName(5, i+2), t.Percent())
+		}
+	}
+	writeTotals("By District", "District", models.BOQByDistrict(rows))
+	writeTotals("By Product", "Product", models.BOQByProduct(rows))
+
+	filename := fmt.Sprintf("boq-progress-%s.xlsx", time.Now().Format("2006-01-02"))
+	c.Response().Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	_ = f.Write(c.Response().Writer)
+	return nil
+}
+
+// ExportDocumentsIssuedExcel exports the documents-issued report as Excel. The
+// first sheet follows the "docs" sheet of the GSTR-1 offline tool, one row per
+// series; the second sheet breaks the cancelled count down.
+func ExportDocumentsIssuedExcel(c echo.Context) error {
+	project, _ := c.Get("currentProject").(*models.Project)
+	_, startDate, endDate := parseDateRange(c)
+
+	series, _, err := database.GetDocumentsIssuedReport(project.ID, startDate, endDate)
+	if err != nil {
+		slog.Error("error exporting documents issued report", slog.String("error", err.Error()), slog.Int("projectID", project.ID))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to generate report"})
+	}
+
+	f := excelize.NewFile()
+	sheet := "docs"
+	_ = f.SetSheetName("Sheet1", sheet)
+
+	totalNumber, totalCancelled := 0, 0
+	for _, s := range series {
+		totalNumber += s.TotalNumber
+		totalCancelled += s.GSTR1Cancelled()
+	}
+	_ = f.SetCellValue(sheet, "A1", "Summary of documents issued during the tax period (13)")
+	_ = f.SetCellValue(sheet, "D2", "Total Number")
+	_ = f.SetCellValue(sheet, "E2", "Total Cancelled")
+	_ = f.SetCellValue(sheet, "D3", totalNumber)
+	_ = f.SetCellValue(sheet, "E3", totalCancelled)
+	headers := []string{"Nature of Document", "Sr. No. From", "Sr. No. To", "Total Number", "Cancelled"}
+	for i, h := range headers {
+		_ = f.SetCellValue(sheet, cellName(i+1, 4), h)
+	}
+	for i, s := range series {
+		row := i + 5
+		_ = f.SetCellValue(sheet, cellName(1, row), services.GSTR1NatureDeliveryChallan)
+		_ = f.SetCellValue(sheet, cellName(2, row), s.FromNumber)
+		_ = f.SetCellValue(sheet, cellName(3, row), s.ToNumber)
+		_ = f.SetCellValue(sheet, cellName(4, row), s.TotalNumber)
+		_ = f.SetCellValue(sheet, cellName(5, row), s.GSTR1Cancelled())
+	}
+
+	detail := "Series Detail"
+	_, _ = f.NewSheet(detail)
+	headers = []string{"DC Type", "Financial Year", "Series", "From", "To", "Total Number", "Issued", "Cancelled", "Not Issued", "Deleted"}
+	for i, h := range headers {
+		_ = f.SetCellValue(detail, cellName(i+1, 1), h)
+	}
+	for i, s := range series {
+		row := i + 2
+		_ = f.SetCellValue(detail, cellName(1, row), s.TypeLabel())
+		_ = f.SetCellValue(detail, cellName(2, row), s.FinancialYearLabel())
+		_ = f.SetCellValue(detail, cellName(3, row), s.Series)
+		_ = f.SetCellValue(detail, cellName(4, row), s.FromNumber)
+		_ = f.SetCellValue(detail, cellName(5, row), s.ToNumber)
+		_ = f.SetCellValue(detail, cellName(6, row), s.TotalNumber)
+		_ = f.SetCellValue(detail, cellName(7, row), s.Issued)
+		_ = f.SetCellValue(detail, cellName(8, row), s.Cancelled)
+		_ = f.SetCellValue(detail, cellName(9, row), s.NotIssued)
+		_ = f.SetCellValue(detail, cellName(10, row), s.Deleted)
+	}
+
+	filename := fmt.Sprintf("documents-issued-%s.xlsx", time.Now().Format("2006-01-02"))
+	c.Response().Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	_ = f.Write(c.Response().Writer)
+	return nil
+}
+
 // ExportTransferDCReportExcel exports the Transfer DC report as Excel.
 func ExportTransferDCReportExcel(c echo.Context) error {
 	project, _ := c.Get("currentProject").(*models.Project)
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	deliverychallan "github.com/narendhupati/dc-management-tool/components/pages/delivery_challans"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+// returnableDC loads the project and DC for the return routes and checks that the
+// DC is an issued Transit DC, which is where dispatched serials are recorded.
+// On failure it sets a flash message and returns a redirect URL.
+func returnableDC(c echo.Context) (*models.Project, *models.DeliveryChallan, string) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, nil, "/projects"
+	}
+
+	dcID, err := strconv.Atoi(c.Param("dcid"))
+	if err != nil {
+		return nil, nil, fmt.Sprintf("/projects/%d", projectID)
+	}
+
+	project, err := database.GetProjectByID(projectID)
+	if err != nil {
+		return nil, nil, "/projects"
+	}
+
+	dc, err := database.GetDeliveryChallanByID(dcID)
+	if err != nil || dc.ProjectID != projectID {
+		auth.SetFlash(c.Request(), "error", "DC not found")
+		return nil, nil, fmt.Sprintf("/projects/%d", projectID)
+	}
+
+	detailURL := fmt.Sprintf("/projects/%d/dcs/%d", projectID, dcID)
+	if dc.DCType != "transit" {
+		auth.SetFlash(c.Request(), "error", "Returns are recorded against Transit DCs only")
+		return nil, nil, detailURL
+	}
+	if dc.Status != models.DCStatusIssued {
+		auth.SetFlash(c.Request(), "error", "Only issued DCs can have material returned against them")
+		return nil, nil, detailURL
+	}
+	return project, dc, ""
+}
+
+// renderReturnDCForm renders the return form with the DC's returnable serials.
+func renderReturnDCForm(c echo.Context, project *models.Project, dc *models.DeliveryChallan, returnDate, reason, remarks string, selected map[int]bool, errors map[string]string) error {
+	user := auth.GetCurrentUser(c)
+	allProjects, _ := database.GetAccessibleProjects(user)
+
+	serials, err := database.GetReturnableSerials(dc.ID)
+	if err != nil {
+		slog.Error("Error fetching returnable serials", slog.Int("dc_id", dc.ID), slog.String("error", err.Error()))
+		errors["general"] = "Failed to load serial numbers"
+	}
+
+	pageContent := deliverychallan.ReturnDCForm(
+		user,
+		project,
+		allProjects,
+		dc,
+		serials,
+		returnDate,
+		reason,
+		remarks,
+		selected,
+		errors,
+		csrf.Token(c.Request()),
+	)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, "", "")
+	return components.RenderOK(c, layouts.MainWithContent("Return DC", sidebar, topbar, "", "", pageContent))
+}
+
+// ShowCreateReturnDCForm handles GET /projects/:id/dcs/:dcid/return.
+func ShowCreateReturnDCForm(c echo.Context) error {
+	project, dc, redirect := returnableDC(c)
+	if redirect != "" {
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	return renderReturnDCForm(c, project, dc, time.Now().Format("2006-01-02"), models.ReturnReasonFaulty, "", map[int]bool{}, map[string]string{})
+}
+
+// CreateReturnDCHandler handles POST /projects/:id/dcs/:dcid/return.
+// It raises a return DC for the selected serials and releases them for re-dispatch.
+func CreateReturnDCHandler(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+
+	project, dc, redirect := returnableDC(c)
+	if redirect != "" {
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	returnDate := strings.TrimSpace(c.FormValue("return_date"))
+	reason := c.FormValue("reason")
+	remarks := strings.TrimSpace(c.FormValue("remarks"))
+
+	form, _ := c.FormParams()
+	var serialIDs []int
+	selected := make(map[int]bool)
+	for _, raw := range form["serial_ids"] {
+		id, err := strconv.Atoi(raw)
+		if err != nil || selected[id] {
+			continue
+		}
+		selected[id] = true
+		serialIDs = append(serialIDs, id)
+	}
+
+	errors := map[string]string{}
+	returned, err := time.Parse("2006-01-02", returnDate)
+	switch {
+	case err != nil:
+		errors["return_date"] = "A valid return date is required"
+	case returned.After(time.Now()):
+		errors["return_date"] = "Return date cannot be in the future"
+	default:
+		if challan, err := time.Parse("2006-01-02", dcChallanDay(dc)); err == nil && returned.Before(challan) {
+			errors["return_date"] = "Return date cannot be before the challan date"
+		}
+	}
+	if !models.IsValidReturnReason(reason) {
+		errors["reason"] = "Select a return reason"
+	}
+	if len(serialIDs) == 0 {
+		errors["serials"] = "Select at least one serial number to return"
+	}
+	var dateGuard challanDateGuard
+	if errors["return_date"] == "" {
+		dateGuard = checkChallanDate(c, project.ID, returnDate, "", 0, services.DCTypeReturn)
+		if dateGuard.Refused != "" {
+			errors["return_date"] = dateGuard.Refused
+		}
+	}
+	if len(errors) > 0 {
+		return renderReturnDCForm(c, project, dc, returnDate, reason, remarks, selected, errors)
+	}
+
+	returnDCID, err := database.CreateReturnDC(dc, returnDate, reason, remarks, serialIDs, user.ID)
+	if err != nil {
+		slog.Error("Failed to create return DC",
+			slog.Int("dc_id", dc.ID),
+			slog.Int("project_id", project.ID),
+			slog.Int("user_id", user.ID),
+			slog.String("error", err.Error()),
+		)
+		errors["general"] = "Failed to create return DC: " + err.Error()
+		return renderReturnDCForm(c, project, dc, returnDate, reason, remarks, selected, errors)
+	}
+
+	returnDC, err := database.GetDeliveryChallanByID(returnDCID)
+	if err == nil {
+		var after interface{} = returnDC
+		if ret, err := database.GetReturnDCByDCID(returnDCID); err == nil && ret != nil {
+			after = map[string]interface{}{"dc": returnDC, "return": ret}
+		}
+		recordAudit(c, project.ID, models.AuditEntityDC, returnDCID, models.AuditActionCreate,
+			fmt.Sprintf("Created return DC %s against DC %s (%d serial(s))", returnDC.DCNumber, dc.DCNumber, len(serialIDs)),
+			nil, after)
+		dateGuard.audit(c, project.ID, models.AuditEntityDC, returnDCID)
+		auth.SetFlash(c.Request(), "success", fmt.Sprintf("Return DC %s created; %d serial(s) released", returnDC.DCNumber, len(serialIDs)))
+	}
+	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/dcs/%d", project.ID, returnDCID))
+}
+
+// ShowReturnDCDetail shows a return DC's details.
+func ShowReturnDCDetail(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.Redirect(http.StatusFound, "/projects")
+	}
+
+	dcID, err := strconv.Atoi(c.Param("dcid"))
+	if err != nil {
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", projectID))
+	}
+
+	project, err := database.GetProjectByID(projectID)
+	if err != nil {
+		return c.Redirect(http.StatusFound, "/projects")
+	}
+
+	dc, err := database.GetDeliveryChallanByID(dcID)
+	if err != nil || dc.ProjectID != projectID {
+		auth.SetFlash(c.Request(), "error", "DC not found")
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", projectID))
+	}
+
+	ret, err := database.GetReturnDCByDCID(dcID)
+	if err != nil {
+		slog.Error("Error fetching return DC", slog.Int("dc_id", dcID), slog.String("error", err.Error()))
+	}
+
+	var returnedFrom, returnedTo *models.Address
+	if snap, err := database.GetDCPrintSnapshot(dc); err == nil {
+		returnedFrom = snap.Address(models.SnapshotRoleShipTo)
+		returnedTo = snap.Address(models.SnapshotRoleDispatchFrom)
+	}
+
+	flashType, flashMessage := auth.PopFlash(c.Request())
+	allProjects, _ := database.GetAccessibleProjects(user)
+
+	pageContent := deliverychallan.ReturnDCDetail(
+		user,
+		project,
+		allProjects,
+		dc,
+		ret,
+		returnedFrom,
+		returnedTo,
+		flashType,
+		flashMessage,
+	)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
+	return components.RenderOK(c, layouts.MainWithContent("Return DC", sidebar, topbar, flashMessage, flashType, pageContent))
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	serialspage "github.com/narendhupati/dc-management-tool/components/pages/serials"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// capturedSerialsShown is how many of the latest scans the capture panel lists.
+const capturedSerialsShown = 20
+
+// ShowShipmentSerialCapture handles GET /projects/:id/shipments/:gid/scan.
+func ShowShipmentSerialCapture(c echo.Context) error {
+	return showSerialCapture(c, models.SerialCaptureShipment, c.Param("gid"))
+}
+
+// ShowTransferSerialCapture handles GET /projects/:id/transfer-dcs/:tdcid/scan.
+func ShowTransferSerialCapture(c echo.Context) error {
+	return showSerialCapture(c, models.SerialCaptureTransfer, c.Param("tdcid"))
+}
+
+// ScanShipmentSerial handles POST /projects/:id/shipments/:gid/scan.
+func ScanShipmentSerial(c echo.Context) error {
+	return scanSerial(c, models.SerialCaptureShipment, c.Param("gid"))
+}
+
+// ScanTransferSerial handles POST /projects/:id/transfer-dcs/:tdcid/scan.
+func ScanTransferSerial(c echo.Context) error {
+	return scanSerial(c, models.SerialCaptureTransfer, c.Param("tdcid"))
+}
+
+// RemoveShipmentScannedSerial handles POST /projects/:id/shipments/:gid/scan/:sid/delete.
+func RemoveShipmentScannedSerial(c echo.Context) error {
+	return removeScannedSerial(c, models.SerialCaptureShipment, c.Param("gid"))
+}
+
+// RemoveTransferScannedSerial handles POST /projects/:id/transfer-dcs/:tdcid/scan/:sid/delete.
+func RemoveTransferScannedSerial(c echo.Context) error {
+	return removeScannedSerial(c, models.SerialCaptureTransfer, c.Param("tdcid"))
+}
+
+// loadCaptureSheet loads the draft in the URL for the scan screen. It fails
+// with 404 when the draft is missing, in another project or no longer a draft.
+func loadCaptureSheet(c echo.Context, kind, idParam string) (*models.SerialCaptureSheet, error) {
+	project := c.Get("currentProject").(*models.Project)
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+	}
+	sheet, err := database.GetSerialCaptureSheet(kind, id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Error loading serial capture sheet", slog.String("kind", kind), slog.Int("id", id), slog.String("error", err.Error()))
+		}
+		return nil, echo.NewHTTPError(http.StatusNotFound, "draft not found")
+	}
+	if sheet.ProjectID != project.ID || sheet.Status != models.DCStatusDraft {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "draft not found")
+	}
+	return sheet, nil
+}
+
+// captureProps builds the scan screen's data for the product and destination
+// picked, defaulting to the first of each.
+func captureProps(c echo.Context, sheet *models.SerialCaptureSheet, productID, addressID int) serialspage.CaptureProps {
+	if sheet.Product(productID) == nil && len(sheet.Products) > 0 {
+		productID = sheet.Products[0].ProductID
+	}
+	if sheet.Destination(addressID) == nil && len(sheet.Destinations) > 0 {
+		addressID = sheet.Destinations[0].AddressID
+	}
+	p := serialspage.CaptureProps{
+		Sheet:     sheet,
+		ProductID: productID,
+		AddressID: addressID,
+		CSRFToken: csrf.Token(c.Request()),
+	}
+	if line := sheet.Product(productID); line != nil && addressID > 0 {
+		recent, err := database.ListCapturedSerials(line.LineItemID, productID, addressID)
+		if err != nil {
+			slog.Error("Error listing scanned serials", slog.Int("line_item_id", line.LineItemID), slog.String("error", err.Error()))
+		}
+		p.RecentTotal = len(recent)
+		if len(recent) > capturedSerialsShown {
+			recent = recent[:capturedSerialsShown]
+		}
+		p.Recent = recent
+	}
+	return p
+}
+
+// showSerialCapture renders the scan screen, or only its panel when htmx asks
+// for it after the product or destination is changed.
+func showSerialCapture(c echo.Context, kind, idParam string) error {
+	user := auth.GetCurrentUser(c)
+	project := c.Get("currentProject").(*models.Project)
+
+	sheet, err := loadCaptureSheet(c, kind, idParam)
+	if err != nil {
+		return err
+	}
+	productID, _ := strconv.Atoi(c.QueryParam("product_id"))
+	addressID, _ := strconv.Atoi(c.QueryParam("address_id"))
+	props := captureProps(c, sheet, productID, addressID)
+
+	if c.Request().Header.Get("HX-Request") == "true" {
+		return components.RenderOK(c, serialspage.CapturePanel(project, props))
+	}
+
+	flashType, flashMessage := auth.PopFlash(c.Request())
+	allProjects, _ := database.GetAccessibleProjects(user)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
+	pageContent := serialspage.Capture(project, props)
+	return components.RenderOK(c, layouts.MainWithContent("Scan Serials", sidebar, topbar, flashMessage, flashType, pageContent))
+}
+
+// scanSerial checks one scanned serial and saves it on the draft's line item
+// for the chosen destination. It always answers with the capture panel, whose
+// result tells the page which sound to play.
+func scanSerial(c echo.Context, kind, idParam string) error {
+	project := c.Get("currentProject").(*models.Project)
+
+	sheet, err := loadCaptureSheet(c, kind, idParam)
+	if err != nil {
+		return err
+	}
+	productID, _ := strconv.Atoi(c.FormValue("product_id"))
+	addressID, _ := strconv.Atoi(c.FormValue("address_id"))
+	serial := strings.TrimSpace(c.FormValue("serial"))
+
+	var result serialspage.ScanResult
+	if msg := captureClosedYear(sheet); msg != "" {
+		result = serialspage.ScanResult{Status: serialspage.ScanRejected, Serial: serial, Message: msg}
+	} else {
+		result = checkScannedSerial(sheet, productID, addressID, serial)
+	}
+	if result.Status != serialspage.ScanRejected {
+		line := sheet.Product(productID)
+		switch err := database.AddCapturedSerial(sheet, productID, addressID, serial); {
+		case errors.Is(err, database.ErrSerialAlreadyCaptured):
+			result = serialspage.ScanResult{Status: serialspage.ScanRejected, Serial: serial, Message: "Already scanned"}
+		case errors.Is(err, database.ErrCaptureClosed):
+			result = serialspage.ScanResult{Status: serialspage.ScanRejected, Serial: serial, Message: captureClosedMessage}
+		case errors.Is(err, database.ErrCaptureQuantityFull):
+			result = serialspage.ScanResult{Status: serialspage.ScanRejected, Serial: serial, Message: "All serials for this destination are already scanned"}
+		case err != nil:
+			slog.Error("Error saving scanned serial", slog.Int("line_item_id", line.LineItemID), slog.String("error", err.Error()))
+			result = serialspage.ScanResult{Status: serialspage.ScanRejected, Serial: serial, Message: "Could not save the serial, please scan it again"}
+		default:
+			if sheet, err = database.GetSerialCaptureSheet(kind, sheet.EntityID); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+		}
+	}
+
+	props := captureProps(c, sheet, productID, addressID)
+	props.Result = &result
+	return components.RenderOK(c, serialspage.CapturePanel(project, props))
+}
+
+// checkScannedSerial runs a scan through the same checks the wizards apply on
+// save: quantities, the product's serial format, duplicates in the project and
+// the serial registry. A serial the registry only warns about is accepted with
+// its warning.
+func checkScannedSerial(sheet *models.SerialCaptureSheet, productID, addressID int, serial string) serialspage.ScanResult {
+	reject := func(msg string) serialspage.ScanResult {
+		return serialspage.ScanResult{Status: serialspage.ScanRejected, Serial: serial, Message: msg}
+	}
+	if serial == "" {
+		return reject("Scan a serial number")
+	}
+	line, dest := sheet.Product(productID), sheet.Destination(addressID)
+	if line == nil || dest == nil {
+		return reject("Choose a product and a destination on this DC")
+	}
+	if sheet.Remaining(productID, addressID) == 0 {
+		switch {
+		case dest.Required[productID] == 0:
+			return reject(fmt.Sprintf("%s takes no %s", dest.Name, line.ItemName))
+		case line.Captured >= line.Required:
+			return reject(fmt.Sprintf("All %d serials of %s are already entered", line.Required, line.ItemName))
+		default:
+			return reject(fmt.Sprintf("All %d %s for %s are already scanned", dest.Required[productID], line.ItemName, dest.Name))
+		}
+	}
+	if msg := models.SerialRuleError(line.ItemName, line.Rule, []string{serial}); msg != "" {
+		return reject(msg)
+	}
+
+	conflicts, err := database.CheckSerialsInProjectByProduct(sheet.ProjectID, productID, []string{serial}, nil)
+	if err != nil {
+		slog.Error("Error checking serial duplicates", slog.Int("project_id", sheet.ProjectID), slog.String("error", err.Error()))
+		return reject("Could not check the serial, please scan it again")
+	}
+	if len(conflicts) > 0 {
+		if conflicts[0].ExistingDCID == sheet.DCID {
+			return reject("Already scanned on this DC")
+		}
+		return reject(fmt.Sprintf("Already on DC %s (%s)", conflicts[0].DCNumber, conflicts[0].DCStatus))
+	}
+
+	guard := newSerialRegistryGuard(sheet.ProjectID)
+	if msg := guard.check(productID, line.ItemName, []string{serial}); msg != "" {
+		return reject(msg)
+	}
+	if len(guard.warnings) > 0 {
+		return serialspage.ScanResult{Status: serialspage.ScanWarned, Serial: serial, Message: guard.warnings[0]}
+	}
+	return serialspage.ScanResult{Status: serialspage.ScanAccepted, Serial: serial, Message: "Saved"}
+}
+
+// removeScannedSerial takes a mis-scanned serial off the draft and answers
+// with the refreshed capture panel.
+func removeScannedSerial(c echo.Context, kind, idParam string) error {
+	project := c.Get("currentProject").(*models.Project)
+
+	sheet, err := loadCaptureSheet(c, kind, idParam)
+	if err != nil {
+		return err
+	}
+	productID, _ := strconv.Atoi(c.FormValue("product_id"))
+	addressID, _ := strconv.Atoi(c.FormValue("address_id"))
+	serialID, _ := strconv.Atoi(c.Param("sid"))
+
+	var result serialspage.ScanResult
+	if msg := captureClosedYear(sheet); msg != "" {
+		result = serialspage.ScanResult{Status: serialspage.ScanRejected, Message: msg}
+	} else if line := sheet.Product(productID); line == nil {
+		result = serialspage.ScanResult{Status: serialspage.ScanRejected, Message: "Serial not found"}
+	} else {
+		switch err := database.RemoveCapturedSerial(sheet, line.LineItemID, serialID); {
+		case errors.Is(err, sql.ErrNoRows):
+			result = serialspage.ScanResult{Status: serialspage.ScanRejected, Message: "Serial not found"}
+		case errors.Is(err, database.ErrCaptureClosed):
+			result = serialspage.ScanResult{Status: serialspage.ScanRejected, Message: captureClosedMessage}
+		case err != nil:
+			slog.Error("Error removing scanned serial", slog.Int("serial_id", serialID), slog.String("error", err.Error()))
+			result = serialspage.ScanResult{Status: serialspage.ScanRejected, Message: "Could not remove the serial"}
+		default:
+			result = serialspage.ScanResult{Status: serialspage.ScanRemoved, Message: "Removed"}
+			if sheet, err = database.GetSerialCaptureSheet(kind, sheet.EntityID); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+		}
+	}
+
+	props := captureProps(c, sheet, productID, addressID)
+	props.Result = &result
+	return components.RenderOK(c, serialspage.CapturePanel(project, props))
+}
+
+// captureClosedMessage answers a scan on a DC that was issued or sent for
+// approval after the scan screen was opened.
+const captureClosedMessage = "This DC is no longer a draft; its serials can no longer change"
+
+// captureClosedYear returns why the sheet's DC cannot take scans because its
+// challan date is in a closed financial year, or "". Scans are edits of the
+// draft, so they honour the same lock as the edit wizards.
+func captureClosedYear(sheet *models.SerialCaptureSheet) string {
+	dc, err := database.GetDeliveryChallanByID(sheet.DCID)
+	if err != nil {
+		slog.Error("Error loading DC for serial capture", slog.Int("dc_id", sheet.DCID), slog.String("error", err.Error()))
+		return "Could not check the challan date, please try again"
+	}
+	return closedYearMessage(dc)
+}
@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	serialspage "github.com/narendhupati/dc-management-tool/components/pages/serials"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+const (
+	// registryPageSize is how many serials the registry page lists at a time.
+	registryPageSize = 50
+	// packingListMaxSize is the largest packing list accepted for stock-in.
+	packingListMaxSize = 10 * 1024 * 1024
+	// registryListLimit is how many serials a flash message names before
+	// summarising the rest as a count.
+	registryListLimit = 5
+)
+
+// ShowSerialRegistry handles GET /projects/:id/serials.
+// It lists the project's registered serials with their state, filtered by
+// product, state and a serial search, and offers the stock-in form.
+func ShowSerialRegistry(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+	project := c.Get("currentProject").(*models.Project)
+
+	filters := database.RegistryFilters{
+		State:    c.QueryParam("state"),
+		Search:   strings.TrimSpace(c.QueryParam("q")),
+		PageSize: registryPageSize,
+	}
+	filters.ProductID, _ = strconv.Atoi(c.QueryParam("product_id"))
+	filters.Page, _ = strconv.Atoi(c.QueryParam("page"))
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+
+	products, err := database.GetProductsByProjectID(project.ID)
+	if err != nil {
+		slog.Error("Error fetching products", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+	}
+	serials, total, err := database.ListRegistrySerials(project.ID, filters)
+	if err != nil {
+		slog.Error("Error listing serial registry", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+	}
+	counts, err := database.RegistryStateCounts(project.ID, filters.ProductID)
+	if err != nil {
+		slog.Error("Error counting serial registry", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+	}
+	mode, err := database.GetSerialRegistryMode(project.ID)
+	if err != nil {
+		slog.Error("Error fetching serial registry mode", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+	}
+
+	totalPages := (total + registryPageSize - 1) / registryPageSize
+	props := serialspage.RegistryProps{
+		Products:    products,
+		Serials:     serials,
+		Counts:      counts,
+		Mode:        mode,
+		ProductID:   filters.ProductID,
+		State:       filters.State,
+		Search:      filters.Search,
+		Page:        filters.Page,
+		TotalPages:  totalPages,
+		TotalCount:  total,
+		CanEdit:     user.IsAdmin(),
+		CSRFToken:   csrf.Token(c.Request()),
+		ReturnQuery: c.QueryString(),
+	}
+
+	flashType, flashMessage := auth.PopFlash(c.Request())
+	allProjects, _ := database.GetAccessibleProjects(user)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
+	pageContent := serialspage.Registry(project, props)
+	return components.RenderOK(c, layouts.MainWithContent("Serial Registry", sidebar, topbar, flashMessage, flashType, pageContent))
+}
+
+// StockInSerialsHandler handles POST /projects/:id/serials/stock-in.
+// It adds serials to the registry from an uploaded OEM packing list or a pasted
+// list. Packing list rows naming a product are stocked against it; other rows
+// and pasted serials go to the product chosen on the form.
+func StockInSerialsHandler(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+	project := c.Get("currentProject").(*models.Project)
+	redirect := fmt.Sprintf("/projects/%d/serials", project.ID)
+
+	products, err := database.GetProductsByProjectID(project.ID)
+	if err != nil {
+		slog.Error("Error fetching products", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to load products")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	var selected *models.Product
+	if pid, _ := strconv.Atoi(c.FormValue("product_id")); pid > 0 {
+		for _, p := range products {
+			if p.ID == pid {
+				selected = p
+			}
+		}
+	}
+	source := strings.TrimSpace(c.FormValue("source"))
+
+	var rows []services.PackingListRow
+	if fh, err := c.FormFile("file"); err == nil {
+		if fh.Size > packingListMaxSize {
+			auth.SetFlash(c.Request(), "error", "File size must be less than 10MB")
+			return c.Redirect(http.StatusFound, redirect)
+		}
+		file, err := fh.Open()
+		if err != nil {
+			auth.SetFlash(c.Request(), "error", "Failed to read the packing list")
+			return c.Redirect(http.StatusFound, redirect)
+		}
+		rows, err = services.ParsePackingList(file, fh.Filename)
+		file.Close()
+		if err != nil {
+			auth.SetFlash(c.Request(), "error", err.Error())
+			return c.Redirect(http.StatusFound, redirect)
+		}
+		if source == "" {
+			source = fh.Filename
+		}
+	} else {
+		for _, sn := range services.SplitSerials(c.FormValue("serials")) {
+			rows = append(rows, services.PackingListRow{Serial: sn})
+		}
+	}
+	if len(rows) == 0 {
+		auth.SetFlash(c.Request(), "error", "Upload a packing list or paste the serial numbers to stock in")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	byProduct, order, rowErrors := groupPackingListRows(rows, products, selected)
+	result := &models.SerialStockInResult{Errors: rowErrors}
+	for _, p := range order {
+		added, existing, err := database.StockInSerials(project.ID, p.ID, byProduct[p.ID], source, user.ID)
+		if err != nil {
+			slog.Error("Error stocking in serials", slog.Int("project_id", project.ID), slog.Int("product_id", p.ID), slog.String("error", err.Error()))
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: failed to save the serials", p.ItemName))
+			continue
+		}
+		result.Added += added
+		result.Existing += existing
+		if added > 0 {
+			recordAudit(c, project.ID, models.AuditEntitySerial, 0, models.AuditActionImport,
+				fmt.Sprintf("Stocked in %d serial(s) of %s", added, p.ItemName),
+				nil, map[string]interface{}{"source": source, "added": added, "already_registered": existing})
+		}
+	}
+
+	msg := fmt.Sprintf("Stocked in %d serial(s)", result.Added)
+	if result.Existing > 0 {
+		msg += fmt.Sprintf("; %d were already registered", result.Existing)
+	}
+	switch {
+	case len(result.Errors) > 0 && result.Added == 0 && result.Existing == 0:
+		auth.SetFlash(c.Request(), "error", "No serials stocked in. "+summariseList(result.Errors))
+	case len(result.Errors) > 0:
+		auth.SetFlash(c.Request(), "warning", msg+". Skipped: "+summariseList(result.Errors))
+	default:
+		auth.SetFlash(c.Request(), "success", msg)
+	}
+	return c.Redirect(http.StatusFound, redirect)
+}
+
+// groupPackingListRows assigns packing list rows to products, matching the
+// row's product against item names and brand/models. Rows without a product
+// go to selected. It returns the serials per product ID, the products in the
+// order first seen, and the rows that could not be assigned.
+func groupPackingListRows(rows []services.PackingListRow, products []*models.Product, selected *models.Product) (map[int][]string, []*models.Product, []string) {
+	lookup := make(map[string]*models.Product, len(products)*2)
+	for _, p := range products {
+		lookup[strings.ToLower(strings.TrimSpace(p.BrandModel))] = p
+	}
+	// Item names win over brand/models when the two collide.
+	for _, p := range products {
+		lookup[strings.ToLower(strings.TrimSpace(p.ItemName))] = p
+	}
+	delete(lookup, "")
+
+	byProduct := make(map[int][]string)
+	var order []*models.Product
+	var errs []string
+	for _, r := range rows {
+		p := selected
+		if r.Product != "" {
+			p = lookup[strings.ToLower(r.Product)]
+			if p == nil {
+				errs = append(errs, fmt.Sprintf("row %d: no product named %q", r.Row, r.Product))
+				continue
+			}
+		}
+		if p == nil {
+			if r.Row > 0 {
+				errs = append(errs, fmt.Sprintf("row %d: choose the product of %s", r.Row, r.Serial))
+			} else {
+				errs = append(errs, "choose the product of "+r.Serial)
+			}
+			continue
+		}
+		if _, ok := byProduct[p.ID]; !ok {
+			order = append(order, p)
+		}
+		byProduct[p.ID] = append(byProduct[p.ID], r.Serial)
+	}
+	return byProduct, order, errs
+}
+
+// SetRegistrySerialConditionHandler handles POST /projects/:id/serials/:sid/condition.
+// Admins mark a registered serial damaged, which keeps it off new DCs, or good again.
+func SetRegistrySerialConditionHandler(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+	redirect := registryReturnURL(c, project.ID)
+
+	serial, ok := registrySerialFromParam(c, project.ID)
+	if !ok {
+		auth.SetFlash(c.Request(), "error", "Serial not found")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	condition := c.FormValue("condition")
+	if condition != models.SerialConditionGood && condition != models.SerialConditionDamaged {
+		auth.SetFlash(c.Request(), "error", "Invalid condition")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	if condition == models.SerialConditionDamaged && serial.State == models.SerialStateDispatched {
+		auth.SetFlash(c.Request(), "error", fmt.Sprintf("Serial %s is on issued DC %s. Take it back on a return DC first.", serial.SerialNumber, serial.DCNumber))
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	if err := database.SetRegistrySerialCondition(project.ID, serial.ID, condition); err != nil {
+		slog.Error("Error updating serial condition", slog.Int("serial_id", serial.ID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to update the serial")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	summary := fmt.Sprintf("Marked serial %s of %s %s", serial.SerialNumber, serial.ItemName, condition)
+	recordAudit(c, project.ID, models.AuditEntitySerial, serial.ID, models.AuditActionUpdate, summary,
+		map[string]interface{}{"condition": serial.Condition}, map[string]interface{}{"condition": condition})
+
+	auth.SetFlash(c.Request(), "success", summary)
+	return c.Redirect(http.StatusFound, redirect)
+}
+
+// DeleteRegistrySerialHandler handles POST /projects/:id/serials/:sid/delete.
+// Admins remove a serial stocked in by mistake; serials on a live DC stay.
+func DeleteRegistrySerialHandler(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+	redirect := registryReturnURL(c, project.ID)
+
+	serial, ok := registrySerialFromParam(c, project.ID)
+	if !ok {
+		auth.SetFlash(c.Request(), "error", "Serial not found")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	if serial.DCID != nil {
+		auth.SetFlash(c.Request(), "error", fmt.Sprintf("Serial %s is on DC %s and cannot be removed from the registry", serial.SerialNumber, serial.DCNumber))
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	if err := database.DeleteRegistrySerial(project.ID, serial.ID); err != nil {
+		slog.Error("Error deleting registry serial", slog.Int("serial_id", serial.ID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to remove the serial")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	summary := fmt.Sprintf("Removed serial %s of %s from the registry", serial.SerialNumber, serial.ItemName)
+	recordAudit(c, project.ID, models.AuditEntitySerial, serial.ID, models.AuditActionDelete, summary, serial, nil)
+
+	auth.SetFlash(c.Request(), "success", summary)
+	return c.Redirect(http.StatusFound, redirect)
+}
+
+// registrySerialFromParam loads the registered serial in the URL.
+func registrySerialFromParam(c echo.Context, projectID int) (*models.RegistrySerial, bool) {
+	id, err := strconv.Atoi(c.Param("sid"))
+	if err != nil {
+		return nil, false
+	}
+	serial, err := database.GetRegistrySerial(projectID, id)
+	if err != nil {
+		return nil, false
+	}
+	return serial, true
+}
+
+// registryReturnURL returns the registry page with the filters the action was
+// taken from.
+func registryReturnURL(c echo.Context, projectID int) string {
+	redirect := fmt.Sprintf("/projects/%d/serials", projectID)
+	if q, err := url.ParseQuery(c.FormValue("return_query")); err == nil && len(q) > 0 {
+		redirect += "?" + q.Encode()
+	}
+	return redirect
+}
+
+// summariseList joins the first few items of a list for a flash message and
+// counts the rest.
+func summariseList(items []string) string {
+	if len(items) <= registryListLimit {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:registryListLimit], ", "), len(items)-registryListLimit)
+}
+
+// serialRegistryGuard checks the serials of a new or edited DC against the
+// project's serial registry. In enforce mode Refused is the message to show
+// for serials missing from the registry or marked damaged; in warn mode they
+// are collected for a warning once the DC is saved.
+type serialRegistryGuard struct {
+	projectID int
+	mode      string
+	Refused   string
+	warnings  []string
+}
+
+// newSerialRegistryGuard loads the registry mode of a project. When it cannot
+// be read the registry is not checked; the duplicate checks still apply.
+func newSerialRegistryGuard(projectID int) *serialRegistryGuard {
+	mode, err := database.GetSerialRegistryMode(projectID)
+	if err != nil {
+		slog.Error("Error fetching serial registry mode", slog.Int("projectID", projectID), slog.String("error", err.Error()))
+		mode = models.SerialRegistryOff
+	}
+	return &serialRegistryGuard{projectID: projectID, mode: mode}
+}
+
+// check checks the serials of one product and returns the refusal message for
+// them, or "" when they may be used.
+func (g *serialRegistryGuard) check(productID int, productName string, serials []string) string {
+	if g.mode == models.SerialRegistryOff || len(serials) == 0 {
+		return ""
+	}
+	missing, damaged, err := database.CheckSerialsInRegistry(g.projectID, productID, serials)
+	if err != nil {
+		slog.Error("Error checking serial registry", slog.Int("projectID", g.projectID), slog.String("error", err.Error()))
+		if g.mode == models.SerialRegistryEnforce {
+			return "Could not check the serial registry, please try again"
+		}
+		return ""
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("not in the serial registry: %s", summariseList(missing)))
+	}
+	if len(damaged) > 0 {
+		problems = append(problems, fmt.Sprintf("marked damaged: %s", summariseList(damaged)))
+	}
+	if len(problems) == 0 {
+		return ""
+	}
+	msg := fmt.Sprintf("%s serials %s", productName, strings.Join(problems, "; "))
+	if g.mode == models.SerialRegistryEnforce {
+		if g.Refused == "" {
+			g.Refused = msg
+		}
+		return msg
+	}
+	g.warnings = append(g.warnings, msg)
+	return ""
+}
+
+// flash sets the success message of the save, turned into a warning that
+// lists the unregistered serials when there are any.
+func (g *serialRegistryGuard) flash(c echo.Context, success string) {
+	if len(g.warnings) == 0 {
+		auth.SetFlash(c.Request(), "success", success)
+		return
+	}
+	auth.SetFlash(c.Request(), "warning", fmt.Sprintf("%s. Check these against the packing list: %s.",
+		strings.TrimSuffix(success, "."), strings.Join(g.warnings, ". ")))
+}
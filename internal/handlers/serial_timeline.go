@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	errorpage "github.com/narendhupati/dc-management-tool/components/pages/error"
+	serialsearchpage "github.com/narendhupati/dc-management-tool/components/pages/serial_search"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// loadSerialTimelines reads the serial and optional product_id query params
+// and returns the matching timelines.
+func loadSerialTimelines(c echo.Context, projectID int) (string, []*models.SerialTimeline, error) {
+	serial := strings.TrimSpace(c.QueryParam("serial"))
+	timelines, err := database.GetSerialTimelines(projectID, serial)
+	if err != nil {
+		return serial, nil, err
+	}
+	if productID, _ := strconv.Atoi(c.QueryParam("product_id")); productID > 0 {
+		var filtered []*models.SerialTimeline
+		for _, t := range timelines {
+			if t.ProductID == productID {
+				filtered = append(filtered, t)
+			}
+		}
+		timelines = filtered
+	}
+	return serial, timelines, nil
+}
+
+// ShowSerialTimeline handles GET /projects/:id/serial-search/timeline
+func ShowSerialTimeline(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+	project := c.Get("currentProject").(*models.Project)
+
+	serial, timelines, err := loadSerialTimelines(c, project.ID)
+	if serial == "" {
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/serial-search", project.ID))
+	}
+	if err != nil {
+		slog.Error("Error building serial timeline", slog.Int("project_id", project.ID), slog.String("serial", serial), slog.String("error", err.Error()))
+		return components.Render(c, http.StatusInternalServerError,
+			errorpage.ErrorPage(http.StatusInternalServerError, "Failed to load serial history", err.Error()))
+	}
+
+	allProjects, _ := database.GetAccessibleProjects(user)
+	flashType, flashMessage := auth.PopFlash(c.Request())
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
+	pageContent := serialsearchpage.SerialTimelinePage(project, serial, timelines)
+	return components.RenderOK(c, layouts.MainWithContent("Serial "+serial, sidebar, topbar, flashMessage, flashType, pageContent))
+}
+
+// SerialTimelineJSON handles GET /projects/:id/serial-search/timeline/json
+func SerialTimelineJSON(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+
+	serial, timelines, err := loadSerialTimelines(c, project.ID)
+	if serial == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "serial is required"})
+	}
+	if err != nil {
+		slog.Error("Error building serial timeline", slog.Int("project_id", project.ID), slog.String("serial", serial), slog.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to load serial history"})
+	}
+	if len(timelines) == 0 {
+		return c.JSON(http.StatusNotFound, map[string]interface{}{"error": "serial not found", "serial_number": serial})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"serial_number": serial,
+		"timelines":     timelines,
+	})
+}
+
+// PrintSerialCertificate handles GET /projects/:id/serial-search/timeline/certificate
+// and renders a one-page history certificate for one product's serial.
+func PrintSerialCertificate(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+
+	serial, timelines, err := loadSerialTimelines(c, project.ID)
+	if serial == "" {
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/serial-search", project.ID))
+	}
+	timelineURL := fmt.Sprintf("/projects/%d/serial-search/timeline?serial=%s", project.ID, url.QueryEscape(serial))
+	if err != nil {
+		slog.Error("Error building serial timeline", slog.Int("project_id", project.ID), slog.String("serial", serial), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to load serial history")
+		return c.Redirect(http.StatusFound, timelineURL)
+	}
+	switch len(timelines) {
+	case 0:
+		auth.SetFlash(c.Request(), "error", "Serial "+serial+" not found")
+		return c.Redirect(http.StatusFound, timelineURL)
+	case 1:
+	default:
+		auth.SetFlash(c.Request(), "warning", "Serial "+serial+" is recorded against several products; print the certificate from the product's timeline")
+		return c.Redirect(http.StatusFound, timelineURL)
+	}
+
+	company, _ := database.GetCompanySettings()
+	return components.RenderOK(c, serialsearchpage.SerialCertificate(project, company, timelines[0], time.Now().Format("02 Jan 2006")))
+}
@@ -1,21 +1,25 @@
 package handlers
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/narendhupati/dc-management-tool/internal/auth"
 	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/helpers"
+	"github.com/narendhupati/dc-management-tool/internal/models"
 )
 
 // SerialValidationRequest is the JSON body for the validation endpoint.
 type SerialValidationRequest struct {
 	ProjectID     int    `json:"project_id"`
 	ProductID     int    `json:"product_id"`
-	SerialNumbers string `json:"serial_numbers"` // newline-separated
+	SerialNumbers string `json:"serial_numbers"` // newline-separated; ranges such as ABC001-ABC500 are expanded
 	ExcludeDCID   *int   `json:"exclude_dc_id"`
 }
 
@@ -24,7 +28,18 @@ type SerialValidationResponse struct {
 	Valid            bool                     `json:"valid"`
 	DuplicateInDB    []SerialConflictResponse `json:"duplicate_in_db"`
 	DuplicateInInput []string                 `json:"duplicate_in_input"`
-	TotalCount       int                      `json:"total_count"`
+	TotalCount       int                      `json:"total_count"` // after range expansion
+	InputError       string                   `json:"input_error,omitempty"`
+
+	// Serial registry findings, reported when a product is given and the
+	// project checks its registry. They only invalidate the input in enforce mode.
+	RegistryMode      string   `json:"registry_mode"`
+	NotInRegistry     []string `json:"not_in_registry"`
+	DamagedInRegistry []string `json:"damaged_in_registry"`
+
+	// Serials breaking the product's serial format rule, each with the reason.
+	FormatRule   string   `json:"format_rule"`
+	FormatErrors []string `json:"format_errors"`
 }
 
 // SerialConflictResponse is a single conflict entry.
@@ -46,8 +61,8 @@ func ValidateSerialNumbers(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "project_id is required"})
 	}
 
-	// Parse serial numbers
-	serials := parseSerialNumbers(req.SerialNumbers)
+	// Parse serial numbers, expanding ranges
+	serials, inputErr := helpers.ParseSerialInput(req.SerialNumbers)
 
 	// Find duplicates within the input itself
 	seen := make(map[string]bool)
@@ -99,11 +114,63 @@ func ValidateSerialNumbers(c echo.Context) error {
 		DuplicateInDB:    dbDups,
 		DuplicateInInput: dupsInInput,
 		TotalCount:       len(serials),
+		RegistryMode:     models.SerialRegistryOff,
+	}
+	if inputErr != nil {
+		resp.InputError = inputErr.Error()
+		resp.Valid = false
+	}
+
+	if req.ProductID > 0 && len(serials) > 0 {
+		if product, err := database.GetProductByID(req.ProductID); err == nil && product.ProjectID == req.ProjectID {
+			resp.FormatRule = product.SerialRule.Describe()
+			resp.FormatErrors = product.SerialRule.Violations(unique)
+			sort.Strings(resp.FormatErrors)
+			if len(resp.FormatErrors) > 0 {
+				resp.Valid = false
+			}
+		}
+	}
+
+	if req.ProductID > 0 && len(unique) > 0 {
+		mode, err := database.GetSerialRegistryMode(req.ProjectID)
+		if err != nil {
+			slog.Error("Failed to load serial registry mode", slog.Int("project_id", req.ProjectID), slog.String("error", err.Error()))
+			mode = models.SerialRegistryOff
+		}
+		resp.RegistryMode = mode
+		if mode != models.SerialRegistryOff {
+			sort.Strings(unique)
+			resp.NotInRegistry, resp.DamagedInRegistry, err = database.CheckSerialsInRegistry(req.ProjectID, req.ProductID, unique)
+			if err != nil {
+				slog.Error("Failed to check serial registry", slog.Int("project_id", req.ProjectID), slog.String("error", err.Error()))
+				return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to validate serial numbers"})
+			}
+			if mode == models.SerialRegistryEnforce && (len(resp.NotInRegistry) > 0 || len(resp.DamagedInRegistry) > 0) {
+				resp.Valid = false
+			}
+		}
 	}
 
 	return c.JSON(http.StatusOK, resp)
 }
 
+// dcIssueRefusal returns why a DC in this status cannot be issued, or "" when
+// it is a draft, awaiting approval or rejected (the approval gate explains that).
+func dcIssueRefusal(status string) string {
+	switch {
+	case models.IsIssuableStatus(status), status == models.DCStatusRejected:
+		return ""
+	case status == models.DCStatusIssued:
+		return "DC is already issued"
+	case status == models.DCStatusCancelled:
+		return "DC is cancelled and cannot be issued"
+	case status == models.DCStatusSplitting, status == models.DCStatusSplit:
+		return "DC has been split into shipments and cannot be issued again"
+	}
+	return fmt.Sprintf("DC is %s and cannot be issued", strings.ReplaceAll(status, "_", " "))
+}
+
 // IssueDCHandler handles POST /projects/:id/dcs/:dcid/issue.
 func IssueDCHandler(c echo.Context) error {
 	user := auth.GetCurrentUser(c)
@@ -123,8 +190,8 @@ func IssueDCHandler(c echo.Context) error {
 		return c.JSON(http.StatusNotFound, map[string]interface{}{"error": "DC not found"})
 	}
 
-	if dc.Status != "draft" {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "DC is already issued"})
+	if msg := dcIssueRefusal(dc.Status); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
 	}
 
 	// Transit and Official DCs must be issued together via the shipment group
@@ -134,23 +201,27 @@ func IssueDCHandler(c echo.Context) error {
 		})
 	}
 
-	// Validate DC has line items with serial numbers
-	lineItems, err := database.GetLineItemsByDCID(dcID)
-	if err != nil || len(lineItems) == 0 {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "DC must have at least one line item to be issued"})
+	subject := &approvalSubject{
+		Target:     models.ApprovalTarget{ProjectID: projectID, DCID: dcID},
+		EntityType: models.AuditEntityDC,
+		EntityID:   dcID,
+		Label:      "DC " + dc.DCNumber,
+		Status:     dc.Status,
+	}
+	msg, err := approvalGate(subject.Target, dc.Status, user.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to check approval"})
+	}
+	if msg != "" {
+		return c.JSON(http.StatusForbidden, map[string]interface{}{"error": msg})
+	}
+	comments, msg := parseApprovalComments(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
 	}
 
-	// Serial numbers are required for transit DCs only
-	if dc.DCType == "transit" {
-		for _, li := range lineItems { //nolint:gocritic
-			serials, _ := database.GetSerialNumbersByLineItemID(li.ID)
-			if len(serials) == 0 {
-				return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "All line items must have serial numbers before issuing"})
-			}
-			if len(serials) != li.Quantity {
-				return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Serial number count must match quantity for all line items"})
-			}
-		}
+	if msg := validateDCIssue(dc); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
 	}
 
 	if err := database.IssueDC(dcID, user.ID); err != nil {
@@ -163,12 +234,44 @@ func IssueDCHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to issue DC: " + err.Error()})
 	}
 
+	recordAudit(c, projectID, models.AuditEntityDC, dcID, models.AuditActionIssue, "Issued DC "+dc.DCNumber,
+		map[string]interface{}{"status": dc.Status}, map[string]interface{}{"status": "issued"})
+	recordApproval(c, subject, user.ID, comments)
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"success": true,
 		"message": "DC issued successfully",
 	})
 }
 
+// validateDCIssue checks that a single DC is ready to issue: its financial year
+// is open, it has line items and, for Transit DCs, serial numbers matching each
+// quantity. It returns a user-facing message when it is not.
+func validateDCIssue(dc *models.DeliveryChallan) string {
+	if msg := closedYearMessage(dc); msg != "" {
+		return msg
+	}
+
+	lineItems, err := database.GetLineItemsByDCID(dc.ID)
+	if err != nil || len(lineItems) == 0 {
+		return "DC must have at least one line item to be issued"
+	}
+
+	// Serial numbers are required for transit DCs only
+	if dc.DCType == "transit" {
+		for _, li := range lineItems { //nolint:gocritic
+			serials, _ := database.GetSerialNumbersByLineItemID(li.ID)
+			if len(serials) == 0 {
+				return "All line items must have serial numbers before issuing"
+			}
+			if len(serials) != li.SerialsRequired() {
+				return "Serial number count must match quantity for all line items"
+			}
+		}
+	}
+	return ""
+}
+
 // DeleteDCHandler handles DELETE /projects/:id/dcs/:dcid.
 func DeleteDCHandler(c echo.Context) error {
 	projectID, err := strconv.Atoi(c.Param("id"))
@@ -209,6 +312,8 @@ func DeleteDCHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to delete DC"})
 	}
 
+	recordAudit(c, projectID, models.AuditEntityDC, dcID, models.AuditActionDelete, "Deleted draft DC "+dc.DCNumber, dc, nil)
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"success":   true,
 		"message":   "DC and all associated serial numbers deleted successfully",
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 
@@ -60,7 +61,7 @@ func BackToStep1(c echo.Context) error {
 	}
 
 	allProjects, _ := database.GetAccessibleProjects(user)
-	pageContent := pageshipments.WizardStep1(user, project, allProjects, templates, transporters, "", "", csrf.Token(c.Request()), 0, prefill)
+	pageContent := pageshipments.WizardStep1(user, project, allProjects, templates, transporters, "", "", csrf.Token(c.Request()), 0, 0, prefill)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
 	return components.RenderOK(c, layouts.MainWithContent("Shipment Wizard", sidebar, topbar, "", "", pageContent))
@@ -94,7 +95,7 @@ func BackToStep2(c echo.Context) error {
 		strconv.Itoa(templateID), transporterName, vehicleNumber,
 		ewayBillNumber, docketNumber, taxType, reverseCharge,
 		billFromAddresses, dispatchFromAddresses, billToAddresses, shipToAddresses,
-		csrf.Token(c.Request()), 0, shipToAddressIDs,
+		csrf.Token(c.Request()), 0, 0, shipToAddressIDs,
 		billFromAddrID, dispatchFromAddrID, billToAddrID,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
@@ -119,7 +120,7 @@ func BackToStep3(c echo.Context) error {
 
 	products, _ := database.GetTemplateProducts(templateID)
 	quantities := parseQuantityForm(c, products, shipToAddressIDs)
-	quantityAddresses := loadQuantityAddresses(projectID, shipToAddressIDs)
+	quantityAddresses := loadQuantityAddresses(projectID, shipToAddressIDs, 0)
 
 	allProjects, _ := database.GetAccessibleProjects(user)
 	pageContent := pageshipments.WizardStep3Quantities(
@@ -128,7 +129,7 @@ func BackToStep3(c echo.Context) error {
 		transporterName, vehicleNumber, ewayBillNumber, docketNumber,
 		taxType, reverseCharge,
 		billFromAddrID, dispatchFromAddrID, billToAddrID, transitShipToAddrID,
-		shipToAddressIDs, csrf.Token(c.Request()), 0,
+		shipToAddressIDs, csrf.Token(c.Request()), 0, 0,
 		"", "",
 		quantities, nil,
 	)
@@ -161,15 +162,7 @@ func BackToStep4(c echo.Context) error {
 	serialData := parseStep4Form(c, products, shipToAddressIDs)
 
 	// Build prefill maps from serial data
-	prefillSerials := make(map[int][]string)
-	prefillAssignments := make(map[string][]string)
-	for _, pd := range serialData {
-		prefillSerials[pd.ProductID] = pd.AllSerials
-		for shipToID, assigned := range pd.Assignments {
-			key := fmt.Sprintf("%d_%d", pd.ProductID, shipToID)
-			prefillAssignments[key] = assigned
-		}
-	}
+	prefillSerials, prefillAssignments := pageshipments.WizardSerialPrefill(serialData)
 
 	// Build quantity hidden fields
 	var quantityHiddenFields []pageshipments.QuantityHiddenField
@@ -200,7 +193,7 @@ func BackToStep4(c echo.Context) error {
 		strconv.Itoa(billFromAddrID), strconv.Itoa(dispatchFromAddrID),
 		strconv.Itoa(billToAddrID), strconv.Itoa(transitShipToAddrID),
 		shipToIDStrings, shipToAddresses,
-		csrf.Token(c.Request()), 0,
+		csrf.Token(c.Request()), 0, 0,
 		prefillSerials, prefillAssignments, nil,
 		quantityHiddenFields,
 		computeProductQuantityTotals(quantities),
@@ -227,6 +220,7 @@ func EditBackToStep1(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid group id")
 	}
+	editVersion := formEditVersion(c)
 
 	templateID, numLocations, challanDate, transporterName, vehicleNumber, ewayBillNumber, docketNumber, taxType, reverseCharge := parseStep2Form(c)
 
@@ -256,7 +250,7 @@ func EditBackToStep1(c echo.Context) error {
 	}
 
 	allProjects, _ := database.GetAccessibleProjects(user)
-	pageContent := pageshipments.WizardStep1(user, project, allProjects, templates, transporters, "", "", csrf.Token(c.Request()), gid, prefill)
+	pageContent := pageshipments.WizardStep1(user, project, allProjects, templates, transporters, "", "", csrf.Token(c.Request()), gid, editVersion, prefill)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
 	return components.RenderOK(c, layouts.MainWithContent("Edit Shipment", sidebar, topbar, "", "", pageContent))
@@ -277,6 +271,7 @@ func EditBackToStep2(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid group id")
 	}
+	editVersion := formEditVersion(c)
 
 	templateID, numLocations, challanDate, transporterName, vehicleNumber, ewayBillNumber, docketNumber, taxType, reverseCharge := parseStep2Form(c)
 	billFromAddrID, dispatchFromAddrID, billToAddrID, _, shipToAddressIDs := parseStep3Form(c)
@@ -294,7 +289,7 @@ func EditBackToStep2(c echo.Context) error {
 		strconv.Itoa(templateID), transporterName, vehicleNumber,
 		ewayBillNumber, docketNumber, taxType, reverseCharge,
 		billFromAddresses, dispatchFromAddresses, billToAddresses, shipToAddresses,
-		csrf.Token(c.Request()), gid, shipToAddressIDs,
+		csrf.Token(c.Request()), gid, editVersion, shipToAddressIDs,
 		billFromAddrID, dispatchFromAddrID, billToAddrID,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
@@ -317,13 +312,14 @@ func EditBackToStep3(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid group id")
 	}
+	editVersion := formEditVersion(c)
 
 	templateID, numLocations, challanDate, transporterName, vehicleNumber, ewayBillNumber, docketNumber, taxType, reverseCharge := parseStep2Form(c)
 	billFromAddrID, dispatchFromAddrID, billToAddrID, transitShipToAddrID, shipToAddressIDs := parseStep3Form(c)
 
 	products, _ := database.GetTemplateProducts(templateID)
 	quantities := parseQuantityForm(c, products, shipToAddressIDs)
-	quantityAddresses := loadQuantityAddresses(projectID, shipToAddressIDs)
+	quantityAddresses := loadQuantityAddresses(projectID, shipToAddressIDs, gid)
 
 	allProjects, _ := database.GetAccessibleProjects(user)
 	pageContent := pageshipments.WizardStep3Quantities(
@@ -332,7 +328,7 @@ func EditBackToStep3(c echo.Context) error {
 		transporterName, vehicleNumber, ewayBillNumber, docketNumber,
 		taxType, reverseCharge,
 		billFromAddrID, dispatchFromAddrID, billToAddrID, transitShipToAddrID,
-		shipToAddressIDs, csrf.Token(c.Request()), gid,
+		shipToAddressIDs, csrf.Token(c.Request()), gid, editVersion,
 		"", "",
 		quantities, nil,
 	)
@@ -356,6 +352,7 @@ func EditBackToStep4(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid group id")
 	}
+	editVersion := formEditVersion(c)
 
 	templateID, numLocations, challanDate, transporterName, vehicleNumber, ewayBillNumber, docketNumber, taxType, reverseCharge := parseStep2Form(c)
 	billFromAddrID, dispatchFromAddrID, billToAddrID, transitShipToAddrID, shipToAddressIDs := parseStep3Form(c)
@@ -368,15 +365,7 @@ func EditBackToStep4(c echo.Context) error {
 	quantities := parseQuantityForm(c, products, shipToAddressIDs)
 	serialData := parseStep4Form(c, products, shipToAddressIDs)
 
-	prefillSerials := make(map[int][]string)
-	prefillAssignments := make(map[string][]string)
-	for _, pd := range serialData {
-		prefillSerials[pd.ProductID] = pd.AllSerials
-		for shipToID, assigned := range pd.Assignments {
-			key := fmt.Sprintf("%d_%d", pd.ProductID, shipToID)
-			prefillAssignments[key] = assigned
-		}
-	}
+	prefillSerials, prefillAssignments := pageshipments.WizardSerialPrefill(serialData)
 
 	var quantityHiddenFields []pageshipments.QuantityHiddenField
 	for _, p := range products {
@@ -406,7 +395,7 @@ func EditBackToStep4(c echo.Context) error {
 		strconv.Itoa(billFromAddrID), strconv.Itoa(dispatchFromAddrID),
 		strconv.Itoa(billToAddrID), strconv.Itoa(transitShipToAddrID),
 		shipToIDStrings, shipToAddresses,
-		csrf.Token(c.Request()), gid,
+		csrf.Token(c.Request()), gid, editVersion,
 		prefillSerials, prefillAssignments, nil,
 		quantityHiddenFields,
 		computeProductQuantityTotals(quantities),
@@ -439,8 +428,14 @@ func loadAllAddresses(projectID int) (billFrom, dispatchFrom, billTo, shipTo []*
 	return
 }
 
-// loadQuantityAddresses loads QuantityAddress structs for the selected ship-to IDs.
-func loadQuantityAddresses(projectID int, shipToAddressIDs []int) []pageshipments.QuantityAddress {
+// loadQuantityAddresses loads QuantityAddress structs for the selected ship-to IDs,
+// with what is left of the BOQ plan for each. The DCs of editGroupID, the
+// shipment being edited, do not count against the plan; pass 0 for a new one.
+func loadQuantityAddresses(projectID int, shipToAddressIDs []int, editGroupID int) []pageshipments.QuantityAddress {
+	remaining, err := database.GetBOQRemaining(projectID, editGroupID, 0)
+	if err != nil {
+		slog.Error("error loading BOQ plan", slog.String("error", err.Error()), slog.Int("projectID", projectID))
+	}
 	shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
 	var result []pageshipments.QuantityAddress
 	if shipToConfig != nil {
@@ -452,8 +447,9 @@ func loadQuantityAddresses(projectID int, shipToAddressIDs []int) []pageshipment
 		for _, a := range allShipTo {
 			if selectedSet[a.ID] {
 				result = append(result, pageshipments.QuantityAddress{
-					ID:   a.ID,
-					Name: a.DisplayName(),
+					ID:            a.ID,
+					Name:          a.DisplayName(),
+					PlanRemaining: remaining[a.ID],
 				})
 			}
 		}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -43,6 +44,8 @@ func ShowEditShipmentWizard(c echo.Context) error {
 	if err != nil || group.Status != "draft" || group.ProjectID != project.ID {
 		return echo.NewHTTPError(http.StatusNotFound, "draft shipment group not found")
 	}
+	// The version travels through every step so a stale save can be rejected.
+	editVersion := currentEditVersion(models.AuditEntityShipmentGroup, gid)
 
 	// 2. Load all DCs in the group (transit + official).
 	dcs, err := database.GetShipmentGroupDCs(gid)
@@ -150,6 +153,7 @@ func ShowEditShipmentWizard(c echo.Context) error {
 		flashMessage,
 		csrfToken,
 		gid,
+		editVersion,
 		prefill,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
@@ -187,6 +191,7 @@ func EditWizardStep2(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid group id")
 	}
+	editVersion := formEditVersion(c)
 	group, err := database.GetShipmentGroup(gid)
 	if err != nil || group.Status != "draft" || group.ProjectID != project.ID {
 		return echo.NewHTTPError(http.StatusNotFound, "draft shipment group not found")
@@ -281,6 +286,7 @@ func EditWizardStep2(c echo.Context) error {
 		shipToAddresses,
 		csrf.Token(c.Request()),
 		gid,
+		editVersion,
 		preselectedIDs,
 		preselectedBillFromID,
 		preselectedDispatchFromID,
@@ -310,6 +316,7 @@ func EditWizardStep3(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid group id")
 	}
+	editVersion := formEditVersion(c)
 	group, err := database.GetShipmentGroup(gid)
 	if err != nil || group.Status != "draft" || group.ProjectID != project.ID {
 		return echo.NewHTTPError(http.StatusNotFound, "draft shipment group not found")
@@ -384,6 +391,7 @@ func EditWizardStep3(c echo.Context) error {
 			billFromAddresses, dispatchFromAddresses, billToAddresses, shipToAddresses,
 			csrf.Token(c.Request()),
 			gid,
+			editVersion,
 			shipToAddressIDs,
 			billFromAddressID, dispatchFromAddressID, billToAddressID,
 		)
@@ -400,23 +408,7 @@ func EditWizardStep3(c echo.Context) error {
 	}
 
 	// Load ship-to address details for quantity grid column headers
-	shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
-	var quantityAddresses []pageshipments.QuantityAddress
-	if shipToConfig != nil {
-		allShipTo, _ := database.GetAllAddressesByConfigID(shipToConfig.ID)
-		selectedSet := make(map[int]bool)
-		for _, id := range shipToAddressIDs {
-			selectedSet[id] = true
-		}
-		for _, a := range allShipTo {
-			if selectedSet[a.ID] {
-				quantityAddresses = append(quantityAddresses, pageshipments.QuantityAddress{
-					ID:   a.ID,
-					Name: a.DisplayName(),
-				})
-			}
-		}
-	}
+	quantityAddresses := loadQuantityAddresses(projectID, shipToAddressIDs, gid)
 
 	// Load existing per-location quantities from official DCs to pre-fill grid
 	dcs, _ := database.GetShipmentGroupDCs(gid)
@@ -445,7 +437,7 @@ func EditWizardStep3(c echo.Context) error {
 		transporterName, vehicleNumber, ewayBillNumber, docketNumber,
 		taxType, reverseCharge,
 		billFromAddressID, dispatchFromAddressID, billToAddressID, transitShipToAddrID,
-		shipToAddressIDs, csrf.Token(c.Request()), gid,
+		shipToAddressIDs, csrf.Token(c.Request()), gid, editVersion,
 		"", "",
 		prefillQuantities, nil,
 	)
@@ -473,6 +465,7 @@ func EditWizardStep4(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid group id")
 	}
+	editVersion := formEditVersion(c)
 	group, err := database.GetShipmentGroup(gid)
 	if err != nil || group.Status != "draft" || group.ProjectID != project.ID {
 		return echo.NewHTTPError(http.StatusNotFound, "draft shipment group not found")
@@ -501,23 +494,7 @@ func EditWizardStep4(c echo.Context) error {
 		}
 
 		// Re-render quantity grid with errors
-		shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
-		var quantityAddresses []pageshipments.QuantityAddress
-		if shipToConfig != nil {
-			allShipTo, _ := database.GetAllAddressesByConfigID(shipToConfig.ID)
-			selectedSet := make(map[int]bool)
-			for _, id := range shipToAddressIDs {
-				selectedSet[id] = true
-			}
-			for _, a := range allShipTo {
-				if selectedSet[a.ID] {
-					quantityAddresses = append(quantityAddresses, pageshipments.QuantityAddress{
-						ID:   a.ID,
-						Name: a.DisplayName(),
-					})
-				}
-			}
-		}
+		quantityAddresses := loadQuantityAddresses(projectID, shipToAddressIDs, gid)
 
 		allProjects, _ := database.GetAccessibleProjects(user)
 		pageContent := pageshipments.WizardStep3Quantities(
@@ -526,7 +503,7 @@ func EditWizardStep4(c echo.Context) error {
 			transporterName, vehicleNumber, ewayBillNumber, docketNumber,
 			taxType, reverseCharge,
 			billFromAddressID, dispatchFromAddressID, billToAddressID, transitShipToAddrID,
-			shipToAddressIDs, csrf.Token(c.Request()), gid,
+			shipToAddressIDs, csrf.Token(c.Request()), gid, editVersion,
 			globalErr, "error",
 			quantities, qtyErrors,
 		)
@@ -580,12 +557,17 @@ func EditWizardStep4(c echo.Context) error {
 			break
 		}
 	}
-	prefillSerials := map[int][]string{}
+	prefillSerials := map[string][]string{}
 	if transitDC != nil {
 		lineItems, _ := database.GetLineItemsByDCID(transitDC.ID)
 		for _, item := range lineItems {
-			serials, _ := database.GetSerialNumbersByLineItemID(item.ID)
-			prefillSerials[item.ProductID] = serials
+			for _, comp := range item.KitComponents {
+				prefillSerials[models.SerialSlotKey(item.ProductID, comp.ProductID)] = comp.SerialNumbers
+			}
+			if len(item.KitComponents) == 0 {
+				serials, _ := database.GetSerialNumbersByLineItemID(item.ID)
+				prefillSerials[models.SerialSlotKey(item.ProductID, 0)] = serials
+			}
 		}
 	}
 
@@ -598,7 +580,7 @@ func EditWizardStep4(c echo.Context) error {
 		strconv.Itoa(billFromAddressID), strconv.Itoa(dispatchFromAddressID),
 		strconv.Itoa(billToAddressID), strconv.Itoa(transitShipToAddrID),
 		shipToIDStrings, shipToAddresses,
-		csrf.Token(c.Request()), gid,
+		csrf.Token(c.Request()), gid, editVersion,
 		prefillSerials, nil, nil,
 		quantityHiddenFields,
 		computeProductQuantityTotals(quantities),
@@ -626,6 +608,7 @@ func EditWizardStep5(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid group id")
 	}
+	editVersion := formEditVersion(c)
 	group, err := database.GetShipmentGroup(gid)
 	if err != nil || group.Status != "draft" || group.ProjectID != project.ID {
 		return echo.NewHTTPError(http.StatusNotFound, "draft shipment group not found")
@@ -649,28 +632,34 @@ func EditWizardStep5(c echo.Context) error {
 	quantities := parseQuantityForm(c, products, shipToAddressIDs)
 
 	// Validate serial counts using per-location quantities
+	slots := wizardSerialSlots(products, serialData)
 	validationErrors := make(map[string]string)
-	for i, pd := range serialData {
+	for _, slot := range slots {
+		pd := slot.Data
+		if pd.InputError != "" {
+			validationErrors["serials_"+slot.Key] = pd.InputError
+			continue
+		}
 		// Sum quantities across all locations for this product
 		expectedTotal := 0
 		for _, addrID := range shipToAddressIDs {
-			expectedTotal += quantities[products[i].ID][addrID]
+			expectedTotal += quantities[slot.LineID][addrID] * slot.PerUnit
 		}
 		if len(pd.AllSerials) > 0 && len(pd.AllSerials) != expectedTotal {
-			validationErrors[fmt.Sprintf("serials_%d", pd.ProductID)] = fmt.Sprintf("Expected %d serials, got %d", expectedTotal, len(pd.AllSerials))
+			validationErrors["serials_"+slot.Key] = fmt.Sprintf("Expected %d serials, got %d", expectedTotal, len(pd.AllSerials))
 		}
 		seen := make(map[string]bool)
 		for _, sn := range pd.AllSerials {
 			if seen[sn] {
-				validationErrors[fmt.Sprintf("serials_%d", pd.ProductID)] = fmt.Sprintf("Duplicate serial: %s", sn)
+				validationErrors["serials_"+slot.Key] = fmt.Sprintf("Duplicate serial: %s", sn)
 				break
 			}
 			seen[sn] = true
 		}
 		for shipToID, assigned := range pd.Assignments {
-			locationQty := quantities[products[i].ID][shipToID]
+			locationQty := quantities[slot.LineID][shipToID] * slot.PerUnit
 			if len(assigned) > locationQty {
-				validationErrors[fmt.Sprintf("assign_%d_%d", pd.ProductID, shipToID)] = fmt.Sprintf("Too many serials assigned (max %d)", locationQty)
+				validationErrors[fmt.Sprintf("assign_%s_%d", slot.Key, shipToID)] = fmt.Sprintf("Too many serials assigned (max %d)", locationQty)
 			}
 		}
 	}
@@ -680,6 +669,18 @@ func EditWizardStep5(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/%d/edit", projectID, gid))
 	}
 
+	registry := newSerialRegistryGuard(projectID)
+	for _, slot := range slots {
+		msg := models.SerialRuleError(slot.Name, slot.Rule, slot.Data.AllSerials)
+		if msg == "" {
+			msg = registry.check(slot.ProductID, slot.Name, slot.Data.AllSerials)
+		}
+		if msg != "" {
+			auth.SetFlash(c.Request(), "error", msg)
+			return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/%d/edit", projectID, gid))
+		}
+	}
+
 	// Load template for display
 	tmpl, _ := database.GetTemplateByID(templateID)
 
@@ -742,7 +743,9 @@ func EditWizardStep5(c echo.Context) error {
 		serialData,
 		csrf.Token(c.Request()),
 		gid,
+		editVersion,
 		quantityHiddenFields,
+		wizardTaxTypeCheck(project, taxType, billFromAddressID, billToAddressID, transitShipToAddrID),
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -851,6 +854,18 @@ func handleEditError(c echo.Context, projectID, gid int, err error) error {
 		fmt.Sprintf("/projects/%d/shipments/%d/edit", projectID, gid))
 }
 
+// shipmentGroupAuditState is the audited view of a draft shipment group's editable fields.
+func shipmentGroupAuditState(g *models.ShipmentGroup, shipToAddressIDs []int) map[string]interface{} {
+	sort.Ints(shipToAddressIDs)
+	return map[string]interface{}{
+		"template_id":         g.TemplateID,
+		"num_locations":       g.NumLocations,
+		"tax_type":            g.TaxType,
+		"reverse_charge":      g.ReverseCharge,
+		"ship_to_address_ids": shipToAddressIDs,
+	}
+}
+
 // ─── SaveShipmentEdit ─────────────────────────────────────────────────────────
 
 // SaveShipmentEdit processes the Step 4 review form and reconciles the DB state to
@@ -894,6 +909,13 @@ func SaveShipmentEdit(c echo.Context) error {
 	}
 
 	serialData := parseStep4Form(c, products, shipToAddressIDs)
+	slots := wizardSerialSlots(products, serialData)
+	for _, slot := range slots {
+		if slot.Data.InputError != "" {
+			auth.SetFlash(c.Request(), "error", fmt.Sprintf("Product %s: %s", slot.Name, slot.Data.InputError))
+			return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/projects/%d/shipments/%d", project.ID, gid))
+		}
+	}
 
 	// 3. Load current DCs; build officialDCMap keyed by ship_to_address_id.
 	// GetShipmentGroupDCs does not populate ShipToAddressID, so fetch each full DC.
@@ -937,51 +959,90 @@ func SaveShipmentEdit(c echo.Context) error {
 		challanDatePtr = &challanDate
 	}
 
-	// 6–10. Execute reconciliation.
-	// Note: soft-rollback design — each DB call manages its own tx. A mid-flight failure
-	// leaves partial writes; redirect prevents further damage.
 	templateIDCopy := templateID
-	if err := database.UpdateShipmentGroup(gid, &templateIDCopy, numLocations, taxType, reverseCharge); err != nil {
-		return handleEditError(c, project.ID, gid, err)
-	}
-	if err := database.UpdateTransitDC(transitDC.ID, challanDatePtr, transporterName, vehicleNumber, ewayBillNumber, docketNumber); err != nil {
-		return handleEditError(c, project.ID, gid, err)
-	}
-	if err := database.ReplaceLineItemsAndSerials(transitDC.ID, project.ID, transitLineItems, transitSerialsByLine); err != nil {
-		return handleEditError(c, project.ID, gid, err)
+	newAddressIDs := append([]int(nil), shipToAddressIDs...)
+	after := &models.ShipmentGroup{TemplateID: &templateIDCopy, NumLocations: numLocations, TaxType: taxType, ReverseCharge: reverseCharge}
+
+	dateGuard := checkChallanDate(c, project.ID, challanDate, dcChallanDay(transitDC), 0, services.DCTypeTransit, services.DCTypeOfficial)
+	if dateGuard.Refused != "" {
+		auth.SetFlash(c.Request(), "error", dateGuard.Refused)
+		return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/projects/%d/shipments/%d", project.ID, gid))
 	}
 
-	for _, addressID := range toUpdate {
-		dc := officialDCMap[addressID]
-		if err := database.UpdateOfficialDC(dc.ID, addressID, challanDatePtr); err != nil {
-			return handleEditError(c, project.ID, gid, err)
+	registry := newSerialRegistryGuard(project.ID)
+	for _, slot := range slots {
+		msg := models.SerialRuleError(slot.Name, slot.Rule, slot.Data.AllSerials)
+		if msg == "" {
+			msg = registry.check(slot.ProductID, slot.Name, slot.Data.AllSerials)
 		}
-		officialLI := buildOfficialLineItems(products, quantities, addressID)
-		if err := database.ReplaceLineItemsAndSerials(dc.ID, project.ID, officialLI, nil); err != nil {
-			return handleEditError(c, project.ID, gid, err)
+		if msg != "" {
+			auth.SetFlash(c.Request(), "error", msg)
+			return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/projects/%d/shipments/%d", project.ID, gid))
 		}
 	}
 
-	for _, addressID := range toDelete {
-		dc := officialDCMap[addressID]
-		if err := database.DeleteOfficialDC(dc.ID); err != nil {
-			return handleEditError(c, project.ID, gid, err)
+	// 6–10. Save the group, its transit DC and its official DCs in one
+	// transaction, provided the group is still at the version the wizard was
+	// opened with; otherwise nothing is written and the conflict page shows
+	// what the other user changed.
+	edit := database.ShipmentGroupEdit{
+		GroupID:          gid,
+		ProjectID:        project.ID,
+		Version:          formEditVersion(c),
+		TemplateID:       &templateIDCopy,
+		NumLocations:     numLocations,
+		TaxType:          taxType,
+		ReverseCharge:    reverseCharge,
+		ChallanDate:      challanDatePtr,
+		CreatedBy:        user.ID,
+		TransitDCID:      transitDC.ID,
+		TransporterName:  transporterName,
+		VehicleNumber:    vehicleNumber,
+		EwayBillNumber:   ewayBillNumber,
+		DocketNumber:     docketNumber,
+		TransitLineItems: transitLineItems,
+		TransitSerials:   transitSerialsByLine,
+	}
+	for _, slot := range slots {
+		if slot.ProductID != slot.LineID {
+			edit.KitSerials = append(edit.KitSerials, database.KitComponentSerials{
+				KitProductID: slot.LineID, ComponentProductID: slot.ProductID, Serials: slot.Data.AllSerials,
+			})
 		}
 	}
-
+	for _, addressID := range toUpdate {
+		edit.Officials = append(edit.Officials, database.OfficialDCEdit{
+			DCID: officialDCMap[addressID].ID, ShipToAddressID: addressID,
+			LineItems: buildOfficialLineItems(products, quantities, addressID),
+		})
+	}
+	for _, addressID := range toDelete {
+		edit.DeleteDCIDs = append(edit.DeleteDCIDs, officialDCMap[addressID].ID)
+	}
 	for _, addressID := range toAdd {
-		officialLI := buildOfficialLineItems(products, quantities, addressID)
-		if _, err := database.CreateOfficialDCInGroup(
-			project.ID, gid, addressID, challanDatePtr,
-			taxType, reverseCharge,
-			officialLI, nil,
-			user.ID,
-		); err != nil {
+		edit.Officials = append(edit.Officials, database.OfficialDCEdit{
+			ShipToAddressID: addressID,
+			LineItems:       buildOfficialLineItems(products, quantities, addressID),
+		})
+	}
+	if err := database.SaveShipmentGroupEdit(edit); err != nil {
+		if !isStaleVersion(err) {
 			return handleEditError(c, project.ID, gid, err)
 		}
+		conflict := newEditConflict(models.AuditEntityShipmentGroup, gid, gid, edit.Version,
+			shipmentGroupAuditState(group, oldAddressIDs), shipmentGroupAuditState(after, newAddressIDs))
+		conflict.Subject = fmt.Sprintf("Shipment group #%d", gid)
+		conflict.ReloadURL = fmt.Sprintf("/projects/%d/shipments/%d/edit", project.ID, gid)
+		conflict.BackURL = fmt.Sprintf("/projects/%d/shipments/%d", project.ID, gid)
+		return renderEditConflict(c, project, conflict)
 	}
 
+	recordAudit(c, project.ID, models.AuditEntityShipmentGroup, gid, models.AuditActionUpdate,
+		fmt.Sprintf("Edited draft shipment group #%d", gid),
+		shipmentGroupAuditState(group, oldAddressIDs), shipmentGroupAuditState(after, newAddressIDs))
+	dateGuard.audit(c, project.ID, models.AuditEntityShipmentGroup, gid)
+
 	// 11. Success — redirect to group detail.
-	auth.SetFlash(c.Request(), "success", "Shipment updated successfully.")
+	registry.flash(c, "Shipment updated successfully.")
 	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/projects/%d/shipments/%d", project.ID, gid))
 }
@@ -3,11 +3,12 @@ package handlers
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/labstack/echo/v4"
 	pageshipments "github.com/narendhupati/dc-management-tool/components/pages/shipments"
+	"github.com/narendhupati/dc-management-tool/internal/helpers"
 	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
 )
 
 // parseStep2Form parses the Step 1 form values that are carried forward as hidden
@@ -103,35 +104,115 @@ func computeProductQuantityTotals(quantities map[int]map[int]int) map[int]int {
 }
 
 // parseStep4Form parses per-product serial numbers and per-address assignments
-// from Step 3 form data.
+// from Step 3 form data. Kits get their serials per component.
 func parseStep4Form(c echo.Context, products []*models.TemplateProductRow, shipToAddressIDs []int) []pageshipments.WizardSerialData {
 	var result []pageshipments.WizardSerialData
 	for _, p := range products {
-		pd := pageshipments.WizardSerialData{
-			ProductID:   p.ID,
-			Assignments: make(map[int][]string),
+		if !p.IsKit() {
+			result = append(result, parseSerialSlot(c, p.ID, models.SerialSlotKey(p.ID, 0), shipToAddressIDs))
+			continue
 		}
-		serialsRaw := c.FormValue(fmt.Sprintf("serials_%d", p.ID))
-		if serialsRaw != "" {
-			for _, sn := range strings.Split(serialsRaw, "\n") {
-				sn = strings.TrimSpace(sn)
-				if sn != "" {
-					pd.AllSerials = append(pd.AllSerials, sn)
-				}
-			}
-		}
-		for _, shipToID := range shipToAddressIDs {
-			assignRaw := c.FormValue(fmt.Sprintf("assign_%d_%d", p.ID, shipToID))
-			if assignRaw != "" {
-				for _, sn := range strings.Split(assignRaw, "\n") {
-					sn = strings.TrimSpace(sn)
-					if sn != "" {
-						pd.Assignments[shipToID] = append(pd.Assignments[shipToID], sn)
-					}
-				}
-			}
+		pd := pageshipments.WizardSerialData{ProductID: p.ID, Assignments: make(map[int][]string)}
+		for _, comp := range p.Components {
+			pd.Components = append(pd.Components, parseSerialSlot(c, comp.ProductID, models.SerialSlotKey(p.ID, comp.ProductID), shipToAddressIDs))
 		}
 		result = append(result, pd)
 	}
 	return result
 }
+
+// parseSerialSlot parses the serials_{slot} and assign_{slot}_{shipToID}
+// fields of one serial slot into serial data for productID.
+func parseSerialSlot(c echo.Context, productID int, slotKey string, shipToAddressIDs []int) pageshipments.WizardSerialData {
+	pd := pageshipments.WizardSerialData{
+		ProductID:   productID,
+		Assignments: make(map[int][]string),
+	}
+	var err error
+	pd.AllSerials, err = helpers.ParseSerialInput(c.FormValue("serials_" + slotKey))
+	if err != nil {
+		pd.InputError = err.Error()
+	}
+	for _, shipToID := range shipToAddressIDs {
+		assigned, err := helpers.ParseSerialInput(c.FormValue(fmt.Sprintf("assign_%s_%d", slotKey, shipToID)))
+		if err != nil && pd.InputError == "" {
+			pd.InputError = err.Error()
+		}
+		if len(assigned) > 0 {
+			pd.Assignments[shipToID] = assigned
+		}
+	}
+	return pd
+}
+
+// buildShipmentLineItems builds the line items of a new shipment from the
+// per-location quantities and the serials of each template product.
+// serialData must be in products order, as parseStep4Form returns it.
+func buildShipmentLineItems(products []*models.TemplateProductRow, quantities map[int]map[int]int, shipToAddressIDs []int, serialData []pageshipments.WizardSerialData) []services.ShipmentLineItem {
+	var lineItems []services.ShipmentLineItem
+	for i, p := range products {
+		// Build per-location quantity map from the quantity grid
+		qtyByLoc := make(map[int]int)
+		for _, addrID := range shipToAddressIDs {
+			qtyByLoc[addrID] = quantities[p.ID][addrID]
+		}
+		item := services.ShipmentLineItem{
+			ProductID:     p.ID,
+			QtyByLocation: qtyByLoc,
+			Rate:          p.PerUnitPrice,
+			TaxPercentage: p.GSTPercentage,
+			AllSerials:    serialData[i].AllSerials,
+			Assignments:   serialData[i].Assignments,
+		}
+		for _, cd := range serialData[i].Components {
+			item.Components = append(item.Components, services.ShipmentComponentSerials{
+				ProductID:   cd.ProductID,
+				AllSerials:  cd.AllSerials,
+				Assignments: cd.Assignments,
+			})
+		}
+		lineItems = append(lineItems, item)
+	}
+	return lineItems
+}
+
+// wizardSerialSlot is the serials entered for one slot of the serial step: a
+// template product, or one component of a kit.
+type wizardSerialSlot struct {
+	Key       string // models.SerialSlotKey
+	LineID    int    // template product the slot belongs to; its quantities apply
+	ProductID int    // product the serials are of
+	Name      string
+	Rule      models.SerialRule
+	PerUnit   int // serials per unit of the template product
+	Data      pageshipments.WizardSerialData
+}
+
+// wizardSerialSlots lists the serial slots of parsed serial data, expanding
+// kits into their components. serialData must be in products order, as
+// parseStep4Form returns it.
+func wizardSerialSlots(products []*models.TemplateProductRow, serialData []pageshipments.WizardSerialData) []wizardSerialSlot {
+	var slots []wizardSerialSlot
+	for i, p := range products {
+		if i >= len(serialData) {
+			break
+		}
+		if !p.IsKit() {
+			slots = append(slots, wizardSerialSlot{
+				Key: models.SerialSlotKey(p.ID, 0), LineID: p.ID, ProductID: p.ID,
+				Name: p.ItemName, Rule: p.SerialRule, PerUnit: 1, Data: serialData[i],
+			})
+			continue
+		}
+		for j, comp := range p.Components {
+			if j >= len(serialData[i].Components) {
+				break
+			}
+			slots = append(slots, wizardSerialSlot{
+				Key: models.SerialSlotKey(p.ID, comp.ProductID), LineID: p.ID, ProductID: comp.ProductID,
+				Name: p.ItemName + " — " + comp.ItemName, Rule: comp.SerialRule, PerUnit: comp.Quantity, Data: serialData[i].Components[j],
+			})
+		}
+	}
+	return slots
+}
@@ -199,3 +199,42 @@ func TestValidateQuantities(t *testing.T) {
 		}
 	})
 }
+
+func TestGroupIssueRefusal(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{models.DCStatusDraft, ""},
+		{models.DCStatusPendingApproval, ""},
+		{models.DCStatusIssued, "Shipment group is already issued"},
+		{models.DCStatusCancelled, "Shipment group is cancelled and cannot be issued"},
+		{models.DCStatusRejected, "This request was rejected; reopen or resubmit it first"},
+		{models.DCStatusSplit, "Shipment group is split and cannot be issued"},
+	}
+	for _, tt := range tests {
+		if got := groupIssueRefusal(tt.status); got != tt.want {
+			t.Errorf("groupIssueRefusal(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestDCIssueRefusal(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{models.DCStatusDraft, ""},
+		{models.DCStatusPendingApproval, ""},
+		{models.DCStatusRejected, ""},
+		{models.DCStatusIssued, "DC is already issued"},
+		{models.DCStatusCancelled, "DC is cancelled and cannot be issued"},
+		{models.DCStatusSplitting, "DC has been split into shipments and cannot be issued again"},
+		{models.DCStatusPartiallyDelivered, "DC is partially delivered and cannot be issued"},
+	}
+	for _, tt := range tests {
+		if got := dcIssueRefusal(tt.status); got != tt.want {
+			t.Errorf("dcIssueRefusal(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
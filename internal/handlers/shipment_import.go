@@ -0,0 +1,714 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	pageshipments "github.com/narendhupati/dc-management-tool/components/pages/shipments"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+// allocationSheetMaxSize is the largest allocation workbook accepted for import.
+const allocationSheetMaxSize = 10 * 1024 * 1024
+
+// allocationSettings are the import form's fields shared by every shipment
+// group of an allocation workbook.
+type allocationSettings struct {
+	TemplateID            int
+	ChallanDate           string
+	TaxType               string
+	ReverseCharge         string
+	BillFromAddressID     int
+	DispatchFromAddressID int
+	BillToAddressID       int
+}
+
+// parseAllocationSettings reads the shared settings of the import form.
+func parseAllocationSettings(c echo.Context) allocationSettings {
+	s := allocationSettings{
+		ChallanDate:   c.FormValue("challan_date"),
+		TaxType:       c.FormValue("tax_type"),
+		ReverseCharge: c.FormValue("reverse_charge"),
+	}
+	s.TemplateID, _ = strconv.Atoi(c.FormValue("template_id"))
+	s.BillFromAddressID, _ = strconv.Atoi(c.FormValue("bill_from_address_id"))
+	s.DispatchFromAddressID, _ = strconv.Atoi(c.FormValue("dispatch_from_address_id"))
+	s.BillToAddressID, _ = strconv.Atoi(c.FormValue("bill_to_address_id"))
+	if s.TaxType == "" {
+		s.TaxType = "cgst_sgst"
+	}
+	if s.ReverseCharge == "" {
+		s.ReverseCharge = "N"
+	}
+	return s
+}
+
+// allocationGroup is one shipment group of an allocation workbook, in the
+// shape the shipment wizard hands to services.CreateShipmentGroupDCs.
+type allocationGroup struct {
+	Name                string
+	ShipToAddressIDs    []int
+	TransitShipToAddrID int
+	VehicleNumber       string
+	TransporterName     string
+	Quantities          map[int]map[int]int              // product -> ship-to address -> units
+	SerialData          []pageshipments.WizardSerialData // in template product order
+	transitRow          int
+}
+
+// allocationImport is a checked allocation workbook: the groups it creates
+// and the preview shown for it.
+type allocationImport struct {
+	products  []*models.TemplateProductRow
+	groups    []*allocationGroup
+	registry  *serialRegistryGuard
+	dateGuard challanDateGuard
+	preview   *pageshipments.ImportPreview
+}
+
+// ShowShipmentImport handles GET /projects/:id/shipments/import.
+// It shows the allocation workbook upload form.
+func ShowShipmentImport(c echo.Context) error {
+	s := allocationSettings{
+		ChallanDate:   time.Now().Format("2006-01-02"),
+		TaxType:       "cgst_sgst",
+		ReverseCharge: "N",
+	}
+	return renderShipmentImport(c, s, nil)
+}
+
+// PreviewShipmentImport handles POST /projects/:id/shipments/import/preview.
+// It parses the uploaded allocation workbook, checks it the way the shipment
+// wizard checks a single group, and renders the dry run.
+func PreviewShipmentImport(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+	redirect := fmt.Sprintf("/projects/%d/shipments/import", project.ID)
+	s := parseAllocationSettings(c)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Please select an allocation workbook to upload")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	if fh.Size > allocationSheetMaxSize {
+		auth.SetFlash(c.Request(), "error", "File size must be less than 10MB")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	file, err := fh.Open()
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", "Failed to read the allocation workbook")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	wb, err := services.ParseAllocationWorkbook(file, fh.Filename)
+	file.Close()
+	if err != nil {
+		auth.SetFlash(c.Request(), "error", err.Error())
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	imp := checkAllocationImport(c, project.ID, s, wb, fh.Filename, false)
+	return renderShipmentImport(c, s, imp.preview)
+}
+
+// ImportShipmentsHandler handles POST /projects/:id/shipments/import.
+// It re-checks the previewed workbook and creates one draft shipment group per
+// allocation group, all in one transaction: when one group fails none is kept,
+// so the workbook can be fixed and imported again.
+func ImportShipmentsHandler(c echo.Context) error {
+	user := auth.GetCurrentUser(c)
+	project := c.Get("currentProject").(*models.Project)
+	redirect := fmt.Sprintf("/projects/%d/shipments/import", project.ID)
+	s := parseAllocationSettings(c)
+	filename := c.FormValue("filename")
+
+	var wb services.AllocationWorkbook
+	if err := json.Unmarshal([]byte(c.FormValue("workbook")), &wb); err != nil {
+		auth.SetFlash(c.Request(), "error", "The preview has expired, please upload the workbook again")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	imp := checkAllocationImport(c, project.ID, s, &wb, filename, true)
+	if len(imp.preview.Errors) > 0 || len(imp.groups) == 0 {
+		return renderShipmentImport(c, s, imp.preview)
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		slog.Error("Error starting shipment import", slog.String("error", err.Error()), slog.Int("projectID", project.ID))
+		auth.SetFlash(c.Request(), "error", "Failed to import shipment groups")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	results := make([]*services.ShipmentResult, 0, len(imp.groups))
+	for _, g := range imp.groups {
+		params := services.ShipmentParams{
+			ProjectID:             project.ID,
+			TemplateID:            s.TemplateID,
+			NumLocations:          len(g.ShipToAddressIDs),
+			ChallanDate:           s.ChallanDate,
+			TaxType:               s.TaxType,
+			ReverseCharge:         s.ReverseCharge,
+			TransporterName:       g.TransporterName,
+			VehicleNumber:         g.VehicleNumber,
+			BillFromAddressID:     s.BillFromAddressID,
+			DispatchFromAddressID: s.DispatchFromAddressID,
+			BillToAddressID:       s.BillToAddressID,
+			ShipToAddressIDs:      g.ShipToAddressIDs,
+			TransitShipToAddrID:   g.TransitShipToAddrID,
+			LineItems:             buildShipmentLineItems(imp.products, g.Quantities, g.ShipToAddressIDs, g.SerialData),
+			CreatedBy:             user.ID,
+		}
+		result, err := services.CreateShipmentGroupDCsTx(tx, params)
+		if err != nil {
+			slog.Error("Error importing shipment", slog.String("error", err.Error()), slog.Int("projectID", project.ID), slog.String("group", g.Name))
+			auth.SetFlash(c.Request(), "error", fmt.Sprintf("No shipment groups were imported; group %s failed: %v", g.Name, err))
+			return c.Redirect(http.StatusFound, redirect)
+		}
+		results = append(results, result)
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("Error committing shipment import", slog.String("error", err.Error()), slog.Int("projectID", project.ID))
+		auth.SetFlash(c.Request(), "error", "Failed to import shipment groups")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+
+	dcs := 0
+	for i, result := range results {
+		g := imp.groups[i]
+		dcs += 1 + len(result.OfficialDCs)
+
+		imp.dateGuard.audit(c, project.ID, models.AuditEntityShipmentGroup, result.GroupID)
+		recordAudit(c, project.ID, models.AuditEntityShipmentGroup, result.GroupID, models.AuditActionImport,
+			fmt.Sprintf("Imported shipment group %s from %s with transit DC %s and %d official DC(s)", g.Name, filename, result.TransitDC.DCNumber, len(result.OfficialDCs)),
+			nil, map[string]interface{}{"template_id": s.TemplateID, "challan_date": s.ChallanDate, "ship_to_address_ids": g.ShipToAddressIDs})
+	}
+
+	imp.registry.flash(c, fmt.Sprintf("Imported %d shipment group(s) with %d DCs from %s", len(imp.groups), dcs, filename))
+	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments", project.ID))
+}
+
+// DownloadAllocationTemplate handles GET /projects/:id/shipments/import/template.
+// It downloads an allocation workbook for the chosen template, with a column
+// per template product and a row per ship-to address that has an address code.
+func DownloadAllocationTemplate(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+	redirect := fmt.Sprintf("/projects/%d/shipments/import", project.ID)
+
+	templateID, _ := strconv.Atoi(c.QueryParam("template_id"))
+	tmpl, err := database.GetTemplateByID(templateID)
+	if err != nil || tmpl.ProjectID != project.ID {
+		auth.SetFlash(c.Request(), "error", "Select a template to download its allocation workbook")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	products, err := database.GetTemplateProducts(templateID)
+	if err != nil {
+		slog.Error("Error fetching template products", slog.Int("templateID", templateID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to load template products")
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	columns := make([]string, 0, len(products))
+	for _, p := range products {
+		columns = append(columns, allocationColumnName(&p.Product))
+	}
+
+	_, _, _, shipTo := loadAllAddresses(project.ID)
+	var destinations [][2]string
+	for _, a := range filterLockedShipToAddresses(project.ID, shipTo) {
+		if a.AddressCode != "" {
+			destinations = append(destinations, [2]string{a.AddressCode, a.DisplayName()})
+		}
+	}
+
+	filename := fmt.Sprintf("allocation-%s.xlsx", time.Now().Format("2006-01-02"))
+	c.Response().Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	if err := services.WriteAllocationTemplate(c.Response().Writer, columns, destinations); err != nil {
+		slog.Error("Error writing allocation workbook", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+// allocationColumnName is the header an allocation workbook uses for a
+// product: its code, else its brand/model, else its name.
+func allocationColumnName(p *models.Product) string {
+	switch {
+	case p.ProductCode != "":
+		return p.ProductCode
+	case p.BrandModel != "":
+		return p.BrandModel
+	}
+	return p.ItemName
+}
+
+// renderShipmentImport renders the import page with the form filled from s
+// and, when given, the preview of an uploaded workbook.
+func renderShipmentImport(c echo.Context, s allocationSettings, preview *pageshipments.ImportPreview) error {
+	user := auth.GetCurrentUser(c)
+	project := c.Get("currentProject").(*models.Project)
+
+	templates, err := database.GetTemplatesByProjectID(project.ID)
+	if err != nil {
+		slog.Error("Error fetching templates", slog.String("error", err.Error()), slog.Int("projectID", project.ID))
+		templates = []*models.DCTemplate{}
+	}
+	billFrom, dispatchFrom, billTo, _ := loadAllAddresses(project.ID)
+
+	props := pageshipments.ImportProps{
+		User:                  user,
+		Templates:             templates,
+		BillFrom:              billFrom,
+		DispatchFrom:          dispatchFrom,
+		BillTo:                billTo,
+		TemplateID:            s.TemplateID,
+		ChallanDate:           s.ChallanDate,
+		TaxType:               s.TaxType,
+		ReverseCharge:         s.ReverseCharge,
+		BillFromAddressID:     s.BillFromAddressID,
+		DispatchFromAddressID: s.DispatchFromAddressID,
+		BillToAddressID:       s.BillToAddressID,
+		Preview:               preview,
+		CSRFToken:             csrf.Token(c.Request()),
+	}
+
+	flashType, flashMessage := auth.PopFlash(c.Request())
+	allProjects, _ := database.GetAccessibleProjects(user)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
+	pageContent := pageshipments.Import(project, props)
+	return components.RenderOK(c, layouts.MainWithContent("Import Shipments", sidebar, topbar, flashMessage, flashType, pageContent))
+}
+
+// checkAllocationImport checks an allocation workbook against the shared
+// settings, the template, the ship-to addresses and the project's serials, and
+// builds its preview. confirm is set when the groups are about to be created;
+// before that an admin is only warned about a refused challan date, since the
+// override is ticked on the confirm form.
+func checkAllocationImport(c echo.Context, projectID int, s allocationSettings, wb *services.AllocationWorkbook, filename string, confirm bool) *allocationImport {
+	imp := &allocationImport{
+		registry: newSerialRegistryGuard(projectID),
+		preview:  &pageshipments.ImportPreview{Filename: filename},
+	}
+	if raw, err := json.Marshal(wb); err == nil {
+		imp.preview.Workbook = string(raw)
+	}
+	var errs, warnings []string
+
+	tmpl, err := database.GetTemplateByID(s.TemplateID)
+	if err != nil || tmpl.ProjectID != projectID {
+		errs = append(errs, "Select a template of this project")
+	} else if imp.products, err = database.GetTemplateProducts(s.TemplateID); err != nil {
+		slog.Error("Error fetching template products", slog.Int("templateID", s.TemplateID), slog.String("error", err.Error()))
+		errs = append(errs, "Failed to load template products")
+	}
+	if s.ChallanDate == "" {
+		errs = append(errs, "Challan date is required")
+	}
+	billFrom, dispatchFrom, billTo, shipTo := loadAllAddresses(projectID)
+	for _, a := range []struct {
+		label     string
+		id        int
+		addresses []*models.Address
+	}{
+		{"bill-from", s.BillFromAddressID, billFrom},
+		{"dispatch-from", s.DispatchFromAddressID, dispatchFrom},
+		{"bill-to", s.BillToAddressID, billTo},
+	} {
+		if !containsAddress(a.addresses, a.id) {
+			errs = append(errs, fmt.Sprintf("Select a %s address", a.label))
+		}
+	}
+	if len(errs) > 0 {
+		imp.preview.Errors = errs
+		return imp
+	}
+
+	shipTo = filterLockedShipToAddresses(projectID, shipTo)
+	catalogue, err := database.GetProductsByProjectID(projectID)
+	if err != nil {
+		slog.Error("Error fetching products", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+	}
+	groups, errs := buildAllocationPlan(wb, imp.products, catalogue, shipTo)
+	imp.groups = groups
+	imp.preview.Products = make([]string, len(imp.products))
+	for i, p := range imp.products {
+		imp.preview.Products[i] = p.ItemName
+	}
+	imp.preview.Groups = allocationPreviewGroups(groups, imp.products, shipTo)
+
+	if len(errs) == 0 {
+		errs, warnings = checkAllocationSerials(projectID, groups, imp.products, imp.registry)
+		warnings = append(warnings, allocationPlanWarnings(projectID, groups, imp.products, shipTo)...)
+	}
+
+	imp.dateGuard = checkChallanDate(c, projectID, s.ChallanDate, "", 0, services.DCTypeTransit, services.DCTypeOfficial)
+	if imp.dateGuard.Refused != "" {
+		if user := auth.GetCurrentUser(c); !confirm && user != nil && user.IsAdmin() {
+			warnings = append(warnings, imp.dateGuard.Refused+" Tick the override to create the shipments anyway.")
+		} else {
+			errs = append(errs, imp.dateGuard.Refused)
+		}
+	}
+
+	imp.preview.Errors = errs
+	imp.preview.Warnings = warnings
+	return imp
+}
+
+// containsAddress reports whether id is one of addresses.
+func containsAddress(addresses []*models.Address, id int) bool {
+	for _, a := range addresses {
+		if a.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// allocationSlot is where the serial sheet puts a product's serials: a
+// template product, or component comp of a kit (-1 for the product itself).
+type allocationSlot struct {
+	line, comp int
+}
+
+// buildAllocationPlan matches an allocation workbook to the template's
+// products and the project's ship-to addresses, and lays it out as shipment
+// groups in sheet order. Kit components are found in catalogue for their
+// codes. Every problem is returned, so the whole workbook can be fixed at once.
+func buildAllocationPlan(wb *services.AllocationWorkbook, products []*models.TemplateProductRow, catalogue []*models.Product, shipTo []*models.Address) ([]*allocationGroup, []string) {
+	var errs []string
+
+	// Product columns match a template product's code, brand/model or name;
+	// names win when they collide.
+	lineLookup := make(map[string]int, len(products)*3)
+	for i, p := range products {
+		lineLookup[strings.ToLower(strings.TrimSpace(p.BrandModel))] = i
+		lineLookup[strings.ToLower(strings.TrimSpace(p.ProductCode))] = i
+	}
+	for i, p := range products {
+		lineLookup[strings.ToLower(strings.TrimSpace(p.ItemName))] = i
+	}
+	delete(lineLookup, "")
+	columns := make([]int, len(wb.Products))
+	seenColumn := make(map[int]string)
+	for j, name := range wb.Products {
+		i, ok := lineLookup[strings.ToLower(strings.TrimSpace(name))]
+		columns[j] = -1
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Sprintf("column %q is not a product of the template", name))
+		case seenColumn[i] != "":
+			errs = append(errs, fmt.Sprintf("columns %q and %q are both %s", seenColumn[i], name, products[i].ItemName))
+		default:
+			seenColumn[i] = name
+			columns[j] = i
+		}
+	}
+
+	byCode := make(map[string][]*models.Address)
+	for _, a := range shipTo {
+		if k := strings.ToLower(strings.TrimSpace(a.AddressCode)); k != "" {
+			byCode[k] = append(byCode[k], a)
+		}
+	}
+
+	type destination struct {
+		group  *allocationGroup
+		addrID int
+	}
+	var groups []*allocationGroup
+	groupByName := make(map[string]*allocationGroup)
+	destByCode := make(map[string]destination)
+	rowOfAddress := make(map[int]int)
+	for _, r := range wb.Rows {
+		name := r.Group
+		if !wb.HasGroups {
+			name = "1"
+		}
+		if name == "" {
+			errs = append(errs, fmt.Sprintf("row %d: no group", r.Row))
+			continue
+		}
+		matches := byCode[strings.ToLower(r.AddressCode)]
+		switch {
+		case r.AddressCode == "":
+			errs = append(errs, fmt.Sprintf("row %d: no address code", r.Row))
+			continue
+		case len(matches) == 0:
+			errs = append(errs, fmt.Sprintf("row %d: no ship-to address with code %s, or it is on an issued shipment", r.Row, r.AddressCode))
+			continue
+		case len(matches) > 1:
+			errs = append(errs, fmt.Sprintf("row %d: address code %s matches %d ship-to addresses", r.Row, r.AddressCode, len(matches)))
+			continue
+		}
+		a := matches[0]
+		if prev, ok := rowOfAddress[a.ID]; ok {
+			errs = append(errs, fmt.Sprintf("row %d: address %s is already allocated on row %d", r.Row, r.AddressCode, prev))
+			continue
+		}
+		rowOfAddress[a.ID] = r.Row
+
+		g := groupByName[name]
+		if g == nil {
+			g = &allocationGroup{Name: name, Quantities: make(map[int]map[int]int)}
+			groupByName[name] = g
+			groups = append(groups, g)
+		}
+		g.ShipToAddressIDs = append(g.ShipToAddressIDs, a.ID)
+		destByCode[strings.ToLower(r.AddressCode)] = destination{g, a.ID}
+		for j, qty := range r.Quantities {
+			if j >= len(columns) || columns[j] < 0 {
+				continue
+			}
+			pid := products[columns[j]].ID
+			if g.Quantities[pid] == nil {
+				g.Quantities[pid] = make(map[int]int)
+			}
+			g.Quantities[pid][a.ID] = qty
+		}
+		if r.Transit {
+			if g.transitRow != 0 {
+				errs = append(errs, fmt.Sprintf("row %d: group %s already has its transit DC on row %d", r.Row, name, g.transitRow))
+			} else {
+				g.TransitShipToAddrID, g.transitRow = a.ID, r.Row
+			}
+		}
+		if v := r.VehicleNumber; v != "" {
+			if g.VehicleNumber != "" && !strings.EqualFold(g.VehicleNumber, v) {
+				errs = append(errs, fmt.Sprintf("row %d: group %s has vehicles %s and %s", r.Row, name, g.VehicleNumber, v))
+			}
+			if g.VehicleNumber == "" {
+				g.VehicleNumber = v
+			}
+		}
+		if t := r.TransporterName; t != "" {
+			if g.TransporterName != "" && !strings.EqualFold(g.TransporterName, t) {
+				errs = append(errs, fmt.Sprintf("row %d: group %s has transporters %s and %s", r.Row, name, g.TransporterName, t))
+			}
+			if g.TransporterName == "" {
+				g.TransporterName = t
+			}
+		}
+	}
+
+	for _, g := range groups {
+		if g.TransitShipToAddrID == 0 {
+			g.TransitShipToAddrID = g.ShipToAddressIDs[0]
+		}
+		var qtyErrs []string
+		for _, msg := range validateQuantities(g.Quantities, products, g.ShipToAddressIDs) {
+			qtyErrs = append(qtyErrs, fmt.Sprintf("group %s: %s", g.Name, msg))
+		}
+		sort.Strings(qtyErrs)
+		errs = append(errs, qtyErrs...)
+
+		g.SerialData = make([]pageshipments.WizardSerialData, len(products))
+		for i, p := range products {
+			g.SerialData[i] = pageshipments.WizardSerialData{ProductID: p.ID, Assignments: make(map[int][]string)}
+			for _, comp := range p.Components {
+				g.SerialData[i].Components = append(g.SerialData[i].Components,
+					pageshipments.WizardSerialData{ProductID: comp.ProductID, Assignments: make(map[int][]string)})
+			}
+		}
+	}
+
+	// The serial sheet names products by code, brand/model or name; a kit's
+	// serials are those of its components.
+	catalogueByID := make(map[int]*models.Product, len(catalogue))
+	for _, p := range catalogue {
+		catalogueByID[p.ID] = p
+	}
+	slotLookup := make(map[string][]allocationSlot)
+	kits := make(map[string]bool)
+	addSlot := func(p *models.Product, slot allocationSlot) {
+		for _, k := range []string{p.ProductCode, p.BrandModel, p.ItemName} {
+			k = strings.ToLower(strings.TrimSpace(k))
+			if k == "" {
+				continue
+			}
+			dup := false
+			for _, s := range slotLookup[k] {
+				dup = dup || s == slot
+			}
+			if !dup {
+				slotLookup[k] = append(slotLookup[k], slot)
+			}
+		}
+	}
+	for i, p := range products {
+		if !p.IsKit() {
+			addSlot(&p.Product, allocationSlot{i, -1})
+			continue
+		}
+		for _, k := range []string{p.ProductCode, p.BrandModel, p.ItemName} {
+			kits[strings.ToLower(strings.TrimSpace(k))] = true
+		}
+		for j, comp := range p.Components {
+			cp := catalogueByID[comp.ProductID]
+			if cp == nil {
+				cp = &models.Product{ItemName: comp.ItemName, BrandModel: comp.BrandModel}
+			}
+			addSlot(cp, allocationSlot{i, j})
+		}
+	}
+	delete(kits, "")
+
+	rowOfSerial := make(map[string]int)
+	for _, sn := range wb.Serials {
+		dest, ok := destByCode[strings.ToLower(sn.AddressCode)]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("serial sheet row %d: address code %s has no allocation row", sn.Row, sn.AddressCode))
+			continue
+		}
+		key := strings.ToLower(sn.ProductCode)
+		slots := slotLookup[key]
+		switch {
+		case len(slots) == 0 && kits[key]:
+			errs = append(errs, fmt.Sprintf("serial sheet row %d: %s is a kit; list the serials of its components", sn.Row, sn.ProductCode))
+			continue
+		case len(slots) == 0:
+			errs = append(errs, fmt.Sprintf("serial sheet row %d: %s is not a product of the template", sn.Row, sn.ProductCode))
+			continue
+		case len(slots) > 1:
+			errs = append(errs, fmt.Sprintf("serial sheet row %d: %s is on more than one line of the template", sn.Row, sn.ProductCode))
+			continue
+		}
+		if prev, ok := rowOfSerial[sn.Serial]; ok {
+			errs = append(errs, fmt.Sprintf("serial sheet row %d: serial %s is already on row %d", sn.Row, sn.Serial, prev))
+			continue
+		}
+		rowOfSerial[sn.Serial] = sn.Row
+
+		data := &dest.group.SerialData[slots[0].line]
+		if slots[0].comp >= 0 {
+			data = &data.Components[slots[0].comp]
+		}
+		data.AllSerials = append(data.AllSerials, sn.Serial)
+		data.Assignments[dest.addrID] = append(data.Assignments[dest.addrID], sn.Serial)
+	}
+
+	codeOf := make(map[int]string, len(shipTo))
+	for _, a := range shipTo {
+		codeOf[a.ID] = a.AddressCode
+	}
+	for _, g := range groups {
+		for _, slot := range wizardSerialSlots(products, g.SerialData) {
+			if len(slot.Data.AllSerials) == 0 {
+				continue
+			}
+			for _, addrID := range g.ShipToAddressIDs {
+				want := g.Quantities[slot.LineID][addrID] * slot.PerUnit
+				if got := len(slot.Data.Assignments[addrID]); got != want {
+					errs = append(errs, fmt.Sprintf("group %s, %s: %s needs %d serial(s), got %d", g.Name, codeOf[addrID], slot.Name, want, got))
+				}
+			}
+			if msg := models.SerialRuleError(slot.Name, slot.Rule, slot.Data.AllSerials); msg != "" {
+				errs = append(errs, fmt.Sprintf("group %s: %s", g.Name, msg))
+			}
+		}
+	}
+	return groups, errs
+}
+
+// checkAllocationSerials checks the serials of every group against the DCs
+// and the serial registry of the project. Registry warnings are returned as
+// well as kept on registry for the flash after the import.
+func checkAllocationSerials(projectID int, groups []*allocationGroup, products []*models.TemplateProductRow, registry *serialRegistryGuard) (errs, warnings []string) {
+	for _, g := range groups {
+		for _, slot := range wizardSerialSlots(products, g.SerialData) {
+			if len(slot.Data.AllSerials) == 0 {
+				continue
+			}
+			conflicts, err := database.CheckSerialsInProject(projectID, slot.Data.AllSerials, nil)
+			if err != nil {
+				slog.Error("Error checking serials", slog.String("error", err.Error()), slog.Int("projectID", projectID))
+			}
+			for _, conflict := range conflicts {
+				errs = append(errs, fmt.Sprintf("group %s: serial %s already exists in DC %s", g.Name, conflict.SerialNumber, conflict.DCNumber))
+			}
+			if msg := registry.check(slot.ProductID, slot.Name, slot.Data.AllSerials); msg != "" {
+				errs = append(errs, fmt.Sprintf("group %s: %s", g.Name, msg))
+			}
+		}
+	}
+	return errs, append(warnings, registry.warnings...)
+}
+
+// allocationPlanWarnings lists the destinations whose imported quantities
+// exceed what the BOQ plan has left for them.
+func allocationPlanWarnings(projectID int, groups []*allocationGroup, products []*models.TemplateProductRow, shipTo []*models.Address) []string {
+	remaining, err := database.GetBOQRemaining(projectID, 0, 0)
+	if err != nil {
+		slog.Error("Error fetching BOQ remaining", slog.Int("project_id", projectID), slog.String("error", err.Error()))
+		return nil
+	}
+	codeOf := make(map[int]string, len(shipTo))
+	for _, a := range shipTo {
+		codeOf[a.ID] = a.AddressCode
+	}
+	var warnings []string
+	for _, g := range groups {
+		for _, addrID := range g.ShipToAddressIDs {
+			for _, p := range products {
+				left, planned := remaining[addrID][p.ID]
+				if qty := g.Quantities[p.ID][addrID]; planned && qty > left {
+					warnings = append(warnings, fmt.Sprintf("group %s, %s: %d %s exceeds the plan's %d left", g.Name, codeOf[addrID], qty, p.ItemName, left))
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// allocationPreviewGroups lays the groups out for the preview, with quantities
+// in template product order.
+func allocationPreviewGroups(groups []*allocationGroup, products []*models.TemplateProductRow, shipTo []*models.Address) []pageshipments.ImportGroup {
+	addressByID := make(map[int]*models.Address, len(shipTo))
+	for _, a := range shipTo {
+		addressByID[a.ID] = a
+	}
+	out := make([]pageshipments.ImportGroup, 0, len(groups))
+	for _, g := range groups {
+		pg := pageshipments.ImportGroup{
+			Name:            g.Name,
+			VehicleNumber:   g.VehicleNumber,
+			TransporterName: g.TransporterName,
+			Totals:          make([]int, len(products)),
+		}
+		if a := addressByID[g.TransitShipToAddrID]; a != nil {
+			pg.Transit = a.DisplayName()
+		}
+		slots := wizardSerialSlots(products, g.SerialData)
+		for _, addrID := range g.ShipToAddressIDs {
+			d := pageshipments.ImportDestination{Quantities: make([]int, len(products))}
+			if a := addressByID[addrID]; a != nil {
+				d.AddressCode, d.Name = a.AddressCode, a.DisplayName()
+			}
+			for i, p := range products {
+				d.Quantities[i] = g.Quantities[p.ID][addrID]
+				pg.Totals[i] += d.Quantities[i]
+			}
+			for _, slot := range slots {
+				d.Serials += len(slot.Data.Assignments[addrID])
+			}
+			pg.Destinations = append(pg.Destinations, d)
+		}
+		out = append(out, pg)
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+func TestBuildAllocationPlan(t *testing.T) {
+	shipTo := []*models.Address{
+		{ID: 1, AddressCode: "ZP-1"},
+		{ID: 2, AddressCode: "ZP-2"},
+		{ID: 3, AddressCode: "ZP-3"},
+		{ID: 4, AddressCode: "ZP-4"},
+		{ID: 5, AddressCode: "ZP-4"},
+	}
+	catalogue := []*models.Product{
+		{ID: 10, ItemName: "Router", ProductCode: "RTR"},
+		{ID: 20, ItemName: "Tablet", ProductCode: "TAB"},
+		{ID: 21, ItemName: "Charger", ProductCode: "CHG"},
+	}
+	products := []*models.TemplateProductRow{
+		{Product: models.Product{ID: 10, ItemName: "Router", ProductCode: "RTR"}},
+		{Product: models.Product{ID: 30, ItemName: "Tablet Kit", ProductCode: "KIT", Components: []models.KitComponent{
+			{ProductID: 20, ItemName: "Tablet", Quantity: 1},
+			{ProductID: 21, ItemName: "Charger", Quantity: 2},
+		}}},
+	}
+
+	t.Run("builds groups with serials", func(t *testing.T) {
+		wb := &services.AllocationWorkbook{
+			HasGroups: true,
+			Products:  []string{"rtr", "Tablet Kit"},
+			Rows: []services.AllocationRow{
+				{Row: 2, Group: "A", AddressCode: "zp-1", VehicleNumber: "AP07", Quantities: map[int]int{0: 2, 1: 1}},
+				{Row: 3, Group: "B", AddressCode: "ZP-3", Quantities: map[int]int{0: 1, 1: 1}},
+				{Row: 4, Group: "A", AddressCode: "ZP-2", Transit: true, Quantities: map[int]int{0: 1}},
+			},
+			Serials: []services.AllocationSerial{
+				{Row: 2, AddressCode: "ZP-1", ProductCode: "RTR", Serial: "R1"},
+				{Row: 2, AddressCode: "ZP-1", ProductCode: "RTR", Serial: "R2"},
+				{Row: 3, AddressCode: "ZP-2", ProductCode: "Router", Serial: "R3"},
+				{Row: 4, AddressCode: "ZP-1", ProductCode: "CHG", Serial: "C1"},
+				{Row: 5, AddressCode: "ZP-1", ProductCode: "CHG", Serial: "C2"},
+			},
+		}
+		groups, errs := buildAllocationPlan(wb, products, catalogue, shipTo)
+		if len(errs) != 0 {
+			t.Fatalf("errs = %q", errs)
+		}
+		if len(groups) != 2 || groups[0].Name != "A" || groups[1].Name != "B" {
+			t.Fatalf("groups = %+v, want A then B", groups)
+		}
+		a := groups[0]
+		if !reflect.DeepEqual(a.ShipToAddressIDs, []int{1, 2}) || a.TransitShipToAddrID != 2 || a.VehicleNumber != "AP07" {
+			t.Errorf("group A = %+v", a)
+		}
+		if want := map[int]map[int]int{10: {1: 2, 2: 1}, 30: {1: 1}}; !reflect.DeepEqual(a.Quantities, want) {
+			t.Errorf("group A quantities = %v, want %v", a.Quantities, want)
+		}
+		if got := a.SerialData[0].Assignments; !reflect.DeepEqual(got, map[int][]string{1: {"R1", "R2"}, 2: {"R3"}}) {
+			t.Errorf("router serials = %v", got)
+		}
+		if got := a.SerialData[1].Components[1].AllSerials; !reflect.DeepEqual(got, []string{"C1", "C2"}) {
+			t.Errorf("charger serials = %v", got)
+		}
+		if b := groups[1]; b.TransitShipToAddrID != 3 {
+			t.Errorf("group B transit = %d, want its first destination", b.TransitShipToAddrID)
+		}
+
+		items := buildShipmentLineItems(products, a.Quantities, a.ShipToAddressIDs, a.SerialData)
+		if len(items) != 2 || items[0].QtyByLocation[1] != 2 || len(items[1].Components) != 2 {
+			t.Errorf("line items = %+v", items)
+		}
+	})
+
+	t.Run("reports every problem", func(t *testing.T) {
+		wb := &services.AllocationWorkbook{
+			HasGroups: true,
+			Products:  []string{"Router", "RTR", "Modem", "KIT"},
+			Rows: []services.AllocationRow{
+				{Row: 2, Group: "A", AddressCode: "ZP-1", Transit: true, Quantities: map[int]int{0: 1, 3: 1}},
+				{Row: 3, Group: "A", AddressCode: "ZP-2", Transit: true, VehicleNumber: "AP07", Quantities: map[int]int{0: 1}},
+				{Row: 4, Group: "A", AddressCode: "ZP-3", VehicleNumber: "AP09", Quantities: map[int]int{0: 1}},
+				{Row: 5, Group: "B", AddressCode: "ZP-1", Quantities: map[int]int{0: 1}},
+				{Row: 6, Group: "B", AddressCode: "ZP-4", Quantities: map[int]int{0: 1}},
+				{Row: 7, Group: "B", AddressCode: "ZP-9", Quantities: map[int]int{0: 1}},
+				{Row: 8, AddressCode: "ZP-9", Quantities: map[int]int{0: 1}},
+			},
+			Serials: []services.AllocationSerial{
+				{Row: 2, AddressCode: "ZP-1", ProductCode: "KIT", Serial: "K1"},
+				{Row: 3, AddressCode: "ZP-8", ProductCode: "RTR", Serial: "R1"},
+				{Row: 4, AddressCode: "ZP-1", ProductCode: "Modem", Serial: "M1"},
+				{Row: 5, AddressCode: "ZP-1", ProductCode: "RTR", Serial: "R1"},
+				{Row: 6, AddressCode: "ZP-2", ProductCode: "RTR", Serial: "R1"},
+			},
+		}
+		_, errs := buildAllocationPlan(wb, products, catalogue, shipTo)
+		want := []string{
+			`columns "Router" and "RTR" are both Router`,
+			`column "Modem" is not a product of the template`,
+			"row 3: group A already has its transit DC on row 2",
+			"row 4: group A has vehicles AP07 and AP09",
+			"row 5: address ZP-1 is already allocated on row 2",
+			"row 6: address code ZP-4 matches 2 ship-to addresses",
+			"row 7: no ship-to address with code ZP-9",
+			"row 8: no group",
+			"serial sheet row 2: KIT is a kit; list the serials of its components",
+			"serial sheet row 3: address code ZP-8 has no allocation row",
+			"serial sheet row 4: Modem is not a product of the template",
+			"serial sheet row 6: serial R1 is already on row 5",
+			"group A, ZP-2: Router needs 1 serial(s), got 0",
+			"group A, ZP-3: Router needs 1 serial(s), got 0",
+		}
+		if len(errs) != len(want) {
+			t.Fatalf("errs = %q, want %d errors", errs, len(want))
+		}
+		for i, w := range want {
+			if !strings.Contains(errs[i], w) {
+				t.Errorf("errs[%d] = %q, want it to contain %q", i, errs[i], w)
+			}
+		}
+	})
+
+	t.Run("checks quantities per group", func(t *testing.T) {
+		wb := &services.AllocationWorkbook{
+			Products: []string{"Router"},
+			Rows:     []services.AllocationRow{{Row: 2, AddressCode: "ZP-1", Quantities: map[int]int{0: 3}}},
+		}
+		groups, errs := buildAllocationPlan(wb, products, catalogue, shipTo)
+		if len(groups) != 1 || groups[0].Name != "1" {
+			t.Fatalf("groups = %+v, want one group named 1", groups)
+		}
+		if len(errs) != 1 || errs[0] != "group 1: Tablet Kit has zero total quantity across all locations" {
+			t.Errorf("errs = %q", errs)
+		}
+	})
+}
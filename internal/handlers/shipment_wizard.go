@@ -58,6 +58,7 @@ func ShowCreateShipmentWizard(c echo.Context) error {
 		flashMessage,
 		csrf.Token(c.Request()),
 		0,
+		0,
 		nil,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
@@ -169,6 +170,7 @@ func ShipmentWizardStep2(c echo.Context) error {
 		shipToAddresses,
 		csrf.Token(c.Request()),
 		0,
+		0,
 		nil,
 		0, 0, 0,
 	)
@@ -278,6 +280,7 @@ func ShipmentWizardQuantityStep(c echo.Context) error {
 			shipToAddresses,
 			csrf.Token(c.Request()),
 			0,
+			0,
 			nil,
 			0, 0, 0,
 		)
@@ -294,23 +297,7 @@ func ShipmentWizardQuantityStep(c echo.Context) error {
 	}
 
 	// Load ship-to address details for quantity grid column headers
-	shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
-	var quantityAddresses []pageshipments.QuantityAddress
-	if shipToConfig != nil {
-		allShipTo, _ := database.GetAllAddressesByConfigID(shipToConfig.ID)
-		selectedSet := make(map[int]bool)
-		for _, id := range shipToAddressIDs {
-			selectedSet[id] = true
-		}
-		for _, a := range allShipTo {
-			if selectedSet[a.ID] {
-				quantityAddresses = append(quantityAddresses, pageshipments.QuantityAddress{
-					ID:   a.ID,
-					Name: a.DisplayName(),
-				})
-			}
-		}
-	}
+	quantityAddresses := loadQuantityAddresses(projectID, shipToAddressIDs, 0)
 
 	allProjects, _ := database.GetAccessibleProjects(user)
 
@@ -336,6 +323,7 @@ func ShipmentWizardQuantityStep(c echo.Context) error {
 		shipToAddressIDs,
 		csrf.Token(c.Request()),
 		0,
+		0,
 		"", "",
 		nil, nil,
 	)
@@ -382,23 +370,7 @@ func ShipmentWizardStep4(c echo.Context) error {
 		}
 
 		// Re-render quantity grid with errors
-		shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
-		var quantityAddresses []pageshipments.QuantityAddress
-		if shipToConfig != nil {
-			allShipTo, _ := database.GetAllAddressesByConfigID(shipToConfig.ID)
-			selectedSet := make(map[int]bool)
-			for _, id := range shipToAddressIDs {
-				selectedSet[id] = true
-			}
-			for _, a := range allShipTo {
-				if selectedSet[a.ID] {
-					quantityAddresses = append(quantityAddresses, pageshipments.QuantityAddress{
-						ID:   a.ID,
-						Name: a.DisplayName(),
-					})
-				}
-			}
-		}
+		quantityAddresses := loadQuantityAddresses(projectID, shipToAddressIDs, 0)
 
 		allProjects, _ := database.GetAccessibleProjects(user)
 		pageContent := pageshipments.WizardStep3Quantities(
@@ -407,7 +379,7 @@ func ShipmentWizardStep4(c echo.Context) error {
 			transporterName, vehicleNumber, ewayBillNumber, docketNumber,
 			taxType, reverseCharge,
 			billFromAddressID, dispatchFromAddressID, billToAddressID, transitShipToAddrID,
-			shipToAddressIDs, csrf.Token(c.Request()), 0,
+			shipToAddressIDs, csrf.Token(c.Request()), 0, 0,
 			globalErr, "error",
 			quantities, qtyErrors,
 		)
@@ -477,6 +449,7 @@ func ShipmentWizardStep4(c echo.Context) error {
 		shipToAddresses,
 		csrf.Token(c.Request()),
 		0,
+		0,
 		nil, nil, nil,
 		quantityHiddenFields,
 		computeProductQuantityTotals(quantities),
@@ -517,16 +490,22 @@ func ShipmentWizardStep5(c echo.Context) error {
 	// Parse quantities for validation
 	quantities := parseQuantityForm(c, products, shipToAddressIDs)
 
-	// Validate serial counts and collect per-product errors
-	serialErrors := make(map[int]string)
-	for i, pd := range serialData {
+	// Validate serial counts and collect per-slot errors
+	serialErrors := make(map[string]string)
+	registry := newSerialRegistryGuard(projectID)
+	for _, slot := range wizardSerialSlots(products, serialData) {
+		pd := slot.Data
+		if pd.InputError != "" {
+			serialErrors[slot.Key] = pd.InputError
+			continue
+		}
 		// Sum quantities across all locations for this product
 		expectedTotal := 0
 		for _, addrID := range shipToAddressIDs {
-			expectedTotal += quantities[products[i].ID][addrID]
+			expectedTotal += quantities[slot.LineID][addrID] * slot.PerUnit
 		}
 		if len(pd.AllSerials) > 0 && len(pd.AllSerials) != expectedTotal {
-			serialErrors[pd.ProductID] = fmt.Sprintf("Expected %d serials, got %d", expectedTotal, len(pd.AllSerials))
+			serialErrors[slot.Key] = fmt.Sprintf("Expected %d serials, got %d", expectedTotal, len(pd.AllSerials))
 			continue
 		}
 
@@ -534,7 +513,7 @@ func ShipmentWizardStep5(c echo.Context) error {
 		seen := make(map[string]bool)
 		for _, sn := range pd.AllSerials {
 			if seen[sn] {
-				serialErrors[pd.ProductID] = fmt.Sprintf("Duplicate serial within this product: %s", sn)
+				serialErrors[slot.Key] = fmt.Sprintf("Duplicate serial within this product: %s", sn)
 				break
 			}
 			seen[sn] = true
@@ -542,20 +521,38 @@ func ShipmentWizardStep5(c echo.Context) error {
 
 		// Check assignment counts don't exceed per-location quantity
 		for shipToID, assigned := range pd.Assignments {
-			locationQty := quantities[products[i].ID][shipToID]
+			locationQty := quantities[slot.LineID][shipToID] * slot.PerUnit
 			if len(assigned) > locationQty {
-				serialErrors[pd.ProductID] = fmt.Sprintf("Too many serials assigned to a destination (max %d)", locationQty)
+				serialErrors[slot.Key] = fmt.Sprintf("Too many serials assigned to a destination (max %d)", locationQty)
 			}
 		}
 
 		// Project-wide duplicate check
-		if _, alreadyHasError := serialErrors[pd.ProductID]; !alreadyHasError && len(pd.AllSerials) > 0 {
+		if _, alreadyHasError := serialErrors[slot.Key]; !alreadyHasError && len(pd.AllSerials) > 0 {
 			conflicts, conflictsErr := database.CheckSerialsInProject(projectID, pd.AllSerials, nil)
 			if conflictsErr != nil {
 				slog.Error("Error checking serials", slog.String("error", conflictsErr.Error()), slog.Int("projectID", projectID))
 			}
 			if len(conflicts) > 0 {
-				serialErrors[pd.ProductID] = fmt.Sprintf("Serial %s already exists in DC %s", conflicts[0].SerialNumber, conflicts[0].DCNumber)
+				serialErrors[slot.Key] = fmt.Sprintf("Serial %s already exists in DC %s", conflicts[0].SerialNumber, conflicts[0].DCNumber)
+			}
+		}
+
+		// Serial format rule check
+		if _, alreadyHasError := serialErrors[slot.Key]; !alreadyHasError {
+			if msg := models.SerialRuleError(slot.Name, slot.Rule, pd.AllSerials); msg != "" {
+				serialErrors[slot.Key] = msg
+			}
+		}
+
+		// Serial registry check
+		if _, alreadyHasError := serialErrors[slot.Key]; !alreadyHasError {
+			msg := models.SerialRuleError(slot.Name, slot.Rule, pd.AllSerials)
+			if msg == "" {
+				msg = registry.check(slot.ProductID, slot.Name, pd.AllSerials)
+			}
+			if msg != "" {
+				serialErrors[slot.Key] = msg
 			}
 		}
 	}
@@ -577,15 +574,7 @@ func ShipmentWizardStep5(c echo.Context) error {
 
 	// If any errors, re-render step 4 (serials) with pre-filled data and inline errors
 	if len(serialErrors) > 0 {
-		prefillSerials := make(map[int][]string)
-		prefillAssignments := make(map[string][]string)
-		for _, pd := range serialData {
-			prefillSerials[pd.ProductID] = pd.AllSerials
-			for shipToID, assigned := range pd.Assignments {
-				key := fmt.Sprintf("%d_%d", pd.ProductID, shipToID)
-				prefillAssignments[key] = assigned
-			}
-		}
+		prefillSerials, prefillAssignments := pageshipments.WizardSerialPrefill(serialData)
 		shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
 		var shipToAddresses []*models.Address
 		if shipToConfig != nil {
@@ -613,7 +602,7 @@ func ShipmentWizardStep5(c echo.Context) error {
 			strconv.Itoa(billFromAddressID), strconv.Itoa(dispatchFromAddressID),
 			strconv.Itoa(billToAddressID), strconv.Itoa(transitShipToAddrID),
 			shipToIDStrings, shipToAddresses,
-			csrf.Token(c.Request()), 0,
+			csrf.Token(c.Request()), 0, 0,
 			prefillSerials, prefillAssignments, serialErrors,
 			quantityHiddenFields,
 			computeProductQuantityTotals(quantities),
@@ -674,7 +663,9 @@ func ShipmentWizardStep5(c echo.Context) error {
 		serialData,
 		csrf.Token(c.Request()),
 		0,
+		0,
 		quantityHiddenFields,
+		wizardTaxTypeCheck(project, taxType, billFromAddressID, billToAddressID, transitShipToAddrID),
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -743,85 +734,55 @@ func CreateShipment(c echo.Context) error {
 	// Parse per-product per-location quantities from the quantity grid
 	quantities := parseQuantityForm(c, products, shipToAddressIDs)
 
-	// Build line items with serials and per-location quantities
-	var lineItems []services.ShipmentLineItem
-	for _, p := range products {
-		// Build per-location quantity map from the quantity grid
-		qtyByLoc := make(map[int]int)
-		for _, addrID := range shipToAddressIDs {
-			qtyByLoc[addrID] = quantities[p.ID][addrID]
-		}
-		item := services.ShipmentLineItem{
-			ProductID:     p.ID,
-			QtyByLocation: qtyByLoc,
-			Rate:          p.PerUnitPrice,
-			TaxPercentage: p.GSTPercentage,
-			Assignments:   make(map[int][]string),
-		}
-
-		// Parse all serials
-		serialsRaw := c.FormValue(fmt.Sprintf("serials_%d", p.ID))
-		if serialsRaw != "" {
-			for _, sn := range strings.Split(serialsRaw, "\n") {
-				sn = strings.TrimSpace(sn)
-				if sn != "" {
-					item.AllSerials = append(item.AllSerials, sn)
-				}
-			}
-		}
-
-		// Parse serial assignments
-		for _, shipToID := range shipToAddressIDs {
-			assignRaw := c.FormValue(fmt.Sprintf("assign_%d_%d", p.ID, shipToID))
-			if assignRaw != "" {
-				for _, sn := range strings.Split(assignRaw, "\n") {
-					sn = strings.TrimSpace(sn)
-					if sn != "" {
-						item.Assignments[shipToID] = append(item.Assignments[shipToID], sn)
-					}
-				}
-			}
+	// Parse serials per product, and per component for kits
+	serialData := parseStep4Form(c, products, shipToAddressIDs)
+	slots := wizardSerialSlots(products, serialData)
+	for _, slot := range slots {
+		if slot.Data.InputError != "" {
+			auth.SetFlash(c.Request(), "error", fmt.Sprintf("Product %s: %s", slot.Name, slot.Data.InputError))
+			return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/new", projectID))
 		}
-
-		lineItems = append(lineItems, item)
 	}
 
+	// Build line items with serials and per-location quantities
+	lineItems := buildShipmentLineItems(products, quantities, shipToAddressIDs, serialData)
+
 	// Validate serial counts using actual quantities from the grid
-	for i, item := range lineItems {
+	for _, slot := range slots {
 		// Sum quantities across all locations for this product
 		expectedTotal := 0
 		for _, addrID := range shipToAddressIDs {
-			expectedTotal += quantities[products[i].ID][addrID]
+			expectedTotal += quantities[slot.LineID][addrID] * slot.PerUnit
 		}
-		if len(item.AllSerials) > 0 && len(item.AllSerials) != expectedTotal {
-			auth.SetFlash(c.Request(), "error", fmt.Sprintf("Product %s: expected %d serials, got %d", products[i].ItemName, expectedTotal, len(item.AllSerials)))
+		if len(slot.Data.AllSerials) > 0 && len(slot.Data.AllSerials) != expectedTotal {
+			auth.SetFlash(c.Request(), "error", fmt.Sprintf("Product %s: expected %d serials, got %d", slot.Name, expectedTotal, len(slot.Data.AllSerials)))
 			return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/new", projectID))
 		}
 
 		// Check duplicates within same product
 		seen := make(map[string]bool)
-		for _, sn := range item.AllSerials {
+		for _, sn := range slot.Data.AllSerials {
 			if seen[sn] {
-				auth.SetFlash(c.Request(), "error", fmt.Sprintf("Duplicate serial %s for product %s", sn, products[i].ItemName))
+				auth.SetFlash(c.Request(), "error", fmt.Sprintf("Duplicate serial %s for product %s", sn, slot.Name))
 				return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/new", projectID))
 			}
 			seen[sn] = true
 		}
 
 		// Check assignments don't exceed per-location quantity
-		for shipToID, assigned := range item.Assignments {
-			locationQty := quantities[products[i].ID][shipToID]
+		for shipToID, assigned := range slot.Data.Assignments {
+			locationQty := quantities[slot.LineID][shipToID] * slot.PerUnit
 			if len(assigned) > locationQty {
-				auth.SetFlash(c.Request(), "error", fmt.Sprintf("Too many serials assigned for product %s to destination %d", products[i].ItemName, shipToID))
+				auth.SetFlash(c.Request(), "error", fmt.Sprintf("Too many serials assigned for product %s to destination %d", slot.Name, shipToID))
 				return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/new", projectID))
 			}
 		}
 	}
 
 	// Check for duplicate serials in project
-	for _, item := range lineItems {
-		if len(item.AllSerials) > 0 {
-			conflicts, conflictsErr := database.CheckSerialsInProject(projectID, item.AllSerials, nil)
+	for _, slot := range slots {
+		if len(slot.Data.AllSerials) > 0 {
+			conflicts, conflictsErr := database.CheckSerialsInProject(projectID, slot.Data.AllSerials, nil)
 			if conflictsErr != nil {
 				slog.Error("Error checking serials", slog.String("error", conflictsErr.Error()), slog.Int("projectID", projectID))
 			}
@@ -832,6 +793,25 @@ func CreateShipment(c echo.Context) error {
 		}
 	}
 
+	// Check serials against the serial registry
+	registry := newSerialRegistryGuard(projectID)
+	for _, slot := range slots {
+		msg := models.SerialRuleError(slot.Name, slot.Rule, slot.Data.AllSerials)
+		if msg == "" {
+			msg = registry.check(slot.ProductID, slot.Name, slot.Data.AllSerials)
+		}
+		if msg != "" {
+			auth.SetFlash(c.Request(), "error", msg)
+			return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/new", projectID))
+		}
+	}
+
+	dateGuard := checkChallanDate(c, projectID, challanDate, "", 0, services.DCTypeTransit, services.DCTypeOfficial)
+	if dateGuard.Refused != "" {
+		auth.SetFlash(c.Request(), "error", dateGuard.Refused)
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/new", projectID))
+	}
+
 	params := services.ShipmentParams{
 		ProjectID:             projectID,
 		TemplateID:            templateID,
@@ -859,7 +839,12 @@ func CreateShipment(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/new", projectID))
 	}
 
-	auth.SetFlash(c.Request(), "success", fmt.Sprintf("Shipment created successfully with %d DCs", 1+len(result.OfficialDCs)))
+	dateGuard.audit(c, projectID, models.AuditEntityShipmentGroup, result.GroupID)
+	recordAudit(c, projectID, models.AuditEntityShipmentGroup, result.GroupID, models.AuditActionCreate,
+		fmt.Sprintf("Created shipment with transit DC %s and %d official DC(s)", result.TransitDC.DCNumber, len(result.OfficialDCs)),
+		nil, map[string]interface{}{"template_id": templateID, "challan_date": challanDate, "ship_to_address_ids": shipToAddressIDs})
+
+	registry.flash(c, fmt.Sprintf("Shipment created successfully with %d DCs", 1+len(result.OfficialDCs)))
 	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/shipments/%d", projectID, result.GroupID))
 }
 
@@ -911,6 +896,7 @@ func ShowShipmentGroup(c echo.Context) error {
 		allProjects,
 		group,
 		dcs,
+		loadApprovalState(models.ApprovalTarget{ProjectID: projectID, ShipmentGroupID: groupID}, group.Status, user.ID),
 		flashType,
 		flashMessage,
 		csrf.Token(c.Request()),
@@ -980,37 +966,106 @@ func IssueShipmentGroup(c echo.Context) error {
 		return c.JSON(http.StatusNotFound, map[string]interface{}{"error": "Shipment group not found"})
 	}
 
-	if group.Status == "issued" {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": "Shipment group is already issued"})
+	if msg := groupIssueRefusal(group.Status); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
 	}
 
-	// Validate serial numbers on transit DCs before issuing the group
-	groupDCs, err := database.GetDCsByShipmentGroup(groupID)
+	subject := &approvalSubject{
+		Target:     models.ApprovalTarget{ProjectID: projectID, ShipmentGroupID: groupID},
+		EntityType: models.AuditEntityShipmentGroup,
+		EntityID:   groupID,
+		Label:      fmt.Sprintf("shipment group #%d", groupID),
+		Status:     group.Status,
+	}
+	msg, err := approvalGate(subject.Target, group.Status, user.ID)
+	if err != nil {
+		slog.Error("Error checking approval", slog.String("error", err.Error()), slog.Int("groupID", groupID))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to check approval"})
+	}
+	if msg != "" {
+		return c.JSON(http.StatusForbidden, map[string]interface{}{"error": msg})
+	}
+	comments, msg := parseApprovalComments(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
+
+	msg, err = validateGroupIssue(projectID, groupID)
 	if err != nil {
 		slog.Error("Error fetching DCs in group", slog.String("error", err.Error()), slog.Int("groupID", groupID))
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to fetch DCs in group"})
 	}
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
+
+	count, err := database.IssueAllDCsInGroup(groupID, user.ID)
+	if err != nil {
+		slog.Error("Error issuing DCs in group", slog.String("error", err.Error()), slog.Int("groupID", groupID))
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to issue DCs"})
+	}
+
+	// Update group status
+	if err := database.UpdateShipmentGroupStatus(groupID, "issued"); err != nil {
+		slog.Error("Error updating group status", slog.String("error", err.Error()), slog.Int("groupID", groupID))
+	}
+
+	recordAudit(c, projectID, models.AuditEntityShipmentGroup, groupID, models.AuditActionIssue,
+		fmt.Sprintf("Issued %d DC(s) in shipment group #%d", count, groupID),
+		map[string]interface{}{"status": group.Status}, map[string]interface{}{"status": "issued"})
+	recordApproval(c, subject, user.ID, comments)
+
+	auth.SetFlash(c.Request(), "success", fmt.Sprintf("Successfully issued %d DCs", count))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"message":  fmt.Sprintf("Successfully issued %d DCs", count),
+		"redirect": fmt.Sprintf("/projects/%d/shipments/%d", projectID, groupID),
+	})
+}
+
+// groupIssueRefusal returns why a shipment group in this status cannot be
+// issued, or "" when it is a draft or awaiting approval.
+func groupIssueRefusal(status string) string {
+	switch {
+	case models.IsIssuableStatus(status):
+		return ""
+	case status == models.DCStatusIssued:
+		return "Shipment group is already issued"
+	case status == models.DCStatusCancelled:
+		return "Shipment group is cancelled and cannot be issued"
+	case status == models.DCStatusRejected:
+		return "This request was rejected; reopen or resubmit it first"
+	}
+	return fmt.Sprintf("Shipment group is %s and cannot be issued", strings.ReplaceAll(status, "_", " "))
+}
+
+// validateGroupIssue checks that a shipment group is ready to issue: no DC falls
+// in a closed financial year, every Transit DC has serial numbers matching its quantities, and no ship-to address is already
+// used by another issued group. It returns a user-facing message when it is not.
+func validateGroupIssue(projectID, groupID int) (string, error) {
+	// Validate serial numbers on transit DCs before issuing the group
+	groupDCs, err := database.GetDCsByShipmentGroup(groupID)
+	if err != nil {
+		return "", err
+	}
 	for _, dc := range groupDCs {
+		if msg := closedYearMessage(dc); msg != "" {
+			return fmt.Sprintf("DC %s: %s", dc.DCNumber, msg), nil
+		}
 		if dc.DCType != "transit" {
 			continue
 		}
 		lineItems, liErr := database.GetLineItemsByDCID(dc.ID)
 		if liErr != nil || len(lineItems) == 0 {
-			return c.JSON(http.StatusBadRequest, map[string]interface{}{
-				"error": fmt.Sprintf("Transit DC %s must have at least one line item", dc.DCNumber),
-			})
+			return fmt.Sprintf("Transit DC %s must have at least one line item", dc.DCNumber), nil
 		}
 		for _, li := range lineItems { //nolint:gocritic
 			serials, _ := database.GetSerialNumbersByLineItemID(li.ID)
 			if len(serials) == 0 {
-				return c.JSON(http.StatusBadRequest, map[string]interface{}{
-					"error": fmt.Sprintf("Transit DC %s: all line items must have serial numbers before issuing", dc.DCNumber),
-				})
+				return fmt.Sprintf("Transit DC %s: all line items must have serial numbers before issuing", dc.DCNumber), nil
 			}
-			if len(serials) != li.Quantity {
-				return c.JSON(http.StatusBadRequest, map[string]interface{}{
-					"error": fmt.Sprintf("Transit DC %s: serial number count must match quantity for all line items", dc.DCNumber),
-				})
+			if len(serials) != li.SerialsRequired() {
+				return fmt.Sprintf("Transit DC %s: serial number count must match quantity for all line items", dc.DCNumber), nil
 			}
 		}
 	}
@@ -1030,29 +1085,11 @@ func IssueShipmentGroup(c echo.Context) error {
 			}
 		}
 		if len(conflicts) > 0 {
-			return c.JSON(http.StatusBadRequest, map[string]interface{}{
-				"error": fmt.Sprintf("Cannot issue: %d ship-to address(es) already used in other issued groups", len(conflicts)),
-			})
+			return fmt.Sprintf("Cannot issue: %d ship-to address(es) already used in other issued groups", len(conflicts)), nil
 		}
 	}
 
-	count, err := database.IssueAllDCsInGroup(groupID, user.ID)
-	if err != nil {
-		slog.Error("Error issuing DCs in group", slog.String("error", err.Error()), slog.Int("groupID", groupID))
-		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to issue DCs"})
-	}
-
-	// Update group status
-	if err := database.UpdateShipmentGroupStatus(groupID, "issued"); err != nil {
-		slog.Error("Error updating group status", slog.String("error", err.Error()), slog.Int("groupID", groupID))
-	}
-
-	auth.SetFlash(c.Request(), "success", fmt.Sprintf("Successfully issued %d DCs", count))
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"success":  true,
-		"message":  fmt.Sprintf("Successfully issued %d DCs", count),
-		"redirect": fmt.Sprintf("/projects/%d/shipments/%d", projectID, groupID),
-	})
+	return "", nil
 }
 
 // filterLockedShipToAddresses removes ship-to addresses that are used in issued shipment groups.
@@ -1100,6 +1137,9 @@ func DeleteShipmentGroupHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to delete shipment group"})
 	}
 
+	recordAudit(c, projectID, models.AuditEntityShipmentGroup, groupID, models.AuditActionDelete,
+		fmt.Sprintf("Deleted draft shipment group #%d and its DCs", groupID), group, nil)
+
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"success":  true,
 		"message":  "Shipment group and all associated DCs deleted successfully",
@@ -16,6 +16,7 @@ import (
 	"github.com/narendhupati/dc-management-tool/internal/auth"
 	"github.com/narendhupati/dc-management-tool/internal/components"
 	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/helpers"
 	"github.com/narendhupati/dc-management-tool/internal/models"
 	"github.com/narendhupati/dc-management-tool/internal/services"
 )
@@ -74,22 +75,14 @@ func validateSplitTransporter(transporterName string) error {
 	return nil
 }
 
-// parseSplitSerials parses newline-separated serial numbers, trimming whitespace and skipping empty lines.
-func parseSplitSerials(raw string) []string {
-	if strings.TrimSpace(raw) == "" {
-		return nil
-	}
-	var result []string
-	for _, line := range strings.Split(raw, "\n") {
-		sn := strings.TrimSpace(line)
-		if sn != "" {
-			result = append(result, sn)
-		}
-	}
+// parseSplitSerials parses pasted serial numbers, expanding ranges (see helpers.ParseSerialInput).
+// It returns nil when there are none.
+func parseSplitSerials(raw string) ([]string, error) {
+	result, err := helpers.ParseSerialInput(raw)
 	if len(result) == 0 {
-		return nil
+		return nil, err
 	}
-	return result
+	return result, err
 }
 
 // ─── Form parsers ───────────────────────────────────────────────────────────
@@ -404,10 +397,16 @@ func SplitWizardStep4(c echo.Context) error {
 	// Parse serials per product
 	productSerials := make(map[int][]string)
 	serialErrors := make(map[int]string)
+	registry := newSerialRegistryGuard(project.ID)
+	rules := splitSerialRules(project.ID)
 	for _, p := range products {
 		raw := c.FormValue(fmt.Sprintf("serials_%d", p.ID))
-		serials := parseSplitSerials(raw)
+		serials, parseErr := parseSplitSerials(raw)
 		productSerials[p.ID] = serials
+		if parseErr != nil {
+			serialErrors[p.ID] = parseErr.Error()
+			continue
+		}
 
 		// Validate serial count
 		expected := expectedQty[p.ID]
@@ -425,6 +424,20 @@ func SplitWizardStep4(c echo.Context) error {
 			}
 			seen[sn] = true
 		}
+
+		// Serial format rule check
+		if _, alreadyHasError := serialErrors[p.ID]; !alreadyHasError {
+			if msg := models.SerialRuleError(p.Name, rules[p.ID], serials); msg != "" {
+				serialErrors[p.ID] = msg
+			}
+		}
+
+		// Serial registry check
+		if _, alreadyHasError := serialErrors[p.ID]; !alreadyHasError {
+			if msg := registry.check(p.ID, p.Name, serials); msg != "" {
+				serialErrors[p.ID] = msg
+			}
+		}
 	}
 
 	// If serial errors, re-render step 3
@@ -485,11 +498,20 @@ func CreateSplitShipmentHandler(c echo.Context) error {
 	}
 	products := buildSplitProducts(unsplitDests)
 
-	// Parse serials
+	// Parse serials and check them against the serial registry
 	var productSerials []services.SplitProductSerials
+	registry := newSerialRegistryGuard(project.ID)
 	for _, p := range products {
 		raw := c.FormValue(fmt.Sprintf("serials_%d", p.ID))
-		serials := parseSplitSerials(raw)
+		serials, parseErr := parseSplitSerials(raw)
+		if parseErr != nil {
+			auth.SetFlash(c.Request(), "error", fmt.Sprintf("%s: %v", p.Name, parseErr))
+			return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs/%d/split", project.ID, tdc.ID))
+		}
+		if msg := registry.check(p.ID, p.Name, serials); msg != "" {
+			auth.SetFlash(c.Request(), "error", msg)
+			return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs/%d/split", project.ID, tdc.ID))
+		}
 		productSerials = append(productSerials, services.SplitProductSerials{
 			ProductID:     p.ID,
 			SerialNumbers: serials,
@@ -510,14 +532,24 @@ func CreateSplitShipmentHandler(c echo.Context) error {
 		CreatedBy:       user.ID,
 	}
 
-	_, err = services.CreateSplitShipment(database.DB, params)
+	// Child DCs take the Transfer DC's date, so only its financial year is checked.
+	if guard := checkChallanDate(c, project.ID, challanDay(tdc.ChallanDate), "", 0); guard.Refused != "" {
+		auth.SetFlash(c.Request(), "error", guard.Refused)
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs/%d/split", project.ID, tdc.ID))
+	}
+
+	result, err := services.CreateSplitShipment(database.DB, params)
 	if err != nil {
 		slog.Error("Error creating split shipment", slog.String("error", err.Error()), slog.Int("transferDCID", tdc.ID))
 		auth.SetFlash(c.Request(), "error", fmt.Sprintf("Failed to create split: %v", err))
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs/%d/split", project.ID, tdc.ID))
 	}
 
-	auth.SetFlash(c.Request(), "success", "Split created successfully")
+	recordAudit(c, project.ID, models.AuditEntityTransferDC, tdc.DCID, models.AuditActionSplit,
+		fmt.Sprintf("Split %d destination(s) of Transfer DC %s into shipment group #%d", len(destIDInts), tdc.DCNumber, result.GroupID),
+		nil, map[string]interface{}{"split_id": result.SplitID, "shipment_group_id": result.GroupID, "destination_ids": destIDInts})
+
+	registry.flash(c, "Split created successfully")
 	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/dcs/%d", project.ID, tdc.DCID))
 }
 
@@ -598,7 +630,7 @@ func SplitWizardBackToStep3(c echo.Context) error {
 	prefillSerials := make(map[int][]string)
 	for _, p := range products {
 		raw := c.FormValue(fmt.Sprintf("serials_%d", p.ID))
-		serials := parseSplitSerials(raw)
+		serials, _ := parseSplitSerials(raw)
 		if len(serials) > 0 {
 			prefillSerials[p.ID] = serials
 		}
@@ -610,3 +642,18 @@ func SplitWizardBackToStep3(c echo.Context) error {
 	topbar := partials.Topbar(user, project, allProjects, "", "")
 	return components.RenderOK(c, layouts.MainWithContent("Split Wizard", sidebar, topbar, "", "", pageContent))
 }
+
+// splitSerialRules returns the serial format rule of each product in the
+// project, keyed by product ID.
+func splitSerialRules(projectID int) map[int]models.SerialRule {
+	rules := make(map[int]models.SerialRule)
+	products, err := database.GetProductsByProjectID(projectID)
+	if err != nil {
+		slog.Error("Error loading product serial rules", slog.Int("projectID", projectID), slog.String("error", err.Error()))
+		return rules
+	}
+	for _, p := range products {
+		rules[p.ID] = p.SerialRule
+	}
+	return rules
+}
@@ -189,6 +189,11 @@ func TestParseSplitSerials(t *testing.T) {
 			raw:      "SN001\n\nSN002\n\n\nSN003\n",
 			expected: []string{"SN001", "SN002", "SN003"},
 		},
+		{
+			name:     "expands ranges",
+			raw:      "SN001-SN003\nX9",
+			expected: []string{"SN001", "SN002", "SN003", "X9"},
+		},
 		{
 			name:     "empty string returns nil",
 			raw:      "",
@@ -203,7 +208,10 @@ func TestParseSplitSerials(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parseSplitSerials(tt.raw)
+			got, err := parseSplitSerials(tt.raw)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
 			if len(got) != len(tt.expected) {
 				t.Fatalf("length: want %d, got %d", len(tt.expected), len(got))
 			}
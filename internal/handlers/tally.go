@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/narendhupati/dc-management-tool/components/layouts"
+	deliverychallan "github.com/narendhupati/dc-management-tool/components/pages/delivery_challans"
+	"github.com/narendhupati/dc-management-tool/components/partials"
+	"github.com/narendhupati/dc-management-tool/internal/auth"
+	"github.com/narendhupati/dc-management-tool/internal/components"
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+	"github.com/narendhupati/dc-management-tool/internal/services"
+)
+
+// tallyPageSize is how many DCs are read per page when exporting by filter.
+const tallyPageSize = 200
+
+// buildDCTallyVoucher maps an issued DC onto a Tally Delivery Note from the
+// master data it was issued with.
+func buildDCTallyVoucher(dc *models.DeliveryChallan) (*services.TallyVoucher, []string, error) {
+	snap, err := database.GetDCPrintSnapshot(dc)
+	if err != nil {
+		return nil, nil, err
+	}
+	lineItems, err := database.GetLineItemsByDCID(dc.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	snap.ApplyProducts(lineItems)
+
+	in := &services.TallyVoucherInput{
+		DC:        dc,
+		Project:   snap.Project,
+		Company:   snap.Company,
+		BillFrom:  snap.Address(models.SnapshotRoleBillFrom),
+		BillTo:    snap.Address(models.SnapshotRoleBillTo),
+		ShipTo:    snap.Address(models.SnapshotRoleShipTo),
+		LineItems: lineItems,
+	}
+
+	switch dc.DCType {
+	case "transfer":
+		tdc, err := database.GetTransferDCByDCID(dc.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if hub := snap.Address(models.SnapshotRoleHub); hub != nil {
+			in.ShipTo = hub
+		}
+		in.TaxType = tdc.TaxType
+		in.TransporterName = tdc.TransporterName
+		in.VehicleNumber = tdc.VehicleNumber
+	case "transit":
+		td, err := database.GetTransitDetailsByDCID(dc.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if td != nil {
+			in.TransporterName = td.TransporterName
+			in.VehicleNumber = td.VehicleNumber
+		}
+		in.TaxType = transitTaxType(dc)
+	}
+
+	voucher, problems := services.BuildTallyVoucher(in)
+	return voucher, problems, nil
+}
+
+// ExportTallyXML handles GET /projects/:id/dcs-list/tally.
+// It downloads a Tally XML import file of Delivery Note vouchers for the DCs
+// ticked on the DC listing or, when none are ticked, for every issued transit
+// and transfer DC that matches the listing's filters within a date range.
+// Nothing is downloaded unless every DC maps onto a voucher.
+func ExportTallyXML(c echo.Context) error {
+	project := c.Get("currentProject").(*models.Project)
+	listURL := fmt.Sprintf("/projects/%d/dcs-list", project.ID)
+
+	dcs, err := tallyExportDCs(c, project)
+	if err != nil {
+		slog.Error("Failed to list DCs for Tally export", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to load the DCs to export")
+		return c.Redirect(http.StatusFound, listURL)
+	}
+	if dcs == nil {
+		auth.SetFlash(c.Request(), "error", "Select the DCs to export to Tally, or filter the list by a date range")
+		return c.Redirect(http.StatusFound, listURL)
+	}
+
+	var vouchers []*services.TallyVoucher
+	var failed []*models.TallyCheck
+	for _, dc := range dcs {
+		exclusion, err := tallyExclusion(dc)
+		if err != nil {
+			slog.Error("Failed to check DC for Tally export", slog.Int("dc_id", dc.ID), slog.String("error", err.Error()))
+			exclusion = "The DC's data could not be loaded"
+		}
+		if exclusion != "" {
+			failed = append(failed, &models.TallyCheck{DCID: dc.ID, DCNumber: dc.DCNumber, Problems: []string{exclusion}})
+			continue
+		}
+		voucher, problems, err := buildDCTallyVoucher(dc)
+		if err != nil {
+			slog.Error("Failed to build Tally voucher", slog.Int("dc_id", dc.ID), slog.String("error", err.Error()))
+			problems = []string{"The DC's data could not be loaded"}
+		}
+		if len(problems) > 0 {
+			failed = append(failed, &models.TallyCheck{DCID: dc.ID, DCNumber: dc.DCNumber, Problems: problems})
+			continue
+		}
+		vouchers = append(vouchers, voucher)
+	}
+	if len(failed) > 0 {
+		return renderTallyProblems(c, project, failed, listURL)
+	}
+	if len(vouchers) == 0 {
+		auth.SetFlash(c.Request(), "error", "No issued DCs match the filters")
+		return c.Redirect(http.StatusFound, listURL)
+	}
+
+	settings, err := database.GetTallySettings(project.ID)
+	if err != nil {
+		slog.Error("Failed to load Tally settings", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to load the Tally settings")
+		return c.Redirect(http.StatusFound, listURL)
+	}
+	data, err := services.MarshalTallyXML(settings, vouchers)
+	if err != nil {
+		slog.Error("Failed to encode Tally XML", slog.Int("project_id", project.ID), slog.String("error", err.Error()))
+		auth.SetFlash(c.Request(), "error", "Failed to generate the Tally XML")
+		return c.Redirect(http.StatusFound, listURL)
+	}
+
+	filename := fmt.Sprintf("Tally_%s_%s_%d_DCs.xml",
+		services.SanitizeDCFilename(project.DCPrefix), time.Now().Format("20060102"), len(vouchers))
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	return c.Blob(http.StatusOK, "application/xml", data)
+}
+
+// tallyExportDCs returns the DCs a Tally export covers: the ticked DCs, or the
+// DCs booked in Tally matching the listing filters when none are ticked. It returns nil
+// when nothing is ticked and the filters have no date range.
+func tallyExportDCs(c echo.Context, project *models.Project) ([]*models.DeliveryChallan, error) {
+	params, _ := c.FormParams()
+	if len(params["dc_id"]) > 0 {
+		var dcs []*models.DeliveryChallan
+		seen := make(map[int]bool)
+		for _, v := range params["dc_id"] {
+			id, err := strconv.Atoi(v)
+			if err != nil || seen[id] {
+				continue
+			}
+			seen[id] = true
+			dc, err := database.GetDeliveryChallanByID(id)
+			if err != nil || dc.ProjectID != project.ID {
+				continue
+			}
+			dcs = append(dcs, dc)
+		}
+		if len(dcs) == 0 {
+			return nil, nil
+		}
+		return dcs, nil
+	}
+
+	dateFrom := strings.TrimSpace(c.QueryParam("date_from"))
+	dateTo := strings.TrimSpace(c.QueryParam("date_to"))
+	if dateFrom == "" || dateTo == "" {
+		return nil, nil
+	}
+	filters := database.DCListFilters{
+		ProjectID: strconv.Itoa(project.ID),
+		DCType:    c.QueryParam("type"),
+		Status:    c.QueryParam("status"),
+		DateFrom:  dateFrom,
+		DateTo:    dateTo,
+		Search:    c.QueryParam("search"),
+		SortBy:    "challan_date",
+		SortOrder: "asc",
+		PageSize:  tallyPageSize,
+	}
+
+	dcs := []*models.DeliveryChallan{}
+	for page := 1; ; page++ {
+		filters.Page = page
+		result, err := database.GetAllDCsFiltered(filters)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range dcListItemsToModels(result.DCs) { //nolint:gocritic
+			// DCs in the range that are not booked in Tally (drafts, cancelled,
+			// officials, returns, split transits) are left out rather than refused.
+			if !models.TallyReady(&item, false) {
+				continue
+			}
+			dc, err := database.GetDeliveryChallanByID(item.ID)
+			if err != nil {
+				return nil, err
+			}
+			if exclusion, err := tallyExclusion(dc); err != nil {
+				return nil, err
+			} else if exclusion != "" {
+				continue
+			}
+			dcs = append(dcs, dc)
+		}
+		if page >= result.TotalPages {
+			break
+		}
+	}
+	return dcs, nil
+}
+
+// tallyExclusion explains why a DC is not exported to Tally, or returns "" when
+// it is; see models.TallyExclusion.
+func tallyExclusion(dc *models.DeliveryChallan) (string, error) {
+	split := false
+	if dc.DCType == "transit" {
+		var err error
+		if split, err = database.IsTransferSplitDC(dc.ID); err != nil {
+			return "", err
+		}
+	}
+	return models.TallyExclusion(dc, split), nil
+}
+
+// renderTallyProblems lists the fields to fix before the Tally XML can be downloaded.
+func renderTallyProblems(c echo.Context, project *models.Project, checks []*models.TallyCheck, backURL string) error {
+	user := auth.GetCurrentUser(c)
+	allProjects, _ := database.GetAccessibleProjects(user)
+	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
+	topbar := partials.Topbar(user, project, allProjects, "", "")
+	pageContent := deliverychallan.TallyProblems(project, checks, backURL)
+	return components.Render(c, http.StatusUnprocessableEntity,
+		layouts.MainWithContent("Tally Export", sidebar, topbar, "", "", pageContent),
+	)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// wizardTaxTypeCheck derives the tax type a shipment or transfer should carry
+// from the bill-from state and the place of supply (bill-to, else ship-to),
+// and compares it with the one picked in step 1. Returns nil when the states
+// cannot be determined.
+func wizardTaxTypeCheck(project *models.Project, chosen string, billFromID, billToID, shipToID int) *models.TaxTypeCheck {
+	company, _ := database.GetCompanySettings()
+	supplier := models.SupplierStateCode(wizardAddress(billFromID), project, company)
+	pos := models.PlaceOfSupplyStateCode(wizardAddress(billToID), wizardAddress(shipToID))
+	return models.NewTaxTypeCheck(chosen, supplier, pos)
+}
+
+// wizardAddress loads an address picked in a wizard, or nil when none was picked.
+func wizardAddress(id int) *models.Address {
+	if id == 0 {
+		return nil
+	}
+	a, err := database.GetAddress(id)
+	if err != nil {
+		return nil
+	}
+	return a
+}
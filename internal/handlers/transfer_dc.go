@@ -23,8 +23,8 @@ import (
 
 // validateTransferDCIssue checks if a Transfer DC can be issued based on its current status.
 func validateTransferDCIssue(currentStatus string) error {
-	if currentStatus != models.DCStatusDraft {
-		return fmt.Errorf("only draft Transfer DCs can be issued")
+	if !models.IsIssuableStatus(currentStatus) {
+		return fmt.Errorf("only draft or approval-pending Transfer DCs can be issued")
 	}
 	return nil
 }
@@ -154,6 +154,8 @@ func ShowTransferDCDetail(c echo.Context) error {
 		destinations,
 		splits,
 		summary,
+		loadApprovalState(models.ApprovalTarget{ProjectID: projectID, DCID: dcID}, dc.Status, user.ID),
+		ewayBillCheck(dc),
 		flashType,
 		flashMessage,
 		csrf.Token(c.Request()),
@@ -185,12 +187,38 @@ func IssueTransferDC(c echo.Context) error {
 	if err := validateTransferDCIssue(dc.Status); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
 	}
+	if msg := closedYearMessage(dc); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
+
+	subject := &approvalSubject{
+		Target:     models.ApprovalTarget{ProjectID: projectID, DCID: dcID},
+		EntityType: models.AuditEntityTransferDC,
+		EntityID:   dcID,
+		Label:      "Transfer DC " + dc.DCNumber,
+		Status:     dc.Status,
+	}
+	msg, err := approvalGate(subject.Target, dc.Status, user.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to check approval"})
+	}
+	if msg != "" {
+		return c.JSON(http.StatusForbidden, map[string]interface{}{"error": msg})
+	}
+	comments, msg := parseApprovalComments(c)
+	if msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{"error": msg})
+	}
 
 	if err := database.IssueDC(dcID, user.ID); err != nil {
 		slog.Error("Error issuing Transfer DC", slog.Int("dc_id", dcID), slog.String("error", err.Error()))
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to issue Transfer DC"})
 	}
 
+	recordAudit(c, projectID, models.AuditEntityTransferDC, dcID, models.AuditActionIssue, "Issued Transfer DC "+dc.DCNumber,
+		map[string]interface{}{"status": dc.Status}, map[string]interface{}{"status": "issued"})
+	recordApproval(c, subject, user.ID, comments)
+
 	auth.SetFlash(c.Request(), "success", "Transfer DC issued successfully")
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"success":  true,
@@ -237,6 +265,8 @@ func DeleteTransferDCHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to delete DC record"})
 	}
 
+	recordAudit(c, projectID, models.AuditEntityTransferDC, dcID, models.AuditActionDelete, "Deleted draft Transfer DC "+dc.DCNumber, dc, nil)
+
 	auth.SetFlash(c.Request(), "success", "Transfer DC deleted successfully")
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"success":  true,
@@ -276,6 +306,9 @@ func DeleteSplitHandler(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/dcs/%d", projectID, tdc.DCID))
 	}
 
+	recordAudit(c, projectID, models.AuditEntityTransferDC, tdc.DCID, models.AuditActionDelete,
+		fmt.Sprintf("Undid split #%d of Transfer DC %s", splitID, tdc.DCNumber), nil, nil)
+
 	auth.SetFlash(c.Request(), "success", "Split undone. Destinations returned to pool.")
 	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/dcs/%d", projectID, tdc.DCID))
 }
@@ -342,11 +375,6 @@ func ShowTransferDCPrintView(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", projectID))
 	}
 
-	project, err := database.GetProjectByID(projectID)
-	if err != nil {
-		return c.Redirect(http.StatusFound, "/projects")
-	}
-
 	tdc, err := database.GetTransferDC(tdcID)
 	if err != nil {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs", projectID))
@@ -357,7 +385,16 @@ func ShowTransferDCPrintView(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs", projectID))
 	}
 
+	// Issued DCs print from the data frozen at issue
+	snap, err := database.GetDCPrintSnapshot(dc)
+	if err != nil {
+		slog.Error("Error loading DC snapshot for print view", slog.Int("dc_id", dc.ID), slog.String("error", err.Error()))
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs", projectID))
+	}
+	project := snap.Project
+
 	lineItems, _ := database.GetLineItemsByDCID(tdc.DCID)
+	snap.ApplyProducts(lineItems)
 	for i := range lineItems {
 		serials, _ := database.GetSerialNumbersByLineItemID(lineItems[i].ID)
 		lineItems[i].SerialNumbers = serials
@@ -374,20 +411,10 @@ func ShowTransferDCPrintView(c echo.Context) error {
 	roundedTotal := math.Round(grandTotal)
 	halfTax := totalTax / 2.0
 
-	// Fetch addresses
-	var hubAddress, billFromAddress, dispatchFromAddress, billToAddress *models.Address
-	if tdc.HubAddressID > 0 {
-		hubAddress, _ = database.GetAddress(tdc.HubAddressID)
-	}
-	if dc.BillFromAddressID != nil && *dc.BillFromAddressID > 0 {
-		billFromAddress, _ = database.GetAddress(*dc.BillFromAddressID)
-	}
-	if dc.DispatchFromAddressID != nil && *dc.DispatchFromAddressID > 0 {
-		dispatchFromAddress, _ = database.GetAddress(*dc.DispatchFromAddressID)
-	}
-	if dc.BillToAddressID != nil && *dc.BillToAddressID > 0 {
-		billToAddress, _ = database.GetAddress(*dc.BillToAddressID)
-	}
+	hubAddress := snap.Address(models.SnapshotRoleHub)
+	billFromAddress := snap.Address(models.SnapshotRoleBillFrom)
+	dispatchFromAddress := snap.Address(models.SnapshotRoleDispatchFrom)
+	billToAddress := snap.Address(models.SnapshotRoleBillTo)
 
 	// Build destinations
 	var destinations []services.TransferDCPDFDestination
@@ -405,12 +432,11 @@ func ShowTransferDCPrintView(c echo.Context) error {
 				qtyMap[q.ProductID] = q.Quantity
 			}
 		}
-		// Fetch full address for proper display name and PDF filtering
+		// Use the frozen address for proper display name and PDF filtering
 		destName := d.AddressName
-		var fullAddr *models.Address
-		if addr, err := database.GetAddress(d.ShipToAddressID); err == nil && addr != nil {
-			destName = addr.DisplayName()
-			fullAddr = addr
+		fullAddr := snap.DestinationAddress(d.ShipToAddressID)
+		if fullAddr != nil {
+			destName = fullAddr.DisplayName()
 		}
 		destinations = append(destinations, services.TransferDCPDFDestination{
 			Name:       destName,
@@ -428,9 +454,9 @@ func ShowTransferDCPrintView(c echo.Context) error {
 	amountInWords := helpers.NumberToIndianWords(roundedTotal)
 
 	// Fetch address configs
-	billFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_from")
-	dispatchFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "dispatch_from")
-	billToConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_to")
+	billFromConfig := snap.AddressConfig("bill_from")
+	dispatchFromConfig := snap.AddressConfig("dispatch_from")
+	billToConfig := snap.AddressConfig("bill_to")
 
 	printData := pagetransfer.PrintData{
 		Project:             project,
@@ -22,23 +22,34 @@ func TestIssueTransferDC_Validation(t *testing.T) {
 			dcStatus:  models.DCStatusDraft,
 			wantError: false,
 		},
+		{
+			name:      "pending approval can be issued",
+			dcStatus:  models.DCStatusPendingApproval,
+			wantError: false,
+		},
+		{
+			name:      "rejected cannot be issued",
+			dcStatus:  models.DCStatusRejected,
+			wantError: true,
+			errMsg:    "only draft or approval-pending Transfer DCs can be issued",
+		},
 		{
 			name:      "issued cannot be re-issued",
 			dcStatus:  models.DCStatusIssued,
 			wantError: true,
-			errMsg:    "only draft Transfer DCs can be issued",
+			errMsg:    "only draft or approval-pending Transfer DCs can be issued",
 		},
 		{
 			name:      "splitting cannot be issued",
 			dcStatus:  models.DCStatusSplitting,
 			wantError: true,
-			errMsg:    "only draft Transfer DCs can be issued",
+			errMsg:    "only draft or approval-pending Transfer DCs can be issued",
 		},
 		{
 			name:      "split cannot be issued",
 			dcStatus:  models.DCStatusSplit,
 			wantError: true,
-			errMsg:    "only draft Transfer DCs can be issued",
+			errMsg:    "only draft or approval-pending Transfer DCs can be issued",
 		},
 	}
 
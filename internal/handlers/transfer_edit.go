@@ -6,7 +6,6 @@ import (
 	"math"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/gorilla/csrf"
 	"github.com/labstack/echo/v4"
@@ -16,6 +15,7 @@ import (
 	"github.com/narendhupati/dc-management-tool/internal/auth"
 	"github.com/narendhupati/dc-management-tool/internal/components"
 	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/helpers"
 	"github.com/narendhupati/dc-management-tool/internal/models"
 	"github.com/narendhupati/dc-management-tool/internal/services"
 )
@@ -116,6 +116,8 @@ func ShowEditTransferWizard(c echo.Context) error {
 		auth.SetFlash(c.Request(), "error", err.Error())
 		return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/projects/%d/transfer-dcs/%d", projectID, tdc.DCID))
 	}
+	// The version travels through every step so a stale save can be rejected.
+	editVersion := currentEditVersion(models.AuditEntityTransferDC, tdcID)
 
 	// Load wizard dropdown dependencies.
 	templates, err := database.GetTemplatesByProjectID(project.ID)
@@ -174,6 +176,7 @@ func ShowEditTransferWizard(c echo.Context) error {
 		flashType, flashMessage, csrf.Token(c.Request()),
 		prefill,
 		tdcID,
+		editVersion,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
@@ -199,6 +202,7 @@ func EditTransferWizardStep2(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid transfer DC id")
 	}
+	editVersion := formEditVersion(c)
 	tdc, err := database.GetTransferDC(tdcID)
 	if err != nil || tdc.DCStatus != models.DCStatusDraft || tdc.ProjectID != project.ID {
 		return echo.NewHTTPError(http.StatusNotFound, "draft transfer DC not found")
@@ -277,6 +281,7 @@ func EditTransferWizardStep2(c echo.Context) error {
 		preselectedIDs,
 		preselectedBillFromID, preselectedDispatchFromID, preselectedBillToID,
 		tdcID,
+		editVersion,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -300,6 +305,7 @@ func EditTransferWizardQuantityStep(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid transfer DC id")
 	}
+	editVersion := formEditVersion(c)
 	tdc, err := database.GetTransferDC(tdcID)
 	if err != nil || tdc.DCStatus != models.DCStatusDraft || tdc.ProjectID != project.ID {
 		return echo.NewHTTPError(http.StatusNotFound, "draft transfer DC not found")
@@ -329,6 +335,7 @@ func EditTransferWizardQuantityStep(c echo.Context) error {
 			shipToAddressIDs,
 			billFromAddrID, dispatchFromAddrID, billToAddrID,
 			tdcID,
+			editVersion,
 		)
 		sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 		topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
@@ -341,7 +348,7 @@ func EditTransferWizardQuantityStep(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs/%d/edit", projectID, tdcID))
 	}
 
-	quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs)
+	quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs, tdcID)
 
 	// Load existing quantity grid from Transfer DC for prefill
 	prefillQuantities := loadTransferEditQuantities(tdcID, shipToAddressIDs)
@@ -358,6 +365,7 @@ func EditTransferWizardQuantityStep(c echo.Context) error {
 		"", "",
 		prefillQuantities, nil,
 		tdcID,
+		editVersion,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -381,6 +389,7 @@ func EditTransferWizardStep4(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid transfer DC id")
 	}
+	editVersion := formEditVersion(c)
 	tdc, err := database.GetTransferDC(tdcID)
 	if err != nil || tdc.DCStatus != models.DCStatusDraft || tdc.ProjectID != project.ID {
 		return echo.NewHTTPError(http.StatusNotFound, "draft transfer DC not found")
@@ -405,7 +414,7 @@ func EditTransferWizardStep4(c echo.Context) error {
 			globalErr = "Please fix the quantity errors below"
 		}
 
-		quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs)
+		quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs, tdcID)
 		allProjects, _ := database.GetAccessibleProjects(user)
 
 		pageContent := pagetransfer.WizardStep3(
@@ -418,6 +427,7 @@ func EditTransferWizardStep4(c echo.Context) error {
 			globalErr, "error",
 			quantities, qtyErrors,
 			tdcID,
+			editVersion,
 		)
 		sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 		topbar := partials.Topbar(user, project, allProjects, "error", globalErr)
@@ -462,6 +472,7 @@ func EditTransferWizardStep4(c echo.Context) error {
 		computeProductQuantityTotals(quantities),
 		prefillSerials, nil,
 		tdcID,
+		editVersion,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -485,6 +496,7 @@ func EditTransferWizardStep5(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid transfer DC id")
 	}
+	editVersion := formEditVersion(c)
 	tdc, err := database.GetTransferDC(tdcID)
 	if err != nil || tdc.DCStatus != models.DCStatusDraft || tdc.ProjectID != project.ID {
 		return echo.NewHTTPError(http.StatusNotFound, "draft transfer DC not found")
@@ -504,7 +516,12 @@ func EditTransferWizardStep5(c echo.Context) error {
 
 	// Validate serial counts
 	serialErrors := make(map[int]string)
+	registry := newSerialRegistryGuard(projectID)
 	for i, sd := range serialData {
+		if sd.InputError != "" {
+			serialErrors[sd.ProductID] = sd.InputError
+			continue
+		}
 		expectedTotal := 0
 		for _, addrID := range shipToAddressIDs {
 			expectedTotal += quantities[products[i].ID][addrID]
@@ -532,6 +549,23 @@ func EditTransferWizardStep5(c echo.Context) error {
 				serialErrors[sd.ProductID] = fmt.Sprintf("Serial %s already exists in DC %s", conflicts[0].SerialNumber, conflicts[0].DCNumber)
 			}
 		}
+		// Serial format rule check
+		if _, alreadyHasError := serialErrors[sd.ProductID]; !alreadyHasError {
+			if msg := models.SerialRuleError(products[i].ItemName, products[i].SerialRule, sd.AllSerials); msg != "" {
+				serialErrors[sd.ProductID] = msg
+			}
+		}
+
+		// Serial registry check
+		if _, alreadyHasError := serialErrors[sd.ProductID]; !alreadyHasError {
+			msg := models.SerialRuleError(products[i].ItemName, products[i].SerialRule, sd.AllSerials)
+			if msg == "" {
+				msg = registry.check(sd.ProductID, products[i].ItemName, sd.AllSerials)
+			}
+			if msg != "" {
+				serialErrors[sd.ProductID] = msg
+			}
+		}
 	}
 
 	// Build quantity hidden fields
@@ -574,6 +608,7 @@ func EditTransferWizardStep5(c echo.Context) error {
 			computeProductQuantityTotals(quantities),
 			prefillSerials, serialErrors,
 			tdcID,
+			editVersion,
 		)
 		sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 		topbar := partials.Topbar(user, project, allProjects, "error", "Please fix the serial number errors below")
@@ -617,6 +652,8 @@ func EditTransferWizardStep5(c echo.Context) error {
 		quantityHiddenFields,
 		computeProductQuantityTotals(quantities),
 		tdcID,
+		editVersion,
+		wizardTaxTypeCheck(project, taxType, billFromAddrID, billToAddrID, hubAddressID),
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -666,37 +703,16 @@ func SaveTransferEdit(c echo.Context) error {
 	var serials []transferEditSerialData
 	for _, p := range products {
 		sd := transferEditSerialData{ProductID: p.ID}
-		serialsRaw := c.FormValue(fmt.Sprintf("serials_%d", p.ID))
-		if serialsRaw != "" {
-			for _, sn := range strings.Split(serialsRaw, "\n") {
-				sn = strings.TrimSpace(sn)
-				if sn != "" {
-					sd.AllSerials = append(sd.AllSerials, sn)
-				}
-			}
+		parsed, parseErr := helpers.ParseSerialInput(c.FormValue(fmt.Sprintf("serials_%d", p.ID)))
+		if parseErr != nil {
+			auth.SetFlash(c.Request(), "error", fmt.Sprintf("Product %s: %v", p.ItemName, parseErr))
+			return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/projects/%d/transfer-dcs/%d", project.ID, tdc.DCID))
 		}
+		sd.AllSerials = parsed
 		serials = append(serials, sd)
 	}
 
-	// 3. Load current destinations; compute sets.
-	oldDests, err := database.GetTransferDCDestinations(tdcID)
-	if err != nil {
-		return handleTransferEditError(c, project.ID, tdcID, err)
-	}
-	oldDestMap := make(map[int]*models.TransferDCDestination) // shipToAddressID → destination
-	oldAddressIDs := make([]int, 0, len(oldDests))
-	for _, d := range oldDests {
-		oldDestMap[d.ShipToAddressID] = d
-		oldAddressIDs = append(oldAddressIDs, d.ShipToAddressID)
-	}
-
-	newAddressSet := toIntSet(shipToAddressIDs)
-	oldAddressSet := toIntSet(oldAddressIDs)
-	toKeep := intersectSets(oldAddressSet, newAddressSet)
-	toAdd := subtractSets(newAddressSet, oldAddressSet)
-	toDelete := subtractSets(oldAddressSet, newAddressSet)
-
-	// 4. Build line items and serials.
+	// 3. Build line items and serials.
 	lineItems, serialsByLine := buildTransferEditLineItems(products, quantities, serials)
 
 	var challanDatePtr *string
@@ -704,7 +720,8 @@ func SaveTransferEdit(c echo.Context) error {
 		challanDatePtr = &challanDate
 	}
 
-	// 5. Update transfer_dcs record (metadata).
+	// 4. Update transfer_dcs record (metadata).
+	before := *tdc
 	tdc.HubAddressID = hubAddressID
 	tdc.TemplateID = &templateID
 	tdc.TaxType = taxType
@@ -713,85 +730,74 @@ func SaveTransferEdit(c echo.Context) error {
 	tdc.VehicleNumber = vehicleNumber
 	tdc.EwayBillNumber = ewayBillNumber
 	tdc.DocketNumber = docketNumber
-	if err := database.UpdateTransferDC(tdc); err != nil {
-		return handleTransferEditError(c, project.ID, tdcID, err)
-	}
 
-	// 6. Update parent delivery_challans record (addresses, date).
-	if err := database.UpdateDeliveryChallanAddressesAndDate(
-		tdc.DCID, challanDatePtr,
-		billFromAddressID, dispatchFromAddressID, billToAddressID, hubAddressID,
-	); err != nil {
-		return handleTransferEditError(c, project.ID, tdcID, err)
+	dateGuard := checkChallanDate(c, project.ID, challanDate, challanDay(before.ChallanDate), 0, services.DCTypeTransfer)
+	if dateGuard.Refused != "" {
+		auth.SetFlash(c.Request(), "error", dateGuard.Refused)
+		return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/projects/%d/transfer-dcs/%d", project.ID, tdc.DCID))
 	}
 
-	// 7. Replace line items and serials on parent DC.
-	if err := database.ReplaceLineItemsAndSerials(tdc.DCID, project.ID, lineItems, serialsByLine); err != nil {
-		return handleTransferEditError(c, project.ID, tdcID, err)
+	registry := newSerialRegistryGuard(project.ID)
+	for i, sd := range serials {
+		msg := models.SerialRuleError(products[i].ItemName, products[i].SerialRule, sd.AllSerials)
+		if msg == "" {
+			msg = registry.check(sd.ProductID, products[i].ItemName, sd.AllSerials)
+		}
+		if msg != "" {
+			auth.SetFlash(c.Request(), "error", msg)
+			return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/projects/%d/transfer-dcs/%d", project.ID, tdc.DCID))
+		}
 	}
 
-	// 8. Reconcile destinations.
-	// Update existing destinations' quantities.
-	for _, addrID := range toKeep {
-		dest := oldDestMap[addrID]
-		var destQtys []models.TransferDCDestinationQty
+	// 5–7. Save the transfer DC, its parent DC and its destinations in one
+	// transaction, provided it is still at the version the wizard was opened
+	// with; otherwise nothing is written and the conflict page shows what the
+	// other user changed.
+	edit := database.TransferDCEdit{
+		TransferDC:            tdc,
+		ProjectID:             project.ID,
+		Version:               formEditVersion(c),
+		ChallanDate:           challanDatePtr,
+		BillFromAddressID:     billFromAddressID,
+		DispatchFromAddressID: dispatchFromAddressID,
+		BillToAddressID:       billToAddressID,
+		LineItems:             lineItems,
+		SerialsByLine:         serialsByLine,
+	}
+	for _, addrID := range shipToAddressIDs {
+		dest := database.TransferDestinationEdit{ShipToAddressID: addrID}
 		for _, p := range products {
-			qty := 0
-			if qMap, ok := quantities[p.ID]; ok {
-				qty = qMap[addrID]
-			}
-			destQtys = append(destQtys, models.TransferDCDestinationQty{
+			dest.Quantities = append(dest.Quantities, models.TransferDCDestinationQty{
 				ProductID: p.ID,
-				Quantity:  qty,
+				Quantity:  quantities[p.ID][addrID],
 			})
 		}
-		if err := database.SetDestinationQuantities(dest.ID, destQtys); err != nil {
-			return handleTransferEditError(c, project.ID, tdcID, err)
-		}
+		edit.Destinations = append(edit.Destinations, dest)
 	}
-
-	// Delete removed destinations.
-	for _, addrID := range toDelete {
-		dest := oldDestMap[addrID]
-		if err := database.DeleteTransferDCDestination(dest.ID); err != nil {
+	if err := database.SaveTransferDCEdit(edit); err != nil {
+		if !isStaleVersion(err) {
 			return handleTransferEditError(c, project.ID, tdcID, err)
 		}
+		conflict := newEditConflict(models.AuditEntityTransferDC, tdcID, tdc.DCID, edit.Version, &before, tdc)
+		conflict.Subject = "Transfer DC " + tdc.DCNumber
+		conflict.ReloadURL = fmt.Sprintf("/projects/%d/transfer-dcs/%d/edit", project.ID, tdcID)
+		conflict.BackURL = fmt.Sprintf("/projects/%d/transfer-dcs/%d", project.ID, tdc.DCID)
+		return renderEditConflict(c, project, conflict)
 	}
 
-	// Add new destinations.
-	if len(toAdd) > 0 {
-		if err := database.AddTransferDCDestinations(tdcID, toAdd); err != nil {
-			return handleTransferEditError(c, project.ID, tdcID, err)
-		}
-		// Set quantities for newly added destinations
-		newDests, _ := database.GetTransferDCDestinations(tdcID)
-		for _, d := range newDests {
-			if newAddressSet[d.ShipToAddressID] && !oldAddressSet[d.ShipToAddressID] {
-				var destQtys []models.TransferDCDestinationQty
-				for _, p := range products {
-					qty := 0
-					if qMap, ok := quantities[p.ID]; ok {
-						qty = qMap[d.ShipToAddressID]
-					}
-					destQtys = append(destQtys, models.TransferDCDestinationQty{
-						ProductID: p.ID,
-						Quantity:  qty,
-					})
-				}
-				if err := database.SetDestinationQuantities(d.ID, destQtys); err != nil {
-					return handleTransferEditError(c, project.ID, tdcID, err)
-				}
-			}
-		}
-	}
-
-	// 9. Recalculate destination count.
+	// 8. Recalculate destination count.
 	if err := database.RecalculateSplitProgress(tdcID); err != nil {
 		slog.Error("Error recalculating split progress", slog.String("error", err.Error()))
 	}
 
 	_ = user // used for context
-	auth.SetFlash(c.Request(), "success", "Transfer DC updated successfully.")
+	if updated, err := database.GetTransferDC(tdcID); err == nil {
+		recordAudit(c, project.ID, models.AuditEntityTransferDC, tdc.DCID, models.AuditActionUpdate,
+			"Edited draft Transfer DC "+tdc.DCNumber, &before, updated)
+	}
+	dateGuard.audit(c, project.ID, models.AuditEntityTransferDC, tdc.DCID)
+
+	registry.flash(c, "Transfer DC updated successfully.")
 	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("/projects/%d/transfer-dcs/%d", project.ID, tdc.DCID))
 }
 
@@ -844,7 +850,8 @@ func EditTransferBackToStep1(c echo.Context) error {
 
 	allProjects, _ := database.GetAccessibleProjects(user)
 	tdcID, _ := strconv.Atoi(c.Param("tdcid"))
-	pageContent := pagetransfer.WizardStep1(user, project, allProjects, templates, transporters, hubAddresses, "", "", csrf.Token(c.Request()), prefill, tdcID)
+	editVersion := formEditVersion(c)
+	pageContent := pagetransfer.WizardStep1(user, project, allProjects, templates, transporters, hubAddresses, "", "", csrf.Token(c.Request()), prefill, tdcID, editVersion)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
 	return components.RenderOK(c, layouts.MainWithContent("Edit Transfer DC", sidebar, topbar, "", "", pageContent))
@@ -872,6 +879,7 @@ func EditTransferBackToStep2(c echo.Context) error {
 
 	allProjects, _ := database.GetAccessibleProjects(user)
 	tdcID, _ := strconv.Atoi(c.Param("tdcid"))
+	editVersion := formEditVersion(c)
 	pageContent := pagetransfer.WizardStep2(
 		user, project, allProjects, tmpl,
 		challanDate, transferDCNumber,
@@ -883,6 +891,7 @@ func EditTransferBackToStep2(c echo.Context) error {
 		shipToAddressIDs,
 		billFromAddrID, dispatchFromAddrID, billToAddrID,
 		tdcID,
+		editVersion,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -906,10 +915,11 @@ func EditTransferBackToStep3(c echo.Context) error {
 
 	products, _ := database.GetTemplateProducts(templateID)
 	quantities := parseQuantityForm(c, products, shipToAddressIDs)
-	quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs)
+	tdcID, _ := strconv.Atoi(c.Param("tdcid"))
+	quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs, tdcID)
 
 	allProjects, _ := database.GetAccessibleProjects(user)
-	tdcID, _ := strconv.Atoi(c.Param("tdcid"))
+	editVersion := formEditVersion(c)
 	pageContent := pagetransfer.WizardStep3(
 		user, project, allProjects, products, quantityAddresses,
 		templateID, challanDate,
@@ -920,6 +930,7 @@ func EditTransferBackToStep3(c echo.Context) error {
 		"", "",
 		quantities, nil,
 		tdcID,
+		editVersion,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -971,6 +982,7 @@ func EditTransferBackToStep4(c echo.Context) error {
 
 	allProjects, _ := database.GetAccessibleProjects(user)
 	tdcID, _ := strconv.Atoi(c.Param("tdcid"))
+	editVersion := formEditVersion(c)
 	pageContent := pagetransfer.WizardStep4(
 		user, project, allProjects, products, challanDate,
 		strconv.Itoa(templateID), strconv.Itoa(hubAddressID),
@@ -984,6 +996,7 @@ func EditTransferBackToStep4(c echo.Context) error {
 		computeProductQuantityTotals(quantities),
 		prefillSerials, nil,
 		tdcID,
+		editVersion,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -15,6 +15,7 @@ import (
 	"github.com/narendhupati/dc-management-tool/internal/auth"
 	"github.com/narendhupati/dc-management-tool/internal/components"
 	"github.com/narendhupati/dc-management-tool/internal/database"
+	"github.com/narendhupati/dc-management-tool/internal/helpers"
 	"github.com/narendhupati/dc-management-tool/internal/models"
 	"github.com/narendhupati/dc-management-tool/internal/services"
 )
@@ -57,14 +58,10 @@ func parseTransferSerialForm(c echo.Context, products []*models.TemplateProductR
 	var result []pagetransfer.TransferSerialData
 	for _, p := range products {
 		sd := pagetransfer.TransferSerialData{ProductID: p.ID}
-		serialsRaw := c.FormValue(fmt.Sprintf("serials_%d", p.ID))
-		if serialsRaw != "" {
-			for _, sn := range strings.Split(serialsRaw, "\n") {
-				sn = strings.TrimSpace(sn)
-				if sn != "" {
-					sd.AllSerials = append(sd.AllSerials, sn)
-				}
-			}
+		var err error
+		sd.AllSerials, err = helpers.ParseSerialInput(c.FormValue(fmt.Sprintf("serials_%d", p.ID)))
+		if err != nil {
+			sd.InputError = err.Error()
 		}
 		result = append(result, sd)
 	}
@@ -121,6 +118,7 @@ func ShowCreateTransferWizard(c echo.Context) error {
 		csrf.Token(c.Request()),
 		nil,
 		0,
+		0,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
@@ -205,6 +203,7 @@ func TransferWizardStep2(c echo.Context) error {
 		nil,
 		0, 0, 0,
 		0,
+		0,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -261,6 +260,7 @@ func TransferWizardQuantityStep(c echo.Context) error {
 			shipToAddressIDs,
 			billFromAddrID, dispatchFromAddrID, billToAddrID,
 			0,
+			0,
 		)
 		sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 		topbar := partials.Topbar(user, project, allProjects, flashType, flashMessage)
@@ -275,7 +275,7 @@ func TransferWizardQuantityStep(c echo.Context) error {
 	}
 
 	// Build QuantityAddress list from selected ship-to IDs
-	quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs)
+	quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs, 0)
 
 	allProjects, _ := database.GetAccessibleProjects(user)
 
@@ -302,6 +302,7 @@ func TransferWizardQuantityStep(c echo.Context) error {
 		"", "",
 		nil, nil,
 		0,
+		0,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -345,7 +346,7 @@ func TransferWizardStep4(c echo.Context) error {
 		}
 
 		// Re-render quantity grid with errors
-		quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs)
+		quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs, 0)
 		allProjects, _ := database.GetAccessibleProjects(user)
 
 		pageContent := pagetransfer.WizardStep3(
@@ -358,6 +359,7 @@ func TransferWizardStep4(c echo.Context) error {
 			globalErr, "error",
 			quantities, qtyErrors,
 			0,
+			0,
 		)
 		sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 		topbar := partials.Topbar(user, project, allProjects, "error", globalErr)
@@ -410,6 +412,7 @@ func TransferWizardStep4(c echo.Context) error {
 		computeProductQuantityTotals(quantities),
 		nil, nil,
 		0,
+		0,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -449,7 +452,12 @@ func TransferWizardStep5(c echo.Context) error {
 
 	// Validate serial counts and collect per-product errors
 	serialErrors := make(map[int]string)
+	registry := newSerialRegistryGuard(projectID)
 	for i, sd := range serialData {
+		if sd.InputError != "" {
+			serialErrors[sd.ProductID] = sd.InputError
+			continue
+		}
 		// Sum quantities across all destinations for this product
 		expectedTotal := 0
 		for _, addrID := range shipToAddressIDs {
@@ -480,6 +488,24 @@ func TransferWizardStep5(c echo.Context) error {
 				serialErrors[sd.ProductID] = fmt.Sprintf("Serial %s already exists in DC %s", conflicts[0].SerialNumber, conflicts[0].DCNumber)
 			}
 		}
+
+		// Serial format rule check
+		if _, alreadyHasError := serialErrors[sd.ProductID]; !alreadyHasError {
+			if msg := models.SerialRuleError(products[i].ItemName, products[i].SerialRule, sd.AllSerials); msg != "" {
+				serialErrors[sd.ProductID] = msg
+			}
+		}
+
+		// Serial registry check
+		if _, alreadyHasError := serialErrors[sd.ProductID]; !alreadyHasError {
+			msg := models.SerialRuleError(products[i].ItemName, products[i].SerialRule, sd.AllSerials)
+			if msg == "" {
+				msg = registry.check(sd.ProductID, products[i].ItemName, sd.AllSerials)
+			}
+			if msg != "" {
+				serialErrors[sd.ProductID] = msg
+			}
+		}
 	}
 
 	// Build quantity hidden fields from carry-forward data
@@ -524,6 +550,7 @@ func TransferWizardStep5(c echo.Context) error {
 			computeProductQuantityTotals(quantities),
 			prefillSerials, serialErrors,
 			0,
+			0,
 		)
 		sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 		topbar := partials.Topbar(user, project, allProjects, "error", "Please fix the serial number errors below")
@@ -583,6 +610,8 @@ func TransferWizardStep5(c echo.Context) error {
 		quantityHiddenFields,
 		computeProductQuantityTotals(quantities),
 		0,
+		0,
+		wizardTaxTypeCheck(project, taxType, billFromAddrID, billToAddrID, hubAddressID),
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -643,15 +672,12 @@ func CreateTransferDC(c echo.Context) error {
 		}
 
 		// Parse serials
-		serialsRaw := c.FormValue(fmt.Sprintf("serials_%d", p.ID))
-		if serialsRaw != "" {
-			for _, sn := range strings.Split(serialsRaw, "\n") {
-				sn = strings.TrimSpace(sn)
-				if sn != "" {
-					item.AllSerials = append(item.AllSerials, sn)
-				}
-			}
+		serials, parseErr := helpers.ParseSerialInput(c.FormValue(fmt.Sprintf("serials_%d", p.ID)))
+		if parseErr != nil {
+			auth.SetFlash(c.Request(), "error", fmt.Sprintf("Product %s: %v", p.ItemName, parseErr))
+			return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs/new", projectID))
 		}
+		item.AllSerials = serials
 
 		lineItems = append(lineItems, item)
 	}
@@ -692,6 +718,25 @@ func CreateTransferDC(c echo.Context) error {
 		}
 	}
 
+	// Check serials against the serial registry
+	registry := newSerialRegistryGuard(projectID)
+	for i, item := range lineItems {
+		msg := models.SerialRuleError(products[i].ItemName, products[i].SerialRule, item.AllSerials)
+		if msg == "" {
+			msg = registry.check(item.ProductID, products[i].ItemName, item.AllSerials)
+		}
+		if msg != "" {
+			auth.SetFlash(c.Request(), "error", msg)
+			return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs/new", projectID))
+		}
+	}
+
+	dateGuard := checkChallanDate(c, projectID, challanDate, "", 0, services.DCTypeTransfer)
+	if dateGuard.Refused != "" {
+		auth.SetFlash(c.Request(), "error", dateGuard.Refused)
+		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs/new", projectID))
+	}
+
 	params := services.TransferDCParams{
 		ProjectID:             projectID,
 		TemplateID:            templateID,
@@ -711,14 +756,21 @@ func CreateTransferDC(c echo.Context) error {
 		CreatedBy:             user.ID,
 	}
 
-	_, err = services.CreateTransferDC(database.DB, params)
+	tdcID, err := services.CreateTransferDC(database.DB, params)
 	if err != nil {
 		slog.Error("Error creating transfer DC", slog.String("error", err.Error()), slog.Int("projectID", projectID))
 		auth.SetFlash(c.Request(), "error", fmt.Sprintf("Failed to create transfer DC: %v", err))
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/transfer-dcs/new", projectID))
 	}
 
-	auth.SetFlash(c.Request(), "success", "Transfer DC created successfully")
+	if tdc, err := database.GetTransferDC(tdcID); err == nil {
+		recordAudit(c, projectID, models.AuditEntityTransferDC, tdc.DCID, models.AuditActionCreate,
+			"Created Transfer DC "+tdc.DCNumber, nil,
+			map[string]interface{}{"template_id": templateID, "hub_address_id": hubAddressID, "challan_date": challanDate, "ship_to_address_ids": shipToAddressIDs})
+		dateGuard.audit(c, projectID, models.AuditEntityTransferDC, tdc.DCID)
+	}
+
+	registry.flash(c, "Transfer DC created successfully")
 	return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d/dashboard", projectID))
 }
 
@@ -772,7 +824,7 @@ func TransferBackToStep1(c echo.Context) error {
 	}
 
 	allProjects, _ := database.GetAccessibleProjects(user)
-	pageContent := pagetransfer.WizardStep1(user, project, allProjects, templates, transporters, hubAddresses, "", "", csrf.Token(c.Request()), prefill, 0)
+	pageContent := pagetransfer.WizardStep1(user, project, allProjects, templates, transporters, hubAddresses, "", "", csrf.Token(c.Request()), prefill, 0, 0)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
 	return components.RenderOK(c, layouts.MainWithContent("Transfer DC Wizard", sidebar, topbar, "", "", pageContent))
@@ -810,6 +862,7 @@ func TransferBackToStep2(c echo.Context) error {
 		shipToAddressIDs,
 		billFromAddrID, dispatchFromAddrID, billToAddrID,
 		0,
+		0,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -833,7 +886,7 @@ func TransferBackToStep3(c echo.Context) error {
 
 	products, _ := database.GetTemplateProducts(templateID)
 	quantities := parseQuantityForm(c, products, shipToAddressIDs)
-	quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs)
+	quantityAddresses := loadTransferQuantityAddresses(projectID, shipToAddressIDs, 0)
 
 	allProjects, _ := database.GetAccessibleProjects(user)
 	pageContent := pagetransfer.WizardStep3(
@@ -846,6 +899,7 @@ func TransferBackToStep3(c echo.Context) error {
 		"", "",
 		quantities, nil,
 		0,
+		0,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -911,6 +965,7 @@ func TransferBackToStep4(c echo.Context) error {
 		computeProductQuantityTotals(quantities),
 		prefillSerials, nil,
 		0,
+		0,
 	)
 	sidebar := partials.Sidebar(user, project, allProjects, c.Request().URL.Path)
 	topbar := partials.Topbar(user, project, allProjects, "", "")
@@ -920,8 +975,14 @@ func TransferBackToStep4(c echo.Context) error {
 // ─── Transfer wizard shared helpers ───────────────────────────────────────────
 
 // loadTransferQuantityAddresses loads QuantityAddress structs (transfer_dcs package type)
-// for the selected ship-to IDs.
-func loadTransferQuantityAddresses(projectID int, shipToAddressIDs []int) []pagetransfer.QuantityAddress {
+// for the selected ship-to IDs, with what is left of the BOQ plan for each. The
+// destinations of editTransferDCID, the Transfer DC being edited, do not count
+// against the plan; pass 0 for a new one.
+func loadTransferQuantityAddresses(projectID int, shipToAddressIDs []int, editTransferDCID int) []pagetransfer.QuantityAddress {
+	remaining, err := database.GetBOQRemaining(projectID, 0, editTransferDCID)
+	if err != nil {
+		slog.Error("error loading BOQ plan", slog.String("error", err.Error()), slog.Int("projectID", projectID))
+	}
 	shipToConfig, _ := database.GetOrCreateAddressConfig(projectID, "ship_to")
 	var result []pagetransfer.QuantityAddress
 	if shipToConfig != nil {
@@ -933,8 +994,9 @@ func loadTransferQuantityAddresses(projectID int, shipToAddressIDs []int) []page
 		for _, a := range allShipTo {
 			if selectedSet[a.ID] {
 				result = append(result, pagetransfer.QuantityAddress{
-					ID:   a.ID,
-					Name: a.DisplayName(),
+					ID:            a.ID,
+					Name:          a.DisplayName(),
+					PlanRemaining: remaining[a.ID],
 				})
 			}
 		}
@@ -6,7 +6,6 @@ import (
 	"math"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/gorilla/csrf"
 	"github.com/labstack/echo/v4"
@@ -42,6 +41,8 @@ func ShowDCDetail(c echo.Context) error {
 		return ShowOfficialDCDetail(c)
 	case "transfer":
 		return ShowTransferDCDetail(c)
+	case "return":
+		return ShowReturnDCDetail(c)
 	default:
 		return showTransitDCDetail(c)
 	}
@@ -154,7 +155,21 @@ func showTransitDCDetail(c echo.Context) error {
 	_ = billToAddress
 	_ = shipmentGroup
 	_ = siblingDCs
-	_ = transitDetails
+
+	revisions, err := database.GetDCRevisionHistory(dc)
+	if err != nil {
+		slog.Error("Error fetching DC revisions", slog.Int("dc_id", dcID), slog.String("error", err.Error()))
+	}
+
+	returns, err := database.GetReturnDCsByOriginalDC(dcID)
+	if err != nil {
+		slog.Error("Error fetching return DCs", slog.Int("dc_id", dcID), slog.String("error", err.Error()))
+	}
+
+	var ewayBillNumber string
+	if transitDetails != nil {
+		ewayBillNumber = transitDetails.EwayBillNumber
+	}
 
 	allProjects, _ := database.GetAccessibleProjects(user)
 
@@ -165,6 +180,10 @@ func showTransitDCDetail(c echo.Context) error {
 		dc,
 		billFromAddress,
 		dispatchFromAddress,
+		revisions,
+		returns,
+		ewayBillNumber,
+		ewayBillCheck(dc),
 		flashType,
 		flashMessage,
 		csrf.Token(c.Request()),
@@ -211,42 +230,29 @@ func ShowTransitDCPrintView(c echo.Context) error {
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", projectID))
 	}
 
-	project, err := database.GetProjectByID(projectID)
-	if err != nil {
-		return c.Redirect(http.StatusFound, "/projects")
-	}
-
 	dc, err := database.GetDeliveryChallanByID(dcID)
 	if err != nil || dc.ProjectID != projectID {
 		auth.SetFlash(c.Request(), "error", "DC not found")
 		return c.Redirect(http.StatusFound, fmt.Sprintf("/projects/%d", projectID))
 	}
 
-	// Fetch Bill From and Dispatch From addresses selected during DC creation
-	var billFromAddress, dispatchFromAddress *models.Address
-	if dc.BillFromAddressID != nil && *dc.BillFromAddressID > 0 {
-		billFromAddress, _ = database.GetAddress(*dc.BillFromAddressID)
-	}
-	if dc.DispatchFromAddressID != nil && *dc.DispatchFromAddressID > 0 {
-		dispatchFromAddress, _ = database.GetAddress(*dc.DispatchFromAddressID)
+	// Issued DCs print from the data frozen at issue
+	snap, err := database.GetDCPrintSnapshot(dc)
+	if err != nil {
+		slog.Error("Error loading DC snapshot for print view", slog.Int("dc_id", dcID), slog.String("error", err.Error()))
+		return c.Redirect(http.StatusFound, "/projects")
 	}
 
-	// Get address configs for print filtering
-	billFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "bill_from")
-	dispatchFromConfig, _ := database.GetOrCreateAddressConfig(projectID, "dispatch_from")
+	billFromAddress := snap.Address(models.SnapshotRoleBillFrom)
+	dispatchFromAddress := snap.Address(models.SnapshotRoleDispatchFrom)
+	billFromConfig := snap.AddressConfig("bill_from")
+	dispatchFromConfig := snap.AddressConfig("dispatch_from")
 
-	return components.RenderOK(c, deliverychallan.TransitPrint(project, dc, billFromAddress, dispatchFromAddress, billFromConfig, dispatchFromConfig))
+	return components.RenderOK(c, deliverychallan.TransitPrint(snap.Project, dc, billFromAddress, dispatchFromAddress, billFromConfig, dispatchFromConfig))
 }
 
-// parseSerialNumbers splits newline-separated serial numbers, trimming whitespace and removing empty lines.
+// parseSerialNumbers reads pasted serial numbers, expanding ranges (see helpers.ParseSerialInput).
 func parseSerialNumbers(raw string) []string {
-	lines := strings.Split(raw, "\n")
-	var serials []string
-	for _, line := range lines {
-		s := strings.TrimSpace(line)
-		if s != "" {
-			serials = append(serials, s)
-		}
-	}
+	serials, _ := helpers.ParseSerialInput(raw)
 	return serials
 }
@@ -103,7 +103,7 @@ func CreateTransporterHandler(c echo.Context) error {
 		CompanyName:   strings.TrimSpace(c.FormValue("company_name")),
 		ContactPerson: strings.TrimSpace(c.FormValue("contact_person")),
 		Phone:         strings.TrimSpace(c.FormValue("phone")),
-		GSTNumber:     strings.TrimSpace(c.FormValue("gst_number")),
+		GSTNumber:     strings.ToUpper(strings.TrimSpace(c.FormValue("gst_number"))),
 		IsActive:      true,
 	}
 
@@ -131,6 +131,9 @@ func CreateTransporterHandler(c echo.Context) error {
 		}))
 	}
 
+	recordAudit(c, projectID, models.AuditEntityTransporter, transporter.ID, models.AuditActionCreate,
+		"Created transporter "+transporter.CompanyName, nil, transporter)
+
 	c.Response().Header().Set("HX-Trigger", "transporterChanged")
 	return components.RenderOK(c, htmxtransporters.TransporterFormSuccess(htmxtransporters.TransporterFormSuccessProps{
 		Message: "Transporter added successfully",
@@ -184,7 +187,7 @@ func UpdateTransporterHandler(c echo.Context) error {
 		CompanyName:   strings.TrimSpace(c.FormValue("company_name")),
 		ContactPerson: strings.TrimSpace(c.FormValue("contact_person")),
 		Phone:         strings.TrimSpace(c.FormValue("phone")),
-		GSTNumber:     strings.TrimSpace(c.FormValue("gst_number")),
+		GSTNumber:     strings.ToUpper(strings.TrimSpace(c.FormValue("gst_number"))),
 		IsActive:      existing.IsActive,
 	}
 
@@ -212,6 +215,11 @@ func UpdateTransporterHandler(c echo.Context) error {
 		}))
 	}
 
+	if updated, err := database.GetTransporterByID(transporterID); err == nil {
+		recordAudit(c, projectID, models.AuditEntityTransporter, transporterID, models.AuditActionUpdate,
+			"Updated transporter "+updated.CompanyName, existing, updated)
+	}
+
 	c.Response().Header().Set("HX-Trigger", "transporterChanged")
 	return components.RenderOK(c, htmxtransporters.TransporterFormSuccess(htmxtransporters.TransporterFormSuccessProps{
 		Message: "Transporter updated successfully",
@@ -245,6 +253,14 @@ func ToggleTransporterStatus(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to update status"})
 	}
 
+	statusSummary := "Deactivated transporter "
+	if !transporter.IsActive {
+		statusSummary = "Activated transporter "
+	}
+	recordAudit(c, projectID, models.AuditEntityTransporter, transporterID, models.AuditActionUpdate,
+		statusSummary+transporter.CompanyName,
+		map[string]interface{}{"is_active": transporter.IsActive}, map[string]interface{}{"is_active": !transporter.IsActive})
+
 	c.Response().Header().Set("HX-Trigger", "transporterChanged")
 	return c.String(http.StatusOK, "")
 }
@@ -293,6 +309,12 @@ func ShowTransporterDetail(c echo.Context) error {
 // saveVehicleDocument saves an uploaded vehicle document file and returns the URL path.
 // Returns "" if no file was provided.
 func saveVehicleDocument(c echo.Context, fieldName string, vehicleID int, prefix string) (string, error) {
+	return saveUploadedDocument(c, fieldName, "vehicles", fmt.Sprintf("%s_%d", prefix, vehicleID))
+}
+
+// saveUploadedDocument saves an uploaded JPG, PNG or PDF (max 5 MB) under subdir of
+// the upload root and returns its URL path. Returns "" if no file was provided.
+func saveUploadedDocument(c echo.Context, fieldName, subdir, name string) (string, error) {
 	fh, err := c.FormFile(fieldName)
 	if err != nil {
 		// No file provided — not an error
@@ -314,12 +336,12 @@ func saveVehicleDocument(c echo.Context, fieldName string, vehicleID int, prefix
 	if uploadRoot == "" {
 		uploadRoot = "./static/uploads"
 	}
-	dir := filepath.Join(uploadRoot, "vehicles")
+	dir := filepath.Join(uploadRoot, subdir)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return "", fmt.Errorf("cannot create upload directory: %w", err)
 	}
 
-	filename := fmt.Sprintf("%s_%d_%d%s", prefix, vehicleID, time.Now().UnixMilli(), ext)
+	filename := fmt.Sprintf("%s_%d%s", name, time.Now().UnixMilli(), ext)
 	dst := filepath.Join(dir, filename)
 
 	src, err := fh.Open()
@@ -338,7 +360,7 @@ func saveVehicleDocument(c echo.Context, fieldName string, vehicleID int, prefix
 		return "", fmt.Errorf("cannot save file: %w", err)
 	}
 
-	return "/static/uploads/vehicles/" + filename, nil
+	return "/static/uploads/" + subdir + "/" + filename, nil
 }
 
 func AddVehicleHandler(c echo.Context) error {
@@ -404,6 +426,9 @@ func AddVehicleHandler(c echo.Context) error {
 		}
 	}
 
+	recordAudit(c, projectID, models.AuditEntityTransporter, transporterID, models.AuditActionUpdate,
+		fmt.Sprintf("Added vehicle %s to %s", vehicle.VehicleNumber, transporter.CompanyName), nil, vehicle)
+
 	vehicles, _ := database.GetVehiclesByTransporterID(transporterID)
 	return components.RenderOK(c, htmxtransporters.HTMXVehicleList(htmxtransporters.VehicleListProps{
 		Vehicles:      vehicles,
@@ -453,6 +478,9 @@ func RemoveVehicleHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": "Failed to remove vehicle"})
 	}
 
+	recordAudit(c, projectID, models.AuditEntityTransporter, transporterID, models.AuditActionUpdate,
+		fmt.Sprintf("Removed vehicle %s from %s", vehicle.VehicleNumber, transporter.CompanyName), vehicle, nil)
+
 	vehicles, _ := database.GetVehiclesByTransporterID(transporterID)
 	return components.RenderOK(c, htmxtransporters.HTMXVehicleList(htmxtransporters.VehicleListProps{
 		Vehicles:      vehicles,
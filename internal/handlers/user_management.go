@@ -130,6 +130,10 @@ func CreateUserHandler(c echo.Context) error {
 		}
 	}
 
+	assignedIDs, _ := database.GetAssignedProjectIDs(newUser.ID)
+	recordAudit(c, 0, models.AuditEntityUser, newUser.ID, models.AuditActionCreate,
+		"Created user "+newUser.Username, nil, userAuditState(newUser, assignedIDs))
+
 	auth.SetFlash(c.Request(), "success", "User created successfully")
 	c.Response().Header().Set("HX-Redirect", "/admin/users")
 	return c.String(http.StatusOK, "")
@@ -167,6 +171,9 @@ func UpdateUserHandler(c echo.Context) error {
 		return c.String(http.StatusNotFound, "User not found")
 	}
 
+	before := *formUser
+	beforeIDs, _ := database.GetAssignedProjectIDs(uid)
+
 	formUser.FullName = strings.TrimSpace(c.FormValue("full_name"))
 	formUser.Email = strings.TrimSpace(c.FormValue("email"))
 	formUser.Role = c.FormValue("role")
@@ -210,6 +217,10 @@ func UpdateUserHandler(c echo.Context) error {
 		}
 	}
 
+	afterIDs, _ := database.GetAssignedProjectIDs(uid)
+	recordAudit(c, 0, models.AuditEntityUser, uid, models.AuditActionUpdate,
+		"Updated user "+formUser.Username, userAuditState(&before, beforeIDs), userAuditState(formUser, afterIDs))
+
 	auth.SetFlash(c.Request(), "success", "User updated successfully")
 	c.Response().Header().Set("HX-Redirect", "/admin/users")
 	return c.String(http.StatusOK, "")
@@ -233,6 +244,13 @@ func ToggleUserStatusHandler(c echo.Context) error {
 		auth.SetFlash(c.Request(), "success", "User activated")
 	}
 
+	statusSummary := "Deactivated user "
+	if !targetUser.IsActive {
+		statusSummary = "Activated user "
+	}
+	recordAudit(c, 0, models.AuditEntityUser, uid, models.AuditActionUpdate, statusSummary+targetUser.Username,
+		map[string]interface{}{"is_active": targetUser.IsActive}, map[string]interface{}{"is_active": !targetUser.IsActive})
+
 	c.Response().Header().Set("HX-Redirect", "/admin/users")
 	return c.String(http.StatusOK, "")
 }
@@ -240,7 +258,7 @@ func ToggleUserStatusHandler(c echo.Context) error {
 func ResetUserPasswordHandler(c echo.Context) error {
 	uid, _ := strconv.Atoi(c.Param("uid"))
 
-	_, err := database.GetUserByID(uid)
+	targetUser, err := database.GetUserByID(uid)
 	if err != nil {
 		return c.String(http.StatusNotFound, "User not found")
 	}
@@ -262,7 +280,22 @@ func ResetUserPasswordHandler(c echo.Context) error {
 
 	_ = database.UpdateUserPassword(uid, hash)
 	slog.Info("User password reset", slog.Int("target_user_id", uid))
+	recordAudit(c, 0, models.AuditEntityUser, uid, models.AuditActionUpdate,
+		"Reset password for "+targetUser.Username, nil, nil)
 	auth.SetFlash(c.Request(), "success", "Password reset successfully")
 	c.Response().Header().Set("HX-Redirect", "/admin/users")
 	return c.String(http.StatusOK, "")
 }
+
+// userAuditState is the audited view of a user: the profile fields (the password
+// hash is never serialised) plus the assigned project IDs.
+func userAuditState(u *models.User, projectIDs []int) map[string]interface{} {
+	return map[string]interface{}{
+		"username":    u.Username,
+		"full_name":   u.FullName,
+		"email":       u.Email,
+		"role":        u.Role,
+		"is_active":   u.IsActive,
+		"project_ids": projectIDs,
+	}
+}
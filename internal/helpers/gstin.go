@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// gstinCharset is the base-36 alphabet the GSTIN check digit is computed over.
+const gstinCharset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// gstinRe matches the GSTIN layout: state code, PAN, entity number, a default
+// letter (Z for regular taxpayers) and the check digit.
+var gstinRe = regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z][A-Z0-9][0-9A-Z]$`)
+
+// ValidateGSTIN checks a GSTIN's layout, state code and check digit. The GSTIN
+// must already be upper-cased; an empty string is reported as missing.
+func ValidateGSTIN(gstin string) error {
+	if gstin == "" {
+		return errors.New("GSTIN is missing")
+	}
+	if len(gstin) != 15 {
+		return fmt.Errorf("GSTIN must be exactly 15 characters, got %d", len(gstin))
+	}
+	if !gstinRe.MatchString(gstin) {
+		return errors.New("GSTIN must be a 2-digit state code, a 10-character PAN and 3 more letters or digits")
+	}
+	if code, _ := strconv.Atoi(gstin[:2]); (code < 1 || code > 38) && code != 97 {
+		return fmt.Errorf("GSTIN state code %s does not exist", gstin[:2])
+	}
+	if want := GSTINCheckDigit(gstin); gstin[14] != want {
+		return fmt.Errorf("GSTIN check digit is wrong (expected %c); check for a typo", want)
+	}
+	return nil
+}
+
+// GSTINCheckDigit computes the 15th character of a GSTIN from its first 14.
+// Characters outside the GSTIN alphabet yield 0.
+func GSTINCheckDigit(gstin string) byte {
+	if len(gstin) < 14 {
+		return 0
+	}
+	sum := 0
+	for i := 0; i < 14; i++ {
+		v := strings.IndexByte(gstinCharset, gstin[i])
+		if v < 0 {
+			return 0
+		}
+		factor := 1
+		if i%2 == 1 {
+			factor = 2
+		}
+		p := v * factor
+		sum += p/36 + p%36
+	}
+	return gstinCharset[(36-sum%36)%36]
+}
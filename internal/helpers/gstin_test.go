@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateGSTIN(t *testing.T) {
+	tests := []struct {
+		name    string
+		gstin   string
+		wantErr string
+	}{
+		{"valid", "36AACCF9742K1Z8", ""},
+		{"valid letter check digit", "27AAPFU0939F1ZV", ""},
+		{"missing", "", "missing"},
+		{"too short", "36AACCF9742K1Z", "15 characters"},
+		{"lowercase", "36aaccf9742k1z8", "state code, a 10-character PAN"},
+		{"unknown state", "99AACCF9742K1Z8", "state code 99"},
+		{"typo in PAN", "36AACCF9724K1Z8", "check digit"},
+		{"wrong check digit", "27AABCU9603R1ZM", "expected N"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGSTIN(tt.gstin)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateGSTIN(%q) = %v, want nil", tt.gstin, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateGSTIN(%q) = %v, want error containing %q", tt.gstin, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGSTINValidatorTag(t *testing.T) {
+	type form struct {
+		GSTIN string `json:"gstin" validate:"omitempty,gstin"`
+	}
+	if errs := ValidateStruct(&form{}); len(errs) != 0 {
+		t.Errorf("empty GSTIN: %v", errs)
+	}
+	if errs := ValidateStruct(&form{GSTIN: "36AACCF9742K1Z8"}); len(errs) != 0 {
+		t.Errorf("valid GSTIN: %v", errs)
+	}
+	errs := ValidateStruct(&form{GSTIN: "36AACCF9742K1Z9"})
+	if !strings.Contains(errs["gstin"], "check digit") {
+		t.Errorf("bad check digit: %v", errs)
+	}
+}
@@ -0,0 +1,131 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxSerialRange caps how many serials one range may expand to, so a typo
+// such as ABC0001-ABC9999999 cannot flood a DC.
+const MaxSerialRange = 10000
+
+// maxSerialRangeDigits is the longest number a range end may carry, enough
+// for IMEIs and small enough for the browser's live count to match.
+const maxSerialRangeDigits = 15
+
+// ParseSerialInput turns pasted serial numbers into a list, in input order.
+// Serials may be separated by new lines, tabs, commas or semicolons, so a
+// column or row copied from Excel works as is; a first line that is a serial
+// column header (Serial, S/N, Serial No, ...) is skipped, and quotes around
+// cells are dropped. A range such as ABC000100-ABC000599 is expanded when both ends share
+// the same prefix and digit width, keeping the zero padding, or the same
+// prefix and no padding (ABC8-ABC12); anything else with a hyphen, such as
+// SN-001, is a single serial.
+//
+// Duplicates are kept for the caller to report. A range larger than
+// MaxSerialRange is left unexpanded and reported in the returned error.
+func ParseSerialInput(raw string) ([]string, error) {
+	var serials []string
+	var firstErr error
+	lines := strings.Split(strings.ReplaceAll(raw, "\r", "\n"), "\n")
+	header := true
+	for _, line := range lines {
+		cells := strings.FieldsFunc(line, func(r rune) bool {
+			return r == '\t' || r == ',' || r == ';'
+		})
+		var tokens []string
+		for _, cell := range cells {
+			cell = strings.Trim(strings.TrimSpace(cell), `"'`)
+			if cell = strings.TrimSpace(cell); cell != "" {
+				tokens = append(tokens, cell)
+			}
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		if header {
+			header = false
+			if isSerialHeader(tokens) {
+				continue
+			}
+		}
+		for _, tok := range tokens {
+			expanded, err := expandSerialRange(tok)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			serials = append(serials, expanded...)
+		}
+	}
+	return serials, firstErr
+}
+
+// serialHeaders are the column headings, lower-cased without dots or colons,
+// a pasted first line may carry instead of a serial.
+var serialHeaders = map[string]bool{
+	"serial": true, "serials": true, "s/n": true, "sn": true,
+	"serial no": true, "serial nos": true, "serial number": true, "serial numbers": true,
+	"sr no": true, "sl no": true,
+}
+
+// isSerialHeader reports whether every cell of a line is a serial column
+// heading, so a serial that merely has no digits is never dropped.
+func isSerialHeader(tokens []string) bool {
+	for _, tok := range tokens {
+		tok = strings.NewReplacer(".", " ", ":", " ", "_", " ", "#", " ").Replace(strings.ToLower(tok))
+		if !serialHeaders[strings.Join(strings.Fields(tok), " ")] {
+			return false
+		}
+	}
+	return true
+}
+
+// expandSerialRange expands tok when it is a range, returning it alone when
+// it is not.
+func expandSerialRange(tok string) ([]string, error) {
+	for i := strings.IndexByte(tok, '-'); i >= 0; {
+		prefix, from, width, ok := splitSerialEnd(strings.TrimSpace(tok[:i]))
+		prefix2, to, width2, ok2 := splitSerialEnd(strings.TrimSpace(tok[i+1:]))
+		// Unpadded ends may differ in width (ABC8-ABC12), but only behind a
+		// prefix, so a date-like serial such as 2023-10001 stays whole.
+		padded := width > len(strconv.FormatInt(from, 10))
+		if ok && ok2 && prefix == prefix2 && from < to && (width == width2 || (prefix != "" && !padded)) {
+			if n := to - from + 1; n > MaxSerialRange {
+				return []string{tok}, fmt.Errorf("range %s has %d serials, more than the %d allowed in one range", tok, n, MaxSerialRange)
+			}
+			if width != width2 {
+				width = 0
+			}
+			out := make([]string, 0, to-from+1)
+			for n := from; n <= to; n++ {
+				out = append(out, fmt.Sprintf("%s%0*d", prefix, width, n))
+			}
+			return out, nil
+		}
+		next := strings.IndexByte(tok[i+1:], '-')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return []string{tok}, nil
+}
+
+// splitSerialEnd splits one end of a range into its prefix and trailing
+// number, reporting the number's digit width.
+func splitSerialEnd(s string) (prefix string, n int64, width int, ok bool) {
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+	digits := s[i:]
+	if digits == "" || len(digits) > maxSerialRangeDigits {
+		return "", 0, 0, false
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return "", 0, 0, false
+	}
+	return s[:i], n, len(digits), true
+}
@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSerialInput(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{"one per line", "SN1\n SN2 \n\nSN3\n", []string{"SN1", "SN2", "SN3"}},
+		{"padded range", "ABC000098-ABC000101", []string{"ABC000098", "ABC000099", "ABC000100", "ABC000101"}},
+		{"range mixed with singles", "X1\nABC08 - ABC10\nX2", []string{"X1", "ABC08", "ABC09", "ABC10", "X2"}},
+		{"unpadded range behind a prefix", "ABC8-ABC11", []string{"ABC8", "ABC9", "ABC10", "ABC11"}},
+		{"hyphenated prefix", "SN-001-SN-003", []string{"SN-001", "SN-002", "SN-003"}},
+		{"hyphenated single serial", "SN-001", []string{"SN-001"}},
+		{"date-like serial", "2023-10001", []string{"2023-10001"}},
+		{"descending is a single serial", "2024-2023", []string{"2024-2023"}},
+		{"different prefixes", "AB01-CD05", []string{"AB01-CD05"}},
+		{"excel column with header", "Serial Number\r\nSN1\r\nSN2\r\n", []string{"SN1", "SN2"}},
+		{"excel row and quotes", "\"SN1\"\tSN2, SN3;SN4", []string{"SN1", "SN2", "SN3", "SN4"}},
+		{"numeric first line is data", "1001\n1002", []string{"1001", "1002"}},
+		{"header variants", "S/N\nSN1", []string{"SN1"}},
+		{"header with punctuation", "Serial No.:\nSN1", []string{"SN1"}},
+		{"digitless first serial is data", "ABCDEF\nSN1", []string{"ABCDEF", "SN1"}},
+		{"duplicates kept", "SN1\nSN1", []string{"SN1", "SN1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSerialInput(tt.raw)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSerialInput(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSerialInput_RangeTooLarge(t *testing.T) {
+	got, err := ParseSerialInput("SN1\nA000001-A999999")
+	if err == nil || !strings.Contains(err.Error(), "A000001-A999999") {
+		t.Fatalf("want an error naming the range, got %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"SN1", "A000001-A999999"}) {
+		t.Errorf("want the range left unexpanded, got %v", got)
+	}
+}
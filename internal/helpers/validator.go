@@ -22,6 +22,11 @@ func init() {
 		}
 		return name
 	})
+
+	// gstin checks the layout and check digit of a GSTIN; pair it with omitempty.
+	_ = SharedValidator.RegisterValidation("gstin", func(fl validator.FieldLevel) bool {
+		return ValidateGSTIN(fl.Field().String()) == nil
+	})
 }
 
 // ValidateStruct validates a struct and returns errors in the legacy map[string]string format.
@@ -50,6 +55,13 @@ func convertValidationErrors(errors validator.ValidationErrors) map[string]strin
 		param := err.Param()
 
 		message := buildErrorMessage(fieldName, tag, param)
+		if tag == "gstin" {
+			if s, ok := err.Value().(string); ok {
+				if gstErr := ValidateGSTIN(s); gstErr != nil {
+					message = gstErr.Error()
+				}
+			}
+		}
 		result[fieldName] = message
 	}
 
@@ -82,6 +94,8 @@ func buildErrorMessage(fieldName, tag, param string) string {
 		return fmt.Sprintf("%s must be one of: %s", friendlyName, strings.ReplaceAll(param, " ", ", "))
 	case "numeric":
 		return fmt.Sprintf("%s must be a number", friendlyName)
+	case "gstin":
+		return fmt.Sprintf("%s is not a valid GSTIN", friendlyName)
 	default:
 		return fmt.Sprintf("%s is invalid (%s)", friendlyName, tag)
 	}
@@ -0,0 +1,134 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Allow issued DCs to be cancelled. The DC row (and its number) is kept;
+-- cancellation metadata records who cancelled it, when and why.
+-- SQLite requires table recreation to modify CHECK constraints.
+
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE delivery_challans_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    dc_number TEXT NOT NULL,
+    dc_type TEXT NOT NULL CHECK(dc_type IN ('transit', 'official', 'transfer')),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK(status IN ('draft', 'issued', 'splitting', 'split', 'cancelled')),
+    template_id INTEGER,
+    bill_to_address_id INTEGER,
+    ship_to_address_id INTEGER NOT NULL,
+    challan_date DATE,
+    issued_at DATETIME,
+    issued_by INTEGER,
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    bundle_id INTEGER REFERENCES dc_bundles(id),
+    shipment_group_id INTEGER REFERENCES shipment_groups(id),
+    bill_from_address_id INTEGER,
+    dispatch_from_address_id INTEGER,
+    transfer_dc_id INTEGER REFERENCES transfer_dcs(id),
+    cancelled_at DATETIME,
+    cancelled_by INTEGER,
+    cancellation_reason TEXT,
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (template_id) REFERENCES dc_templates(id) ON DELETE SET NULL,
+    FOREIGN KEY (bill_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (ship_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (issued_by) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (cancelled_by) REFERENCES users(id),
+    UNIQUE(project_id, dc_number)
+);
+
+INSERT INTO delivery_challans_new (
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id
+)
+SELECT
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id
+FROM delivery_challans;
+DROP TABLE delivery_challans;
+ALTER TABLE delivery_challans_new RENAME TO delivery_challans;
+
+CREATE INDEX idx_delivery_challans_project_id ON delivery_challans(project_id);
+CREATE INDEX idx_delivery_challans_dc_number ON delivery_challans(dc_number);
+CREATE INDEX idx_delivery_challans_status ON delivery_challans(status);
+CREATE INDEX idx_delivery_challans_dc_type ON delivery_challans(dc_type);
+CREATE INDEX idx_delivery_challans_created_by ON delivery_challans(created_by);
+CREATE INDEX IF NOT EXISTS idx_delivery_challans_bundle_id ON delivery_challans(bundle_id);
+
+-- Serial numbers on a cancelled DC are released rather than deleted so the
+-- cancelled DC still prints what it carried. Uniqueness only applies to
+-- serials that are still held by a live DC.
+ALTER TABLE serial_numbers ADD COLUMN released_at DATETIME;
+DROP INDEX IF EXISTS idx_serial_numbers_project_product_serial;
+CREATE UNIQUE INDEX idx_serial_numbers_project_product_serial
+    ON serial_numbers(project_id, product_id, serial_number)
+    WHERE released_at IS NULL;
+
+PRAGMA foreign_keys = ON;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+
+PRAGMA foreign_keys = OFF;
+
+DELETE FROM serial_numbers WHERE released_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_serial_numbers_project_product_serial;
+CREATE UNIQUE INDEX idx_serial_numbers_project_product_serial
+    ON serial_numbers(project_id, product_id, serial_number);
+ALTER TABLE serial_numbers DROP COLUMN released_at;
+
+CREATE TABLE delivery_challans_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    dc_number TEXT NOT NULL,
+    dc_type TEXT NOT NULL CHECK(dc_type IN ('transit', 'official', 'transfer')),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK(status IN ('draft', 'issued', 'splitting', 'split')),
+    template_id INTEGER,
+    bill_to_address_id INTEGER,
+    ship_to_address_id INTEGER NOT NULL,
+    challan_date DATE,
+    issued_at DATETIME,
+    issued_by INTEGER,
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    bundle_id INTEGER REFERENCES dc_bundles(id),
+    shipment_group_id INTEGER REFERENCES shipment_groups(id),
+    bill_from_address_id INTEGER,
+    dispatch_from_address_id INTEGER,
+    transfer_dc_id INTEGER REFERENCES transfer_dcs(id),
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (template_id) REFERENCES dc_templates(id) ON DELETE SET NULL,
+    FOREIGN KEY (bill_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (ship_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (issued_by) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    UNIQUE(project_id, dc_number)
+);
+
+-- Cancelled DCs fall back to issued so their numbers remain accounted for.
+INSERT INTO delivery_challans_old
+SELECT
+    id, project_id, dc_number, dc_type,
+    CASE WHEN status = 'cancelled' THEN 'issued' ELSE status END,
+    template_id, bill_to_address_id, ship_to_address_id, challan_date, issued_at,
+    issued_by, created_by, created_at, updated_at, bundle_id, shipment_group_id,
+    bill_from_address_id, dispatch_from_address_id, transfer_dc_id
+FROM delivery_challans;
+DROP TABLE delivery_challans;
+ALTER TABLE delivery_challans_old RENAME TO delivery_challans;
+
+CREATE INDEX idx_delivery_challans_project_id ON delivery_challans(project_id);
+CREATE INDEX idx_delivery_challans_dc_number ON delivery_challans(dc_number);
+CREATE INDEX idx_delivery_challans_status ON delivery_challans(status);
+CREATE INDEX idx_delivery_challans_dc_type ON delivery_challans(dc_type);
+CREATE INDEX idx_delivery_challans_created_by ON delivery_challans(created_by);
+CREATE INDEX IF NOT EXISTS idx_delivery_challans_bundle_id ON delivery_challans(bundle_id);
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Issued DCs can be amended: each amendment bumps delivery_challans.revision
+-- (printed as a "/R<n>" suffix on the DC number) and the superseded state is
+-- stored read-only in dc_revisions.
+ALTER TABLE delivery_challans ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS dc_revisions (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    dc_id            INTEGER NOT NULL REFERENCES delivery_challans(id) ON DELETE CASCADE,
+    revision         INTEGER NOT NULL,
+    dc_number        TEXT NOT NULL,
+    snapshot_json    TEXT NOT NULL,
+    amendment_reason TEXT NOT NULL,
+    amended_by       INTEGER REFERENCES users(id),
+    amended_at       DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(dc_id, revision)
+);
+CREATE INDEX idx_dc_revisions_dc_id ON dc_revisions(dc_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_dc_revisions_dc_id;
+DROP TABLE IF EXISTS dc_revisions;
+ALTER TABLE delivery_challans DROP COLUMN revision;
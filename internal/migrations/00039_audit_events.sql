@@ -0,0 +1,23 @@
+-- +goose Up
+-- Project-wide audit trail. project_id is NULL for global entities (users).
+-- changes_json holds a list of {field, before, after} for the fields that changed.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id   INTEGER REFERENCES projects(id) ON DELETE SET NULL,
+    user_id      INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    entity_type  TEXT NOT NULL,
+    entity_id    INTEGER NOT NULL DEFAULT 0,
+    action       TEXT NOT NULL,
+    summary      TEXT NOT NULL DEFAULT '',
+    changes_json TEXT NOT NULL DEFAULT '[]',
+    created_at   DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_audit_events_project_created ON audit_events(project_id, created_at);
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id);
+CREATE INDEX idx_audit_events_created ON audit_events(created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_audit_events_created;
+DROP INDEX IF EXISTS idx_audit_events_entity;
+DROP INDEX IF EXISTS idx_audit_events_project_created;
+DROP TABLE IF EXISTS audit_events;
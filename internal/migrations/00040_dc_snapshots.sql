@@ -0,0 +1,12 @@
+-- +goose Up
+-- Issued DCs are printed from the master data they were issued with: project and
+-- company header, resolved addresses, print column configs and product fields are
+-- frozen here at issue so later edits to addresses or products do not change them.
+CREATE TABLE IF NOT EXISTS dc_snapshots (
+    dc_id         INTEGER PRIMARY KEY REFERENCES delivery_challans(id) ON DELETE CASCADE,
+    snapshot_json TEXT NOT NULL,
+    captured_at   DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS dc_snapshots;
@@ -0,0 +1,146 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Official DCs can record proof of delivery: a scanned signed acknowledgement
+-- with the received date and receiver. Recording a POD moves an issued DC to
+-- 'delivered', or to 'partially_delivered' when only part of the goods arrived.
+-- SQLite requires table recreation to modify CHECK constraints.
+
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE delivery_challans_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    dc_number TEXT NOT NULL,
+    dc_type TEXT NOT NULL CHECK(dc_type IN ('transit', 'official', 'transfer')),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK(status IN ('draft', 'issued', 'splitting', 'split', 'cancelled', 'delivered', 'partially_delivered')),
+    template_id INTEGER,
+    bill_to_address_id INTEGER,
+    ship_to_address_id INTEGER NOT NULL,
+    challan_date DATE,
+    issued_at DATETIME,
+    issued_by INTEGER,
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    bundle_id INTEGER REFERENCES dc_bundles(id),
+    shipment_group_id INTEGER REFERENCES shipment_groups(id),
+    bill_from_address_id INTEGER,
+    dispatch_from_address_id INTEGER,
+    transfer_dc_id INTEGER REFERENCES transfer_dcs(id),
+    cancelled_at DATETIME,
+    cancelled_by INTEGER,
+    cancellation_reason TEXT,
+    revision INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (template_id) REFERENCES dc_templates(id) ON DELETE SET NULL,
+    FOREIGN KEY (bill_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (ship_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (issued_by) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (cancelled_by) REFERENCES users(id),
+    UNIQUE(project_id, dc_number)
+);
+
+INSERT INTO delivery_challans_new (
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id, cancelled_at, cancelled_by,
+    cancellation_reason, revision
+)
+SELECT
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id, cancelled_at, cancelled_by,
+    cancellation_reason, revision
+FROM delivery_challans;
+DROP TABLE delivery_challans;
+ALTER TABLE delivery_challans_new RENAME TO delivery_challans;
+
+CREATE INDEX idx_delivery_challans_project_id ON delivery_challans(project_id);
+CREATE INDEX idx_delivery_challans_dc_number ON delivery_challans(dc_number);
+CREATE INDEX idx_delivery_challans_status ON delivery_challans(status);
+CREATE INDEX idx_delivery_challans_dc_type ON delivery_challans(dc_type);
+CREATE INDEX idx_delivery_challans_created_by ON delivery_challans(created_by);
+CREATE INDEX IF NOT EXISTS idx_delivery_challans_bundle_id ON delivery_challans(bundle_id);
+
+CREATE TABLE IF NOT EXISTS dc_proofs_of_delivery (
+    id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+    dc_id                INTEGER NOT NULL REFERENCES delivery_challans(id) ON DELETE CASCADE,
+    received_date        DATE NOT NULL,
+    receiver_name        TEXT NOT NULL,
+    receiver_designation TEXT NOT NULL DEFAULT '',
+    is_partial           INTEGER NOT NULL DEFAULT 0,
+    remarks              TEXT NOT NULL DEFAULT '',
+    file_path            TEXT NOT NULL,
+    recorded_by          INTEGER REFERENCES users(id),
+    recorded_at          DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_dc_proofs_of_delivery_dc_id ON dc_proofs_of_delivery(dc_id);
+
+PRAGMA foreign_keys = ON;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+
+PRAGMA foreign_keys = OFF;
+
+DROP INDEX IF EXISTS idx_dc_proofs_of_delivery_dc_id;
+DROP TABLE IF EXISTS dc_proofs_of_delivery;
+
+CREATE TABLE delivery_challans_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    dc_number TEXT NOT NULL,
+    dc_type TEXT NOT NULL CHECK(dc_type IN ('transit', 'official', 'transfer')),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK(status IN ('draft', 'issued', 'splitting', 'split', 'cancelled')),
+    template_id INTEGER,
+    bill_to_address_id INTEGER,
+    ship_to_address_id INTEGER NOT NULL,
+    challan_date DATE,
+    issued_at DATETIME,
+    issued_by INTEGER,
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    bundle_id INTEGER REFERENCES dc_bundles(id),
+    shipment_group_id INTEGER REFERENCES shipment_groups(id),
+    bill_from_address_id INTEGER,
+    dispatch_from_address_id INTEGER,
+    transfer_dc_id INTEGER REFERENCES transfer_dcs(id),
+    cancelled_at DATETIME,
+    cancelled_by INTEGER,
+    cancellation_reason TEXT,
+    revision INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (template_id) REFERENCES dc_templates(id) ON DELETE SET NULL,
+    FOREIGN KEY (bill_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (ship_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (issued_by) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (cancelled_by) REFERENCES users(id),
+    UNIQUE(project_id, dc_number)
+);
+
+-- Delivered DCs fall back to issued.
+INSERT INTO delivery_challans_old
+SELECT
+    id, project_id, dc_number, dc_type,
+    CASE WHEN status IN ('delivered', 'partially_delivered') THEN 'issued' ELSE status END,
+    template_id, bill_to_address_id, ship_to_address_id, challan_date, issued_at,
+    issued_by, created_by, created_at, updated_at, bundle_id, shipment_group_id,
+    bill_from_address_id, dispatch_from_address_id, transfer_dc_id, cancelled_at,
+    cancelled_by, cancellation_reason, revision
+FROM delivery_challans;
+DROP TABLE delivery_challans;
+ALTER TABLE delivery_challans_old RENAME TO delivery_challans;
+
+CREATE INDEX idx_delivery_challans_project_id ON delivery_challans(project_id);
+CREATE INDEX idx_delivery_challans_dc_number ON delivery_challans(dc_number);
+CREATE INDEX idx_delivery_challans_status ON delivery_challans(status);
+CREATE INDEX idx_delivery_challans_dc_type ON delivery_challans(dc_type);
+CREATE INDEX idx_delivery_challans_created_by ON delivery_challans(created_by);
+CREATE INDEX IF NOT EXISTS idx_delivery_challans_bundle_id ON delivery_challans(bundle_id);
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,40 @@
+-- +goose Up
+-- Goods receipt reported by the receiver of an official DC: per line item, how many
+-- units arrived in good condition, how many were short and how many were damaged,
+-- plus the serial numbers reported damaged or missing.
+CREATE TABLE IF NOT EXISTS dc_receipts (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    dc_id         INTEGER NOT NULL UNIQUE REFERENCES delivery_challans(id) ON DELETE CASCADE,
+    received_date DATE NOT NULL,
+    remarks       TEXT NOT NULL DEFAULT '',
+    recorded_by   INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    recorded_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at    DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS dc_receipt_lines (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    receipt_id   INTEGER NOT NULL REFERENCES dc_receipts(id) ON DELETE CASCADE,
+    line_item_id INTEGER NOT NULL REFERENCES dc_line_items(id) ON DELETE CASCADE,
+    received_qty INTEGER NOT NULL DEFAULT 0 CHECK (received_qty >= 0),
+    short_qty    INTEGER NOT NULL DEFAULT 0 CHECK (short_qty >= 0),
+    damaged_qty  INTEGER NOT NULL DEFAULT 0 CHECK (damaged_qty >= 0),
+    UNIQUE(receipt_id, line_item_id)
+);
+
+CREATE TABLE IF NOT EXISTS dc_receipt_serials (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    receipt_id    INTEGER NOT NULL REFERENCES dc_receipts(id) ON DELETE CASCADE,
+    line_item_id  INTEGER NOT NULL REFERENCES dc_line_items(id) ON DELETE CASCADE,
+    serial_number TEXT NOT NULL,
+    condition     TEXT NOT NULL CHECK (condition IN ('damaged', 'missing')),
+    UNIQUE(line_item_id, serial_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_dc_receipt_lines_receipt_id ON dc_receipt_lines(receipt_id);
+CREATE INDEX IF NOT EXISTS idx_dc_receipt_serials_receipt_id ON dc_receipt_serials(receipt_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS dc_receipt_serials;
+DROP TABLE IF EXISTS dc_receipt_lines;
+DROP TABLE IF EXISTS dc_receipts;
@@ -0,0 +1,194 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Return DCs (material return notes) record equipment coming back from a ship-to
+-- location against an earlier DC. They are numbered in their own 'return' series
+-- and list the serials taken back, which are released for re-dispatch.
+-- SQLite requires table recreation to modify CHECK constraints.
+
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE dc_number_sequences_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    dc_type TEXT NOT NULL CHECK(dc_type IN ('transit', 'official', 'transfer', 'return')),
+    financial_year TEXT NOT NULL,
+    next_sequence INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, dc_type, financial_year)
+);
+INSERT INTO dc_number_sequences_new SELECT * FROM dc_number_sequences;
+DROP TABLE dc_number_sequences;
+ALTER TABLE dc_number_sequences_new RENAME TO dc_number_sequences;
+CREATE INDEX IF NOT EXISTS idx_dc_sequences_lookup ON dc_number_sequences(project_id, dc_type, financial_year);
+
+CREATE TABLE delivery_challans_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    dc_number TEXT NOT NULL,
+    dc_type TEXT NOT NULL CHECK(dc_type IN ('transit', 'official', 'transfer', 'return')),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK(status IN ('draft', 'issued', 'splitting', 'split', 'cancelled', 'delivered', 'partially_delivered')),
+    template_id INTEGER,
+    bill_to_address_id INTEGER,
+    ship_to_address_id INTEGER NOT NULL,
+    challan_date DATE,
+    issued_at DATETIME,
+    issued_by INTEGER,
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    bundle_id INTEGER REFERENCES dc_bundles(id),
+    shipment_group_id INTEGER REFERENCES shipment_groups(id),
+    bill_from_address_id INTEGER,
+    dispatch_from_address_id INTEGER,
+    transfer_dc_id INTEGER REFERENCES transfer_dcs(id),
+    cancelled_at DATETIME,
+    cancelled_by INTEGER,
+    cancellation_reason TEXT,
+    revision INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (template_id) REFERENCES dc_templates(id) ON DELETE SET NULL,
+    FOREIGN KEY (bill_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (ship_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (issued_by) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (cancelled_by) REFERENCES users(id),
+    UNIQUE(project_id, dc_number)
+);
+
+INSERT INTO delivery_challans_new (
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id, cancelled_at, cancelled_by,
+    cancellation_reason, revision
+)
+SELECT
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id, cancelled_at, cancelled_by,
+    cancellation_reason, revision
+FROM delivery_challans;
+DROP TABLE delivery_challans;
+ALTER TABLE delivery_challans_new RENAME TO delivery_challans;
+
+CREATE INDEX idx_delivery_challans_project_id ON delivery_challans(project_id);
+CREATE INDEX idx_delivery_challans_dc_number ON delivery_challans(dc_number);
+CREATE INDEX idx_delivery_challans_status ON delivery_challans(status);
+CREATE INDEX idx_delivery_challans_dc_type ON delivery_challans(dc_type);
+CREATE INDEX idx_delivery_challans_created_by ON delivery_challans(created_by);
+CREATE INDEX IF NOT EXISTS idx_delivery_challans_bundle_id ON delivery_challans(bundle_id);
+
+CREATE TABLE IF NOT EXISTS return_dcs (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    dc_id          INTEGER NOT NULL UNIQUE REFERENCES delivery_challans(id) ON DELETE CASCADE,
+    original_dc_id INTEGER NOT NULL REFERENCES delivery_challans(id),
+    reason         TEXT NOT NULL CHECK(reason IN ('faulty', 'excess', 'wrong_model')),
+    remarks        TEXT NOT NULL DEFAULT '',
+    created_at     DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_return_dcs_original_dc_id ON return_dcs(original_dc_id);
+
+CREATE TABLE IF NOT EXISTS return_dc_serials (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    return_dc_id     INTEGER NOT NULL REFERENCES return_dcs(id) ON DELETE CASCADE,
+    serial_number_id INTEGER NOT NULL REFERENCES serial_numbers(id),
+    line_item_id     INTEGER NOT NULL,
+    product_id       INTEGER,
+    serial_number    TEXT NOT NULL,
+    UNIQUE(serial_number_id)
+);
+CREATE INDEX idx_return_dc_serials_return_dc_id ON return_dc_serials(return_dc_id);
+CREATE INDEX idx_return_dc_serials_serial_number ON return_dc_serials(serial_number);
+
+PRAGMA foreign_keys = ON;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+
+PRAGMA foreign_keys = OFF;
+
+DROP INDEX IF EXISTS idx_return_dc_serials_serial_number;
+DROP INDEX IF EXISTS idx_return_dc_serials_return_dc_id;
+DROP TABLE IF EXISTS return_dc_serials;
+DROP INDEX IF EXISTS idx_return_dcs_original_dc_id;
+DROP TABLE IF EXISTS return_dcs;
+DELETE FROM dc_snapshots WHERE dc_id IN (SELECT id FROM delivery_challans WHERE dc_type = 'return');
+
+CREATE TABLE dc_number_sequences_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    dc_type TEXT NOT NULL CHECK(dc_type IN ('transit', 'official', 'transfer')),
+    financial_year TEXT NOT NULL,
+    next_sequence INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    UNIQUE (project_id, dc_type, financial_year)
+);
+INSERT INTO dc_number_sequences_old SELECT * FROM dc_number_sequences WHERE dc_type != 'return';
+DROP TABLE dc_number_sequences;
+ALTER TABLE dc_number_sequences_old RENAME TO dc_number_sequences;
+CREATE INDEX IF NOT EXISTS idx_dc_sequences_lookup ON dc_number_sequences(project_id, dc_type, financial_year);
+
+CREATE TABLE delivery_challans_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    dc_number TEXT NOT NULL,
+    dc_type TEXT NOT NULL CHECK(dc_type IN ('transit', 'official', 'transfer')),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK(status IN ('draft', 'issued', 'splitting', 'split', 'cancelled', 'delivered', 'partially_delivered')),
+    template_id INTEGER,
+    bill_to_address_id INTEGER,
+    ship_to_address_id INTEGER NOT NULL,
+    challan_date DATE,
+    issued_at DATETIME,
+    issued_by INTEGER,
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    bundle_id INTEGER REFERENCES dc_bundles(id),
+    shipment_group_id INTEGER REFERENCES shipment_groups(id),
+    bill_from_address_id INTEGER,
+    dispatch_from_address_id INTEGER,
+    transfer_dc_id INTEGER REFERENCES transfer_dcs(id),
+    cancelled_at DATETIME,
+    cancelled_by INTEGER,
+    cancellation_reason TEXT,
+    revision INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (template_id) REFERENCES dc_templates(id) ON DELETE SET NULL,
+    FOREIGN KEY (bill_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (ship_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (issued_by) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (cancelled_by) REFERENCES users(id),
+    UNIQUE(project_id, dc_number)
+);
+
+INSERT INTO delivery_challans_old (
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id, cancelled_at, cancelled_by,
+    cancellation_reason, revision
+)
+SELECT
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id, cancelled_at, cancelled_by,
+    cancellation_reason, revision
+FROM delivery_challans WHERE dc_type != 'return';
+DROP TABLE delivery_challans;
+ALTER TABLE delivery_challans_old RENAME TO delivery_challans;
+
+CREATE INDEX idx_delivery_challans_project_id ON delivery_challans(project_id);
+CREATE INDEX idx_delivery_challans_dc_number ON delivery_challans(dc_number);
+CREATE INDEX idx_delivery_challans_status ON delivery_challans(status);
+CREATE INDEX idx_delivery_challans_dc_type ON delivery_challans(dc_type);
+CREATE INDEX idx_delivery_challans_created_by ON delivery_challans(created_by);
+CREATE INDEX IF NOT EXISTS idx_delivery_challans_bundle_id ON delivery_challans(bundle_id);
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,169 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Optional per-project approval step between draft and issued. When a project
+-- requires approval, DCs are submitted (pending_approval) and a project approver
+-- other than the submitter either issues them or rejects them with comments.
+-- SQLite requires table recreation to modify CHECK constraints.
+
+PRAGMA foreign_keys = OFF;
+
+ALTER TABLE projects ADD COLUMN require_approval INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS project_approvers (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(project_id, user_id)
+);
+CREATE INDEX idx_project_approvers_user_id ON project_approvers(user_id);
+
+CREATE TABLE IF NOT EXISTS dc_approvals (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id        INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    shipment_group_id INTEGER REFERENCES shipment_groups(id) ON DELETE CASCADE,
+    dc_id             INTEGER REFERENCES delivery_challans(id) ON DELETE CASCADE,
+    action            TEXT NOT NULL CHECK(action IN ('submit', 'approve', 'reject', 'reopen')),
+    comments          TEXT NOT NULL DEFAULT '',
+    user_id           INTEGER REFERENCES users(id),
+    created_at        DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((shipment_group_id IS NULL) != (dc_id IS NULL))
+);
+CREATE INDEX idx_dc_approvals_shipment_group_id ON dc_approvals(shipment_group_id);
+CREATE INDEX idx_dc_approvals_dc_id ON dc_approvals(dc_id);
+
+CREATE TABLE delivery_challans_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    dc_number TEXT NOT NULL,
+    dc_type TEXT NOT NULL CHECK(dc_type IN ('transit', 'official', 'transfer', 'return')),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK(status IN ('draft', 'pending_approval', 'rejected', 'issued', 'splitting', 'split', 'cancelled', 'delivered', 'partially_delivered')),
+    template_id INTEGER,
+    bill_to_address_id INTEGER,
+    ship_to_address_id INTEGER NOT NULL,
+    challan_date DATE,
+    issued_at DATETIME,
+    issued_by INTEGER,
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    bundle_id INTEGER REFERENCES dc_bundles(id),
+    shipment_group_id INTEGER REFERENCES shipment_groups(id),
+    bill_from_address_id INTEGER,
+    dispatch_from_address_id INTEGER,
+    transfer_dc_id INTEGER REFERENCES transfer_dcs(id),
+    cancelled_at DATETIME,
+    cancelled_by INTEGER,
+    cancellation_reason TEXT,
+    revision INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (template_id) REFERENCES dc_templates(id) ON DELETE SET NULL,
+    FOREIGN KEY (bill_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (ship_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (issued_by) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (cancelled_by) REFERENCES users(id),
+    UNIQUE(project_id, dc_number)
+);
+
+INSERT INTO delivery_challans_new (
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id, cancelled_at, cancelled_by,
+    cancellation_reason, revision
+)
+SELECT
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id, cancelled_at, cancelled_by,
+    cancellation_reason, revision
+FROM delivery_challans;
+DROP TABLE delivery_challans;
+ALTER TABLE delivery_challans_new RENAME TO delivery_challans;
+
+CREATE INDEX idx_delivery_challans_project_id ON delivery_challans(project_id);
+CREATE INDEX idx_delivery_challans_dc_number ON delivery_challans(dc_number);
+CREATE INDEX idx_delivery_challans_status ON delivery_challans(status);
+CREATE INDEX idx_delivery_challans_dc_type ON delivery_challans(dc_type);
+CREATE INDEX idx_delivery_challans_created_by ON delivery_challans(created_by);
+CREATE INDEX IF NOT EXISTS idx_delivery_challans_bundle_id ON delivery_challans(bundle_id);
+
+PRAGMA foreign_keys = ON;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+
+PRAGMA foreign_keys = OFF;
+
+DROP INDEX IF EXISTS idx_dc_approvals_dc_id;
+DROP INDEX IF EXISTS idx_dc_approvals_shipment_group_id;
+DROP TABLE IF EXISTS dc_approvals;
+DROP INDEX IF EXISTS idx_project_approvers_user_id;
+DROP TABLE IF EXISTS project_approvers;
+UPDATE shipment_groups SET status = 'draft' WHERE status IN ('pending_approval', 'rejected');
+
+CREATE TABLE delivery_challans_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    dc_number TEXT NOT NULL,
+    dc_type TEXT NOT NULL CHECK(dc_type IN ('transit', 'official', 'transfer', 'return')),
+    status TEXT NOT NULL DEFAULT 'draft' CHECK(status IN ('draft', 'issued', 'splitting', 'split', 'cancelled', 'delivered', 'partially_delivered')),
+    template_id INTEGER,
+    bill_to_address_id INTEGER,
+    ship_to_address_id INTEGER NOT NULL,
+    challan_date DATE,
+    issued_at DATETIME,
+    issued_by INTEGER,
+    created_by INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    bundle_id INTEGER REFERENCES dc_bundles(id),
+    shipment_group_id INTEGER REFERENCES shipment_groups(id),
+    bill_from_address_id INTEGER,
+    dispatch_from_address_id INTEGER,
+    transfer_dc_id INTEGER REFERENCES transfer_dcs(id),
+    cancelled_at DATETIME,
+    cancelled_by INTEGER,
+    cancellation_reason TEXT,
+    revision INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (template_id) REFERENCES dc_templates(id) ON DELETE SET NULL,
+    FOREIGN KEY (bill_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (ship_to_address_id) REFERENCES addresses(id),
+    FOREIGN KEY (issued_by) REFERENCES users(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (cancelled_by) REFERENCES users(id),
+    UNIQUE(project_id, dc_number)
+);
+
+INSERT INTO delivery_challans_old (
+    id, project_id, dc_number, dc_type, status, template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id, cancelled_at, cancelled_by,
+    cancellation_reason, revision
+)
+SELECT
+    id, project_id, dc_number, dc_type,
+    CASE WHEN status IN ('pending_approval', 'rejected') THEN 'draft' ELSE status END,
+    template_id, bill_to_address_id,
+    ship_to_address_id, challan_date, issued_at, issued_by, created_by, created_at,
+    updated_at, bundle_id, shipment_group_id, bill_from_address_id,
+    dispatch_from_address_id, transfer_dc_id, cancelled_at, cancelled_by,
+    cancellation_reason, revision
+FROM delivery_challans;
+DROP TABLE delivery_challans;
+ALTER TABLE delivery_challans_old RENAME TO delivery_challans;
+
+CREATE INDEX idx_delivery_challans_project_id ON delivery_challans(project_id);
+CREATE INDEX idx_delivery_challans_dc_number ON delivery_challans(dc_number);
+CREATE INDEX idx_delivery_challans_status ON delivery_challans(status);
+CREATE INDEX idx_delivery_challans_dc_type ON delivery_challans(dc_type);
+CREATE INDEX idx_delivery_challans_created_by ON delivery_challans(created_by);
+CREATE INDEX IF NOT EXISTS idx_delivery_challans_bundle_id ON delivery_challans(bundle_id);
+
+ALTER TABLE projects DROP COLUMN require_approval;
+
+PRAGMA foreign_keys = ON;
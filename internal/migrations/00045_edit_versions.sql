@@ -0,0 +1,15 @@
+-- +goose Up
+-- Optimistic locking for the edit wizards and master-data forms: every save
+-- bumps version, and a save carrying an older version is rejected as stale.
+ALTER TABLE shipment_groups ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE transfer_dcs ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE products ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE addresses ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE dc_templates ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE dc_templates DROP COLUMN version;
+ALTER TABLE addresses DROP COLUMN version;
+ALTER TABLE products DROP COLUMN version;
+ALTER TABLE transfer_dcs DROP COLUMN version;
+ALTER TABLE shipment_groups DROP COLUMN version;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Financial years closed against new DCs and edits, per project. Reopening a
+-- year deletes its row; both actions are kept in the audit log.
+CREATE TABLE IF NOT EXISTS financial_year_closures (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id     INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    financial_year TEXT NOT NULL,
+    closed_by      INTEGER REFERENCES users(id),
+    closed_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(project_id, financial_year)
+);
+
+-- When set, a challan date may not precede the latest issued challan date of
+-- the same DC type in its financial year.
+ALTER TABLE projects ADD COLUMN enforce_date_order INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE projects DROP COLUMN enforce_date_order;
+DROP TABLE IF EXISTS financial_year_closures;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Per-project names of the Tally masters a Delivery Note import posts to. A
+-- project without a row uses Tally's default ledger, group and godown names.
+CREATE TABLE IF NOT EXISTS tally_settings (
+    project_id      INTEGER PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    company_name    TEXT NOT NULL DEFAULT '',
+    voucher_type    TEXT NOT NULL DEFAULT 'Delivery Note',
+    sales_ledger    TEXT NOT NULL DEFAULT 'Sales',
+    cgst_ledger     TEXT NOT NULL DEFAULT 'Output CGST',
+    sgst_ledger     TEXT NOT NULL DEFAULT 'Output SGST',
+    igst_ledger     TEXT NOT NULL DEFAULT 'Output IGST',
+    party_group     TEXT NOT NULL DEFAULT 'Sundry Debtors',
+    godown          TEXT NOT NULL DEFAULT 'Main Location',
+    include_masters INTEGER NOT NULL DEFAULT 1,
+    updated_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS tally_settings;
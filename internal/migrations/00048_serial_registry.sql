@@ -0,0 +1,27 @@
+-- +goose Up
+-- Serial numbers stocked into a project from the OEM's packing lists, per
+-- product. Only the condition is stored: whether a serial is in stock,
+-- allocated, dispatched or returned is derived from serial_numbers and
+-- return_dc_serials.
+CREATE TABLE IF NOT EXISTS serial_registry (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id    INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    product_id    INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    serial_number TEXT NOT NULL,
+    condition     TEXT NOT NULL DEFAULT 'good' CHECK(condition IN ('good', 'damaged')),
+    source        TEXT NOT NULL DEFAULT '',
+    stocked_in_by INTEGER REFERENCES users(id),
+    created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(project_id, product_id, serial_number)
+);
+CREATE INDEX IF NOT EXISTS idx_serial_registry_serial_number ON serial_registry(serial_number);
+
+-- How the DC wizards treat serials missing from the registry: 'off' ignores
+-- the registry, 'warn' saves them with a warning and 'enforce' refuses them.
+ALTER TABLE projects ADD COLUMN serial_registry_mode TEXT NOT NULL DEFAULT 'off';
+
+-- +goose Down
+ALTER TABLE projects DROP COLUMN serial_registry_mode;
+DROP INDEX IF EXISTS idx_serial_registry_serial_number;
+DROP TABLE IF EXISTS serial_registry;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Optional per-product serial number format: a regular expression, a length
+-- range, a fixed prefix and a Luhn check digit. Empty or zero parts are not checked.
+ALTER TABLE products ADD COLUMN serial_pattern TEXT NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN serial_min_length INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN serial_max_length INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN serial_prefix TEXT NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN serial_luhn INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE products DROP COLUMN serial_luhn;
+ALTER TABLE products DROP COLUMN serial_prefix;
+ALTER TABLE products DROP COLUMN serial_max_length;
+ALTER TABLE products DROP COLUMN serial_min_length;
+ALTER TABLE products DROP COLUMN serial_pattern;
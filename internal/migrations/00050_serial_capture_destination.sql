@@ -0,0 +1,7 @@
+-- +goose Up
+-- Destination a serial was scanned for on the serial capture screen. Serials
+-- entered through the wizards leave it NULL.
+ALTER TABLE serial_numbers ADD COLUMN ship_to_address_id INTEGER;
+
+-- +goose Down
+ALTER TABLE serial_numbers DROP COLUMN ship_to_address_id;
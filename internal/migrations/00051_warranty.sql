@@ -0,0 +1,9 @@
+-- +goose Up
+-- Warranty period of a product, in months; 0 means no warranty is tracked.
+ALTER TABLE products ADD COLUMN warranty_months INTEGER NOT NULL DEFAULT 0;
+-- Project-wide warranty period overriding every product's; 0 keeps each product's own.
+ALTER TABLE projects ADD COLUMN warranty_months INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE projects DROP COLUMN warranty_months;
+ALTER TABLE products DROP COLUMN warranty_months;
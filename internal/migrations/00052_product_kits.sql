@@ -0,0 +1,17 @@
+-- +goose Up
+-- Bill of materials of kit products. A product with components is a kit: it
+-- is priced and shipped as one line, but serial numbers are captured for each
+-- component instead of the kit itself.
+CREATE TABLE IF NOT EXISTS product_kit_components (
+    id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+    kit_product_id       INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    component_product_id INTEGER NOT NULL REFERENCES products(id),
+    quantity             INTEGER NOT NULL DEFAULT 1 CHECK(quantity > 0),
+    line_order           INTEGER NOT NULL DEFAULT 0,
+    UNIQUE(kit_product_id, component_product_id)
+);
+CREATE INDEX IF NOT EXISTS idx_product_kit_components_component ON product_kit_components(component_product_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_product_kit_components_component;
+DROP TABLE IF EXISTS product_kit_components;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- The tender's bill of quantities: how many units of each product a project
+-- plans to deliver to each ship-to address.
+CREATE TABLE IF NOT EXISTS boq_plan_items (
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id         INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    ship_to_address_id INTEGER NOT NULL REFERENCES addresses(id) ON DELETE CASCADE,
+    product_id         INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    planned_quantity   INTEGER NOT NULL CHECK(planned_quantity >= 0),
+    created_at         DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(project_id, ship_to_address_id, product_id)
+);
+CREATE INDEX IF NOT EXISTS idx_boq_plan_items_product ON boq_plan_items(product_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_boq_plan_items_product;
+DROP TABLE IF EXISTS boq_plan_items;
@@ -71,6 +71,23 @@ func (a *Address) DisplayName() string {
 	return strings.Join(parts, " | ")
 }
 
+// Field returns the first non-empty data field matching one of names. Column names
+// are user-configured, so matching ignores case, spaces and punctuation.
+func (a *Address) Field(names ...string) string {
+	if a == nil {
+		return ""
+	}
+	for _, name := range names {
+		want := normaliseKey(name)
+		for k, v := range a.Data {
+			if normaliseKey(k) == want && strings.TrimSpace(v) != "" {
+				return strings.TrimSpace(v)
+			}
+		}
+	}
+	return ""
+}
+
 // FormatAddressJSON parses a raw JSON address_data string into a human-readable display name.
 // This is used when only the raw JSON string is available (e.g., from JOIN queries).
 func FormatAddressJSON(jsonStr string) string {
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Audit entity types.
+const (
+	AuditEntityProject         = "project"
+	AuditEntityProjectSettings = "project_settings"
+	AuditEntityProduct         = "product"
+	AuditEntityAddress         = "address"
+	AuditEntityAddressConfig   = "address_config"
+	AuditEntityTemplate        = "template"
+	AuditEntityTransporter     = "transporter"
+	AuditEntityUser            = "user"
+	AuditEntityShipmentGroup   = "shipment_group"
+	AuditEntityDC              = "delivery_challan"
+	AuditEntityTransferDC      = "transfer_dc"
+	AuditEntitySerial          = "serial"
+	AuditEntityBOQPlan         = "boq_plan"
+)
+
+// Audit actions.
+const (
+	AuditActionCreate   = "create"
+	AuditActionUpdate   = "update"
+	AuditActionDelete   = "delete"
+	AuditActionImport   = "import"
+	AuditActionIssue    = "issue"
+	AuditActionCancel   = "cancel"
+	AuditActionAmend    = "amend"
+	AuditActionSplit    = "split"
+	AuditActionDeliver  = "deliver"
+	AuditActionSubmit   = "submit"
+	AuditActionApprove  = "approve"
+	AuditActionReject   = "reject"
+	AuditActionCloseFY  = "close_year"
+	AuditActionReopenFY = "reopen_year"
+	AuditActionOverride = "override"
+)
+
+// AuditEntityTypes lists the entity types in the order shown in filters.
+var AuditEntityTypes = []string{
+	AuditEntityDC,
+	AuditEntityShipmentGroup,
+	AuditEntityTransferDC,
+	AuditEntityProduct,
+	AuditEntitySerial,
+	AuditEntityBOQPlan,
+	AuditEntityAddress,
+	AuditEntityAddressConfig,
+	AuditEntityTemplate,
+	AuditEntityTransporter,
+	AuditEntityProject,
+	AuditEntityProjectSettings,
+	AuditEntityUser,
+}
+
+// AuditActions lists the actions in the order shown in filters.
+var AuditActions = []string{
+	AuditActionCreate,
+	AuditActionUpdate,
+	AuditActionDelete,
+	AuditActionImport,
+	AuditActionIssue,
+	AuditActionCancel,
+	AuditActionAmend,
+	AuditActionSplit,
+	AuditActionDeliver,
+	AuditActionSubmit,
+	AuditActionApprove,
+	AuditActionReject,
+	AuditActionCloseFY,
+	AuditActionReopenFY,
+	AuditActionOverride,
+}
+
+// AuditEvent records one change made by a user.
+type AuditEvent struct {
+	ID         int           `json:"id"`
+	ProjectID  *int          `json:"project_id"` // nil for global entities such as users
+	UserID     *int          `json:"user_id"`
+	EntityType string        `json:"entity_type"`
+	EntityID   int           `json:"entity_id"`
+	Action     string        `json:"action"`
+	Summary    string        `json:"summary"`
+	Changes    []AuditChange `json:"changes"`
+	CreatedAt  time.Time     `json:"created_at"`
+
+	// Joined fields
+	UserName    string `json:"user_name"`
+	ProjectName string `json:"project_name"`
+}
+
+// AuditChange is the before/after value of a single field.
+type AuditChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// auditIgnoredFields are bookkeeping fields that change on every write.
+var auditIgnoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// auditFields flattens v to its top-level JSON fields. Non-object values are
+// reported under a single "value" field; nil yields no fields.
+func auditFields(v interface{}) map[string]interface{} {
+	if v == nil {
+		return map[string]interface{}{}
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return map[string]interface{}{"value": fmt.Sprint(v)}
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		var scalar interface{}
+		_ = json.Unmarshal(raw, &scalar)
+		if scalar == nil {
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{"value": scalar}
+	}
+	for k := range auditIgnoredFields {
+		delete(fields, k)
+	}
+	return fields
+}
+
+// DiffAudit returns the fields that differ between before and after, sorted by name.
+// Either side may be nil (create / delete), in which case every non-empty field of
+// the other side is reported.
+func DiffAudit(before, after interface{}) []AuditChange {
+	b := auditFields(before)
+	a := auditFields(after)
+
+	keys := make([]string, 0, len(a)+len(b))
+	seen := map[string]bool{}
+	for k := range b {
+		keys = append(keys, k)
+		seen[k] = true
+	}
+	for k := range a {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []AuditChange
+	for _, k := range keys {
+		bv, av := b[k], a[k]
+		bj, _ := json.Marshal(bv)
+		aj, _ := json.Marshal(av)
+		if string(bj) == string(aj) {
+			continue
+		}
+		if isEmptyAuditValue(bv) && isEmptyAuditValue(av) {
+			continue
+		}
+		changes = append(changes, AuditChange{Field: k, Before: bv, After: av})
+	}
+	return changes
+}
+
+// isEmptyAuditValue treats nil, "", 0, false and empty collections as "no value".
+func isEmptyAuditValue(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case float64:
+		return t == 0
+	case bool:
+		return !t
+	case []interface{}:
+		return len(t) == 0
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// FormatAuditValue renders a before/after value for display.
+func FormatAuditValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		if t == float64(int64(t)) {
+			return fmt.Sprintf("%d", int64(t))
+		}
+		return fmt.Sprintf("%g", t)
+	case bool:
+		if t {
+			return "yes"
+		}
+		return "no"
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(raw)
+}
+
+// AuditLabel turns an entity type or action slug into a display label,
+// e.g. "shipment_group" → "Shipment Group".
+func AuditLabel(slug string) string {
+	out := []rune(slug)
+	upper := true
+	for i, r := range out {
+		switch {
+		case r == '_':
+			out[i] = ' '
+			upper = true
+		case upper && r >= 'a' && r <= 'z':
+			out[i] = r - 'a' + 'A'
+			upper = false
+		default:
+			upper = false
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,73 @@
+package models
+
+import "testing"
+
+func TestDiffAudit_Update(t *testing.T) {
+	before := &Transporter{ID: 1, CompanyName: "Acme Logistics", Phone: "9000000000", IsActive: true}
+	after := &Transporter{ID: 1, CompanyName: "Acme Logistics", Phone: "9111111111", GSTNumber: "36AABCU9603R1ZM", IsActive: true}
+
+	changes := DiffAudit(before, after)
+	if len(changes) != 2 {
+		t.Fatalf("want 2 changes, got %d: %+v", len(changes), changes)
+	}
+	// Sorted by field name
+	if changes[0].Field != "gst_number" || changes[0].After != "36AABCU9603R1ZM" {
+		t.Errorf("unexpected first change: %+v", changes[0])
+	}
+	if changes[1].Field != "phone" || changes[1].Before != "9000000000" || changes[1].After != "9111111111" {
+		t.Errorf("unexpected second change: %+v", changes[1])
+	}
+}
+
+func TestDiffAudit_CreateAndDelete(t *testing.T) {
+	state := map[string]interface{}{"name": "Kit A", "purpose": "", "products": map[int]int{3: 2}}
+
+	created := DiffAudit(nil, state)
+	if len(created) != 2 {
+		t.Fatalf("create: want 2 changes (empty purpose skipped), got %+v", created)
+	}
+	if created[0].Field != "name" || created[0].Before != nil || created[0].After != "Kit A" {
+		t.Errorf("create: unexpected change: %+v", created[0])
+	}
+
+	deleted := DiffAudit(state, nil)
+	if len(deleted) != 2 || deleted[1].Field != "products" || deleted[1].After != nil {
+		t.Errorf("delete: unexpected changes: %+v", deleted)
+	}
+}
+
+func TestDiffAudit_IgnoresTimestamps(t *testing.T) {
+	before := map[string]interface{}{"status": "draft", "updated_at": "2026-04-01"}
+	after := map[string]interface{}{"status": "draft", "updated_at": "2026-04-02"}
+	if changes := DiffAudit(before, after); len(changes) != 0 {
+		t.Errorf("want no changes, got %+v", changes)
+	}
+}
+
+func TestFormatAuditValue(t *testing.T) {
+	cases := []struct {
+		in   interface{}
+		want string
+	}{
+		{nil, ""},
+		{"AP01AB1234", "AP01AB1234"},
+		{float64(18), "18"},
+		{2.5, "2.5"},
+		{true, "yes"},
+		{[]interface{}{float64(1), float64(2)}, "[1,2]"},
+	}
+	for _, tc := range cases {
+		if got := FormatAuditValue(tc.in); got != tc.want {
+			t.Errorf("FormatAuditValue(%v) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestAuditLabel(t *testing.T) {
+	if got := AuditLabel("shipment_group"); got != "Shipment Group" {
+		t.Errorf("got %q", got)
+	}
+	if got := AuditLabel("amend"); got != "Amend" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,141 @@
+package models
+
+import "sort"
+
+// BOQPlanItem is the tender's planned quantity of one product for one ship-to
+// address.
+type BOQPlanItem struct {
+	ShipToAddressID int `json:"ship_to_address_id"`
+	ProductID       int `json:"product_id"`
+	PlannedQuantity int `json:"planned_quantity"`
+}
+
+// BOQProgress is the plan of one product for one ship-to address against what
+// has gone out to it. Dispatched counts issued official DCs; Pending counts
+// draft official DCs and Transfer DC destinations not yet split into a
+// shipment, which will be dispatched once issued.
+type BOQProgress struct {
+	AddressID  int
+	District   string
+	Mandal     string
+	ShipTo     string
+	ProductID  int
+	ItemName   string
+	Planned    int
+	Dispatched int
+	Pending    int
+	HasPlan    bool // false when units went to the address without a planned quantity
+}
+
+// Balance returns the planned units still to be dispatched; negative when
+// more went out than planned.
+func (p BOQProgress) Balance() int {
+	return p.Planned - p.Dispatched
+}
+
+// BOQTotal sums planned and dispatched units under a label: a district, a
+// product, or the whole plan.
+type BOQTotal struct {
+	Label      string
+	Planned    int
+	Dispatched int
+}
+
+// Balance returns the planned units still to be dispatched.
+func (t BOQTotal) Balance() int {
+	return t.Planned - t.Dispatched
+}
+
+// Percent returns the share of the plan dispatched, capped at 100.
+func (t BOQTotal) Percent() int {
+	if t.Planned <= 0 {
+		return 0
+	}
+	if t.Dispatched >= t.Planned {
+		return 100
+	}
+	return t.Dispatched * 100 / t.Planned
+}
+
+// BOQMandalGroup is the progress of every product planned for, or dispatched
+// to, one district and mandal.
+type BOQMandalGroup struct {
+	District string
+	Mandal   string
+	Rows     []BOQProgress
+	Total    BOQTotal
+}
+
+// GroupBOQByMandal groups progress rows by district and mandal, ordered by
+// district, mandal, ship-to and product name.
+func GroupBOQByMandal(rows []BOQProgress) []BOQMandalGroup {
+	sorted := make([]BOQProgress, len(rows))
+	copy(sorted, rows)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		x, y := sorted[a], sorted[b]
+		if x.District != y.District {
+			return x.District < y.District
+		}
+		if x.Mandal != y.Mandal {
+			return x.Mandal < y.Mandal
+		}
+		if x.ShipTo != y.ShipTo {
+			return x.ShipTo < y.ShipTo
+		}
+		return x.ItemName < y.ItemName
+	})
+
+	var groups []BOQMandalGroup
+	for _, r := range sorted {
+		if n := len(groups); n == 0 || groups[n-1].District != r.District || groups[n-1].Mandal != r.Mandal {
+			groups = append(groups, BOQMandalGroup{District: r.District, Mandal: r.Mandal})
+		}
+		g := &groups[len(groups)-1]
+		g.Rows = append(g.Rows, r)
+		g.Total.Planned += r.Planned
+		g.Total.Dispatched += r.Dispatched
+	}
+	return groups
+}
+
+// BOQByDistrict totals progress rows per district, in district order.
+func BOQByDistrict(rows []BOQProgress) []BOQTotal {
+	return boqTotals(rows, func(r BOQProgress) string { return r.District })
+}
+
+// BOQByProduct totals progress rows per product, in product name order.
+func BOQByProduct(rows []BOQProgress) []BOQTotal {
+	return boqTotals(rows, func(r BOQProgress) string { return r.ItemName })
+}
+
+// BOQGrandTotal totals all progress rows.
+func BOQGrandTotal(rows []BOQProgress) BOQTotal {
+	t := BOQTotal{Label: "Total"}
+	for _, r := range rows {
+		t.Planned += r.Planned
+		t.Dispatched += r.Dispatched
+	}
+	return t
+}
+
+func boqTotals(rows []BOQProgress, label func(BOQProgress) string) []BOQTotal {
+	byLabel := make(map[string]*BOQTotal)
+	var out []*BOQTotal
+	for _, r := range rows {
+		l := label(r)
+		t, ok := byLabel[l]
+		if !ok {
+			t = &BOQTotal{Label: l}
+			byLabel[l] = t
+			out = append(out, t)
+		}
+		t.Planned += r.Planned
+		t.Dispatched += r.Dispatched
+	}
+	sort.SliceStable(out, func(a, b int) bool { return out[a].Label < out[b].Label })
+	totals := make([]BOQTotal, len(out))
+	for i, t := range out {
+		totals[i] = *t
+	}
+	return totals
+}
@@ -0,0 +1,59 @@
+package models
+
+import "testing"
+
+func TestBOQTotalPercent(t *testing.T) {
+	tests := []struct {
+		planned, dispatched, want int
+	}{
+		{0, 5, 0},
+		{40, 10, 25},
+		{40, 39, 97},
+		{40, 55, 100},
+	}
+	for _, tt := range tests {
+		got := BOQTotal{Planned: tt.planned, Dispatched: tt.dispatched}.Percent()
+		if got != tt.want {
+			t.Errorf("Percent(%d of %d) = %d, want %d", tt.dispatched, tt.planned, got, tt.want)
+		}
+	}
+	if got := (BOQTotal{Planned: 10, Dispatched: 12}).Balance(); got != -2 {
+		t.Errorf("Balance over plan = %d, want -2", got)
+	}
+}
+
+func TestGroupBOQByMandal(t *testing.T) {
+	rows := []BOQProgress{
+		{District: "Guntur", Mandal: "Tenali", ShipTo: "ZPHS Tenali", ItemName: "Switch", Planned: 2, Dispatched: 2},
+		{District: "Krishna", Mandal: "Gudivada", ShipTo: "MPPS Gudivada", ItemName: "Router", Planned: 5},
+		{District: "Guntur", Mandal: "Bapatla", ShipTo: "ZPHS Bapatla", ItemName: "Router", Planned: 10, Dispatched: 4},
+		{District: "Guntur", Mandal: "Tenali", ShipTo: "ZPHS Tenali", ItemName: "Router", Planned: 10, Dispatched: 12},
+	}
+
+	groups := GroupBOQByMandal(rows)
+	if len(groups) != 3 {
+		t.Fatalf("got %d groups, want 3: %+v", len(groups), groups)
+	}
+	if groups[0].Mandal != "Bapatla" || groups[1].Mandal != "Tenali" || groups[2].Mandal != "Gudivada" {
+		t.Errorf("group order = %s, %s, %s; want Bapatla, Tenali, Gudivada", groups[0].Mandal, groups[1].Mandal, groups[2].Mandal)
+	}
+	tenali := groups[1]
+	if len(tenali.Rows) != 2 || tenali.Rows[0].ItemName != "Router" {
+		t.Errorf("Tenali rows = %+v, want Router then Switch", tenali.Rows)
+	}
+	if tenali.Total.Planned != 12 || tenali.Total.Dispatched != 14 {
+		t.Errorf("Tenali total = %+v, want 12 planned, 14 dispatched", tenali.Total)
+	}
+
+	districts := BOQByDistrict(rows)
+	if len(districts) != 2 || districts[0] != (BOQTotal{Label: "Guntur", Planned: 22, Dispatched: 18}) {
+		t.Errorf("districts = %+v", districts)
+	}
+	products := BOQByProduct(rows)
+	if len(products) != 2 || products[0] != (BOQTotal{Label: "Router", Planned: 25, Dispatched: 16}) {
+		t.Errorf("products = %+v", products)
+	}
+	if got := BOQGrandTotal(rows); got != (BOQTotal{Label: "Total", Planned: 27, Dispatched: 18}) {
+		t.Errorf("grand total = %+v", got)
+	}
+}
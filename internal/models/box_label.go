@@ -0,0 +1,18 @@
+package models
+
+// BoxLabelDestination is one official DC of a shipment group and the products
+// it carries, from which its box labels are cut.
+type BoxLabelDestination struct {
+	DCNumber  string
+	AddressID int
+	ShipTo    string // ship-to address DisplayName
+	Lines     []*BoxLabelLine
+}
+
+// BoxLabelLine is one product going to a destination.
+type BoxLabelLine struct {
+	ProductID int
+	ItemName  string
+	Quantity  int
+	Serials   []string // serials assigned or scanned for this destination
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Approval history actions.
+const (
+	ApprovalActionSubmit  = "submit"
+	ApprovalActionApprove = "approve"
+	ApprovalActionReject  = "reject"
+	ApprovalActionReopen  = "reopen"
+)
+
+// ApprovalSettings is a project's approval configuration: whether DCs need an
+// approver's sign-off before issue, and which users hold the approver right.
+type ApprovalSettings struct {
+	ProjectID       int   `json:"project_id"`
+	RequireApproval bool  `json:"require_approval"`
+	ApproverIDs     []int `json:"approver_ids"`
+}
+
+// IsApprover reports whether the user holds the approver right on the project.
+func (s *ApprovalSettings) IsApprover(userID int) bool {
+	for _, id := range s.ApproverIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// ApprovalTarget identifies what goes through approval: a shipment group, whose
+// DCs are approved together, or a single DC such as a Transfer DC.
+type ApprovalTarget struct {
+	ProjectID       int
+	ShipmentGroupID int
+	DCID            int
+}
+
+// DCApproval is one step in the approval history of a shipment group or DC.
+type DCApproval struct {
+	ID              int       `json:"id"`
+	ProjectID       int       `json:"project_id"`
+	ShipmentGroupID *int      `json:"shipment_group_id"`
+	DCID            *int      `json:"dc_id"`
+	Action          string    `json:"action"`
+	Comments        string    `json:"comments"`
+	UserID          *int      `json:"user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	// Joined fields
+	UserName string `json:"user_name"`
+}
+
+// ApprovalState is what a detail page needs to render the approval controls.
+type ApprovalState struct {
+	Required   bool
+	CanApprove bool // the user is an approver and did not submit the pending request
+	History    []DCApproval
+}
+
+// LastStep returns the most recent approval step, or nil when there is none.
+func (s *ApprovalState) LastStep() *DCApproval {
+	if s == nil || len(s.History) == 0 {
+		return nil
+	}
+	return &s.History[len(s.History)-1]
+}
+
+// ApprovalQueueItem is a shipment group or DC awaiting an approver.
+type ApprovalQueueItem struct {
+	ProjectID       int
+	ProjectName     string
+	ShipmentGroupID int // set for shipment groups
+	DCID            int // set for single DCs
+	DCType          string
+	DCNumbers       string
+	DCCount         int
+	SubmittedBy     string
+	SubmittedByID   int
+	SubmittedAt     time.Time
+	Comments        string
+}
+
+// URL returns the detail page where the item is reviewed.
+func (q *ApprovalQueueItem) URL() string {
+	if q.ShipmentGroupID > 0 {
+		return fmt.Sprintf("/projects/%d/shipments/%d", q.ProjectID, q.ShipmentGroupID)
+	}
+	return fmt.Sprintf("/projects/%d/dcs/%d", q.ProjectID, q.DCID)
+}
@@ -0,0 +1,37 @@
+package models
+
+import "testing"
+
+func TestApprovalSettingsIsApprover(t *testing.T) {
+	s := &ApprovalSettings{ProjectID: 1, ApproverIDs: []int{2, 5}}
+	if !s.IsApprover(5) {
+		t.Error("IsApprover(5) = false; want true")
+	}
+	if s.IsApprover(1) {
+		t.Error("IsApprover(1) = true; want false")
+	}
+}
+
+func TestApprovalStateLastStep(t *testing.T) {
+	var nilState *ApprovalState
+	if nilState.LastStep() != nil {
+		t.Error("LastStep on nil state should be nil")
+	}
+	s := &ApprovalState{History: []DCApproval{{Action: ApprovalActionSubmit}, {Action: ApprovalActionReject}}}
+	if got := s.LastStep(); got == nil || got.Action != ApprovalActionReject {
+		t.Errorf("LastStep() = %+v; want the reject step", got)
+	}
+}
+
+func TestIsIssuableStatus(t *testing.T) {
+	for status, want := range map[string]bool{
+		DCStatusDraft:           true,
+		DCStatusPendingApproval: true,
+		DCStatusRejected:        false,
+		DCStatusIssued:          false,
+	} {
+		if got := IsIssuableStatus(status); got != want {
+			t.Errorf("IsIssuableStatus(%q) = %v; want %v", status, got, want)
+		}
+	}
+}
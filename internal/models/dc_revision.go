@@ -0,0 +1,181 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DisplayNumber returns the DC number with its revision suffix, e.g. "TDC-2526-001/R1".
+// The original issue (revision 0) has no suffix.
+func (dc *DeliveryChallan) DisplayNumber() string {
+	return dc.DCNumber + RevisionSuffix(dc.Revision)
+}
+
+// RevisionSuffix returns the "/R<n>" suffix appended to an amended DC number.
+func RevisionSuffix(revision int) string {
+	if revision <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("/R%d", revision)
+}
+
+// RevisionLabel returns a short human label for a revision ("Original", "R1", "R2", ...).
+func RevisionLabel(revision int) string {
+	if revision <= 0 {
+		return "Original"
+	}
+	return fmt.Sprintf("R%d", revision)
+}
+
+// DCRevisionLineItem is the line item portion of a revision snapshot.
+type DCRevisionLineItem struct {
+	ProductID     int      `json:"product_id"`
+	ItemName      string   `json:"item_name"`
+	Quantity      int      `json:"quantity"`
+	SerialNumbers []string `json:"serial_numbers,omitempty"`
+}
+
+// DCRevisionSnapshot is the amendable state of a DC at one revision.
+// Address names are captured alongside the IDs so a snapshot stays readable
+// even if the address list is edited later.
+type DCRevisionSnapshot struct {
+	ChallanDate           string               `json:"challan_date"`
+	BillFromAddressID     int                  `json:"bill_from_address_id,omitempty"`
+	BillFromAddress       string               `json:"bill_from_address,omitempty"`
+	DispatchFromAddressID int                  `json:"dispatch_from_address_id,omitempty"`
+	DispatchFromAddress   string               `json:"dispatch_from_address,omitempty"`
+	BillToAddressID       int                  `json:"bill_to_address_id,omitempty"`
+	BillToAddress         string               `json:"bill_to_address,omitempty"`
+	ShipToAddressID       int                  `json:"ship_to_address_id,omitempty"`
+	ShipToAddress         string               `json:"ship_to_address,omitempty"`
+	TransporterName       string               `json:"transporter_name,omitempty"`
+	VehicleNumber         string               `json:"vehicle_number,omitempty"`
+	EwayBillNumber        string               `json:"eway_bill_number,omitempty"`
+	Notes                 string               `json:"notes,omitempty"`
+	LineItems             []DCRevisionLineItem `json:"line_items"`
+}
+
+// DCRevision is a read-only, superseded revision of a DC.
+// Amended* fields describe the amendment that replaced this revision with the next one.
+type DCRevision struct {
+	ID              int                `json:"id"`
+	DCID            int                `json:"dc_id"`
+	Revision        int                `json:"revision"`
+	DCNumber        string             `json:"dc_number"` // number as printed at this revision
+	Snapshot        DCRevisionSnapshot `json:"snapshot"`
+	AmendmentReason string             `json:"amendment_reason"`
+	AmendedBy       *int               `json:"amended_by"`
+	AmendedByName   string             `json:"amended_by_name"`
+	AmendedAt       time.Time          `json:"amended_at"`
+}
+
+// DCRevisionChange is a single field difference between two revisions.
+type DCRevisionChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// DCRevisionHistoryEntry describes one amendment: the revision it produced,
+// who made it and why, and what changed relative to the previous revision.
+type DCRevisionHistoryEntry struct {
+	FromRevision  int
+	ToRevision    int
+	DCNumber      string // number as printed at ToRevision
+	Reason        string
+	AmendedByName string
+	AmendedAt     time.Time
+	Changes       []DCRevisionChange
+}
+
+// DCAmendment holds the fields a user can change when amending an issued DC.
+type DCAmendment struct {
+	Reason      string
+	ChallanDate *string
+
+	// Transit DC fields
+	TransporterName string
+	VehicleNumber   string
+	EwayBillNumber  string
+	Notes           string
+
+	// Official DC fields (0 clears the address)
+	BillFromAddressID     int
+	DispatchFromAddressID int
+	BillToAddressID       int
+}
+
+// snapshotFields returns the comparable fields of a snapshot in display order.
+func (s *DCRevisionSnapshot) snapshotFields() [][2]string {
+	fields := [][2]string{
+		{"Challan Date", s.ChallanDate},
+		{"Bill From", s.BillFromAddress},
+		{"Dispatch From", s.DispatchFromAddress},
+		{"Bill To", s.BillToAddress},
+		{"Ship To", s.ShipToAddress},
+		{"Transporter", s.TransporterName},
+		{"Vehicle Number", s.VehicleNumber},
+		{"E-Way Bill Number", s.EwayBillNumber},
+		{"Notes", s.Notes},
+	}
+	for _, li := range s.LineItems {
+		v := fmt.Sprintf("%d", li.Quantity)
+		if len(li.SerialNumbers) > 0 {
+			v += " (" + strings.Join(li.SerialNumbers, ", ") + ")"
+		}
+		fields = append(fields, [2]string{"Qty: " + li.ItemName, v})
+	}
+	return fields
+}
+
+// DiffDCRevisionSnapshots lists the fields that differ between two snapshots,
+// in display order. Line items are matched by product name.
+func DiffDCRevisionSnapshots(before, after *DCRevisionSnapshot) []DCRevisionChange {
+	oldVals := map[string]string{}
+	var order []string
+	for _, f := range before.snapshotFields() {
+		oldVals[f[0]] = f[1]
+		order = append(order, f[0])
+	}
+	newVals := map[string]string{}
+	for _, f := range after.snapshotFields() {
+		if _, seen := oldVals[f[0]]; !seen {
+			order = append(order, f[0])
+		}
+		newVals[f[0]] = f[1]
+	}
+
+	var changes []DCRevisionChange
+	for _, field := range order {
+		if oldVals[field] != newVals[field] {
+			changes = append(changes, DCRevisionChange{Field: field, OldValue: oldVals[field], NewValue: newVals[field]})
+		}
+	}
+	return changes
+}
+
+// BuildDCRevisionHistory turns the stored revisions of a DC (ascending by revision)
+// plus the DC's current snapshot into a newest-first list of amendments.
+func BuildDCRevisionHistory(dcNumber string, revisions []*DCRevision, current *DCRevisionSnapshot) []DCRevisionHistoryEntry {
+	entries := make([]DCRevisionHistoryEntry, 0, len(revisions))
+	for i, rev := range revisions {
+		next := current
+		if i+1 < len(revisions) {
+			next = &revisions[i+1].Snapshot
+		}
+		entries = append(entries, DCRevisionHistoryEntry{
+			FromRevision:  rev.Revision,
+			ToRevision:    rev.Revision + 1,
+			DCNumber:      dcNumber + RevisionSuffix(rev.Revision+1),
+			Reason:        rev.AmendmentReason,
+			AmendedByName: rev.AmendedByName,
+			AmendedAt:     rev.AmendedAt,
+			Changes:       DiffDCRevisionSnapshots(&rev.Snapshot, next),
+		})
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries
+}
@@ -0,0 +1,62 @@
+package models
+
+import "testing"
+
+func TestDisplayNumber(t *testing.T) {
+	dc := &DeliveryChallan{DCNumber: "TDC-2526-001"}
+	if got := dc.DisplayNumber(); got != "TDC-2526-001" {
+		t.Errorf("revision 0: got %q", got)
+	}
+	dc.Revision = 2
+	if got := dc.DisplayNumber(); got != "TDC-2526-001/R2" {
+		t.Errorf("revision 2: got %q", got)
+	}
+}
+
+func TestDiffDCRevisionSnapshots(t *testing.T) {
+	before := &DCRevisionSnapshot{
+		ChallanDate:   "2026-04-01",
+		VehicleNumber: "AP01AB1234",
+		LineItems:     []DCRevisionLineItem{{ItemName: "Router", Quantity: 2}},
+	}
+	after := &DCRevisionSnapshot{
+		ChallanDate:    "2026-04-01",
+		VehicleNumber:  "AP01CD5678",
+		EwayBillNumber: "331000123456",
+		LineItems:      []DCRevisionLineItem{{ItemName: "Router", Quantity: 2}},
+	}
+
+	changes := DiffDCRevisionSnapshots(before, after)
+	if len(changes) != 2 {
+		t.Fatalf("want 2 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Field != "Vehicle Number" || changes[0].OldValue != "AP01AB1234" || changes[0].NewValue != "AP01CD5678" {
+		t.Errorf("unexpected first change: %+v", changes[0])
+	}
+	if changes[1].Field != "E-Way Bill Number" || changes[1].OldValue != "" {
+		t.Errorf("unexpected second change: %+v", changes[1])
+	}
+}
+
+func TestBuildDCRevisionHistory(t *testing.T) {
+	revisions := []*DCRevision{
+		{Revision: 0, Snapshot: DCRevisionSnapshot{VehicleNumber: "V1"}, AmendmentReason: "truck swap"},
+		{Revision: 1, Snapshot: DCRevisionSnapshot{VehicleNumber: "V2"}, AmendmentReason: "e-way bill"},
+	}
+	current := &DCRevisionSnapshot{VehicleNumber: "V2", EwayBillNumber: "E1"}
+
+	entries := BuildDCRevisionHistory("TDC-001", revisions, current)
+	if len(entries) != 2 {
+		t.Fatalf("want 2 entries, got %d", len(entries))
+	}
+	// Newest first
+	if entries[0].DCNumber != "TDC-001/R2" || entries[0].Reason != "e-way bill" {
+		t.Errorf("unexpected newest entry: %+v", entries[0])
+	}
+	if len(entries[0].Changes) != 1 || entries[0].Changes[0].Field != "E-Way Bill Number" {
+		t.Errorf("unexpected newest changes: %+v", entries[0].Changes)
+	}
+	if entries[1].DCNumber != "TDC-001/R1" || len(entries[1].Changes) != 1 || entries[1].Changes[0].NewValue != "V2" {
+		t.Errorf("unexpected oldest entry: %+v", entries[1])
+	}
+}
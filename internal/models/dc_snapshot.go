@@ -0,0 +1,140 @@
+package models
+
+import "time"
+
+// Address roles stored in a DCSnapshot.
+const (
+	SnapshotRoleShipTo       = "ship_to"
+	SnapshotRoleBillTo       = "bill_to"
+	SnapshotRoleBillFrom     = "bill_from"
+	SnapshotRoleDispatchFrom = "dispatch_from"
+	SnapshotRoleHub          = "hub"
+)
+
+// SnapshotAddressTypes lists the address list configs whose print columns are frozen.
+var SnapshotAddressTypes = []string{"ship_to", "bill_to", "bill_from", "dispatch_from"}
+
+// DCSnapshot is the master data a DC was issued with: project and company header,
+// resolved addresses, print column configs, product descriptive fields and kit components.
+// Issued DCs are printed and exported from their snapshot, so later edits to
+// addresses, products or settings do not change documents that already travelled.
+type DCSnapshot struct {
+	CapturedAt   time.Time                     `json:"captured_at"`
+	Project      *Project                      `json:"project"`
+	Company      *CompanySettings              `json:"company,omitempty"`
+	Addresses    map[string]*DCSnapshotAddress `json:"addresses"`              // keyed by role
+	Destinations map[int]*DCSnapshotAddress    `json:"destinations,omitempty"` // transfer DCs, keyed by address ID
+	PrintColumns map[string][]ColumnDefinition `json:"print_columns"`          // keyed by address type
+	Products     map[int]DCSnapshotProduct     `json:"products"`               // keyed by product ID
+
+	// KitComponents are the bills of materials of the DC's kit lines, keyed
+	// by kit product ID, without serials. Nil in snapshots taken before
+	// components were recorded.
+	KitComponents map[int][]KitComponent `json:"kit_components"`
+}
+
+// DCSnapshotAddress is the serialisable form of an Address, including its data fields.
+type DCSnapshotAddress struct {
+	ID           int               `json:"id"`
+	ConfigID     int               `json:"config_id"`
+	AddressCode  string            `json:"address_code"`
+	Data         map[string]string `json:"data"`
+	DistrictName string            `json:"district_name"`
+	MandalName   string            `json:"mandal_name"`
+	MandalCode   string            `json:"mandal_code"`
+}
+
+// DCSnapshotProduct holds the product fields printed on a DC line.
+type DCSnapshotProduct struct {
+	ItemName        string  `json:"item_name"`
+	ItemDescription string  `json:"item_description"`
+	HSNCode         string  `json:"hsn_code"`
+	UoM             string  `json:"uom"`
+	BrandModel      string  `json:"brand_model"`
+	GSTPercentage   float64 `json:"gst_percentage"`
+}
+
+// NewDCSnapshotAddress copies an address for storage in a snapshot. Returns nil for nil.
+func NewDCSnapshotAddress(a *Address) *DCSnapshotAddress {
+	if a == nil {
+		return nil
+	}
+	data := make(map[string]string, len(a.Data))
+	for k, v := range a.Data {
+		data[k] = v
+	}
+	return &DCSnapshotAddress{
+		ID:           a.ID,
+		ConfigID:     a.ConfigID,
+		AddressCode:  a.AddressCode,
+		Data:         data,
+		DistrictName: a.DistrictName,
+		MandalName:   a.MandalName,
+		MandalCode:   a.MandalCode,
+	}
+}
+
+// ToAddress returns the snapshot address as an Address for rendering. Returns nil for nil.
+func (sa *DCSnapshotAddress) ToAddress() *Address {
+	if sa == nil {
+		return nil
+	}
+	a := &Address{
+		ID:           sa.ID,
+		ConfigID:     sa.ConfigID,
+		AddressCode:  sa.AddressCode,
+		Data:         make(map[string]string, len(sa.Data)),
+		DistrictName: sa.DistrictName,
+		MandalName:   sa.MandalName,
+		MandalCode:   sa.MandalCode,
+	}
+	for k, v := range sa.Data {
+		a.Data[k] = v
+	}
+	a.DataJSON, _ = a.DataToJSON()
+	return a
+}
+
+// Address returns the frozen address for a role, or nil when the DC has none.
+func (s *DCSnapshot) Address(role string) *Address {
+	return s.Addresses[role].ToAddress()
+}
+
+// DestinationAddress returns the frozen destination address of a transfer DC, or nil.
+func (s *DCSnapshot) DestinationAddress(addressID int) *Address {
+	return s.Destinations[addressID].ToAddress()
+}
+
+// AddressConfig returns the frozen print config for an address type, or nil when none was captured.
+func (s *DCSnapshot) AddressConfig(addressType string) *AddressListConfig {
+	cols, ok := s.PrintColumns[addressType]
+	if !ok {
+		return nil
+	}
+	cfg := &AddressListConfig{
+		AddressType:       addressType,
+		ColumnDefinitions: cols,
+	}
+	if s.Project != nil {
+		cfg.ProjectID = s.Project.ID
+	}
+	cfg.ColumnJSON, _ = cfg.ColumnsToJSON()
+	return cfg
+}
+
+// ApplyProducts overwrites the joined product fields of line items with the frozen values.
+// Line items whose product was not captured keep their live values.
+func (s *DCSnapshot) ApplyProducts(items []DCLineItem) {
+	for i := range items {
+		p, ok := s.Products[items[i].ProductID]
+		if !ok {
+			continue
+		}
+		items[i].ItemName = p.ItemName
+		items[i].ItemDescription = p.ItemDescription
+		items[i].HSNCode = p.HSNCode
+		items[i].UoM = p.UoM
+		items[i].BrandModel = p.BrandModel
+		items[i].GSTPercentage = p.GSTPercentage
+	}
+}
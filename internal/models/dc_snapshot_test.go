@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDCSnapshot_RoundTrip(t *testing.T) {
+	addr := &Address{ID: 7, AddressCode: "HQ", Data: map[string]string{"name": "Head Office", "gstin": "36AABCU9603R1ZM"}}
+	show := false
+	snap := &DCSnapshot{
+		Project:      &Project{ID: 3, Name: "Fiber Rollout"},
+		Addresses:    map[string]*DCSnapshotAddress{SnapshotRoleBillFrom: NewDCSnapshotAddress(addr)},
+		PrintColumns: map[string][]ColumnDefinition{"bill_from": {{Name: "name"}, {Name: "gstin", ShowInPrint: &show}}},
+	}
+
+	// Editing the live address must not change the snapshot.
+	addr.Data["name"] = "Renamed Office"
+
+	b, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got DCSnapshot
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	billFrom := got.Address(SnapshotRoleBillFrom)
+	if billFrom == nil || billFrom.Data["name"] != "Head Office" || billFrom.AddressCode != "HQ" {
+		t.Errorf("unexpected bill-from: %+v", billFrom)
+	}
+	if got.Address(SnapshotRoleShipTo) != nil {
+		t.Error("missing role should return nil")
+	}
+
+	cfg := got.AddressConfig("bill_from")
+	if cfg == nil || cfg.ProjectID != 3 || len(cfg.PrintVisibleColumns()) != 1 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if got.AddressConfig("ship_to") != nil {
+		t.Error("missing config should return nil")
+	}
+}
+
+func TestDCSnapshot_ApplyProducts(t *testing.T) {
+	snap := &DCSnapshot{Products: map[int]DCSnapshotProduct{
+		1: {ItemName: "ONT", HSNCode: "8517", UoM: "Nos", GSTPercentage: 18},
+	}}
+	items := []DCLineItem{
+		{ProductID: 1, ItemName: "ONT (new)", HSNCode: "8518", Rate: 1200},
+		{ProductID: 2, ItemName: "Patch Cord"},
+	}
+	snap.ApplyProducts(items)
+
+	if items[0].ItemName != "ONT" || items[0].HSNCode != "8517" || items[0].GSTPercentage != 18 || items[0].Rate != 1200 {
+		t.Errorf("unexpected frozen item: %+v", items[0])
+	}
+	if items[1].ItemName != "Patch Cord" {
+		t.Errorf("uncaptured product should keep live values, got %+v", items[1])
+	}
+}
@@ -10,15 +10,34 @@ const (
 	DCStatusIssued    = "issued"
 	DCStatusSplitting = "splitting"
 	DCStatusSplit     = "split"
+	DCStatusCancelled = "cancelled"
+
+	DCStatusDelivered          = "delivered"
+	DCStatusPartiallyDelivered = "partially_delivered"
+
+	// Approval states, used when the project requires approval before issue.
+	DCStatusPendingApproval = "pending_approval"
+	DCStatusRejected        = "rejected"
 )
 
+// IsDeliveryStatus reports whether a DC has recorded proof of delivery.
+func IsDeliveryStatus(status string) bool {
+	return status == DCStatusDelivered || status == DCStatusPartiallyDelivered
+}
+
+// IsIssuableStatus reports whether a DC in this status can move to issued:
+// a draft, or a DC awaiting approval.
+func IsIssuableStatus(status string) bool {
+	return status == DCStatusDraft || status == DCStatusPendingApproval
+}
+
 // DeliveryChallan represents a delivery challan (transit, official, or transfer).
 type DeliveryChallan struct {
 	ID              int        `json:"id"`
 	ProjectID       int        `json:"project_id" validate:"required,gt=0"`
 	DCNumber        string     `json:"dc_number"`
-	DCType          string     `json:"dc_type" validate:"required,oneof=transit official transfer"` // "transit", "official", or "transfer"
-	Status          string     `json:"status"`                                             // "draft" or "issued"
+	DCType          string     `json:"dc_type" validate:"required,oneof=transit official transfer return"` // "transit", "official", "transfer" or "return"
+	Status          string     `json:"status"`                                                             // "draft" or "issued"
 	TemplateID      *int       `json:"template_id"`
 	BillToAddressID *int       `json:"bill_to_address_id"`
 	ShipToAddressID int        `json:"ship_to_address_id" validate:"required,gt=0"`
@@ -40,6 +59,15 @@ type DeliveryChallan struct {
 	// Transfer DC reference
 	TransferDCID *int `json:"transfer_dc_id"`
 
+	// Cancellation details (set only when Status is "cancelled")
+	CancelledAt        *time.Time `json:"cancelled_at"`
+	CancelledBy        *int       `json:"cancelled_by"`
+	CancellationReason string     `json:"cancellation_reason"`
+	CancelledByName    string     `json:"cancelled_by_name"`
+
+	// Amendment revision (0 = original issue; see DisplayNumber)
+	Revision int `json:"revision"`
+
 	// Computed/joined fields
 	ProjectName   string `json:"project_name"`
 	TemplateName  string `json:"template_name"`
@@ -80,6 +108,10 @@ type DCLineItem struct {
 	BrandModel      string   `json:"brand_model"`
 	GSTPercentage   float64  `json:"gst_percentage"`
 	SerialNumbers   []string `json:"serial_numbers"`
+
+	// KitComponents is the bill of materials when the product is a kit, with
+	// each component's serials on this line.
+	KitComponents []KitComponent `json:"kit_components,omitempty"`
 }
 
 // SerialNumber represents a serial number tracked per line item.
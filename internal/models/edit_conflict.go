@@ -0,0 +1,21 @@
+package models
+
+// EditConflict describes a save that was rejected because someone else saved the
+// same record after the form was opened.
+type EditConflict struct {
+	Subject   string        // what was being edited, e.g. "Shipment group #12"
+	Version   int           // the record's current edit version
+	Changes   []AuditChange // Before = the record as saved now, After = the rejected values
+	Saves     []*AuditEvent // saves made since the form was opened, newest first
+	ReloadURL string        // reopens the form on the saved record
+	BackURL   string
+}
+
+// LastSave returns the most recent save made since the form was opened, or nil
+// when it was not audited.
+func (c *EditConflict) LastSave() *AuditEvent {
+	if len(c.Saves) == 0 {
+		return nil
+	}
+	return c.Saves[0]
+}
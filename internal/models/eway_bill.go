@@ -0,0 +1,27 @@
+package models
+
+// EwayBillDCType reports whether DCs of this type move goods under an e-way bill:
+// transit and transfer DCs do, official DCs travel with their transit DC.
+func EwayBillDCType(dcType string) bool {
+	return dcType == "transit" || dcType == "transfer"
+}
+
+// EwayBillReady reports whether an e-way bill can be generated for a DC: a transit
+// or transfer DC that has been issued and not cancelled.
+func EwayBillReady(dc *DeliveryChallan) bool {
+	if dc == nil || !EwayBillDCType(dc.DCType) {
+		return false
+	}
+	switch dc.Status {
+	case DCStatusDraft, DCStatusPendingApproval, DCStatusRejected, DCStatusCancelled:
+		return false
+	}
+	return true
+}
+
+// EwayBillCheck lists what stops a DC's e-way bill JSON from being generated.
+type EwayBillCheck struct {
+	DCID     int
+	DCNumber string
+	Problems []string
+}
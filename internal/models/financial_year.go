@@ -0,0 +1,63 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// FinancialYearLabel formats a compact financial year ("2526") as "2025-26".
+func FinancialYearLabel(fy string) string {
+	if len(fy) != 4 {
+		return fy
+	}
+	return "20" + fy[:2] + "-" + fy[2:]
+}
+
+// FinancialYearClosure records a financial year closed against new DCs and edits.
+type FinancialYearClosure struct {
+	ID            int       `json:"id"`
+	ProjectID     int       `json:"project_id"`
+	FinancialYear string    `json:"financial_year"` // compact, e.g. "2526"
+	ClosedBy      *int      `json:"closed_by"`
+	ClosedAt      time.Time `json:"closed_at"`
+
+	// Joined fields
+	ClosedByName string `json:"closed_by_name"`
+}
+
+// DCDateSettings is a project's challan date controls: the closed financial
+// years, and whether challan dates must follow the latest issued date.
+type DCDateSettings struct {
+	ProjectID        int                    `json:"project_id"`
+	EnforceDateOrder bool                   `json:"enforce_date_order"`
+	ClosedYears      []FinancialYearClosure `json:"closed_years"`
+}
+
+// Closure returns the closure of a financial year, or nil when it is open.
+func (s *DCDateSettings) Closure(fy string) *FinancialYearClosure {
+	for i := range s.ClosedYears {
+		if s.ClosedYears[i].FinancialYear == fy {
+			return &s.ClosedYears[i]
+		}
+	}
+	return nil
+}
+
+// ChallanDateError explains why a challan date was refused: its financial year
+// is closed, or it precedes the latest issued DC of the same type in that year.
+type ChallanDateError struct {
+	Date          string // the refused challan date, YYYY-MM-DD
+	FinancialYear string // compact, e.g. "2526"
+	Closed        bool
+	DCType        string
+	LatestDate    string // latest issued challan date of DCType, when not Closed
+	LatestNumber  string
+}
+
+func (e *ChallanDateError) Error() string {
+	if e.Closed {
+		return fmt.Sprintf("financial year %s is closed for new DCs and edits", FinancialYearLabel(e.FinancialYear))
+	}
+	return fmt.Sprintf("challan date %s is before %s, the date of the latest issued %s DC %s",
+		e.Date, e.LatestDate, e.DCType, e.LatestNumber)
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Condition of a serial number reported in a goods receipt.
+const (
+	SerialConditionDamaged = "damaged"
+	SerialConditionMissing = "missing"
+)
+
+// GoodsReceipt is the receiver's account of what arrived against an official DC.
+// Each DC has at most one receipt; recording it again replaces the previous figures.
+type GoodsReceipt struct {
+	ID             int                `json:"id"`
+	DCID           int                `json:"dc_id"`
+	ReceivedDate   string             `json:"received_date"`
+	Remarks        string             `json:"remarks"`
+	RecordedBy     *int               `json:"recorded_by"`
+	RecordedByName string             `json:"recorded_by_name"`
+	RecordedAt     time.Time          `json:"recorded_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	Lines          []GoodsReceiptLine `json:"lines"`
+}
+
+// GoodsReceiptLine holds the received, short and damaged quantities of one DC line item.
+// Received counts only units accepted in good condition, so
+// Received + Short + Damaged always equals Dispatched.
+type GoodsReceiptLine struct {
+	LineItemID     int      `json:"line_item_id"`
+	Received       int      `json:"received"`
+	Short          int      `json:"short"`
+	Damaged        int      `json:"damaged"`
+	DamagedSerials []string `json:"damaged_serials"`
+	MissingSerials []string `json:"missing_serials"`
+
+	// Joined from the DC line item (not stored in dc_receipt_lines)
+	ItemName          string   `json:"item_name"`
+	Dispatched        int      `json:"dispatched"`
+	DispatchedSerials []string `json:"-"`
+}
+
+// HasDiscrepancy reports whether fewer units were received than dispatched.
+func (l *GoodsReceiptLine) HasDiscrepancy() bool {
+	return l.Short > 0 || l.Damaged > 0
+}
+
+// HasDiscrepancy reports whether any line of the receipt is short or damaged.
+func (r *GoodsReceipt) HasDiscrepancy() bool {
+	for i := range r.Lines {
+		if r.Lines[i].HasDiscrepancy() {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the receipt against the dispatched quantities and serials.
+// Errors are keyed by "received_date" or "line_<line item ID>".
+func (r *GoodsReceipt) Validate() map[string]string {
+	errors := map[string]string{}
+	if _, err := time.Parse("2006-01-02", r.ReceivedDate); err != nil {
+		errors["received_date"] = "A valid received date is required"
+	}
+	for i := range r.Lines {
+		if msg := r.Lines[i].validate(); msg != "" {
+			errors[fmt.Sprintf("line_%d", r.Lines[i].LineItemID)] = msg
+		}
+	}
+	return errors
+}
+
+func (l *GoodsReceiptLine) validate() string {
+	if l.Received < 0 || l.Short < 0 || l.Damaged < 0 {
+		return "Quantities cannot be negative"
+	}
+	if l.Received+l.Short+l.Damaged != l.Dispatched {
+		return fmt.Sprintf("Received, short and damaged must add up to the %d dispatched", l.Dispatched)
+	}
+	if len(l.DamagedSerials) > l.Damaged {
+		return fmt.Sprintf("%d damaged serial(s) listed but only %d unit(s) damaged", len(l.DamagedSerials), l.Damaged)
+	}
+	if len(l.MissingSerials) > l.Short {
+		return fmt.Sprintf("%d missing serial(s) listed but only %d unit(s) short", len(l.MissingSerials), l.Short)
+	}
+
+	dispatched := make(map[string]bool, len(l.DispatchedSerials))
+	for _, sn := range l.DispatchedSerials {
+		dispatched[sn] = true
+	}
+	seen := make(map[string]bool)
+	for _, list := range [][]string{l.DamagedSerials, l.MissingSerials} {
+		for _, sn := range list {
+			if !dispatched[sn] {
+				return fmt.Sprintf("Serial %s was not dispatched on this line", sn)
+			}
+			if seen[sn] {
+				return fmt.Sprintf("Serial %s is listed more than once", sn)
+			}
+			seen[sn] = true
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package models
+
+import "testing"
+
+func TestGoodsReceiptValidate(t *testing.T) {
+	base := func() *GoodsReceipt {
+		return &GoodsReceipt{
+			ReceivedDate: "2026-04-05",
+			Lines: []GoodsReceiptLine{{
+				LineItemID:        7,
+				Received:          3,
+				Dispatched:        3,
+				DispatchedSerials: []string{"SN-1", "SN-2", "SN-3"},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(r *GoodsReceipt)
+		wantKey string
+	}{
+		{"received in full", func(r *GoodsReceipt) {}, ""},
+		{"missing date", func(r *GoodsReceipt) { r.ReceivedDate = "" }, "received_date"},
+		{"negative quantity", func(r *GoodsReceipt) { r.Lines[0].Received, r.Lines[0].Short = 4, -1 }, "line_7"},
+		{"does not add up", func(r *GoodsReceipt) { r.Lines[0].Received = 2 }, "line_7"},
+		{"short with serial", func(r *GoodsReceipt) {
+			r.Lines[0].Received, r.Lines[0].Short = 2, 1
+			r.Lines[0].MissingSerials = []string{"SN-3"}
+		}, ""},
+		{"more damaged serials than units", func(r *GoodsReceipt) {
+			r.Lines[0].Received, r.Lines[0].Damaged = 2, 1
+			r.Lines[0].DamagedSerials = []string{"SN-1", "SN-2"}
+		}, "line_7"},
+		{"serial not dispatched", func(r *GoodsReceipt) {
+			r.Lines[0].Received, r.Lines[0].Short = 2, 1
+			r.Lines[0].MissingSerials = []string{"SN-9"}
+		}, "line_7"},
+		{"serial both damaged and missing", func(r *GoodsReceipt) {
+			r.Lines[0].Received, r.Lines[0].Short, r.Lines[0].Damaged = 1, 1, 1
+			r.Lines[0].MissingSerials = []string{"SN-1"}
+			r.Lines[0].DamagedSerials = []string{"SN-1"}
+		}, "line_7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := base()
+			tt.mutate(r)
+			errs := r.Validate()
+			if tt.wantKey == "" {
+				if len(errs) > 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if errs[tt.wantKey] == "" {
+				t.Errorf("want error on %q, got %v", tt.wantKey, errs)
+			}
+		})
+	}
+}
+
+func TestGoodsReceiptHasDiscrepancy(t *testing.T) {
+	r := &GoodsReceipt{Lines: []GoodsReceiptLine{{Received: 2, Dispatched: 2}}}
+	if r.HasDiscrepancy() {
+		t.Error("full receipt should not be a discrepancy")
+	}
+	r.Lines = append(r.Lines, GoodsReceiptLine{Received: 1, Damaged: 1, Dispatched: 2})
+	if !r.HasDiscrepancy() {
+		t.Error("damaged line should be a discrepancy")
+	}
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gstStateCodes maps normalised state and union territory names to their GST state codes.
+var gstStateCodes = map[string]int{
+	"jammuandkashmir":                   1,
+	"himachalpradesh":                   2,
+	"punjab":                            3,
+	"chandigarh":                        4,
+	"uttarakhand":                       5,
+	"uttaranchal":                       5,
+	"haryana":                           6,
+	"delhi":                             7,
+	"newdelhi":                          7,
+	"nctofdelhi":                        7,
+	"rajasthan":                         8,
+	"uttarpradesh":                      9,
+	"bihar":                             10,
+	"sikkim":                            11,
+	"arunachalpradesh":                  12,
+	"nagaland":                          13,
+	"manipur":                           14,
+	"mizoram":                           15,
+	"tripura":                           16,
+	"meghalaya":                         17,
+	"assam":                             18,
+	"westbengal":                        19,
+	"jharkhand":                         20,
+	"odisha":                            21,
+	"orissa":                            21,
+	"chhattisgarh":                      22,
+	"madhyapradesh":                     23,
+	"gujarat":                           24,
+	"damananddiu":                       26,
+	"dadraandnagarhaveli":               26,
+	"dadraandnagarhavelianddamananddiu": 26,
+	"maharashtra":                       27,
+	"karnataka":                         29,
+	"goa":                               30,
+	"lakshadweep":                       31,
+	"kerala":                            32,
+	"tamilnadu":                         33,
+	"puducherry":                        34,
+	"pondicherry":                       34,
+	"andamanandnicobarislands":          35,
+	"telangana":                         36,
+	"andhrapradesh":                     37,
+	"ladakh":                            38,
+}
+
+// normaliseKey lowercases s and drops everything but letters and digits, so
+// "PIN Code", "Pin-code" and "pincode" compare equal.
+func normaliseKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// GSTStateCode returns the GST state code for a state name, or for a state
+// already given as its code ("36"). Returns 0 when the state is not recognised.
+func GSTStateCode(state string) int {
+	state = strings.TrimSpace(strings.ReplaceAll(state, "&", " and "))
+	if code, err := strconv.Atoi(state); err == nil {
+		if (code > 0 && code <= 38) || code == 97 {
+			return code
+		}
+		return 0
+	}
+	return gstStateCodes[normaliseKey(state)]
+}
+
+// GSTINStateCode returns the state code in the first two digits of a GSTIN,
+// or 0 when the GSTIN does not start with one.
+func GSTINStateCode(gstin string) int {
+	gstin = strings.TrimSpace(gstin)
+	if len(gstin) < 2 {
+		return 0
+	}
+	return GSTStateCode(gstin[:2])
+}
+
+// IsGSTINColumn reports whether an address column holds a GSTIN, so that
+// "GSTIN", "GST No" and "GST Number" columns are all validated.
+func IsGSTINColumn(name string) bool {
+	switch normaliseKey(name) {
+	case "gstin", "gstno", "gstnumber", "gstinno":
+		return true
+	}
+	return false
+}
+
+// AddressStateCode returns the GST state code of an address from its State
+// column, falling back to the prefix of its GSTIN. Returns 0 when neither is set.
+func AddressStateCode(a *Address) int {
+	if code := GSTStateCode(a.Field("State", "State Name", "State Code")); code != 0 {
+		return code
+	}
+	return GSTINStateCode(a.Field("GSTIN", "GST No", "GST Number"))
+}
+
+// SupplierStateCode returns the state the goods are supplied from. The bill-from
+// address chosen on the DC wins because projects may bill from a branch
+// registered in another state; the project's GSTIN and the company settings are
+// the fallbacks, in that order.
+func SupplierStateCode(billFrom *Address, project *Project, company *CompanySettings) int {
+	if code := AddressStateCode(billFrom); code != 0 {
+		return code
+	}
+	if project != nil {
+		if code := GSTINStateCode(project.CompanyGSTIN); code != 0 {
+			return code
+		}
+	}
+	if company != nil {
+		if code := GSTStateCode(company.StateCode); code != 0 {
+			return code
+		}
+		return GSTINStateCode(company.GSTIN)
+	}
+	return 0
+}
+
+// PlaceOfSupplyStateCode returns the place of supply for goods billed to one
+// party and shipped to another: the bill-to state (IGST Act s.10(1)(b)), or the
+// ship-to state when there is no bill-to address or it carries no state.
+func PlaceOfSupplyStateCode(billTo, shipTo *Address) int {
+	if code := AddressStateCode(billTo); code != 0 {
+		return code
+	}
+	return AddressStateCode(shipTo)
+}
+
+// ExpectedTaxType returns "cgst_sgst" for an intra-state supply and "igst" for
+// an inter-state one. Returns "" when either state is unknown.
+func ExpectedTaxType(supplierState, placeOfSupply int) string {
+	switch {
+	case supplierState == 0 || placeOfSupply == 0:
+		return ""
+	case supplierState == placeOfSupply:
+		return "cgst_sgst"
+	default:
+		return "igst"
+	}
+}
+
+// TaxTypeLabel returns the display name of a tax type.
+func TaxTypeLabel(taxType string) string {
+	if taxType == "igst" {
+		return "IGST"
+	}
+	return "CGST + SGST"
+}
+
+// TaxTypeCheck compares the tax type picked in a wizard with the one the state
+// codes call for.
+type TaxTypeCheck struct {
+	Chosen        string
+	Expected      string
+	SupplierState int
+	PlaceOfSupply int
+}
+
+// NewTaxTypeCheck builds a TaxTypeCheck. Returns nil when either state is
+// unknown, since no tax type can then be derived.
+func NewTaxTypeCheck(chosen string, supplierState, placeOfSupply int) *TaxTypeCheck {
+	expected := ExpectedTaxType(supplierState, placeOfSupply)
+	if expected == "" {
+		return nil
+	}
+	return &TaxTypeCheck{Chosen: chosen, Expected: expected, SupplierState: supplierState, PlaceOfSupply: placeOfSupply}
+}
+
+// Mismatch reports whether the chosen tax type contradicts the state codes.
+func (t *TaxTypeCheck) Mismatch() bool {
+	return t != nil && t.Chosen != t.Expected
+}
+
+// Message explains the mismatch in a sentence the wizard can show.
+func (t *TaxTypeCheck) Message() string {
+	if !t.Mismatch() {
+		return ""
+	}
+	kind := "an intra-state"
+	if t.Expected == "igst" {
+		kind = "an inter-state"
+	}
+	return fmt.Sprintf("Supplier state %02d and place of supply %02d make this %s supply, which is taxed as %s, but %s was chosen.",
+		t.SupplierState, t.PlaceOfSupply, kind, TaxTypeLabel(t.Expected), TaxTypeLabel(t.Chosen))
+}
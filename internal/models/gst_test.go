@@ -0,0 +1,101 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGSTStateCode(t *testing.T) {
+	tests := []struct {
+		state string
+		want  int
+	}{
+		{"Telangana", 36},
+		{"  andhra pradesh ", 37},
+		{"Jammu & Kashmir", 1},
+		{"Orissa", 21},
+		{"36", 36},
+		{"07", 7},
+		{"99", 0},
+		{"Atlantis", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := GSTStateCode(tt.state); got != tt.want {
+			t.Errorf("GSTStateCode(%q) = %d, want %d", tt.state, got, tt.want)
+		}
+	}
+
+	if got := GSTINStateCode("36AABCT1234F1Z5"); got != 36 {
+		t.Errorf("GSTINStateCode = %d, want 36", got)
+	}
+	if got := GSTINStateCode("URP"); got != 0 {
+		t.Errorf("GSTINStateCode(URP) = %d, want 0", got)
+	}
+}
+
+func TestAddressField(t *testing.T) {
+	a := &Address{Data: map[string]string{"Pin-code": " 500001 ", "GSTIN": "", "Gst No": "36AABCT1234F1Z5"}}
+
+	if got := a.Field("PIN Code"); got != "500001" {
+		t.Errorf("Field(PIN Code) = %q, want 500001", got)
+	}
+	if got := a.Field("GSTIN", "GST No"); got != "36AABCT1234F1Z5" {
+		t.Errorf("Field skipped the empty GSTIN column: got %q", got)
+	}
+	if got := a.Field("State"); got != "" {
+		t.Errorf("Field(State) = %q, want empty", got)
+	}
+	var nilAddr *Address
+	if got := nilAddr.Field("City"); got != "" {
+		t.Errorf("nil address Field = %q, want empty", got)
+	}
+}
+
+func TestTaxTypeCheck(t *testing.T) {
+	telangana := &Address{Data: map[string]string{"State": "Telangana"}}
+	andhra := &Address{Data: map[string]string{"GSTIN": "37AAACA1234B1ZH"}}
+	project := &Project{CompanyGSTIN: "36AACCF9742K1Z8"}
+	company := &CompanySettings{StateCode: "29"}
+
+	if got := SupplierStateCode(nil, project, company); got != 36 {
+		t.Errorf("supplier from project GSTIN = %d, want 36", got)
+	}
+	if got := SupplierStateCode(andhra, project, company); got != 37 {
+		t.Errorf("supplier from bill-from = %d, want 37", got)
+	}
+	if got := SupplierStateCode(nil, &Project{}, company); got != 29 {
+		t.Errorf("supplier from company settings = %d, want 29", got)
+	}
+	if got := PlaceOfSupplyStateCode(nil, andhra); got != 37 {
+		t.Errorf("place of supply from ship-to = %d, want 37", got)
+	}
+	if got := PlaceOfSupplyStateCode(telangana, andhra); got != 36 {
+		t.Errorf("place of supply prefers bill-to: got %d, want 36", got)
+	}
+
+	if check := NewTaxTypeCheck("cgst_sgst", 36, 36); check.Mismatch() {
+		t.Errorf("intra-state CGST+SGST flagged: %s", check.Message())
+	}
+	check := NewTaxTypeCheck("cgst_sgst", 36, 37)
+	if !check.Mismatch() || check.Expected != "igst" {
+		t.Fatalf("inter-state CGST+SGST not flagged: %+v", check)
+	}
+	if !strings.Contains(check.Message(), "IGST") {
+		t.Errorf("message = %q", check.Message())
+	}
+	if check := NewTaxTypeCheck("igst", 0, 37); check != nil || check.Mismatch() {
+		t.Errorf("unknown supplier state should give no check, got %+v", check)
+	}
+}
+
+func TestIsGSTINColumn(t *testing.T) {
+	for _, name := range []string{"GSTIN", "GST No.", "Gst Number"} {
+		if !IsGSTINColumn(name) {
+			t.Errorf("IsGSTINColumn(%q) = false", name)
+		}
+	}
+	if IsGSTINColumn("State") {
+		t.Error(`IsGSTINColumn("State") = true`)
+	}
+}
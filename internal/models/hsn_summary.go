@@ -0,0 +1,132 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// HSNSummaryRow is one line of an HSN-wise tax summary: the goods sharing an
+// HSN code, unit and GST rate, with the tax split into CGST/SGST or IGST.
+type HSNSummaryRow struct {
+	HSNCode      string
+	Description  string // item names, comma separated
+	UoM          string
+	TaxRate      float64 // full GST rate; CGST and SGST are charged at half each
+	Quantity     int
+	TaxableValue float64
+	CGST         float64
+	SGST         float64
+	IGST         float64
+}
+
+// TotalTax returns the CGST, SGST and IGST of the row together.
+func (r HSNSummaryRow) TotalTax() float64 {
+	return r.CGST + r.SGST + r.IGST
+}
+
+// TotalValue returns the taxable value plus tax.
+func (r HSNSummaryRow) TotalValue() float64 {
+	return r.TaxableValue + r.TotalTax()
+}
+
+// HSNSummary accumulates line items into HSN summary rows.
+type HSNSummary struct {
+	rows  []HSNSummaryRow
+	names []map[string]bool
+	index map[hsnSummaryKey]int
+}
+
+type hsnSummaryKey struct {
+	hsn  string
+	uom  string
+	rate float64
+}
+
+// Add adds goods to the row for their HSN code, unit and rate. taxType is
+// "igst" for inter-state supplies; anything else is split into CGST and SGST.
+func (s *HSNSummary) Add(hsn, name, uom string, rate float64, qty int, taxable, tax float64, taxType string) {
+	if s.index == nil {
+		s.index = make(map[hsnSummaryKey]int)
+	}
+	hsn = strings.TrimSpace(hsn)
+	uom = strings.TrimSpace(uom)
+	key := hsnSummaryKey{hsn: hsn, uom: strings.ToUpper(uom), rate: rate}
+	i, ok := s.index[key]
+	if !ok {
+		i = len(s.rows)
+		s.index[key] = i
+		s.rows = append(s.rows, HSNSummaryRow{HSNCode: hsn, UoM: uom, TaxRate: rate})
+		s.names = append(s.names, make(map[string]bool))
+	}
+
+	r := &s.rows[i]
+	r.Quantity += qty
+	r.TaxableValue += taxable
+	if taxType == "igst" {
+		r.IGST += tax
+	} else {
+		half := math.Round(tax*50) / 100
+		r.CGST += half
+		r.SGST += tax - half
+	}
+	if name = strings.TrimSpace(name); name != "" && !s.names[i][name] {
+		s.names[i][name] = true
+		if r.Description != "" {
+			r.Description += ", "
+		}
+		r.Description += name
+	}
+}
+
+// Rows returns the summary ordered by HSN code and rate, with amounts rounded
+// to paise.
+func (s *HSNSummary) Rows() []HSNSummaryRow {
+	rows := make([]HSNSummaryRow, len(s.rows))
+	copy(rows, s.rows)
+	for i := range rows {
+		rows[i].TaxableValue = RoundPaise(rows[i].TaxableValue)
+		rows[i].CGST = RoundPaise(rows[i].CGST)
+		rows[i].SGST = RoundPaise(rows[i].SGST)
+		rows[i].IGST = RoundPaise(rows[i].IGST)
+	}
+	sort.SliceStable(rows, func(a, b int) bool {
+		if rows[a].HSNCode != rows[b].HSNCode {
+			return rows[a].HSNCode < rows[b].HSNCode
+		}
+		return rows[a].TaxRate < rows[b].TaxRate
+	})
+	return rows
+}
+
+// SummariseByHSN groups a DC's line items by HSN code, unit and GST rate.
+func SummariseByHSN(items []DCLineItem, taxType string) []HSNSummaryRow {
+	var s HSNSummary
+	for _, li := range items {
+		s.Add(li.HSNCode, li.ItemName, li.UoM, li.TaxPercentage, li.Quantity, li.TaxableAmount, li.TaxAmount, taxType)
+	}
+	return s.Rows()
+}
+
+// HSNSummaryTotals adds up summary rows; the HSN, description, unit and rate
+// of the result are left empty.
+func HSNSummaryTotals(rows []HSNSummaryRow) HSNSummaryRow {
+	var t HSNSummaryRow
+	for _, r := range rows {
+		t.Quantity += r.Quantity
+		t.TaxableValue += r.TaxableValue
+		t.CGST += r.CGST
+		t.SGST += r.SGST
+		t.IGST += r.IGST
+	}
+	t.TaxableValue = RoundPaise(t.TaxableValue)
+	t.CGST = RoundPaise(t.CGST)
+	t.SGST = RoundPaise(t.SGST)
+	t.IGST = RoundPaise(t.IGST)
+	return t
+}
+
+// RoundPaise rounds an amount in rupees to the nearest paisa.
+func RoundPaise(v float64) float64 {
+	return math.Round(v*100) / 100
+}
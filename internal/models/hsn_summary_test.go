@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestSummariseByHSN(t *testing.T) {
+	items := []DCLineItem{
+		{ItemName: "Router", HSNCode: "8517", UoM: "Nos", Quantity: 2, TaxPercentage: 18, TaxableAmount: 1000, TaxAmount: 180},
+		{ItemName: "Cable", HSNCode: "8544", UoM: "Mtr", Quantity: 100, TaxPercentage: 12, TaxableAmount: 500, TaxAmount: 60},
+		{ItemName: "Switch", HSNCode: " 8517", UoM: "NOS", Quantity: 3, TaxPercentage: 18, TaxableAmount: 1500, TaxAmount: 270},
+		{ItemName: "Router", HSNCode: "8517", UoM: "Nos", Quantity: 1, TaxPercentage: 18, TaxableAmount: 500, TaxAmount: 90},
+		{ItemName: "Solar Panel", HSNCode: "8517", UoM: "Nos", Quantity: 1, TaxPercentage: 5, TaxableAmount: 101, TaxAmount: 5.05},
+	}
+
+	rows := SummariseByHSN(items, "cgst_sgst")
+	if len(rows) != 3 {
+		t.Fatalf("rows = %d, want 3: %+v", len(rows), rows)
+	}
+	// Sorted by HSN, then rate.
+	if rows[0].HSNCode != "8517" || rows[0].TaxRate != 5 || rows[1].TaxRate != 18 || rows[2].HSNCode != "8544" {
+		t.Fatalf("order = %+v", rows)
+	}
+	r := rows[1]
+	if r.Quantity != 6 || r.TaxableValue != 3000 || r.CGST != 270 || r.SGST != 270 || r.IGST != 0 {
+		t.Errorf("8517 @ 18%% = %+v", r)
+	}
+	if r.Description != "Router, Switch" {
+		t.Errorf("description = %q, want each item once", r.Description)
+	}
+	// Odd paise go to SGST so the halves add back up to the line tax.
+	if rows[0].CGST+rows[0].SGST != 5.05 {
+		t.Errorf("5%% row tax = %.2f + %.2f, want 5.05", rows[0].CGST, rows[0].SGST)
+	}
+
+	igst := SummariseByHSN(items, "igst")
+	if igst[1].IGST != 540 || igst[1].CGST != 0 || igst[1].TotalTax() != 540 {
+		t.Errorf("igst row = %+v", igst[1])
+	}
+
+	total := HSNSummaryTotals(rows)
+	if total.Quantity != 107 || total.TaxableValue != 3601 || total.TotalTax() != 605.05 || total.TotalValue() != 4206.05 {
+		t.Errorf("totals = %+v", total)
+	}
+}
@@ -5,18 +5,21 @@ import (
 )
 
 type Product struct {
-	ID              int       `json:"id"`
-	ProjectID       int       `json:"project_id"`
-	ProductCode     string    `json:"product_code"`
-	ItemName        string    `json:"item_name" validate:"required,max=255"`
-	ItemDescription string    `json:"item_description" validate:"required,max=1000"`
-	HSNCode         string    `json:"hsn_code"`
-	UoM             string    `json:"uom" validate:"required,max=50"`
-	BrandModel      string    `json:"brand_model" validate:"required,max=255"`
-	PerUnitPrice    float64   `json:"per_unit_price" validate:"required,gt=0"`
-	GSTPercentage   float64   `json:"gst_percentage" validate:"gte=0,lte=100"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              int            `json:"id"`
+	ProjectID       int            `json:"project_id"`
+	ProductCode     string         `json:"product_code"`
+	ItemName        string         `json:"item_name" validate:"required,max=255"`
+	ItemDescription string         `json:"item_description" validate:"required,max=1000"`
+	HSNCode         string         `json:"hsn_code"`
+	UoM             string         `json:"uom" validate:"required,max=50"`
+	BrandModel      string         `json:"brand_model" validate:"required,max=255"`
+	PerUnitPrice    float64        `json:"per_unit_price" validate:"required,gt=0"`
+	GSTPercentage   float64        `json:"gst_percentage" validate:"gte=0,lte=100"`
+	SerialRule      SerialRule     `json:"serial_rule"`
+	WarrantyMonths  int            `json:"warranty_months" validate:"gte=0,lte=600"`
+	Components      []KitComponent `json:"components,omitempty"` // bill of materials; empty unless the product is a kit
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
 }
 
 func (p *Product) PriceWithGST() float64 {
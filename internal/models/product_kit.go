@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KitComponent is one product in a kit's bill of materials.
+type KitComponent struct {
+	ProductID  int        `json:"product_id"`
+	ItemName   string     `json:"item_name"`
+	BrandModel string     `json:"brand_model"`
+	Quantity   int        `json:"quantity"` // units of the component in one kit
+	SerialRule SerialRule `json:"serial_rule"`
+
+	// SerialNumbers are the component's serials on a DC line; only set on
+	// the components of a line item.
+	SerialNumbers []string `json:"serial_numbers,omitempty"`
+}
+
+// IsKit reports whether the product is a kit of other products.
+func (p *Product) IsKit() bool {
+	return len(p.Components) > 0
+}
+
+// SerialsPerUnit returns how many serial numbers one unit of the product
+// carries: one for a plain product, one per component unit for a kit.
+func (p *Product) SerialsPerUnit() int {
+	return kitSerialsPerUnit(p.Components)
+}
+
+// KitBreakdown describes a kit's components, e.g. "1 x Projector, 2 x Speaker".
+func (p *Product) KitBreakdown() string {
+	return kitBreakdown(p.Components)
+}
+
+// SerialsRequired returns how many serial numbers the line carries: its
+// quantity, times the component units per kit for a kit.
+func (li *DCLineItem) SerialsRequired() int {
+	return li.Quantity * kitSerialsPerUnit(li.KitComponents)
+}
+
+// KitBreakdown describes the components of a kit line, "" for a plain product.
+func (li *DCLineItem) KitBreakdown() string {
+	return kitBreakdown(li.KitComponents)
+}
+
+func kitSerialsPerUnit(components []KitComponent) int {
+	if len(components) == 0 {
+		return 1
+	}
+	n := 0
+	for _, c := range components {
+		n += c.Quantity
+	}
+	return n
+}
+
+func kitBreakdown(components []KitComponent) string {
+	parts := make([]string, 0, len(components))
+	for _, c := range components {
+		parts = append(parts, fmt.Sprintf("%d x %s", c.Quantity, c.ItemName))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SerialSlotKey names the serial numbers of a product in the DC wizards'
+// serial step: "12" for product 12's own serials, "12-34" for those of
+// component 34 of kit 12.
+func SerialSlotKey(productID, componentID int) string {
+	if componentID == 0 {
+		return fmt.Sprint(productID)
+	}
+	return fmt.Sprintf("%d-%d", productID, componentID)
+}
+
+// ValidateKitComponents checks a kit's bill of materials against the
+// project's products, returning "" when it is usable. Components must be
+// other products of the project that are not kits themselves, each listed
+// once with a positive quantity. usedAsComponent reports whether the kit is
+// itself a component of another kit, which rules out giving it components.
+func ValidateKitComponents(kitID int, components []KitComponent, projectProducts []*Product, usedAsComponent bool) string {
+	if len(components) == 0 {
+		return ""
+	}
+	if usedAsComponent {
+		return "This product is a component of another kit, so it cannot have components itself"
+	}
+	byID := make(map[int]*Product, len(projectProducts))
+	for _, p := range projectProducts {
+		byID[p.ID] = p
+	}
+	seen := make(map[int]bool, len(components))
+	for _, c := range components {
+		p := byID[c.ProductID]
+		switch {
+		case p == nil:
+			return "Choose a product of this project for every component"
+		case kitID != 0 && c.ProductID == kitID:
+			return "A kit cannot be a component of itself"
+		case p.IsKit():
+			return fmt.Sprintf("%s is a kit; kits cannot be components of other kits", p.ItemName)
+		case seen[c.ProductID]:
+			return fmt.Sprintf("%s is listed more than once", p.ItemName)
+		case c.Quantity < 1:
+			return fmt.Sprintf("Quantity of %s must be at least 1", p.ItemName)
+		}
+		seen[c.ProductID] = true
+	}
+	return ""
+}
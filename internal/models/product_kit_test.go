@@ -0,0 +1,68 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProduct_KitSerials(t *testing.T) {
+	plain := &Product{ID: 1, ItemName: "Router"}
+	if plain.IsKit() || plain.SerialsPerUnit() != 1 || plain.KitBreakdown() != "" {
+		t.Errorf("plain product: kit=%v per unit=%d breakdown=%q", plain.IsKit(), plain.SerialsPerUnit(), plain.KitBreakdown())
+	}
+
+	kit := &Product{ID: 2, ItemName: "Smart Class Set", Components: []KitComponent{
+		{ProductID: 3, ItemName: "Projector", Quantity: 1},
+		{ProductID: 4, ItemName: "Speaker", Quantity: 2},
+	}}
+	if !kit.IsKit() || kit.SerialsPerUnit() != 3 {
+		t.Errorf("kit: kit=%v per unit=%d, want true/3", kit.IsKit(), kit.SerialsPerUnit())
+	}
+	if got := kit.KitBreakdown(); got != "1 x Projector, 2 x Speaker" {
+		t.Errorf("KitBreakdown = %q", got)
+	}
+
+	li := &DCLineItem{Quantity: 4, KitComponents: kit.Components}
+	if got := li.SerialsRequired(); got != 12 {
+		t.Errorf("SerialsRequired = %d, want 12", got)
+	}
+}
+
+func TestSerialSlotKey(t *testing.T) {
+	if got := SerialSlotKey(12, 0); got != "12" {
+		t.Errorf("SerialSlotKey(12, 0) = %q", got)
+	}
+	if got := SerialSlotKey(12, 34); got != "12-34" {
+		t.Errorf("SerialSlotKey(12, 34) = %q", got)
+	}
+}
+
+func TestValidateKitComponents(t *testing.T) {
+	products := []*Product{
+		{ID: 1, ItemName: "Smart Class Set"},
+		{ID: 2, ItemName: "Projector"},
+		{ID: 3, ItemName: "Speaker"},
+		{ID: 4, ItemName: "Lab Kit", Components: []KitComponent{{ProductID: 2, Quantity: 1}}},
+	}
+	tests := []struct {
+		name       string
+		components []KitComponent
+		used       bool
+		want       string
+	}{
+		{"no components", nil, true, ""},
+		{"valid", []KitComponent{{ProductID: 2, Quantity: 1}, {ProductID: 3, Quantity: 2}}, false, ""},
+		{"itself", []KitComponent{{ProductID: 1, Quantity: 1}}, false, "component of itself"},
+		{"unknown product", []KitComponent{{ProductID: 99, Quantity: 1}}, false, "Choose a product"},
+		{"nested kit", []KitComponent{{ProductID: 4, Quantity: 1}}, false, "Lab Kit is a kit"},
+		{"duplicate", []KitComponent{{ProductID: 2, Quantity: 1}, {ProductID: 2, Quantity: 1}}, false, "more than once"},
+		{"zero quantity", []KitComponent{{ProductID: 3, Quantity: 0}}, false, "at least 1"},
+		{"kit is a component", []KitComponent{{ProductID: 2, Quantity: 1}}, true, "component of another kit"},
+	}
+	for _, tt := range tests {
+		got := ValidateKitComponents(1, tt.components, products, tt.used)
+		if tt.want == "" && got != "" || tt.want != "" && !strings.Contains(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -16,7 +16,7 @@ type Project struct {
 	BillFromAddress      string    `json:"bill_from_address"`
 	DispatchFromAddress  string    `json:"dispatch_from_address"`
 	CompanyName          string    `json:"company_name"`
-	CompanyGSTIN         string    `json:"company_gstin" validate:"omitempty,len=15"`
+	CompanyGSTIN         string    `json:"company_gstin" validate:"omitempty,gstin"`
 	CompanyEmail         string    `json:"company_email" validate:"omitempty,email"`
 	CompanyCIN           string    `json:"company_cin"`
 	CompanyPAN           string    `json:"company_pan"`
@@ -46,6 +46,11 @@ func TestProjectValidate(t *testing.T) {
 			project:    Project{Name: "Test", DCPrefix: "SCP", CompanyGSTIN: "short"},
 			wantErrors: []string{"company_gstin"},
 		},
+		{
+			name:       "GSTIN check digit wrong",
+			project:    Project{Name: "Test", DCPrefix: "SCP", CompanyGSTIN: "36AACCF9742K1Z9"},
+			wantErrors: []string{"company_gstin"},
+		},
 		{
 			name:       "valid GSTIN",
 			project:    Project{Name: "Test", DCPrefix: "SCP", CompanyGSTIN: "36AACCF9742K1Z8"},
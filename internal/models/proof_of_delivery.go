@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ProofOfDelivery is a scanned, signed acknowledgement recorded against an official DC.
+// A partial POD leaves the DC partially delivered; a later full POD completes it.
+type ProofOfDelivery struct {
+	ID                  int       `json:"id"`
+	DCID                int       `json:"dc_id"`
+	ReceivedDate        string    `json:"received_date"`
+	ReceiverName        string    `json:"receiver_name"`
+	ReceiverDesignation string    `json:"receiver_designation"`
+	IsPartial           bool      `json:"is_partial"`
+	Remarks             string    `json:"remarks"`
+	FilePath            string    `json:"file_path"`
+	RecordedBy          *int      `json:"recorded_by"`
+	RecordedByName      string    `json:"recorded_by_name"`
+	RecordedAt          time.Time `json:"recorded_at"`
+}
+
+// DeliveryStatus returns the DC status this POD moves the DC to.
+func (p *ProofOfDelivery) DeliveryStatus() string {
+	if p.IsPartial {
+		return DCStatusPartiallyDelivered
+	}
+	return DCStatusDelivered
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Reasons equipment is returned from a ship-to location.
+const (
+	ReturnReasonFaulty     = "faulty"
+	ReturnReasonExcess     = "excess"
+	ReturnReasonWrongModel = "wrong_model"
+)
+
+// ReturnReasons lists the valid return reasons in display order.
+var ReturnReasons = []string{ReturnReasonFaulty, ReturnReasonExcess, ReturnReasonWrongModel}
+
+// ReturnReasonLabel returns the display label of a return reason.
+func ReturnReasonLabel(reason string) string {
+	switch reason {
+	case ReturnReasonFaulty:
+		return "Faulty"
+	case ReturnReasonExcess:
+		return "Excess"
+	case ReturnReasonWrongModel:
+		return "Wrong Model"
+	default:
+		return reason
+	}
+}
+
+// IsValidReturnReason reports whether reason is one of ReturnReasons.
+func IsValidReturnReason(reason string) bool {
+	for _, r := range ReturnReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// ReturnDC is a material return note: a "return" DC recording serials taken back
+// from the ship-to location of an earlier DC. The returned serials are released
+// so they can be dispatched again.
+type ReturnDC struct {
+	ID           int              `json:"id"`
+	DCID         int              `json:"dc_id"`
+	OriginalDCID int              `json:"original_dc_id"`
+	Reason       string           `json:"reason"`
+	Remarks      string           `json:"remarks"`
+	CreatedAt    time.Time        `json:"created_at"`
+	Serials      []ReturnDCSerial `json:"serials"`
+
+	// Joined fields
+	DCNumber         string `json:"dc_number"`
+	ChallanDate      string `json:"challan_date"`
+	OriginalDCNumber string `json:"original_dc_number"`
+}
+
+// ReturnDCSerial is one serial number on a return DC, or one serial an earlier DC
+// dispatched that can still be returned.
+type ReturnDCSerial struct {
+	SerialNumberID int    `json:"serial_number_id"`
+	LineItemID     int    `json:"line_item_id"`
+	ProductID      int    `json:"product_id"`
+	SerialNumber   string `json:"serial_number"`
+	ItemName       string `json:"item_name"`
+}
+
+// ReturnDCProduct groups the serials of a return DC by product.
+type ReturnDCProduct struct {
+	ProductID int
+	ItemName  string
+	Serials   []string
+}
+
+// Products groups the returned serials by product, in product name order.
+func (r *ReturnDC) Products() []ReturnDCProduct {
+	byProduct := make(map[int]*ReturnDCProduct)
+	var order []int
+	for _, s := range r.Serials {
+		p := byProduct[s.ProductID]
+		if p == nil {
+			p = &ReturnDCProduct{ProductID: s.ProductID, ItemName: s.ItemName}
+			byProduct[s.ProductID] = p
+			order = append(order, s.ProductID)
+		}
+		p.Serials = append(p.Serials, s.SerialNumber)
+	}
+	products := make([]ReturnDCProduct, 0, len(order))
+	for _, id := range order {
+		products = append(products, *byProduct[id])
+	}
+	sort.SliceStable(products, func(i, j int) bool { return products[i].ItemName < products[j].ItemName })
+	return products
+}
@@ -0,0 +1,33 @@
+package models
+
+import "testing"
+
+func TestReturnDCProducts(t *testing.T) {
+	r := &ReturnDC{Serials: []ReturnDCSerial{
+		{ProductID: 2, ItemName: "Router", SerialNumber: "R-1"},
+		{ProductID: 1, ItemName: "Camera", SerialNumber: "C-1"},
+		{ProductID: 2, ItemName: "Router", SerialNumber: "R-2"},
+	}}
+
+	products := r.Products()
+	if len(products) != 2 {
+		t.Fatalf("want 2 products, got %d", len(products))
+	}
+	if products[0].ItemName != "Camera" || len(products[0].Serials) != 1 {
+		t.Errorf("products[0] = %+v; want Camera with 1 serial", products[0])
+	}
+	if products[1].ItemName != "Router" || len(products[1].Serials) != 2 || products[1].Serials[1] != "R-2" {
+		t.Errorf("products[1] = %+v; want Router with R-1, R-2", products[1])
+	}
+}
+
+func TestIsValidReturnReason(t *testing.T) {
+	for _, r := range ReturnReasons {
+		if !IsValidReturnReason(r) {
+			t.Errorf("IsValidReturnReason(%q) = false", r)
+		}
+	}
+	if IsValidReturnReason("lost") {
+		t.Error("IsValidReturnReason(\"lost\") = true")
+	}
+}
@@ -0,0 +1,98 @@
+package models
+
+// Kinds of draft that serials can be scanned into.
+const (
+	SerialCaptureShipment = "shipment" // a shipment group; serials go on its transit DC
+	SerialCaptureTransfer = "transfer" // a transfer DC
+)
+
+// SerialCaptureSheet is what the scan screen needs of one draft: the DC that
+// carries the serials, its products and the destinations from the quantity grid.
+type SerialCaptureSheet struct {
+	Kind      string
+	EntityID  int // shipment group or transfer DC ID
+	ProjectID int
+	DCID      int // delivery challan whose line items hold the serials
+	DCNumber  string
+	Status    string
+
+	Products     []*SerialCaptureProduct
+	Destinations []*SerialCaptureDestination
+}
+
+// SerialCaptureProduct is one line item of the draft, or one component of a
+// kit line item.
+type SerialCaptureProduct struct {
+	ProductID  int
+	LineItemID int
+	ItemName   string
+	Rule       SerialRule
+	Required   int // line item quantity, times the units per kit for a component
+	Captured   int // serials on the line item, however entered
+
+	KitProductID int // kit the product is a component of; 0 for a plain line
+	PerKit       int // units of the component in one kit
+}
+
+// SerialCaptureDestination is one ship-to address of the draft.
+type SerialCaptureDestination struct {
+	AddressID int
+	Name      string
+	Required  map[int]int // product ID -> quantity from the quantity grid
+	Captured  map[int]int // product ID -> serials scanned for this destination
+}
+
+// Product returns the draft's line for a product, nil when it has none.
+func (s *SerialCaptureSheet) Product(productID int) *SerialCaptureProduct {
+	for _, p := range s.Products {
+		if p.ProductID == productID {
+			return p
+		}
+	}
+	return nil
+}
+
+// KitComponents returns the sheet's component lines of a kit product.
+func (s *SerialCaptureSheet) KitComponents(kitProductID int) []*SerialCaptureProduct {
+	var out []*SerialCaptureProduct
+	for _, p := range s.Products {
+		if p.KitProductID == kitProductID && kitProductID != 0 {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Destination returns the draft's destination at an address, nil when it has none.
+func (s *SerialCaptureSheet) Destination(addressID int) *SerialCaptureDestination {
+	for _, d := range s.Destinations {
+		if d.AddressID == addressID {
+			return d
+		}
+	}
+	return nil
+}
+
+// Remaining returns how many more serials of a product the sheet takes for a
+// destination: the lower of what the destination and the line item still need.
+func (s *SerialCaptureSheet) Remaining(productID, addressID int) int {
+	p, d := s.Product(productID), s.Destination(addressID)
+	if p == nil || d == nil {
+		return 0
+	}
+	left := d.Required[productID] - d.Captured[productID]
+	if lineLeft := p.Required - p.Captured; lineLeft < left {
+		left = lineLeft
+	}
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// CapturedSerial is a serial scanned for a destination.
+type CapturedSerial struct {
+	ID           int
+	SerialNumber string
+	CreatedAt    string
+}
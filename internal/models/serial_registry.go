@@ -0,0 +1,106 @@
+package models
+
+import "time"
+
+// Serial registry modes: how the DC wizards treat serials missing from a
+// project's serial registry.
+const (
+	SerialRegistryOff     = "off"     // serials are only checked for duplicates
+	SerialRegistryWarn    = "warn"    // unregistered serials are saved with a warning
+	SerialRegistryEnforce = "enforce" // unregistered or damaged serials are refused
+)
+
+// SerialRegistryModes lists the registry modes in display order.
+var SerialRegistryModes = []string{SerialRegistryOff, SerialRegistryWarn, SerialRegistryEnforce}
+
+// SerialRegistryModeLabel returns the display label of a registry mode.
+func SerialRegistryModeLabel(mode string) string {
+	switch mode {
+	case SerialRegistryOff:
+		return "Off"
+	case SerialRegistryWarn:
+		return "Warn"
+	case SerialRegistryEnforce:
+		return "Enforce"
+	default:
+		return mode
+	}
+}
+
+// IsValidSerialRegistryMode reports whether mode is one of SerialRegistryModes.
+func IsValidSerialRegistryMode(mode string) bool {
+	for _, m := range SerialRegistryModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// SerialConditionGood is the condition of a registered serial fit to dispatch;
+// the alternative is SerialConditionDamaged.
+const SerialConditionGood = "good"
+
+// States of a registered serial. Only the condition is stored; the rest is
+// derived from the DCs the serial is on.
+const (
+	SerialStateInStock    = "in_stock"   // registered and on no live DC
+	SerialStateAllocated  = "allocated"  // on a DC that has not been issued yet
+	SerialStateDispatched = "dispatched" // on an issued DC
+	SerialStateReturned   = "returned"   // taken back on a return DC and not dispatched again
+	SerialStateDamaged    = "damaged"    // marked damaged; never dispatched
+)
+
+// SerialStates lists the serial states in display order.
+var SerialStates = []string{
+	SerialStateInStock,
+	SerialStateAllocated,
+	SerialStateDispatched,
+	SerialStateReturned,
+	SerialStateDamaged,
+}
+
+// SerialStateLabel returns the display label of a serial state.
+func SerialStateLabel(state string) string {
+	switch state {
+	case SerialStateInStock:
+		return "In Stock"
+	case SerialStateAllocated:
+		return "Allocated"
+	case SerialStateDispatched:
+		return "Dispatched"
+	case SerialStateReturned:
+		return "Returned"
+	case SerialStateDamaged:
+		return "Damaged"
+	default:
+		return state
+	}
+}
+
+// RegistrySerial is a serial number stocked into a project's serial registry,
+// with the state derived from the DCs it is on.
+type RegistrySerial struct {
+	ID           int       `json:"id"`
+	ProjectID    int       `json:"project_id"`
+	ProductID    int       `json:"product_id"`
+	SerialNumber string    `json:"serial_number"`
+	Condition    string    `json:"condition"`
+	Source       string    `json:"source"` // packing list reference or file name
+	StockedInBy  *int      `json:"stocked_in_by"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Joined and derived fields
+	ItemName string `json:"item_name"`
+	State    string `json:"state"`
+	DCID     *int   `json:"dc_id"` // live DC the serial is on
+	DCNumber string `json:"dc_number"`
+}
+
+// SerialStockInResult summarises a stock-in: serials added to the registry,
+// serials already registered, and rows that could not be used.
+type SerialStockInResult struct {
+	Added    int
+	Existing int
+	Errors   []string
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// serialRuleListLimit is how many offending serials an error message names.
+const serialRuleListLimit = 5
+
+// SerialRule is the OEM format a product's serial numbers follow. Every part
+// is optional; the zero rule accepts any serial.
+type SerialRule struct {
+	Pattern   string `json:"pattern,omitempty"`    // regular expression the whole serial must match
+	MinLength int    `json:"min_length,omitempty"` // 0 for no minimum
+	MaxLength int    `json:"max_length,omitempty"` // 0 for no maximum
+	Prefix    string `json:"prefix,omitempty"`
+	Luhn      bool   `json:"luhn,omitempty"` // last digit is a Luhn check digit, as on IMEIs
+}
+
+// IsZero reports whether the rule accepts any serial.
+func (r SerialRule) IsZero() bool {
+	return r == SerialRule{}
+}
+
+// Validate checks that the rule itself is usable, returning errors keyed by
+// the product form field at fault.
+func (r SerialRule) Validate() map[string]string {
+	errs := map[string]string{}
+	if r.Pattern != "" {
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			errs["serial_pattern"] = "Invalid regular expression: " + err.Error()
+		}
+	}
+	if r.MinLength < 0 {
+		errs["serial_min_length"] = "Minimum length cannot be negative"
+	}
+	if r.MaxLength < 0 {
+		errs["serial_max_length"] = "Maximum length cannot be negative"
+	} else if r.MaxLength > 0 && r.MinLength > r.MaxLength {
+		errs["serial_max_length"] = "Maximum length must be at least the minimum length"
+	}
+	return errs
+}
+
+// Describe summarises the rule for display, "" for the zero rule.
+func (r SerialRule) Describe() string {
+	var parts []string
+	if r.Prefix != "" {
+		parts = append(parts, fmt.Sprintf("starts with %q", r.Prefix))
+	}
+	if r.MinLength > 0 || r.MaxLength > 0 {
+		parts = append(parts, r.lengthText())
+	}
+	if r.Pattern != "" {
+		parts = append(parts, "matches "+r.Pattern)
+	}
+	if r.Luhn {
+		parts = append(parts, "Luhn check digit")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Violations returns each serial that breaks the rule with the reason, such
+// as `AB12 (must start with "AX")`, in input order.
+func (r SerialRule) Violations(serials []string) []string {
+	if r.IsZero() {
+		return nil
+	}
+	var re *regexp.Regexp
+	if r.Pattern != "" {
+		// An invalid pattern is refused when the product is saved; skip it here.
+		re, _ = regexp.Compile(`^(?:` + r.Pattern + `)$`)
+	}
+	var out []string
+	for _, sn := range serials {
+		if reason := r.check(sn, re); reason != "" {
+			out = append(out, sn+" ("+reason+")")
+		}
+	}
+	return out
+}
+
+// check returns why a serial breaks the rule, "" when it does not.
+func (r SerialRule) check(sn string, re *regexp.Regexp) string {
+	n := len([]rune(sn))
+	switch {
+	case r.Prefix != "" && !strings.HasPrefix(sn, r.Prefix):
+		return fmt.Sprintf("must start with %q", r.Prefix)
+	case r.MinLength > 0 && n < r.MinLength, r.MaxLength > 0 && n > r.MaxLength:
+		return fmt.Sprintf("has %d characters, expected %s", n, r.lengthText())
+	case re != nil && !re.MatchString(sn):
+		return "does not match " + r.Pattern
+	case r.Luhn && !LuhnValid(sn):
+		return "check digit is wrong"
+	}
+	return ""
+}
+
+// lengthText describes the rule's length range; at least one bound is set.
+func (r SerialRule) lengthText() string {
+	switch {
+	case r.MinLength > 0 && r.MinLength == r.MaxLength:
+		return fmt.Sprintf("%d characters", r.MinLength)
+	case r.MinLength > 0 && r.MaxLength > 0:
+		return fmt.Sprintf("%d-%d characters", r.MinLength, r.MaxLength)
+	case r.MinLength > 0:
+		return fmt.Sprintf("at least %d characters", r.MinLength)
+	default:
+		return fmt.Sprintf("at most %d characters", r.MaxLength)
+	}
+}
+
+// SerialRuleError returns the message refusing serials of a product that
+// break its rule, naming the offending serials, or "" when all conform.
+func SerialRuleError(itemName string, rule SerialRule, serials []string) string {
+	bad := rule.Violations(serials)
+	if len(bad) == 0 {
+		return ""
+	}
+	list := bad
+	if len(list) > serialRuleListLimit {
+		list = list[:serialRuleListLimit]
+	}
+	msg := fmt.Sprintf("%s serials not in the required format (%s): %s", itemName, rule.Describe(), strings.Join(list, ", "))
+	if more := len(bad) - len(list); more > 0 {
+		msg += fmt.Sprintf(" and %d more", more)
+	}
+	return msg
+}
+
+// LuhnValid reports whether s is all digits and its last digit is the Luhn
+// check digit of the rest.
+func LuhnValid(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
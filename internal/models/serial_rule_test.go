@@ -0,0 +1,80 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSerialRule_Violations(t *testing.T) {
+	rule := SerialRule{Prefix: "35", MinLength: 15, MaxLength: 15, Pattern: `[0-9]+`, Luhn: true}
+	serials := []string{
+		"356938035643809", // valid IMEI
+		"356938035643808", // wrong check digit
+		"12345",           // wrong prefix
+		"3569380356438",   // too short
+		"35693803564380X", // not digits
+	}
+
+	bad := rule.Violations(serials)
+	if len(bad) != 4 {
+		t.Fatalf("want 4 violations, got %d: %v", len(bad), bad)
+	}
+	for i, want := range []string{"check digit", "must start with", "has 13 characters", "does not match"} {
+		if !strings.Contains(bad[i], want) {
+			t.Errorf("violation %d = %q, want it to mention %q", i, bad[i], want)
+		}
+	}
+}
+
+func TestSerialRule_ZeroAcceptsAll(t *testing.T) {
+	if bad := (SerialRule{}).Violations([]string{"anything", ""}); len(bad) != 0 {
+		t.Errorf("zero rule should accept any serial, got %v", bad)
+	}
+}
+
+func TestSerialRule_PatternIsAnchored(t *testing.T) {
+	rule := SerialRule{Pattern: `[A-Z]{2}\d{4}`}
+	if bad := rule.Violations([]string{"AB1234", "xAB1234", "AB12345"}); len(bad) != 2 {
+		t.Errorf("want the pattern to match whole serials only, got %v", bad)
+	}
+}
+
+func TestSerialRule_Validate(t *testing.T) {
+	errs := SerialRule{Pattern: "[", MinLength: 10, MaxLength: 8}.Validate()
+	if errs["serial_pattern"] == "" || errs["serial_max_length"] == "" {
+		t.Errorf("want pattern and length errors, got %v", errs)
+	}
+	if errs := (SerialRule{Pattern: `\d+`, MinLength: 8, MaxLength: 8}).Validate(); len(errs) != 0 {
+		t.Errorf("unexpected errors for a valid rule: %v", errs)
+	}
+}
+
+func TestSerialRuleError_NamesOffenders(t *testing.T) {
+	rule := SerialRule{Prefix: "SN"}
+	serials := []string{"SN1", "A1", "A2", "A3", "A4", "A5", "A6", "A7"}
+
+	msg := SerialRuleError("Router", rule, serials)
+	if !strings.HasPrefix(msg, "Router serials not in the required format") {
+		t.Errorf("unexpected message: %q", msg)
+	}
+	if !strings.Contains(msg, "A1 (") || strings.Contains(msg, "SN1") || !strings.HasSuffix(msg, "and 2 more") {
+		t.Errorf("want the first five offenders and a count of the rest, got %q", msg)
+	}
+	if msg := SerialRuleError("Router", rule, []string{"SN1"}); msg != "" {
+		t.Errorf("want no message for conforming serials, got %q", msg)
+	}
+}
+
+func TestLuhnValid(t *testing.T) {
+	for s, want := range map[string]bool{
+		"79927398713":     true,
+		"79927398710":     false,
+		"490154203237518": true,
+		"4901542032375X8": false,
+		"0":               false,
+	} {
+		if got := LuhnValid(s); got != want {
+			t.Errorf("LuhnValid(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package models
+
+// Kinds of event on a serial number's timeline.
+const (
+	SerialEventStockedIn  = "stocked_in" // entered into the serial registry
+	SerialEventTransfer   = "transfer"   // sent to a hub on a transfer DC
+	SerialEventSplit      = "split"      // allotted to a split of its transfer DC
+	SerialEventTransit    = "transit"    // carried on a transit DC
+	SerialEventOfficial   = "official"   // covered by an official DC of its shipment
+	SerialEventDispatched = "dispatched" // on a DC of any other type
+	SerialEventDelivered  = "delivered"  // proof of delivery recorded
+	SerialEventReceipt    = "receipt"    // reported damaged or missing on a goods receipt
+	SerialEventCancelled  = "cancelled"  // its DC was cancelled and the serial released
+	SerialEventReturned   = "returned"   // taken back on a return DC
+)
+
+// SerialEventLabel returns the display label of a timeline event kind.
+func SerialEventLabel(kind string) string {
+	switch kind {
+	case SerialEventStockedIn:
+		return "Stocked In"
+	case SerialEventTransfer:
+		return "Transfer to Hub"
+	case SerialEventSplit:
+		return "Split"
+	case SerialEventTransit:
+		return "Transit DC"
+	case SerialEventOfficial:
+		return "Official DC"
+	case SerialEventDispatched:
+		return "Dispatched"
+	case SerialEventDelivered:
+		return "Delivered"
+	case SerialEventReceipt:
+		return "Goods Receipt"
+	case SerialEventCancelled:
+		return "Cancelled"
+	case SerialEventReturned:
+		return "Returned"
+	default:
+		return kind
+	}
+}
+
+// SerialTimelineEvent is one step in the journey of a serial number.
+type SerialTimelineEvent struct {
+	Kind     string `json:"kind"`
+	Date     string `json:"date"` // YYYY-MM-DD; "" when the source has no date
+	Title    string `json:"title"`
+	Detail   string `json:"detail,omitempty"`
+	DCID     *int   `json:"dc_id,omitempty"`
+	DCNumber string `json:"dc_number,omitempty"`
+	DCType   string `json:"dc_type,omitempty"`
+	DCStatus string `json:"dc_status,omitempty"`
+	Address  string `json:"address,omitempty"` // hub for transfers, ship-to otherwise
+}
+
+// SerialTimeline is the chronological history of one serial number of one
+// product within a project.
+type SerialTimeline struct {
+	ProjectID    int    `json:"project_id"`
+	ProductID    int    `json:"product_id"`
+	ItemName     string `json:"item_name"`
+	SerialNumber string `json:"serial_number"`
+
+	// Summary of the serial's current position
+	Condition     string `json:"condition,omitempty"` // registry condition; "" when not registered
+	CurrentDCID   *int   `json:"current_dc_id"`
+	CurrentDC     string `json:"current_dc_number,omitempty"`
+	HubAddress    string `json:"hub_address,omitempty"`
+	ShipToAddress string `json:"ship_to_address,omitempty"`
+
+	Events []SerialTimelineEvent `json:"events"`
+}
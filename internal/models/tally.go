@@ -0,0 +1,67 @@
+package models
+
+// TallySettings names the Tally masters a project's Delivery Note import posts
+// to. The names must match the masters in the accounts team's Tally company.
+type TallySettings struct {
+	ProjectID      int    `json:"project_id"`
+	CompanyName    string `json:"company_name"` // blank imports into the company open in Tally
+	VoucherType    string `json:"voucher_type"`
+	SalesLedger    string `json:"sales_ledger"`
+	CGSTLedger     string `json:"cgst_ledger"`
+	SGSTLedger     string `json:"sgst_ledger"`
+	IGSTLedger     string `json:"igst_ledger"`
+	PartyGroup     string `json:"party_group"` // group new party ledgers are created under
+	Godown         string `json:"godown"`
+	IncludeMasters bool   `json:"include_masters"` // create party ledgers, stock items and units with the vouchers
+}
+
+// DefaultTallySettings returns the settings of a project that has not mapped its
+// Tally masters: Tally's own default names.
+func DefaultTallySettings(projectID int) *TallySettings {
+	return &TallySettings{
+		ProjectID:      projectID,
+		VoucherType:    "Delivery Note",
+		SalesLedger:    "Sales",
+		CGSTLedger:     "Output CGST",
+		SGSTLedger:     "Output SGST",
+		IGSTLedger:     "Output IGST",
+		PartyGroup:     "Sundry Debtors",
+		Godown:         "Main Location",
+		IncludeMasters: true,
+	}
+}
+
+// TallyExclusion explains why a DC is not exported to Tally, or returns "" when
+// it is. Each movement of goods is booked once, on the DCs the HSN summary
+// report counts: issued transit and transfer DCs. Official DCs repeat their
+// transit DC's goods per destination, a transit DC split from a Transfer DC
+// (splitFromTransfer) repeats the transfer, and return DCs bring goods back.
+func TallyExclusion(dc *DeliveryChallan, splitFromTransfer bool) string {
+	switch dc.Status {
+	case DCStatusDraft, DCStatusPendingApproval, DCStatusRejected, DCStatusCancelled:
+		return "Only issued DCs are exported to Tally"
+	}
+	switch dc.DCType {
+	case "official":
+		return "Official DCs are booked in Tally through their Transit DC"
+	case "return":
+		return "Return DCs are not exported to Tally"
+	case "transit":
+		if splitFromTransfer {
+			return "Transit DCs split from a Transfer DC are booked in Tally through the Transfer DC"
+		}
+	}
+	return ""
+}
+
+// TallyReady reports whether a DC is exported to Tally; see TallyExclusion.
+func TallyReady(dc *DeliveryChallan, splitFromTransfer bool) bool {
+	return dc != nil && TallyExclusion(dc, splitFromTransfer) == ""
+}
+
+// TallyCheck lists what stops a DC from being exported as a Tally voucher.
+type TallyCheck struct {
+	DCID     int
+	DCNumber string
+	Problems []string
+}
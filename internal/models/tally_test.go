@@ -0,0 +1,44 @@
+package models
+
+import "testing"
+
+func TestTallyExclusion(t *testing.T) {
+	groupID := 7
+	transit := &DeliveryChallan{ID: 1, DCType: "transit", Status: DCStatusIssued, ShipmentGroupID: &groupID}
+	officials := []*DeliveryChallan{
+		{ID: 2, DCType: "official", Status: DCStatusIssued, ShipmentGroupID: &groupID},
+		{ID: 3, DCType: "official", Status: DCStatusIssued, ShipmentGroupID: &groupID},
+	}
+
+	// A transit DC and its officials are one movement, booked once on the transit DC.
+	if !TallyReady(transit, false) {
+		t.Errorf("transit DC excluded: %s", TallyExclusion(transit, false))
+	}
+	for _, dc := range officials {
+		if TallyReady(dc, false) {
+			t.Errorf("official DC %d is exported alongside its transit DC", dc.ID)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		dc    *DeliveryChallan
+		split bool
+		want  bool
+	}{
+		{"issued transfer", &DeliveryChallan{DCType: "transfer", Status: DCStatusIssued}, false, true},
+		{"delivered transit", &DeliveryChallan{DCType: "transit", Status: DCStatusDelivered}, false, true},
+		{"transit split from a transfer", &DeliveryChallan{DCType: "transit", Status: DCStatusIssued}, true, false},
+		{"return", &DeliveryChallan{DCType: "return", Status: DCStatusIssued}, false, false},
+		{"draft transit", &DeliveryChallan{DCType: "transit", Status: DCStatusDraft}, false, false},
+		{"cancelled transfer", &DeliveryChallan{DCType: "transfer", Status: DCStatusCancelled}, false, false},
+	}
+	for _, tt := range tests {
+		if got := TallyReady(tt.dc, tt.split); got != tt.want {
+			t.Errorf("%s: TallyReady = %v, want %v (%s)", tt.name, got, tt.want, TallyExclusion(tt.dc, tt.split))
+		}
+	}
+	if TallyReady(nil, false) {
+		t.Error("nil DC is ready")
+	}
+}
@@ -8,7 +8,7 @@ type Transporter struct {
 	CompanyName   string                `json:"company_name" validate:"required,max=255"`
 	ContactPerson string                `json:"contact_person" validate:"max=255"`
 	Phone         string                `json:"phone" validate:"max=15"`
-	GSTNumber     string                `json:"gst_number" validate:"omitempty,len=15"`
+	GSTNumber     string                `json:"gst_number" validate:"omitempty,gstin"`
 	IsActive      bool                  `json:"is_active"`
 	CreatedAt     time.Time             `json:"created_at"`
 	UpdatedAt     time.Time             `json:"updated_at"`
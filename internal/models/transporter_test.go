@@ -30,7 +30,7 @@ func TestTransporterValidate(t *testing.T) {
 				CompanyName:   "ABC Transport",
 				ContactPerson: "John",
 				Phone:         "9876543210",
-				GSTNumber:     "27AABCU9603R1ZM",
+				GSTNumber:     "27AABCU9603R1ZN",
 			},
 			wantErr: false,
 		},
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// WarrantySerial is a serial number delivered to a destination, with the
+// warranty that runs from its delivery.
+type WarrantySerial struct {
+	SerialNumber string
+	ItemName     string
+	DCNumber     string // DC the serial was delivered on
+	District     string
+	Mandal       string
+	ShipTo       string
+	StartDate    time.Time
+	FromPOD      bool // StartDate is the POD received date rather than the challan date
+	Months       int
+}
+
+// EffectiveWarrantyMonths returns a project's warranty override when it has
+// one, otherwise the product's own period.
+func EffectiveWarrantyMonths(productMonths, projectMonths int) int {
+	if projectMonths > 0 {
+		return projectMonths
+	}
+	return productMonths
+}
+
+// Expiry returns the last day the serial is under warranty.
+func (w WarrantySerial) Expiry() time.Time {
+	return w.StartDate.AddDate(0, w.Months, -1)
+}
+
+// DaysLeft returns the days from today until the warranty expires; 0 on the
+// last day and negative once expired.
+func (w WarrantySerial) DaysLeft(today time.Time) int {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	return int(w.Expiry().Sub(today).Hours() / 24)
+}
+
+// WarrantyGroup is the serials of one district and mandal.
+type WarrantyGroup struct {
+	District string
+	Mandal   string
+	Serials  []WarrantySerial
+}
+
+// ExpiringWarranties keeps the serials whose warranty expires within days of
+// today and groups them by district and mandal, soonest expiry first within a
+// group. Already expired serials are kept only when includeExpired is set.
+// Serials without a warranty period or start date are left out.
+func ExpiringWarranties(serials []WarrantySerial, today time.Time, days int, includeExpired bool) []WarrantyGroup {
+	var keep []WarrantySerial
+	for _, s := range serials {
+		if s.Months <= 0 || s.StartDate.IsZero() {
+			continue
+		}
+		left := s.DaysLeft(today)
+		if left > days || (left < 0 && !includeExpired) {
+			continue
+		}
+		keep = append(keep, s)
+	}
+	sort.SliceStable(keep, func(a, b int) bool {
+		if keep[a].District != keep[b].District {
+			return keep[a].District < keep[b].District
+		}
+		if keep[a].Mandal != keep[b].Mandal {
+			return keep[a].Mandal < keep[b].Mandal
+		}
+		return keep[a].Expiry().Before(keep[b].Expiry())
+	})
+
+	var groups []WarrantyGroup
+	for _, s := range keep {
+		if n := len(groups); n == 0 || groups[n-1].District != s.District || groups[n-1].Mandal != s.Mandal {
+			groups = append(groups, WarrantyGroup{District: s.District, Mandal: s.Mandal})
+		}
+		g := &groups[len(groups)-1]
+		g.Serials = append(g.Serials, s)
+	}
+	return groups
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWarrantyExpiry(t *testing.T) {
+	w := WarrantySerial{StartDate: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), Months: 36}
+	if got := w.Expiry().Format("2006-01-02"); got != "2028-01-09" {
+		t.Errorf("Expiry = %s, want 2028-01-09", got)
+	}
+	if got := w.DaysLeft(time.Date(2028, 1, 9, 17, 30, 0, 0, time.UTC)); got != 0 {
+		t.Errorf("DaysLeft on the last day = %d, want 0", got)
+	}
+	if got := w.DaysLeft(time.Date(2028, 1, 12, 0, 0, 0, 0, time.UTC)); got != -3 {
+		t.Errorf("DaysLeft after expiry = %d, want -3", got)
+	}
+	if got := EffectiveWarrantyMonths(36, 60); got != 60 {
+		t.Errorf("EffectiveWarrantyMonths with override = %d, want 60", got)
+	}
+	if got := EffectiveWarrantyMonths(36, 0); got != 36 {
+		t.Errorf("EffectiveWarrantyMonths without override = %d, want 36", got)
+	}
+}
+
+func TestExpiringWarranties(t *testing.T) {
+	start := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) // 36 months expire 2026-05-31
+	today := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	serials := []WarrantySerial{
+		{SerialNumber: "A1", District: "Guntur", Mandal: "Tenali", StartDate: start, Months: 36},
+		{SerialNumber: "A2", District: "Guntur", Mandal: "Tenali", StartDate: start.AddDate(0, 0, -10), Months: 36},
+		{SerialNumber: "B1", District: "Guntur", Mandal: "Bapatla", StartDate: start, Months: 36},
+		{SerialNumber: "C1", District: "Krishna", Mandal: "Gudivada", StartDate: start, Months: 60},                   // years away
+		{SerialNumber: "D1", District: "Krishna", Mandal: "Gudivada", StartDate: start.AddDate(0, -2, 0), Months: 36}, // expired
+		{SerialNumber: "E1", District: "Krishna", Mandal: "Gudivada", StartDate: start},                               // no warranty
+		{SerialNumber: "F1", District: "Krishna", Mandal: "Gudivada", Months: 36},                                     // no start date
+	}
+
+	groups := ExpiringWarranties(serials, today, 90, false)
+	if len(groups) != 2 || groups[0].Mandal != "Bapatla" || groups[1].Mandal != "Tenali" {
+		t.Fatalf("groups = %+v, want Guntur/Bapatla then Guntur/Tenali", groups)
+	}
+	if s := groups[1].Serials; len(s) != 2 || s[0].SerialNumber != "A2" || s[1].SerialNumber != "A1" {
+		t.Errorf("Tenali serials = %+v, want A2 (expires first) then A1", s)
+	}
+
+	groups = ExpiringWarranties(serials, today, 90, true)
+	if len(groups) != 3 || groups[2].District != "Krishna" || len(groups[2].Serials) != 1 || groups[2].Serials[0].SerialNumber != "D1" {
+		t.Errorf("with expired = %+v, want D1 under Krishna/Gudivada", groups)
+	}
+}
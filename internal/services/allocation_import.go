@@ -0,0 +1,332 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// allocationHeaderRows is how far down an allocation sheet the header row is
+// looked for.
+const allocationHeaderRows = 20
+
+// AllocationWorkbook is a bulk shipment allocation: rows of destination
+// address code × product quantities, grouped into shipments, and optionally
+// the serials going to each destination.
+type AllocationWorkbook struct {
+	HasGroups bool     // whether the sheet has a Group column; without one all rows are one shipment
+	Products  []string // product column headers, in sheet order
+	Rows      []AllocationRow
+	Serials   []AllocationSerial
+}
+
+// AllocationRow is one destination of an allocation sheet. Quantities holds
+// the units of each product column with a quantity, keyed by its index in
+// AllocationWorkbook.Products.
+type AllocationRow struct {
+	Row             int // sheet row, for error messages
+	Group           string
+	AddressCode     string
+	Transit         bool // the transit DC of the group ships to this destination
+	VehicleNumber   string
+	TransporterName string
+	Quantities      map[int]int
+}
+
+// AllocationSerial is one serial of the serial sheet and the destination and
+// product it goes to.
+type AllocationSerial struct {
+	Row         int
+	AddressCode string
+	ProductCode string
+	Serial      string
+}
+
+// allocationColumn is what an allocation sheet column holds.
+type allocationColumn int
+
+const (
+	allocColProduct allocationColumn = iota
+	allocColIgnored
+	allocColGroup
+	allocColAddressCode
+	allocColTransit
+	allocColVehicle
+	allocColTransporter
+)
+
+// allocationColumnKind classifies a lower-cased allocation sheet header.
+// Descriptive columns such as the district or ship-to name are ignored; any
+// other column is a product.
+func allocationColumnKind(h string) allocationColumn {
+	switch {
+	case h == "":
+		return allocColIgnored
+	case strings.Contains(h, "address code") || h == "code":
+		return allocColAddressCode
+	case strings.Contains(h, "group") || h == "shipment":
+		return allocColGroup
+	case strings.Contains(h, "transit"):
+		return allocColTransit
+	case strings.Contains(h, "vehicle"):
+		return allocColVehicle
+	case strings.Contains(h, "transporter"):
+		return allocColTransporter
+	case strings.Contains(h, "district") || strings.Contains(h, "mandal") || strings.Contains(h, "ship to") ||
+		strings.Contains(h, "ship-to") || h == "destination" || h == "location" || h == "name" ||
+		h == "s.no" || h == "s.no." || h == "sl. no." || h == "sl.no" || h == "sr. no." || h == "sno" ||
+		strings.Contains(h, "total") || strings.Contains(h, "remarks"):
+		return allocColIgnored
+	}
+	return allocColProduct
+}
+
+// ParseAllocationWorkbook reads a bulk shipment allocation from Excel (.xlsx)
+// or CSV. The allocation sheet's header row is the first with an Address Code
+// column; its other columns are Group, Transit, Vehicle Number and
+// Transporter, and one quantity column per product code. In a workbook,
+// another sheet with Address Code, Product Code and Serial Number columns
+// maps serials to destinations. Rows without quantities are skipped.
+func ParseAllocationWorkbook(r io.Reader, filename string) (*AllocationWorkbook, error) {
+	type sheet struct {
+		name  string
+		rows  [][]string
+		lines []int // file line of each row, when it is not the row index + 1
+	}
+	var sheets []sheet
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		s := sheet{name: filename}
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CSV: %v", err)
+			}
+			// Blank lines are skipped by the reader; keep the file's line numbers.
+			line, _ := reader.FieldPos(0)
+			s.lines = append(s.lines, line)
+			s.rows = append(s.rows, record)
+		}
+		sheets = append(sheets, s)
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Excel file: %v", err)
+		}
+		defer f.Close()
+		for _, name := range f.GetSheetList() {
+			rows, err := f.GetRows(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read sheet %q: %v", name, err)
+			}
+			sheets = append(sheets, sheet{name: name, rows: rows})
+		}
+	default:
+		return nil, fmt.Errorf("only Excel (.xlsx) and CSV allocation sheets are supported")
+	}
+	lineOf := func(s sheet, i int) int {
+		if s.lines != nil {
+			return s.lines[i]
+		}
+		return i + 1
+	}
+
+	wb := &AllocationWorkbook{}
+	allocation := -1
+	for n, s := range sheets {
+		if findSerialSheetHeader(s.rows) >= 0 {
+			continue
+		}
+		headerRow, kinds := findAllocationHeader(s.rows)
+		if headerRow < 0 {
+			continue
+		}
+		allocation = n
+		productIndex := make(map[int]int) // sheet column -> index in wb.Products
+		for j, k := range kinds {
+			switch k {
+			case allocColProduct:
+				productIndex[j] = len(wb.Products)
+				wb.Products = append(wb.Products, strings.TrimSpace(s.rows[headerRow][j]))
+			case allocColGroup:
+				wb.HasGroups = true
+			}
+		}
+		if len(wb.Products) == 0 {
+			return nil, fmt.Errorf("sheet %q: no product columns found in the header row", s.name)
+		}
+
+		for i := headerRow + 1; i < len(s.rows); i++ {
+			line := lineOf(s, i)
+			row := AllocationRow{Row: line, Quantities: make(map[int]int)}
+			for j, cell := range s.rows[i] {
+				if j >= len(kinds) {
+					break
+				}
+				cell = strings.TrimSpace(cell)
+				switch kinds[j] {
+				case allocColGroup:
+					row.Group = cell
+				case allocColAddressCode:
+					row.AddressCode = cell
+				case allocColTransit:
+					row.Transit = allocationFlag(cell)
+				case allocColVehicle:
+					row.VehicleNumber = cell
+				case allocColTransporter:
+					row.TransporterName = cell
+				case allocColProduct:
+					if cell == "" {
+						continue
+					}
+					qty, err := strconv.Atoi(strings.ReplaceAll(cell, ",", ""))
+					if err != nil || qty < 0 {
+						return nil, fmt.Errorf("row %d, %s: %q is not a whole number of units", line, wb.Products[productIndex[j]], cell)
+					}
+					if qty > 0 {
+						row.Quantities[productIndex[j]] = qty
+					}
+				}
+			}
+			if len(row.Quantities) == 0 {
+				continue
+			}
+			wb.Rows = append(wb.Rows, row)
+		}
+		break
+	}
+	if allocation < 0 {
+		return nil, fmt.Errorf("no allocation sheet found: it needs an Address Code column and one column per product")
+	}
+
+	for n, s := range sheets {
+		if n == allocation {
+			continue
+		}
+		headerRow := findSerialSheetHeader(s.rows)
+		if headerRow < 0 {
+			continue
+		}
+		addressCol, productCol, serialCol := -1, -1, -1
+		for j, cell := range s.rows[headerRow] {
+			h := strings.ToLower(strings.TrimSpace(cell))
+			switch {
+			case addressCol < 0 && (strings.Contains(h, "address") || strings.Contains(h, "destination")):
+				addressCol = j
+			case serialCol < 0 && isSerialHeader(h):
+				serialCol = j
+			case productCol < 0 && (strings.Contains(h, "product") || strings.Contains(h, "model") ||
+				strings.Contains(h, "item") || h == "code"):
+				productCol = j
+			}
+		}
+		if addressCol < 0 || productCol < 0 || serialCol < 0 {
+			return nil, fmt.Errorf("sheet %q: the serial sheet needs Address Code, Product Code and Serial Number columns", s.name)
+		}
+		for i := headerRow + 1; i < len(s.rows); i++ {
+			row := s.rows[i]
+			cell := func(j int) string {
+				if j < len(row) {
+					return strings.TrimSpace(row[j])
+				}
+				return ""
+			}
+			for _, sn := range SplitSerials(cell(serialCol)) {
+				wb.Serials = append(wb.Serials, AllocationSerial{
+					Row:         lineOf(s, i),
+					AddressCode: cell(addressCol),
+					ProductCode: cell(productCol),
+					Serial:      sn,
+				})
+			}
+		}
+	}
+	return wb, nil
+}
+
+// findAllocationHeader returns the index of the allocation header row and the
+// kind of each of its columns, or -1 when no row has an Address Code column.
+func findAllocationHeader(rows [][]string) (int, []allocationColumn) {
+	for i := 0; i < len(rows) && i < allocationHeaderRows; i++ {
+		kinds := make([]allocationColumn, len(rows[i]))
+		found := false
+		for j, cell := range rows[i] {
+			kinds[j] = allocationColumnKind(strings.ToLower(strings.TrimSpace(cell)))
+			if kinds[j] == allocColAddressCode {
+				found = true
+			}
+		}
+		if found {
+			return i, kinds
+		}
+	}
+	return -1, nil
+}
+
+// findSerialSheetHeader returns the index of the first row with a serial
+// number column, or -1 when the sheet has none.
+func findSerialSheetHeader(rows [][]string) int {
+	for i := 0; i < len(rows) && i < allocationHeaderRows; i++ {
+		for _, cell := range rows[i] {
+			if isSerialHeader(strings.ToLower(strings.TrimSpace(cell))) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// allocationFlag reads a yes/no cell such as the Transit column.
+func allocationFlag(cell string) bool {
+	switch strings.ToLower(cell) {
+	case "y", "yes", "x", "1", "true", "✓":
+		return true
+	}
+	return false
+}
+
+// WriteAllocationTemplate writes an allocation workbook to fill in: the
+// allocation sheet with one row per destination and one column per product,
+// and an empty serial sheet.
+func WriteAllocationTemplate(w io.Writer, products []string, destinations [][2]string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+	name := "Allocation"
+	_ = f.SetSheetName("Sheet1", name)
+
+	header := []interface{}{"Group", "Address Code", "Ship To", "Transit", "Vehicle Number", "Transporter"}
+	for _, p := range products {
+		header = append(header, p)
+	}
+	if err := f.SetSheetRow(name, "A1", &header); err != nil {
+		return err
+	}
+	for i, d := range destinations {
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := f.SetSheetRow(name, cell, &[]interface{}{nil, d[0], d[1]}); err != nil {
+			return err
+		}
+	}
+	_ = f.SetColWidth(name, "B", "C", 24)
+
+	serials := "Serials"
+	if _, err := f.NewSheet(serials); err != nil {
+		return err
+	}
+	if err := f.SetSheetRow(serials, "A1", &[]interface{}{"Address Code", "Product Code", "Serial Number"}); err != nil {
+		return err
+	}
+	_ = f.SetColWidth(serials, "A", "C", 24)
+	return f.Write(w)
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseAllocationWorkbookCSV(t *testing.T) {
+	csv := "Allocation for November\n\nGroup,Address Code,Ship To,Transit,Vehicle Number,RTR-01,SW24,Total\n" +
+		"A,ZP-1,ZPHS Tenali,,AP07 1234,\"1,000\",2,1002\n" +
+		"A,ZP-2,ZPHS Bapatla,yes,,5,,5\n" +
+		"B,ZP-3,ZPHS Ponnur,,,,,0\n"
+	wb, err := ParseAllocationWorkbook(strings.NewReader(csv), "allocation.CSV")
+	if err != nil {
+		t.Fatalf("ParseAllocationWorkbook: %v", err)
+	}
+	if !wb.HasGroups {
+		t.Error("HasGroups = false, want true")
+	}
+	if want := []string{"RTR-01", "SW24"}; !reflect.DeepEqual(wb.Products, want) {
+		t.Errorf("products = %q, want %q", wb.Products, want)
+	}
+	want := []AllocationRow{
+		{Row: 4, Group: "A", AddressCode: "ZP-1", VehicleNumber: "AP07 1234", Quantities: map[int]int{0: 1000, 1: 2}},
+		{Row: 5, Group: "A", AddressCode: "ZP-2", Transit: true, Quantities: map[int]int{0: 5}},
+	}
+	if !reflect.DeepEqual(wb.Rows, want) {
+		t.Errorf("rows = %+v, want %+v", wb.Rows, want)
+	}
+}
+
+func TestParseAllocationWorkbookErrors(t *testing.T) {
+	tests := []struct {
+		name, csv, want string
+	}{
+		{"no header", "Group,Router\nA,2\n", "no allocation sheet"},
+		{"no products", "Address Code,Ship To,Total\nZP-1,Tenali,4\n", "no product columns"},
+		{"fraction", "Address Code,Router\nZP-1,2.5\n", `row 2, Router: "2.5" is not a whole number`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAllocationWorkbook(strings.NewReader(tt.csv), "allocation.csv")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+	if _, err := ParseAllocationWorkbook(strings.NewReader(""), "allocation.pdf"); err == nil {
+		t.Error("expected an error for an unsupported file type")
+	}
+}
+
+func TestAllocationTemplateRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteAllocationTemplate(&buf, []string{"RTR-01", "SW24"}, [][2]string{{"ZP-1", "ZPHS Tenali"}, {"ZP-2", "ZPHS Bapatla"}}); err != nil {
+		t.Fatalf("WriteAllocationTemplate: %v", err)
+	}
+
+	// Fill the template in as a user would.
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("OpenReader: %v", err)
+	}
+	for cell, v := range map[string]interface{}{"A2": "1", "G2": 2, "H2": 1, "A3": "1", "D3": "Y", "G3": 1} {
+		_ = f.SetCellValue("Allocation", cell, v)
+	}
+	_ = f.SetSheetRow("Serials", "A2", &[]interface{}{"ZP-1", "RTR-01", "R1, R2"})
+	_ = f.SetSheetRow("Serials", "A3", &[]interface{}{"ZP-2", "RTR-01", "R3"})
+	var filled bytes.Buffer
+	if err := f.Write(&filled); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	wb, err := ParseAllocationWorkbook(&filled, "allocation.xlsx")
+	if err != nil {
+		t.Fatalf("ParseAllocationWorkbook: %v", err)
+	}
+	want := &AllocationWorkbook{
+		HasGroups: true,
+		Products:  []string{"RTR-01", "SW24"},
+		Rows: []AllocationRow{
+			{Row: 2, Group: "1", AddressCode: "ZP-1", Quantities: map[int]int{0: 2, 1: 1}},
+			{Row: 3, Group: "1", AddressCode: "ZP-2", Transit: true, Quantities: map[int]int{0: 1}},
+		},
+		Serials: []AllocationSerial{
+			{Row: 2, AddressCode: "ZP-1", ProductCode: "RTR-01", Serial: "R1"},
+			{Row: 2, AddressCode: "ZP-1", ProductCode: "RTR-01", Serial: "R2"},
+			{Row: 3, AddressCode: "ZP-2", ProductCode: "RTR-01", Serial: "R3"},
+		},
+	}
+	if !reflect.DeepEqual(wb, want) {
+		t.Errorf("workbook = %+v, want %+v", wb, want)
+	}
+}
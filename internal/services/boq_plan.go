@@ -0,0 +1,222 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// boqHeaderRows is how far down a BOQ sheet the header row is looked for;
+// tender annexures usually open with a title and a few lines of notes.
+const boqHeaderRows = 20
+
+// BOQSheet is a bill of quantities laid out as in tender annexures: one row
+// per destination and one column of planned quantities per product.
+type BOQSheet struct {
+	Products []string // product column headers, in sheet order
+	Rows     []BOQSheetRow
+}
+
+// BOQSheetRow is one destination of a BOQ sheet. Any of the identifying
+// columns may be empty; Quantities holds the planned quantity of each product
+// column that has one, keyed by its index in BOQSheet.Products.
+type BOQSheetRow struct {
+	Row         int // sheet row, for error messages
+	District    string
+	Mandal      string
+	MandalCode  string
+	AddressCode string
+	ShipTo      string
+	Quantities  map[int]int
+}
+
+// boqColumn is what a BOQ sheet column holds.
+type boqColumn int
+
+const (
+	boqColProduct boqColumn = iota
+	boqColIgnored
+	boqColDistrict
+	boqColMandal
+	boqColMandalCode
+	boqColAddressCode
+	boqColShipTo
+)
+
+// boqColumnKind classifies a lower-cased BOQ sheet header. Serial number and
+// total columns are ignored; any other column is a product.
+func boqColumnKind(h string) boqColumn {
+	switch {
+	case h == "":
+		return boqColIgnored
+	case strings.Contains(h, "district"):
+		return boqColDistrict
+	case strings.Contains(h, "mandal") && strings.Contains(h, "code"):
+		return boqColMandalCode
+	case strings.Contains(h, "mandal"):
+		return boqColMandal
+	case strings.Contains(h, "address code") || h == "code":
+		return boqColAddressCode
+	case strings.Contains(h, "ship to") || strings.Contains(h, "ship-to") || h == "destination" || h == "location":
+		return boqColShipTo
+	case h == "s.no" || h == "s.no." || h == "sl. no." || h == "sl.no" || h == "sr. no." || h == "sno" ||
+		strings.Contains(h, "total") || strings.Contains(h, "remarks"):
+		return boqColIgnored
+	}
+	return boqColProduct
+}
+
+// ParseBOQSheet reads a BOQ plan from CSV or Excel (.xlsx). The header row is
+// the first with a mandal, address code or ship-to column; the other headers
+// name the products. Blank quantity cells are left out of the plan, and rows
+// with no destination are skipped.
+func ParseBOQSheet(r io.Reader, filename string) (*BOQSheet, error) {
+	var rows [][]string
+	var lines []int // file line of each row, when it is not the row index + 1
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CSV: %v", err)
+			}
+			// Blank lines are skipped by the reader; keep the file's line numbers.
+			line, _ := reader.FieldPos(0)
+			lines = append(lines, line)
+			rows = append(rows, record)
+		}
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Excel file: %v", err)
+		}
+		defer f.Close()
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("Excel file has no sheets")
+		}
+		all, err := f.GetRows(sheets[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sheet: %v", err)
+		}
+		rows = all
+	default:
+		return nil, fmt.Errorf("only CSV and Excel (.xlsx) BOQ sheets are supported")
+	}
+
+	headerRow := -1
+	var kinds []boqColumn
+	for i := 0; i < len(rows) && i < boqHeaderRows && headerRow < 0; i++ {
+		kinds = kinds[:0]
+		for _, cell := range rows[i] {
+			k := boqColumnKind(strings.ToLower(strings.TrimSpace(cell)))
+			kinds = append(kinds, k)
+			if k == boqColMandal || k == boqColMandalCode || k == boqColAddressCode || k == boqColShipTo {
+				headerRow = i
+			}
+		}
+	}
+	if headerRow < 0 {
+		return nil, fmt.Errorf("no header row found: the sheet needs a Mandal, Mandal Code, Address Code or Ship To column")
+	}
+
+	sheet := &BOQSheet{}
+	productIndex := make(map[int]int) // sheet column -> index in sheet.Products
+	for j, k := range kinds {
+		if k == boqColProduct {
+			productIndex[j] = len(sheet.Products)
+			sheet.Products = append(sheet.Products, strings.TrimSpace(rows[headerRow][j]))
+		}
+	}
+	if len(sheet.Products) == 0 {
+		return nil, fmt.Errorf("no product columns found in the header row")
+	}
+
+	for i := headerRow + 1; i < len(rows); i++ {
+		line := i + 1
+		if lines != nil {
+			line = lines[i]
+		}
+		row := BOQSheetRow{Row: line, Quantities: make(map[int]int)}
+		for j, cell := range rows[i] {
+			if j >= len(kinds) {
+				break
+			}
+			cell = strings.TrimSpace(cell)
+			switch kinds[j] {
+			case boqColDistrict:
+				row.District = cell
+			case boqColMandal:
+				row.Mandal = cell
+			case boqColMandalCode:
+				row.MandalCode = cell
+			case boqColAddressCode:
+				row.AddressCode = cell
+			case boqColShipTo:
+				row.ShipTo = cell
+			case boqColProduct:
+				if cell == "" {
+					continue
+				}
+				qty, err := strconv.Atoi(strings.ReplaceAll(cell, ",", ""))
+				if err != nil || qty < 0 {
+					return nil, fmt.Errorf("row %d, %s: %q is not a whole number of units", line, sheet.Products[productIndex[j]], cell)
+				}
+				row.Quantities[productIndex[j]] = qty
+			}
+		}
+		if row.Mandal == "" && row.MandalCode == "" && row.AddressCode == "" && row.ShipTo == "" {
+			continue
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+	return sheet, nil
+}
+
+// WriteBOQSheet writes a BOQ plan as an Excel workbook in the layout
+// ParseBOQSheet reads, so that an exported plan can be edited and imported
+// again.
+func WriteBOQSheet(w io.Writer, sheet *BOQSheet) error {
+	f := excelize.NewFile()
+	defer f.Close()
+	name := "BOQ Plan"
+	_ = f.SetSheetName("Sheet1", name)
+
+	header := []interface{}{"District", "Mandal", "Mandal Code", "Address Code", "Ship To"}
+	fixed := len(header)
+	for _, p := range sheet.Products {
+		header = append(header, p)
+	}
+	if err := f.SetSheetRow(name, "A1", &header); err != nil {
+		return err
+	}
+	for i, r := range sheet.Rows {
+		values := []interface{}{r.District, r.Mandal, r.MandalCode, r.AddressCode, r.ShipTo}
+		for j := range sheet.Products {
+			if qty, ok := r.Quantities[j]; ok {
+				values = append(values, qty)
+			} else {
+				values = append(values, nil)
+			}
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := f.SetSheetRow(name, cell, &values); err != nil {
+			return err
+		}
+	}
+	if last, err := excelize.ColumnNumberToName(fixed); err == nil {
+		_ = f.SetColWidth(name, "A", last, 18)
+	}
+	return f.Write(w)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBOQSheetCSV(t *testing.T) {
+	csv := "Annexure II - Bill of Quantities\n\nS.No,District,Mandal,Mandal Code,AX3000 Router,24-Port Switch,Total\n" +
+		"1,Guntur,Tenali,GNT-01,\"1,200\",4,1204\n" +
+		"2,Guntur,Bapatla,GNT-02,,0,0\n" +
+		",,,,,,1204\n"
+	sheet, err := ParseBOQSheet(strings.NewReader(csv), "boq.CSV")
+	if err != nil {
+		t.Fatalf("ParseBOQSheet: %v", err)
+	}
+	if want := []string{"AX3000 Router", "24-Port Switch"}; !reflect.DeepEqual(sheet.Products, want) {
+		t.Errorf("products = %q, want %q", sheet.Products, want)
+	}
+	want := []BOQSheetRow{
+		{Row: 4, District: "Guntur", Mandal: "Tenali", MandalCode: "GNT-01", Quantities: map[int]int{0: 1200, 1: 4}},
+		{Row: 5, District: "Guntur", Mandal: "Bapatla", MandalCode: "GNT-02", Quantities: map[int]int{1: 0}},
+	}
+	if !reflect.DeepEqual(sheet.Rows, want) {
+		t.Errorf("rows = %+v, want %+v", sheet.Rows, want)
+	}
+}
+
+func TestParseBOQSheetErrors(t *testing.T) {
+	tests := []struct {
+		name, csv, want string
+	}{
+		{"no header", "Router,Switch\n1,2\n", "no header row"},
+		{"no products", "Mandal,Total\nTenali,4\n", "no product columns"},
+		{"fraction", "Mandal,Router\nTenali,2.5\n", `row 2, Router: "2.5" is not a whole number`},
+		{"negative", "Mandal,Router\nTenali,-1\n", `row 2, Router: "-1" is not a whole number`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBOQSheet(strings.NewReader(tt.csv), "boq.csv")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+	if _, err := ParseBOQSheet(strings.NewReader(""), "boq.pdf"); err == nil {
+		t.Error("expected an error for an unsupported file type")
+	}
+}
+
+func TestWriteBOQSheetRoundTrip(t *testing.T) {
+	in := &BOQSheet{
+		Products: []string{"Router", "Switch"},
+		Rows: []BOQSheetRow{
+			{Row: 2, District: "Guntur", Mandal: "Tenali", MandalCode: "GNT-01", AddressCode: "ZP-7", ShipTo: "ZPHS Tenali", Quantities: map[int]int{0: 10}},
+			{Row: 3, District: "Guntur", Mandal: "Bapatla", ShipTo: "ZPHS Bapatla", Quantities: map[int]int{0: 0, 1: 3}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteBOQSheet(&buf, in); err != nil {
+		t.Fatalf("WriteBOQSheet: %v", err)
+	}
+	out, err := ParseBOQSheet(&buf, "boq.xlsx")
+	if err != nil {
+		t.Fatalf("ParseBOQSheet: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// Label sheet sizes.
+const (
+	LabelSizeA4      = "a4"      // 2 x 4 labels on an A4 sheet
+	LabelSizeThermal = "thermal" // one 100 x 150 mm label per page
+)
+
+// BoxLabel is one carton of a destination.
+type BoxLabel struct {
+	DCNumber string
+	ShipTo   string
+	ItemName string
+	BoxNo    int
+	BoxCount int // boxes going to the destination
+	Quantity int
+	Serials  []string
+}
+
+// labelLayout is where labels sit on a page.
+type labelLayout struct {
+	pageW, pageH float64
+	cols, rows   int
+	margin, gap  float64
+	qrSize       float64
+	scale        float64 // font size multiplier
+}
+
+func labelLayoutFor(size string) labelLayout {
+	if size == LabelSizeThermal {
+		return labelLayout{pageW: 100, pageH: 150, cols: 1, rows: 1, margin: 0, qrSize: 42, scale: 1.3}
+	}
+	return labelLayout{pageW: pageW, pageH: pageH, cols: 2, rows: 4, margin: 7, gap: 4, qrSize: 26, scale: 1}
+}
+
+// BuildBoxLabels cuts each destination's products into boxes. unitsPerBox
+// maps a product ID to how many units fit in one box; products without an
+// entry go in a single box per destination. Serials are dealt to the boxes in
+// order, and boxes are numbered across all products of the destination.
+func BuildBoxLabels(dests []*models.BoxLabelDestination, unitsPerBox map[int]int) []BoxLabel {
+	var labels []BoxLabel
+	for _, d := range dests {
+		first := len(labels)
+		for _, line := range d.Lines {
+			per := unitsPerBox[line.ProductID]
+			if per <= 0 || per > line.Quantity {
+				per = line.Quantity
+			}
+			for start := 0; start < line.Quantity; start += per {
+				qty := per
+				if start+qty > line.Quantity {
+					qty = line.Quantity - start
+				}
+				var serials []string
+				if start < len(line.Serials) {
+					serials = line.Serials[start:min(start+qty, len(line.Serials))]
+				}
+				labels = append(labels, BoxLabel{
+					DCNumber: d.DCNumber,
+					ShipTo:   d.ShipTo,
+					ItemName: line.ItemName,
+					Quantity: qty,
+					Serials:  serials,
+				})
+			}
+		}
+		count := len(labels) - first
+		for i := first; i < len(labels); i++ {
+			labels[i].BoxNo = i - first + 1
+			labels[i].BoxCount = count
+		}
+	}
+	return labels
+}
+
+// GenerateBoxLabelsPDF lays box labels out on A4 sheets or thermal labels.
+func GenerateBoxLabelsPDF(labels []BoxLabel, size string) ([]byte, error) {
+	l := labelLayoutFor(size)
+	pdf := fpdf.NewCustom(&fpdf.InitType{UnitStr: "mm", Size: fpdf.SizeType{Wd: l.pageW, Ht: l.pageH}})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	registerFonts(pdf)
+
+	perPage := l.cols * l.rows
+	labelW := (l.pageW - 2*l.margin - float64(l.cols-1)*l.gap) / float64(l.cols)
+	labelH := (l.pageH - 2*l.margin - float64(l.rows-1)*l.gap) / float64(l.rows)
+	for i, lb := range labels {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+		n := i % perPage
+		x := l.margin + float64(n%l.cols)*(labelW+l.gap)
+		y := l.margin + float64(n/l.cols)*(labelH+l.gap)
+		drawBoxLabel(pdf, lb, x, y, labelW, labelH, l)
+	}
+	if len(labels) == 0 {
+		pdf.AddPage()
+	}
+	return pdfToBytes(pdf)
+}
+
+// boxLabelQRContent is what a label's QR code carries: the DC number, the box
+// and its serials, one per line.
+func boxLabelQRContent(lb BoxLabel) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\nBox %d/%d\n%s x %d", lb.DCNumber, lb.BoxNo, lb.BoxCount, lb.ItemName, lb.Quantity)
+	for _, sn := range lb.Serials {
+		b.WriteString("\n" + sn)
+	}
+	return b.String()
+}
+
+func drawBoxLabel(pdf *fpdf.Fpdf, lb BoxLabel, x, y, w, h float64, l labelLayout) {
+	const pad = 4.0
+	setDrawColor(pdf, colorBlack)
+	pdf.SetLineWidth(0.3)
+	pdf.Rect(x, y, w, h, "D")
+
+	// QR code in the top-right corner; too many serials fall back to the DC number.
+	qrX, qrY := x+w-pad-l.qrSize, y+pad
+	if !drawQRCodeAt(pdf, boxLabelQRContent(lb), qrX, qrY, l.qrSize) {
+		drawQRCodeAt(pdf, lb.DCNumber, qrX, qrY, l.qrSize)
+	}
+
+	textW := w - 3*pad - l.qrSize
+	setColor(pdf, colorBlack)
+	pdf.SetXY(x+pad, y+pad)
+	setFont(pdf, "B", 11*l.scale)
+	pdf.CellFormat(textW, 5*l.scale, lb.DCNumber, "", 2, "L", false, 0, "")
+	setFont(pdf, "", 8*l.scale)
+	pdf.SetX(x + pad)
+	pdf.MultiCell(textW, 3.6*l.scale, lb.ShipTo, "", "L", false)
+	pdf.Ln(1)
+	pdf.SetX(x + pad)
+	setFont(pdf, "B", 14*l.scale)
+	pdf.CellFormat(textW, 7*l.scale, fmt.Sprintf("Box %d of %d", lb.BoxNo, lb.BoxCount), "", 2, "L", false, 0, "")
+
+	// Product and serials run the full width below the QR code.
+	pdf.SetXY(x+pad, max(pdf.GetY(), qrY+l.qrSize)+1)
+	setFont(pdf, "B", 9*l.scale)
+	pdf.MultiCell(w-2*pad, 4*l.scale, fmt.Sprintf("%s  x %d", lb.ItemName, lb.Quantity), "", "L", false)
+	if len(lb.Serials) == 0 {
+		return
+	}
+	setFont(pdf, "", 6.5*l.scale)
+	lineH := 3 * l.scale
+	room := int((y + h - pad - pdf.GetY()) / lineH)
+	if room < 1 {
+		return
+	}
+	text := "S/N: " + strings.Join(lb.Serials, ", ")
+	lines := pdf.SplitText(text, w-2*pad)
+	if len(lines) > room {
+		lines = append(lines[:room-1], fmt.Sprintf("... %d serials, scan the QR code for all", len(lb.Serials)))
+	}
+	for _, line := range lines {
+		pdf.SetX(x + pad)
+		pdf.CellFormat(w-2*pad, lineH, line, "", 2, "L", false, 0, "")
+	}
+}
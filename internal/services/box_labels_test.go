@@ -0,0 +1,74 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+func TestBuildBoxLabels(t *testing.T) {
+	dests := []*models.BoxLabelDestination{
+		{
+			DCNumber: "TST-ODC-001",
+			ShipTo:   "Mandal Office, Guntur",
+			Lines: []*models.BoxLabelLine{
+				{ProductID: 1, ItemName: "Router", Quantity: 5, Serials: []string{"R1", "R2", "R3", "R4", "R5"}},
+				{ProductID: 2, ItemName: "Cable", Quantity: 30},
+			},
+		},
+		{
+			DCNumber: "TST-ODC-002",
+			ShipTo:   "Mandal Office, Tenali",
+			Lines: []*models.BoxLabelLine{
+				{ProductID: 1, ItemName: "Router", Quantity: 1, Serials: []string{"R6"}},
+			},
+		},
+	}
+
+	labels := BuildBoxLabels(dests, map[int]int{1: 2})
+
+	type box struct {
+		dc      string
+		no, of  int
+		qty     int
+		serials []string
+	}
+	var got []box
+	for _, l := range labels {
+		got = append(got, box{l.DCNumber, l.BoxNo, l.BoxCount, l.Quantity, l.Serials})
+	}
+	want := []box{
+		{"TST-ODC-001", 1, 4, 2, []string{"R1", "R2"}},
+		{"TST-ODC-001", 2, 4, 2, []string{"R3", "R4"}},
+		{"TST-ODC-001", 3, 4, 1, []string{"R5"}},
+		{"TST-ODC-001", 4, 4, 30, nil},
+		{"TST-ODC-002", 1, 1, 1, []string{"R6"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildBoxLabels =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestGenerateBoxLabelsPDF(t *testing.T) {
+	var serials []string
+	for i := 0; i < 400; i++ {
+		serials = append(serials, fmt.Sprintf("SN%010d", i))
+	}
+	labels := []BoxLabel{
+		{DCNumber: "TST-ODC-001", ShipTo: "Mandal Office, Guntur", ItemName: "Router", BoxNo: 1, BoxCount: 2, Quantity: 3, Serials: []string{"R1", "R2", "R3"}},
+		// Too many serials for a QR code: the label falls back to the DC number.
+		{DCNumber: "TST-ODC-001", ShipTo: "Mandal Office, Guntur", ItemName: "Router", BoxNo: 2, BoxCount: 2, Quantity: 400, Serials: serials},
+	}
+	for _, size := range []string{LabelSizeA4, LabelSizeThermal} {
+		out, err := GenerateBoxLabelsPDF(labels, size)
+		if err != nil {
+			t.Fatalf("%s: GenerateBoxLabelsPDF: %v", size, err)
+		}
+		if !bytes.HasPrefix(out, []byte("%PDF")) {
+			t.Errorf("%s: output is not a PDF", size)
+		}
+	}
+}
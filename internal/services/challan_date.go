@@ -0,0 +1,78 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// CheckFinancialYearOpen returns a *models.ChallanDateError when the financial
+// year of date is closed for the project.
+func CheckFinancialYearOpen(db *sql.DB, projectID int, date time.Time) error {
+	fy := GetFinancialYear(date)
+	var closed int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM financial_year_closures WHERE project_id = ? AND financial_year = ?`,
+		projectID, fy,
+	).Scan(&closed)
+	if err != nil {
+		return fmt.Errorf("failed to read financial year closures: %w", err)
+	}
+	if closed > 0 {
+		return &models.ChallanDateError{Date: date.Format("2006-01-02"), FinancialYear: fy, Closed: true}
+	}
+	return nil
+}
+
+// CheckChallanDate checks a challan date for a DC of dcType before the DC is
+// created or re-dated. The date's financial year must be open and, when the
+// project enforces date order, the date may not precede the latest issued
+// challan date of the same DC type in that year, so numbers stay in date order.
+// excludeDCID leaves the DC being edited out of that lookup. A refused date is
+// returned as a *models.ChallanDateError.
+func CheckChallanDate(db *sql.DB, projectID int, dcType string, date time.Time, excludeDCID int) error {
+	if err := CheckFinancialYearOpen(db, projectID, date); err != nil {
+		return err
+	}
+
+	var enforce bool
+	if err := db.QueryRow(`SELECT enforce_date_order FROM projects WHERE id = ?`, projectID).Scan(&enforce); err != nil {
+		return fmt.Errorf("failed to read date order rule: %w", err)
+	}
+	if !enforce {
+		return nil
+	}
+
+	fy := GetFinancialYear(date)
+	startYear, _, err := ParseFinancialYear(fy)
+	if err != nil {
+		return err
+	}
+	var latestDate, latestNumber string
+	err = db.QueryRow(`
+		SELECT substr(challan_date, 1, 10), dc_number FROM delivery_challans
+		WHERE project_id = ? AND dc_type = ? AND id != ?
+		  AND status IN ('issued', 'splitting', 'split', 'delivered', 'partially_delivered')
+		  AND substr(challan_date, 1, 10) BETWEEN ? AND ?
+		ORDER BY substr(challan_date, 1, 10) DESC, id DESC
+		LIMIT 1`,
+		projectID, dcType, excludeDCID,
+		GetFinancialYearStart(startYear).Format("2006-01-02"), GetFinancialYearEnd(startYear).Format("2006-01-02"),
+	).Scan(&latestDate, &latestNumber)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read latest issued challan date: %w", err)
+	}
+
+	if d := date.Format("2006-01-02"); d < latestDate {
+		return &models.ChallanDateError{
+			Date: d, FinancialYear: fy, DCType: dcType,
+			LatestDate: latestDate, LatestNumber: latestNumber,
+		}
+	}
+	return nil
+}
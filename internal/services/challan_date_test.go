@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+func TestCheckChallanDate(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	for _, q := range []string{
+		`ALTER TABLE projects ADD COLUMN enforce_date_order INTEGER NOT NULL DEFAULT 0`,
+		`CREATE TABLE financial_year_closures (id INTEGER PRIMARY KEY, project_id INTEGER, financial_year TEXT, closed_by INTEGER, closed_at DATETIME)`,
+		`CREATE TABLE delivery_challans (id INTEGER PRIMARY KEY, project_id INTEGER, dc_number TEXT, dc_type TEXT, status TEXT, challan_date DATE)`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	projectID := insertTestProject(t, db, "Test", "SCP")
+	if _, err := db.Exec(`INSERT INTO delivery_challans (id, project_id, dc_number, dc_type, status, challan_date) VALUES
+		(1, ?, 'SCP-TDC-2526-001', 'transit', 'issued', '2025-06-10'),
+		(2, ?, 'SCP-TDC-2526-002', 'transit', 'draft', '2025-07-01'),
+		(3, ?, 'SCP-ODC-2526-001', 'official', 'issued', '2025-08-01')`, projectID, projectID, projectID); err != nil {
+		t.Fatal(err)
+	}
+
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	refused := func(err error) *models.ChallanDateError {
+		var dateErr *models.ChallanDateError
+		if errors.As(err, &dateErr) {
+			return dateErr
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return nil
+	}
+
+	// Without the rule any date in an open year is accepted.
+	if e := refused(CheckChallanDate(db, projectID, DCTypeTransit, day("2025-05-01"), 0)); e != nil {
+		t.Errorf("back-dated DC refused without the rule: %v", e)
+	}
+
+	if _, err := db.Exec(`UPDATE projects SET enforce_date_order = 1 WHERE id = ?`, projectID); err != nil {
+		t.Fatal(err)
+	}
+	e := refused(CheckChallanDate(db, projectID, DCTypeTransit, day("2025-06-09"), 0))
+	if e == nil || e.Closed || e.LatestNumber != "SCP-TDC-2526-001" {
+		t.Errorf("back-dated transit DC: got %+v; want refused after SCP-TDC-2526-001", e)
+	}
+	if e := refused(CheckChallanDate(db, projectID, DCTypeTransit, day("2025-06-10"), 0)); e != nil {
+		t.Errorf("same-day DC refused: %v", e)
+	}
+	// The DC being re-dated is left out of the comparison.
+	if e := refused(CheckChallanDate(db, projectID, DCTypeTransit, day("2025-06-09"), 1)); e != nil {
+		t.Errorf("re-dating the latest DC itself refused: %v", e)
+	}
+	// The next financial year starts its own series.
+	if e := refused(CheckChallanDate(db, projectID, DCTypeOfficial, day("2026-04-01"), 0)); e != nil {
+		t.Errorf("new FY refused: %v", e)
+	}
+
+	if _, err := db.Exec(`INSERT INTO financial_year_closures (project_id, financial_year) VALUES (?, '2526')`, projectID); err != nil {
+		t.Fatal(err)
+	}
+	e = refused(CheckChallanDate(db, projectID, DCTypeTransit, day("2025-12-01"), 0))
+	if e == nil || !e.Closed || e.FinancialYear != "2526" {
+		t.Errorf("closed FY: got %+v; want closed 2526", e)
+	}
+	if e := refused(CheckFinancialYearOpen(db, projectID, day("2026-04-01"))); e != nil {
+		t.Errorf("open FY refused: %v", e)
+	}
+}
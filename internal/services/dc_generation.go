@@ -33,12 +33,20 @@ type ShipmentParams struct {
 // ShipmentLineItem holds product info and serial assignments for a shipment.
 type ShipmentLineItem struct {
 	ProductID     int
-	QtyPerSet     int              // deprecated: use QtyByLocation instead
-	QtyByLocation map[int]int      // map[shipToAddressID] → quantity for that location
+	QtyPerSet     int         // deprecated: use QtyByLocation instead
+	QtyByLocation map[int]int // map[shipToAddressID] → quantity for that location
 	Rate          float64
 	TaxPercentage float64
 	AllSerials    []string
-	Assignments   map[int][]string // map[shipToAddressID][]serialNumbers
+	Assignments   map[int][]string           // map[shipToAddressID][]serialNumbers
+	Components    []ShipmentComponentSerials // serials per component when the product is a kit
+}
+
+// ShipmentComponentSerials holds the serials of one component of a kit line.
+type ShipmentComponentSerials struct {
+	ProductID   int
+	AllSerials  []string
+	Assignments map[int][]string // map[shipToAddressID][]serialNumbers
 }
 
 // TotalQty returns the sum of quantities across all locations.
@@ -72,6 +80,25 @@ type ShipmentResult struct {
 
 // CreateShipmentGroupDCs creates a shipment group with 1 transit DC + N official DCs in a transaction.
 func CreateShipmentGroupDCs(db *sql.DB, params ShipmentParams) (*ShipmentResult, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := CreateShipmentGroupDCsTx(tx, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// CreateShipmentGroupDCsTx is CreateShipmentGroupDCs within tx, so several
+// shipment groups can be created or rolled back together.
+func CreateShipmentGroupDCsTx(tx *sql.Tx, params ShipmentParams) (*ShipmentResult, error) {
 	if len(params.ShipToAddressIDs) == 0 {
 		return nil, fmt.Errorf("at least one ship-to address is required")
 	}
@@ -94,12 +121,6 @@ func CreateShipmentGroupDCs(db *sql.DB, params ShipmentParams) (*ShipmentResult,
 		return nil, fmt.Errorf("transit ship-to address must be one of the selected ship-to addresses")
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer func() { _ = tx.Rollback() }()
-
 	// Parse DC date for financial year
 	dcDate, err := time.Parse("2006-01-02", params.ChallanDate)
 	if err != nil {
@@ -212,14 +233,14 @@ func CreateShipmentGroupDCs(db *sql.DB, params ShipmentParams) (*ShipmentResult,
 			return nil, fmt.Errorf("failed to get line item ID: %w", err)
 		}
 
-		// Insert all serial numbers for this product
-		for _, sn := range item.AllSerials {
-			_, err = tx.Exec(
-				`INSERT INTO serial_numbers (project_id, line_item_id, serial_number, product_id) VALUES (?, ?, ?, ?)`,
-				params.ProjectID, int(liID), sn, item.ProductID,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("failed to insert serial number '%s': %w", sn, err)
+		// Insert all serial numbers for this product, or for each component
+		// of a kit.
+		if err := insertShipmentSerials(tx, params.ProjectID, int(liID), item.ProductID, item.AllSerials, item.Assignments); err != nil {
+			return nil, err
+		}
+		for _, comp := range item.Components {
+			if err := insertShipmentSerials(tx, params.ProjectID, int(liID), comp.ProductID, comp.AllSerials, comp.Assignments); err != nil {
+				return nil, err
 			}
 		}
 	}
@@ -294,10 +315,6 @@ func CreateShipmentGroupDCs(db *sql.DB, params ShipmentParams) (*ShipmentResult,
 		officialDCs = append(officialDCs, offDC)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return &ShipmentResult{
 		GroupID:     int(groupID),
 		TransitDC:   transitDC,
@@ -305,6 +322,31 @@ func CreateShipmentGroupDCs(db *sql.DB, params ShipmentParams) (*ShipmentResult,
 	}, nil
 }
 
+// insertShipmentSerials inserts the serials of one product on a transit line
+// item, keeping the destination of those assigned to one so box labels can
+// list them.
+func insertShipmentSerials(tx *sql.Tx, projectID, lineItemID, productID int, serials []string, assignments map[int][]string) error {
+	destinationOf := make(map[string]int)
+	for shipToID, assigned := range assignments {
+		for _, sn := range assigned {
+			destinationOf[sn] = shipToID
+		}
+	}
+	for _, sn := range serials {
+		var shipToID sql.NullInt64
+		if id, ok := destinationOf[sn]; ok {
+			shipToID = sql.NullInt64{Int64: int64(id), Valid: true}
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO serial_numbers (project_id, line_item_id, serial_number, product_id, ship_to_address_id) VALUES (?, ?, ?, ?, ?)`,
+			projectID, lineItemID, sn, productID, shipToID,
+		); err != nil {
+			return fmt.Errorf("failed to insert serial number '%s': %w", sn, err)
+		}
+	}
+	return nil
+}
+
 // TransferDCParams holds all parameters needed to create a Transfer DC.
 type TransferDCParams struct {
 	ProjectID             int
@@ -72,6 +72,7 @@ func setupDCGenTestDB(t *testing.T) *sql.DB {
 			line_item_id INTEGER NOT NULL,
 			serial_number TEXT NOT NULL,
 			product_id INTEGER NOT NULL,
+			ship_to_address_id INTEGER,
 			FOREIGN KEY (line_item_id) REFERENCES dc_line_items(id)
 		)`,
 	}
@@ -125,6 +126,8 @@ func TestCreateShipmentGroupDCs_CustomQuantities(t *testing.T) {
 				QtyByLocation: map[int]int{100: 5, 200: 3},
 				Rate:          100.0,
 				TaxPercentage: 18.0,
+				AllSerials:    []string{"A1", "A2", "A3", "A4", "A5", "A6", "A7", "A8"},
+				Assignments:   map[int][]string{200: {"A6", "A7", "A8"}},
 			},
 			{
 				ProductID:     2,
@@ -184,6 +187,14 @@ func TestCreateShipmentGroupDCs_CustomQuantities(t *testing.T) {
 	if qty := getLineItemQty(t, db, offDC200, 2); qty != 0 {
 		t.Errorf("official DC addr=200, product 2: got qty %d, want 0", qty)
 	}
+
+	// Serials assigned to addr 200 keep their destination; the rest have none.
+	var assigned, unassigned int
+	db.QueryRow("SELECT COUNT(*) FROM serial_numbers WHERE ship_to_address_id = 200").Scan(&assigned)
+	db.QueryRow("SELECT COUNT(*) FROM serial_numbers WHERE ship_to_address_id IS NULL").Scan(&unassigned)
+	if assigned != 3 || unassigned != 5 {
+		t.Errorf("serials with/without destination = %d/%d, want 3/5", assigned, unassigned)
+	}
 }
 
 func TestCreateShipmentGroupDCs_ZeroQtyLocationSkipped(t *testing.T) {
@@ -255,6 +266,56 @@ func TestCreateShipmentGroupDCs_ZeroQtyLocationSkipped(t *testing.T) {
 	}
 }
 
+func TestCreateShipmentGroupDCsTx_RollsBackTogether(t *testing.T) {
+	db := setupDCGenTestDB(t)
+	defer db.Close()
+
+	projectID := insertTestProject(t, db, "TestProject", "TST")
+	params := func(shipTo int) ShipmentParams {
+		return ShipmentParams{
+			ProjectID:           projectID,
+			TemplateID:          1,
+			NumLocations:        1,
+			ChallanDate:         "2026-01-15",
+			TaxType:             "igst",
+			ReverseCharge:       "N",
+			ShipToAddressIDs:    []int{shipTo},
+			TransitShipToAddrID: shipTo,
+			LineItems:           []ShipmentLineItem{{ProductID: 1, QtyPerSet: 2, Rate: 100.0, TaxPercentage: 18.0}},
+			CreatedBy:           1,
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	first, err := CreateShipmentGroupDCsTx(tx, params(100))
+	if err != nil {
+		t.Fatalf("first group: %v", err)
+	}
+	second, err := CreateShipmentGroupDCsTx(tx, params(200))
+	if err != nil {
+		t.Fatalf("second group: %v", err)
+	}
+	if first.TransitDC.DCNumber == second.TransitDC.DCNumber {
+		t.Errorf("both groups got transit DC %s", first.TransitDC.DCNumber)
+	}
+	bad := params(300)
+	bad.TransitShipToAddrID = 100
+	if _, err := CreateShipmentGroupDCsTx(tx, bad); err == nil {
+		t.Fatal("expected an error for a transit address outside the group")
+	}
+	_ = tx.Rollback()
+
+	var groups, dcs int
+	db.QueryRow(`SELECT COUNT(*) FROM shipment_groups`).Scan(&groups)
+	db.QueryRow(`SELECT COUNT(*) FROM delivery_challans`).Scan(&dcs)
+	if groups != 0 || dcs != 0 {
+		t.Errorf("after rollback: %d groups and %d DCs, want none", groups, dcs)
+	}
+}
+
 func TestShipmentLineItem_TotalQty(t *testing.T) {
 	// Test with QtyByLocation
 	item := ShipmentLineItem{
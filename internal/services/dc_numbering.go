@@ -14,6 +14,7 @@ const (
 	DCTypeTransit  = "transit"
 	DCTypeOfficial = "official"
 	DCTypeTransfer = "transfer"
+	DCTypeReturn   = "return"
 )
 
 // dcTypeCode maps DC type to its code in the DC number.
@@ -21,6 +22,7 @@ var dcTypeCode = map[string]string{
 	DCTypeTransit:  "TDC",
 	DCTypeOfficial: "ODC",
 	DCTypeTransfer: "STDC",
+	DCTypeReturn:   "RDC",
 }
 
 // dcCodeToType maps DC number code back to DC type.
@@ -28,10 +30,11 @@ var dcCodeToType = map[string]string{
 	"TDC":  DCTypeTransit,
 	"ODC":  DCTypeOfficial,
 	"STDC": DCTypeTransfer,
+	"RDC":  DCTypeReturn,
 }
 
 // dcNumberPattern validates the DC number format: PREFIX-TDC-2526-001
-var dcNumberPattern = regexp.MustCompile(`^[A-Za-z0-9/]+-(TDC|ODC|STDC)-\d{4}-\d{3,}$`)
+var dcNumberPattern = regexp.MustCompile(`^[A-Za-z0-9/]+-(TDC|ODC|STDC|RDC)-\d{4}-\d{3,}$`)
 
 // DCNumberParts represents the parsed components of a DC number.
 type DCNumberParts struct {
@@ -149,13 +152,30 @@ func GenerateDCNumberForDate(db *sql.DB, projectID int, dcType string, date time
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	number, err := GenerateDCNumberTx(tx, projectID, dcType, date)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return number, nil
+}
+
+// GenerateDCNumberTx is GenerateDCNumberForDate within the caller's
+// transaction, so the sequence is only consumed if the caller commits.
+func GenerateDCNumberTx(tx *sql.Tx, projectID int, dcType string, date time.Time) (string, error) {
+	if _, ok := dcTypeCode[dcType]; !ok {
+		return "", fmt.Errorf("invalid DC type: %s", dcType)
+	}
+
 	if _, lockErr := tx.Exec("SELECT 1 FROM dc_number_sequences LIMIT 0"); lockErr != nil {
 		return "", fmt.Errorf("failed to acquire lock: %w", lockErr)
 	}
 
 	var dcPrefix, dcNumberFormat string
 	var seqPadding int
-	err = tx.QueryRow("SELECT dc_prefix, dc_number_format, seq_padding FROM projects WHERE id = ?", projectID).Scan(&dcPrefix, &dcNumberFormat, &seqPadding)
+	err := tx.QueryRow("SELECT dc_prefix, dc_number_format, seq_padding FROM projects WHERE id = ?", projectID).Scan(&dcPrefix, &dcNumberFormat, &seqPadding)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return "", fmt.Errorf("project not found: %d", projectID)
@@ -174,10 +194,6 @@ func GenerateDCNumberForDate(db *sql.DB, projectID int, dcType string, date time
 		return "", fmt.Errorf("failed to get next sequence: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return "", fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	if dcNumberFormat != "" && dcNumberFormat != "{PREFIX}-{TYPE}-{FY}-{SEQ}" {
 		return FormatDCNumberConfigurable(dcNumberFormat, dcPrefix, dcPrefix, fy, dcType, sequence, seqPadding), nil
 	}
@@ -102,6 +102,7 @@ func TestFormatDCNumber(t *testing.T) {
 		{"X", "2627", DCTypeTransit, 1000, "X-TDC-2627-1000"},
 		{"SCP", "2526", DCTypeTransfer, 1, "SCP-STDC-2526-001"},
 		{"PWD/AP", "2526", DCTypeTransfer, 42, "PWD/AP-STDC-2526-042"},
+		{"SCP", "2526", DCTypeReturn, 3, "SCP-RDC-2526-003"},
 	}
 
 	for _, tt := range tests {
@@ -130,6 +131,7 @@ func TestParseDCNumber(t *testing.T) {
 		{"X-TDC-2627-1000", "X", "2627", DCTypeTransit, 1000, false},
 		{"SCP-STDC-2526-001", "SCP", "2526", DCTypeTransfer, 1, false},
 		{"PWD/AP-STDC-2526-042", "PWD/AP", "2526", DCTypeTransfer, 42, false},
+		{"SCP-RDC-2526-003", "SCP", "2526", DCTypeReturn, 3, false},
 		{"INVALID", "", "", "", 0, true},
 		{"", "", "", "", 0, true},
 		{"SCP-XDC-2425-001", "", "", "", 0, true},
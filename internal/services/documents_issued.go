@@ -0,0 +1,203 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// GSTR1NatureDeliveryChallan is the GSTR-1 Table 13 "Nature of Document" for
+// challans that move goods other than by way of supply, which is what every DC
+// type in this tool is.
+const GSTR1NatureDeliveryChallan = "Delivery Challan in cases other than by way of supply (excluding at S no. 9 to 11)"
+
+// IssuedDocument is one numbered DC considered for the documents-issued summary.
+type IssuedDocument struct {
+	DCNumber    string
+	DCType      string
+	Status      string
+	ChallanDate time.Time
+}
+
+// DocumentSeries summarises one DC number series (same number pattern, DC type
+// and financial year) for GSTR-1 Table 13.
+type DocumentSeries struct {
+	Series        string // DC number with the sequence shown as "#", e.g. "FSS-TDC-2526-#"
+	DCType        string
+	FinancialYear string // compact, e.g. "2526"
+	FromNumber    string
+	ToNumber      string
+	TotalNumber   int // numbers in the From-To range, used or not
+	Issued        int
+	Cancelled     int
+	NotIssued     int // still draft, pending approval or rejected
+	Deleted       int // numbers in the range with no DC: drafts that were deleted
+
+	fromSeq, toSeq int
+	seqs           map[int]bool
+}
+
+// GSTR1Cancelled returns the count reported as cancelled in Table 13: every
+// number in the range that did not become an issued document.
+func (s DocumentSeries) GSTR1Cancelled() int {
+	return s.Cancelled + s.NotIssued + s.Deleted
+}
+
+// documentTypeLabels names each DC type in the summary.
+var documentTypeLabels = map[string]string{
+	DCTypeTransit:  "Transit DC",
+	DCTypeOfficial: "Official DC",
+	DCTypeTransfer: "Transfer DC",
+	DCTypeReturn:   "Return DC",
+}
+
+// TypeLabel returns the display name of the series' DC type.
+func (s DocumentSeries) TypeLabel() string {
+	if l, ok := documentTypeLabels[s.DCType]; ok {
+		return l
+	}
+	return s.DCType
+}
+
+// FinancialYearLabel returns the financial year as "2025-26".
+func (s DocumentSeries) FinancialYearLabel() string {
+	return models.FinancialYearLabel(s.FinancialYear)
+}
+
+// formatTokenRe finds the tokens of a DC number format.
+var formatTokenRe = regexp.MustCompile(`\{(PREFIX|PROJECT_CODE|FY|TYPE|SEQ)\}`)
+
+// dcNumberRegexp builds a regexp matching DC numbers of a format, with the
+// sequence as the only capture group. Returns nil when the format has no {SEQ}.
+func dcNumberRegexp(format string) *regexp.Regexp {
+	if !strings.Contains(format, "{SEQ}") {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, m := range formatTokenRe.FindAllStringSubmatchIndex(format, -1) {
+		b.WriteString(regexp.QuoteMeta(format[last:m[0]]))
+		switch format[m[2]:m[3]] {
+		case "SEQ":
+			b.WriteString(`(\d+)`)
+		case "FY":
+			b.WriteString(`\d{2}-?\d{2}`)
+		case "TYPE":
+			b.WriteString(`[A-Z]+`)
+		default:
+			b.WriteString(`.+?`)
+		}
+		last = m[1]
+	}
+	b.WriteString(regexp.QuoteMeta(format[last:]))
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+// trailingDigitsRe matches the sequence at the end of a DC number.
+var trailingDigitsRe = regexp.MustCompile(`(\d+)$`)
+
+// SplitDCNumberSequence splits a DC number into its series (the number with the
+// sequence replaced by "#") and its sequence. The project's number format is
+// tried first; numbers issued under an earlier format fall back to the trailing
+// digits, which is where every built-in format puts the sequence.
+func SplitDCNumberSequence(dcNumber, format string) (series string, seq int, ok bool) {
+	if format == "" {
+		format = "{PREFIX}-{TYPE}-{FY}-{SEQ}"
+	}
+	loc := []int(nil)
+	if re := dcNumberRegexp(format); re != nil {
+		if m := re.FindStringSubmatchIndex(dcNumber); m != nil {
+			loc = m[2:4]
+		}
+	}
+	if loc == nil {
+		m := trailingDigitsRe.FindStringSubmatchIndex(dcNumber)
+		if m == nil {
+			return "", 0, false
+		}
+		loc = m[2:4]
+	}
+	seq, err := strconv.Atoi(dcNumber[loc[0]:loc[1]])
+	if err != nil {
+		return "", 0, false
+	}
+	return dcNumber[:loc[0]] + "#" + dcNumber[loc[1]:], seq, true
+}
+
+// documentTypeOrder lists DC types in the order the summary shows them.
+var documentTypeOrder = map[string]int{DCTypeTransit: 0, DCTypeOfficial: 1, DCTypeTransfer: 2, DCTypeReturn: 3}
+
+// SummariseDocumentSeries groups DCs by number series and financial year and
+// finds the first and last number of each, with counts of issued, cancelled,
+// not yet issued and deleted numbers in between. DC numbers that carry no
+// sequence are returned separately.
+func SummariseDocumentSeries(docs []IssuedDocument, format string) (series []DocumentSeries, unparsed []string) {
+	index := make(map[string]int)
+	for _, d := range docs {
+		pattern, seq, ok := SplitDCNumberSequence(d.DCNumber, format)
+		if !ok {
+			unparsed = append(unparsed, d.DCNumber)
+			continue
+		}
+		fy := GetFinancialYear(d.ChallanDate)
+		key := d.DCType + "|" + fy + "|" + pattern
+		i, seen := index[key]
+		if !seen {
+			i = len(series)
+			index[key] = i
+			series = append(series, DocumentSeries{
+				Series: pattern, DCType: d.DCType, FinancialYear: fy,
+				FromNumber: d.DCNumber, ToNumber: d.DCNumber, fromSeq: seq, toSeq: seq,
+				seqs: make(map[int]bool),
+			})
+		}
+
+		s := &series[i]
+		if s.seqs[seq] {
+			continue // the same number twice is a data error; count it once
+		}
+		s.seqs[seq] = true
+		if seq < s.fromSeq {
+			s.fromSeq, s.FromNumber = seq, d.DCNumber
+		}
+		if seq > s.toSeq {
+			s.toSeq, s.ToNumber = seq, d.DCNumber
+		}
+		switch d.Status {
+		case models.DCStatusCancelled:
+			s.Cancelled++
+		case models.DCStatusDraft, models.DCStatusPendingApproval, models.DCStatusRejected:
+			s.NotIssued++
+		default:
+			s.Issued++
+		}
+	}
+
+	for i := range series {
+		s := &series[i]
+		s.TotalNumber = s.toSeq - s.fromSeq + 1
+		s.Deleted = s.TotalNumber - len(s.seqs)
+		s.seqs = nil
+	}
+	sort.SliceStable(series, func(a, b int) bool {
+		x, y := series[a], series[b]
+		if x.DCType != y.DCType {
+			return documentTypeOrder[x.DCType] < documentTypeOrder[y.DCType]
+		}
+		if x.FinancialYear != y.FinancialYear {
+			return x.FinancialYear < y.FinancialYear
+		}
+		return x.Series < y.Series
+	})
+	return series, unparsed
+}
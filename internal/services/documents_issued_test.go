@@ -0,0 +1,76 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitDCNumberSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		dcNumber string
+		format   string
+		series   string
+		seq      int
+		ok       bool
+	}{
+		{"default format", "SCP-TDC-25-26-007", "", "SCP-TDC-25-26-#", 7, true},
+		{"slash format", "FS/GSWS/25-26/012", "{PREFIX}/{PROJECT_CODE}/{FY}/{SEQ}", "FS/GSWS/25-26/#", 12, true},
+		{"sequence not last", "FS-0042-25-26", "{PREFIX}-{SEQ}-{FY}", "FS-#-25-26", 42, true},
+		{"legacy number falls back", "SCP-TDC-2526-003", "{PREFIX}/{PROJECT_CODE}/{FY}/{SEQ}", "SCP-TDC-2526-#", 3, true},
+		{"no sequence", "MANUAL", "", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, seq, ok := SplitDCNumberSequence(tt.dcNumber, tt.format)
+			if ok != tt.ok || series != tt.series || seq != tt.seq {
+				t.Errorf("got (%q, %d, %v); want (%q, %d, %v)", series, seq, ok, tt.series, tt.seq, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSummariseDocumentSeries(t *testing.T) {
+	may := time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	apr := time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC)
+	docs := []IssuedDocument{
+		{"SCP-ODC-25-26-001", DCTypeOfficial, "issued", may},
+		{"SCP-TDC-25-26-001", DCTypeTransit, "issued", may},
+		{"SCP-TDC-25-26-002", DCTypeTransit, "cancelled", may},
+		{"SCP-TDC-25-26-005", DCTypeTransit, "delivered", feb},
+		{"SCP-TDC-25-26-004", DCTypeTransit, "draft", feb},
+		{"SCP-TDC-26-27-001", DCTypeTransit, "issued", apr},
+		{"OLD", DCTypeTransit, "issued", may},
+	}
+
+	series, unparsed := SummariseDocumentSeries(docs, "")
+	if len(unparsed) != 1 || unparsed[0] != "OLD" {
+		t.Errorf("unparsed = %v; want [OLD]", unparsed)
+	}
+	if len(series) != 3 {
+		t.Fatalf("got %d series; want 3", len(series))
+	}
+
+	tdc := series[0]
+	if tdc.DCType != DCTypeTransit || tdc.FinancialYear != "2526" {
+		t.Fatalf("first series = %s %s; want transit 2526", tdc.DCType, tdc.FinancialYear)
+	}
+	if tdc.FromNumber != "SCP-TDC-25-26-001" || tdc.ToNumber != "SCP-TDC-25-26-005" {
+		t.Errorf("range = %s..%s", tdc.FromNumber, tdc.ToNumber)
+	}
+	if tdc.TotalNumber != 5 || tdc.Issued != 2 || tdc.Cancelled != 1 || tdc.NotIssued != 1 || tdc.Deleted != 1 {
+		t.Errorf("counts = total %d issued %d cancelled %d not issued %d deleted %d; want 5 2 1 1 1",
+			tdc.TotalNumber, tdc.Issued, tdc.Cancelled, tdc.NotIssued, tdc.Deleted)
+	}
+	if tdc.GSTR1Cancelled() != 3 {
+		t.Errorf("GSTR1Cancelled = %d; want 3", tdc.GSTR1Cancelled())
+	}
+
+	if series[1].FinancialYear != "2627" || series[1].TotalNumber != 1 {
+		t.Errorf("second series = %+v; want transit 2627 with 1 number", series[1])
+	}
+	if series[2].DCType != DCTypeOfficial || series[2].Issued != 1 {
+		t.Errorf("third series = %+v; want official with 1 issued", series[2])
+	}
+}
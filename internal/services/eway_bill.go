@@ -0,0 +1,489 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/narendhupati/dc-management-tool/internal/helpers"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// EwayBillJSONVersion is the schema version of the NIC bulk e-way bill upload file.
+const EwayBillJSONVersion = "1.0.0621"
+
+// NIC code values used on delivery challans.
+const (
+	ewbSupplyOutward    = "O"
+	ewbSubSupplyOwnUse  = 5 // branch and hub transfers
+	ewbSubSupplyOthers  = 8
+	ewbDocTypeChallan   = "CHL"
+	ewbTransModeRoad    = 1
+	ewbVehicleRegular   = "R"
+	ewbUnregistered     = "URP"
+	ewbMaxDocNoLen      = 16
+	ewbMaxItems         = 250
+	ewbMaxProductLength = 100
+)
+
+var (
+	ewbPincodeRe = regexp.MustCompile(`^[1-9][0-9]{5}$`)
+	ewbHSNRe     = regexp.MustCompile(`^[0-9]{4,8}$`)
+	ewbVehicleRe = regexp.MustCompile(`^[A-Z0-9]{7,15}$`)
+	ewbDocNoRe   = regexp.MustCompile(`^[A-Za-z0-9/-]+$`)
+)
+
+// EwayBillFile is the NIC bulk-upload file: one or more e-way bills.
+type EwayBillFile struct {
+	Version   string     `json:"version"`
+	BillLists []EwayBill `json:"billLists"`
+}
+
+// EwayBill is one e-way bill in the NIC bulk-upload format.
+type EwayBill struct {
+	UserGstin           string         `json:"userGstin"`
+	SupplyType          string         `json:"supplyType"`
+	SubSupplyType       int            `json:"subSupplyType"`
+	SubSupplyDesc       string         `json:"subSupplyDesc"`
+	DocType             string         `json:"docType"`
+	DocNo               string         `json:"docNo"`
+	DocDate             string         `json:"docDate"`
+	TransType           int            `json:"transType"`
+	FromGstin           string         `json:"fromGstin"`
+	FromTrdName         string         `json:"fromTrdName"`
+	FromAddr1           string         `json:"fromAddr1"`
+	FromAddr2           string         `json:"fromAddr2"`
+	FromPlace           string         `json:"fromPlace"`
+	FromPincode         int            `json:"fromPincode"`
+	FromStateCode       int            `json:"fromStateCode"`
+	ActualFromStateCode int            `json:"actualFromStateCode"`
+	ToGstin             string         `json:"toGstin"`
+	ToTrdName           string         `json:"toTrdName"`
+	ToAddr1             string         `json:"toAddr1"`
+	ToAddr2             string         `json:"toAddr2"`
+	ToPlace             string         `json:"toPlace"`
+	ToPincode           int            `json:"toPincode"`
+	ToStateCode         int            `json:"toStateCode"`
+	ActualToStateCode   int            `json:"actualToStateCode"`
+	TotalValue          float64        `json:"totalValue"`
+	CgstValue           float64        `json:"cgstValue"`
+	SgstValue           float64        `json:"sgstValue"`
+	IgstValue           float64        `json:"igstValue"`
+	CessValue           float64        `json:"cessValue"`
+	TotNonAdvolVal      float64        `json:"TotNonAdvolVal"`
+	OthValue            float64        `json:"OthValue"`
+	TotInvValue         float64        `json:"totInvValue"`
+	TransMode           int            `json:"transMode"`
+	TransDistance       int            `json:"transDistance"` // 0 lets the portal compute it from the pincodes
+	TransporterName     string         `json:"transporterName"`
+	TransporterID       string         `json:"transporterId"`
+	TransDocNo          string         `json:"transDocNo"`
+	TransDocDate        string         `json:"transDocDate"`
+	VehicleNo           string         `json:"vehicleNo"`
+	VehicleType         string         `json:"vehicleType"`
+	MainHsnCode         int            `json:"mainHsnCode"`
+	ItemList            []EwayBillItem `json:"itemList"`
+}
+
+// EwayBillItem is one HSN line of an e-way bill.
+type EwayBillItem struct {
+	ItemNo        int     `json:"itemNo"`
+	ProductName   string  `json:"productName"`
+	ProductDesc   string  `json:"productDesc"`
+	HsnCode       int     `json:"hsnCode"`
+	Quantity      float64 `json:"quantity"`
+	QtyUnit       string  `json:"qtyUnit"`
+	TaxableAmount float64 `json:"taxableAmount"`
+	SgstRate      float64 `json:"sgstRate"`
+	CgstRate      float64 `json:"cgstRate"`
+	IgstRate      float64 `json:"igstRate"`
+	CessRate      float64 `json:"cessRate"`
+	CessNonAdvol  float64 `json:"cessNonAdvol"`
+}
+
+// EwayBillInput holds everything an e-way bill is built from. Addresses come from
+// the DC's snapshot; for transfer DCs ShipTo is the hub the goods move to.
+type EwayBillInput struct {
+	DC               *models.DeliveryChallan
+	Project          *models.Project
+	Company          *models.CompanySettings
+	BillFrom         *models.Address
+	DispatchFrom     *models.Address
+	BillTo           *models.Address
+	ShipTo           *models.Address
+	TaxType          string // "cgst_sgst" or "igst"; derived from the state codes when empty
+	LineItems        []models.DCLineItem
+	TransporterName  string
+	TransporterGSTIN string
+	VehicleNumber    string
+}
+
+// Address column names looked up for each e-way bill field, most specific first.
+var (
+	ewbNameFields    = []string{"Company Name", "Legal Name", "Trade Name", "Name"}
+	ewbAddr1Fields   = []string{"Address Line 1", "Address 1", "Address", "Location"}
+	ewbAddr2Fields   = []string{"Address Line 2", "Address 2"}
+	ewbPlaceFields   = []string{"City", "Place", "Town", "Village"}
+	ewbPincodeFields = []string{"PIN Code", "Pincode", "PIN", "Postal Code"}
+	ewbStateFields   = []string{"State", "State Name"}
+	ewbGSTINFields   = []string{"GSTIN", "GST Number", "GST No"}
+)
+
+// BuildEwayBill maps a transit or transfer DC onto an NIC e-way bill. It returns the
+// bill together with the problems that would make the portal reject it; the bill
+// must not be offered for download while there are problems.
+func BuildEwayBill(in *EwayBillInput) (*EwayBill, []string) {
+	var problems []string
+	dc := in.DC
+
+	bill := &EwayBill{
+		SupplyType:    ewbSupplyOutward,
+		SubSupplyType: ewbSubSupplyOthers,
+		SubSupplyDesc: "Project delivery",
+		DocType:       ewbDocTypeChallan,
+		DocNo:         dc.DCNumber,
+		TransMode:     ewbTransModeRoad,
+		VehicleType:   ewbVehicleRegular,
+	}
+	if dc.DCType == "transfer" {
+		bill.SubSupplyType = ewbSubSupplyOwnUse
+		bill.SubSupplyDesc = ""
+	}
+
+	if len(dc.DCNumber) > ewbMaxDocNoLen || !ewbDocNoRe.MatchString(dc.DCNumber) {
+		problems = append(problems, fmt.Sprintf("DC number %q must be at most %d letters, digits, '/' or '-'", dc.DCNumber, ewbMaxDocNoLen))
+	}
+	if d, ok := ewbDate(dc.ChallanDate); ok {
+		bill.DocDate = d
+	} else {
+		problems = append(problems, "Challan date is missing")
+	}
+
+	// Consignor: registered at the bill-from GSTIN, dispatching from the dispatch-from address.
+	var companyName, companyGSTIN string
+	if in.Project != nil {
+		companyName = in.Project.CompanyName
+		companyGSTIN = in.Project.CompanyGSTIN
+	}
+	if in.Company != nil {
+		companyName = firstNonEmpty(companyName, in.Company.Name)
+		companyGSTIN = firstNonEmpty(companyGSTIN, in.Company.GSTIN)
+	}
+	bill.FromGstin = strings.ToUpper(firstNonEmpty(in.BillFrom.Field(ewbGSTINFields...), companyGSTIN))
+	bill.UserGstin = bill.FromGstin
+	bill.FromTrdName = firstNonEmpty(in.BillFrom.Field(ewbNameFields...), companyName)
+
+	from := in.DispatchFrom
+	if from == nil {
+		from = in.BillFrom
+	}
+	bill.FromAddr1 = from.Field(ewbAddr1Fields...)
+	bill.FromAddr2 = from.Field(ewbAddr2Fields...)
+	bill.FromPlace = firstNonEmpty(from.Field(ewbPlaceFields...), addressDistrict(from))
+	fromPincode := from.Field(ewbPincodeFields...)
+	fromState := from.Field(ewbStateFields...)
+	if from == nil && in.Company != nil {
+		bill.FromAddr1 = in.Company.Address
+		bill.FromPlace = in.Company.City
+		fromPincode = in.Company.Pincode
+		fromState = firstNonEmpty(in.Company.StateCode, in.Company.State)
+	}
+
+	switch {
+	case bill.FromGstin == "":
+		problems = append(problems, "Supplier GSTIN is missing (set it on the project or the bill-from address)")
+	default:
+		if err := helpers.ValidateGSTIN(bill.FromGstin); err != nil {
+			problems = append(problems, fmt.Sprintf("Supplier GSTIN %q: %s", bill.FromGstin, err))
+		}
+	}
+	if bill.FromTrdName == "" {
+		problems = append(problems, "Supplier name is missing")
+	}
+	if bill.FromAddr1 == "" {
+		problems = append(problems, "Dispatch-from address line is missing")
+	}
+	if bill.FromPlace == "" {
+		problems = append(problems, "Dispatch-from city is missing")
+	}
+	bill.FromPincode = ewbPincode(fromPincode, "Dispatch-from", &problems)
+	bill.FromStateCode = models.GSTINStateCode(bill.FromGstin)
+	bill.ActualFromStateCode = models.GSTStateCode(fromState)
+	if bill.ActualFromStateCode == 0 {
+		bill.ActualFromStateCode = bill.FromStateCode
+	}
+	if bill.ActualFromStateCode == 0 {
+		problems = append(problems, "Dispatch-from state is missing or not recognised")
+	}
+
+	// Consignee: billed to the bill-to party when there is one, delivered to the ship-to.
+	to := in.ShipTo
+	billTo := in.BillTo
+	if billTo == nil {
+		billTo = to
+	}
+	if to == nil {
+		problems = append(problems, "Ship-to address is missing")
+	}
+	bill.ToGstin = strings.ToUpper(billTo.Field(ewbGSTINFields...))
+	if bill.ToGstin == "" && dc.DCType == "transfer" {
+		// Stock moving to our own hub stays under the supplier's registration.
+		bill.ToGstin = bill.FromGstin
+	}
+	if bill.ToGstin == "" {
+		bill.ToGstin = ewbUnregistered
+	}
+	bill.ToTrdName = firstNonEmpty(billTo.Field(ewbNameFields...), to.Field(ewbNameFields...), addressDistrict(to))
+	if dc.DCType == "transfer" && bill.ToTrdName == "" {
+		bill.ToTrdName = bill.FromTrdName
+	}
+	bill.ToAddr1 = firstNonEmpty(to.Field(ewbAddr1Fields...), addressLocality(to))
+	bill.ToAddr2 = to.Field(ewbAddr2Fields...)
+	bill.ToPlace = firstNonEmpty(to.Field(ewbPlaceFields...), addressDistrict(to))
+	toState := to.Field(ewbStateFields...)
+
+	if bill.ToGstin != ewbUnregistered {
+		if err := helpers.ValidateGSTIN(bill.ToGstin); err != nil {
+			problems = append(problems, fmt.Sprintf("Recipient GSTIN %q: %s", bill.ToGstin, err))
+		}
+	}
+	if bill.ToTrdName == "" {
+		problems = append(problems, "Recipient name is missing")
+	}
+	if to != nil && bill.ToAddr1 == "" {
+		problems = append(problems, "Ship-to address line is missing")
+	}
+	if to != nil && bill.ToPlace == "" {
+		problems = append(problems, "Ship-to city is missing")
+	}
+	if to != nil {
+		bill.ToPincode = ewbPincode(to.Field(ewbPincodeFields...), "Ship-to", &problems)
+	}
+	bill.ActualToStateCode = models.GSTStateCode(toState)
+	if bill.ToGstin != ewbUnregistered {
+		bill.ToStateCode = models.GSTINStateCode(bill.ToGstin)
+	} else {
+		bill.ToStateCode = models.GSTStateCode(firstNonEmpty(billTo.Field(ewbStateFields...), toState))
+	}
+	if bill.ActualToStateCode == 0 {
+		bill.ActualToStateCode = bill.ToStateCode
+	}
+	if bill.ToStateCode == 0 {
+		bill.ToStateCode = bill.ActualToStateCode
+	}
+	if to != nil && bill.ActualToStateCode == 0 {
+		problems = append(problems, "Ship-to state is missing or not recognised")
+	}
+
+	switch {
+	case in.BillTo != nil && in.ShipTo != nil && in.BillTo.ID != in.ShipTo.ID:
+		bill.TransType = 2 // Bill To - Ship To
+	default:
+		bill.TransType = 1
+	}
+	if in.DispatchFrom != nil && in.BillFrom != nil && in.DispatchFrom.ID != in.BillFrom.ID {
+		bill.TransType += 2 // Bill From - Dispatch From, or the combination of both
+	}
+
+	// Transport: Part-B needs a vehicle, or a transporter who will fill it in later.
+	bill.TransporterName = in.TransporterName
+	bill.TransporterID = strings.ToUpper(strings.TrimSpace(in.TransporterGSTIN))
+	bill.VehicleNo = ewbVehicleNumber(in.VehicleNumber)
+	if bill.VehicleNo != "" && !ewbVehicleRe.MatchString(bill.VehicleNo) {
+		problems = append(problems, fmt.Sprintf("Vehicle number %q is not a valid registration number", in.VehicleNumber))
+	}
+	if bill.VehicleNo == "" && bill.TransporterID == "" {
+		problems = append(problems, "Vehicle number or the transporter's GSTIN is required")
+	}
+
+	items, itemProblems := ewbItems(in.LineItems, ewbIsInterState(in.TaxType, bill))
+	problems = append(problems, itemProblems...)
+	bill.ItemList = items
+	var mainTaxable float64
+	for i := range items {
+		it := &items[i]
+		bill.TotalValue += it.TaxableAmount
+		bill.CgstValue += it.TaxableAmount * it.CgstRate / 100
+		bill.SgstValue += it.TaxableAmount * it.SgstRate / 100
+		bill.IgstValue += it.TaxableAmount * it.IgstRate / 100
+		if it.TaxableAmount > mainTaxable || bill.MainHsnCode == 0 {
+			mainTaxable = it.TaxableAmount
+			bill.MainHsnCode = it.HsnCode
+		}
+	}
+	bill.TotalValue = models.RoundPaise(bill.TotalValue)
+	bill.CgstValue = models.RoundPaise(bill.CgstValue)
+	bill.SgstValue = models.RoundPaise(bill.SgstValue)
+	bill.IgstValue = models.RoundPaise(bill.IgstValue)
+	bill.TotInvValue = models.RoundPaise(bill.TotalValue + bill.CgstValue + bill.SgstValue + bill.IgstValue)
+
+	return bill, problems
+}
+
+// MarshalEwayBills encodes bills as an NIC bulk-upload file.
+func MarshalEwayBills(bills []EwayBill) ([]byte, error) {
+	return json.MarshalIndent(EwayBillFile{Version: EwayBillJSONVersion, BillLists: bills}, "", "  ")
+}
+
+// ewbItems turns line items into e-way bill items, one per HSN code, unit and
+// tax rate, grouped the way the DC's HSN summary groups them.
+func ewbItems(lineItems []models.DCLineItem, interState bool) ([]EwayBillItem, []string) {
+	if len(lineItems) == 0 {
+		return nil, []string{"DC has no line items"}
+	}
+
+	taxType := "cgst_sgst"
+	if interState {
+		taxType = "igst"
+	}
+	var problems []string
+	var summary models.HSNSummary
+	for _, li := range lineItems { //nolint:gocritic
+		hsn := strings.ReplaceAll(strings.TrimSpace(li.HSNCode), " ", "")
+		if !ewbHSNRe.MatchString(hsn) {
+			problems = append(problems, fmt.Sprintf("HSN code of %s is missing or not 4-8 digits", li.ItemName))
+			continue
+		}
+		summary.Add(hsn, li.ItemName, li.UoM, li.TaxPercentage, li.Quantity, li.TaxableAmount, li.TaxAmount, taxType)
+	}
+	rows := summary.Rows()
+	if len(rows) > ewbMaxItems {
+		problems = append(problems, fmt.Sprintf("DC has %d HSN lines; an e-way bill takes at most %d", len(rows), ewbMaxItems))
+	}
+
+	items := make([]EwayBillItem, 0, len(rows))
+	for i, r := range rows {
+		code, _ := strconv.Atoi(r.HSNCode)
+		it := EwayBillItem{
+			ItemNo:        i + 1,
+			HsnCode:       code,
+			QtyUnit:       ewbQtyUnit(r.UoM),
+			Quantity:      float64(r.Quantity),
+			TaxableAmount: r.TaxableValue,
+			ProductName:   truncateRunes(r.Description, ewbMaxProductLength),
+		}
+		it.ProductDesc = it.ProductName
+		if interState {
+			it.IgstRate = r.TaxRate
+		} else {
+			it.CgstRate = r.TaxRate / 2
+			it.SgstRate = r.TaxRate / 2
+		}
+		items = append(items, it)
+	}
+	return items, problems
+}
+
+// ewbIsInterState reports whether a bill carries IGST. The DC's recorded tax type
+// wins; older DCs without one compare the registration states.
+func ewbIsInterState(taxType string, bill *EwayBill) bool {
+	switch taxType {
+	case "igst":
+		return true
+	case "cgst_sgst":
+		return false
+	}
+	return bill.FromStateCode != 0 && bill.ToStateCode != 0 && bill.FromStateCode != bill.ToStateCode
+}
+
+// ewbPincode parses a 6-digit pincode, recording a problem for the named address
+// when it is missing or malformed.
+func ewbPincode(s, label string, problems *[]string) int {
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	if s == "" {
+		*problems = append(*problems, label+" PIN code is missing")
+		return 0
+	}
+	if !ewbPincodeRe.MatchString(s) {
+		*problems = append(*problems, fmt.Sprintf("%s PIN code %q is not 6 digits", label, s))
+		return 0
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// ewbDate formats a challan date (YYYY-MM-DD...) as the portal's dd/mm/yyyy.
+func ewbDate(challanDate *string) (string, bool) {
+	if challanDate == nil || len(*challanDate) < 10 {
+		return "", false
+	}
+	t, err := time.Parse("2006-01-02", (*challanDate)[:10])
+	if err != nil {
+		return "", false
+	}
+	return t.Format("02/01/2006"), true
+}
+
+// ewbVehicleNumber strips the spaces and dashes people type into registration numbers.
+func ewbVehicleNumber(s string) string {
+	return strings.NewReplacer(" ", "", "-", "", ".", "").Replace(strings.ToUpper(strings.TrimSpace(s)))
+}
+
+// ewbQtyUnits maps common units of measure to GST unit quantity codes.
+var ewbQtyUnits = map[string]string{
+	"nos": "NOS", "no": "NOS", "number": "NOS", "numbers": "NOS",
+	"pcs": "PCS", "pc": "PCS", "piece": "PCS", "pieces": "PCS",
+	"set": "SET", "sets": "SET",
+	"box": "BOX", "boxes": "BOX",
+	"unit": "UNT", "units": "UNT",
+	"kg": "KGS", "kgs": "KGS",
+	"mtr": "MTR", "mtrs": "MTR", "meter": "MTR", "meters": "MTR", "metre": "MTR", "metres": "MTR",
+	"ltr": "LTR", "litre": "LTR", "liter": "LTR",
+	"pair": "PRS", "pairs": "PRS",
+	"roll": "ROL", "rolls": "ROL",
+	"pack": "PAC", "packs": "PAC",
+}
+
+// ewbQtyUnit returns the GST unit quantity code for a unit of measure, NOS by default.
+func ewbQtyUnit(uom string) string {
+	uom = strings.ToLower(strings.TrimSpace(uom))
+	if uom == "" {
+		return "NOS"
+	}
+	if code, ok := ewbQtyUnits[uom]; ok {
+		return code
+	}
+	return "OTH"
+}
+
+// addressDistrict returns an address's district, the place name ship-to addresses carry.
+func addressDistrict(a *models.Address) string {
+	if a == nil {
+		return ""
+	}
+	return a.DistrictName
+}
+
+// addressLocality joins the mandal and district of a ship-to address.
+func addressLocality(a *models.Address) string {
+	if a == nil {
+		return ""
+	}
+	var parts []string
+	for _, p := range []string{a.MandalName, a.DistrictName} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+func newTestEwayBillInput() *EwayBillInput {
+	challanDate := "2026-03-09"
+	return &EwayBillInput{
+		DC: &models.DeliveryChallan{
+			ID:          1,
+			ProjectID:   1,
+			DCNumber:    "TST-TDC-2526-001",
+			DCType:      "transit",
+			Status:      "issued",
+			ChallanDate: &challanDate,
+		},
+		Project: &models.Project{CompanyName: "Test Company Ltd", CompanyGSTIN: "36AABCT1234F1ZR"},
+		BillFrom: &models.Address{ID: 1, Data: map[string]string{
+			"Company Name": "Test Company Ltd", "Address Line 1": "1 HQ Road", "City": "Hyderabad", "State": "Telangana", "PIN Code": "500001",
+		}},
+		DispatchFrom: &models.Address{ID: 2, Data: map[string]string{
+			"Company Name": "Test Company Ltd", "Address Line 1": "Plot 7, Industrial Area", "City": "Medchal", "State": "Telangana", "PIN Code": "501401",
+		}},
+		BillTo: &models.Address{ID: 3, Data: map[string]string{
+			"Company Name": "AP Fibernet", "GSTIN": "37AAACA1234B1ZH", "Address Line 1": "Govt Complex", "City": "Vijayawada", "State": "Andhra Pradesh", "PIN Code": "520001",
+		}},
+		ShipTo: &models.Address{ID: 4, DistrictName: "Krishna", MandalName: "Gudivada", Data: map[string]string{
+			"Location": "Gram Panchayat Office", "State": "Andhra Pradesh", "Pincode": "521301",
+		}},
+		TaxType: "igst",
+		LineItems: []models.DCLineItem{
+			{ItemName: "Router", HSNCode: "8517", UoM: "Nos", Quantity: 2, TaxPercentage: 18, TaxableAmount: 1000},
+			{ItemName: "Switch", HSNCode: "8517", UoM: "Nos", Quantity: 3, TaxPercentage: 18, TaxableAmount: 1500},
+			{ItemName: "Cable", HSNCode: "8544 ", UoM: "Mtr", Quantity: 100, TaxPercentage: 12, TaxableAmount: 500},
+		},
+		TransporterName: "ABC Logistics",
+		VehicleNumber:   "ts 09-ab 1234",
+	}
+}
+
+func TestBuildEwayBill(t *testing.T) {
+	bill, problems := BuildEwayBill(newTestEwayBillInput())
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+
+	if bill.SupplyType != "O" || bill.DocType != "CHL" || bill.SubSupplyType != ewbSubSupplyOthers {
+		t.Errorf("supply/doc type = %s/%s/%d", bill.SupplyType, bill.DocType, bill.SubSupplyType)
+	}
+	if bill.DocDate != "09/03/2026" {
+		t.Errorf("DocDate = %q, want 09/03/2026", bill.DocDate)
+	}
+	if bill.FromGstin != "36AABCT1234F1ZR" || bill.FromStateCode != 36 || bill.FromPincode != 501401 || bill.FromPlace != "Medchal" {
+		t.Errorf("from = %s/%d/%d/%s", bill.FromGstin, bill.FromStateCode, bill.FromPincode, bill.FromPlace)
+	}
+	if bill.ToGstin != "37AAACA1234B1ZH" || bill.ToTrdName != "AP Fibernet" || bill.ToStateCode != 37 {
+		t.Errorf("to = %s/%s/%d", bill.ToGstin, bill.ToTrdName, bill.ToStateCode)
+	}
+	if bill.ToAddr1 != "Gram Panchayat Office" || bill.ToPlace != "Krishna" || bill.ToPincode != 521301 {
+		t.Errorf("ship to = %s/%s/%d", bill.ToAddr1, bill.ToPlace, bill.ToPincode)
+	}
+	if bill.TransType != 4 {
+		t.Errorf("TransType = %d, want 4 (bill-to/ship-to and bill-from/dispatch-from)", bill.TransType)
+	}
+	if bill.VehicleNo != "TS09AB1234" {
+		t.Errorf("VehicleNo = %q, want TS09AB1234", bill.VehicleNo)
+	}
+
+	// Router and Switch share an HSN code and rate, so they become one item.
+	if len(bill.ItemList) != 2 {
+		t.Fatalf("items = %d, want 2", len(bill.ItemList))
+	}
+	first := bill.ItemList[0]
+	if first.HsnCode != 8517 || first.Quantity != 5 || first.TaxableAmount != 2500 || first.IgstRate != 18 || first.CgstRate != 0 {
+		t.Errorf("first item = %+v", first)
+	}
+	if first.ProductName != "Router, Switch" || first.QtyUnit != "NOS" {
+		t.Errorf("first item name/unit = %q/%q", first.ProductName, first.QtyUnit)
+	}
+	if bill.ItemList[1].QtyUnit != "MTR" {
+		t.Errorf("cable unit = %q, want MTR", bill.ItemList[1].QtyUnit)
+	}
+	if bill.MainHsnCode != 8517 {
+		t.Errorf("MainHsnCode = %d, want 8517", bill.MainHsnCode)
+	}
+	if bill.TotalValue != 3000 || bill.IgstValue != 510 || bill.TotInvValue != 3510 {
+		t.Errorf("totals = %.2f/%.2f/%.2f", bill.TotalValue, bill.IgstValue, bill.TotInvValue)
+	}
+}
+
+func TestBuildEwayBillIntraStateTransfer(t *testing.T) {
+	in := newTestEwayBillInput()
+	in.DC.DCType = "transfer"
+	in.TaxType = "cgst_sgst"
+	in.BillTo = nil
+	in.ShipTo = &models.Address{ID: 5, Data: map[string]string{
+		"Address Line 1": "Hub Warehouse", "City": "Warangal", "State": "Telangana", "PIN Code": "506002",
+	}}
+
+	bill, problems := BuildEwayBill(in)
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	if bill.SubSupplyType != ewbSubSupplyOwnUse {
+		t.Errorf("SubSupplyType = %d, want own use", bill.SubSupplyType)
+	}
+	if bill.ToGstin != bill.FromGstin || bill.ToTrdName != "Test Company Ltd" {
+		t.Errorf("hub consignee = %s/%s, want the supplier", bill.ToGstin, bill.ToTrdName)
+	}
+	if bill.TransType != 3 {
+		t.Errorf("TransType = %d, want 3", bill.TransType)
+	}
+	it := bill.ItemList[0]
+	if it.CgstRate != 9 || it.SgstRate != 9 || it.IgstRate != 0 {
+		t.Errorf("rates = %v/%v/%v", it.CgstRate, it.SgstRate, it.IgstRate)
+	}
+	if bill.CgstValue != 255 || bill.SgstValue != 255 {
+		t.Errorf("cgst/sgst = %.2f/%.2f", bill.CgstValue, bill.SgstValue)
+	}
+}
+
+func TestBuildEwayBillProblems(t *testing.T) {
+	in := newTestEwayBillInput()
+	in.Project.CompanyGSTIN = ""
+	delete(in.ShipTo.Data, "Pincode")
+	in.LineItems[2].HSNCode = ""
+	in.VehicleNumber = ""
+	in.DC.ChallanDate = nil
+
+	_, problems := BuildEwayBill(in)
+	want := []string{"Supplier GSTIN", "Ship-to PIN code", "HSN code of Cable", "Vehicle number", "Challan date"}
+	joined := strings.Join(problems, "\n")
+	for _, w := range want {
+		if !strings.Contains(joined, w) {
+			t.Errorf("problems missing %q:\n%s", w, joined)
+		}
+	}
+
+	// A registered transporter lets the portal fill in the vehicle later.
+	in = newTestEwayBillInput()
+	in.VehicleNumber = ""
+	in.TransporterGSTIN = "36AAFCA9999K1ZR"
+	if _, problems := BuildEwayBill(in); len(problems) > 0 {
+		t.Errorf("transporter GSTIN without vehicle: %v", problems)
+	}
+
+	// Unregistered recipients are sent as URP.
+	in = newTestEwayBillInput()
+	delete(in.BillTo.Data, "GSTIN")
+	bill, problems := BuildEwayBill(in)
+	if len(problems) > 0 || bill.ToGstin != "URP" || bill.ToStateCode != 37 {
+		t.Errorf("unregistered recipient: gstin=%s state=%d problems=%v", bill.ToGstin, bill.ToStateCode, problems)
+	}
+}
+
+func TestMarshalEwayBills(t *testing.T) {
+	bill, _ := BuildEwayBill(newTestEwayBillInput())
+	data, err := MarshalEwayBills([]EwayBill{*bill, *bill})
+	if err != nil {
+		t.Fatalf("MarshalEwayBills: %v", err)
+	}
+	var file map[string]interface{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if file["version"] != EwayBillJSONVersion {
+		t.Errorf("version = %v", file["version"])
+	}
+	lists, _ := file["billLists"].([]interface{})
+	if len(lists) != 2 {
+		t.Fatalf("billLists = %d, want 2", len(lists))
+	}
+	first := lists[0].(map[string]interface{})
+	for _, k := range []string{"docType", "fromPincode", "toStateCode", "itemList", "vehicleNo", "mainHsnCode"} {
+		if _, ok := first[k]; !ok {
+			t.Errorf("bill JSON missing %q", k)
+		}
+	}
+}
@@ -41,6 +41,7 @@ type TransitDCExcelData struct {
 	HalfTax             float64
 	TotalQty            int
 	AmountInWords       string
+	TaxType             string // "cgst_sgst" or "igst", from the shipment group
 }
 
 // OfficialDCExcelData holds data needed for Official DC Excel generation.
@@ -237,7 +238,7 @@ func GenerateTransitDCExcel(data *TransitDCExcelData) (*excelize.File, error) {
 	// --- DC details + PO details (matches PDF drawDCAndPOGrid) ---
 	row += 2
 	_ = f.SetCellValue(sheet, fmt.Sprintf("A%d", row), "DC No:")
-	_ = f.SetCellValue(sheet, fmt.Sprintf("B%d", row), data.DC.DCNumber)
+	_ = f.SetCellValue(sheet, fmt.Sprintf("B%d", row), data.DC.DisplayNumber())
 	_ = f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("A%d", row), boldStyle)
 	if data.DC.ChallanDate != nil {
 		_ = f.SetCellValue(sheet, fmt.Sprintf("G%d", row), "DC Date:")
@@ -401,18 +402,27 @@ func GenerateTransitDCExcel(data *TransitDCExcelData) (*excelize.File, error) {
 		_ = f.SetCellStyle(sheet, fmt.Sprintf("%c%d", col, row), fmt.Sprintf("%c%d", col, row), s)
 	}
 
-	// --- Tax summary (matching PDF drawTaxSummary) ---
+	// --- HSN summary (matching PDF drawHSNSummary) ---
 	row += 2
-	summaryItems := []struct {
+	row = writeHSNSummary(f, sheet, row, models.SummariseByHSN(data.LineItems, data.TaxType), data.TaxType,
+		boldStyle, tableHeaderStyle, cellStyle, numStyle, totalRowStyle, totalRowNumStyle)
+
+	// --- Tax summary (matching PDF drawTaxSummary) ---
+	row++
+	type summaryItem struct {
 		label string
 		value float64
-	}{
-		{"Taxable Value:", data.TotalTaxable},
-		{"CGST:", data.HalfTax},
-		{"SGST:", data.HalfTax},
-		{"Round Off:", data.RoundOff},
-		{"Invoice Value:", data.RoundedTotal},
 	}
+	summaryItems := []summaryItem{{"Taxable Value:", data.TotalTaxable}}
+	if data.TaxType == "igst" {
+		summaryItems = append(summaryItems, summaryItem{"IGST:", data.TotalTax})
+	} else {
+		summaryItems = append(summaryItems, summaryItem{"CGST:", data.HalfTax}, summaryItem{"SGST:", data.HalfTax})
+	}
+	summaryItems = append(summaryItems,
+		summaryItem{"Round Off:", data.RoundOff},
+		summaryItem{"Invoice Value:", data.RoundedTotal},
+	)
 	for _, item := range summaryItems {
 		_ = f.SetCellValue(sheet, fmt.Sprintf("J%d", row), item.label)
 		_ = f.SetCellValue(sheet, fmt.Sprintf("K%d", row), item.value)
@@ -485,6 +495,74 @@ func GenerateTransitDCExcel(data *TransitDCExcelData) (*excelize.File, error) {
 	return f, nil
 }
 
+// writeHSNSummary writes the HSN summary table starting at row and returns the
+// row after it. Nothing is written when there are no rows.
+func writeHSNSummary(f *excelize.File, sheet string, row int, rows []models.HSNSummaryRow, taxType string,
+	titleStyle, headerStyle, cellStyle, numStyle, totalStyle, totalNumStyle int) int {
+	if len(rows) == 0 {
+		return row
+	}
+
+	headers := []string{"HSN", "Description", "UoM", "Qty", "Taxable", "CGST %", "CGST", "SGST %", "SGST", "Total Tax"}
+	if taxType == "igst" {
+		headers = []string{"HSN", "Description", "UoM", "Qty", "Taxable", "IGST %", "IGST", "Total Tax"}
+	}
+	numCols := map[string]bool{"Taxable": true, "CGST": true, "SGST": true, "IGST": true, "Total Tax": true}
+
+	_ = f.SetCellValue(sheet, fmt.Sprintf("A%d", row), "HSN Summary")
+	_ = f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("A%d", row), titleStyle)
+	row++
+	for i, h := range headers {
+		cell := cellName(i+1, row)
+		_ = f.SetCellValue(sheet, cell, h)
+		_ = f.SetCellStyle(sheet, cell, cell, headerStyle)
+	}
+	row++
+
+	writeRow := func(r models.HSNSummaryRow, total bool) {
+		values := []interface{}{r.HSNCode, r.Description, r.UoM, r.Quantity, r.TaxableValue}
+		if taxType == "igst" {
+			values = append(values, r.TaxRate, r.IGST, r.TotalTax())
+		} else {
+			values = append(values, r.TaxRate/2, r.CGST, r.TaxRate/2, r.SGST, r.TotalTax())
+		}
+		if total {
+			values[1] = "Total"
+			for i, h := range headers {
+				if strings.HasSuffix(h, "%") {
+					values[i] = ""
+				}
+			}
+		}
+		for i, v := range values {
+			cell := cellName(i+1, row)
+			_ = f.SetCellValue(sheet, cell, v)
+			style := cellStyle
+			switch {
+			case total && numCols[headers[i]]:
+				style = totalNumStyle
+			case total:
+				style = totalStyle
+			case numCols[headers[i]]:
+				style = numStyle
+			}
+			_ = f.SetCellStyle(sheet, cell, cell, style)
+		}
+		row++
+	}
+	for _, r := range rows {
+		writeRow(r, false)
+	}
+	writeRow(models.HSNSummaryTotals(rows), true)
+	return row
+}
+
+// cellName returns the A1-style name of a 1-based column and row.
+func cellName(col, row int) string {
+	name, _ := excelize.CoordinatesToCellName(col, row)
+	return name
+}
+
 // resolveCompanyHeader returns company header fields from project settings with CompanySettings fallback.
 func resolveCompanyHeader(project *models.Project, company *models.CompanySettings) (name, addr, gstin, cin, pan string) {
 	if project != nil {
@@ -633,7 +711,7 @@ func GenerateOfficialDCExcel(data *OfficialDCExcelData) (*excelize.File, error)
 	row += 2
 	// Left column: DC No, Date, Transporter, Vehicle, E-Way Bill, Reverse Charge
 	_ = f.SetCellValue(sheet, fmt.Sprintf("A%d", row), "DC Number:")
-	_ = f.SetCellValue(sheet, fmt.Sprintf("B%d", row), data.DC.DCNumber)
+	_ = f.SetCellValue(sheet, fmt.Sprintf("B%d", row), data.DC.DisplayNumber())
 	_ = f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("A%d", row), boldStyle)
 	if data.DC.ChallanDate != nil {
 		_ = f.SetCellValue(sheet, fmt.Sprintf("E%d", row), "DC Date:")
@@ -147,6 +147,22 @@ func TestGenerateTransitDCExcel(t *testing.T) {
 	if val != "TEST COMPANY" {
 		t.Errorf("A1 = %q, want 'TEST COMPANY'", val)
 	}
+
+	// HSN summary follows the product table, with CGST/SGST at half the rate.
+	rows, _ := f.GetRows("Transit DC")
+	found := false
+	for i, r := range rows {
+		if len(r) > 0 && r[0] == "HSN Summary" && i+2 < len(rows) {
+			found = true
+			line := rows[i+2]
+			if len(line) < 10 || line[0] != "850440" || line[5] != "9" || line[9] != "3,600.00" {
+				t.Errorf("HSN summary row = %v", line)
+			}
+		}
+	}
+	if !found {
+		t.Error("HSN Summary section missing")
+	}
 }
 
 func TestSanitizeDCFilename(t *testing.T) {
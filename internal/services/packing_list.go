@@ -0,0 +1,141 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// packingListHeaderRows is how far down a packing list the header row is
+// looked for; OEM lists usually open with a few lines of consignment details.
+const packingListHeaderRows = 20
+
+// PackingListRow is one serial read from an OEM packing list. Product is the
+// model or item named on the row, "" when the list has no such column.
+type PackingListRow struct {
+	Row     int
+	Product string
+	Serial  string
+}
+
+// ParsePackingList reads the serials from an OEM packing list in CSV or Excel
+// (.xlsx) format. The header row is the first with a serial number column; a
+// model, product or item column next to it names the product of each serial.
+// A list with no recognisable header is read as one serial per row from the
+// first column. A cell holding several serials is split like a pasted list.
+func ParsePackingList(r io.Reader, filename string) ([]PackingListRow, error) {
+	var rows [][]string
+	var lines []int // file line of each row, when it is not the row index + 1
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CSV: %v", err)
+			}
+			// Blank lines are skipped by the reader; keep the file's line numbers.
+			line, _ := reader.FieldPos(0)
+			lines = append(lines, line)
+			rows = append(rows, record)
+		}
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Excel file: %v", err)
+		}
+		defer f.Close()
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("Excel file has no sheets")
+		}
+		all, err := f.GetRows(sheets[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sheet: %v", err)
+		}
+		rows = all
+	default:
+		return nil, fmt.Errorf("only CSV and Excel (.xlsx) packing lists are supported")
+	}
+
+	headerRow, serialCol, productCol := findPackingListHeader(rows)
+	if headerRow < 0 {
+		serialCol = 0
+	}
+
+	var out []PackingListRow
+	for i := headerRow + 1; i < len(rows); i++ {
+		row := rows[i]
+		if serialCol >= len(row) {
+			continue
+		}
+		product := ""
+		if productCol >= 0 && productCol < len(row) {
+			product = strings.TrimSpace(row[productCol])
+		}
+		for _, sn := range SplitSerials(row[serialCol]) {
+			line := i + 1
+			if lines != nil {
+				line = lines[i]
+			}
+			out = append(out, PackingListRow{Row: line, Product: product, Serial: sn})
+		}
+	}
+	return out, nil
+}
+
+// findPackingListHeader returns the index of the header row and of its serial
+// and product columns, or -1 for each that is not found.
+func findPackingListHeader(rows [][]string) (headerRow, serialCol, productCol int) {
+	for i := 0; i < len(rows) && i < packingListHeaderRows; i++ {
+		serialCol, productCol = -1, -1
+		for j, cell := range rows[i] {
+			h := strings.ToLower(strings.TrimSpace(cell))
+			switch {
+			case serialCol < 0 && isSerialHeader(h):
+				serialCol = j
+			case productCol < 0 && (strings.Contains(h, "model") || strings.Contains(h, "product") ||
+				strings.Contains(h, "item") || strings.Contains(h, "part")):
+				productCol = j
+			}
+		}
+		if serialCol >= 0 {
+			return i, serialCol, productCol
+		}
+	}
+	return -1, -1, -1
+}
+
+// isSerialHeader reports whether a lower-cased header names a serial column.
+// "Sr. No." is left out: on Indian packing lists it is the row number.
+func isSerialHeader(h string) bool {
+	switch h {
+	case "sn", "s/n", "imei", "mac", "mac address":
+		return true
+	}
+	return strings.Contains(h, "serial")
+}
+
+// SplitSerials splits a pasted list of serials on newlines, commas, semicolons
+// and tabs, trimming whitespace and skipping empty entries.
+func SplitSerials(raw string) []string {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == '\n' || r == '\r' || r == ',' || r == ';' || r == '\t'
+	})
+	var serials []string
+	for _, f := range fields {
+		if s := strings.TrimSpace(f); s != "" {
+			serials = append(serials, s)
+		}
+	}
+	return serials
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParsePackingListCSV(t *testing.T) {
+	csv := "Packing List,INV-778\nConsignee,AP Fibernet\n\nSl. No.,Model,Serial Number\n1,AX3000,SN001\n2,AX3000,\"SN002, SN003\"\n3,,\n"
+	rows, err := ParsePackingList(strings.NewReader(csv), "list.CSV")
+	if err != nil {
+		t.Fatalf("ParsePackingList: %v", err)
+	}
+	want := []PackingListRow{
+		{Row: 5, Product: "AX3000", Serial: "SN001"},
+		{Row: 6, Product: "AX3000", Serial: "SN002"},
+		{Row: 6, Product: "AX3000", Serial: "SN003"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+}
+
+func TestParsePackingListNoHeader(t *testing.T) {
+	rows, err := ParsePackingList(strings.NewReader("SN001\nSN002\n\n"), "serials.csv")
+	if err != nil {
+		t.Fatalf("ParsePackingList: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Serial != "SN001" || rows[1].Product != "" {
+		t.Errorf("rows = %+v", rows)
+	}
+}
+
+func TestParsePackingListExcel(t *testing.T) {
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Item", "IMEI"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Tracker", "356938035643809"})
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatalf("write xlsx: %v", err)
+	}
+
+	rows, err := ParsePackingList(&buf, "list.xlsx")
+	if err != nil {
+		t.Fatalf("ParsePackingList: %v", err)
+	}
+	want := []PackingListRow{{Row: 2, Product: "Tracker", Serial: "356938035643809"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+
+	if _, err := ParsePackingList(strings.NewReader(""), "list.pdf"); err == nil {
+		t.Error("expected an error for a PDF packing list")
+	}
+}
+
+func TestSplitSerials(t *testing.T) {
+	got := SplitSerials(" SN1\r\nSN2, SN3;SN4\tSN5\n\n")
+	want := []string{"SN1", "SN2", "SN3", "SN4", "SN5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitSerials = %v, want %v", got, want)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/go-pdf/fpdf"
@@ -70,6 +71,7 @@ type TransitDCPDFData struct {
 	TotalQty          int
 	AmountInWords     string
 	TransferDCNumber  string // parent Transfer DC number (for split transit DCs)
+	TaxType           string // "cgst_sgst" or "igst", from the shipment group
 }
 
 // OfficialDCPDFData holds all data needed to generate an Official DC PDF.
@@ -102,14 +104,15 @@ type tableCol struct {
 
 // GenerateTransitDCPDF produces a PDF for a Transit Delivery Challan.
 func GenerateTransitDCPDF(data *TransitDCPDFData) ([]byte, error) {
-	pdf := newPDF(&pdfHeaderConfig{Project: data.Project, Company: data.Company})
+	pdf := newPDF(&pdfHeaderConfig{Project: data.Project, Company: data.Company, Watermark: dcWatermark(data.DC)})
 
 	drawCompanyHeader(pdf, data.Project, data.Company, false, 0)
 	drawDCTitle(pdf, "Delivery Challan", false)
 	drawDCAndPOGrid(pdf, data.DC, data.TransitDetails, data.Project, data.TransferDCNumber)
 	drawTransitAddressGrid(pdf, data.Company, data.BillFromAddress, data.DispatchFromAddress, data.BillToAddress, data.ShipToAddress, data.BillFromConfig, data.DispatchFromConfig, data.BillToConfig, data.ShipToConfig)
 	drawTransitProductTable(pdf, data.LineItems, data.TotalQty, data.TotalTaxable, data.TotalTax, data.GrandTotal)
-	drawTaxSummary(pdf, data.TotalTaxable, data.HalfTax, data.RoundOff, data.RoundedTotal)
+	drawHSNSummary(pdf, models.SummariseByHSN(data.LineItems, data.TaxType), data.TaxType)
+	drawTaxSummary(pdf, data.TotalTaxable, data.TotalTax, data.TaxType, data.RoundOff, data.RoundedTotal)
 	drawAmountInWords(pdf, data.AmountInWords)
 
 	if data.Project != nil && data.Project.Notes != "" {
@@ -130,6 +133,7 @@ func GenerateOfficialDCPDF(data *OfficialDCPDFData) ([]byte, error) {
 		ShowEmail:  true,
 		QRReserved: 25,
 		QRDCNumber: data.DC.DCNumber,
+		Watermark:  dcWatermark(data.DC),
 	})
 
 	drawCompanyHeader(pdf, data.Project, data.Company, true, 25)
@@ -168,20 +172,45 @@ type pdfHeaderConfig struct {
 	ShowEmail  bool
 	QRReserved float64
 	QRDCNumber string // non-empty → draw QR code on page 1 only (Official DC)
+	Watermark  string // non-empty → diagonal watermark behind every page (e.g. CANCELLED)
+}
+
+// dcWatermark returns the watermark text for a DC's status, or "" for none.
+func dcWatermark(dc *models.DeliveryChallan) string {
+	if dc != nil && dc.Status == models.DCStatusCancelled {
+		return "CANCELLED"
+	}
+	return ""
+}
+
+// drawWatermark draws large diagonal text across the centre of the current page.
+// It is drawn from the header func so page content is laid over it.
+func drawWatermark(pdf *fpdf.Fpdf, text string) {
+	x, y := pdf.GetXY()
+	setFont(pdf, "B", 90)
+	pdf.SetTextColor(250, 200, 200)
+	w := pdf.GetStringWidth(text)
+	cx, cy := pageW/2, pageH/2
+	pdf.TransformBegin()
+	pdf.TransformRotate(45, cx, cy)
+	pdf.Text(cx-w/2, cy+10, text)
+	pdf.TransformEnd()
+	setColor(pdf, colorBlack)
+	pdf.SetXY(x, y)
 }
 
 func newPDF(hdr *pdfHeaderConfig) *fpdf.Fpdf {
 	pdf := fpdf.New("P", "mm", "A4", "")
 	pdf.SetMargins(marginL, marginT, marginR)
 	pdf.SetAutoPageBreak(true, marginB)
-
-	// Register embedded UTF-8 fonts
-	pdf.AddUTF8FontFromBytes("dejavu", "", dejaVuSansRegular)
-	pdf.AddUTF8FontFromBytes("dejavu", "B", dejaVuSansBold)
+	registerFonts(pdf)
 
 	// Automatic company header on every page
 	if hdr != nil {
 		pdf.SetHeaderFunc(func() {
+			if hdr.Watermark != "" {
+				drawWatermark(pdf, hdr.Watermark)
+			}
 			drawCompanyHeader(pdf, hdr.Project, hdr.Company, hdr.ShowEmail, hdr.QRReserved)
 			if hdr.QRDCNumber != "" && pdf.PageNo() == 1 {
 				drawQRCode(pdf, hdr.QRDCNumber)
@@ -202,6 +231,12 @@ func newPDF(hdr *pdfHeaderConfig) *fpdf.Fpdf {
 	return pdf
 }
 
+// registerFonts registers the embedded UTF-8 fonts used by setFont.
+func registerFonts(pdf *fpdf.Fpdf) {
+	pdf.AddUTF8FontFromBytes("dejavu", "", dejaVuSansRegular)
+	pdf.AddUTF8FontFromBytes("dejavu", "B", dejaVuSansBold)
+}
+
 func pdfToBytes(pdf *fpdf.Fpdf) ([]byte, error) {
 	if err := pdf.Error(); err != nil {
 		return nil, fmt.Errorf("pdf generation error: %w", err)
@@ -385,20 +420,27 @@ func drawQRCode(pdf *fpdf.Fpdf, dcNumber string) {
 	if dcNumber == "" {
 		return
 	}
-	png, err := qrcode.Encode(dcNumber, qrcode.Medium, 256)
-	if err != nil {
-		return // silently skip QR on error
-	}
 	const qrSize = 20.0
-	x := pageW - marginR - qrSize
-	y := marginT
+	drawQRCodeAt(pdf, dcNumber, pageW-marginR-qrSize, marginT, qrSize) // silently skip QR on error
+}
 
+// drawQRCodeAt places a QR code of content at an absolute position without
+// moving the Y cursor. It returns false when content does not fit in a QR code.
+func drawQRCodeAt(pdf *fpdf.Fpdf, content string, x, y, size float64) bool {
+	png, err := qrcode.Encode(content, qrcode.Medium, 256)
+	if err != nil {
+		return false
+	}
 	opts := fpdf.ImageOptions{ImageType: "PNG", ReadDpi: true}
-	pdf.RegisterImageOptionsReader("qr_"+dcNumber, opts, bytes.NewReader(png))
+	name := "qr_" + content
+	if pdf.GetImageInfo(name) == nil {
+		pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(png))
+	}
 
 	savedY := pdf.GetY()
-	pdf.ImageOptions("qr_"+dcNumber, x, y, qrSize, qrSize, false, opts, 0, "")
+	pdf.ImageOptions(name, x, y, size, size, false, opts, 0, "")
 	pdf.SetY(savedY)
+	return true
 }
 
 func drawCompanyHeader(pdf *fpdf.Fpdf, project *models.Project, company *models.CompanySettings, showEmail bool, qrReserved float64) {
@@ -576,7 +618,7 @@ func drawDCAndPOGrid(pdf *fpdf.Fpdf, dc *models.DeliveryChallan, td *models.DCTr
 	// Left box: DC details
 	drawBorderedRect(pdf, marginL, y, colW, boxH)
 	pdf.SetXY(marginL+cellPad, y+cellPad)
-	kvRow(pdf, "DC No:", dc.DCNumber, 24, innerW)
+	kvRow(pdf, "DC No:", dc.DisplayNumber(), 24, innerW)
 	if refDCNumber != "" {
 		pdf.SetX(marginL + cellPad)
 		kvRow(pdf, "Ref DC:", refDCNumber, 24, innerW)
@@ -758,6 +800,9 @@ func drawTransitProductTable(pdf *fpdf.Fpdf, items []models.DCLineItem, totalQty
 		if li.ItemDescription != "" {
 			desc += "\n" + li.ItemDescription
 		}
+		if kit := li.KitBreakdown(); kit != "" {
+			desc += "\nKit: " + kit
+		}
 
 		values := []string{
 			fmt.Sprintf("%d", i+1),
@@ -787,9 +832,80 @@ func drawTransitProductTable(pdf *fpdf.Fpdf, items []models.DCLineItem, totalQty
 	spacer(pdf, 4)
 }
 
+// --- Section: HSN Summary ---
+
+// drawHSNSummary draws the line items grouped by HSN code and GST rate, with the
+// tax shown as CGST and SGST, or as IGST for inter-state supplies.
+func drawHSNSummary(pdf *fpdf.Fpdf, rows []models.HSNSummaryRow, taxType string) {
+	if len(rows) == 0 {
+		return
+	}
+
+	var cols []tableCol
+	if taxType == "igst" {
+		cols = []tableCol{
+			{"HSN", 16, "C"},
+			{"Description", 62, "L"},
+			{"UoM", 10, "C"},
+			{"Qty", 12, "C"},
+			{"Taxable", 26, "R"},
+			{"IGST %", 14, "C"},
+			{"IGST", 28, "R"},
+			{"Total Tax", 28, "R"},
+		}
+	} else {
+		cols = []tableCol{
+			{"HSN", 16, "C"},
+			{"Description", 40, "L"},
+			{"UoM", 10, "C"},
+			{"Qty", 12, "C"},
+			{"Taxable", 26, "R"},
+			{"CGST %", 11, "C"},
+			{"CGST", 22, "R"},
+			{"SGST %", 11, "C"},
+			{"SGST", 22, "R"},
+			{"Total Tax", 26, "R"},
+		}
+	}
+	values := func(r models.HSNSummaryRow, total bool) []string {
+		hsn, desc, uom, qty := r.HSNCode, r.Description, r.UoM, fmt.Sprintf("%d", r.Quantity)
+		igstRate, halfRate := fmtRate(r.TaxRate), fmtRate(r.TaxRate/2)
+		if total {
+			hsn, desc, uom, igstRate, halfRate = "", "Total", "", "", ""
+		} else if hsn == "" {
+			hsn = "-"
+		}
+		if taxType == "igst" {
+			return []string{hsn, desc, uom, qty, "Rs." + fmtINR(r.TaxableValue), igstRate, "Rs." + fmtINR(r.IGST), "Rs." + fmtINR(r.TotalTax())}
+		}
+		return []string{hsn, desc, uom, qty, "Rs." + fmtINR(r.TaxableValue), halfRate, "Rs." + fmtINR(r.CGST), halfRate, "Rs." + fmtINR(r.SGST), "Rs." + fmtINR(r.TotalTax())}
+	}
+
+	// Keep the title with the header row and at least one data row.
+	ensureSpace(pdf, 4+6+6)
+	pdf.SetX(tblMarginL)
+	setFont(pdf, "B", 7)
+	setColor(pdf, colorBlack)
+	pdf.CellFormat(tblW, 4, "HSN SUMMARY", "", 1, "L", false, 0, "")
+
+	drawTableHeaderRow(pdf, cols)
+	for _, r := range rows {
+		drawTableDataRow(pdf, cols, values(r, false), false)
+	}
+	drawTableDataRow(pdf, cols, values(models.HSNSummaryTotals(rows), true), true)
+	spacer(pdf, 4)
+}
+
+// fmtRate formats a GST rate without trailing zeros ("18%", "2.5%").
+func fmtRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64) + "%"
+}
+
 // --- Section: Tax Summary ---
 
-func drawTaxSummary(pdf *fpdf.Fpdf, totalTaxable, halfTax, roundOff, roundedTotal float64) {
+// drawTaxSummary draws the tax totals box. Inter-state supplies show IGST; all
+// others split the tax equally into CGST and SGST.
+func drawTaxSummary(pdf *fpdf.Fpdf, totalTaxable, totalTax float64, taxType string, roundOff, roundedTotal float64) {
 	boxW := 80.0
 	x := marginL + contentW - boxW
 	y := pdf.GetY()
@@ -797,17 +913,25 @@ func drawTaxSummary(pdf *fpdf.Fpdf, totalTaxable, halfTax, roundOff, roundedTota
 	setDrawColor(pdf, colorBlack)
 	pdf.SetLineWidth(0.3)
 
-	rows := []struct {
+	type summaryRow struct {
 		label string
 		value string
 		bold  bool
-	}{
-		{"Taxable Value", "Rs." + fmtINR(totalTaxable), false},
-		{"CGST", "Rs." + fmtINR(halfTax), false},
-		{"SGST", "Rs." + fmtINR(halfTax), false},
-		{"Round Off", fmt.Sprintf("Rs.%.2f", roundOff), false},
-		{"Invoice Value", "Rs." + fmtINR(roundedTotal), true},
 	}
+	rows := []summaryRow{{"Taxable Value", "Rs." + fmtINR(totalTaxable), false}}
+	if taxType == "igst" {
+		rows = append(rows, summaryRow{"IGST", "Rs." + fmtINR(totalTax), false})
+	} else {
+		halfTax := totalTax / 2.0
+		rows = append(rows,
+			summaryRow{"CGST", "Rs." + fmtINR(halfTax), false},
+			summaryRow{"SGST", "Rs." + fmtINR(halfTax), false},
+		)
+	}
+	rows = append(rows,
+		summaryRow{"Round Off", fmt.Sprintf("Rs.%.2f", roundOff), false},
+		summaryRow{"Invoice Value", "Rs." + fmtINR(roundedTotal), true},
+	)
 
 	rowH := 5.0
 	totalH := float64(len(rows)) * rowH
@@ -986,14 +1110,11 @@ func drawTransitSignatures(pdf *fpdf.Fpdf, company *models.CompanySettings, proj
 
 func drawSerialNumbersAnnexure(pdf *fpdf.Fpdf, items []models.DCLineItem, annexureNumber int) {
 	// Check if any line item has serial numbers
-	hasSerials := false
+	var groups []serialGroup
 	for _, li := range items {
-		if len(li.SerialNumbers) > 0 {
-			hasSerials = true
-			break
-		}
+		groups = append(groups, lineSerialGroups(li)...)
 	}
-	if !hasSerials {
+	if len(groups) == 0 {
 		return
 	}
 
@@ -1024,23 +1145,42 @@ func drawSerialNumbersAnnexure(pdf *fpdf.Fpdf, items []models.DCLineItem, annexu
 
 	drawTableHeaderRow(pdf, cols)
 
-	row := 1
-	for _, li := range items {
-		if len(li.SerialNumbers) == 0 {
-			continue
-		}
-		serialsText := strings.Join(li.SerialNumbers, ", ")
+	for i, g := range groups {
 		values := []string{
-			fmt.Sprintf("%d", row),
-			li.ItemName,
-			serialsText,
+			fmt.Sprintf("%d", i+1),
+			g.label,
+			strings.Join(g.serials, ", "),
 		}
 		drawTableDataRow(pdf, cols, values, false)
-		row++
 	}
 	spacer(pdf, 4)
 }
 
+// serialGroup is the serials of one product on a line: the line's own
+// product, or one component of a kit.
+type serialGroup struct {
+	label   string
+	serials []string
+}
+
+// lineSerialGroups splits a line item's serials by product, one group per
+// component of a kit, leaving out products without serials.
+func lineSerialGroups(li models.DCLineItem) []serialGroup {
+	if len(li.KitComponents) == 0 {
+		if len(li.SerialNumbers) == 0 {
+			return nil
+		}
+		return []serialGroup{{label: li.ItemName, serials: li.SerialNumbers}}
+	}
+	var groups []serialGroup
+	for _, c := range li.KitComponents {
+		if len(c.SerialNumbers) > 0 {
+			groups = append(groups, serialGroup{label: li.ItemName + " — " + c.ItemName, serials: c.SerialNumbers})
+		}
+	}
+	return groups
+}
+
 // --- Section: Official Product Table ---
 
 func drawOfficialProductTable(pdf *fpdf.Fpdf, items []models.DCLineItem) {
@@ -1056,6 +1196,15 @@ func drawOfficialProductTable(pdf *fpdf.Fpdf, items []models.DCLineItem) {
 
 	for i, li := range items {
 		serials := strings.Join(li.SerialNumbers, "\n")
+		if len(li.KitComponents) > 0 {
+			var lines []string
+			for _, c := range li.KitComponents {
+				if len(c.SerialNumbers) > 0 {
+					lines = append(lines, c.ItemName+": "+strings.Join(c.SerialNumbers, ", "))
+				}
+			}
+			serials = strings.Join(lines, "\n")
+		}
 
 		// Combine item name, description, and brand/model into one stacked cell
 		itemDetails := li.ItemName
@@ -1065,6 +1214,9 @@ func drawOfficialProductTable(pdf *fpdf.Fpdf, items []models.DCLineItem) {
 		if li.BrandModel != "" {
 			itemDetails += "\nMake & Model: " + li.BrandModel
 		}
+		if kit := li.KitBreakdown(); kit != "" {
+			itemDetails += "\nKit: " + kit
+		}
 
 		values := []string{
 			fmt.Sprintf("%d", i+1),
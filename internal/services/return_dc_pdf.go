@@ -0,0 +1,101 @@
+package services
+
+import (
+	"math"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// ReturnDCPDFData holds all data needed to generate a Return DC (material return note) PDF.
+type ReturnDCPDFData struct {
+	Project   *models.Project
+	DC        *models.DeliveryChallan
+	Return    *models.ReturnDC
+	Company   *models.CompanySettings
+	LineItems []models.DCLineItem // one per returned product, carrying its serials
+
+	// The goods come back from the original ship-to location to the dispatch-from address.
+	ReturnedFromAddress *models.Address
+	ReturnedToAddress   *models.Address
+	ReturnedFromConfig  *models.AddressListConfig
+	ReturnedToConfig    *models.AddressListConfig
+}
+
+// GenerateReturnDCPDF produces a PDF for a Return DC, in the Official DC layout.
+func GenerateReturnDCPDF(data *ReturnDCPDFData) ([]byte, error) {
+	pdf := newPDF(&pdfHeaderConfig{Project: data.Project, Company: data.Company, ShowEmail: true, Watermark: dcWatermark(data.DC)})
+
+	drawDCTitle(pdf, "Material Return Note", false)
+	drawReturnDCDetailsGrid(pdf, data)
+	drawReturnAddressGrid(pdf, data)
+	drawOfficialProductTable(pdf, data.LineItems)
+
+	notes := ""
+	if data.Return != nil {
+		notes = data.Return.Remarks
+	}
+	ensureSpace(pdf, estimateNotesHeight(pdf, notes)+65)
+	if notes != "" {
+		drawNotes(pdf, notes)
+	}
+	drawOfficialSignatures(pdf, data.Project)
+
+	return pdfToBytes(pdf)
+}
+
+func drawReturnDCDetailsGrid(pdf *fpdf.Fpdf, data *ReturnDCPDFData) {
+	y := pdf.GetY()
+	colW := contentW/2 - 2
+	gap := 4.0
+	innerW := colW - 2*cellPad
+
+	// Left box: return details; right box: PO details
+	boxH := 4*lineH + 2*cellPad
+
+	drawBorderedRect(pdf, marginL, y, colW, boxH)
+	pdf.SetXY(marginL+cellPad, y+cellPad)
+	kvRow(pdf, "Return No:", data.DC.DCNumber, 24, innerW)
+	if data.DC.ChallanDate != nil {
+		pdf.SetX(marginL + cellPad)
+		kvRow(pdf, "Date:", *data.DC.ChallanDate, 24, innerW)
+	}
+	if data.Return != nil {
+		pdf.SetX(marginL + cellPad)
+		kvRow(pdf, "Against DC:", data.Return.OriginalDCNumber, 24, innerW)
+		pdf.SetX(marginL + cellPad)
+		kvRow(pdf, "Reason:", models.ReturnReasonLabel(data.Return.Reason), 24, innerW)
+	}
+
+	rightX := marginL + colW + gap
+	drawBorderedRect(pdf, rightX, y, colW, boxH)
+	pdf.SetXY(rightX+cellPad, y+cellPad)
+	if data.Project != nil {
+		if data.Project.POReference != "" {
+			pdf.SetX(rightX + cellPad)
+			kvRow(pdf, "PO Number:", data.Project.POReference, 22, innerW)
+		}
+		if data.Project.PODate != nil {
+			pdf.SetX(rightX + cellPad)
+			kvRow(pdf, "PO Date:", *data.Project.PODate, 22, innerW)
+		}
+		pdf.SetX(rightX + cellPad)
+		kvRow(pdf, "Project:", data.Project.Name, 22, innerW)
+	}
+
+	pdf.SetY(y + boxH + 2)
+}
+
+func drawReturnAddressGrid(pdf *fpdf.Fpdf, data *ReturnDCPDFData) {
+	colW := contentW/2 - 2
+	gap := 4.0
+	y := pdf.GetY()
+
+	returnedToLines := addressLinesFiltered(data.ReturnedToAddress, data.ReturnedToConfig)
+	if len(returnedToLines) == 0 {
+		returnedToLines = companyAddressLines(data.Company)
+	}
+	h1 := drawAddressBox(pdf, marginL, y, colW, "Returned From", addressLinesFiltered(data.ReturnedFromAddress, data.ReturnedFromConfig))
+	h2 := drawAddressBox(pdf, marginL+colW+gap, y, colW, "Returned To", returnedToLines)
+	pdf.SetY(y + math.Max(h1, h2) + 2)
+}
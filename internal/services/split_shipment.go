@@ -6,6 +6,8 @@ import (
 	"math"
 	"strings"
 	"time"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
 )
 
 // SplitShipmentParams holds all parameters needed to create a split from a Transfer DC.
@@ -52,11 +54,13 @@ func validateSplitDestinations(selectedIDs []int, unsplitDestIDs map[int]bool) e
 // parentSerials: map[productID]map[serialNumber]bool — all serials from parent DC
 // usedSerials: map[serialNumber]bool — serials already consumed by previous splits
 // expectedQty: map[productID]int — expected total quantity per product for this split
+// products: map[productID]models.Product — item names and serial format rules
 func validateSplitSerials(
 	productSerials []SplitProductSerials,
 	expectedQty map[int]int,
 	parentSerials map[int]map[string]bool,
 	usedSerials map[string]bool,
+	products map[int]models.Product,
 ) map[string]string {
 	errs := make(map[string]string)
 
@@ -92,6 +96,18 @@ func validateSplitSerials(
 				break
 			}
 		}
+
+		// Check serials follow the product's serial format rule
+		if _, failed := errs[productKey+"_serials"]; !failed {
+			p := products[ps.ProductID]
+			name := p.ItemName
+			if name == "" {
+				name = fmt.Sprintf("product %d", ps.ProductID)
+			}
+			if msg := models.SerialRuleError(name, p.SerialRule, ps.SerialNumbers); msg != "" {
+				errs[productKey+"_serials"] = msg
+			}
+		}
 	}
 
 	return errs
@@ -259,8 +275,29 @@ func CreateSplitShipment(db *sql.DB, params SplitShipmentParams) (*SplitResult,
 	}
 	usedRows.Close()
 
+	// 6b. Get the serial format rules of the project's products
+	products := make(map[int]models.Product)
+	ruleRows, err := db.Query(
+		`SELECT id, item_name, serial_pattern, serial_min_length, serial_max_length, serial_prefix, serial_luhn
+		 FROM products WHERE project_id = ?`,
+		params.ProjectID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product serial rules: %w", err)
+	}
+	for ruleRows.Next() {
+		var p models.Product
+		r := &p.SerialRule
+		if err := ruleRows.Scan(&p.ID, &p.ItemName, &r.Pattern, &r.MinLength, &r.MaxLength, &r.Prefix, &r.Luhn); err != nil {
+			ruleRows.Close()
+			return nil, fmt.Errorf("failed to read product serial rules: %w", err)
+		}
+		products[p.ID] = p
+	}
+	ruleRows.Close()
+
 	// 7. Validate serials
-	serialErrs := validateSplitSerials(params.ProductSerials, productTotalQty, parentSerials, usedSerials)
+	serialErrs := validateSplitSerials(params.ProductSerials, productTotalQty, parentSerials, usedSerials, products)
 	if len(serialErrs) > 0 {
 		// Collect first error
 		for _, msg := range serialErrs {
@@ -2,7 +2,10 @@ package services
 
 import (
 	"database/sql"
+	"strings"
 	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
 )
 
 // setupSplitTestDB creates an in-memory SQLite database with all tables needed
@@ -55,6 +58,17 @@ func setupSplitTestDB(t *testing.T) *sql.DB {
 			created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
 			UNIQUE(destination_id, product_id)
 		)`,
+		`CREATE TABLE IF NOT EXISTS products (
+			id                INTEGER PRIMARY KEY AUTOINCREMENT,
+			project_id        INTEGER NOT NULL,
+			item_name         TEXT NOT NULL,
+			serial_pattern    TEXT NOT NULL DEFAULT '',
+			serial_min_length INTEGER NOT NULL DEFAULT 0,
+			serial_max_length INTEGER NOT NULL DEFAULT 0,
+			serial_prefix     TEXT NOT NULL DEFAULT '',
+			serial_luhn       INTEGER NOT NULL DEFAULT 0,
+			warranty_months   INTEGER NOT NULL DEFAULT 0
+		)`,
 		// Add updated_at column to shipment_groups for status updates
 		`ALTER TABLE shipment_groups ADD COLUMN updated_at DATETIME DEFAULT CURRENT_TIMESTAMP`,
 		// Add transfer_dc_id and split_id to shipment_groups
@@ -187,7 +201,7 @@ func padInt(n, width int) string {
 func TestValidateSplitDestinations_MustBeUnsplit(t *testing.T) {
 	// If a destination is already split, it cannot be selected again
 	unsplitDestIDs := map[int]bool{1: true, 2: true} // only 1 and 2 are unsplit
-	selectedIDs := []int{1, 3}                        // 3 is NOT unsplit
+	selectedIDs := []int{1, 3}                       // 3 is NOT unsplit
 
 	err := validateSplitDestinations(selectedIDs, unsplitDestIDs)
 	if err == nil {
@@ -241,7 +255,7 @@ func TestValidateSplitSerials_MustBelongToParent(t *testing.T) {
 	}
 	expectedQty := map[int]int{1: 2}
 
-	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials)
+	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials, nil)
 	if errs["product_1_serials"] == "" {
 		t.Fatal("expected error for serial not belonging to parent")
 	}
@@ -259,7 +273,7 @@ func TestValidateSplitSerials_ExactCount(t *testing.T) {
 	}
 	expectedQty := map[int]int{1: 3}
 
-	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials)
+	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials, nil)
 	if errs["product_1_count"] == "" {
 		t.Fatal("expected error for serial count mismatch")
 	}
@@ -276,7 +290,7 @@ func TestValidateSplitSerials_NoDuplicates(t *testing.T) {
 	}
 	expectedQty := map[int]int{1: 2}
 
-	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials)
+	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials, nil)
 	if errs["product_1_serials"] == "" {
 		t.Fatal("expected error for duplicate serial")
 	}
@@ -293,7 +307,7 @@ func TestValidateSplitSerials_NotAlreadyUsedInOtherSplit(t *testing.T) {
 	}
 	expectedQty := map[int]int{1: 2}
 
-	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials)
+	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials, nil)
 	if errs["product_1_serials"] == "" {
 		t.Fatal("expected error for serial already used in another split")
 	}
@@ -312,7 +326,7 @@ func TestValidateSplitSerials_HappyPath(t *testing.T) {
 	}
 	expectedQty := map[int]int{1: 2, 2: 1}
 
-	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials)
+	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials, nil)
 	if len(errs) != 0 {
 		t.Fatalf("unexpected validation errors: %v", errs)
 	}
@@ -360,10 +374,10 @@ func TestCreateSplitShipment_HappyPath(t *testing.T) {
 	// Split destinations 1 and 2 (addr 100: P1=5,P2=3; addr 200: P1=5,P2=2)
 	// Total: P1=10, P2=5
 	params := SplitShipmentParams{
-		TransferDCID:   tdcID,
-		ParentDCID:     dcID,
-		ProjectID:      projectID,
-		DestinationIDs: []int{destIDs[0], destIDs[1]},
+		TransferDCID:    tdcID,
+		ParentDCID:      dcID,
+		ProjectID:       projectID,
+		DestinationIDs:  []int{destIDs[0], destIDs[1]},
 		TransporterName: "Small Vehicle Co",
 		VehicleNumber:   "TS01-AB-1234",
 		ProductSerials: []SplitProductSerials{
@@ -448,10 +462,10 @@ func TestCreateSplitShipment_InheritsRatesFromParent(t *testing.T) {
 	tdcID, dcID, destIDs := createTestTransferDC(t, db, projectID)
 
 	params := SplitShipmentParams{
-		TransferDCID:   tdcID,
-		ParentDCID:     dcID,
-		ProjectID:      projectID,
-		DestinationIDs: []int{destIDs[0]}, // addr 100: P1=5, P2=3
+		TransferDCID:    tdcID,
+		ParentDCID:      dcID,
+		ProjectID:       projectID,
+		DestinationIDs:  []int{destIDs[0]}, // addr 100: P1=5, P2=3
 		TransporterName: "Test",
 		VehicleNumber:   "TS01",
 		ProductSerials: []SplitProductSerials{
@@ -494,10 +508,10 @@ func TestCreateSplitShipment_InheritsTaxType(t *testing.T) {
 	tdcID, dcID, destIDs := createTestTransferDC(t, db, projectID)
 
 	params := SplitShipmentParams{
-		TransferDCID:   tdcID,
-		ParentDCID:     dcID,
-		ProjectID:      projectID,
-		DestinationIDs: []int{destIDs[0]},
+		TransferDCID:    tdcID,
+		ParentDCID:      dcID,
+		ProjectID:       projectID,
+		DestinationIDs:  []int{destIDs[0]},
 		TransporterName: "Test",
 		VehicleNumber:   "TS01",
 		ProductSerials: []SplitProductSerials{
@@ -531,10 +545,10 @@ func TestCreateSplitShipment_InheritsAddresses(t *testing.T) {
 	tdcID, dcID, destIDs := createTestTransferDC(t, db, projectID)
 
 	params := SplitShipmentParams{
-		TransferDCID:   tdcID,
-		ParentDCID:     dcID,
-		ProjectID:      projectID,
-		DestinationIDs: []int{destIDs[0]},
+		TransferDCID:    tdcID,
+		ParentDCID:      dcID,
+		ProjectID:       projectID,
+		DestinationIDs:  []int{destIDs[0]},
 		TransporterName: "Test",
 		VehicleNumber:   "TS01",
 		ProductSerials: []SplitProductSerials{
@@ -576,10 +590,10 @@ func TestCreateSplitShipment_CorrectOfficialDCQuantities(t *testing.T) {
 
 	// Split 2 destinations: addr 100 (P1=5, P2=3) and addr 200 (P1=5, P2=2)
 	params := SplitShipmentParams{
-		TransferDCID:   tdcID,
-		ParentDCID:     dcID,
-		ProjectID:      projectID,
-		DestinationIDs: []int{destIDs[0], destIDs[1]},
+		TransferDCID:    tdcID,
+		ParentDCID:      dcID,
+		ProjectID:       projectID,
+		DestinationIDs:  []int{destIDs[0], destIDs[1]},
 		TransporterName: "Test",
 		VehicleNumber:   "TS01",
 		ProductSerials: []SplitProductSerials{
@@ -629,10 +643,10 @@ func TestCreateSplitShipment_SingleDestination(t *testing.T) {
 
 	// Split just 1 destination: addr 100 (P1=5, P2=3) → 1 TDC + 1 ODC
 	params := SplitShipmentParams{
-		TransferDCID:   tdcID,
-		ParentDCID:     dcID,
-		ProjectID:      projectID,
-		DestinationIDs: []int{destIDs[0]},
+		TransferDCID:    tdcID,
+		ParentDCID:      dcID,
+		ProjectID:       projectID,
+		DestinationIDs:  []int{destIDs[0]},
 		TransporterName: "Test",
 		VehicleNumber:   "TS01",
 		ProductSerials: []SplitProductSerials{
@@ -663,10 +677,10 @@ func TestCreateSplitShipment_AllRemainingDestinations(t *testing.T) {
 
 	// Split ALL 4 destinations → status should become "split"
 	params := SplitShipmentParams{
-		TransferDCID:   tdcID,
-		ParentDCID:     dcID,
-		ProjectID:      projectID,
-		DestinationIDs: destIDs, // all 4
+		TransferDCID:    tdcID,
+		ParentDCID:      dcID,
+		ProjectID:       projectID,
+		DestinationIDs:  destIDs, // all 4
 		TransporterName: "Test",
 		VehicleNumber:   "TS01",
 		ProductSerials: []SplitProductSerials{
@@ -706,10 +720,10 @@ func TestCreateSplitShipment_TransferDCMustBeIssued(t *testing.T) {
 	db.Exec(`UPDATE delivery_challans SET status = 'draft' WHERE id = ?`, dcID)
 
 	params := SplitShipmentParams{
-		TransferDCID:   tdcID,
-		ParentDCID:     dcID,
-		ProjectID:      projectID,
-		DestinationIDs: []int{destIDs[0]},
+		TransferDCID:    tdcID,
+		ParentDCID:      dcID,
+		ProjectID:       projectID,
+		DestinationIDs:  []int{destIDs[0]},
 		TransporterName: "Test",
 		VehicleNumber:   "TS01",
 		ProductSerials: []SplitProductSerials{
@@ -736,10 +750,10 @@ func TestCreateSplitShipment_TransferDCCannotBeSplit(t *testing.T) {
 	db.Exec(`UPDATE delivery_challans SET status = 'split' WHERE id = ?`, dcID)
 
 	params := SplitShipmentParams{
-		TransferDCID:   tdcID,
-		ParentDCID:     dcID,
-		ProjectID:      projectID,
-		DestinationIDs: []int{destIDs[0]},
+		TransferDCID:    tdcID,
+		ParentDCID:      dcID,
+		ProjectID:       projectID,
+		DestinationIDs:  []int{destIDs[0]},
 		TransporterName: "Test",
 		VehicleNumber:   "TS01",
 		ProductSerials: []SplitProductSerials{
@@ -763,3 +777,24 @@ func serialRange(prefix string, from, to int) []string {
 	}
 	return serials
 }
+
+func TestValidateSplitSerials_FormatRule(t *testing.T) {
+	parentSerials := map[int]map[string]bool{
+		1: {"IM-490154203237518": true, "IM-12345": true},
+	}
+	usedSerials := map[string]bool{}
+	products := map[int]models.Product{
+		1: {ID: 1, ItemName: "Tracker", SerialRule: models.SerialRule{Prefix: "IM-", MinLength: 18, MaxLength: 18}},
+	}
+
+	productSerials := []SplitProductSerials{
+		{ProductID: 1, SerialNumbers: []string{"IM-490154203237518", "IM-12345"}},
+	}
+	expectedQty := map[int]int{1: 2}
+
+	errs := validateSplitSerials(productSerials, expectedQty, parentSerials, usedSerials, products)
+	msg := errs["product_1_serials"]
+	if !strings.Contains(msg, "IM-12345") || strings.Contains(msg, "IM-490154203237518") {
+		t.Fatalf("expected format error naming only IM-12345, got %q", msg)
+	}
+}
@@ -0,0 +1,538 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+// Tally names that are not configurable per project.
+const (
+	tallySalesGroup     = "Sales Accounts"
+	tallyDutiesGroup    = "Duties & Taxes"
+	tallyPrimaryBatch   = "Primary Batch"
+	tallyInvoiceView    = "Invoice Voucher View"
+	tallyDefaultUnit    = "Nos"
+	tallyGSTApplicable  = "20170701"
+	tallyRegistered     = "Regular"
+	tallyUnregistered   = "Unregistered"
+	tallyCountry        = "India"
+	tallyMaxNameRunes   = 100
+	tallyCentralTax     = "Central Tax"
+	tallyStateTax       = "State Tax"
+	tallyIntegratedTax  = "Integrated Tax"
+	tallyImportRequest  = "Import Data"
+	tallyVoucherReport  = "Vouchers"
+	tallyYes            = "Yes"
+	tallyNo             = "No"
+	tallyActionCreate   = "Create"
+	tallyPartyAddrLines = 4
+)
+
+// TallyVoucherInput holds everything a Delivery Note voucher is built from.
+// Addresses come from the DC's snapshot.
+type TallyVoucherInput struct {
+	DC              *models.DeliveryChallan
+	Project         *models.Project
+	Company         *models.CompanySettings
+	BillFrom        *models.Address
+	BillTo          *models.Address
+	ShipTo          *models.Address
+	TaxType         string // "cgst_sgst" or "igst"; derived from the state codes when empty
+	LineItems       []models.DCLineItem
+	TransporterName string
+	VehicleNumber   string
+}
+
+// TallyVoucher is one DC mapped onto a Tally Delivery Note.
+type TallyVoucher struct {
+	Number      string
+	Date        string // YYYYMMDD
+	Party       TallyParty
+	Destination string
+	Transporter string
+	Vehicle     string
+	Items       []TallyItem
+	Taxable     float64
+	CGST        float64
+	SGST        float64
+	IGST        float64
+}
+
+// Total is the voucher value the party ledger is debited with.
+func (v *TallyVoucher) Total() float64 {
+	return models.RoundPaise(v.Taxable + v.CGST + v.SGST + v.IGST)
+}
+
+// TallyParty is the party ledger a voucher is raised against: the DC's bill-to.
+type TallyParty struct {
+	Name    string
+	Address []string
+	State   string
+	Pincode string
+	GSTIN   string
+}
+
+// TallyItem is one stock item line of a voucher.
+type TallyItem struct {
+	Name     string
+	HSN      string
+	Unit     string
+	GSTRate  float64
+	Quantity int
+	Rate     float64
+	Amount   float64
+}
+
+// BuildTallyVoucher maps an issued DC onto a Tally Delivery Note. It returns the
+// voucher together with the problems that would make Tally reject it; the
+// voucher must not be exported while there are problems.
+func BuildTallyVoucher(in *TallyVoucherInput) (*TallyVoucher, []string) {
+	var problems []string
+	dc := in.DC
+
+	v := &TallyVoucher{
+		Number:      dc.DCNumber,
+		Transporter: strings.TrimSpace(in.TransporterName),
+		Vehicle:     strings.ToUpper(strings.TrimSpace(in.VehicleNumber)),
+	}
+	if dc.ChallanDate != nil && len(*dc.ChallanDate) >= 10 {
+		if t, err := time.Parse("2006-01-02", (*dc.ChallanDate)[:10]); err == nil {
+			v.Date = t.Format("20060102")
+		}
+	}
+	if v.Date == "" {
+		problems = append(problems, "Challan date is missing")
+	}
+
+	// The party is billed to the bill-to address; DCs without one bill the ship-to.
+	billTo := in.BillTo
+	if billTo == nil {
+		billTo = in.ShipTo
+	}
+	v.Party = TallyParty{
+		Name:    truncateRunes(firstNonEmpty(billTo.Field(ewbNameFields...), addressDistrict(billTo)), tallyMaxNameRunes),
+		State:   billTo.Field(ewbStateFields...),
+		Pincode: strings.ReplaceAll(billTo.Field(ewbPincodeFields...), " ", ""),
+		GSTIN:   strings.ToUpper(billTo.Field(ewbGSTINFields...)),
+	}
+	for _, line := range []string{
+		billTo.Field(ewbAddr1Fields...),
+		billTo.Field(ewbAddr2Fields...),
+		firstNonEmpty(billTo.Field(ewbPlaceFields...), addressLocality(billTo)),
+	} {
+		if line != "" && len(v.Party.Address) < tallyPartyAddrLines {
+			v.Party.Address = append(v.Party.Address, line)
+		}
+	}
+	if v.Party.Name == "" {
+		problems = append(problems, "Bill-to party name is missing; it names the party ledger in Tally")
+	}
+	v.Destination = firstNonEmpty(in.ShipTo.Field(ewbPlaceFields...), addressDistrict(in.ShipTo))
+
+	if len(in.LineItems) == 0 {
+		problems = append(problems, "DC has no line items")
+	}
+	interState := tallyIsInterState(in)
+	var tax float64
+	for _, li := range in.LineItems { //nolint:gocritic
+		name := truncateRunes(strings.TrimSpace(li.ItemName), tallyMaxNameRunes)
+		if name == "" {
+			problems = append(problems, fmt.Sprintf("Line %d has no item name; it names the stock item in Tally", li.LineOrder))
+			continue
+		}
+		if li.Quantity <= 0 {
+			continue
+		}
+		unit := strings.TrimSpace(li.UoM)
+		if unit == "" {
+			unit = tallyDefaultUnit
+		}
+		v.Items = append(v.Items, TallyItem{
+			Name:     name,
+			HSN:      strings.ReplaceAll(strings.TrimSpace(li.HSNCode), " ", ""),
+			Unit:     unit,
+			GSTRate:  li.TaxPercentage,
+			Quantity: li.Quantity,
+			Rate:     li.Rate,
+			Amount:   models.RoundPaise(li.TaxableAmount),
+		})
+		v.Taxable += li.TaxableAmount
+		tax += li.TaxAmount
+	}
+	v.Taxable = models.RoundPaise(v.Taxable)
+	tax = models.RoundPaise(tax)
+	if interState {
+		v.IGST = tax
+	} else {
+		// Any odd paisa goes to SGST so the halves add back up to the DC's tax.
+		paise := int64(math.Round(tax * 100))
+		v.CGST = float64(paise/2) / 100
+		v.SGST = float64(paise-paise/2) / 100
+	}
+	return v, problems
+}
+
+// tallyIsInterState reports whether a voucher carries IGST. The DC's recorded
+// tax type wins; DCs without one compare the supplier state with the place of supply.
+func tallyIsInterState(in *TallyVoucherInput) bool {
+	switch in.TaxType {
+	case "igst":
+		return true
+	case "cgst_sgst":
+		return false
+	}
+	supplier := models.SupplierStateCode(in.BillFrom, in.Project, in.Company)
+	return models.ExpectedTaxType(supplier, models.PlaceOfSupplyStateCode(in.BillTo, in.ShipTo)) == "igst"
+}
+
+// MarshalTallyXML encodes vouchers as a Tally XML import file. When the settings
+// ask for masters, the file first creates the units, godown, ledgers and stock
+// items the vouchers use; Tally skips those that already exist.
+func MarshalTallyXML(settings *models.TallySettings, vouchers []*TallyVoucher) ([]byte, error) {
+	env := tallyEnvelope{}
+	env.Header.TallyRequest = tallyImportRequest
+	env.Body.ImportData.RequestDesc.ReportName = tallyVoucherReport
+	env.Body.ImportData.RequestDesc.StaticVariables.Company = strings.TrimSpace(settings.CompanyName)
+
+	var messages []tallyMessage
+	if settings.IncludeMasters {
+		messages = append(messages, tallyMasters(settings, vouchers)...)
+	}
+	for _, v := range vouchers {
+		messages = append(messages, tallyMessage{Voucher: tallyVoucherXML(settings, v)})
+	}
+	env.Body.ImportData.RequestData.Messages = messages
+
+	out, err := xml.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("MarshalTallyXML: %w", err)
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// tallyMasters returns the masters the vouchers post to, each created once.
+func tallyMasters(settings *models.TallySettings, vouchers []*TallyVoucher) []tallyMessage {
+	var units, items, parties []tallyMessage
+	seenUnit := make(map[string]bool)
+	seenItem := make(map[string]bool)
+	seenParty := make(map[string]bool)
+	var cgst, igst bool
+	for _, v := range vouchers {
+		cgst = cgst || v.CGST != 0 || v.SGST != 0
+		igst = igst || v.IGST != 0
+		if key := strings.ToLower(v.Party.Name); !seenParty[key] {
+			seenParty[key] = true
+			parties = append(parties, tallyMessage{Ledger: tallyPartyLedger(settings, &v.Party)})
+		}
+		for i := range v.Items {
+			it := &v.Items[i]
+			if key := strings.ToLower(it.Unit); !seenUnit[key] {
+				seenUnit[key] = true
+				units = append(units, tallyMessage{Unit: &tallyUnitXML{
+					Name: it.Unit, Action: tallyActionCreate, UnitName: it.Unit, IsSimpleUnit: tallyYes,
+				}})
+			}
+			if key := strings.ToLower(it.Name); !seenItem[key] {
+				seenItem[key] = true
+				items = append(items, tallyMessage{StockItem: tallyStockItem(it)})
+			}
+		}
+	}
+
+	messages := append([]tallyMessage{}, units...)
+	messages = append(messages, tallyMessage{Godown: &tallyGodownXML{
+		Name: settings.Godown, Action: tallyActionCreate, NameList: tallyNames(settings.Godown),
+	}})
+	messages = append(messages, tallyMessage{Ledger: &tallyLedgerXML{
+		Name: settings.SalesLedger, Action: tallyActionCreate, NameList: tallyNames(settings.SalesLedger),
+		Parent: tallySalesGroup,
+	}})
+	if cgst {
+		messages = append(messages,
+			tallyMessage{Ledger: tallyDutyLedger(settings.CGSTLedger, tallyCentralTax)},
+			tallyMessage{Ledger: tallyDutyLedger(settings.SGSTLedger, tallyStateTax)})
+	}
+	if igst {
+		messages = append(messages, tallyMessage{Ledger: tallyDutyLedger(settings.IGSTLedger, tallyIntegratedTax)})
+	}
+	messages = append(messages, parties...)
+	return append(messages, items...)
+}
+
+func tallyPartyLedger(settings *models.TallySettings, p *TallyParty) *tallyLedgerXML {
+	l := &tallyLedgerXML{
+		Name:         p.Name,
+		Action:       tallyActionCreate,
+		NameList:     tallyNames(p.Name),
+		Parent:       settings.PartyGroup,
+		IsBillWiseOn: tallyYes,
+		StateName:    p.State,
+		Pincode:      p.Pincode,
+		Country:      tallyCountry,
+		GSTRegType:   tallyUnregistered,
+	}
+	if len(p.Address) > 0 {
+		l.Address = &tallyAddressList{Type: "String", Lines: p.Address}
+	}
+	if p.GSTIN != "" {
+		l.GSTRegType = tallyRegistered
+		l.PartyGSTIN = p.GSTIN
+	}
+	return l
+}
+
+func tallyDutyLedger(name, dutyHead string) *tallyLedgerXML {
+	return &tallyLedgerXML{
+		Name:        name,
+		Action:      tallyActionCreate,
+		NameList:    tallyNames(name),
+		Parent:      tallyDutiesGroup,
+		TaxType:     "GST",
+		GSTDutyHead: dutyHead,
+	}
+}
+
+func tallyStockItem(it *TallyItem) *tallyStockItemXML {
+	s := &tallyStockItemXML{
+		Name:      it.Name,
+		Action:    tallyActionCreate,
+		NameList:  tallyNames(it.Name),
+		BaseUnits: it.Unit,
+	}
+	if it.HSN != "" {
+		s.GSTDetails = &tallyGSTDetails{
+			ApplicableFrom: tallyGSTApplicable,
+			HSNCode:        it.HSN,
+			Taxability:     "Taxable",
+			StateWise: tallyStateWise{
+				StateName: "Any",
+				Rates: []tallyRateDetail{
+					{DutyHead: tallyCentralTax, Rate: tallyNumber(it.GSTRate / 2)},
+					{DutyHead: tallyStateTax, Rate: tallyNumber(it.GSTRate / 2)},
+					{DutyHead: tallyIntegratedTax, Rate: tallyNumber(it.GSTRate)},
+				},
+			},
+		}
+	}
+	return s
+}
+
+// tallyVoucherXML lays a voucher out in Tally's invoice view. Tally signs
+// amounts by side: the party is debited (negative), sales and taxes credited.
+func tallyVoucherXML(settings *models.TallySettings, v *TallyVoucher) *tallyVoucherElem {
+	x := &tallyVoucherElem{
+		VchType:         settings.VoucherType,
+		Action:          tallyActionCreate,
+		ObjView:         tallyInvoiceView,
+		Date:            v.Date,
+		EffectiveDate:   v.Date,
+		VoucherTypeName: settings.VoucherType,
+		VoucherNumber:   v.Number,
+		Reference:       v.Number,
+		PartyLedgerName: v.Party.Name,
+		PartyName:       v.Party.Name,
+		BuyerName:       v.Party.Name,
+		PartyGSTIN:      v.Party.GSTIN,
+		StateName:       v.Party.State,
+		PlaceOfSupply:   v.Party.State,
+		ShippedBy:       v.Transporter,
+		VesselNo:        v.Vehicle,
+		Destination:     v.Destination,
+		Narration:       "Delivery challan " + v.Number,
+		PersistedView:   tallyInvoiceView,
+		IsInvoice:       tallyYes,
+	}
+	for i := range v.Items {
+		it := &v.Items[i]
+		qty := fmt.Sprintf(" %d %s", it.Quantity, it.Unit)
+		amount := tallyAmount(it.Amount)
+		x.Inventory = append(x.Inventory, tallyInventoryEntry{
+			StockItemName:  it.Name,
+			IsDeemedPos:    tallyNo,
+			Rate:           fmt.Sprintf("%s/%s", tallyAmount(it.Rate), it.Unit),
+			Amount:         amount,
+			ActualQty:      qty,
+			BilledQty:      qty,
+			Batch:          tallyBatchAllocation{Godown: settings.Godown, Batch: tallyPrimaryBatch, Amount: amount, ActualQty: qty, BilledQty: qty},
+			AccountingLine: tallyLedgerEntry{LedgerName: settings.SalesLedger, IsDeemedPos: tallyNo, Amount: amount},
+		})
+	}
+
+	x.Ledgers = append(x.Ledgers, tallyLedgerEntry{
+		LedgerName: v.Party.Name, IsDeemedPos: tallyYes, IsPartyLedger: tallyYes, Amount: tallyAmount(-v.Total()),
+	})
+	for _, tax := range []struct {
+		ledger string
+		amount float64
+	}{
+		{settings.CGSTLedger, v.CGST},
+		{settings.SGSTLedger, v.SGST},
+		{settings.IGSTLedger, v.IGST},
+	} {
+		if tax.amount != 0 {
+			x.Ledgers = append(x.Ledgers, tallyLedgerEntry{LedgerName: tax.ledger, IsDeemedPos: tallyNo, Amount: tallyAmount(tax.amount)})
+		}
+	}
+	return x
+}
+
+func tallyNames(name string) tallyNameList {
+	return tallyNameList{Names: []string{name}}
+}
+
+func tallyAmount(v float64) string {
+	return fmt.Sprintf("%.2f", models.RoundPaise(v))
+}
+
+func tallyNumber(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.3f", v), "0"), ".")
+}
+
+// Tally XML import envelope. Element names are Tally's; the order of fields
+// follows the order Tally itself exports them in.
+type tallyEnvelope struct {
+	XMLName xml.Name `xml:"ENVELOPE"`
+	Header  struct {
+		TallyRequest string `xml:"TALLYREQUEST"`
+	} `xml:"HEADER"`
+	Body struct {
+		ImportData struct {
+			RequestDesc struct {
+				ReportName      string `xml:"REPORTNAME"`
+				StaticVariables struct {
+					Company string `xml:"SVCURRENTCOMPANY,omitempty"`
+				} `xml:"STATICVARIABLES"`
+			} `xml:"REQUESTDESC"`
+			RequestData struct {
+				Messages []tallyMessage `xml:"TALLYMESSAGE"`
+			} `xml:"REQUESTDATA"`
+		} `xml:"IMPORTDATA"`
+	} `xml:"BODY"`
+}
+
+type tallyMessage struct {
+	Unit      *tallyUnitXML      `xml:"UNIT,omitempty"`
+	Godown    *tallyGodownXML    `xml:"GODOWN,omitempty"`
+	Ledger    *tallyLedgerXML    `xml:"LEDGER,omitempty"`
+	StockItem *tallyStockItemXML `xml:"STOCKITEM,omitempty"`
+	Voucher   *tallyVoucherElem  `xml:"VOUCHER,omitempty"`
+}
+
+type tallyNameList struct {
+	Names []string `xml:"NAME"`
+}
+
+type tallyUnitXML struct {
+	Name         string `xml:"NAME,attr"`
+	Action       string `xml:"ACTION,attr"`
+	UnitName     string `xml:"NAME"`
+	IsSimpleUnit string `xml:"ISSIMPLEUNIT"`
+}
+
+type tallyGodownXML struct {
+	Name     string        `xml:"NAME,attr"`
+	Action   string        `xml:"ACTION,attr"`
+	NameList tallyNameList `xml:"NAME.LIST"`
+}
+
+type tallyLedgerXML struct {
+	Name         string            `xml:"NAME,attr"`
+	Action       string            `xml:"ACTION,attr"`
+	NameList     tallyNameList     `xml:"NAME.LIST"`
+	Parent       string            `xml:"PARENT"`
+	IsBillWiseOn string            `xml:"ISBILLWISEON,omitempty"`
+	Address      *tallyAddressList `xml:"ADDRESS.LIST,omitempty"`
+	StateName    string            `xml:"LEDSTATENAME,omitempty"`
+	Pincode      string            `xml:"PINCODE,omitempty"`
+	Country      string            `xml:"COUNTRYNAME,omitempty"`
+	GSTRegType   string            `xml:"GSTREGISTRATIONTYPE,omitempty"`
+	PartyGSTIN   string            `xml:"PARTYGSTIN,omitempty"`
+	TaxType      string            `xml:"TAXTYPE,omitempty"`
+	GSTDutyHead  string            `xml:"GSTDUTYHEAD,omitempty"`
+}
+
+type tallyAddressList struct {
+	Type  string   `xml:"TYPE,attr"`
+	Lines []string `xml:"ADDRESS"`
+}
+
+type tallyStockItemXML struct {
+	Name       string           `xml:"NAME,attr"`
+	Action     string           `xml:"ACTION,attr"`
+	NameList   tallyNameList    `xml:"NAME.LIST"`
+	BaseUnits  string           `xml:"BASEUNITS"`
+	GSTDetails *tallyGSTDetails `xml:"GSTDETAILS.LIST,omitempty"`
+}
+
+type tallyGSTDetails struct {
+	ApplicableFrom string         `xml:"APPLICABLEFROM"`
+	HSNCode        string         `xml:"HSNCODE"`
+	Taxability     string         `xml:"TAXABILITY"`
+	StateWise      tallyStateWise `xml:"STATEWISEDETAILS.LIST"`
+}
+
+type tallyStateWise struct {
+	StateName string            `xml:"STATENAME"`
+	Rates     []tallyRateDetail `xml:"RATEDETAILS.LIST"`
+}
+
+type tallyRateDetail struct {
+	DutyHead string `xml:"GSTRATEDUTYHEAD"`
+	Rate     string `xml:"GSTRATE"`
+}
+
+type tallyVoucherElem struct {
+	VchType         string                `xml:"VCHTYPE,attr"`
+	Action          string                `xml:"ACTION,attr"`
+	ObjView         string                `xml:"OBJVIEW,attr"`
+	Date            string                `xml:"DATE"`
+	EffectiveDate   string                `xml:"EFFECTIVEDATE"`
+	VoucherTypeName string                `xml:"VOUCHERTYPENAME"`
+	VoucherNumber   string                `xml:"VOUCHERNUMBER"`
+	Reference       string                `xml:"REFERENCE"`
+	PartyLedgerName string                `xml:"PARTYLEDGERNAME"`
+	PartyName       string                `xml:"PARTYNAME"`
+	BuyerName       string                `xml:"BASICBUYERNAME"`
+	PartyGSTIN      string                `xml:"PARTYGSTIN,omitempty"`
+	StateName       string                `xml:"STATENAME,omitempty"`
+	PlaceOfSupply   string                `xml:"PLACEOFSUPPLY,omitempty"`
+	ShippedBy       string                `xml:"BASICSHIPPEDBY,omitempty"`
+	VesselNo        string                `xml:"BASICSHIPVESSELNO,omitempty"`
+	Destination     string                `xml:"BASICFINALDESTINATION,omitempty"`
+	Narration       string                `xml:"NARRATION"`
+	PersistedView   string                `xml:"PERSISTEDVIEW"`
+	IsInvoice       string                `xml:"ISINVOICE"`
+	Inventory       []tallyInventoryEntry `xml:"ALLINVENTORYENTRIES.LIST"`
+	Ledgers         []tallyLedgerEntry    `xml:"LEDGERENTRIES.LIST"`
+}
+
+type tallyInventoryEntry struct {
+	StockItemName  string               `xml:"STOCKITEMNAME"`
+	IsDeemedPos    string               `xml:"ISDEEMEDPOSITIVE"`
+	Rate           string               `xml:"RATE"`
+	Amount         string               `xml:"AMOUNT"`
+	ActualQty      string               `xml:"ACTUALQTY"`
+	BilledQty      string               `xml:"BILLEDQTY"`
+	Batch          tallyBatchAllocation `xml:"BATCHALLOCATIONS.LIST"`
+	AccountingLine tallyLedgerEntry     `xml:"ACCOUNTINGALLOCATIONS.LIST"`
+}
+
+type tallyBatchAllocation struct {
+	Godown    string `xml:"GODOWNNAME"`
+	Batch     string `xml:"BATCHNAME"`
+	Amount    string `xml:"AMOUNT"`
+	ActualQty string `xml:"ACTUALQTY"`
+	BilledQty string `xml:"BILLEDQTY"`
+}
+
+type tallyLedgerEntry struct {
+	LedgerName    string `xml:"LEDGERNAME"`
+	IsDeemedPos   string `xml:"ISDEEMEDPOSITIVE"`
+	IsPartyLedger string `xml:"ISPARTYLEDGER,omitempty"`
+	Amount        string `xml:"AMOUNT"`
+}
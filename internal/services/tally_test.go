@@ -0,0 +1,130 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/narendhupati/dc-management-tool/internal/models"
+)
+
+func newTestTallyInput() *TallyVoucherInput {
+	challanDate := "2026-03-09"
+	return &TallyVoucherInput{
+		DC: &models.DeliveryChallan{
+			ID:          1,
+			ProjectID:   1,
+			DCNumber:    "TST-TDC-2526-001",
+			DCType:      "transit",
+			Status:      "issued",
+			ChallanDate: &challanDate,
+		},
+		Project: &models.Project{CompanyName: "Test Company Ltd", CompanyGSTIN: "36AABCT1234F1ZR"},
+		BillTo: &models.Address{ID: 3, Data: map[string]string{
+			"Company Name": "AP Fibernet", "GSTIN": "37aaaca1234b1zh", "Address Line 1": "Govt Complex", "City": "Vijayawada", "State": "Andhra Pradesh", "PIN Code": "520 001",
+		}},
+		ShipTo: &models.Address{ID: 4, DistrictName: "Krishna", MandalName: "Gudivada", Data: map[string]string{
+			"Location": "Gram Panchayat Office", "State": "Andhra Pradesh",
+		}},
+		LineItems: []models.DCLineItem{
+			{LineOrder: 1, ItemName: "Router", HSNCode: "8517", UoM: "Nos", Quantity: 2, Rate: 500, TaxPercentage: 18, TaxableAmount: 1000, TaxAmount: 180.01},
+			{LineOrder: 2, ItemName: "Cable", HSNCode: "8544", UoM: "Mtr", Quantity: 100, Rate: 5, TaxPercentage: 12, TaxableAmount: 500, TaxAmount: 60},
+		},
+		TransporterName: "ABC Logistics",
+		VehicleNumber:   "ts09ab1234",
+	}
+}
+
+func TestBuildTallyVoucher(t *testing.T) {
+	v, problems := BuildTallyVoucher(newTestTallyInput())
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	if v.Date != "20260309" || v.Number != "TST-TDC-2526-001" {
+		t.Errorf("date/number = %s/%s", v.Date, v.Number)
+	}
+	if v.Party.Name != "AP Fibernet" || v.Party.GSTIN != "37AAACA1234B1ZH" || v.Party.Pincode != "520001" {
+		t.Errorf("party = %+v", v.Party)
+	}
+	if v.Destination != "Krishna" || v.Vehicle != "TS09AB1234" {
+		t.Errorf("destination/vehicle = %s/%s", v.Destination, v.Vehicle)
+	}
+	// Telangana supplier, Andhra Pradesh bill-to: inter-state.
+	if v.IGST != 240.01 || v.CGST != 0 || v.SGST != 0 {
+		t.Errorf("taxes = %v/%v/%v, want IGST 240.01", v.CGST, v.SGST, v.IGST)
+	}
+	if v.Taxable != 1500 || v.Total() != 1740.01 {
+		t.Errorf("taxable/total = %v/%v", v.Taxable, v.Total())
+	}
+
+	in := newTestTallyInput()
+	in.TaxType = "cgst_sgst"
+	v, _ = BuildTallyVoucher(in)
+	if v.CGST != 120 || v.SGST != 120.01 || v.IGST != 0 {
+		t.Errorf("intra-state taxes = %v/%v/%v, want 120/120.01/0", v.CGST, v.SGST, v.IGST)
+	}
+}
+
+func TestBuildTallyVoucherProblems(t *testing.T) {
+	in := newTestTallyInput()
+	in.DC.ChallanDate = nil
+	in.BillTo = nil
+	in.ShipTo = &models.Address{ID: 4}
+	in.LineItems = nil
+
+	_, problems := BuildTallyVoucher(in)
+	if len(problems) != 3 {
+		t.Fatalf("problems = %v, want date, party and line items", problems)
+	}
+}
+
+func TestMarshalTallyXML(t *testing.T) {
+	settings := models.DefaultTallySettings(1)
+	settings.CompanyName = "Test Company Ltd"
+	settings.SalesLedger = "Project Sales"
+
+	first, _ := BuildTallyVoucher(newTestTallyInput())
+	in := newTestTallyInput()
+	in.DC.DCNumber = "TST-TDC-2526-002"
+	in.TaxType = "cgst_sgst"
+	second, _ := BuildTallyVoucher(in)
+
+	data, err := MarshalTallyXML(settings, []*TallyVoucher{first, second})
+	if err != nil {
+		t.Fatalf("MarshalTallyXML: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"<SVCURRENTCOMPANY>Test Company Ltd</SVCURRENTCOMPANY>",
+		`<VOUCHER VCHTYPE="Delivery Note" ACTION="Create" OBJVIEW="Invoice Voucher View">`,
+		"<VOUCHERNUMBER>TST-TDC-2526-002</VOUCHERNUMBER>",
+		"<DATE>20260309</DATE>",
+		"<RATE>500.00/Nos</RATE>",
+		"<ACTUALQTY> 100 Mtr</ACTUALQTY>",
+		"<GODOWNNAME>Main Location</GODOWNNAME>",
+		"<LEDGERNAME>Project Sales</LEDGERNAME>",
+		"<AMOUNT>-1740.01</AMOUNT>",
+		"<LEDGERNAME>Output IGST</LEDGERNAME>",
+		"<LEDGERNAME>Output CGST</LEDGERNAME>",
+		"<PARTYGSTIN>37AAACA1234B1ZH</PARTYGSTIN>",
+		"<HSNCODE>8544</HSNCODE>",
+		"<GSTDUTYHEAD>Central Tax</GSTDUTYHEAD>",
+		"<PARENT>Duties &amp; Taxes</PARENT>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("XML missing %s", want)
+		}
+	}
+	// Masters are created once however many vouchers use them.
+	if n := strings.Count(out, `<LEDGER NAME="AP Fibernet"`); n != 1 {
+		t.Errorf("party ledger created %d times, want 1", n)
+	}
+	if n := strings.Count(out, `<STOCKITEM NAME="Router"`); n != 1 {
+		t.Errorf("stock item created %d times, want 1", n)
+	}
+
+	settings.IncludeMasters = false
+	data, _ = MarshalTallyXML(settings, []*TallyVoucher{first})
+	if strings.Contains(string(data), "<LEDGER ") || strings.Contains(string(data), "<STOCKITEM ") {
+		t.Error("masters exported with IncludeMasters off")
+	}
+}
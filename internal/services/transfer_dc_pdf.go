@@ -60,13 +60,15 @@ type TransferDCPDFProduct struct {
 
 // GenerateTransferDCPDF produces a PDF for a Split Transfer Delivery Challan.
 func GenerateTransferDCPDF(data *TransferDCPDFData) ([]byte, error) {
-	pdf := newPDF(&pdfHeaderConfig{Project: data.Project, Company: data.Company})
+	pdf := newPDF(&pdfHeaderConfig{Project: data.Project, Company: data.Company, Watermark: dcWatermark(data.DC)})
 
 	drawDCTitle(pdf, "Split Transfer Delivery Challan", false)
 	drawTransferDCDetailsGrid(pdf, data)
 	drawTransferAddressGrid(pdf, data)
 	drawTransferProductTable(pdf, data.LineItems, data.TotalQty, data.TotalTaxable, data.TotalTax, data.GrandTotal)
-	drawTaxSummary(pdf, data.TotalTaxable, data.HalfTax, data.RoundOff, data.RoundedTotal)
+	taxType := transferTaxType(data.TransferDC)
+	drawHSNSummary(pdf, models.SummariseByHSN(data.LineItems, taxType), taxType)
+	drawTaxSummary(pdf, data.TotalTaxable, data.TotalTax, taxType, data.RoundOff, data.RoundedTotal)
 	drawAmountInWords(pdf, data.AmountInWords)
 
 	if data.Project != nil && data.Project.Notes != "" {
@@ -87,6 +89,14 @@ func GenerateTransferDCPDF(data *TransferDCPDFData) ([]byte, error) {
 
 // --- Transfer DC specific drawing functions ---
 
+// transferTaxType returns the tax type chosen for a Transfer DC, or "" when unknown.
+func transferTaxType(tdc *models.TransferDC) string {
+	if tdc == nil {
+		return ""
+	}
+	return tdc.TaxType
+}
+
 func drawTransferDCDetailsGrid(pdf *fpdf.Fpdf, data *TransferDCPDFData) {
 	y := pdf.GetY()
 	colW := contentW/2 - 2
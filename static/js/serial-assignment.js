@@ -23,22 +23,15 @@
     }
 
     /**
-     * Parse textarea into array of serial numbers, trim whitespace, remove blanks.
+     * Parse textarea into array of serial numbers, expanding ranges (serial-input.js).
      */
     function parseSerials(textarea) {
-        var lines = textarea.value.split('\n');
-        var serials = [];
-        for (var i = 0; i < lines.length; i++) {
-            var s = lines[i].trim();
-            if (s !== '') {
-                serials.push(s);
-            }
-        }
-        return serials;
+        return SerialInput.parse(textarea.value);
     }
 
     /**
-     * Validate serials against the server for duplicates in the project.
+     * Validate serials against the server for duplicates in the project
+     * and, when the project checks it, the serial registry.
      */
     function validateSerials(productId, serials, projectId, csrfToken) {
         if (serials.length === 0) return;
@@ -60,6 +53,9 @@
         .then(function(resp) { return resp.json(); })
         .then(function(data) {
             var html = '';
+            if (data.input_error) {
+                html += '<p class="text-sm text-red-600 mt-1">' + escapeHtml(data.input_error) + '</p>';
+            }
             if (data.duplicate_in_db && data.duplicate_in_db.length > 0) {
                 html += '<p class="text-sm text-red-600 mt-1">Already used in project: ';
                 html += data.duplicate_in_db.map(function(d) {
@@ -67,6 +63,22 @@
                 }).join(', ');
                 html += '</p>';
             }
+            if (data.format_errors && data.format_errors.length > 0) {
+                html += '<p class="text-sm text-red-600 mt-1">Not in the required format (' + escapeHtml(data.format_rule) + '): ' +
+                    data.format_errors.map(escapeHtml).join(', ') + '</p>';
+            }
+            // Serial registry: refused in enforce mode, flagged in warn mode
+            if (data.registry_mode && data.registry_mode !== 'off') {
+                var colour = data.registry_mode === 'enforce' ? 'text-red-600' : 'text-amber-600';
+                if (data.not_in_registry && data.not_in_registry.length > 0) {
+                    html += '<p class="text-sm ' + colour + ' mt-1">Not in the serial registry: ' +
+                        data.not_in_registry.map(escapeHtml).join(', ') + '</p>';
+                }
+                if (data.damaged_in_registry && data.damaged_in_registry.length > 0) {
+                    html += '<p class="text-sm ' + colour + ' mt-1">Marked damaged in the serial registry: ' +
+                        data.damaged_in_registry.map(escapeHtml).join(', ') + '</p>';
+                }
+            }
             // Append to existing errors (don't overwrite duplicate-in-input errors)
             var existing = errDiv.querySelector('.db-errors');
             if (existing) existing.remove();
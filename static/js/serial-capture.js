@@ -0,0 +1,79 @@
+/**
+ * Scanner feedback for the serial capture screen.
+ *
+ * USB barcode scanners type the serial and press Enter, which submits the scan
+ * form over htmx. When the server's panel comes back this plays a short tone
+ * and flashes the scan field green or red, then clears and refocuses it so the
+ * next serial can be scanned straight away.
+ */
+
+(function() {
+    'use strict';
+
+    var audio = null;
+
+    // Play a tone; a rising pair for a saved serial, a low buzz for a refusal.
+    function beep(status) {
+        var Ctx = window.AudioContext || window.webkitAudioContext;
+        if (!Ctx) return;
+        if (!audio) audio = new Ctx();
+        var notes = {
+            accepted: [[880, 0, 0.08], [1320, 0.09, 0.1]],
+            warned: [[660, 0, 0.15]],
+            rejected: [[180, 0, 0.35]]
+        }[status];
+        if (!notes) return;
+        notes.forEach(function(n) {
+            var osc = audio.createOscillator();
+            var gain = audio.createGain();
+            osc.type = status === 'rejected' ? 'square' : 'sine';
+            osc.frequency.value = n[0];
+            gain.gain.value = 0.15;
+            osc.connect(gain);
+            gain.connect(audio.destination);
+            osc.start(audio.currentTime + n[1]);
+            osc.stop(audio.currentTime + n[1] + n[2]);
+        });
+    }
+
+    function flash(input, status) {
+        var cls = {
+            accepted: 'ring-green-500',
+            warned: 'ring-yellow-500',
+            rejected: 'ring-red-500'
+        }[status];
+        if (!cls) return;
+        input.classList.add('ring-4', cls);
+        setTimeout(function() { input.classList.remove('ring-4', cls); }, 600);
+    }
+
+    function focusScan() {
+        var input = document.getElementById('scan-serial');
+        if (input) input.focus();
+    }
+
+    document.body.addEventListener('htmx:afterSwap', function(evt) {
+        if (!evt.detail.target || evt.detail.target.id !== 'capture-panel') return;
+        var input = document.getElementById('scan-serial');
+        if (!input) return;
+        var result = document.getElementById('scan-result');
+        var status = result ? result.dataset.scanStatus : '';
+        // Keep a refused serial in the field so a typo can be corrected.
+        if (status !== 'rejected') input.value = '';
+        else input.select();
+        beep(status);
+        flash(input, status);
+        input.focus();
+    });
+
+    // Ignore empty Enters from the scanner's trailing keystrokes.
+    document.addEventListener('htmx:confirm', function(evt) {
+        if (evt.detail.elt && evt.detail.elt.id === 'scan-form') {
+            var input = document.getElementById('scan-serial');
+            if (input && input.value.trim() === '') evt.preventDefault();
+        }
+    });
+
+    document.addEventListener('DOMContentLoaded', focusScan);
+    focusScan();
+})();
@@ -0,0 +1,89 @@
+/**
+ * Pasted serial number parsing for the serial entry steps.
+ *
+ * Mirrors helpers.ParseSerialInput on the server so live counts match what
+ * will be saved: serials split on new lines, tabs, commas or semicolons, a
+ * first line that is a serial column header (Serial, S/N, Serial No, ...) is
+ * skipped and ranges like ABC000100-ABC000599 are expanded. The server remains the authority on submit.
+ */
+
+(function() {
+    'use strict';
+
+    var MAX_RANGE = 10000;
+    var MAX_DIGITS = 15; // longest number a range end may carry, as on IMEIs
+
+    // Split one end of a range into its prefix and trailing number.
+    function splitEnd(s) {
+        var m = /^(.*?)(\d+)$/.exec(s.trim());
+        if (!m || m[2].length > MAX_DIGITS) return null;
+        return { prefix: m[1], digits: m[2], n: parseInt(m[2], 10) };
+    }
+
+    // Column headings, lower-cased without dots or colons, as in helpers.serialHeaders.
+    var HEADERS = ['serial', 'serials', 's/n', 'sn', 'serial no', 'serial nos',
+        'serial number', 'serial numbers', 'sr no', 'sl no'];
+
+    function isHeader(tokens) {
+        return tokens.every(function(tok) {
+            var t = tok.toLowerCase().replace(/[.:_#]/g, ' ').trim().split(/\s+/).join(' ');
+            return HEADERS.indexOf(t) >= 0;
+        });
+    }
+
+    function pad(n, width) {
+        var s = String(n);
+        while (s.length < width) s = '0' + s;
+        return s;
+    }
+
+    // Expand tok when it is a range; returns null when it is not.
+    function expandRange(tok) {
+        for (var i = tok.indexOf('-'); i >= 0; i = tok.indexOf('-', i + 1)) {
+            var a = splitEnd(tok.slice(0, i));
+            var b = splitEnd(tok.slice(i + 1));
+            if (!a || !b || a.prefix !== b.prefix || a.n >= b.n) continue;
+            var padded = a.digits.length > String(a.n).length;
+            var sameWidth = a.digits.length === b.digits.length;
+            if (!sameWidth && (a.prefix === '' || padded)) continue;
+            if (b.n - a.n + 1 > MAX_RANGE) return null;
+            var width = sameWidth ? a.digits.length : 0;
+            var out = [];
+            for (var n = a.n; n <= b.n; n++) {
+                out.push(a.prefix + pad(n, width));
+            }
+            return out;
+        }
+        return null;
+    }
+
+    /**
+     * Parse pasted text into serial numbers, in input order, duplicates kept.
+     */
+    function parse(text) {
+        var serials = [];
+        var header = true;
+        var lines = text.replace(/\r/g, '\n').split('\n');
+        for (var i = 0; i < lines.length; i++) {
+            var tokens = lines[i].split(/[\t,;]/).map(function(c) {
+                return c.trim().replace(/^["']+|["']+$/g, '').trim();
+            }).filter(function(c) { return c !== ''; });
+            if (tokens.length === 0) continue;
+            if (header) {
+                header = false;
+                if (isHeader(tokens)) continue;
+            }
+            tokens.forEach(function(tok) {
+                var expanded = expandRange(tok);
+                if (expanded) {
+                    Array.prototype.push.apply(serials, expanded);
+                } else {
+                    serials.push(tok);
+                }
+            });
+        }
+        return serials;
+    }
+
+    window.SerialInput = { parse: parse };
+})();
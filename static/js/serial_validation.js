@@ -119,6 +119,10 @@
         var container = getOrCreateErrorContainer(textarea);
         var html = '';
 
+        if (result.input_error) {
+            html += '<div class="text-red-600 text-xs mb-1">' + escapeHtml(result.input_error) + '</div>';
+        }
+
         if (result.duplicate_in_input && result.duplicate_in_input.length > 0) {
             html += '<div class="text-red-600 text-xs mb-1">' +
                 '<strong>Duplicates in this list:</strong> ' +
@@ -126,6 +130,13 @@
                 '</div>';
         }
 
+        if (result.format_errors && result.format_errors.length > 0) {
+            html += '<div class="text-red-600 text-xs mb-1">' +
+                '<strong>Not in the required format (' + escapeHtml(result.format_rule) + '):</strong> ' +
+                escapeHtml(result.format_errors.join(', ')) +
+                '</div>';
+        }
+
         if (result.duplicate_in_db && result.duplicate_in_db.length > 0) {
             html += '<div class="text-red-600 text-xs">' +
                 '<strong>Already used in other DCs:</strong>' +